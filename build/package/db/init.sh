@@ -6,12 +6,136 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
     {
       "AttributeName": "Username",
       "AttributeType": "S"
+    },
+    {
+      "AttributeName": "UsernameLower",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "Username",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "UsernameLower-index",
+      "KeySchema": [
+        {
+          "AttributeName": "UsernameLower",
+          "KeyType": "HASH"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ]
+}'
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-session",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "Username",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "Username-index",
+      "KeySchema": [
+        {
+          "AttributeName": "Username",
+          "KeyType": "HASH"
+        },
+        {
+          "AttributeName": "ID",
+          "KeyType": "RANGE"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ]
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-session \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-revocation",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "Username",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
     }
   ],
   "KeySchema": [
     {
       "AttributeName": "Username",
       "KeyType": "HASH"
+    },
+    {
+      "AttributeName": "ID",
+      "KeyType": "RANGE"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-revocation \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-reset",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
     }
   ],
   "ProvisionedThroughput": {
@@ -20,6 +144,180 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
   }
 }'
 
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-reset \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-attempt",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-attempt \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-identity",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "Username",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "Username-index",
+      "KeySchema": [
+        {
+          "AttributeName": "Username",
+          "KeyType": "HASH"
+        },
+        {
+          "AttributeName": "ID",
+          "KeyType": "RANGE"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ]
+}'
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-token",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "Username",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "Username-index",
+      "KeySchema": [
+        {
+          "AttributeName": "Username",
+          "KeyType": "HASH"
+        },
+        {
+          "AttributeName": "ID",
+          "KeyType": "RANGE"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ]
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-token \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-invite",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "TeamID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "ID",
+      "KeyType": "HASH"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "TeamID-index",
+      "KeySchema": [
+        {
+          "AttributeName": "TeamID",
+          "KeyType": "HASH"
+        },
+        {
+          "AttributeName": "ID",
+          "KeyType": "RANGE"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ]
+}'
+
+aws dynamodb update-time-to-live --endpoint-url http://localhost:8000 \
+  --table-name goteam-invite \
+  --time-to-live-specification "Enabled=true, AttributeName=ExpiresAt"
+
 aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
   "TableName": "goteam-team",
   "AttributeDefinitions": [
@@ -40,6 +338,56 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
   }
 }'
 
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-membership",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "Username",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "TeamID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "Username",
+      "KeyType": "HASH"
+    },
+    {
+      "AttributeName": "TeamID",
+      "KeyType": "RANGE"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  },
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "TeamID-index",
+      "KeySchema": [
+        {
+          "AttributeName": "TeamID",
+          "KeyType": "HASH"
+        },
+        {
+          "AttributeName": "Username",
+          "KeyType": "RANGE"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "ALL"
+      },
+      "ProvisionedThroughput": {
+        "ReadCapacityUnits": 1,
+        "WriteCapacityUnits": 1
+      }
+    }
+  ]
+}'
+
 aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
   "TableName": "goteam-task",
   "AttributeDefinitions": [
@@ -93,3 +441,31 @@ aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json
     }
   ]
 }'
+
+aws dynamodb create-table --endpoint-url http://localhost:8000 --cli-input-json '{
+  "TableName": "goteam-override",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "BoardID",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "ID",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "BoardID",
+      "KeyType": "HASH"
+    },
+    {
+      "AttributeName": "ID",
+      "KeyType": "RANGE"
+    }
+  ],
+  "ProvisionedThroughput": {
+    "ReadCapacityUnits": 1,
+    "WriteCapacityUnits": 1
+  }
+}'
@@ -0,0 +1,85 @@
+// Command breachfilter turns a breached password corpus into a bloom filter
+// file that the user service can check new passwords against. The filter
+// takes a fraction of the memory that the corpus would, at the cost of
+// occasionally rejecting a password that was never breached.
+//
+// The corpus must have one password per line, either in plaintext or as a
+// hex-encoded SHA-1 digest optionally followed by a colon and a count, as in
+// Have I Been Pwned's downloads. It is read twice, once to count the passwords
+// and once to add them to the filter, so that the filter can be sized to fit.
+//
+// Usage:
+//
+//	breachfilter -in pwned-passwords-sha1.txt -out breached.bloom -fp 0.001
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
+)
+
+func main() {
+	// create a logger
+	log := log.New()
+
+	// parse flags
+	var (
+		in     = flag.String("in", "", "path of the corpus to read")
+		out    = flag.String("out", "", "path of the bloom filter to write")
+		fpRate = flag.Float64("fp", 0.001, "false positive rate to size for")
+	)
+	flag.Parse()
+	if *in == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *fpRate <= 0 || *fpRate >= 1 {
+		log.Fatal("fp must be between 0 and 1")
+		return
+	}
+
+	// count the passwords in the corpus
+	var n uint64
+	if err := scan(*in, func(password.Digest) { n++ }); err != nil {
+		log.Fatal(err)
+		return
+	}
+	log.Info("corpus has", n, "passwords")
+
+	// add them to a filter sized to fit
+	filter := password.NewBloomFilter(n, *fpRate)
+	if err := scan(*in, filter.AddDigest); err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// write the filter to the output file
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if _, err = filter.WriteTo(f); err != nil {
+		f.Close()
+		log.Fatal(err)
+		return
+	}
+	if err = f.Close(); err != nil {
+		log.Fatal(err)
+		return
+	}
+	log.Info("wrote bloom filter to", *out)
+}
+
+// scan calls fn with the digest of each password in the corpus at path.
+func scan(path string, fn func(password.Digest)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return password.ScanCorpus(f, fn)
+}
@@ -0,0 +1,439 @@
+// Command migrateteams moves the teams that were created when a team's ID was
+// its admin's username onto UUID identifiers. It gives each team an owner and a
+// name, and rewrites the team ID of the team's users, tasks, and invites. It
+// also adds a membership for each user of a team so that users who were
+// registered before memberships were introduced can be found in their team.
+//
+// The new team IDs are derived from the old ones so that the migration can be
+// run again safely if it fails halfway through. The old team row is deleted
+// only after everything else has been moved over.
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+const (
+	// envAWSEndpoint is the name of the environment variable used for setting
+	// the AWS endpoint to connect to for DynamoDB. It should only be non-empty
+	// on local pointing to the local DynamoDB instance.
+	envAWSEndpoint = "AWS_ENDPOINT"
+
+	// envAWSAccessKey is the name of the environment variable used for
+	// providing AWS access key to the DynamoDB client.
+	envAWSAccessKey = "AWS_ACCESS_KEY"
+
+	// envAWSSecretKey is the name of the environment variable used for
+	// providing AWS secret key to the DynamoDB client.
+	envAWSSecretKey = "AWS_SECRET_KEY"
+
+	// envAWSRegion is the name of the environment variable used for determining
+	// the AWS region to connect to for DynamoDB.
+	envAWSRegion = "AWS_REGION"
+
+	// envTeamTableName is the name of the environment variable used for
+	// setting the name of the team table.
+	envTeamTableName = "TEAM_TABLE_NAME"
+
+	// envUserTableName is the name of the environment variable used for
+	// setting the name of the user table.
+	envUserTableName = "USER_TABLE_NAME"
+
+	// envTaskTableName is the name of the environment variable used for
+	// setting the name of the task table.
+	envTaskTableName = "TASK_TABLE_NAME"
+
+	// envInviteTableName is the name of the environment variable used for
+	// setting the name of the invite table.
+	envInviteTableName = "INVITE_TABLE_NAME"
+
+	// envMembershipTableName is the name of the environment variable used for
+	// setting the name of the membership table.
+	envMembershipTableName = "MEMBERSHIP_TABLE_NAME"
+)
+
+// teamNamespace is the namespace that new team IDs are derived from old ones
+// in. It must never change, otherwise rerunning the migration would create
+// duplicate teams.
+var teamNamespace = uuid.MustParse("b1f3c2e4-6a5d-4f7e-9c8b-2d1e0f3a4b5c")
+
+func main() {
+	// create a logger
+	log := log.New()
+
+	// load environment variables
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// get environment variables
+	var (
+		awsEndpoint     = os.Getenv(envAWSEndpoint)
+		awsAccessKey    = os.Getenv(envAWSAccessKey)
+		awsSecretKey    = os.Getenv(envAWSSecretKey)
+		awsRegion       = os.Getenv(envAWSRegion)
+		teamTableName   = os.Getenv(envTeamTableName)
+		userTableName   = os.Getenv(envUserTableName)
+		taskTableName   = os.Getenv(envTaskTableName)
+		inviteTableName = os.Getenv(envInviteTableName)
+		memberTableName = os.Getenv(envMembershipTableName)
+	)
+
+	// check all environment variables were set
+	// - except aws endpoint, which is only set on local
+	errPostfix := "was empty"
+	switch "" {
+	case awsAccessKey:
+		log.Fatal(envAWSAccessKey, errPostfix)
+		return
+	case awsSecretKey:
+		log.Fatal(envAWSSecretKey, errPostfix)
+		return
+	case awsRegion:
+		log.Fatal(envAWSRegion, errPostfix)
+		return
+	case teamTableName:
+		log.Fatal(envTeamTableName, errPostfix)
+		return
+	case userTableName:
+		log.Fatal(envUserTableName, errPostfix)
+		return
+	case taskTableName:
+		log.Fatal(envTaskTableName, errPostfix)
+		return
+	case inviteTableName:
+		log.Fatal(envInviteTableName, errPostfix)
+		return
+	case memberTableName:
+		log.Fatal(envMembershipTableName, errPostfix)
+		return
+	}
+
+	// define aws config
+	cfg := aws.Config{
+		Region: awsRegion,
+		Credentials: credentials.NewStaticCredentialsProvider(
+			awsAccessKey, awsSecretKey, "",
+		),
+	}
+	if awsEndpoint != "" {
+		cfg.BaseEndpoint = aws.String(awsEndpoint)
+	}
+
+	// create DynamoDB client from config
+	client := dynamodb.NewFromConfig(cfg)
+	m := migrator{
+		client:          client,
+		teamTableName:   teamTableName,
+		inviteTableName: inviteTableName,
+		teamInserter:    teamtbl.NewInserter(client),
+		teamUpdater:     teamtbl.NewUpdater(client),
+		userUpdater:     usertbl.NewUpdater(client),
+		taskRetriever:   tasktbl.NewRetrieverByTeam(client),
+		taskInserter:    tasktbl.NewInserter(client),
+		taskDeleter:     tasktbl.NewDeleter(client),
+		inviteRetriever: invitetbl.NewRetrieverByTeam(client),
+		memberInserter:  membershiptbl.NewInserter(client),
+	}
+	ctx := context.Background()
+
+	// group users by their team so that each team's users can be moved along
+	// with it
+	log.Info("reading users")
+	users, err := m.usersByTeam(ctx, userTableName)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// migrate teams one by one
+	log.Info("reading teams")
+	teams, err := m.teams(ctx)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	var nMigrated int
+	for _, team := range teams {
+		migrated, err := m.migrate(ctx, team, users[team.ID])
+		if err != nil {
+			log.Error("migrating team", team.ID, "failed:", err)
+			continue
+		}
+		if migrated {
+			nMigrated++
+		}
+	}
+	log.Info("migrated", nMigrated, "of", len(teams), "teams")
+}
+
+// migrator contains the dependencies that are used to migrate teams.
+type migrator struct {
+	client          *dynamodb.Client
+	teamTableName   string
+	inviteTableName string
+	teamInserter    db.Inserter[teamtbl.Team]
+	teamUpdater     db.Updater[teamtbl.Team]
+	userUpdater     db.Updater[usertbl.User]
+	taskRetriever   db.Retriever[[]tasktbl.Task]
+	taskInserter    db.Inserter[tasktbl.Task]
+	taskDeleter     db.DeleterDualKey
+	inviteRetriever db.Retriever[[]invitetbl.Invite]
+	memberInserter  db.Inserter[membershiptbl.Membership]
+}
+
+// usersByTeam scans the user table and returns its users grouped by team ID.
+func (m migrator) usersByTeam(
+	ctx context.Context, tableName string,
+) (map[string][]usertbl.User, error) {
+	users := map[string][]usertbl.User{}
+	p := dynamodb.NewScanPaginator(m.client, &dynamodb.ScanInput{
+		TableName: &tableName,
+	})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range out.Items {
+			var user usertbl.User
+			if err = attributevalue.UnmarshalMap(item, &user); err != nil {
+				return nil, err
+			}
+
+			// username claims share the table but aren't users
+			if strings.HasPrefix(user.Username, usertbl.ClaimPrefix) {
+				continue
+			}
+
+			// users registered before roles were introduced only have IsAdmin
+			if user.Role == "" {
+				var legacy struct{ IsAdmin bool }
+				err = attributevalue.UnmarshalMap(item, &legacy)
+				if err != nil {
+					return nil, err
+				}
+				user.Role = role.FromIsAdmin(legacy.IsAdmin)
+			}
+
+			users[user.TeamID] = append(users[user.TeamID], user)
+		}
+	}
+	return users, nil
+}
+
+// teams scans the team table and returns all teams in it.
+func (m migrator) teams(ctx context.Context) ([]teamtbl.Team, error) {
+	var teams []teamtbl.Team
+	p := dynamodb.NewScanPaginator(m.client, &dynamodb.ScanInput{
+		TableName: &m.teamTableName,
+	})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var page []teamtbl.Team
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return nil, err
+		}
+		teams = append(teams, page...)
+	}
+	return teams, nil
+}
+
+// migrate moves a team and everything that belongs to it onto a UUID team ID
+// and returns whether there was anything to migrate. The memberships of a team
+// that has already been moved are still added in case it was moved before
+// memberships were introduced.
+func (m migrator) migrate(
+	ctx context.Context, team teamtbl.Team, users []usertbl.User,
+) (bool, error) {
+	oldID := team.ID
+	_, errParse := uuid.Parse(oldID)
+	if errParse == nil && team.Owner != "" {
+		for _, u := range users {
+			if err := m.addMembership(ctx, u); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	// a team whose ID is not a UUID is owned by the user whose username is its
+	// ID, otherwise look for its owner among its users
+	if team.Owner == "" {
+		if errParse != nil {
+			team.Owner = oldID
+		} else {
+			for _, u := range users {
+				if u.Role == role.Owner {
+					team.Owner = u.Username
+					break
+				}
+			}
+		}
+		if team.Owner == "" {
+			return false, errors.New("owner not found")
+		}
+	}
+	if team.Name == "" {
+		team.Name = team.Owner + "'s Team"
+	}
+
+	// teams that already have a UUID only need the new fields to be set
+	if errParse == nil {
+		if err := m.migrateUsers(ctx, users, team); err != nil {
+			return false, err
+		}
+		return true, m.teamUpdater.Update(ctx, team)
+	}
+
+	// insert the team under its new ID - it will already exist if a previous
+	// run failed after inserting it
+	team.ID = uuid.NewSHA1(teamNamespace, []byte(oldID)).String()
+	if err := m.teamInserter.Insert(
+		ctx, team,
+	); errors.Is(err, db.ErrDupKey) {
+		if err = m.teamUpdater.Update(ctx, team); err != nil {
+			return false, err
+		}
+	} else if err != nil {
+		return false, err
+	}
+
+	if err := m.migrateUsers(ctx, users, team); err != nil {
+		return false, err
+	}
+	if err := m.migrateTasks(ctx, oldID, team.ID); err != nil {
+		return false, err
+	}
+	if err := m.migrateInvites(ctx, oldID, team.ID); err != nil {
+		return false, err
+	}
+
+	// delete the old team last so that the migration can be rerun for it if
+	// any of the steps above fails
+	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: &m.teamTableName,
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: oldID},
+		},
+	})
+	return err == nil, err
+}
+
+// migrateUsers moves the given users into the team and stores their roles
+// explicitly, making sure that the team's owner is the only user who owns it.
+func (m migrator) migrateUsers(
+	ctx context.Context, users []usertbl.User, team teamtbl.Team,
+) error {
+	for _, u := range users {
+		u.TeamID = team.ID
+		if u.Username == team.Owner {
+			u.Role = role.Owner
+		} else if u.Role == role.Owner {
+			u.Role = role.Admin
+		}
+		if err := m.userUpdater.Update(
+			ctx, u,
+		); err != nil && !errors.Is(err, db.ErrNoItem) {
+			return err
+		}
+		if err := m.addMembership(ctx, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addMembership adds the membership of the user to their team with their role
+// in it. A membership that already exists is left as is, since it is the
+// source of truth once it has been added.
+func (m migrator) addMembership(ctx context.Context, u usertbl.User) error {
+	err := m.memberInserter.Insert(
+		ctx, membershiptbl.NewMembership(u.Username, u.TeamID, u.Role),
+	)
+	if errors.Is(err, db.ErrDupKey) {
+		return nil
+	}
+	return err
+}
+
+// migrateTasks moves the tasks of the team with the old ID into the team with
+// the new ID. Since the team ID is the task table's partition key, each task is
+// copied over and then deleted.
+func (m migrator) migrateTasks(ctx context.Context, oldID, newID string) error {
+	// deleting the tasks that were moved also pages through them
+	for {
+		tasks, err := m.taskRetriever.Retrieve(ctx, oldID)
+		if err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+
+		for _, t := range tasks {
+			t.TeamID = newID
+			if err = m.taskInserter.Insert(
+				ctx, t,
+			); err != nil && !errors.Is(err, db.ErrDupKey) {
+				return err
+			}
+			if err = m.taskDeleter.Delete(
+				ctx, oldID, t.ID,
+			); err != nil && !errors.Is(err, db.ErrNoItem) {
+				return err
+			}
+		}
+	}
+}
+
+// migrateInvites moves the invites of the team with the old ID into the team
+// with the new ID so that they can still be used to join it.
+func (m migrator) migrateInvites(
+	ctx context.Context, oldID, newID string,
+) error {
+	invites, err := m.inviteRetriever.Retrieve(ctx, oldID)
+	if err != nil {
+		return err
+	}
+	for _, inv := range invites {
+		inv.TeamID = newID
+		item, err := attributevalue.MarshalMap(inv)
+		if err != nil {
+			return err
+		}
+		if _, err = m.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: &m.inviteTableName, Item: item,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Command migrateusernames prepares the user table for case-insensitive
+// usernames. It gives each user who registered before usernames were
+// case-insensitive the lower case form of their username that usernames are
+// now compared by, claims their username in all casings so that it can't be
+// registered again in another casing, and reports the usernames that are owned
+// by more than one user in different casings.
+//
+// Colliding users can still log in with the exact casing of their username,
+// but not with any other casing since it can't be told whose account is meant.
+// They need to be resolved by hand, e.g. by asking all but one of the users to
+// register again under a different username. Deleting a colliding user
+// releases the claim they share with the others, so the migration should be
+// rerun afterwards to claim the username again and to check that no
+// collisions are left. It can be rerun safely.
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/joho/godotenv"
+
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+const (
+	// envAWSEndpoint is the name of the environment variable used for setting
+	// the AWS endpoint to connect to for DynamoDB. It should only be non-empty
+	// on local pointing to the local DynamoDB instance.
+	envAWSEndpoint = "AWS_ENDPOINT"
+
+	// envAWSAccessKey is the name of the environment variable used for
+	// providing AWS access key to the DynamoDB client.
+	envAWSAccessKey = "AWS_ACCESS_KEY"
+
+	// envAWSSecretKey is the name of the environment variable used for
+	// providing AWS secret key to the DynamoDB client.
+	envAWSSecretKey = "AWS_SECRET_KEY"
+
+	// envAWSRegion is the name of the environment variable used for determining
+	// the AWS region to connect to for DynamoDB.
+	envAWSRegion = "AWS_REGION"
+
+	// envUserTableName is the name of the environment variable used for
+	// setting the name of the user table.
+	envUserTableName = "USER_TABLE_NAME"
+)
+
+func main() {
+	// create a logger
+	log := log.New()
+
+	// load environment variables
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// get environment variables
+	var (
+		awsEndpoint   = os.Getenv(envAWSEndpoint)
+		awsAccessKey  = os.Getenv(envAWSAccessKey)
+		awsSecretKey  = os.Getenv(envAWSSecretKey)
+		awsRegion     = os.Getenv(envAWSRegion)
+		userTableName = os.Getenv(envUserTableName)
+	)
+
+	// check all environment variables were set
+	// - except aws endpoint, which is only set on local
+	errPostfix := "was empty"
+	switch "" {
+	case awsAccessKey:
+		log.Fatal(envAWSAccessKey, errPostfix)
+		return
+	case awsSecretKey:
+		log.Fatal(envAWSSecretKey, errPostfix)
+		return
+	case awsRegion:
+		log.Fatal(envAWSRegion, errPostfix)
+		return
+	case userTableName:
+		log.Fatal(envUserTableName, errPostfix)
+		return
+	}
+
+	// define aws config
+	cfg := aws.Config{
+		Region: awsRegion,
+		Credentials: credentials.NewStaticCredentialsProvider(
+			awsAccessKey, awsSecretKey, "",
+		),
+	}
+	if awsEndpoint != "" {
+		cfg.BaseEndpoint = aws.String(awsEndpoint)
+	}
+
+	// create DynamoDB client from config
+	client := dynamodb.NewFromConfig(cfg)
+	ctx := context.Background()
+
+	// give each user the lower case form of their username and claim it,
+	// grouping the usernames by it to find the ones that collide
+	log.Info("reading users")
+	usernames := map[string][]string{}
+	var nUsers, nMigrated int
+	p := dynamodb.NewScanPaginator(client, &dynamodb.ScanInput{
+		TableName:            &userTableName,
+		ProjectionExpression: aws.String("Username, UsernameLower"),
+	})
+	for p.HasMorePages() {
+		out, err := p.NextPage(ctx)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		var users []struct{ Username, UsernameLower string }
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &users,
+		); err != nil {
+			log.Fatal(err)
+			return
+		}
+
+		for _, u := range users {
+			if strings.HasPrefix(u.Username, usertbl.ClaimPrefix) {
+				continue
+			}
+			nUsers++
+			lower := usertbl.LowerUsername(u.Username)
+			usernames[lower] = append(usernames[lower], u.Username)
+			if u.UsernameLower != lower {
+				if err = setLower(
+					ctx, client, userTableName, u.Username, lower,
+				); err != nil {
+					log.Error("migrating user", u.Username, "failed:", err)
+					continue
+				}
+			}
+			if err = claim(
+				ctx, client, userTableName, u.Username,
+			); err != nil {
+				log.Error("migrating user", u.Username, "failed:", err)
+				continue
+			}
+			nMigrated++
+		}
+	}
+	log.Info("migrated", nMigrated, "of", nUsers, "users")
+
+	// report the usernames that are owned by more than one user
+	var collisions []string
+	for lower, owners := range usernames {
+		if len(owners) > 1 {
+			collisions = append(collisions, lower)
+		}
+	}
+	sort.Strings(collisions)
+	for _, lower := range collisions {
+		log.Warn("username", lower, "is owned by", usernames[lower])
+	}
+	log.Info("found", len(collisions), "colliding usernames")
+}
+
+// setLower sets the lower case form of the username of the user with the given
+// username. A user who was deleted since the table was scanned is skipped.
+func setLower(
+	ctx context.Context,
+	client *dynamodb.Client,
+	tableName, username, lower string,
+) error {
+	_, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: &tableName,
+		Key: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: username},
+		},
+		UpdateExpression:    aws.String("SET UsernameLower = :lower"),
+		ConditionExpression: aws.String("attribute_exists(Username)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":lower": &types.AttributeValueMemberS{Value: lower},
+		},
+	})
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return nil
+	}
+	return err
+}
+
+// claim puts the item that claims the given username in all of its casings
+// unless it already exists.
+func claim(
+	ctx context.Context, client *dynamodb.Client, tableName, username string,
+) error {
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: &tableName,
+		Item: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{
+				Value: usertbl.ClaimUsername(username),
+			},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(Username)"),
+	})
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return nil
+	}
+	return err
+}
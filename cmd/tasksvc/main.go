@@ -3,6 +3,7 @@ package main
 import (
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -13,8 +14,15 @@ import (
 	"github.com/kxplxn/goteam/internal/tasksvc/tasksapi"
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pat"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 const (
@@ -39,8 +47,9 @@ const (
 	// the AWS region to connect to for DynamoDB.
 	envAWSRegion = "AWS_REGION"
 
-	// envJWTKey is the name of the environment variable used for signing JWTs.
-	envJWTKey = "JWT_KEY"
+	// envUserServiceJWKSURL is the name of the environment variable used for
+	// setting the URL of the user service's JWKS to validate auth tokens with.
+	envUserServiceJWKSURL = "USER_SERVICE_JWKS_URL"
 
 	// envClientOrigin is the name of the environment variable used to set up
 	// CORS with the client app.
@@ -65,7 +74,7 @@ func main() {
 		awsAccessKey = os.Getenv(envAWSAccessKey)
 		awsSecretKey = os.Getenv(envAWSSecretKey)
 		awsRegion    = os.Getenv(envAWSRegion)
-		jwtKey       = os.Getenv(envJWTKey)
+		userJWKSURL  = os.Getenv(envUserServiceJWKSURL)
 		clientOrigin = os.Getenv(envClientOrigin)
 	)
 
@@ -85,8 +94,8 @@ func main() {
 	case awsRegion:
 		log.Fatal(envAWSRegion, errPostfix)
 		return
-	case jwtKey:
-		log.Fatal(envJWTKey, errPostfix)
+	case userJWKSURL:
+		log.Fatal(envUserServiceJWKSURL, errPostfix)
 		return
 	case clientOrigin:
 		log.Fatal(envClientOrigin, errPostfix)
@@ -107,8 +116,34 @@ func main() {
 	// create DynamoDB client from config
 	db := dynamodb.NewFromConfig(cfg)
 
-	// create auth decoder to be used by API handlers
-	authDecoder := cookie.NewAuthDecoder([]byte(jwtKey))
+	// create auth decoders to be used by API handlers
+	// - personal access tokens are accepted in place of auth tokens as long
+	//   as they were granted the scope that the route requires
+	authDecoder := cookie.NewAuthDecoder(
+		cookie.NewRemoteKeySet(userJWKSURL, 10*time.Minute),
+		revocationtbl.NewChecker(db),
+	)
+	var (
+		readDecoder = pat.NewDecoder(
+			authDecoder,
+			tokentbl.NewRetriever(db),
+			usertbl.NewRetriever(db),
+			pat.ScopeTasksRead,
+		)
+		writeDecoder = pat.NewDecoder(
+			authDecoder,
+			tokentbl.NewRetriever(db),
+			usertbl.NewRetriever(db),
+			pat.ScopeTasksWrite,
+		)
+	)
+
+	// load the default team quotas - teams can override these individually
+	limits, err := quota.LoadLimits()
+	if err != nil {
+		log.Error(err)
+		return
+	}
 
 	// register handlers for HTTP routes
 	mux := http.NewServeMux()
@@ -116,20 +151,31 @@ func main() {
 	taskTitleValidator := taskapi.NewTitleValidator()
 	mux.Handle("/task", api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPost: taskapi.NewPostHandler(
-			authDecoder,
+			writeDecoder,
 			taskapi.ValidatePostReq,
+			teamtbl.NewRetriever(db),
+			tasktbl.NewRetrieverByBoard(db),
+			limits,
+			overridetbl.NewInserter(db),
 			tasktbl.NewInserter(db),
 			log,
 		),
 		http.MethodPatch: taskapi.NewPatchHandler(
-			authDecoder,
+			writeDecoder,
 			taskTitleValidator,
 			taskTitleValidator,
+			tasktbl.NewRetriever(db),
+			teamtbl.NewRetriever(db),
+			tasktbl.NewRetrieverByBoard(db),
+			limits,
+			overridetbl.NewInserter(db),
 			tasktbl.NewUpdater(db),
 			log,
 		),
 		http.MethodDelete: taskapi.NewDeleteHandler(
-			authDecoder,
+			writeDecoder,
+			tasktbl.NewRetriever(db),
+			teamtbl.NewRetriever(db),
 			tasktbl.NewDeleter(db),
 			log,
 		),
@@ -137,16 +183,20 @@ func main() {
 
 	mux.Handle("/tasks", api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPatch: tasksapi.NewPatchHandler(
-			authDecoder,
-			tasksapi.NewColNoValidator(),
+			writeDecoder,
+			tasktbl.NewRetriever(db),
+			teamtbl.NewRetriever(db),
+			tasktbl.NewRetrieverByBoard(db),
+			overridetbl.NewInserter(db),
 			tasktbl.NewMultiUpdater(db),
 			log,
 		),
 		http.MethodGet: tasksapi.NewGetHandler(
 			tasksapi.NewBoardIDValidator(),
 			tasktbl.NewRetrieverByBoard(db),
-			authDecoder,
+			readDecoder,
 			tasktbl.NewRetrieverByTeam(db),
+			teamtbl.NewRetriever(db),
 			log,
 		),
 	}))
@@ -11,11 +11,27 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/kxplxn/goteam/internal/teamsvc/boardapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/columnapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/inviteapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/ownerapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/quotaapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/roleapi"
 	"github.com/kxplxn/goteam/internal/teamsvc/teamapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/userapi"
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pat"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 const (
@@ -40,8 +56,9 @@ const (
 	// the AWS region to connect to for DynamoDB.
 	envAWSRegion = "AWS_REGION"
 
-	// envJWTKey is the name of the environment variable used for signing JWTs.
-	envJWTKey = "JWT_KEY"
+	// envUserServiceJWKSURL is the name of the environment variable used for
+	// setting the URL of the user service's JWKS to validate auth tokens with.
+	envUserServiceJWKSURL = "USER_SERVICE_JWKS_URL"
 
 	// envClientOrigin is the name of the environment variable used to set up
 	// CORS with the client app.
@@ -66,7 +83,7 @@ func main() {
 		awsAccessKey = os.Getenv(envAWSAccessKey)
 		awsSecretKey = os.Getenv(envAWSSecretKey)
 		awsRegion    = os.Getenv(envAWSRegion)
-		jwtKey       = os.Getenv(envJWTKey)
+		userJWKSURL  = os.Getenv(envUserServiceJWKSURL)
 		clientOrigin = os.Getenv(envClientOrigin)
 	)
 
@@ -86,8 +103,8 @@ func main() {
 	case awsRegion:
 		log.Error(envAWSRegion, errPostfix)
 		return
-	case jwtKey:
-		log.Error(envJWTKey, errPostfix)
+	case userJWKSURL:
+		log.Error(envUserServiceJWKSURL, errPostfix)
 		return
 	case clientOrigin:
 		log.Error(envClientOrigin, errPostfix)
@@ -108,44 +125,212 @@ func main() {
 	// create DynamoDB client from config
 	db := dynamodb.NewFromConfig(cfg)
 
-	// create auth encoder to be used for authenticating user on all routes
-	authDecoder := cookie.NewAuthDecoder([]byte(jwtKey))
+	// create auth decoders to be used for authenticating user on all routes
+	// - personal access tokens are accepted in place of auth tokens as long
+	//   as they were granted the scope that the route requires
+	authDecoder := cookie.NewAuthDecoder(
+		cookie.NewRemoteKeySet(userJWKSURL, 10*time.Minute),
+		revocationtbl.NewChecker(db),
+	)
+	var (
+		readDecoder = pat.NewDecoder(
+			authDecoder,
+			tokentbl.NewRetriever(db),
+			usertbl.NewRetriever(db),
+			pat.ScopeTeamRead,
+		)
+		writeDecoder = pat.NewDecoder(
+			authDecoder,
+			tokentbl.NewRetriever(db),
+			usertbl.NewRetriever(db),
+			pat.ScopeTeamWrite,
+		)
+	)
+
+	// load the default team quotas - teams can override these individually
+	limits, err := quota.LoadLimits()
+	if err != nil {
+		log.Error(err)
+		return
+	}
 
 	// register handlers for HTTP routes
 	mux := http.NewServeMux()
 
 	mux.Handle("/team", api.NewHandler(map[string]api.MethodHandler{
 		http.MethodGet: teamapi.NewGetHandler(
-			authDecoder,
+			readDecoder,
 			teamtbl.NewRetriever(db),
 			teamtbl.NewInserter(db),
 			teamtbl.NewUpdater(db),
-			cookie.NewInviteEncoder([]byte(jwtKey), 1*time.Hour),
+			membershiptbl.NewRetriever(db),
+			usertbl.NewProfileRetriever(db),
+			tasktbl.NewRetrieverByBoard(db),
+			log,
+		),
+		http.MethodPatch: teamapi.NewPatchHandler(
+			writeDecoder,
+			teamtbl.NewRetriever(db),
+			teamtbl.NewUpdater(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/team/invites", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: inviteapi.NewGetHandler(
+			readDecoder,
+			invitetbl.NewRetrieverByTeam(db),
+			log,
+		),
+		http.MethodPost: inviteapi.NewPostHandler(
+			writeDecoder,
+			inviteapi.ValidatePostReq,
+			invitetbl.NewInserter(db),
+			log,
+		),
+		http.MethodDelete: inviteapi.NewDeleteHandler(
+			writeDecoder,
+			invitetbl.NewDeleter(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/team/owner", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPut: ownerapi.NewPutHandler(
+			writeDecoder,
+			teamtbl.NewRetriever(db),
+			teamtbl.NewOwnerTransferer(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/team/role", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPut: roleapi.NewPutHandler(
+			writeDecoder,
+			teamtbl.NewRetriever(db),
+			membershiptbl.NewUpdater(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/team/quota", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: quotaapi.NewGetHandler(
+			readDecoder,
+			teamtbl.NewRetriever(db),
+			membershiptbl.NewRetrieverByTeam(db),
+			tasktbl.NewRetrieverByTeam(db),
+			limits,
 			log,
 		),
 	}))
 
 	mux.Handle("/board", api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPost: boardapi.NewPostHandler(
-			authDecoder,
+			writeDecoder,
 			boardapi.NewNameValidator(),
-			teamtbl.NewBoardInserter(db),
+			teamtbl.NewBoardInserter(db, limits),
 			log,
 		),
 		http.MethodPatch: boardapi.NewPatchHandler(
-			authDecoder,
+			writeDecoder,
 			boardapi.NewIDValidator(),
 			boardapi.NewNameValidator(),
+			teamtbl.NewRetriever(db),
 			teamtbl.NewBoardUpdater(db),
 			log,
 		),
 		http.MethodDelete: boardapi.NewDeleteHandler(
-			authDecoder,
+			writeDecoder,
+			tasktbl.NewDeleterByBoard(db),
 			teamtbl.NewBoardDeleter(db),
 			log,
 		),
 	}))
 
+	mux.Handle("/board/archive", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: boardapi.NewGetArchivedHandler(
+			readDecoder,
+			teamtbl.NewRetriever(db),
+			log,
+		),
+		http.MethodPost: boardapi.NewArchiveHandler(
+			writeDecoder,
+			boardapi.NewIDValidator(),
+			teamtbl.NewBoardArchiver(db, limits),
+			log,
+		),
+		http.MethodDelete: boardapi.NewRestoreHandler(
+			writeDecoder,
+			boardapi.NewIDValidator(),
+			teamtbl.NewBoardArchiver(db, limits),
+			log,
+		),
+	}))
+
+	mux.Handle("/board/columns", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: columnapi.NewPostHandler(
+			writeDecoder,
+			boardapi.NewIDValidator(),
+			columnapi.NewNameValidator(),
+			columnapi.NewColorValidator(),
+			teamtbl.NewRetriever(db),
+			teamtbl.NewBoardUpdater(db),
+			log,
+		),
+		http.MethodPatch: columnapi.NewPatchHandler(
+			writeDecoder,
+			boardapi.NewIDValidator(),
+			columnapi.NewNameValidator(),
+			columnapi.NewColorValidator(),
+			teamtbl.NewRetriever(db),
+			teamtbl.NewBoardUpdater(db),
+			log,
+		),
+		http.MethodPut: columnapi.NewPutHandler(
+			writeDecoder,
+			boardapi.NewIDValidator(),
+			teamtbl.NewRetriever(db),
+			teamtbl.NewBoardUpdater(db),
+			log,
+		),
+		http.MethodDelete: columnapi.NewDeleteHandler(
+			writeDecoder,
+			boardapi.NewIDValidator(),
+			teamtbl.NewRetriever(db),
+			tasktbl.NewRetrieverByBoard(db),
+			tasktbl.NewMultiUpdater(db),
+			teamtbl.NewBoardUpdater(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/user", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodDelete: userapi.NewDeleteHandler(
+			writeDecoder,
+			teamtbl.NewRetriever(db),
+			revocationtbl.NewInserter(db),
+			sessiontbl.NewDeleterByUser(db),
+			membershiptbl.NewDeleter(db),
+			membershiptbl.NewRetrieverByUser(db),
+			usertbl.NewRetriever(db),
+			usertbl.NewUpdater(db),
+			tokentbl.NewRetrieverByUser(db),
+			tokentbl.NewDeleter(db),
+			identitytbl.NewRetrieverByUser(db),
+			identitytbl.NewDeleter(db),
+			usertbl.NewDeleter(db),
+			teamtbl.NewUpdater(db),
+			log,
+		),
+		http.MethodPatch: userapi.NewPatchHandler(
+			writeDecoder,
+			boardapi.NewIDValidator(),
+			teamtbl.NewRetriever(db),
+			teamtbl.NewBoardUpdater(db),
+			log,
+		),
+	}))
+
 	// serve the registered routes
 	log.Info("running team service on port", port)
 	if err := http.ListenAndServe(":"+port, mux); err != nil {
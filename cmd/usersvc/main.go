@@ -10,12 +10,38 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/joho/godotenv"
 
+	"github.com/kxplxn/goteam/internal/usersvc/activeteamapi"
 	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
+	"github.com/kxplxn/goteam/internal/usersvc/logoutapi"
+	"github.com/kxplxn/goteam/internal/usersvc/mfaapi"
+	"github.com/kxplxn/goteam/internal/usersvc/oidcapi"
+	"github.com/kxplxn/goteam/internal/usersvc/passwordapi"
+	"github.com/kxplxn/goteam/internal/usersvc/profileapi"
+	"github.com/kxplxn/goteam/internal/usersvc/refreshapi"
 	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/internal/usersvc/resetapi"
+	"github.com/kxplxn/goteam/internal/usersvc/sessionsapi"
+	"github.com/kxplxn/goteam/internal/usersvc/teamsapi"
+	"github.com/kxplxn/goteam/internal/usersvc/tokenapi"
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/resettbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mail"
+	"github.com/kxplxn/goteam/pkg/mfa"
+	"github.com/kxplxn/goteam/pkg/oidc"
+	"github.com/kxplxn/goteam/pkg/password"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 const (
@@ -40,12 +66,80 @@ const (
 	// the AWS region to connect to for DynamoDB.
 	envAWSRegion = "AWS_REGION"
 
-	// envJWTKey is the name of the environment variable used for signing JWTs.
+	// envJWTKey is the name of the environment variable used for signing
+	// refresh tokens.
 	envJWTKey = "JWT_KEY"
 
+	// envAuthKeys is the name of the environment variable used for listing the
+	// private keys to sign auth tokens with as comma-separated <kid>:<path>
+	// pairs. The first key is used for signing and the rest are only published
+	// so that tokens signed with them stay valid during a key rotation.
+	envAuthKeys = "AUTH_KEYS"
+
 	// envClientOrigin is the name of the environment variable used to set up
 	// CORS with the client app.
 	envClientOrigin = "CLIENT_ORIGIN"
+
+	// envAttemptTableName is the name of the environment variable used for
+	// setting the table to count failed login attempts in. If it is empty,
+	// they are counted in memory, which only works with a single instance.
+	envAttemptTableName = "ATTEMPT_TABLE_NAME"
+
+	// envSMTPHost is the name of the environment variable used for setting the
+	// SMTP server to send emails through. If it is empty, emails are written to
+	// the file set in envMailFile, or to stdout if that is empty as well.
+	envSMTPHost = "SMTP_HOST"
+
+	// envSMTPPort is the name of the environment variable used for setting the
+	// port of the SMTP server.
+	envSMTPPort = "SMTP_PORT"
+
+	// envSMTPUsername is the name of the environment variable used for
+	// authenticating with the SMTP server.
+	envSMTPUsername = "SMTP_USERNAME"
+
+	// envSMTPPassword is the name of the environment variable used for
+	// authenticating with the SMTP server.
+	envSMTPPassword = "SMTP_PASSWORD"
+
+	// envMailFrom is the name of the environment variable used for setting the
+	// address that emails are sent from.
+	envMailFrom = "MAIL_FROM"
+
+	// envMailFile is the name of the environment variable used for setting the
+	// file to write emails to on local.
+	envMailFile = "MAIL_FILE"
+
+	// envOIDCIssuer is the name of the environment variable used for setting
+	// the issuer URL of the OpenID Connect identity provider that users can
+	// log in through. If it is empty, the OIDC routes are not registered.
+	envOIDCIssuer = "OIDC_ISSUER"
+
+	// envOIDCClientID is the name of the environment variable used for setting
+	// the client ID that the user service is registered with at the identity
+	// provider.
+	envOIDCClientID = "OIDC_CLIENT_ID"
+
+	// envOIDCClientSecret is the name of the environment variable used for
+	// setting the client secret that the user service is registered with at
+	// the identity provider. It should be empty for public clients.
+	envOIDCClientSecret = "OIDC_CLIENT_SECRET"
+
+	// envOIDCRedirectURL is the name of the environment variable used for
+	// setting the URL of the OIDC callback route that the identity provider
+	// redirects users back to.
+	envOIDCRedirectURL = "OIDC_REDIRECT_URL"
+
+	// envPasswordPolicy is the name of the environment variable used for
+	// choosing the rules that passwords must follow. It can be "strict" to
+	// require a mix of character classes, or "nist" to only limit length as
+	// NIST recommends. If it is empty, the strict policy is used.
+	envPasswordPolicy = "PASSWORD_POLICY"
+
+	// envBreachedPasswordsFile is the name of the environment variable used
+	// for setting the breached password corpus or bloom filter file that new
+	// passwords are checked against. If it is empty, they are not checked.
+	envBreachedPasswordsFile = "BREACHED_PASSWORDS_FILE"
 )
 
 func main() {
@@ -67,7 +161,21 @@ func main() {
 		awsSecretKey = os.Getenv(envAWSSecretKey)
 		awsRegion    = os.Getenv(envAWSRegion)
 		jwtKey       = os.Getenv(envJWTKey)
+		authKeys     = os.Getenv(envAuthKeys)
 		clientOrigin = os.Getenv(envClientOrigin)
+		attemptTable = os.Getenv(envAttemptTableName)
+		smtpHost     = os.Getenv(envSMTPHost)
+		smtpPort     = os.Getenv(envSMTPPort)
+		smtpUsername = os.Getenv(envSMTPUsername)
+		smtpPassword = os.Getenv(envSMTPPassword)
+		mailFrom     = os.Getenv(envMailFrom)
+		mailFile     = os.Getenv(envMailFile)
+		oidcIssuer   = os.Getenv(envOIDCIssuer)
+		oidcClientID = os.Getenv(envOIDCClientID)
+		oidcSecret   = os.Getenv(envOIDCClientSecret)
+		oidcRedirect = os.Getenv(envOIDCRedirectURL)
+		pwdPolicy    = os.Getenv(envPasswordPolicy)
+		breachedFile = os.Getenv(envBreachedPasswordsFile)
 	)
 
 	// check all environment variables were set
@@ -89,9 +197,26 @@ func main() {
 	case jwtKey:
 		log.Error(envJWTKey, errPostfix)
 		return
+	case authKeys:
+		log.Error(envAuthKeys, errPostfix)
+		return
 	case clientOrigin:
 		log.Error(envClientOrigin, errPostfix)
 		return
+	case mailFrom:
+		log.Error(envMailFrom, errPostfix)
+		return
+	}
+	// - OIDC variables are only needed if an identity provider is set
+	if oidcIssuer != "" {
+		switch "" {
+		case oidcClientID:
+			log.Error(envOIDCClientID, errPostfix)
+			return
+		case oidcRedirect:
+			log.Error(envOIDCRedirectURL, errPostfix)
+			return
+		}
 	}
 
 	// define aws config
@@ -108,27 +233,130 @@ func main() {
 	// create DynamoDB client from config
 	db := dynamodb.NewFromConfig(cfg)
 
+	// load auth token signing keys
+	signingKeys, err := cookie.LoadSigningKeys(authKeys)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
 	// create JWT encoders and decoders
+	// - auth tokens are short-lived and renewed using refresh tokens, which
+	//   are rotated on every use
+	// - auth tokens are signed with asymmetric keys so that other services
+	//   can validate them using the published public keys
+	// - MFA tokens only need to last long enough for the user to enter a code
 	key := []byte(jwtKey)
-	dur := 1 * time.Hour
 	var (
-		inviteDecoder = cookie.NewInviteDecoder(key)
-		authEncoder   = cookie.NewAuthEncoder(key, dur)
+		authEncoder    = cookie.NewAuthEncoder(signingKeys[0], 15*time.Minute)
+		refreshEncoder = cookie.NewRefreshEncoder(key, 30*24*time.Hour)
+		refreshDecoder = cookie.NewRefreshDecoder(key)
+		mfaEncoder     = cookie.NewMFAEncoder(key, 5*time.Minute)
+		mfaDecoder     = cookie.NewMFADecoder(key)
+		authDecoder    = cookie.NewAuthDecoder(
+			cookie.NewStaticKeySet(signingKeys...),
+			revocationtbl.NewChecker(db),
+		)
+	)
+
+	// create password hasher
+	// - passwords are hashed with argon2id, and the bcrypt hashes of users who
+	//   registered before it was adopted are rehashed when they log in
+	pwdHasher := password.NewHasher(password.DefaultParams)
+
+	// create password validator
+	// - the breached password corpus is loaded into memory once on start-up,
+	//   so very large corpora should be turned into a bloom filter with
+	//   cmd/breachfilter first
+	var policy registerapi.PwdPolicy
+	switch pwdPolicy {
+	case "", "strict":
+		policy = registerapi.StrictPwdPolicy
+	case "nist":
+		policy = registerapi.NISTPwdPolicy
+	default:
+		log.Error(envPasswordPolicy, "was invalid:", pwdPolicy)
+		return
+	}
+	var blocklist registerapi.Blocklist
+	if breachedFile != "" {
+		f, err := os.Open(breachedFile)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		blocklist, err = password.ReadBlocklist(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+	}
+	pwdValidator := registerapi.NewPasswordValidator(policy, blocklist)
+
+	// load the default team quotas - teams can override these individually
+	limits, err := quota.LoadLimits()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// create login attempt store
+	attemptRetriever, attemptIncrementer, attemptDeleter := newAttemptStore(
+		db, attemptTable != "",
 	)
 
+	// create mailer
+	// - emails are only sent on production, they are written to a file or
+	//   stdout otherwise
+	var mailer mail.Mailer
+	if smtpHost != "" {
+		mailer = mail.NewSMTPMailer(
+			smtpHost, smtpPort, smtpUsername, smtpPassword, mailFrom,
+		)
+	} else if mailFile != "" {
+		f, err := os.OpenFile(
+			mailFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644,
+		)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		defer f.Close()
+		mailer = mail.NewWriterMailer(f, mailFrom)
+	} else {
+		mailer = mail.NewWriterMailer(os.Stdout, mailFrom)
+	}
+
 	// register handlers for HTTP routes
 	mux := http.NewServeMux()
 
+	mux.Handle("/.well-known/jwks.json", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodGet: api.NewJWKSHandler(
+				cookie.NewJWKS(signingKeys...), log,
+			),
+		},
+	))
+
 	mux.Handle("/register", api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPost: registerapi.NewPostHandler(
 			registerapi.NewUserValidator(
 				registerapi.NewUsernameValidator(),
-				registerapi.NewPasswordValidator(),
+				registerapi.NewEmailValidator(),
+				pwdValidator,
 			),
-			inviteDecoder,
-			registerapi.NewPasswordHasher(),
+			invitetbl.NewConsumer(db),
+			invitetbl.NewReleaser(db),
+			teamtbl.NewRetriever(db),
+			membershiptbl.NewRetrieverByTeam(db),
+			limits,
+			pwdHasher,
 			usertbl.NewInserter(db),
+			membershiptbl.NewInserter(db),
 			authEncoder,
+			refreshEncoder,
+			sessiontbl.NewInserter(db),
 			log,
 		),
 	}))
@@ -136,13 +364,268 @@ func main() {
 	mux.Handle("/login", api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPost: loginapi.NewPostHandler(
 			loginapi.NewValidator(),
+			attemptRetriever,
+			attemptIncrementer,
+			attemptDeleter,
+			usertbl.NewRetrieverAnyCase(db),
+			pwdHasher,
+			pwdHasher,
+			usertbl.NewUpdater(db),
+			teamtbl.NewRetriever(db),
+			mfaEncoder,
+			authEncoder,
+			refreshEncoder,
+			sessiontbl.NewInserter(db),
+			log,
+			log,
+		),
+	}))
+
+	mux.Handle("/login/mfa", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: loginapi.NewMFAHandler(
+			mfaDecoder,
+			attemptRetriever,
+			attemptIncrementer,
+			attemptDeleter,
+			usertbl.NewRetriever(db),
+			mfa.NewTOTP(),
+			usertbl.NewUpdater(db),
+			authEncoder,
+			refreshEncoder,
+			sessiontbl.NewInserter(db),
+			log,
+			log,
+		),
+	}))
+
+	mux.Handle("/refresh", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: refreshapi.NewPostHandler(
+			refreshDecoder,
+			sessiontbl.NewRetriever(db),
+			usertbl.NewRetriever(db),
+			refreshEncoder,
+			authEncoder,
+			sessiontbl.NewUpdater(db),
+			sessiontbl.NewDeleter(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/logout", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: logoutapi.NewPostHandler(
+			authDecoder,
+			refreshDecoder,
+			revocationtbl.NewInserter(db),
+			sessiontbl.NewDeleter(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/sessions", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodDelete: sessionsapi.NewDeleteHandler(
+			authDecoder,
+			revocationtbl.NewInserter(db),
+			sessiontbl.NewDeleterByUser(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/user/password", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPatch: passwordapi.NewPatchHandler(
+			authDecoder,
+			pwdValidator,
 			usertbl.NewRetriever(db),
-			loginapi.NewPasswordComparator(),
+			pwdHasher,
+			pwdHasher,
+			usertbl.NewUpdater(db),
+			revocationtbl.NewInserter(db),
+			sessiontbl.NewDeleterByUser(db),
 			authEncoder,
+			refreshEncoder,
+			sessiontbl.NewInserter(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/user/me", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: profileapi.NewGetHandler(
+			authDecoder, usertbl.NewRetriever(db), log,
+		),
+		http.MethodPatch: profileapi.NewPatchHandler(
+			authDecoder,
+			profileapi.NewProfileValidator(
+				profileapi.NewDisplayNameValidator(),
+				registerapi.NewEmailValidator(),
+				profileapi.NewAvatarURLValidator(),
+				profileapi.NewTimeZoneValidator(),
+				profileapi.NewLocaleValidator(),
+			),
+			usertbl.NewRetriever(db),
+			usertbl.NewUpdater(db),
+			log,
+		),
+		http.MethodDelete: profileapi.NewDeleteHandler(
+			authDecoder,
+			usertbl.NewRetriever(db),
+			pwdHasher,
+			membershiptbl.NewRetrieverByUser(db),
+			teamtbl.NewRetriever(db),
+			teamtbl.NewUpdater(db),
+			membershiptbl.NewDeleter(db),
+			tokentbl.NewRetrieverByUser(db),
+			tokentbl.NewDeleter(db),
+			identitytbl.NewRetrieverByUser(db),
+			identitytbl.NewDeleter(db),
+			attemptDeleter,
+			sessiontbl.NewDeleterByUser(db),
+			revocationtbl.NewInserter(db),
+			usertbl.NewDeleter(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/user/mfa", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: mfaapi.NewPostHandler(
+			authDecoder,
+			mfaDecoder,
+			usertbl.NewRetriever(db),
+			mfa.NewTOTP(),
+			usertbl.NewUpdater(db),
+			log,
+		),
+		http.MethodPatch: mfaapi.NewPatchHandler(
+			authDecoder,
+			mfaDecoder,
+			usertbl.NewRetriever(db),
+			mfa.NewTOTP(),
+			usertbl.NewUpdater(db),
+			authEncoder,
+			refreshEncoder,
+			sessiontbl.NewInserter(db),
+			log,
+		),
+	}))
+
+	// personal access tokens are not accepted on the tokens route so that
+	// they can't be used to create more of themselves
+	mux.Handle("/user/tokens", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: tokenapi.NewGetHandler(
+			authDecoder,
+			tokentbl.NewRetrieverByUser(db),
+			log,
+		),
+		http.MethodPost: tokenapi.NewPostHandler(
+			authDecoder,
+			tokenapi.ValidatePostReq,
+			tokentbl.NewRetrieverByUser(db),
+			tokentbl.NewInserter(db),
+			log,
+		),
+		http.MethodDelete: tokenapi.NewDeleteHandler(
+			authDecoder,
+			tokentbl.NewDeleter(db),
 			log,
 		),
 	}))
 
+	mux.Handle("/user/teams", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: teamsapi.NewGetHandler(
+			authDecoder,
+			membershiptbl.NewRetrieverByUser(db),
+			teamtbl.NewRetriever(db),
+			log,
+		),
+		http.MethodPost: teamsapi.NewPostHandler(
+			authDecoder,
+			invitetbl.NewConsumer(db),
+			invitetbl.NewReleaser(db),
+			teamtbl.NewRetriever(db),
+			membershiptbl.NewRetrieverByTeam(db),
+			limits,
+			membershiptbl.NewInserter(db),
+			log,
+		),
+	}))
+
+	mux.Handle("/user/teams/active", api.NewHandler(
+		map[string]api.MethodHandler{
+			http.MethodPut: activeteamapi.NewPutHandler(
+				authDecoder,
+				membershiptbl.NewRetriever(db),
+				usertbl.NewRetriever(db),
+				teamtbl.NewRetriever(db),
+				usertbl.NewUpdater(db),
+				authEncoder,
+				log,
+			),
+		},
+	))
+
+	mux.Handle("/reset", api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: resetapi.NewPostHandler(
+			usertbl.NewRetrieverAnyCase(db),
+			resettbl.NewInserter(db),
+			mailer,
+			clientOrigin+"/reset",
+			log,
+		),
+		http.MethodPatch: resetapi.NewPatchHandler(
+			pwdValidator,
+			resettbl.NewConsumer(db),
+			usertbl.NewRetriever(db),
+			pwdHasher,
+			usertbl.NewUpdater(db),
+			revocationtbl.NewInserter(db),
+			sessiontbl.NewDeleterByUser(db),
+			log,
+		),
+	}))
+
+	if oidcIssuer != "" {
+		authenticator := oidc.NewProvider(
+			oidcIssuer, oidcClientID, oidcSecret, oidcRedirect,
+		)
+
+		mux.Handle("/oidc/login", api.NewHandler(map[string]api.MethodHandler{
+			http.MethodGet: oidcapi.NewLoginHandler(
+				authenticator,
+				cookie.NewOIDCEncoder(key, 10*time.Minute),
+				log,
+			),
+		}))
+
+		mux.Handle("/oidc/link", api.NewHandler(map[string]api.MethodHandler{
+			http.MethodGet: oidcapi.NewLinkHandler(
+				authDecoder,
+				authenticator,
+				cookie.NewOIDCEncoder(key, 10*time.Minute),
+				log,
+			),
+		}))
+
+		mux.Handle("/oidc/callback", api.NewHandler(
+			map[string]api.MethodHandler{
+				http.MethodGet: oidcapi.NewCallbackHandler(
+					cookie.NewOIDCDecoder(key),
+					authenticator,
+					identitytbl.NewRetriever(db),
+					identitytbl.NewInserter(db),
+					usertbl.NewRetrieverAnyCase(db),
+					registerapi.NewUsernameValidator(),
+					usertbl.NewInserter(db),
+					membershiptbl.NewInserter(db),
+					teamtbl.NewRetriever(db),
+					mfaEncoder,
+					authEncoder,
+					refreshEncoder,
+					sessiontbl.NewInserter(db),
+					clientOrigin,
+					log,
+				),
+			},
+		))
+	}
+
 	// serve the registered routes
 	log.Info("running user service on port", port)
 	if err := http.ListenAndServe(":"+port, mux); err != nil {
@@ -150,3 +633,19 @@ func main() {
 		return
 	}
 }
+
+// newAttemptStore returns the types used for counting failed login attempts.
+// They use the attempt table if useTable is true, and memory otherwise.
+func newAttemptStore(client *dynamodb.Client, useTable bool) (
+	db.Retriever[attempttbl.Attempt],
+	db.Incrementer[attempttbl.Attempt],
+	db.Deleter,
+) {
+	if useTable {
+		return attempttbl.NewRetriever(client),
+			attempttbl.NewIncrementer(client),
+			attempttbl.NewDeleter(client)
+	}
+	attempts := attempttbl.NewMemory()
+	return attempts, attempts, attempts
+}
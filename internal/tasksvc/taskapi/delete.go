@@ -7,7 +7,10 @@ import (
 
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 // DeleteResp defines the body of DELETE task responses.
@@ -18,21 +21,27 @@ type DeleteResp struct {
 // DeleteHandler is an api.MethodHandler that can be used to handle DELETE
 // requests made to the task route.
 type DeleteHandler struct {
-	authDecoder cookie.Decoder[cookie.Auth]
-	taskDeleter db.DeleterDualKey
-	log         log.Errorer
+	authDecoder   cookie.Decoder[cookie.Auth]
+	taskRetriever db.RetrieverDualKey[tasktbl.Task]
+	teamRetriever db.Retriever[teamtbl.Team]
+	taskDeleter   db.DeleterDualKey
+	log           log.Errorer
 }
 
 // NewDeleteHandler creates and returns a new DELETEHandler.
 func NewDeleteHandler(
 	authDecoder cookie.Decoder[cookie.Auth],
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	teamRetriever db.Retriever[teamtbl.Team],
 	taskDeleter db.DeleterDualKey,
 	log log.Errorer,
 ) DeleteHandler {
 	return DeleteHandler{
-		authDecoder: authDecoder,
-		taskDeleter: taskDeleter,
-		log:         log,
+		authDecoder:   authDecoder,
+		taskRetriever: taskRetriever,
+		teamRetriever: teamRetriever,
+		taskDeleter:   taskDeleter,
+		log:           log,
 	}
 }
 
@@ -41,7 +50,7 @@ func (h DeleteHandler) Handle(
 	w http.ResponseWriter, r *http.Request, username string,
 ) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		if encodeErr := json.NewEncoder(w).Encode(DeleteResp{
@@ -66,25 +75,62 @@ func (h DeleteHandler) Handle(
 		}); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			h.log.Error(err)
-			return
 		}
+		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
+	// validate user can delete tasks
+	if !role.Can(auth.Role, role.DeleteTask) {
 		w.WriteHeader(http.StatusForbidden)
 		if err = json.NewEncoder(w).Encode(DeleteResp{
 			Error: "Only team admins can delete tasks.",
 		}); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			h.log.Error(err)
-			return
 		}
+		return
+	}
+
+	// retrieve the task to find out which board it is on
+	id := r.URL.Query().Get("id")
+	task, err := h.taskRetriever.Retrieve(r.Context(), auth.TeamID, id)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Task not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate the task's board is not archived - tasks left behind by a
+	// board that no longer exists can still be deleted
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if team.IsBoardArchived(task.BoardID) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Tasks of archived boards cannot be changed.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
 	}
 
 	// delete task from the task table
 	if err = h.taskDeleter.Delete(
-		r.Context(), auth.TeamID, r.URL.Query().Get("id"),
+		r.Context(), auth.TeamID, id,
 	); errors.Is(err, db.ErrNoItem) {
 		w.WriteHeader(http.StatusNotFound)
 		if err := json.NewEncoder(w).Encode(DeleteResp{
@@ -11,86 +11,171 @@ import (
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 // TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
 // behaves correctly in all possible scenarios.
 func TestDeleteHandler(t *testing.T) {
 	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	taskRetriever := &db.FakeRetrieverDualKey[tasktbl.Task]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
 	taskDeleter := &db.FakeDeleterDualKey{}
 	log := &log.FakeErrorer{}
-	sut := NewDeleteHandler(authDecoder, taskDeleter, log)
+	sut := NewDeleteHandler(
+		authDecoder, taskRetriever, teamRetriever, taskDeleter, log,
+	)
+
+	task := tasktbl.Task{ID: "taskid", BoardID: "boardid"}
+	archivedTeam := teamtbl.Team{
+		Boards: []teamtbl.Board{{ID: "boardid", Archived: true}},
+	}
 
 	for _, c := range []struct {
-		name          string
-		authToken     string
-		errDecodeAuth error
-		auth          cookie.Auth
-		errDeleteTask error
-		wantStatus    int
-		assertFunc    func(*testing.T, *http.Response, []any)
+		name            string
+		authToken       string
+		errDecodeAuth   error
+		auth            cookie.Auth
+		task            tasktbl.Task
+		errRetrieveTask error
+		team            teamtbl.Team
+		errRetrieveTeam error
+		errDeleteTask   error
+		wantStatus      int
+		assertFunc      func(*testing.T, *http.Response, []any)
 	}{
 		{
-			name:          "NoAuth",
-			authToken:     "",
-			errDecodeAuth: nil,
-			auth:          cookie.Auth{},
-			errDeleteTask: nil,
-			wantStatus:    http.StatusUnauthorized,
-			assertFunc:    assert.OnRespErr("Auth token not found."),
+			name:            "NoAuth",
+			authToken:       "",
+			errDecodeAuth:   nil,
+			auth:            cookie.Auth{},
+			task:            task,
+			errRetrieveTask: nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errDeleteTask:   nil,
+			wantStatus:      http.StatusUnauthorized,
+			assertFunc:      assert.OnRespErr("Auth token not found."),
 		},
 		{
-			name:          "ErrDecodeAuth",
-			authToken:     "nonempty",
-			errDecodeAuth: errors.New("decode auth failed"),
-			auth:          cookie.Auth{},
-			errDeleteTask: nil,
-			wantStatus:    http.StatusUnauthorized,
-			assertFunc:    assert.OnRespErr("Invalid auth token."),
+			name:            "ErrDecodeAuth",
+			authToken:       "nonempty",
+			errDecodeAuth:   errors.New("decode auth failed"),
+			auth:            cookie.Auth{},
+			task:            task,
+			errRetrieveTask: nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errDeleteTask:   nil,
+			wantStatus:      http.StatusUnauthorized,
+			assertFunc:      assert.OnRespErr("Invalid auth token."),
 		},
 		{
-			name:          "NotAdmin",
-			authToken:     "nonempty",
-			errDecodeAuth: nil,
-			auth:          cookie.Auth{IsAdmin: false},
-			errDeleteTask: nil,
-			wantStatus:    http.StatusForbidden,
+			name:            "Member",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			auth:            cookie.Auth{Role: role.Member},
+			task:            task,
+			errRetrieveTask: nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errDeleteTask:   nil,
+			wantStatus:      http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
 				"Only team admins can delete tasks.",
 			),
 		},
 		{
-			name:          "NotFound",
-			authToken:     "nonempty",
-			errDecodeAuth: nil,
-			auth:          cookie.Auth{IsAdmin: true},
-			errDeleteTask: db.ErrNoItem,
-			wantStatus:    http.StatusNotFound,
-			assertFunc:    assert.OnRespErr("Task not found."),
+			name:            "NotFound",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			auth:            cookie.Auth{Role: role.Admin},
+			task:            tasktbl.Task{},
+			errRetrieveTask: db.ErrNoItem,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errDeleteTask:   nil,
+			wantStatus:      http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Task not found."),
+		},
+		{
+			name:            "ErrRetrieve",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			auth:            cookie.Auth{Role: role.Admin},
+			task:            tasktbl.Task{},
+			errRetrieveTask: errors.New("retrieve task failed"),
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errDeleteTask:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve task failed"),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			auth:            cookie.Auth{Role: role.Admin},
+			task:            task,
+			errRetrieveTask: nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: errors.New("retrieve team failed"),
+			errDeleteTask:   nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:            "ArchivedBoard",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			auth:            cookie.Auth{Role: role.Admin},
+			task:            task,
+			errRetrieveTask: nil,
+			team:            archivedTeam,
+			errRetrieveTeam: nil,
+			errDeleteTask:   nil,
+			wantStatus:      http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Tasks of archived boards cannot be changed.",
+			),
 		},
 		{
-			name:          "ErrDeleteTask",
-			authToken:     "nonempty",
-			errDecodeAuth: nil,
-			auth:          cookie.Auth{IsAdmin: true},
-			errDeleteTask: errors.New("delete task failed"),
-			wantStatus:    http.StatusInternalServerError,
-			assertFunc:    assert.OnLoggedErr("delete task failed"),
+			name:            "ErrDeleteTask",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			auth:            cookie.Auth{Role: role.Admin},
+			task:            task,
+			errRetrieveTask: nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errDeleteTask:   errors.New("delete task failed"),
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("delete task failed"),
 		},
 		{
-			name:          "Success",
-			authToken:     "nonempty",
-			errDecodeAuth: nil,
-			auth:          cookie.Auth{IsAdmin: true},
-			errDeleteTask: nil,
-			wantStatus:    http.StatusOK,
-			assertFunc:    func(*testing.T, *http.Response, []any) {},
+			name:            "Success",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			auth:            cookie.Auth{Role: role.Admin},
+			task:            task,
+			errRetrieveTask: nil,
+			team:            teamtbl.Team{},
+			errRetrieveTeam: nil,
+			errDeleteTask:   nil,
+			wantStatus:      http.StatusOK,
+			assertFunc:      func(*testing.T, *http.Response, []any) {},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			authDecoder.Res = c.auth
 			authDecoder.Err = c.errDecodeAuth
+			taskRetriever.Res = c.task
+			taskRetriever.Err = c.errRetrieveTask
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
 			taskDeleter.Err = c.errDeleteTask
 
 			r := httptest.NewRequest("", "/?id=foo", nil)
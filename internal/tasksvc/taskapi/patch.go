@@ -4,12 +4,21 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
+	"github.com/kxplxn/goteam/pkg/wip"
 )
 
 // PatchReq defines the body of PATCH task requests.
@@ -23,11 +32,16 @@ type PatchResp struct {
 // PatchHandler is an api.MethodHandler that can handle PATCH requests sent to
 // the task route.
 type PatchHandler struct {
-	authDecoder        cookie.Decoder[cookie.Auth]
-	titleValidator     validator.String
-	subtTitleValidator validator.String
-	taskUpdater        db.Updater[tasktbl.Task]
-	log                log.Errorer
+	authDecoder         cookie.Decoder[cookie.Auth]
+	titleValidator      validator.String
+	subtTitleValidator  validator.String
+	taskRetriever       db.RetrieverDualKey[tasktbl.Task]
+	teamRetriever       db.Retriever[teamtbl.Team]
+	boardTasksRetriever db.Retriever[[]tasktbl.Task]
+	limits              quota.Limits
+	overrideInserter    db.Inserter[overridetbl.Override]
+	taskUpdater         db.Updater[tasktbl.Task]
+	log                 log.Errorer
 }
 
 // NewPatchHandler returns a new PatchHandler.
@@ -35,15 +49,25 @@ func NewPatchHandler(
 	authDecoder cookie.Decoder[cookie.Auth],
 	taskTitleValidator validator.String,
 	subtaskTitleValidator validator.String,
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	teamRetriever db.Retriever[teamtbl.Team],
+	boardTasksRetriever db.Retriever[[]tasktbl.Task],
+	limits quota.Limits,
+	overrideInserter db.Inserter[overridetbl.Override],
 	taskUpdater db.Updater[tasktbl.Task],
 	log log.Errorer,
 ) *PatchHandler {
 	return &PatchHandler{
-		authDecoder:        authDecoder,
-		titleValidator:     taskTitleValidator,
-		subtTitleValidator: subtaskTitleValidator,
-		taskUpdater:        taskUpdater,
-		log:                log,
+		authDecoder:         authDecoder,
+		titleValidator:      taskTitleValidator,
+		subtTitleValidator:  subtaskTitleValidator,
+		taskRetriever:       taskRetriever,
+		teamRetriever:       teamRetriever,
+		boardTasksRetriever: boardTasksRetriever,
+		limits:              limits,
+		overrideInserter:    overrideInserter,
+		taskUpdater:         taskUpdater,
+		log:                 log,
 	}
 }
 
@@ -52,7 +76,7 @@ func (h *PatchHandler) Handle(
 	w http.ResponseWriter, r *http.Request, username string,
 ) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		if encodeErr := json.NewEncoder(w).Encode(PatchResp{
@@ -81,8 +105,8 @@ func (h *PatchHandler) Handle(
 		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
+	// validate user can edit tasks
+	if !role.Can(auth.Role, role.EditTask) {
 		w.WriteHeader(http.StatusForbidden)
 		if err := json.NewEncoder(w).Encode(PatchResp{
 			Error: "Only team admins can edit tasks.",
@@ -149,9 +173,138 @@ func (h *PatchHandler) Handle(
 		}
 	}
 
-	// update task in task table
+	// retrieve the task as it is stored to validate it isn't moved to another
+	// board since the tasks per board quota is only checked on task creation
+	stored, err := h.taskRetriever.Retrieve(r.Context(), auth.TeamID, req.ID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Task not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if req.BoardID == "" {
+		req.BoardID = stored.BoardID
+	} else if req.BoardID != stored.BoardID {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Tasks cannot be moved between boards.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate the board belongs to the user's active team, that it isn't
+	// archived, and that it has the task's column
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if !team.HasBoard(req.BoardID) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if team.IsBoardArchived(req.BoardID) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Tasks of archived boards cannot be changed.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	if !team.HasColumn(req.BoardID, req.ColID) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Column not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate the task doesn't exceed the team's subtask quota
+	limit := h.limits.Override(team.Quota).SubtasksPerTask
+	if !quota.Allows(limit, 0, len(req.Subtasks)) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Tasks cannot have more than " + strconv.Itoa(limit) +
+				" subtasks.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate the task isn't moved into a column beyond its WIP limit unless
+	// the user explicitly overrides it, in which case the override is recorded
+	// before the task is updated
 	task := tasktbl.Task(req)
 	task.TeamID = auth.TeamID
+	changed := []tasktbl.Task{task}
+	if wip.Limited(team, req.BoardID, changed) {
+		tasks, err := h.boardTasksRetriever.Retrieve(
+			r.Context(), req.BoardID,
+		)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+
+		violations := wip.Check(team, req.BoardID, tasks, changed)
+		if len(violations) > 0 && !wip.IsOverride(r) {
+			w.WriteHeader(http.StatusConflict)
+			if err := json.NewEncoder(w).Encode(PatchResp{
+				Error: violations[0].Msg(),
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
+		}
+		for _, v := range violations {
+			if err = h.overrideInserter.Insert(
+				r.Context(), overridetbl.NewOverride(
+					v.BoardID,
+					uuid.NewString(),
+					auth.TeamID,
+					v.Col.ID,
+					auth.Username,
+					v.Col.WIPLimit,
+					v.Count,
+					time.Now().Unix(),
+				),
+			); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+				return
+			}
+		}
+	}
+
+	// update task in task table
 	err = h.taskUpdater.Update(r.Context(), task)
 	if errors.Is(err, db.ErrNoItem) {
 		w.WriteHeader(http.StatusNotFound)
@@ -13,8 +13,12 @@ import (
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -23,16 +27,35 @@ func TestPatchHandler(t *testing.T) {
 	decodeAuth := &cookie.FakeDecoder[cookie.Auth]{}
 	titleValidator := &api.FakeStringValidator{}
 	subtTitleValidator := &api.FakeStringValidator{}
+	taskRetriever := &db.FakeRetrieverDualKey[tasktbl.Task]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	boardTasksRetriever := &db.FakeRetriever[[]tasktbl.Task]{}
+	overrideInserter := &db.FakeInserter[overridetbl.Override]{}
 	taskUpdater := &db.FakeUpdater[tasktbl.Task]{}
 	log := &log.FakeErrorer{}
 	sut := NewPatchHandler(
 		decodeAuth,
 		titleValidator,
 		subtTitleValidator,
+		taskRetriever,
+		teamRetriever,
+		boardTasksRetriever,
+		quota.Limits{SubtasksPerTask: 2},
+		overrideInserter,
 		taskUpdater,
 		log,
 	)
 
+	team := teamtbl.Team{Boards: []teamtbl.Board{{ID: "boardid"}}}
+	wipTeam := teamtbl.Team{Boards: []teamtbl.Board{{
+		ID: "boardid",
+		Columns: []teamtbl.Column{
+			teamtbl.NewColumn("inbox", "Inbox", "", 1),
+		},
+	}}}
+	inboxTasks := []tasktbl.Task{{ID: "taskid", ColID: "inbox"}}
+	task := tasktbl.Task{ID: "qwerty", BoardID: "boardid", ColID: "ready"}
+
 	for _, c := range []struct {
 		name                 string
 		authToken            string
@@ -40,6 +63,14 @@ func TestPatchHandler(t *testing.T) {
 		errDecodeAuth        error
 		errValidateTitle     error
 		errValidateSubtTitle error
+		task                 tasktbl.Task
+		errRetrieveTask      error
+		team                 teamtbl.Team
+		errRetrieveTeam      error
+		tasks                []tasktbl.Task
+		errRetrieveTasks     error
+		override             bool
+		errInsertOverride    error
 		taskUpdaterErr       error
 		wantStatusCode       int
 		assertFunc           func(*testing.T, *http.Response, []any)
@@ -51,6 +82,14 @@ func TestPatchHandler(t *testing.T) {
 			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: nil,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusUnauthorized,
 			assertFunc:           assert.OnRespErr("Auth token not found."),
@@ -62,17 +101,33 @@ func TestPatchHandler(t *testing.T) {
 			errDecodeAuth:        cookie.ErrInvalid,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: nil,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusUnauthorized,
 			assertFunc:           assert.OnRespErr("Invalid auth token."),
 		},
 		{
-			name:                 "NotAdmin",
+			name:                 "Member",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: false},
+			authDecoded:          cookie.Auth{Role: role.Member},
 			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: nil,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
@@ -82,10 +137,18 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:                 "TaskTitleEmpty",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     validator.ErrEmpty,
 			errValidateSubtTitle: nil,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
@@ -95,10 +158,18 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:                 "TaskTitleTooLong",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     validator.ErrTooLong,
 			errValidateSubtTitle: nil,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
@@ -108,10 +179,18 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:                 "TaskTitleErr",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     validator.ErrWrongFormat,
 			errValidateSubtTitle: nil,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusInternalServerError,
 			assertFunc: assert.OnLoggedErr(
@@ -121,10 +200,18 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:                 "SubtaskTitleEmpty",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: validator.ErrEmpty,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
@@ -134,10 +221,18 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:                 "SubtaskTitleTooLong",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: validator.ErrTooLong,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
@@ -147,23 +242,286 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:                 "SubtaskTitleErr",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: validator.ErrWrongFormat,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusInternalServerError,
 			assertFunc: assert.OnLoggedErr(
 				validator.ErrWrongFormat.Error(),
 			),
 		},
+		{
+			name:                 "ErrRetrieveTask",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      errors.New("retrieve task failed"),
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
+			taskUpdaterErr:       nil,
+			wantStatusCode:       http.StatusInternalServerError,
+			assertFunc:           assert.OnLoggedErr("retrieve task failed"),
+		},
 		{
 			name:                 "TaskNotFound",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 tasktbl.Task{},
+			errRetrieveTask:      db.ErrNoItem,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
+			taskUpdaterErr:       nil,
+			wantStatusCode:       http.StatusNotFound,
+			assertFunc:           assert.OnRespErr("Task not found."),
+		},
+		{
+			name:                 "BoardChanged",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task: tasktbl.Task{
+				ID: "qwerty", BoardID: "otherboardid", ColID: "ready",
+			},
+			errRetrieveTask:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			taskUpdaterErr:    nil,
+			wantStatusCode:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Tasks cannot be moved between boards.",
+			),
+		},
+		{
+			name:                 "ErrRetrieveTeam",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      errors.New("retrieve team failed"),
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
+			taskUpdaterErr:       nil,
+			wantStatusCode:       http.StatusInternalServerError,
+			assertFunc:           assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:                 "BoardNotInTeam",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 teamtbl.Team{},
+			errRetrieveTeam:      db.ErrNoItem,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
+			taskUpdaterErr:       nil,
+			wantStatusCode:       http.StatusNotFound,
+			assertFunc:           assert.OnRespErr("Board not found."),
+		},
+		{
+			name:                 "ArchivedBoard",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team: teamtbl.Team{
+				Boards: []teamtbl.Board{{ID: "boardid", Archived: true}},
+			},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			taskUpdaterErr:    nil,
+			wantStatusCode:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Tasks of archived boards cannot be changed.",
+			),
+		},
+		{
+			name:                 "ColumnNotFound",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team: teamtbl.Team{Boards: []teamtbl.Board{{
+				ID:      "boardid",
+				Columns: []teamtbl.Column{{ID: "todo"}},
+			}}},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			taskUpdaterErr:    nil,
+			wantStatusCode:    http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Column not found."),
+		},
+		{
+			name:                 "SubtaskLimitReached",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team: teamtbl.Team{
+				Boards: team.Boards,
+				Quota:  quota.Limits{SubtasksPerTask: 1},
+			},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			taskUpdaterErr:    nil,
+			wantStatusCode:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Tasks cannot have more than 1 subtasks.",
+			),
+		},
+		{
+			name:                 "ErrRetrieveTasks",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 wipTeam,
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     errors.New("retrieve tasks failed"),
+			override:             false,
+			errInsertOverride:    nil,
+			taskUpdaterErr:       nil,
+			wantStatusCode:       http.StatusInternalServerError,
+			assertFunc:           assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:                 "WIPLimitReached",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 wipTeam,
+			errRetrieveTeam:      nil,
+			tasks:                inboxTasks,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
+			taskUpdaterErr:       nil,
+			wantStatusCode:       http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Column \"Inbox\" cannot have more than 1 tasks without " +
+					"overriding its WIP limit.",
+			),
+		},
+		{
+			name:                 "ErrInsertOverride",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 wipTeam,
+			errRetrieveTeam:      nil,
+			tasks:                inboxTasks,
+			errRetrieveTasks:     nil,
+			override:             true,
+			errInsertOverride:    errors.New("insert override failed"),
+			taskUpdaterErr:       nil,
+			wantStatusCode:       http.StatusInternalServerError,
+			assertFunc:           assert.OnLoggedErr("insert override failed"),
+		},
+		{
+			name:                 "WIPLimitOverridden",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 wipTeam,
+			errRetrieveTeam:      nil,
+			tasks:                inboxTasks,
+			errRetrieveTasks:     nil,
+			override:             true,
+			errInsertOverride:    nil,
+			taskUpdaterErr:       nil,
+			wantStatusCode:       http.StatusOK,
+			assertFunc:           func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                 "TaskDeleted",
+			authToken:            "nonempty",
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
+			errDecodeAuth:        nil,
+			errValidateTitle:     nil,
+			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       db.ErrNoItem,
 			wantStatusCode:       http.StatusNotFound,
 			assertFunc:           assert.OnRespErr("Task not found."),
@@ -171,10 +529,18 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:                 "TaskUpdaterErr",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       errors.New("update task failed"),
 			wantStatusCode:       http.StatusInternalServerError,
 			assertFunc:           assert.OnLoggedErr("update task failed"),
@@ -182,10 +548,18 @@ func TestPatchHandler(t *testing.T) {
 		{
 			name:                 "Success",
 			authToken:            "nonempty",
-			authDecoded:          cookie.Auth{IsAdmin: true, TeamID: "21"},
+			authDecoded:          cookie.Auth{Role: role.Admin, TeamID: "21"},
 			errDecodeAuth:        nil,
 			errValidateTitle:     nil,
 			errValidateSubtTitle: nil,
+			task:                 task,
+			errRetrieveTask:      nil,
+			team:                 team,
+			errRetrieveTeam:      nil,
+			tasks:                nil,
+			errRetrieveTasks:     nil,
+			override:             false,
+			errInsertOverride:    nil,
 			taskUpdaterErr:       nil,
 			wantStatusCode:       http.StatusOK,
 			assertFunc:           func(*testing.T, *http.Response, []any) {},
@@ -196,13 +570,26 @@ func TestPatchHandler(t *testing.T) {
 			decodeAuth.Err = c.errDecodeAuth
 			titleValidator.Err = c.errValidateTitle
 			subtTitleValidator.Err = c.errValidateSubtTitle
+			taskRetriever.Res = c.task
+			taskRetriever.Err = c.errRetrieveTask
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			boardTasksRetriever.Res = c.tasks
+			boardTasksRetriever.Err = c.errRetrieveTasks
+			overrideInserter.Err = c.errInsertOverride
 			taskUpdater.Err = c.taskUpdaterErr
+			url := "/?id=qwerty"
+			if c.override {
+				url += "&overrideWIP=true"
+			}
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest("", "/?id=qwerty", strings.NewReader(`{
-				"column":      0,
+			r := httptest.NewRequest("", url, strings.NewReader(`{
+				"id":          "qwerty",
+				"boardID":     "boardid",
+				"colID":       "inbox",
 				"title":       "",
 				"description": "",
-				"subtasks":    [{"title": ""}]
+				"subtasks":    [{"title": ""}, {"title": ""}]
 			}`))
 			if c.authToken != "" {
 				r.AddCookie(&http.Cookie{
@@ -4,20 +4,27 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
+	"github.com/kxplxn/goteam/pkg/wip"
 )
 
 // PostReq defines the body of POST task requests.
 type PostReq struct {
 	BoardID     string            `json:"boardID"`
-	ColNo       int               `json:"colNo"`
+	ColID       string            `json:"colID"`
 	Title       string            `json:"title"`
 	Description string            `json:"description"`
 	Order       int               `json:"order"`
@@ -32,24 +39,36 @@ type PostResp struct {
 // PostHandler is an api.MethodHandler that can be used to handle POST requests
 // sent to the task route.
 type PostHandler struct {
-	authDecoder  cookie.Decoder[cookie.Auth]
-	validateReq  validator.Func[PostReq]
-	taskInserter db.Inserter[tasktbl.Task]
-	log          log.Errorer
+	authDecoder      cookie.Decoder[cookie.Auth]
+	validateReq      validator.Func[PostReq]
+	teamRetriever    db.Retriever[teamtbl.Team]
+	taskRetriever    db.Retriever[[]tasktbl.Task]
+	limits           quota.Limits
+	overrideInserter db.Inserter[overridetbl.Override]
+	taskInserter     db.Inserter[tasktbl.Task]
+	log              log.Errorer
 }
 
 // NewPostHandler creates and returns a new POSTHandler.
 func NewPostHandler(
 	authDecoder cookie.Decoder[cookie.Auth],
 	validateReq validator.Func[PostReq],
+	teamRetriever db.Retriever[teamtbl.Team],
+	taskRetriever db.Retriever[[]tasktbl.Task],
+	limits quota.Limits,
+	overrideInserter db.Inserter[overridetbl.Override],
 	taskInserter db.Inserter[tasktbl.Task],
 	log log.Errorer,
 ) *PostHandler {
 	return &PostHandler{
-		authDecoder:  authDecoder,
-		validateReq:  validateReq,
-		taskInserter: taskInserter,
-		log:          log,
+		authDecoder:      authDecoder,
+		validateReq:      validateReq,
+		teamRetriever:    teamRetriever,
+		taskRetriever:    taskRetriever,
+		limits:           limits,
+		overrideInserter: overrideInserter,
+		taskInserter:     taskInserter,
+		log:              log,
 	}
 }
 
@@ -58,7 +77,7 @@ func (h *PostHandler) Handle(
 	w http.ResponseWriter, r *http.Request, _ string,
 ) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		if err = json.NewEncoder(w).Encode(PostResp{
@@ -87,11 +106,13 @@ func (h *PostHandler) Handle(
 		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
+	// validate user can create tasks - whether they can do so on the board
+	// they specified is checked after the request is validated if their role
+	// only allows it on the boards that they are a member of
+	if role.Check(auth.Role, role.CreateTask) == role.Denied {
 		w.WriteHeader(http.StatusForbidden)
 		if err := json.NewEncoder(w).Encode(PostResp{
-			Error: "Only team admins can create tasks.",
+			Error: "You do not have permission to create tasks.",
 		}); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			h.log.Error(err)
@@ -115,8 +136,8 @@ func (h *PostHandler) Handle(
 			msg = "Board ID cannot be empty."
 		case errors.Is(err, errParseBoardID):
 			msg = "Board ID is must be a valid UUID."
-		case errors.Is(err, errColNoOutOfBounds):
-			msg = "Column number must be between 0 and 3."
+		case errors.Is(err, errColIDEmpty):
+			msg = "Column ID cannot be empty."
 		case errors.Is(err, errTitleEmpty):
 			msg = "Task title cannot be empty."
 		case errors.Is(err, errTitleTooLong):
@@ -143,6 +164,130 @@ func (h *PostHandler) Handle(
 		return
 	}
 
+	// validate the board belongs to the user's active team, that it isn't
+	// archived, that it has the task's column, and that the user is a member
+	// of it if their role requires it
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if !team.HasBoard(req.BoardID) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if team.IsBoardArchived(req.BoardID) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Tasks of archived boards cannot be changed.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if !team.HasColumn(req.BoardID, req.ColID) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Column not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if !role.CanOnBoard(
+		auth.Role,
+		role.CreateTask,
+		team.IsBoardMember(req.BoardID, auth.Username),
+	) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "You can only create tasks on boards you are a member of.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate the task fits within the team's quotas - the board's tasks are
+	// only retrieved when the board has a limit or the task's column has a WIP
+	// limit since neither is the case by default
+	limits := h.limits.Override(team.Quota)
+	if !quota.Allows(limits.SubtasksPerTask, 0, len(req.Subtasks)) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Tasks cannot have more than " +
+				strconv.Itoa(limits.SubtasksPerTask) + " subtasks.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	added := []tasktbl.Task{{BoardID: req.BoardID, ColID: req.ColID}}
+	var tasks []tasktbl.Task
+	if limits.TasksPerBoard >= 0 || wip.Limited(team, req.BoardID, added) {
+		tasks, err = h.taskRetriever.Retrieve(r.Context(), req.BoardID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+	}
+	if !quota.Allows(limits.TasksPerBoard, len(tasks), 1) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "This board has reached its limit of " +
+				strconv.Itoa(limits.TasksPerBoard) + " tasks.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate the task doesn't exceed its column's WIP limit unless the user
+	// explicitly overrides it, in which case the override is recorded before
+	// the task is inserted
+	violations := wip.Check(team, req.BoardID, tasks, added)
+	if len(violations) > 0 && !wip.IsOverride(r) {
+		w.WriteHeader(http.StatusConflict)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: violations[0].Msg(),
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	for _, v := range violations {
+		if err = h.overrideInserter.Insert(
+			r.Context(), overridetbl.NewOverride(
+				v.BoardID,
+				uuid.NewString(),
+				auth.TeamID,
+				v.Col.ID,
+				auth.Username,
+				v.Col.WIPLimit,
+				v.Count,
+				time.Now().Unix(),
+			),
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+	}
+
 	// insert a new task into the task table - retry up to 3 times for the
 	// unlikely event that the generated UUID is a duplicate
 	for i := 0; i < 3; i++ {
@@ -150,7 +295,7 @@ func (h *PostHandler) Handle(
 		if err = h.taskInserter.Insert(r.Context(), tasktbl.NewTask(
 			auth.TeamID,
 			req.BoardID,
-			req.ColNo,
+			req.ColID,
 			id,
 			req.Title,
 			req.Description,
@@ -12,8 +12,12 @@ import (
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -22,194 +26,578 @@ import (
 func TestPostHandler(t *testing.T) {
 	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
 	validate := &validator.FakeFunc[PostReq]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	taskRetriever := &db.FakeRetriever[[]tasktbl.Task]{}
+	overrideInserter := &db.FakeInserter[overridetbl.Override]{}
 	taskInserter := &db.FakeInserter[tasktbl.Task]{}
 	log := &log.FakeErrorer{}
 	sut := NewPostHandler(
 		authDecoder,
 		validate.Func,
+		teamRetriever,
+		taskRetriever,
+		quota.Limits{TasksPerBoard: 2, SubtasksPerTask: 2},
+		overrideInserter,
 		taskInserter,
 		log,
 	)
 
+	team := teamtbl.Team{Boards: []teamtbl.Board{
+		{ID: "boardid", Members: []string{"bob123"}},
+	}}
+	wipTeam := teamtbl.Team{Boards: []teamtbl.Board{{
+		ID:      "boardid",
+		Columns: []teamtbl.Column{teamtbl.NewColumn("go", "Go!", "", 1)},
+	}}}
+
 	for _, c := range []struct {
-		name          string
-		authToken     string
-		authDecoded   cookie.Auth
-		errDecodeAuth error
-		errValidate   error
-		errInsertTask error
-		wantStatus    int
-		assertFunc    func(*testing.T, *http.Response, []any)
+		name              string
+		authToken         string
+		authDecoded       cookie.Auth
+		errDecodeAuth     error
+		errValidate       error
+		team              teamtbl.Team
+		errRetrieve       error
+		tasks             []tasktbl.Task
+		errRetrieveTasks  error
+		override          bool
+		errInsertOverride error
+		errInsertTask     error
+		wantStatus        int
+		assertFunc        func(*testing.T, *http.Response, []any)
 	}{
 		{
-			name:          "NoAuth",
-			authToken:     "",
-			errDecodeAuth: cookie.ErrInvalid,
-			errValidate:   nil,
-			errInsertTask: nil,
-			wantStatus:    http.StatusUnauthorized,
-			assertFunc:    assert.OnRespErr("Auth token not found."),
+			name:              "NoAuth",
+			authToken:         "",
+			errDecodeAuth:     cookie.ErrInvalid,
+			errValidate:       nil,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Auth token not found."),
 		},
 		{
-			name:          "InvalidAuth",
-			authToken:     "nonempty",
-			errDecodeAuth: cookie.ErrInvalid,
-			errValidate:   nil,
-			errInsertTask: nil,
-			wantStatus:    http.StatusUnauthorized,
-			assertFunc:    assert.OnRespErr("Invalid auth token."),
+			name:              "InvalidAuth",
+			authToken:         "nonempty",
+			errDecodeAuth:     cookie.ErrInvalid,
+			errValidate:       nil,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Invalid auth token."),
 		},
 		{
-			name:          "NotAdmin",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{},
-			errDecodeAuth: nil,
-			errValidate:   nil,
-			errInsertTask: nil,
-			wantStatus:    http.StatusForbidden,
+			name:              "Viewer",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Viewer},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
-				"Only team admins can create tasks.",
+				"You do not have permission to create tasks.",
 			),
 		},
 		{
-			name:          "ErrBoardIDEmpty",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errBoardIDEmpty,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc:    assert.OnRespErr("Board ID cannot be empty."),
+			name:              "ErrBoardIDEmpty",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errBoardIDEmpty,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
+			assertFunc:        assert.OnRespErr("Board ID cannot be empty."),
 		},
 		{
-			name:          "ErrParseBoardID",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errParseBoardID,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:              "ErrParseBoardID",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errParseBoardID,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Board ID is must be a valid UUID.",
 			),
 		},
 		{
-			name:          "ErrColNoOutOfBounds",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errColNoOutOfBounds,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:              "ErrColIDEmpty",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errColIDEmpty,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
-				"Column number must be between 0 and 3.",
+				"Column ID cannot be empty.",
 			),
 		},
 		{
-			name:          "ErrTitleEmpty",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errTitleEmpty,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc:    assert.OnRespErr("Task title cannot be empty."),
+			name:              "ErrTitleEmpty",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errTitleEmpty,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
+			assertFunc:        assert.OnRespErr("Task title cannot be empty."),
 		},
 		{
-			name:          "ErrTitleTooLong",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errTitleTooLong,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:              "ErrTitleTooLong",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errTitleTooLong,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Task title cannot be longer than 50 characters.",
 			),
 		},
 		{
-			name:          "ErrDescTooLong",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errDescTooLong,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			name:              "ErrDescTooLong",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errDescTooLong,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Task description cannot be longer than 500 characters.",
 			),
 		},
 		{
-			name:          "ErrSubtaskTitleEmpty",
+			name:              "ErrSubtaskTitleEmpty",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errSubtaskTitleEmpty,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Subtask title cannot be empty.",
+			),
+		},
+		{
+			name:              "ErrSubtaskTitleTooLong",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errSubtaskTitleTooLong,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Subtask title cannot be longer than 50 characters.",
+			),
+		},
+		{
+			name:              "ErrOrderNegative",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errOrderNegative,
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
+			assertFunc:        assert.OnRespErr("Order cannot be negative."),
+		},
+		{
+			name:              "ErrValidate",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       errors.New("validate failed"),
+			team:              teamtbl.Team{},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("validate failed"),
+		},
+		{
+			name:      "ErrRetrieveTeam",
+			authToken: "nonempty",
+			authDecoded: cookie.Auth{
+				Username: "bob123", Role: role.Member,
+			},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              teamtbl.Team{},
+			errRetrieve:       errors.New("retrieve failed"),
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:      "TeamNotFound",
+			authToken: "nonempty",
+			authDecoded: cookie.Auth{
+				Username: "bob123", Role: role.Member,
+			},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              teamtbl.Team{},
+			errRetrieve:       db.ErrNoItem,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "BoardNotInTeam",
 			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
+			authDecoded:   cookie.Auth{Role: role.Admin},
 			errDecodeAuth: nil,
-			errValidate:   errSubtaskTitleEmpty,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc:    assert.OnRespErr("Subtask title cannot be empty."),
+			errValidate:   nil,
+			team: teamtbl.Team{
+				Boards: []teamtbl.Board{{ID: "otherboardid"}},
+			},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Board not found."),
 		},
 		{
-			name:          "ErrSubtaskTitleTooLong",
+			name:          "ArchivedBoard",
 			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
+			authDecoded:   cookie.Auth{Role: role.Admin},
 			errDecodeAuth: nil,
-			errValidate:   errSubtaskTitleTooLong,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
+			errValidate:   nil,
+			team: teamtbl.Team{
+				Boards: []teamtbl.Board{{ID: "boardid", Archived: true}},
+			},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
-				"Subtask title cannot be longer than 50 characters.",
+				"Tasks of archived boards cannot be changed.",
 			),
 		},
 		{
-			name:          "ErrOrderNegative",
+			name:          "ColumnNotFound",
 			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
+			authDecoded:   cookie.Auth{Role: role.Admin},
 			errDecodeAuth: nil,
-			errValidate:   errOrderNegative,
-			errInsertTask: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc:    assert.OnRespErr("Order cannot be negative."),
+			errValidate:   nil,
+			team: teamtbl.Team{Boards: []teamtbl.Board{{
+				ID:      "boardid",
+				Columns: []teamtbl.Column{{ID: "inbox"}, {ID: "done"}},
+			}}},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Column not found."),
 		},
 		{
-			name:          "ErrValidate",
-			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
-			errDecodeAuth: nil,
-			errValidate:   errors.New("validate failed"),
-			errInsertTask: nil,
-			wantStatus:    http.StatusInternalServerError,
-			assertFunc:    assert.OnLoggedErr("validate failed"),
+			name:      "NotBoardMember",
+			authToken: "nonempty",
+			authDecoded: cookie.Auth{
+				Username: "alice", Role: role.Member,
+			},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              team,
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"You can only create tasks on boards you are a member of.",
+			),
 		},
 		{
-			name:          "ErrPutTask",
+			name:          "SubtaskLimitReached",
 			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
+			authDecoded:   cookie.Auth{Role: role.Admin},
 			errDecodeAuth: nil,
 			errValidate:   nil,
-			errInsertTask: errors.New("put task failed"),
-			wantStatus:    http.StatusInternalServerError,
-			assertFunc:    assert.OnLoggedErr("put task failed"),
+			team: teamtbl.Team{
+				Boards: team.Boards,
+				Quota:  quota.Limits{SubtasksPerTask: 1},
+			},
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Tasks cannot have more than 1 subtasks.",
+			),
+		},
+		{
+			name:              "ErrRetrieveTasks",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              team,
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  errors.New("retrieve tasks failed"),
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve tasks failed"),
 		},
 		{
-			name:          "OK",
+			name:              "TaskLimitReached",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              team,
+			errRetrieve:       nil,
+			tasks:             make([]tasktbl.Task, 2),
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"This board has reached its limit of 2 tasks.",
+			),
+		},
+		{
+			name:          "TaskLimitOverridden",
 			authToken:     "nonempty",
-			authDecoded:   cookie.Auth{IsAdmin: true},
+			authDecoded:   cookie.Auth{Role: role.Admin},
 			errDecodeAuth: nil,
 			errValidate:   nil,
-			errInsertTask: nil,
-			wantStatus:    http.StatusOK,
-			assertFunc:    func(*testing.T, *http.Response, []any) {},
+			team: teamtbl.Team{
+				Boards: team.Boards,
+				Quota:  quota.Limits{TasksPerBoard: quota.Unlimited},
+			},
+			errRetrieve:       nil,
+			tasks:             make([]tasktbl.Task, 2),
+			errRetrieveTasks:  errors.New("retrieve tasks failed"),
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusOK,
+			assertFunc:        func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:              "WIPLimitReached",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              wipTeam,
+			errRetrieve:       nil,
+			tasks:             []tasktbl.Task{{ID: "taskid", ColID: "go"}},
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Column \"Go!\" cannot have more than 1 tasks without " +
+					"overriding its WIP limit.",
+			),
+		},
+		{
+			name:              "ErrInsertOverride",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              wipTeam,
+			errRetrieve:       nil,
+			tasks:             []tasktbl.Task{{ID: "taskid", ColID: "go"}},
+			errRetrieveTasks:  nil,
+			override:          true,
+			errInsertOverride: errors.New("insert override failed"),
+			errInsertTask:     nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("insert override failed"),
+		},
+		{
+			name:              "WIPLimitOverridden",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              wipTeam,
+			errRetrieve:       nil,
+			tasks:             []tasktbl.Task{{ID: "taskid", ColID: "go"}},
+			errRetrieveTasks:  nil,
+			override:          true,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusOK,
+			assertFunc:        func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:              "ErrPutTask",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              team,
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     errors.New("put task failed"),
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("put task failed"),
+		},
+		{
+			name:              "OK",
+			authToken:         "nonempty",
+			authDecoded:       cookie.Auth{Role: role.Admin},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              team,
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusOK,
+			assertFunc:        func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:      "OKMember",
+			authToken: "nonempty",
+			authDecoded: cookie.Auth{
+				Username: "bob123", Role: role.Member,
+			},
+			errDecodeAuth:     nil,
+			errValidate:       nil,
+			team:              team,
+			errRetrieve:       nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errInsertTask:     nil,
+			wantStatus:        http.StatusOK,
+			assertFunc:        func(*testing.T, *http.Response, []any) {},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			authDecoder.Res = c.authDecoded
 			authDecoder.Err = c.errDecodeAuth
 			validate.Err = c.errValidate
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			taskRetriever.Res = c.tasks
+			taskRetriever.Err = c.errRetrieveTasks
+			overrideInserter.Err = c.errInsertOverride
 			taskInserter.Err = c.errInsertTask
+			url := "/"
+			if c.override {
+				url += "?overrideWIP=true"
+			}
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(
-				http.MethodPost, "/", strings.NewReader("{}"),
+				http.MethodPost,
+				url,
+				strings.NewReader(`{
+					"boardID":  "boardid",
+					"colID":    "go",
+					"subtasks": [{"title": "a"}, {"title": "b"}]
+				}`),
 			)
 			if c.authToken != "" {
 				r.AddCookie(&http.Cookie{
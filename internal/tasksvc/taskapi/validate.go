@@ -25,9 +25,6 @@ func (v TitleValidator) Validate(title string) error {
 	return nil
 }
 
-// ColNoValidator can be used to validate a task's column number.
-type ColNoValidator struct{}
-
 // ValidatePostReq validates a given PostReq.
 func ValidatePostReq(req PostReq) error {
 	if req.BoardID == "" {
@@ -36,8 +33,8 @@ func ValidatePostReq(req PostReq) error {
 	if _, err := uuid.Parse(req.BoardID); err != nil {
 		return errParseBoardID
 	}
-	if req.ColNo < 0 || req.ColNo > 3 {
-		return errColNoOutOfBounds
+	if req.ColID == "" {
+		return errColIDEmpty
 	}
 	if req.Title == "" {
 		return errTitleEmpty
@@ -69,8 +66,8 @@ var (
 	// errParseBoardID is returned when a board ID cannot be parsed.
 	errParseBoardID = errors.New("could not parse board id")
 
-	// errColNoOutOfBounds is returned when a column number is out of bounds.
-	errColNoOutOfBounds = errors.New("column number is out of bounds")
+	// errColIDEmpty is returned when a column ID is empty.
+	errColIDEmpty = errors.New("column id is empty")
 
 	// errTitleEmpty is returned when a task title is empty.
 	errTitleEmpty = errors.New("title is empty")
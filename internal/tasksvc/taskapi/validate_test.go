@@ -31,26 +31,18 @@ func TestValidatePostReq(t *testing.T) {
 			wantErr: errParseBoardID,
 		},
 		{
-			name: "ColNoTooSmall",
+			name: "ColIDEmpty",
 			req: PostReq{
 				BoardID: "00000000-0000-0000-0000-000000000000",
-				ColNo:   -1,
+				ColID:   "",
 			},
-			wantErr: errColNoOutOfBounds,
-		},
-		{
-			name: "ColNoTooBig",
-			req: PostReq{
-				BoardID: "00000000-0000-0000-0000-000000000000",
-				ColNo:   4,
-			},
-			wantErr: errColNoOutOfBounds,
+			wantErr: errColIDEmpty,
 		},
 		{
 			name: "TitleEmpty",
 			req: PostReq{
 				BoardID: "00000000-0000-0000-0000-000000000000",
-				ColNo:   2,
+				ColID:   "go",
 				Title:   "",
 			},
 			wantErr: errTitleEmpty,
@@ -59,7 +51,7 @@ func TestValidatePostReq(t *testing.T) {
 			name: "TitleTooLong",
 			req: PostReq{
 				BoardID: "00000000-0000-0000-0000-000000000000",
-				ColNo:   2,
+				ColID:   "go",
 				Title:   "asdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasd",
 			},
 			wantErr: errTitleTooLong,
@@ -68,7 +60,7 @@ func TestValidatePostReq(t *testing.T) {
 			name: "DescriptionTooLong",
 			req: PostReq{
 				BoardID: "00000000-0000-0000-0000-000000000000",
-				ColNo:   2,
+				ColID:   "go",
 				Title:   "Some Task",
 				Description: "asdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqw" +
 					"easdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasda" +
@@ -87,7 +79,7 @@ func TestValidatePostReq(t *testing.T) {
 			name: "SubtaskTitleEmpty",
 			req: PostReq{
 				BoardID:     "00000000-0000-0000-0000-000000000000",
-				ColNo:       2,
+				ColID:       "go",
 				Title:       "Some Task",
 				Description: "Some Description",
 				Subtasks:    []tasktbl.Subtask{{Title: ""}},
@@ -98,7 +90,7 @@ func TestValidatePostReq(t *testing.T) {
 			name: "SubtaskTitleTooLong",
 			req: PostReq{
 				BoardID:     "00000000-0000-0000-0000-000000000000",
-				ColNo:       2,
+				ColID:       "go",
 				Title:       "Some Task",
 				Description: "Some Description",
 				Subtasks: []tasktbl.Subtask{
@@ -114,7 +106,7 @@ func TestValidatePostReq(t *testing.T) {
 			name: "OrderNegative",
 			req: PostReq{
 				BoardID:     "00000000-0000-0000-0000-000000000000",
-				ColNo:       2,
+				ColID:       "go",
 				Title:       "Some Task",
 				Description: "Some Description",
 				Subtasks:    []tasktbl.Subtask{{Title: "Some Subtask"}},
@@ -126,7 +118,7 @@ func TestValidatePostReq(t *testing.T) {
 			name: "OK",
 			req: PostReq{
 				BoardID:     "00000000-0000-0000-0000-000000000000",
-				ColNo:       2,
+				ColID:       "go",
 				Title:       "Some Task",
 				Description: "Some Description",
 				Subtasks:    []tasktbl.Subtask{{Title: "Some Subtask"}},
@@ -9,7 +9,9 @@ import (
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -23,6 +25,7 @@ type GetHandler struct {
 	retrieverByBoard db.Retriever[[]tasktbl.Task]
 	authDecoder      cookie.Decoder[cookie.Auth]
 	retrieverByTeam  db.Retriever[[]tasktbl.Task]
+	teamRetriever    db.Retriever[teamtbl.Team]
 	log              log.Errorer
 }
 
@@ -32,6 +35,7 @@ func NewGetHandler(
 	retrieverByBoard db.Retriever[[]tasktbl.Task],
 	authDecoder cookie.Decoder[cookie.Auth],
 	retrieverByTeam db.Retriever[[]tasktbl.Task],
+	teamRetriever db.Retriever[teamtbl.Team],
 	log log.Errorer,
 ) GetHandler {
 	return GetHandler{
@@ -39,6 +43,7 @@ func NewGetHandler(
 		retrieverByBoard: retrieverByBoard,
 		authDecoder:      authDecoder,
 		retrieverByTeam:  retrieverByTeam,
+		teamRetriever:    teamRetriever,
 		log:              log,
 	}
 }
@@ -46,7 +51,7 @@ func NewGetHandler(
 // Handle handles GET requests sent to the tasks route.
 func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
@@ -63,6 +68,12 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		return
 	}
 
+	// validate user can view tasks
+	if !role.Can(auth.Role, role.ViewTasks) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	// get tasks by board ID if present, otherwise get tasks by team ID of the
 	// auth cookie
 	var (
@@ -118,6 +129,7 @@ func (h GetHandler) getByBoardID(
 
 // getByTeamID gets the team ID from the auth token, retrieves all tasks for
 // the team, and writes the ones with the first task's board ID to the response.
+// Tasks of archived boards are skipped.
 func (h GetHandler) getByTeamID(
 	ctx context.Context, auth cookie.Auth, w http.ResponseWriter,
 ) ([]tasktbl.Task, int) {
@@ -131,12 +143,22 @@ func (h GetHandler) getByTeamID(
 		return nil, http.StatusInternalServerError
 	}
 
-	// if more than one task, only return the ones with the first task's board
-	// ID
-	if len(tasks) > 1 {
+	// retrieve the team to find out which of its boards are archived
+	team, err := h.teamRetriever.Retrieve(ctx, auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		h.log.Error(err)
+		return nil, http.StatusInternalServerError
+	}
+
+	// only return the tasks with the first task's board ID, skipping the
+	// tasks of archived boards
+	if len(tasks) > 0 {
 		singleBoardTasks := []tasktbl.Task{}
 		var boardID string
 		for _, t := range tasks {
+			if team.IsBoardArchived(t.BoardID) {
+				continue
+			}
 			switch boardID {
 			case "":
 				boardID = t.BoardID
@@ -13,7 +13,9 @@ import (
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -22,12 +24,14 @@ func TestGetHandler(t *testing.T) {
 	retrieverByBoard := &db.FakeRetriever[[]tasktbl.Task]{}
 	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
 	retrieverByTeam := &db.FakeRetriever[[]tasktbl.Task]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
 	log := &log.FakeErrorer{}
 	sut := NewGetHandler(
 		boardIDValidator,
 		retrieverByBoard,
 		authDecoder,
 		retrieverByTeam,
+		teamRetriever,
 		log,
 	)
 
@@ -35,7 +39,7 @@ func TestGetHandler(t *testing.T) {
 		{
 			TeamID:      "team1",
 			BoardID:     "board1",
-			ColNo:       0,
+			ColID:       "inbox",
 			ID:          "task1",
 			Title:       "taskone",
 			Description: "task one description",
@@ -48,7 +52,7 @@ func TestGetHandler(t *testing.T) {
 		{
 			TeamID:      "team1",
 			BoardID:     "board1",
-			ColNo:       2,
+			ColID:       "go",
 			ID:          "task2",
 			Title:       "tasktwo",
 			Description: "task two description",
@@ -61,7 +65,7 @@ func TestGetHandler(t *testing.T) {
 		{
 			TeamID:      "team1",
 			BoardID:     "board2",
-			ColNo:       0,
+			ColID:       "inbox",
 			ID:          "task3",
 			Title:       "taskthree",
 			Description: "task three description",
@@ -107,12 +111,23 @@ func TestGetHandler(t *testing.T) {
 				wantStatus:         http.StatusUnauthorized,
 				assertFunc:         func(*testing.T, *http.Response, []any) {},
 			},
+			{
+				name:               "CannotView",
+				errValidateBoardID: nil,
+				authToken:          "nonempty",
+				errDecodeAuth:      nil,
+				auth:               cookie.Auth{Role: ""},
+				errRetrieve:        nil,
+				tasks:              []tasktbl.Task{},
+				wantStatus:         http.StatusForbidden,
+				assertFunc:         func(*testing.T, *http.Response, []any) {},
+			},
 			{
 				name:               "InvalidBoardID",
 				errValidateBoardID: errors.New("validate board ID failed"),
 				authToken:          "nonempty",
 				errDecodeAuth:      nil,
-				auth:               cookie.Auth{},
+				auth:               cookie.Auth{Role: role.Viewer},
 				errRetrieve:        nil,
 				tasks:              []tasktbl.Task{},
 				wantStatus:         http.StatusBadRequest,
@@ -123,7 +138,7 @@ func TestGetHandler(t *testing.T) {
 				errValidateBoardID: nil,
 				authToken:          "nonempty",
 				errDecodeAuth:      nil,
-				auth:               cookie.Auth{},
+				auth:               cookie.Auth{Role: role.Viewer},
 				errRetrieve:        errors.New("retrieve failed"),
 				tasks:              []tasktbl.Task{},
 				wantStatus:         http.StatusInternalServerError,
@@ -134,18 +149,20 @@ func TestGetHandler(t *testing.T) {
 				errValidateBoardID: nil,
 				authToken:          "nonempty",
 				errDecodeAuth:      nil,
-				auth:               cookie.Auth{TeamID: "team2"},
-				errRetrieve:        nil,
-				tasks:              tasksA,
-				wantStatus:         http.StatusForbidden,
-				assertFunc:         func(*testing.T, *http.Response, []any) {},
+				auth: cookie.Auth{
+					Role: role.Viewer, TeamID: "team2",
+				},
+				errRetrieve: nil,
+				tasks:       tasksA,
+				wantStatus:  http.StatusForbidden,
+				assertFunc:  func(*testing.T, *http.Response, []any) {},
 			},
 			{
 				name:               "OKNone",
 				errValidateBoardID: nil,
 				authToken:          "nonempty",
 				errDecodeAuth:      nil,
-				auth:               cookie.Auth{},
+				auth:               cookie.Auth{Role: role.Viewer},
 				errRetrieve:        nil,
 				tasks:              []tasktbl.Task{},
 				wantStatus:         http.StatusOK,
@@ -162,10 +179,12 @@ func TestGetHandler(t *testing.T) {
 				errValidateBoardID: nil,
 				authToken:          "nonempty",
 				errDecodeAuth:      nil,
-				auth:               cookie.Auth{TeamID: "team1"},
-				errRetrieve:        nil,
-				tasks:              tasksA,
-				wantStatus:         http.StatusOK,
+				auth: cookie.Auth{
+					Role: role.Viewer, TeamID: "team1",
+				},
+				errRetrieve: nil,
+				tasks:       tasksA,
+				wantStatus:  http.StatusOK,
 				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
 					var tasks []tasktbl.Task
 					err := json.NewDecoder(resp.Body).Decode(&tasks)
@@ -180,7 +199,7 @@ func TestGetHandler(t *testing.T) {
 							gotTask.BoardID, tasksA[i].BoardID,
 						)
 						assert.Equal(t.Error,
-							gotTask.ColNo, tasksA[i].ColNo,
+							gotTask.ColID, tasksA[i].ColID,
 						)
 						assert.Equal(t.Error, gotTask.ID, tasksA[i].ID)
 						assert.Equal(t.Error, gotTask.Title, tasksA[i].Title)
@@ -232,54 +251,90 @@ func TestGetHandler(t *testing.T) {
 	})
 
 	t.Run("WithoutBoardID", func(t *testing.T) {
+		viewer := cookie.Auth{Role: role.Viewer, TeamID: "team1"}
+
 		for _, c := range []struct {
-			name          string
-			authToken     string
-			errDecodeAuth error
-			auth          cookie.Auth
-			errRetrieve   error
-			tasks         []tasktbl.Task
-			wantStatus    int
-			assertFunc    func(*testing.T, *http.Response, []any)
+			name            string
+			authToken       string
+			errDecodeAuth   error
+			auth            cookie.Auth
+			errRetrieve     error
+			tasks           []tasktbl.Task
+			team            teamtbl.Team
+			errRetrieveTeam error
+			wantStatus      int
+			assertFunc      func(*testing.T, *http.Response, []any)
 		}{
 			{
-				name:          "NoAuth",
-				authToken:     "",
-				errDecodeAuth: nil,
-				auth:          cookie.Auth{},
-				errRetrieve:   nil,
-				tasks:         []tasktbl.Task{},
-				wantStatus:    http.StatusUnauthorized,
-				assertFunc:    func(*testing.T, *http.Response, []any) {},
+				name:            "NoAuth",
+				authToken:       "",
+				errDecodeAuth:   nil,
+				auth:            cookie.Auth{},
+				errRetrieve:     nil,
+				tasks:           []tasktbl.Task{},
+				team:            teamtbl.Team{},
+				errRetrieveTeam: nil,
+				wantStatus:      http.StatusUnauthorized,
+				assertFunc:      func(*testing.T, *http.Response, []any) {},
 			},
 			{
-				name:          "InvalidAuth",
-				authToken:     "nonempty",
-				errDecodeAuth: errors.New("decode auth failed"),
-				auth:          cookie.Auth{},
-				errRetrieve:   nil,
-				tasks:         []tasktbl.Task{},
-				wantStatus:    http.StatusUnauthorized,
-				assertFunc:    func(*testing.T, *http.Response, []any) {},
+				name:            "InvalidAuth",
+				authToken:       "nonempty",
+				errDecodeAuth:   errors.New("decode auth failed"),
+				auth:            cookie.Auth{},
+				errRetrieve:     nil,
+				tasks:           []tasktbl.Task{},
+				team:            teamtbl.Team{},
+				errRetrieveTeam: nil,
+				wantStatus:      http.StatusUnauthorized,
+				assertFunc:      func(*testing.T, *http.Response, []any) {},
 			},
 			{
-				name:          "ErrRetrieve",
-				authToken:     "nonempty",
-				errDecodeAuth: nil,
-				auth:          cookie.Auth{TeamID: "team1"},
-				errRetrieve:   errors.New("retrieve failed"),
-				tasks:         []tasktbl.Task{},
-				wantStatus:    http.StatusInternalServerError,
-				assertFunc:    func(*testing.T, *http.Response, []any) {},
+				name:            "CannotView",
+				authToken:       "nonempty",
+				errDecodeAuth:   nil,
+				auth:            cookie.Auth{Role: ""},
+				errRetrieve:     nil,
+				tasks:           []tasktbl.Task{},
+				team:            teamtbl.Team{},
+				errRetrieveTeam: nil,
+				wantStatus:      http.StatusForbidden,
+				assertFunc:      func(*testing.T, *http.Response, []any) {},
 			},
 			{
-				name:          "OKNone",
-				authToken:     "nonempty",
-				errDecodeAuth: nil,
-				auth:          cookie.Auth{TeamID: "team1"},
-				errRetrieve:   nil,
-				tasks:         []tasktbl.Task{},
-				wantStatus:    http.StatusOK,
+				name:            "ErrRetrieve",
+				authToken:       "nonempty",
+				errDecodeAuth:   nil,
+				auth:            viewer,
+				errRetrieve:     errors.New("retrieve failed"),
+				tasks:           []tasktbl.Task{},
+				team:            teamtbl.Team{},
+				errRetrieveTeam: nil,
+				wantStatus:      http.StatusInternalServerError,
+				assertFunc:      func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:            "ErrRetrieveTeam",
+				authToken:       "nonempty",
+				errDecodeAuth:   nil,
+				auth:            viewer,
+				errRetrieve:     nil,
+				tasks:           tasksA,
+				team:            teamtbl.Team{},
+				errRetrieveTeam: errors.New("retrieve team failed"),
+				wantStatus:      http.StatusInternalServerError,
+				assertFunc:      func(*testing.T, *http.Response, []any) {},
+			},
+			{
+				name:            "OKNone",
+				authToken:       "nonempty",
+				errDecodeAuth:   nil,
+				auth:            viewer,
+				errRetrieve:     nil,
+				tasks:           []tasktbl.Task{},
+				team:            teamtbl.Team{},
+				errRetrieveTeam: nil,
+				wantStatus:      http.StatusOK,
 				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
 					var tasks []tasktbl.Task
 					err := json.NewDecoder(resp.Body).Decode(&tasks)
@@ -288,13 +343,15 @@ func TestGetHandler(t *testing.T) {
 				},
 			},
 			{
-				name:          "OKSome",
-				authToken:     "nonempty",
-				errDecodeAuth: nil,
-				auth:          cookie.Auth{TeamID: "team1"},
-				errRetrieve:   nil,
-				tasks:         tasksA,
-				wantStatus:    http.StatusOK,
+				name:            "OKSome",
+				authToken:       "nonempty",
+				errDecodeAuth:   nil,
+				auth:            viewer,
+				errRetrieve:     nil,
+				tasks:           tasksA,
+				team:            teamtbl.Team{},
+				errRetrieveTeam: nil,
+				wantStatus:      http.StatusOK,
 				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
 					var tasks []tasktbl.Task
 					err := json.NewDecoder(resp.Body).Decode(&tasks)
@@ -313,7 +370,7 @@ func TestGetHandler(t *testing.T) {
 							gotTask.BoardID, wantTasks[i].BoardID,
 						)
 						assert.Equal(t.Error,
-							gotTask.ColNo, wantTasks[i].ColNo,
+							gotTask.ColID, wantTasks[i].ColID,
 						)
 						assert.Equal(t.Error, gotTask.ID, wantTasks[i].ID)
 						assert.Equal(t.Error, gotTask.Title, wantTasks[i].Title)
@@ -338,12 +395,37 @@ func TestGetHandler(t *testing.T) {
 					}
 				},
 			},
+			{
+				name:          "OKArchivedSkipped",
+				authToken:     "nonempty",
+				errDecodeAuth: nil,
+				auth:          viewer,
+				errRetrieve:   nil,
+				tasks:         tasksA,
+				team: teamtbl.Team{Boards: []teamtbl.Board{
+					{ID: "board1", Archived: true}, {ID: "board2"},
+				}},
+				errRetrieveTeam: nil,
+				wantStatus:      http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var tasks []tasktbl.Task
+					err := json.NewDecoder(resp.Body).Decode(&tasks)
+					assert.Nil(t.Fatal, err)
+
+					// board1 is archived, so only the task on board2 should
+					// be returned
+					assert.Equal(t.Fatal, len(tasks), 1)
+					assert.Equal(t.Error, tasks[0].ID, tasksA[2].ID)
+				},
+			},
 		} {
 			t.Run(c.name, func(t *testing.T) {
 				authDecoder.Res = c.auth
 				authDecoder.Err = c.errDecodeAuth
 				retrieverByTeam.Err = c.errRetrieve
 				retrieverByTeam.Res = c.tasks
+				teamRetriever.Res = c.team
+				teamRetriever.Err = c.errRetrieveTeam
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(http.MethodGet, "/", nil)
 				if c.authToken != "" {
@@ -4,12 +4,18 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
-	"github.com/kxplxn/goteam/pkg/validator"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/wip"
 )
 
 // PatchReq defines body of PATCH tasks requests.
@@ -29,24 +35,33 @@ type PatchResp struct {
 // PatchHandler is an api.MethodHandler that can be used to handle PATCH
 // requests sent to the tasks route.
 type PatchHandler struct {
-	authDecoder    cookie.Decoder[cookie.Auth]
-	colNoValidator validator.Int
-	tasksUpdater   db.Updater[[]tasktbl.Task]
-	log            log.Errorer
+	authDecoder         cookie.Decoder[cookie.Auth]
+	taskRetriever       db.RetrieverDualKey[tasktbl.Task]
+	teamRetriever       db.Retriever[teamtbl.Team]
+	boardTasksRetriever db.Retriever[[]tasktbl.Task]
+	overrideInserter    db.Inserter[overridetbl.Override]
+	tasksUpdater        db.Updater[[]tasktbl.Task]
+	log                 log.Errorer
 }
 
 // NewPatchHandler creates and returns a new PATCHHandler.
 func NewPatchHandler(
 	authDecoder cookie.Decoder[cookie.Auth],
-	colNoValidator validator.Int,
+	taskRetriever db.RetrieverDualKey[tasktbl.Task],
+	teamRetriever db.Retriever[teamtbl.Team],
+	boardTasksRetriever db.Retriever[[]tasktbl.Task],
+	overrideInserter db.Inserter[overridetbl.Override],
 	tasksUpdater db.Updater[[]tasktbl.Task],
 	log log.Errorer,
 ) PatchHandler {
 	return PatchHandler{
-		authDecoder:    authDecoder,
-		colNoValidator: colNoValidator,
-		tasksUpdater:   tasksUpdater,
-		log:            log,
+		authDecoder:         authDecoder,
+		taskRetriever:       taskRetriever,
+		teamRetriever:       teamRetriever,
+		boardTasksRetriever: boardTasksRetriever,
+		overrideInserter:    overrideInserter,
+		tasksUpdater:        tasksUpdater,
+		log:                 log,
 	}
 }
 
@@ -55,7 +70,7 @@ func (h PatchHandler) Handle(
 	w http.ResponseWriter, r *http.Request, username string,
 ) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		if err = json.NewEncoder(w).Encode(PatchResp{
@@ -80,20 +95,22 @@ func (h PatchHandler) Handle(
 		}); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			h.log.Error(err)
-			return
 		}
+		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
+	// validate user can move tasks - whether they can do so on the boards of
+	// the tasks is checked after the request is validated if their role only
+	// allows it on the boards that they are a member of
+	if role.Check(auth.Role, role.MoveTask) == role.Denied {
 		w.WriteHeader(http.StatusForbidden)
 		if err = json.NewEncoder(w).Encode(PatchResp{
-			Error: "Only team admins can edit tasks.",
+			Error: "You do not have permission to move tasks.",
 		}); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			h.log.Error(err)
-			return
 		}
+		return
 	}
 
 	// decode request body
@@ -115,14 +132,30 @@ func (h PatchHandler) Handle(
 		return
 	}
 
-	// map request body into tasks, validating them as we go
+	// retrieve the tasks as they are stored so that only their columns and
+	// orders are changed, and so that the user's access is checked against the
+	// boards that they are actually on
 	var tasks []tasktbl.Task
 	for _, t := range req {
-		// TODO: validate other fields, too
-		if err := h.colNoValidator.Validate(t.ColNo); err != nil {
+		task, err := h.taskRetriever.Retrieve(r.Context(), auth.TeamID, t.ID)
+		if errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(http.StatusNotFound)
+			if err = json.NewEncoder(w).Encode(PatchResp{
+				Error: "Task not found.",
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
+		} else if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		if t.BoardID != "" && t.BoardID != task.BoardID {
 			w.WriteHeader(http.StatusBadRequest)
 			if err = json.NewEncoder(w).Encode(PatchResp{
-				Error: "Invalid column number.",
+				Error: "Tasks cannot be moved between boards.",
 			}); err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
 				h.log.Error(err)
@@ -130,18 +163,121 @@ func (h PatchHandler) Handle(
 			return
 		}
 
-		task := tasktbl.Task{
-			TeamID:      auth.TeamID,
-			BoardID:     t.BoardID,
-			ColNo:       t.ColNo,
-			ID:          t.ID,
-			Title:       t.Title,
-			Description: t.Description,
-			Order:       t.Order,
-			Subtasks:    t.Subtasks,
+		task.ColID, task.Order = t.ColID, t.Order
+		tasks = append(tasks, task)
+	}
+
+	// validate the tasks' boards belong to the user's active team, that they
+	// aren't archived, that they have the tasks' columns, and that the user is
+	// a member of them if their role requires it
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	for _, t := range tasks {
+		if !team.HasBoard(t.BoardID) {
+			w.WriteHeader(http.StatusNotFound)
+			if err = json.NewEncoder(w).Encode(PatchResp{
+				Error: "Board not found.",
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
 		}
+		if team.IsBoardArchived(t.BoardID) {
+			w.WriteHeader(http.StatusForbidden)
+			if err = json.NewEncoder(w).Encode(PatchResp{
+				Error: "Tasks of archived boards cannot be changed.",
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
+		}
+		if !team.HasColumn(t.BoardID, t.ColID) {
+			w.WriteHeader(http.StatusNotFound)
+			if err = json.NewEncoder(w).Encode(PatchResp{
+				Error: "Column not found.",
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
+		}
+		if !role.CanOnBoard(
+			auth.Role,
+			role.MoveTask,
+			team.IsBoardMember(t.BoardID, auth.Username),
+		) {
+			w.WriteHeader(http.StatusForbidden)
+			if err = json.NewEncoder(w).Encode(PatchResp{
+				Error: "You can only move tasks on boards you are a " +
+					"member of.",
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
+		}
+	}
 
-		tasks = append(tasks, task)
+	// validate the tasks aren't moved into columns beyond their WIP limits
+	// unless the user explicitly overrides them, in which case the overrides
+	// are recorded before the tasks are updated - each board's tasks are only
+	// retrieved if any of the columns the tasks are placed in on it has a limit
+	var violations []wip.Violation
+	checked := map[string]bool{}
+	for _, t := range tasks {
+		if checked[t.BoardID] {
+			continue
+		}
+		checked[t.BoardID] = true
+		if !wip.Limited(team, t.BoardID, tasks) {
+			continue
+		}
+
+		boardTasks, err := h.boardTasksRetriever.Retrieve(
+			r.Context(), t.BoardID,
+		)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		violations = append(
+			violations, wip.Check(team, t.BoardID, boardTasks, tasks)...,
+		)
+	}
+	if len(violations) > 0 && !wip.IsOverride(r) {
+		w.WriteHeader(http.StatusConflict)
+		if err = json.NewEncoder(w).Encode(PatchResp{
+			Error: violations[0].Msg(),
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	for _, v := range violations {
+		if err = h.overrideInserter.Insert(
+			r.Context(), overridetbl.NewOverride(
+				v.BoardID,
+				uuid.NewString(),
+				auth.TeamID,
+				v.Col.ID,
+				auth.Username,
+				v.Col.WIPLimit,
+				v.Count,
+				time.Now().Unix(),
+			),
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
 	}
 
 	// update tasks in the task table
@@ -9,153 +9,564 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 func TestPatchHandler(t *testing.T) {
 	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
-	colNoVdtor := &api.FakeIntValidator{}
+	taskRetriever := &db.FakeRetrieverDualKey[tasktbl.Task]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	boardTasksRetriever := &db.FakeRetriever[[]tasktbl.Task]{}
+	overrideInserter := &db.FakeInserter[overridetbl.Override]{}
 	tasksUpdater := &db.FakeUpdater[[]tasktbl.Task]{}
 	log := &log.FakeErrorer{}
 	sut := NewPatchHandler(
 		authDecoder,
-		colNoVdtor,
+		taskRetriever,
+		teamRetriever,
+		boardTasksRetriever,
+		overrideInserter,
 		tasksUpdater,
 		log,
 	)
 
+	team := teamtbl.Team{Boards: []teamtbl.Board{
+		{ID: "board1", Members: []string{"bob123"}},
+		{ID: "board2", Members: []string{"alice"}},
+	}}
+	wipTeam := teamtbl.Team{Boards: []teamtbl.Board{{
+		ID: "board1",
+		Columns: []teamtbl.Column{
+			teamtbl.NewColumn("inbox", "Inbox", "", 1),
+		},
+	}}}
+	archivedTeam := teamtbl.Team{Boards: []teamtbl.Board{
+		{ID: "board1", Archived: true}, {ID: "board2"},
+	}}
+	task := tasktbl.Task{
+		TeamID: "1", ID: "taskid", BoardID: "board1", ColID: "ready",
+		Title: "Do something!", Order: 1,
+	}
+	onBoard := func(boardID string) tasktbl.Task {
+		t := task
+		t.BoardID = boardID
+		return t
+	}
+	inboxTasks := []tasktbl.Task{{ID: "othertaskid", ColID: "inbox"}}
+	reqBody := `[{"id": "taskid", "boardID": "board1", "order": 3, ` +
+		`"colID": "inbox"}]`
+
 	for _, c := range []struct {
-		name             string
-		rBody            string
-		authToken        string
-		errDecodeAuth    error
-		authDecoded      cookie.Auth
-		errValidateColNo error
-		errUpdateTasks   error
-		errEncodeState   error
-		outState         http.Cookie
-		wantStatus       int
-		assertFunc       func(*testing.T, *http.Response, []any)
+		name              string
+		rBody             string
+		authToken         string
+		errDecodeAuth     error
+		authDecoded       cookie.Auth
+		task              tasktbl.Task
+		errRetrieveTask   error
+		team              teamtbl.Team
+		errRetrieveTeam   error
+		tasks             []tasktbl.Task
+		errRetrieveTasks  error
+		override          bool
+		errInsertOverride error
+		errUpdateTasks    error
+		errEncodeState    error
+		outState          http.Cookie
+		wantStatus        int
+		assertFunc        func(*testing.T, *http.Response, []any)
 	}{
 		{
-			name:             "NoAuth",
-			rBody:            "[]",
-			authToken:        "",
-			errDecodeAuth:    nil,
-			authDecoded:      cookie.Auth{},
-			errValidateColNo: nil,
-			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusUnauthorized,
-			assertFunc:       assert.OnRespErr("Auth token not found."),
-		},
-		{
-			name:             "ErrDecodeAuth",
-			rBody:            "[]",
-			authToken:        "nonempty",
-			errDecodeAuth:    errors.New("decode auth failed"),
-			authDecoded:      cookie.Auth{},
-			errValidateColNo: nil,
-			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusUnauthorized,
-			assertFunc:       assert.OnRespErr("Invalid auth token."),
-		},
-		{
-			name:             "NotAdmin",
-			rBody:            "[]",
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
-			authDecoded:      cookie.Auth{IsAdmin: false},
-			errValidateColNo: nil,
-			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusForbidden,
+			name:              "NoAuth",
+			rBody:             "[]",
+			authToken:         "",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{},
+			task:              tasktbl.Task{},
+			errRetrieveTask:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:              "ErrDecodeAuth",
+			rBody:             "[]",
+			authToken:         "nonempty",
+			errDecodeAuth:     errors.New("decode auth failed"),
+			authDecoded:       cookie.Auth{},
+			task:              tasktbl.Task{},
+			errRetrieveTask:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:              "Viewer",
+			rBody:             "[]",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Viewer},
+			task:              tasktbl.Task{},
+			errRetrieveTask:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
-				"Only team admins can edit tasks.",
+				"You do not have permission to move tasks.",
 			),
 		},
 		{
-			name:             "NoTasks",
-			rBody:            "[]",
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
-			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
-			errValidateColNo: nil,
-			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusBadRequest,
-			assertFunc:       assert.OnRespErr("No tasks provided."),
-		},
-		{
-			name:             "ColNoInvalid",
-			rBody:            "[{}]",
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
-			authDecoded:      cookie.Auth{IsAdmin: true},
-			errValidateColNo: errors.New("err validate column number"),
-			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusBadRequest,
-			assertFunc:       assert.OnRespErr("Invalid column number."),
-		},
-		{
-			name:             "TaskNotFound",
-			rBody:            `[{"id": "taskid", "order": 3, "column": 0}]`,
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
-			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
-			errValidateColNo: nil,
-			errUpdateTasks:   db.ErrNoItem,
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusNotFound,
-			assertFunc:       assert.OnRespErr("Task not found."),
-		},
-		{
-			name:             "ErrUpdateTasks",
-			rBody:            `[{"id": "taskid", "order": 3, "column": 0}]`,
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
-			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
-			errValidateColNo: nil,
-			errUpdateTasks:   errors.New("update tasks failed"),
-			errEncodeState:   nil,
-			outState:         http.Cookie{},
-			wantStatus:       http.StatusInternalServerError,
-			assertFunc:       assert.OnLoggedErr("update tasks failed"),
-		},
-		{
-			name:             "OK",
-			rBody:            `[{"id": "taskid", "order": 3, "column": 0}]`,
-			authToken:        "nonempty",
-			errDecodeAuth:    nil,
-			authDecoded:      cookie.Auth{IsAdmin: true, TeamID: "1"},
-			errValidateColNo: nil,
-			errUpdateTasks:   nil,
-			errEncodeState:   nil,
-			outState:         http.Cookie{Name: "foo", Value: "bar"},
-			wantStatus:       http.StatusOK,
-			assertFunc:       func(*testing.T, *http.Response, []any) {},
+			name:              "NoTasks",
+			rBody:             "[]",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              tasktbl.Task{},
+			errRetrieveTask:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusBadRequest,
+			assertFunc:        assert.OnRespErr("No tasks provided."),
+		},
+		{
+			name:              "ErrRetrieveTask",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              tasktbl.Task{},
+			errRetrieveTask:   errors.New("retrieve task failed"),
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve task failed"),
+		},
+		{
+			name:              "TaskNotFound",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              tasktbl.Task{},
+			errRetrieveTask:   db.ErrNoItem,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Task not found."),
+		},
+		{
+			name:          "BoardChanged",
+			rBody:         reqBody,
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded: cookie.Auth{
+				Username: "bob123", Role: role.Member,
+			},
+			task:              onBoard("board2"),
+			errRetrieveTask:   nil,
+			team:              team,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Tasks cannot be moved between boards.",
+			),
+		},
+		{
+			name: "ErrRetrieveTeam",
+			rBody: `[{"id": "taskid", "boardID": "board1", "order": 3, ` +
+				`"colID": "inbox"}]`,
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded: cookie.Auth{
+				Username: "bob123", Role: role.Member,
+			},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   errors.New("retrieve team failed"),
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:          "NotBoardMember",
+			rBody:         `[{"id": "taskid", "order": 3, "colID": "inbox"}]`,
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded: cookie.Auth{
+				Username: "bob123", Role: role.Member,
+			},
+			task:              onBoard("board2"),
+			errRetrieveTask:   nil,
+			team:              team,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"You can only move tasks on boards you are a member of.",
+			),
+		},
+		{
+			name:              "BoardNotInTeam",
+			rBody:             `[{"id": "taskid", "boardID": "board3"}]`,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              onBoard("board3"),
+			errRetrieveTask:   nil,
+			team:              team,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Board not found."),
+		},
+		{
+			name:              "ArchivedBoard",
+			rBody:             `[{"id": "taskid", "boardID": "board1"}]`,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              archivedTeam,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Tasks of archived boards cannot be changed.",
+			),
+		},
+		{
+			name:              "ArchivedBoardOmitted",
+			rBody:             `[{"id": "taskid", "colID": "inbox"}]`,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              archivedTeam,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Tasks of archived boards cannot be changed.",
+			),
+		},
+		{
+			name:              "MovedOutOfArchivedBoard",
+			rBody:             `[{"id": "taskid", "boardID": "board2"}]`,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              archivedTeam,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Tasks cannot be moved between boards.",
+			),
+		},
+		{
+			name: "ColumnNotFound",
+			rBody: `[{"id": "taskid", "boardID": "board1", "order": 3, ` +
+				`"colID": "backlog"}]`,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              team,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Column not found."),
+		},
+		{
+			name:              "ErrRetrieveTasks",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              wipTeam,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  errors.New("retrieve tasks failed"),
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:              "WIPLimitReached",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              wipTeam,
+			errRetrieveTeam:   nil,
+			tasks:             inboxTasks,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Column \"Inbox\" cannot have more than 1 tasks without " +
+					"overriding its WIP limit.",
+			),
+		},
+		{
+			name:              "ErrInsertOverride",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              wipTeam,
+			errRetrieveTeam:   nil,
+			tasks:             inboxTasks,
+			errRetrieveTasks:  nil,
+			override:          true,
+			errInsertOverride: errors.New("insert override failed"),
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("insert override failed"),
+		},
+		{
+			name:              "WIPLimitOverridden",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              wipTeam,
+			errRetrieveTeam:   nil,
+			tasks:             inboxTasks,
+			errRetrieveTasks:  nil,
+			override:          true,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusOK,
+			assertFunc:        func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:              "TaskDeleted",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              team,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    db.ErrNoItem,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Task not found."),
+		},
+		{
+			name:              "ErrUpdateTasks",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              team,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    errors.New("update tasks failed"),
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("update tasks failed"),
+		},
+		{
+			name:              "OK",
+			rBody:             reqBody,
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       cookie.Auth{Role: role.Admin, TeamID: "1"},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              team,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{Name: "foo", Value: "bar"},
+			wantStatus:        http.StatusOK,
+			assertFunc:        func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name: "OKMember",
+			rBody: `[{"id": "taskid", "boardID": "board1", "order": 3, ` +
+				`"colID": "inbox"}]`,
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded: cookie.Auth{
+				Username: "bob123", Role: role.Member,
+			},
+			task:              task,
+			errRetrieveTask:   nil,
+			team:              team,
+			errRetrieveTeam:   nil,
+			tasks:             nil,
+			errRetrieveTasks:  nil,
+			override:          false,
+			errInsertOverride: nil,
+			errUpdateTasks:    nil,
+			errEncodeState:    nil,
+			outState:          http.Cookie{},
+			wantStatus:        http.StatusOK,
+			assertFunc:        func(*testing.T, *http.Response, []any) {},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			authDecoder.Res = c.authDecoded
 			authDecoder.Err = c.errDecodeAuth
-			colNoVdtor.Err = c.errValidateColNo
+			taskRetriever.Res = c.task
+			taskRetriever.Err = c.errRetrieveTask
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			boardTasksRetriever.Res = c.tasks
+			boardTasksRetriever.Err = c.errRetrieveTasks
+			overrideInserter.Err = c.errInsertOverride
 			tasksUpdater.Err = c.errUpdateTasks
+			url := "/"
+			if c.override {
+				url += "?overrideWIP=true"
+			}
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest("", "/", strings.NewReader(c.rBody))
+			r := httptest.NewRequest("", url, strings.NewReader(c.rBody))
 			if c.authToken != "" {
 				r.AddCookie(&http.Cookie{
 					Name: "auth-token", Value: c.authToken,
@@ -9,33 +9,33 @@ import (
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
-// TestColNoValidator tests the ColNoValidator.Validate method.
-func TestColNoValidator(t *testing.T) {
-	sut := NewColNoValidator()
+// TestBoardIDValidator tests the BoardIDValidator.Validate method.
+func TestBoardIDValidator(t *testing.T) {
+	sut := NewBoardIDValidator()
 
 	for _, c := range []struct {
 		name    string
-		colNo   int
+		boardID string
 		wantErr error
 	}{
 		{
-			name:    "ColNoTooSmall",
-			colNo:   -1,
-			wantErr: validator.ErrOutOfBounds,
+			name:    "Empty",
+			boardID: "",
+			wantErr: validator.ErrEmpty,
 		},
 		{
-			name:    "ColNoTooBig",
-			colNo:   4,
-			wantErr: validator.ErrOutOfBounds,
+			name:    "NotUUID",
+			boardID: "boardid",
+			wantErr: validator.ErrWrongFormat,
 		},
 		{
 			name:    "Success",
-			colNo:   2,
+			boardID: "b3bda61e-67ba-4e27-8a2d-4e3ec6e27b5e",
 			wantErr: nil,
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			err := sut.Validate(c.colNo)
+			err := sut.Validate(c.boardID)
 			assert.ErrIs(t.Error, err, c.wantErr)
 		})
 	}
@@ -0,0 +1,158 @@
+package boardapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// ArchiveResp defines the body of POST and DELETE board archive responses.
+type ArchiveResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// ArchiveHandler is an api.MethodHandler that can be used to handle POST
+// requests that archive a board and DELETE requests that restore one, both
+// sent to the board archive route.
+type ArchiveHandler struct {
+	archived      bool
+	authDecoder   cookie.Decoder[cookie.Auth]
+	idValidator   validator.String
+	boardArchiver db.ArchiverDualKey
+	log           log.Errorer
+}
+
+// NewArchiveHandler creates and returns a new ArchiveHandler that archives
+// boards.
+func NewArchiveHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	idValidator validator.String,
+	boardArchiver db.ArchiverDualKey,
+	log log.Errorer,
+) ArchiveHandler {
+	return ArchiveHandler{
+		archived:      true,
+		authDecoder:   authDecoder,
+		idValidator:   idValidator,
+		boardArchiver: boardArchiver,
+		log:           log,
+	}
+}
+
+// NewRestoreHandler creates and returns a new ArchiveHandler that restores
+// archived boards.
+func NewRestoreHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	idValidator validator.String,
+	boardArchiver db.ArchiverDualKey,
+	log log.Errorer,
+) ArchiveHandler {
+	return ArchiveHandler{
+		archived:      false,
+		authDecoder:   authDecoder,
+		idValidator:   idValidator,
+		boardArchiver: boardArchiver,
+		log:           log,
+	}
+}
+
+// Handle handles POST and DELETE board archive requests.
+func (h ArchiveHandler) Handle(
+	w http.ResponseWriter, r *http.Request, username string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(
+			ArchiveResp{Error: "Auth token not found."},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(
+			ArchiveResp{Error: "Invalid auth token."},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can archive and restore boards
+	if !role.Can(auth.Role, role.EditBoard) {
+		w.WriteHeader(http.StatusForbidden)
+		if err = json.NewEncoder(w).Encode(ArchiveResp{
+			Error: "Only team admins can archive and restore boards.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate board ID
+	id := r.URL.Query().Get("id")
+	if err := h.idValidator.Validate(id); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Board ID cannot be empty."
+		} else if errors.Is(err, validator.ErrWrongFormat) {
+			msg = "Board ID must be a UUID."
+		}
+
+		if err = json.NewEncoder(w).Encode(
+			ArchiveResp{Error: msg},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// archive or restore the board
+	err = h.boardArchiver.Archive(r.Context(), auth.TeamID, id, h.archived)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(
+			ArchiveResp{Error: "Board not found."},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if errors.Is(err, db.ErrLimitReached) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(ArchiveResp{
+			Error: "You already have the maximum amount of boards allowed " +
+				"per team. Please delete or archive one of your boards to " +
+				"restore this one.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
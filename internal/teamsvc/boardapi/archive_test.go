@@ -0,0 +1,170 @@
+//go:build utest
+
+package boardapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestArchiveHandler tests the Handle method of ArchiveHandler, both when it
+// archives boards and when it restores them, to assert that it behaves
+// correctly in all possible scenarios.
+func TestArchiveHandler(t *testing.T) {
+	decodeAuth := &cookie.FakeDecoder[cookie.Auth]{}
+	idValidator := &api.FakeStringValidator{}
+	archiver := &db.FakeArchiverDualKey{}
+	log := &log.FakeErrorer{}
+
+	for name, sut := range map[string]ArchiveHandler{
+		"Archive": NewArchiveHandler(decodeAuth, idValidator, archiver, log),
+		"Restore": NewRestoreHandler(decodeAuth, idValidator, archiver, log),
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, c := range []struct {
+				name          string
+				authToken     string
+				errDecodeAuth error
+				authDecoded   cookie.Auth
+				errValidateID error
+				errArchive    error
+				wantStatus    int
+				assertFunc    func(*testing.T, *http.Response, []any)
+			}{
+				{
+					name:          "NoAuth",
+					authToken:     "",
+					errDecodeAuth: nil,
+					authDecoded:   cookie.Auth{},
+					errValidateID: nil,
+					errArchive:    nil,
+					wantStatus:    http.StatusUnauthorized,
+					assertFunc:    assert.OnRespErr("Auth token not found."),
+				},
+				{
+					name:          "InvalidAuth",
+					authToken:     "nonempty",
+					errDecodeAuth: cookie.ErrInvalid,
+					authDecoded:   cookie.Auth{},
+					errValidateID: nil,
+					errArchive:    nil,
+					wantStatus:    http.StatusUnauthorized,
+					assertFunc:    assert.OnRespErr("Invalid auth token."),
+				},
+				{
+					name:          "NotAdmin",
+					authToken:     "nonempty",
+					errDecodeAuth: nil,
+					authDecoded:   cookie.Auth{Role: role.Member},
+					errValidateID: nil,
+					errArchive:    nil,
+					wantStatus:    http.StatusForbidden,
+					assertFunc: assert.OnRespErr(
+						"Only team admins can archive and restore boards.",
+					),
+				},
+				{
+					name:          "IDEmpty",
+					authToken:     "nonempty",
+					errDecodeAuth: nil,
+					authDecoded:   cookie.Auth{Role: role.Admin},
+					errValidateID: validator.ErrEmpty,
+					errArchive:    nil,
+					wantStatus:    http.StatusBadRequest,
+					assertFunc: assert.OnRespErr(
+						"Board ID cannot be empty.",
+					),
+				},
+				{
+					name:          "IDNotUUID",
+					authToken:     "nonempty",
+					errDecodeAuth: nil,
+					authDecoded:   cookie.Auth{Role: role.Admin},
+					errValidateID: validator.ErrWrongFormat,
+					errArchive:    nil,
+					wantStatus:    http.StatusBadRequest,
+					assertFunc: assert.OnRespErr(
+						"Board ID must be a UUID.",
+					),
+				},
+				{
+					name:          "BoardNotFound",
+					authToken:     "nonempty",
+					errDecodeAuth: nil,
+					authDecoded:   cookie.Auth{Role: role.Admin},
+					errValidateID: nil,
+					errArchive:    db.ErrNoItem,
+					wantStatus:    http.StatusNotFound,
+					assertFunc:    assert.OnRespErr("Board not found."),
+				},
+				{
+					name:          "LimitReached",
+					authToken:     "nonempty",
+					errDecodeAuth: nil,
+					authDecoded:   cookie.Auth{Role: role.Admin},
+					errValidateID: nil,
+					errArchive:    db.ErrLimitReached,
+					wantStatus:    http.StatusBadRequest,
+					assertFunc: assert.OnRespErr(
+						"You already have the maximum amount of boards " +
+							"allowed per team. Please delete or archive one " +
+							"of your boards to restore this one.",
+					),
+				},
+				{
+					name:          "ErrArchive",
+					authToken:     "nonempty",
+					errDecodeAuth: nil,
+					authDecoded:   cookie.Auth{Role: role.Admin},
+					errValidateID: nil,
+					errArchive:    errors.New("archive failed"),
+					wantStatus:    http.StatusInternalServerError,
+					assertFunc:    assert.OnLoggedErr("archive failed"),
+				},
+				{
+					name:          "OK",
+					authToken:     "nonempty",
+					errDecodeAuth: nil,
+					authDecoded:   cookie.Auth{Role: role.Admin},
+					errValidateID: nil,
+					errArchive:    nil,
+					wantStatus:    http.StatusOK,
+					assertFunc:    func(*testing.T, *http.Response, []any) {},
+				},
+			} {
+				t.Run(c.name, func(t *testing.T) {
+					decodeAuth.Err = c.errDecodeAuth
+					decodeAuth.Res = c.authDecoded
+					idValidator.Err = c.errValidateID
+					archiver.Err = c.errArchive
+					w := httptest.NewRecorder()
+					r := httptest.NewRequest(
+						"", "/?id=c193d6ba-ebfe-45fe-80d9-00b545690b4b", nil,
+					)
+					if c.authToken != "" {
+						r.AddCookie(&http.Cookie{
+							Name:  cookie.AuthName,
+							Value: c.authToken,
+						})
+					}
+
+					sut.Handle(w, r, "")
+
+					resp := w.Result()
+					assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+					c.assertFunc(t, resp, log.Args)
+				})
+			}
+		})
+	}
+}
@@ -9,12 +9,14 @@ import (
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 // DeleteHandler is an api.MethodHandler that can be used to handle DELETE board
 // requests.
 type DeleteHandler struct {
 	authDecoder  cookie.Decoder[cookie.Auth]
+	taskDeleter  db.DeleterDualKey
 	boardDeleter db.DeleterDualKey
 	log          log.Errorer
 }
@@ -22,11 +24,13 @@ type DeleteHandler struct {
 // NewDeleteHandler creates and returns a new DeleteHandler.
 func NewDeleteHandler(
 	authDecoder cookie.Decoder[cookie.Auth],
+	taskDeleter db.DeleterDualKey,
 	boardDeleter db.DeleterDualKey,
 	log log.Errorer,
 ) DeleteHandler {
 	return DeleteHandler{
 		authDecoder:  authDecoder,
+		taskDeleter:  taskDeleter,
 		boardDeleter: boardDeleter,
 		log:          log,
 	}
@@ -37,7 +41,7 @@ func (h DeleteHandler) Handle(
 	w http.ResponseWriter, r *http.Request, username string,
 ) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
@@ -54,8 +58,8 @@ func (h DeleteHandler) Handle(
 		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
+	// validate user can delete boards
+	if !role.Can(auth.Role, role.DeleteBoard) {
 		w.WriteHeader(http.StatusForbidden)
 		return
 	}
@@ -67,6 +71,16 @@ func (h DeleteHandler) Handle(
 		return
 	}
 
+	// delete the board's tasks before the board itself so that, if either
+	// fails, the board is still there for the request to be sent again - this
+	// also deletes any tasks that are left over from a board that was already
+	// deleted, since deleting tasks that don't exist isn't an error
+	if err = h.taskDeleter.Delete(r.Context(), auth.TeamID, id); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	// delete the board
 	if err = h.boardDeleter.Delete(
 		r.Context(), auth.TeamID, id,
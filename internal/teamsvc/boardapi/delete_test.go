@@ -12,15 +12,17 @@ import (
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 // TestDeleteHandler tests the Handle method of DELETEHandler to assert that it
 // behaves correctly in all possible scenarios.
 func TestDeleteHandler(t *testing.T) {
 	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
-	deleter := &db.FakeDeleterDualKey{}
+	taskDeleter := &db.FakeDeleterDualKey{}
+	boardDeleter := &db.FakeDeleterDualKey{}
 	log := &log.FakeErrorer{}
-	sut := NewDeleteHandler(authDecoder, deleter, log)
+	sut := NewDeleteHandler(authDecoder, taskDeleter, boardDeleter, log)
 
 	for _, c := range []struct {
 		name           string
@@ -28,6 +30,7 @@ func TestDeleteHandler(t *testing.T) {
 		authToken      string
 		errDecodeAuth  error
 		authDecoded    cookie.Auth
+		deleteTasksErr error
 		deleteBoardErr error
 		wantStatusCode int
 		assertFunc     func(*testing.T, *http.Response, []any)
@@ -38,6 +41,7 @@ func TestDeleteHandler(t *testing.T) {
 			authToken:      "",
 			errDecodeAuth:  nil,
 			authDecoded:    cookie.Auth{},
+			deleteTasksErr: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusUnauthorized,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
@@ -48,6 +52,7 @@ func TestDeleteHandler(t *testing.T) {
 			authToken:      "nonempty",
 			errDecodeAuth:  cookie.ErrInvalid,
 			authDecoded:    cookie.Auth{},
+			deleteTasksErr: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusUnauthorized,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
@@ -57,7 +62,8 @@ func TestDeleteHandler(t *testing.T) {
 			boardID:        "",
 			authToken:      "nonempty",
 			errDecodeAuth:  nil,
-			authDecoded:    cookie.Auth{IsAdmin: false},
+			authDecoded:    cookie.Auth{Role: role.Member},
+			deleteTasksErr: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusForbidden,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
@@ -67,7 +73,8 @@ func TestDeleteHandler(t *testing.T) {
 			boardID:        "",
 			authToken:      "nonempty",
 			errDecodeAuth:  nil,
-			authDecoded:    cookie.Auth{IsAdmin: true},
+			authDecoded:    cookie.Auth{Role: role.Admin},
+			deleteTasksErr: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusBadRequest,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
@@ -77,17 +84,30 @@ func TestDeleteHandler(t *testing.T) {
 			boardID:        "adksfjahsd",
 			authToken:      "nonempty",
 			errDecodeAuth:  nil,
-			authDecoded:    cookie.Auth{IsAdmin: true},
+			authDecoded:    cookie.Auth{Role: role.Admin},
+			deleteTasksErr: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusBadRequest,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
 		},
+		{
+			name:           "DeleteTasksErr",
+			boardID:        "66c16e54-c14f-4481-ada6-404bca897fb0",
+			authToken:      "nonempty",
+			errDecodeAuth:  nil,
+			authDecoded:    cookie.Auth{Role: role.Admin, TeamID: "1"},
+			deleteTasksErr: errors.New("delete tasks failed"),
+			deleteBoardErr: nil,
+			wantStatusCode: http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("delete tasks failed"),
+		},
 		{
 			name:           "ErrNoItem",
 			boardID:        "66c16e54-c14f-4481-ada6-404bca897fb0",
 			authToken:      "nonempty",
 			errDecodeAuth:  nil,
-			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			authDecoded:    cookie.Auth{Role: role.Admin, TeamID: "1"},
+			deleteTasksErr: nil,
 			deleteBoardErr: db.ErrNoItem,
 			wantStatusCode: http.StatusNotFound,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
@@ -97,7 +117,8 @@ func TestDeleteHandler(t *testing.T) {
 			boardID:        "66c16e54-c14f-4481-ada6-404bca897fb0",
 			authToken:      "nonempty",
 			errDecodeAuth:  nil,
-			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			authDecoded:    cookie.Auth{Role: role.Admin, TeamID: "1"},
+			deleteTasksErr: nil,
 			deleteBoardErr: errors.New("delete board failed"),
 			wantStatusCode: http.StatusInternalServerError,
 			assertFunc:     assert.OnLoggedErr("delete board failed"),
@@ -107,7 +128,8 @@ func TestDeleteHandler(t *testing.T) {
 			boardID:        "66c16e54-c14f-4481-ada6-404bca897fb0",
 			authToken:      "nonempty",
 			errDecodeAuth:  nil,
-			authDecoded:    cookie.Auth{IsAdmin: true, TeamID: "1"},
+			authDecoded:    cookie.Auth{Role: role.Admin, TeamID: "1"},
+			deleteTasksErr: nil,
 			deleteBoardErr: nil,
 			wantStatusCode: http.StatusOK,
 			assertFunc:     func(*testing.T, *http.Response, []any) {},
@@ -116,7 +138,8 @@ func TestDeleteHandler(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			authDecoder.Err = c.errDecodeAuth
 			authDecoder.Res = c.authDecoded
-			deleter.Err = c.deleteBoardErr
+			taskDeleter.Err = c.deleteTasksErr
+			boardDeleter.Err = c.deleteBoardErr
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodPost, "/?id="+c.boardID, nil)
 			if c.authToken != "" {
@@ -0,0 +1,120 @@
+package boardapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetArchivedResp defines the body of GET board archive responses.
+type GetArchivedResp struct {
+	Boards []ArchivedBoard `json:"boards"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// ArchivedBoard defines an archived board in GET board archive responses.
+type ArchivedBoard struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+// GetArchivedHandler is an api.MethodHandler that can be used to handle GET
+// requests sent to the board archive route to list the team's archived boards.
+type GetArchivedHandler struct {
+	authDecoder   cookie.Decoder[cookie.Auth]
+	teamRetriever db.Retriever[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewGetArchivedHandler creates and returns a new GetArchivedHandler.
+func NewGetArchivedHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	teamRetriever db.Retriever[teamtbl.Team],
+	log log.Errorer,
+) GetArchivedHandler {
+	return GetArchivedHandler{
+		authDecoder:   authDecoder,
+		teamRetriever: teamRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles GET board archive requests.
+func (h GetArchivedHandler) Handle(
+	w http.ResponseWriter, r *http.Request, username string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(
+			GetArchivedResp{Error: "Auth token not found."},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(
+			GetArchivedResp{Error: "Invalid auth token."},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(
+			GetArchivedResp{Error: "Team not found."},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// list the team's archived boards - users who aren't admins only see the
+	// ones they are a member of, same as the team's other boards
+	boards := []ArchivedBoard{}
+	for _, b := range team.Boards {
+		if !b.Archived || (!auth.Role.IsAdmin() &&
+			!team.IsBoardMember(b.ID, auth.Username)) {
+			continue
+		}
+		boards = append(boards, ArchivedBoard{
+			ID: b.ID, Name: b.Name, Members: b.Members,
+		})
+	}
+
+	// encode the boards
+	if err = json.NewEncoder(w).Encode(
+		GetArchivedResp{Boards: boards},
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,153 @@
+//go:build utest
+
+package boardapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestGetArchivedHandler tests the Handle method of GetArchivedHandler to
+// assert that it behaves correctly in all possible scenarios.
+func TestGetArchivedHandler(t *testing.T) {
+	decodeAuth := &cookie.FakeDecoder[cookie.Auth]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetArchivedHandler(decodeAuth, teamRetriever, log)
+
+	team := teamtbl.Team{Boards: []teamtbl.Board{
+		{ID: "board1", Name: "Board 1", Members: []string{"bob123"}},
+		{
+			ID:       "board2",
+			Name:     "Board 2",
+			Members:  []string{"bob123"},
+			Archived: true,
+		},
+		{ID: "board3", Name: "Board 3", Archived: true},
+	}}
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		authDecoded   cookie.Auth
+		team          teamtbl.Team
+		errRetrieve   error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			team:          teamtbl.Team{},
+			errRetrieve:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			authDecoded:   cookie.Auth{},
+			team:          teamtbl.Team{},
+			errRetrieve:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "TeamNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			team:          teamtbl.Team{},
+			errRetrieve:   db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Team not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			team:          teamtbl.Team{},
+			errRetrieve:   errors.New("retrieve failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "OKAdmin",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			team:          team,
+			errRetrieve:   nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody GetArchivedResp
+				if err := json.NewDecoder(resp.Body).Decode(
+					&respBody,
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Fatal, len(respBody.Boards), 2)
+				assert.Equal(t.Error, respBody.Boards[0].ID, "board2")
+				assert.Equal(t.Error, respBody.Boards[0].Name, "Board 2")
+				assert.Equal(t.Error, respBody.Boards[1].ID, "board3")
+			},
+		},
+		{
+			name:          "OKMember",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded: cookie.Auth{
+				Username: "bob123", Role: role.Member,
+			},
+			team:        team,
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody GetArchivedResp
+				if err := json.NewDecoder(resp.Body).Decode(
+					&respBody,
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Fatal, len(respBody.Boards), 1)
+				assert.Equal(t.Error, respBody.Boards[0].ID, "board2")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			decodeAuth.Err = c.errDecodeAuth
+			decodeAuth.Res = c.authDecoded
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  cookie.AuthName,
+					Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -9,11 +9,17 @@ import (
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
-// PatchReq defines the body of PATCH board requests.
-type PatchReq teamtbl.Board
+// PatchReq defines the body of PATCH board requests. Only the board's name can
+// be changed through them - its members are edited through the user route and
+// its columns through the columns route.
+type PatchReq struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
 
 // PatchResp defines the body of PATCH board responses.
 type PatchResp struct {
@@ -25,6 +31,7 @@ type PatchHandler struct {
 	authDecoder   cookie.Decoder[cookie.Auth]
 	idValidator   validator.String
 	nameValidator validator.String
+	teamRetriever db.Retriever[teamtbl.Team]
 	boardUpdater  db.UpdaterDualKey[teamtbl.Board]
 	log           log.Errorer
 }
@@ -35,6 +42,7 @@ func NewPatchHandler(
 	authDecoder cookie.Decoder[cookie.Auth],
 	idValidator validator.String,
 	nameValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
 	boardUpdater db.UpdaterDualKey[teamtbl.Board],
 	log log.Errorer,
 ) *PatchHandler {
@@ -42,6 +50,7 @@ func NewPatchHandler(
 		authDecoder:   authDecoder,
 		idValidator:   idValidator,
 		nameValidator: nameValidator,
+		teamRetriever: teamRetriever,
 		boardUpdater:  boardUpdater,
 		log:           log,
 	}
@@ -52,7 +61,7 @@ func (h *PatchHandler) Handle(
 	w http.ResponseWriter, r *http.Request, username string,
 ) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		if err := json.NewEncoder(w).Encode(
@@ -81,8 +90,8 @@ func (h *PatchHandler) Handle(
 		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
+	// validate user can edit boards
+	if !role.Can(auth.Role, role.EditBoard) {
 		w.WriteHeader(http.StatusForbidden)
 		if err = json.NewEncoder(w).Encode(PatchResp{
 			Error: "Only team admins can edit boards.",
@@ -135,9 +144,45 @@ func (h *PatchHandler) Handle(
 		return
 	}
 
-	// update the board for the team
+	// retrieve the board as it is stored so that only its name is changed
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(
+			PatchResp{Error: "Team not found."},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	var board teamtbl.Board
+	var found bool
+	for _, b := range team.Boards {
+		if b.ID == req.ID {
+			board, found = b, true
+			break
+		}
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(
+			PatchResp{Error: "Board not found."},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// rename the board
+	board.Name = req.Name
 	if err := h.boardUpdater.Update(
-		r.Context(), auth.TeamID, teamtbl.Board(req),
+		r.Context(), auth.TeamID, board,
 	); errors.Is(err, db.ErrNoItem) {
 		w.WriteHeader(http.StatusNotFound)
 		if err := json.NewEncoder(w).Encode(
@@ -15,6 +15,7 @@ import (
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -22,16 +23,23 @@ func TestPatchHandler(t *testing.T) {
 	decodeAuth := &cookie.FakeDecoder[cookie.Auth]{}
 	idValidator := &api.FakeStringValidator{}
 	nameValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
 	updater := &db.FakeUpdaterDualKey[teamtbl.Board]{}
 	log := &log.FakeErrorer{}
 	sut := NewPatchHandler(
 		decodeAuth,
 		idValidator,
 		nameValidator,
+		teamRetriever,
 		updater,
 		log,
 	)
 
+	boardID := "c193d6ba-ebfe-45fe-80d9-00b545690b4b"
+	team := teamtbl.Team{Boards: []teamtbl.Board{{
+		ID: boardID, Name: "Board", Members: []string{"bob124"},
+	}}}
+
 	for _, c := range []struct {
 		name            string
 		authToken       string
@@ -39,6 +47,8 @@ func TestPatchHandler(t *testing.T) {
 		authDecoded     cookie.Auth
 		errValidateID   error
 		errValidateName error
+		team            teamtbl.Team
+		errRetrieve     error
 		errUpdateBoard  error
 		wantStatus      int
 		assertFunc      func(*testing.T, *http.Response, []any)
@@ -50,6 +60,8 @@ func TestPatchHandler(t *testing.T) {
 			authDecoded:     cookie.Auth{},
 			errValidateID:   nil,
 			errValidateName: nil,
+			team:            teamtbl.Team{},
+			errRetrieve:     nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusUnauthorized,
 			assertFunc:      assert.OnRespErr("Auth token not found."),
@@ -61,6 +73,8 @@ func TestPatchHandler(t *testing.T) {
 			authDecoded:     cookie.Auth{},
 			errValidateID:   nil,
 			errValidateName: nil,
+			team:            teamtbl.Team{},
+			errRetrieve:     nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusUnauthorized,
 			assertFunc:      assert.OnRespErr("Invalid auth token."),
@@ -69,9 +83,11 @@ func TestPatchHandler(t *testing.T) {
 			name:            "NotAdmin",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: false},
+			authDecoded:     cookie.Auth{Role: role.Member},
 			errValidateID:   nil,
 			errValidateName: nil,
+			team:            teamtbl.Team{},
+			errRetrieve:     nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusForbidden,
 			assertFunc: assert.OnRespErr(
@@ -82,9 +98,11 @@ func TestPatchHandler(t *testing.T) {
 			name:            "IDEmpty",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateID:   validator.ErrEmpty,
 			errValidateName: nil,
+			team:            teamtbl.Team{},
+			errRetrieve:     nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusBadRequest,
 			assertFunc:      assert.OnRespErr("Board ID cannot be empty."),
@@ -93,9 +111,11 @@ func TestPatchHandler(t *testing.T) {
 			name:            "IDNotUUID",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateID:   validator.ErrWrongFormat,
 			errValidateName: nil,
+			team:            teamtbl.Team{},
+			errRetrieve:     nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusBadRequest,
 			assertFunc:      assert.OnRespErr("Board ID must be a UUID."),
@@ -104,9 +124,11 @@ func TestPatchHandler(t *testing.T) {
 			name:            "NameEmpty",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateID:   nil,
 			errValidateName: validator.ErrEmpty,
+			team:            teamtbl.Team{},
+			errRetrieve:     nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusBadRequest,
 			assertFunc:      assert.OnRespErr("Board name cannot be empty."),
@@ -115,21 +137,65 @@ func TestPatchHandler(t *testing.T) {
 			name:            "NameTooLong",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateID:   nil,
 			errValidateName: validator.ErrTooLong,
+			team:            teamtbl.Team{},
+			errRetrieve:     nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"Board name cannot be longer than 35 characters.",
 			),
 		},
+		{
+			name:            "TeamNotFound",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			authDecoded:     cookie.Auth{Role: role.Admin},
+			errValidateID:   nil,
+			errValidateName: nil,
+			team:            teamtbl.Team{},
+			errRetrieve:     db.ErrNoItem,
+			errUpdateBoard:  nil,
+			wantStatus:      http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Team not found."),
+		},
+		{
+			name:            "ErrRetrieveTeam",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			authDecoded:     cookie.Auth{Role: role.Admin},
+			errValidateID:   nil,
+			errValidateName: nil,
+			team:            teamtbl.Team{},
+			errRetrieve:     errors.New("retrieve team failed"),
+			errUpdateBoard:  nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve team failed"),
+		},
 		{
 			name:            "BoardNotFound",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
+			errValidateID:   nil,
+			errValidateName: nil,
+			team:            teamtbl.Team{},
+			errRetrieve:     nil,
+			errUpdateBoard:  nil,
+			wantStatus:      http.StatusNotFound,
+			assertFunc:      assert.OnRespErr("Board not found."),
+		},
+		{
+			name:            "BoardDeleted",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			authDecoded:     cookie.Auth{Role: role.Admin},
+			errValidateID:   nil,
 			errValidateName: nil,
+			team:            team,
+			errRetrieve:     nil,
 			errUpdateBoard:  db.ErrNoItem,
 			wantStatus:      http.StatusNotFound,
 			assertFunc:      assert.OnRespErr("Board not found."),
@@ -138,8 +204,11 @@ func TestPatchHandler(t *testing.T) {
 			name:            "BoardUpdaterErr",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
+			errValidateID:   nil,
 			errValidateName: nil,
+			team:            team,
+			errRetrieve:     nil,
 			errUpdateBoard:  errors.New("update board failed"),
 			wantStatus:      http.StatusInternalServerError,
 			assertFunc:      assert.OnLoggedErr("update board failed"),
@@ -148,8 +217,11 @@ func TestPatchHandler(t *testing.T) {
 			name:            "Success",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
+			errValidateID:   nil,
 			errValidateName: nil,
+			team:            team,
+			errRetrieve:     nil,
 			errUpdateBoard:  nil,
 			wantStatus:      http.StatusOK,
 			assertFunc:      func(*testing.T, *http.Response, []any) {},
@@ -160,10 +232,12 @@ func TestPatchHandler(t *testing.T) {
 			decodeAuth.Res = c.authDecoded
 			idValidator.Err = c.errValidateID
 			nameValidator.Err = c.errValidateName
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
 			updater.Err = c.errUpdateBoard
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("", "/", strings.NewReader(`{
-                "id": "c193d6ba-ebfe-45fe-80d9-00b545690b4b"
+                "id": "`+boardID+`"
             }`))
 			if c.authToken != "" {
 				r.AddCookie(&http.Cookie{
@@ -11,6 +11,7 @@ import (
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -53,7 +54,7 @@ func (h PostHandler) Handle(
 	w http.ResponseWriter, r *http.Request, username string,
 ) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		if err := json.NewEncoder(w).Encode(
@@ -82,8 +83,8 @@ func (h PostHandler) Handle(
 		return
 	}
 
-	// validate user is admin
-	if !auth.IsAdmin {
+	// validate user can create boards
+	if !role.Can(auth.Role, role.CreateBoard) {
 		w.WriteHeader(http.StatusForbidden)
 		if err = json.NewEncoder(w).Encode(PatchResp{
 			Error: "Only team admins can edit boards.",
@@ -121,9 +122,9 @@ func (h PostHandler) Handle(
 	// times for the unlikely event that the generated UUID is a duplicate
 	for i := 0; i < 3; i++ {
 		id := uuid.NewString()
-		if err = h.inserter.Insert(r.Context(), auth.TeamID, teamtbl.Board{
-			ID: id, Name: req.Name,
-		}); !errors.Is(err, db.ErrDupKey) {
+		if err = h.inserter.Insert(
+			r.Context(), auth.TeamID, teamtbl.NewBoard(id, req.Name),
+		); !errors.Is(err, db.ErrDupKey) {
 			break
 		}
 	}
@@ -132,8 +133,8 @@ func (h PostHandler) Handle(
 		if err := json.NewEncoder(w).Encode(
 			PostResp{
 				Error: "You have already created the maximum amount of " +
-					"boards allowed per team. Please delete one of your " +
-					"boards to create a new one.",
+					"boards allowed per team. Please delete or archive one " +
+					"of your boards to create a new one.",
 			},
 		); err != nil {
 			h.log.Error(err)
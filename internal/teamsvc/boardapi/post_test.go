@@ -15,6 +15,7 @@ import (
 	"github.com/kxplxn/goteam/pkg/db"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/pkg/validator"
 )
 
@@ -59,7 +60,7 @@ func TestPostHandler(t *testing.T) {
 			name:            "NotAdmin",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: false},
+			authDecoded:     cookie.Auth{Role: role.Member},
 			errValidateName: nil,
 			boardUpdaterErr: nil,
 			wantStatusCode:  http.StatusForbidden,
@@ -71,7 +72,7 @@ func TestPostHandler(t *testing.T) {
 			name:            "NameEmpty",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateName: validator.ErrEmpty,
 			boardUpdaterErr: nil,
 			wantStatusCode:  http.StatusBadRequest,
@@ -81,7 +82,7 @@ func TestPostHandler(t *testing.T) {
 			name:            "NameTooLong",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateName: validator.ErrTooLong,
 			boardUpdaterErr: nil,
 			wantStatusCode:  http.StatusBadRequest,
@@ -93,21 +94,21 @@ func TestPostHandler(t *testing.T) {
 			name:            "ErrLimitReached",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateName: nil,
 			boardUpdaterErr: db.ErrLimitReached,
 			wantStatusCode:  http.StatusBadRequest,
 			assertFunc: assert.OnRespErr(
 				"You have already created the maximum amount of boards " +
-					"allowed per team. Please delete one of your boards to " +
-					"create a new one.",
+					"allowed per team. Please delete or archive one of " +
+					"your boards to create a new one.",
 			),
 		},
 		{
 			name:            "BoardUpdaterErr",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateName: nil,
 			boardUpdaterErr: errors.New("update board failed"),
 			wantStatusCode:  http.StatusInternalServerError,
@@ -117,7 +118,7 @@ func TestPostHandler(t *testing.T) {
 			name:            "Success",
 			authToken:       "nonempty",
 			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
+			authDecoded:     cookie.Auth{Role: role.Admin},
 			errValidateName: nil,
 			boardUpdaterErr: nil,
 			wantStatusCode:  http.StatusOK,
@@ -0,0 +1,28 @@
+// Package columnapi contains code for responding to HTTP requests made to the
+// board columns API route, which is used by team admins for adding, editing,
+// reordering, and deleting the columns of a board.
+package columnapi
+
+import "github.com/kxplxn/goteam/pkg/db/teamtbl"
+
+// findBoard returns the board with the given ID from the given team's boards
+// along with whether it was found.
+func findBoard(team teamtbl.Team, boardID string) (teamtbl.Board, bool) {
+	for _, b := range team.Boards {
+		if b.ID == boardID {
+			return b, true
+		}
+	}
+	return teamtbl.Board{}, false
+}
+
+// findColumn returns the index of the column with the given ID in the given
+// columns, or -1 if it is not found.
+func findColumn(cols []teamtbl.Column, colID string) int {
+	for i, c := range cols {
+		if c.ID == colID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,272 @@
+package columnapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// maxTasksPerUpdate is the maximum number of tasks that can be updated at once
+// since the task table's multi-updater writes them in a single transaction.
+const maxTasksPerUpdate = 100
+
+// DeleteResp defines the body of DELETE column responses.
+type DeleteResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteHandler is an api.MethodHandler that can be used to handle DELETE
+// column requests, which delete a board's column after moving its tasks to
+// another one of its columns.
+type DeleteHandler struct {
+	authDecoder      cookie.Decoder[cookie.Auth]
+	boardIDValidator validator.String
+	teamRetriever    db.Retriever[teamtbl.Team]
+	taskRetriever    db.Retriever[[]tasktbl.Task]
+	tasksUpdater     db.Updater[[]tasktbl.Task]
+	boardUpdater     db.UpdaterDualKey[teamtbl.Board]
+	log              log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	boardIDValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	taskRetriever db.Retriever[[]tasktbl.Task],
+	tasksUpdater db.Updater[[]tasktbl.Task],
+	boardUpdater db.UpdaterDualKey[teamtbl.Board],
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		authDecoder:      authDecoder,
+		boardIDValidator: boardIDValidator,
+		teamRetriever:    teamRetriever,
+		taskRetriever:    taskRetriever,
+		tasksUpdater:     tasksUpdater,
+		boardUpdater:     boardUpdater,
+		log:              log,
+	}
+}
+
+// Handle handles DELETE column requests.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can edit boards
+	if !role.Can(auth.Role, role.EditBoard) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Only team admins can edit boards.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate request
+	boardID := r.URL.Query().Get("boardID")
+	colID := r.URL.Query().Get("id")
+	moveTo := r.URL.Query().Get("moveTo")
+	if err := h.boardIDValidator.Validate(boardID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Board ID cannot be empty."
+		} else if errors.Is(err, validator.ErrWrongFormat) {
+			msg = "Board ID must be a UUID."
+		}
+
+		if err := json.NewEncoder(w).Encode(
+			DeleteResp{Error: msg},
+		); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if colID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Column ID cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if moveTo == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "ID of the column to move tasks to cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if moveTo == colID {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Tasks cannot be moved to the column being deleted.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team and find the board
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	board, ok := findBoard(team, boardID)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if board.Archived {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Tasks of archived boards cannot be changed.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// find the column to delete and the one to move its tasks to - since they
+	// are different columns, the board cannot be left without any columns
+	cols := board.ColumnsOrDefault()
+	i := findColumn(cols, colID)
+	if i == -1 {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Column not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if findColumn(cols, moveTo) == -1 {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Column to move tasks to not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// move the column's tasks to the end of the other column, keeping their
+	// order
+	tasks, err := h.taskRetriever.Retrieve(r.Context(), boardID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	moved := moveTasks(tasks, colID, moveTo)
+	for len(moved) > 0 {
+		n := min(len(moved), maxTasksPerUpdate)
+		if err = h.tasksUpdater.Update(r.Context(), moved[:n]); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		moved = moved[n:]
+	}
+
+	// delete the column from the board
+	board.Columns = append(cols[:i:i], cols[i+1:]...)
+	if err = h.boardUpdater.Update(
+		r.Context(), auth.TeamID, board,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
+
+// moveTasks returns the tasks in the column with the ID from, moved to the end
+// of the column with the ID to while keeping their order.
+func moveTasks(tasks []tasktbl.Task, from, to string) []tasktbl.Task {
+	var moved []tasktbl.Task
+	nextOrder := 0
+	for _, t := range tasks {
+		if t.ColID == from {
+			moved = append(moved, t)
+		} else if t.ColID == to && t.Order >= nextOrder {
+			nextOrder = t.Order + 1
+		}
+	}
+	sort.SliceStable(moved, func(i, j int) bool {
+		return moved[i].Order < moved[j].Order
+	})
+	for i := range moved {
+		moved[i].ColID = to
+		moved[i].Order = nextOrder + i
+	}
+	return moved
+}
@@ -0,0 +1,484 @@
+//go:build utest
+
+package columnapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	boardIDValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	taskRetriever := &db.FakeRetriever[[]tasktbl.Task]{}
+	tasksUpdater := &db.FakeUpdater[[]tasktbl.Task]{}
+	boardUpdater := &db.FakeUpdaterDualKey[teamtbl.Board]{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(
+		authDecoder,
+		boardIDValidator,
+		teamRetriever,
+		taskRetriever,
+		tasksUpdater,
+		boardUpdater,
+		log,
+	)
+
+	team := teamtbl.Team{Boards: []teamtbl.Board{{ID: "boardid"}}}
+	tasks := []tasktbl.Task{{ID: "task1", ColID: "go"}}
+	admin := cookie.Auth{Role: role.Admin}
+
+	for _, c := range []struct {
+		name             string
+		authToken        string
+		errDecodeAuth    error
+		authDecoded      cookie.Auth
+		errValidateID    error
+		colID            string
+		moveTo           string
+		team             teamtbl.Team
+		errRetrieve      error
+		tasks            []tasktbl.Task
+		errRetrieveTasks error
+		errUpdateTasks   error
+		errUpdate        error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:             "NoAuth",
+			authToken:        "",
+			errDecodeAuth:    nil,
+			authDecoded:      cookie.Auth{},
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusUnauthorized,
+			assertFunc:       assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:             "InvalidAuth",
+			authToken:        "nonempty",
+			errDecodeAuth:    cookie.ErrInvalid,
+			authDecoded:      cookie.Auth{},
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusUnauthorized,
+			assertFunc:       assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:             "NotAdmin",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      cookie.Auth{Role: role.Member},
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit boards.",
+			),
+		},
+		{
+			name:             "BoardIDEmpty",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    validator.ErrEmpty,
+			colID:            "go",
+			moveTo:           "done",
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Board ID cannot be empty."),
+		},
+		{
+			name:             "BoardIDNotUUID",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    validator.ErrWrongFormat,
+			colID:            "go",
+			moveTo:           "done",
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Board ID must be a UUID."),
+		},
+		{
+			name:             "ColIDEmpty",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "",
+			moveTo:           "done",
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Column ID cannot be empty."),
+		},
+		{
+			name:             "MoveToEmpty",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "",
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"ID of the column to move tasks to cannot be empty.",
+			),
+		},
+		{
+			name:             "MoveToSame",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "go",
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Tasks cannot be moved to the column being deleted.",
+			),
+		},
+		{
+			name:             "ErrRetrieve",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             teamtbl.Team{},
+			errRetrieve:      errors.New("retrieve failed"),
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:             "BoardNotFound",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             teamtbl.Team{},
+			errRetrieve:      db.ErrNoItem,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "BoardArchived",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			colID:         "go",
+			moveTo:        "done",
+			team: teamtbl.Team{Boards: []teamtbl.Board{
+				{ID: "boardid", Archived: true},
+			}},
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Tasks of archived boards cannot be changed.",
+			),
+		},
+		{
+			name:             "ColumnNotFound",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "review",
+			moveTo:           "done",
+			team:             team,
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Column not found."),
+		},
+		{
+			name:             "MoveToNotFound",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "review",
+			team:             team,
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusNotFound,
+			assertFunc: assert.OnRespErr(
+				"Column to move tasks to not found.",
+			),
+		},
+		{
+			name:             "ErrRetrieveTasks",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             team,
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: errors.New("retrieve tasks failed"),
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:             "ErrUpdateTasks",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             team,
+			errRetrieve:      nil,
+			tasks:            tasks,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   errors.New("update tasks failed"),
+			errUpdate:        nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("update tasks failed"),
+		},
+		{
+			name:             "BoardDeleted",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             team,
+			errRetrieve:      nil,
+			tasks:            tasks,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        db.ErrNoItem,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Board not found."),
+		},
+		{
+			name:             "ErrUpdate",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             team,
+			errRetrieve:      nil,
+			tasks:            tasks,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        errors.New("update failed"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:             "OK",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             team,
+			errRetrieve:      nil,
+			tasks:            tasks,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusOK,
+			assertFunc:       func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:             "OKNoTasks",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			colID:            "go",
+			moveTo:           "done",
+			team:             team,
+			errRetrieve:      nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			errUpdateTasks:   errors.New("update tasks failed"),
+			errUpdate:        nil,
+			wantStatus:       http.StatusOK,
+			assertFunc:       func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			authDecoder.Res = c.authDecoded
+			boardIDValidator.Err = c.errValidateID
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			taskRetriever.Res = c.tasks
+			taskRetriever.Err = c.errRetrieveTasks
+			tasksUpdater.Err = c.errUpdateTasks
+			boardUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodDelete,
+				"/?boardID=boardid&id="+c.colID+"&moveTo="+c.moveTo,
+				nil,
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  cookie.AuthName,
+					Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
+
+// TestMoveTasks tests the moveTasks function to assert that it moves the tasks
+// in a column to the end of another column while keeping their order.
+func TestMoveTasks(t *testing.T) {
+	tasks := []tasktbl.Task{
+		{ID: "task1", ColID: "go", Order: 3},
+		{ID: "task2", ColID: "done", Order: 0},
+		{ID: "task3", ColID: "go", Order: 1},
+		{ID: "task4", ColID: "inbox", Order: 7},
+		{ID: "task5", ColID: "done", Order: 4},
+	}
+
+	for _, c := range []struct {
+		name      string
+		from      string
+		to        string
+		wantIDs   []string
+		wantOrder []int
+	}{
+		{
+			name:      "NoTasksInColumn",
+			from:      "ready",
+			to:        "done",
+			wantIDs:   []string{},
+			wantOrder: []int{},
+		},
+		{
+			name:      "ToEmptyColumn",
+			from:      "go",
+			to:        "ready",
+			wantIDs:   []string{"task3", "task1"},
+			wantOrder: []int{0, 1},
+		},
+		{
+			name:      "ToColumnWithTasks",
+			from:      "go",
+			to:        "done",
+			wantIDs:   []string{"task3", "task1"},
+			wantOrder: []int{5, 6},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			moved := moveTasks(tasks, c.from, c.to)
+
+			ids := make([]string, len(moved))
+			orders := make([]int, len(moved))
+			for i, task := range moved {
+				assert.Equal(t.Error, task.ColID, c.to)
+				ids[i], orders[i] = task.ID, task.Order
+			}
+			assert.AllEqual(t.Error, ids, c.wantIDs)
+			assert.AllEqual(t.Error, orders, c.wantOrder)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package columnapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PatchReq defines the body of PATCH column requests.
+type PatchReq struct {
+	BoardID  string `json:"boardID"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	WIPLimit int    `json:"wipLimit"`
+}
+
+// PatchResp defines the body of PATCH column responses.
+type PatchResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can be used to handle PATCH column
+// requests, which rename and recolor a board's column and set its WIP limit.
+type PatchHandler struct {
+	authDecoder      cookie.Decoder[cookie.Auth]
+	boardIDValidator validator.String
+	nameValidator    validator.String
+	colorValidator   validator.String
+	teamRetriever    db.Retriever[teamtbl.Team]
+	boardUpdater     db.UpdaterDualKey[teamtbl.Board]
+	log              log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	boardIDValidator validator.String,
+	nameValidator validator.String,
+	colorValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	boardUpdater db.UpdaterDualKey[teamtbl.Board],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		authDecoder:      authDecoder,
+		boardIDValidator: boardIDValidator,
+		nameValidator:    nameValidator,
+		colorValidator:   colorValidator,
+		teamRetriever:    teamRetriever,
+		boardUpdater:     boardUpdater,
+		log:              log,
+	}
+}
+
+// Handle handles PATCH column requests.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can edit boards
+	if !role.Can(auth.Role, role.EditBoard) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Only team admins can edit boards.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate request
+	if err := h.boardIDValidator.Validate(req.BoardID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Board ID cannot be empty."
+		} else if errors.Is(err, validator.ErrWrongFormat) {
+			msg = "Board ID must be a UUID."
+		}
+
+		if err := json.NewEncoder(w).Encode(PatchResp{Error: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if req.ID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Column ID cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err := h.nameValidator.Validate(req.Name); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Column name cannot be empty."
+		} else if errors.Is(err, validator.ErrTooLong) {
+			msg = "Column name cannot be longer than 25 characters."
+		}
+
+		if err := json.NewEncoder(w).Encode(PatchResp{Error: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err := h.colorValidator.Validate(req.Color); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Column color must be a hex color code such as #1a2b3c.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if req.WIPLimit < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Column WIP limit cannot be negative.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team and find the board
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	board, ok := findBoard(team, req.BoardID)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// find the column and update its name, color, and WIP limit
+	board.Columns = board.ColumnsOrDefault()
+	i := findColumn(board.Columns, req.ID)
+	if i == -1 {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Column not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	board.Columns[i].Name = req.Name
+	board.Columns[i].Color = req.Color
+	board.Columns[i].WIPLimit = req.WIPLimit
+	if err = h.boardUpdater.Update(
+		r.Context(), auth.TeamID, board,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,218 @@
+package columnapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PostReq defines the body of POST column requests.
+type PostReq struct {
+	BoardID  string `json:"boardID"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	WIPLimit int    `json:"wipLimit"`
+}
+
+// PostResp defines the body of POST column responses.
+type PostResp struct {
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can be used to handle POST column
+// requests, which add a column to the end of a board's columns.
+type PostHandler struct {
+	authDecoder      cookie.Decoder[cookie.Auth]
+	boardIDValidator validator.String
+	nameValidator    validator.String
+	colorValidator   validator.String
+	teamRetriever    db.Retriever[teamtbl.Team]
+	boardUpdater     db.UpdaterDualKey[teamtbl.Board]
+	log              log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	boardIDValidator validator.String,
+	nameValidator validator.String,
+	colorValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	boardUpdater db.UpdaterDualKey[teamtbl.Board],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		authDecoder:      authDecoder,
+		boardIDValidator: boardIDValidator,
+		nameValidator:    nameValidator,
+		colorValidator:   colorValidator,
+		teamRetriever:    teamRetriever,
+		boardUpdater:     boardUpdater,
+		log:              log,
+	}
+}
+
+// Handle handles POST column requests.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can edit boards
+	if !role.Can(auth.Role, role.EditBoard) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Only team admins can edit boards.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate request
+	if err := h.boardIDValidator.Validate(req.BoardID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Board ID cannot be empty."
+		} else if errors.Is(err, validator.ErrWrongFormat) {
+			msg = "Board ID must be a UUID."
+		}
+
+		if err := json.NewEncoder(w).Encode(PostResp{Error: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err := h.nameValidator.Validate(req.Name); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Column name cannot be empty."
+		} else if errors.Is(err, validator.ErrTooLong) {
+			msg = "Column name cannot be longer than 25 characters."
+		}
+
+		if err := json.NewEncoder(w).Encode(PostResp{Error: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err := h.colorValidator.Validate(req.Color); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Column color must be a hex color code such as #1a2b3c.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if req.WIPLimit < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Column WIP limit cannot be negative.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team and find the board
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	board, ok := findBoard(team, req.BoardID)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// add the column to the end of the board's columns
+	col := teamtbl.NewColumn(
+		uuid.NewString(), req.Name, req.Color, req.WIPLimit,
+	)
+	board.Columns = append(board.ColumnsOrDefault(), col)
+	if err = h.boardUpdater.Update(
+		r.Context(), auth.TeamID, board,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// respond with the column ID for the frontend to refer to it by
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(PostResp{ID: col.ID}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
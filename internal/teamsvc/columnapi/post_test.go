@@ -0,0 +1,322 @@
+//go:build utest
+
+package columnapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	boardIDValidator := &api.FakeStringValidator{}
+	nameValidator := &api.FakeStringValidator{}
+	colorValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	boardUpdater := &db.FakeUpdaterDualKey[teamtbl.Board]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		authDecoder,
+		boardIDValidator,
+		nameValidator,
+		colorValidator,
+		teamRetriever,
+		boardUpdater,
+		log,
+	)
+
+	team := teamtbl.Team{Boards: []teamtbl.Board{{ID: "boardid"}}}
+	admin := cookie.Auth{Role: role.Admin}
+
+	for _, c := range []struct {
+		name             string
+		authToken        string
+		errDecodeAuth    error
+		authDecoded      cookie.Auth
+		errValidateID    error
+		errValidateName  error
+		errValidateColor error
+		wipLimit         int
+		team             teamtbl.Team
+		errRetrieve      error
+		errUpdate        error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:             "NoAuth",
+			authToken:        "",
+			errDecodeAuth:    nil,
+			authDecoded:      cookie.Auth{},
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusUnauthorized,
+			assertFunc:       assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:             "InvalidAuth",
+			authToken:        "nonempty",
+			errDecodeAuth:    cookie.ErrInvalid,
+			authDecoded:      cookie.Auth{},
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusUnauthorized,
+			assertFunc:       assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:             "NotAdmin",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      cookie.Auth{Role: role.Member},
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit boards.",
+			),
+		},
+		{
+			name:             "BoardIDEmpty",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    validator.ErrEmpty,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Board ID cannot be empty."),
+		},
+		{
+			name:             "BoardIDNotUUID",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    validator.ErrWrongFormat,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Board ID must be a UUID."),
+		},
+		{
+			name:             "NameEmpty",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  validator.ErrEmpty,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Column name cannot be empty."),
+		},
+		{
+			name:             "NameTooLong",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  validator.ErrTooLong,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column name cannot be longer than 25 characters.",
+			),
+		},
+		{
+			name:             "ColorInvalid",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: validator.ErrWrongFormat,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column color must be a hex color code such as #1a2b3c.",
+			),
+		},
+		{
+			name:             "WIPLimitNegative",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         -1,
+			team:             teamtbl.Team{},
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column WIP limit cannot be negative.",
+			),
+		},
+		{
+			name:             "ErrRetrieve",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             teamtbl.Team{},
+			errRetrieve:      errors.New("retrieve failed"),
+			errUpdate:        nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:             "BoardNotFound",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team: teamtbl.Team{
+				Boards: []teamtbl.Board{{ID: "otherboardid"}},
+			},
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("Board not found."),
+		},
+		{
+			name:             "BoardDeleted",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             team,
+			errRetrieve:      nil,
+			errUpdate:        db.ErrNoItem,
+			wantStatus:       http.StatusNotFound,
+			assertFunc:       assert.OnRespErr("Board not found."),
+		},
+		{
+			name:             "ErrUpdate",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             team,
+			errRetrieve:      nil,
+			errUpdate:        errors.New("update failed"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:             "OK",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			authDecoded:      admin,
+			errValidateID:    nil,
+			errValidateName:  nil,
+			errValidateColor: nil,
+			wipLimit:         0,
+			team:             team,
+			errRetrieve:      nil,
+			errUpdate:        nil,
+			wantStatus:       http.StatusCreated,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody PostResp
+				if err := json.NewDecoder(resp.Body).Decode(
+					&respBody,
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.True(t.Error, respBody.ID != "")
+				assert.Equal(t.Error, respBody.Error, "")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			authDecoder.Res = c.authDecoded
+			boardIDValidator.Err = c.errValidateID
+			nameValidator.Err = c.errValidateName
+			colorValidator.Err = c.errValidateColor
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			boardUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+				"boardID":  "boardid",
+				"name":     "In Review",
+				"color":    "#1a2b3c",
+				"wipLimit": `+strconv.Itoa(c.wipLimit)+`
+			}`))
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  cookie.AuthName,
+					Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
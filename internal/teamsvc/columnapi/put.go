@@ -0,0 +1,183 @@
+package columnapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PutReq defines the body of PUT column requests.
+type PutReq struct {
+	BoardID   string   `json:"boardID"`
+	ColumnIDs []string `json:"columnIDs"`
+}
+
+// PutResp defines the body of PUT column responses.
+type PutResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PutHandler is an api.MethodHandler that can be used to handle PUT column
+// requests, which reorder a board's columns.
+type PutHandler struct {
+	authDecoder      cookie.Decoder[cookie.Auth]
+	boardIDValidator validator.String
+	teamRetriever    db.Retriever[teamtbl.Team]
+	boardUpdater     db.UpdaterDualKey[teamtbl.Board]
+	log              log.Errorer
+}
+
+// NewPutHandler creates and returns a new PutHandler.
+func NewPutHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	boardIDValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	boardUpdater db.UpdaterDualKey[teamtbl.Board],
+	log log.Errorer,
+) PutHandler {
+	return PutHandler{
+		authDecoder:      authDecoder,
+		boardIDValidator: boardIDValidator,
+		teamRetriever:    teamRetriever,
+		boardUpdater:     boardUpdater,
+		log:              log,
+	}
+}
+
+// Handle handles PUT column requests.
+func (h PutHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can edit boards
+	if !role.Can(auth.Role, role.EditBoard) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Only team admins can edit boards.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PutReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate request
+	if err := h.boardIDValidator.Validate(req.BoardID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Board ID cannot be empty."
+		} else if errors.Is(err, validator.ErrWrongFormat) {
+			msg = "Board ID must be a UUID."
+		}
+
+		if err := json.NewEncoder(w).Encode(PutResp{Error: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team and find the board
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	board, ok := findBoard(team, req.BoardID)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// reorder the board's columns based on the given IDs, which must contain
+	// each of the board's column IDs exactly once
+	cols := board.ColumnsOrDefault()
+	newCols := make([]teamtbl.Column, 0, len(cols))
+	for _, id := range req.ColumnIDs {
+		i := findColumn(cols, id)
+		if i == -1 || findColumn(newCols, id) != -1 {
+			break
+		}
+		newCols = append(newCols, cols[i])
+	}
+	if len(req.ColumnIDs) != len(cols) || len(newCols) != len(cols) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Column IDs must contain each of the board's columns " +
+				"exactly once.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	board.Columns = newCols
+	if err = h.boardUpdater.Update(
+		r.Context(), auth.TeamID, board,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,272 @@
+//go:build utest
+
+package columnapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPutHandler tests the Handle method of PutHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPutHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	boardIDValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	boardUpdater := &db.FakeUpdaterDualKey[teamtbl.Board]{}
+	log := &log.FakeErrorer{}
+	sut := NewPutHandler(
+		authDecoder, boardIDValidator, teamRetriever, boardUpdater, log,
+	)
+
+	team := teamtbl.Team{Boards: []teamtbl.Board{{ID: "boardid"}}}
+	admin := cookie.Auth{Role: role.Admin}
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		authDecoded   cookie.Auth
+		errValidateID error
+		columnIDs     string
+		team          teamtbl.Team
+		errRetrieve   error
+		errUpdate     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			errValidateID: nil,
+			columnIDs:     "[]",
+			team:          teamtbl.Team{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			authDecoded:   cookie.Auth{},
+			errValidateID: nil,
+			columnIDs:     "[]",
+			team:          teamtbl.Team{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotAdmin",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Member},
+			errValidateID: nil,
+			columnIDs:     "[]",
+			team:          teamtbl.Team{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit boards.",
+			),
+		},
+		{
+			name:          "BoardIDEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: validator.ErrEmpty,
+			columnIDs:     "[]",
+			team:          teamtbl.Team{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Board ID cannot be empty."),
+		},
+		{
+			name:          "BoardIDNotUUID",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: validator.ErrWrongFormat,
+			columnIDs:     "[]",
+			team:          teamtbl.Team{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Board ID must be a UUID."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox", "ready"]`,
+			team:          teamtbl.Team{},
+			errRetrieve:   errors.New("retrieve failed"),
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "BoardNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox", "ready"]`,
+			team:          teamtbl.Team{},
+			errRetrieve:   db.ErrNoItem,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "ColumnMissing",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox"]`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column IDs must contain each of the board's columns " +
+					"exactly once.",
+			),
+		},
+		{
+			name:          "ColumnNotOnBoard",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox", "review"]`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column IDs must contain each of the board's columns " +
+					"exactly once.",
+			),
+		},
+		{
+			name:          "ColumnDuplicate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox", "inbox"]`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column IDs must contain each of the board's columns " +
+					"exactly once.",
+			),
+		},
+		{
+			name:          "ColumnExtra",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox", "ready", "ready"]`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Column IDs must contain each of the board's columns " +
+					"exactly once.",
+			),
+		},
+		{
+			name:          "BoardDeleted",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox", "ready"]`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox", "ready"]`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     errors.New("update failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			errValidateID: nil,
+			columnIDs:     `["go", "done", "inbox", "ready"]`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			authDecoder.Res = c.authDecoded
+			boardIDValidator.Err = c.errValidateID
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			boardUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(
+				`{"boardID": "boardid", "columnIDs": `+c.columnIDs+`}`,
+			))
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  cookie.AuthName,
+					Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package columnapi
+
+import (
+	"regexp"
+
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// NameValidator can be used to validate a column name.
+type NameValidator struct{}
+
+// NewNameValidator creates and returns a new NameValidator.
+func NewNameValidator() NameValidator { return NameValidator{} }
+
+// Validate validates a given column name.
+func (v NameValidator) Validate(name string) error {
+	if name == "" {
+		return validator.ErrEmpty
+	}
+	if len(name) > 25 {
+		return validator.ErrTooLong
+	}
+	return nil
+}
+
+// ColorValidator can be used to validate a column color.
+type ColorValidator struct{ re *regexp.Regexp }
+
+// NewColorValidator creates and returns a new ColorValidator.
+func NewColorValidator() ColorValidator {
+	return ColorValidator{re: regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)}
+}
+
+// Validate validates a given column color, which is either empty for the
+// default color or a hex color code such as #1a2b3c.
+func (v ColorValidator) Validate(color string) error {
+	if color != "" && !v.re.MatchString(color) {
+		return validator.ErrWrongFormat
+	}
+	return nil
+}
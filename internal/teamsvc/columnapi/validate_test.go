@@ -0,0 +1,87 @@
+//go:build utest
+
+package columnapi
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+func TestNameValidator(t *testing.T) {
+	sut := NewNameValidator()
+
+	for _, c := range []struct {
+		name    string
+		colName string
+		wantErr error
+	}{
+		{
+			name:    "Empty",
+			colName: "",
+			wantErr: validator.ErrEmpty,
+		},
+		{
+			name:    "MaxLength",
+			colName: "columnycolumnsycolumnkyco",
+			wantErr: nil,
+		},
+		{
+			name:    "TooLong",
+			colName: "columnycolumnsycolumnkycol",
+			wantErr: validator.ErrTooLong,
+		},
+		{
+			name:    "OK",
+			colName: "In Review",
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := sut.Validate(c.colName)
+			assert.ErrIs(t.Error, err, c.wantErr)
+		})
+	}
+}
+
+func TestColorValidator(t *testing.T) {
+	sut := NewColorValidator()
+
+	for _, c := range []struct {
+		name    string
+		color   string
+		wantErr error
+	}{
+		{
+			name:    "Empty",
+			color:   "",
+			wantErr: nil,
+		},
+		{
+			name:    "NoHash",
+			color:   "1a2b3c",
+			wantErr: validator.ErrWrongFormat,
+		},
+		{
+			name:    "TooShort",
+			color:   "#fff",
+			wantErr: validator.ErrWrongFormat,
+		},
+		{
+			name:    "NotHex",
+			color:   "#1a2b3g",
+			wantErr: validator.ErrWrongFormat,
+		},
+		{
+			name:    "OK",
+			color:   "#1A2b3c",
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := sut.Validate(c.color)
+			assert.ErrIs(t.Error, err, c.wantErr)
+		})
+	}
+}
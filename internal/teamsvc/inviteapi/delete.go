@@ -0,0 +1,118 @@
+package inviteapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// DeleteResp defines the body of DELETE invites responses.
+type DeleteResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteHandler is an api.MethodHandler that can be used to handle DELETE
+// invites requests, which are used for revoking a team invite.
+type DeleteHandler struct {
+	authDecoder   cookie.Decoder[cookie.Auth]
+	inviteDeleter db.DeleterDualKey
+	log           log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	inviteDeleter db.DeleterDualKey,
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		authDecoder:   authDecoder,
+		inviteDeleter: inviteDeleter,
+		log:           log,
+	}
+}
+
+// Handle handles the DELETE requests sent to the invites route.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can revoke invites
+	if !role.Can(auth.Role, role.DeleteInvite) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Only team admins can revoke invites.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate invite ID
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Invite ID cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// delete the invite so that it can't be used anymore - only the admin's
+	// own team's invites can be deleted
+	err = h.inviteDeleter.Delete(r.Context(), auth.TeamID, id)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Invite not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
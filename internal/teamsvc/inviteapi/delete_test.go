@@ -0,0 +1,130 @@
+//go:build utest
+
+package inviteapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	inviteDeleter := &db.FakeDeleterDualKey{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(authDecoder, inviteDeleter, log)
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		authDecoded   cookie.Auth
+		errDecodeAuth error
+		inviteID      string
+		errDelete     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			authDecoded:   cookie.Auth{},
+			errDecodeAuth: nil,
+			inviteID:      "",
+			errDelete:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{},
+			errDecodeAuth: cookie.ErrInvalid,
+			inviteID:      "",
+			errDelete:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotAdmin",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Member},
+			errDecodeAuth: nil,
+			inviteID:      "",
+			errDelete:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can revoke invites.",
+			),
+		},
+		{
+			name:          "IDEmpty",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			inviteID:      "",
+			errDelete:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Invite ID cannot be empty."),
+		},
+		{
+			name:          "NotFound",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			inviteID:      "inviteid",
+			errDelete:     db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Invite not found."),
+		},
+		{
+			name:          "ErrDelete",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			inviteID:      "inviteid",
+			errDelete:     errors.New("delete failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("delete failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			inviteID:      "inviteid",
+			errDelete:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecodeAuth
+			inviteDeleter.Err = c.errDelete
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodDelete, "/?id="+c.inviteID, nil,
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package inviteapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// GetResp defines the body of GET invites responses.
+type GetResp struct {
+	Invites []Invite `json:"invites"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Invite defines a team invite in GET invites responses.
+type Invite struct {
+	ID        string    `json:"id"`
+	CreatedBy string    `json:"createdBy"`
+	Username  string    `json:"username,omitempty"`
+	Role      role.Role `json:"role,omitempty"`
+	MaxUses   int       `json:"maxUses"`
+	Uses      int       `json:"uses"`
+	CreatedAt int64     `json:"createdAt"`
+	ExpiresAt int64     `json:"expiresAt"`
+}
+
+// GetHandler is an api.MethodHandler that can be used to handle GET invites
+// requests, which are used for listing a team's pending invites.
+type GetHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	retrieverByTeam db.Retriever[[]invitetbl.Invite]
+	log             log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	retrieverByTeam db.Retriever[[]invitetbl.Invite],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		authDecoder:     authDecoder,
+		retrieverByTeam: retrieverByTeam,
+		log:             log,
+	}
+}
+
+// Handle handles the GET requests sent to the invites route.
+func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can view invites
+	if !role.Can(auth.Role, role.ViewInvites) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Only team admins can view invites.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team's invites
+	invites, err := h.retrieverByTeam.Retrieve(r.Context(), auth.TeamID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// respond with the invites that can still be used - used up and expired
+	// invites are kept in the table until their TTL passes so that admins can
+	// track them, but they are filtered out here
+	resp := GetResp{Invites: []Invite{}}
+	now := time.Now().Unix()
+	for _, inv := range invites {
+		if !inv.IsPending(now) {
+			continue
+		}
+		resp.Invites = append(resp.Invites, Invite{
+			ID:        inv.ID,
+			CreatedBy: inv.CreatedBy,
+			Username:  inv.Username,
+			Role:      inv.Role,
+			MaxUses:   inv.MaxUses,
+			Uses:      inv.Uses,
+			CreatedAt: inv.CreatedAt,
+			ExpiresAt: inv.ExpiresAt,
+		})
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
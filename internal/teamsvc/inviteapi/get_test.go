@@ -0,0 +1,155 @@
+//go:build utest
+
+package inviteapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	retrieverByTeam := &db.FakeRetriever[[]invitetbl.Invite]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(authDecoder, retrieverByTeam, log)
+
+	future := time.Now().Add(time.Hour).Unix()
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		authDecoded   cookie.Auth
+		errDecodeAuth error
+		invites       []invitetbl.Invite
+		errRetrieve   error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			authDecoded:   cookie.Auth{},
+			errDecodeAuth: nil,
+			invites:       nil,
+			errRetrieve:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{},
+			errDecodeAuth: cookie.ErrInvalid,
+			invites:       nil,
+			errRetrieve:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotAdmin",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Member},
+			errDecodeAuth: nil,
+			invites:       nil,
+			errRetrieve:   nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can view invites.",
+			),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			invites:       nil,
+			errRetrieve:   errors.New("retrieve failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			invites: []invitetbl.Invite{
+				{
+					ID:        "pending",
+					CreatedBy: "bob123",
+					Username:  "alice",
+					Role:      role.Viewer,
+					MaxUses:   2,
+					Uses:      1,
+					CreatedAt: 1700000000,
+					ExpiresAt: future,
+				},
+				{
+					ID:        "usedup",
+					CreatedBy: "bob123",
+					MaxUses:   1,
+					Uses:      1,
+					CreatedAt: 1700000000,
+					ExpiresAt: future,
+				},
+				{
+					ID:        "expired",
+					CreatedBy: "bob123",
+					MaxUses:   1,
+					Uses:      0,
+					CreatedAt: 1700000000,
+					ExpiresAt: 1700000001,
+				},
+			},
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody GetResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Fatal, len(respBody.Invites), 1)
+				inv := respBody.Invites[0]
+				assert.Equal(t.Error, inv.ID, "pending")
+				assert.Equal(t.Error, inv.CreatedBy, "bob123")
+				assert.Equal(t.Error, inv.Username, "alice")
+				assert.Equal(t.Error, inv.Role, role.Viewer)
+				assert.Equal(t.Error, inv.MaxUses, 2)
+				assert.Equal(t.Error, inv.Uses, 1)
+				assert.Equal(t.Error, inv.CreatedAt, int64(1700000000))
+				assert.Equal(t.Error, inv.ExpiresAt, future)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecodeAuth
+			retrieverByTeam.Res = c.invites
+			retrieverByTeam.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package inviteapi contains code for responding to HTTP requests made to the
+// invites API route, which is used by team admins for creating, listing, and
+// revoking the invites that other users can register into their team with.
+package inviteapi
@@ -0,0 +1,185 @@
+package inviteapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PostReq defines the body of POST invites requests. Username is optional and
+// restricts the invite to the user who registers with that username. Role is
+// also optional and defaults to member.
+type PostReq struct {
+	Username       string    `json:"username"`
+	Role           role.Role `json:"role"`
+	MaxUses        int       `json:"maxUses"`
+	ExpiresInHours int       `json:"expiresInHours"`
+}
+
+// PostResp defines the body of POST invites responses.
+type PostResp struct {
+	ID        string    `json:"id,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	Role      role.Role `json:"role,omitempty"`
+	MaxUses   int       `json:"maxUses,omitempty"`
+	ExpiresAt int64     `json:"expiresAt,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can be used to handle POST invites
+// requests, which are used for creating a team invite.
+type PostHandler struct {
+	authDecoder    cookie.Decoder[cookie.Auth]
+	validateReq    validator.Func[PostReq]
+	inviteInserter db.Inserter[invitetbl.Invite]
+	log            log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	validateReq validator.Func[PostReq],
+	inviteInserter db.Inserter[invitetbl.Invite],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		authDecoder:    authDecoder,
+		validateReq:    validateReq,
+		inviteInserter: inviteInserter,
+		log:            log,
+	}
+}
+
+// Handle handles the POST requests sent to the invites route.
+func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can invite users
+	if !role.Can(auth.Role, role.CreateInvite) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Only team admins can invite users.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate request
+	if err := h.validateReq(req); err != nil {
+		var msg string
+		switch {
+		case errors.Is(err, errUsernameTooLong):
+			msg = "Username cannot be longer than 15 characters."
+		case errors.Is(err, errMaxUsesOutOfBounds):
+			msg = "Invites must be usable 1 to 100 times."
+		case errors.Is(err, errExpiryOutOfBounds):
+			msg = "Invites must expire in 1 to 168 hours."
+		case errors.Is(err, errRoleInvalid):
+			msg = "Invites can only grant admin, member, or viewer role."
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		if err = json.NewEncoder(w).Encode(PostResp{Error: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if req.Role == "" {
+		req.Role = role.Member
+	}
+
+	// insert the invite into the invite table - retry up to 3 times for the
+	// unlikely event that the generated UUID is a duplicate
+	var (
+		id  string
+		now = time.Now()
+		exp = now.Add(time.Duration(req.ExpiresInHours) * time.Hour).Unix()
+	)
+	for i := 0; i < 3; i++ {
+		id = uuid.NewString()
+		if err = h.inviteInserter.Insert(r.Context(), invitetbl.NewInvite(
+			id,
+			auth.TeamID,
+			auth.Username,
+			req.Username,
+			req.Role,
+			req.MaxUses,
+			now.Unix(),
+			exp,
+		)); !errors.Is(err, db.ErrDupKey) {
+			break
+		}
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// respond with the invite for the admin to share
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(PostResp{
+		ID:        id,
+		Username:  req.Username,
+		Role:      req.Role,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: exp,
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,188 @@
+//go:build utest
+
+package inviteapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	var validateErr error
+	validateReq := func(PostReq) error { return validateErr }
+	inviteInserter := &db.FakeInserter[invitetbl.Invite]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(authDecoder, validateReq, inviteInserter, log)
+
+	reqBody := `{"username": "alice", "maxUses": 1, "expiresInHours": 24}`
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		authDecoded   cookie.Auth
+		errDecodeAuth error
+		errValidate   error
+		errInsert     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			authDecoded:   cookie.Auth{},
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{},
+			errDecodeAuth: cookie.ErrInvalid,
+			errValidate:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotAdmin",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Member},
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can invite users.",
+			),
+		},
+		{
+			name:          "UsernameTooLong",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			errValidate:   errUsernameTooLong,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Username cannot be longer than 15 characters.",
+			),
+		},
+		{
+			name:          "MaxUsesOutOfBounds",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			errValidate:   errMaxUsesOutOfBounds,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Invites must be usable 1 to 100 times.",
+			),
+		},
+		{
+			name:          "ExpiryOutOfBounds",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			errValidate:   errExpiryOutOfBounds,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Invites must expire in 1 to 168 hours.",
+			),
+		},
+		{
+			name:          "RoleInvalid",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			errValidate:   errRoleInvalid,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Invites can only grant admin, member, or viewer role.",
+			),
+		},
+		{
+			name:          "ErrValidate",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			errValidate:   errors.New("validate failed"),
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("validate failed"),
+		},
+		{
+			name:          "ErrInsert",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			errInsert:     errors.New("insert failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("insert failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusCreated,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody PostResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+				_, err = uuid.Parse(respBody.ID)
+				assert.Nil(t.Error, err)
+				assert.Equal(t.Error, respBody.Username, "alice")
+				assert.Equal(t.Error, respBody.Role, role.Member)
+				assert.Equal(t.Error, respBody.MaxUses, 1)
+				assert.True(t.Error, respBody.ExpiresAt > 0)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecodeAuth
+			validateErr = c.errValidate
+			inviteInserter.Err = c.errInsert
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
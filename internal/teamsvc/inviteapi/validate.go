@@ -0,0 +1,44 @@
+package inviteapi
+
+import (
+	"errors"
+
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// ValidatePostReq validates a given PostReq.
+func ValidatePostReq(req PostReq) error {
+	if len([]rune(req.Username)) > 15 {
+		return errUsernameTooLong
+	}
+	if req.MaxUses < 1 || req.MaxUses > 100 {
+		return errMaxUsesOutOfBounds
+	}
+	if req.ExpiresInHours < 1 || req.ExpiresInHours > 168 {
+		return errExpiryOutOfBounds
+	}
+	switch req.Role {
+	case "", role.Admin, role.Member, role.Viewer:
+	default:
+		return errRoleInvalid
+	}
+	return nil
+}
+
+var (
+	// errUsernameTooLong is returned when an invite's target username is too
+	// long to belong to any user.
+	errUsernameTooLong = errors.New("username is too long")
+
+	// errMaxUsesOutOfBounds is returned when an invite's maximum uses is out of
+	// bounds.
+	errMaxUsesOutOfBounds = errors.New("max uses is out of bounds")
+
+	// errExpiryOutOfBounds is returned when an invite's expiry is out of
+	// bounds.
+	errExpiryOutOfBounds = errors.New("expiry is out of bounds")
+
+	// errRoleInvalid is returned when an invite's role is not one that can be
+	// granted by an invite, which excludes the owner role.
+	errRoleInvalid = errors.New("role is invalid")
+)
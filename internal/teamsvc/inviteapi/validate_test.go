@@ -0,0 +1,86 @@
+//go:build utest
+
+package inviteapi
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestValidatePostReq tests the ValidatePostReq function to assert that it
+// returns the correct error based on the PostReq input.
+func TestValidatePostReq(t *testing.T) {
+	sut := ValidatePostReq
+
+	for _, c := range []struct {
+		name    string
+		req     PostReq
+		wantErr error
+	}{
+		{
+			name:    "UsernameTooLong",
+			req:     PostReq{Username: "abcdefghijklmnop"},
+			wantErr: errUsernameTooLong,
+		},
+		{
+			name:    "MaxUsesTooSmall",
+			req:     PostReq{MaxUses: 0},
+			wantErr: errMaxUsesOutOfBounds,
+		},
+		{
+			name:    "MaxUsesTooBig",
+			req:     PostReq{MaxUses: 101},
+			wantErr: errMaxUsesOutOfBounds,
+		},
+		{
+			name:    "ExpiryTooSmall",
+			req:     PostReq{MaxUses: 1, ExpiresInHours: 0},
+			wantErr: errExpiryOutOfBounds,
+		},
+		{
+			name:    "ExpiryTooBig",
+			req:     PostReq{MaxUses: 1, ExpiresInHours: 169},
+			wantErr: errExpiryOutOfBounds,
+		},
+		{
+			name: "RoleOwner",
+			req: PostReq{
+				MaxUses: 1, ExpiresInHours: 1, Role: role.Owner,
+			},
+			wantErr: errRoleInvalid,
+		},
+		{
+			name: "RoleUnknown",
+			req: PostReq{
+				MaxUses: 1, ExpiresInHours: 1, Role: "superuser",
+			},
+			wantErr: errRoleInvalid,
+		},
+		{
+			name:    "OK",
+			req:     PostReq{MaxUses: 100, ExpiresInHours: 168},
+			wantErr: nil,
+		},
+		{
+			name: "OKWithUsername",
+			req: PostReq{
+				Username: "bob123", MaxUses: 1, ExpiresInHours: 1,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "OKWithRole",
+			req: PostReq{
+				MaxUses: 1, ExpiresInHours: 1, Role: role.Viewer,
+			},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := sut(c.req)
+			assert.ErrIs(t.Error, err, c.wantErr)
+		})
+	}
+}
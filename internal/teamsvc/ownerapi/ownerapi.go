@@ -0,0 +1,4 @@
+// Package ownerapi contains code for responding to HTTP requests made to the
+// team owner API route, which is used by team owners for transferring the
+// ownership of their team to another one of its members.
+package ownerapi
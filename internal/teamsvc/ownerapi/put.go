@@ -0,0 +1,189 @@
+package ownerapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// PutReq defines the body of PUT owner requests.
+type PutReq struct {
+	Username string `json:"username"`
+}
+
+// PutResp defines the body of PUT owner responses.
+type PutResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PutHandler is an api.MethodHandler that can be used to handle PUT owner
+// requests, which are used for transferring the team's ownership to another
+// member. The previous owner becomes an admin, and both users get their new
+// role in their auth token the next time it is refreshed.
+type PutHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	teamRetriever   db.Retriever[teamtbl.Team]
+	ownerTransferer db.Transferer
+	log             log.Errorer
+}
+
+// NewPutHandler creates and returns a new PutHandler.
+func NewPutHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	teamRetriever db.Retriever[teamtbl.Team],
+	ownerTransferer db.Transferer,
+	log log.Errorer,
+) PutHandler {
+	return PutHandler{
+		authDecoder:     authDecoder,
+		teamRetriever:   teamRetriever,
+		ownerTransferer: ownerTransferer,
+		log:             log,
+	}
+}
+
+// Handle handles PUT requests sent to the owner route.
+func (h PutHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user is owner
+	if !role.Can(auth.Role, role.TransferTeam) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Only the team owner can transfer ownership.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode and validate request
+	var req PutReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if req.Username == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Username cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if req.Username == auth.Username {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "You already own the team.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team and check that the user still owns it and that the
+	// new owner is a member of it - the auth token might be outdated if the
+	// user has transferred the ownership since it was issued
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Team not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if team.Owner != auth.Username {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Only the team owner can transfer ownership.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	var isMember bool
+	for _, m := range team.Members {
+		if m == req.Username {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Member not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// transfer the ownership
+	if err = h.ownerTransferer.Transfer(
+		r.Context(), team.ID, auth.Username, req.Username,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Member not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,218 @@
+//go:build utest
+
+package ownerapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestPutHandler tests the Handle method of PutHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPutHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	ownerTransferer := &db.FakeTransferer{}
+	log := &log.FakeErrorer{}
+	sut := NewPutHandler(authDecoder, teamRetriever, ownerTransferer, log)
+
+	owner := cookie.Auth{Username: "bob123", Role: role.Owner, TeamID: "teamid"}
+	team := teamtbl.Team{
+		ID: "teamid", Owner: "bob123", Members: []string{"bob123", "bob124"},
+	}
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		authDecoded   cookie.Auth
+		reqBody       string
+		team          teamtbl.Team
+		errRetrieve   error
+		errTransfer   error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			reqBody:       `{"username": "bob124"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			authDecoded:   cookie.Auth{},
+			reqBody:       `{"username": "bob124"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotOwner",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			reqBody:       `{"username": "bob124"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only the team owner can transfer ownership.",
+			),
+		},
+		{
+			name:          "UsernameEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": ""}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Username cannot be empty."),
+		},
+		{
+			name:          "TransferToSelf",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob123"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("You already own the team."),
+		},
+		{
+			name:          "TeamNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob124"}`,
+			team:          teamtbl.Team{},
+			errRetrieve:   db.ErrNoItem,
+			errTransfer:   nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Team not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob124"}`,
+			team:          teamtbl.Team{},
+			errRetrieve:   errors.New("retrieve failed"),
+			errTransfer:   nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "NoLongerOwner",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob124"}`,
+			team:          teamtbl.Team{Owner: "bob124", Members: team.Members},
+			errRetrieve:   nil,
+			errTransfer:   nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only the team owner can transfer ownership.",
+			),
+		},
+		{
+			name:          "MemberNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob125"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Member not found."),
+		},
+		{
+			name:          "TransferNoItem",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob124"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Member not found."),
+		},
+		{
+			name:          "ErrTransfer",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob124"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   errors.New("transfer failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("transfer failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob124"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errTransfer:   nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecodeAuth
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			ownerTransferer.Err = c.errTransfer
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPut, "/team/owner", strings.NewReader(c.reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
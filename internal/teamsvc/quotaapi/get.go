@@ -0,0 +1,160 @@
+package quotaapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// GetResp defines the body of GET quota responses.
+type GetResp struct {
+	Limits quota.Limits `json:"limits"`
+	Usage  Usage        `json:"usage"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// Usage defines how much of each resource a team is using in GET quota
+// responses. TasksPerBoard maps each board's ID to its number of tasks, and
+// SubtasksPerTask is the number of subtasks of the task that has the most.
+type Usage struct {
+	Boards          int            `json:"boards"`
+	TasksPerBoard   map[string]int `json:"tasksPerBoard"`
+	Members         int            `json:"members"`
+	SubtasksPerTask int            `json:"subtasksPerTask"`
+}
+
+// GetHandler is an api.MethodHandler that can be used to handle GET quota
+// requests.
+type GetHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	teamRetriever   db.Retriever[teamtbl.Team]
+	memberRetriever db.Retriever[[]membershiptbl.Membership]
+	taskRetriever   db.Retriever[[]tasktbl.Task]
+	limits          quota.Limits
+	log             log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	teamRetriever db.Retriever[teamtbl.Team],
+	memberRetriever db.Retriever[[]membershiptbl.Membership],
+	taskRetriever db.Retriever[[]tasktbl.Task],
+	limits quota.Limits,
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		authDecoder:     authDecoder,
+		teamRetriever:   teamRetriever,
+		memberRetriever: memberRetriever,
+		taskRetriever:   taskRetriever,
+		limits:          limits,
+		log:             log,
+	}
+}
+
+// Handle handles the GET requests sent to the quota route.
+func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can view quotas
+	if !role.Can(auth.Role, role.ViewQuotas) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Only team admins can view quotas.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team - it may not have been created yet, in which case it
+	// has no boards and no limits are overridden
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// retrieve the team's memberships and tasks to count them
+	members, err := h.memberRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	tasks, err := h.taskRetriever.Retrieve(r.Context(), auth.TeamID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// count the tasks of each of the team's boards - tasks of boards that are
+	// no longer on the team don't count towards any quota so are skipped, and
+	// neither do archived boards count towards the board quota
+	usage := Usage{
+		Boards:        team.ActiveBoards(),
+		TasksPerBoard: make(map[string]int, len(team.Boards)),
+		Members:       len(members),
+	}
+	for _, b := range team.Boards {
+		usage.TasksPerBoard[b.ID] = 0
+	}
+	for _, t := range tasks {
+		if _, ok := usage.TasksPerBoard[t.BoardID]; !ok {
+			continue
+		}
+		usage.TasksPerBoard[t.BoardID]++
+		if len(t.Subtasks) > usage.SubtasksPerTask {
+			usage.SubtasksPerTask = len(t.Subtasks)
+		}
+	}
+
+	if err = json.NewEncoder(w).Encode(GetResp{
+		Limits: h.limits.Override(team.Quota), Usage: usage,
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
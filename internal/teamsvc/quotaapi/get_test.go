@@ -0,0 +1,241 @@
+//go:build utest
+
+package quotaapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	memberRetriever := &db.FakeRetriever[[]membershiptbl.Membership]{}
+	taskRetriever := &db.FakeRetriever[[]tasktbl.Task]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(
+		authDecoder,
+		teamRetriever,
+		memberRetriever,
+		taskRetriever,
+		quota.Limits{
+			Boards:          3,
+			TasksPerBoard:   quota.Unlimited,
+			Members:         10,
+			SubtasksPerTask: 5,
+		},
+		log,
+	)
+
+	for _, c := range []struct {
+		name             string
+		authToken        string
+		authDecoded      cookie.Auth
+		errDecodeAuth    error
+		team             teamtbl.Team
+		errRetrieveTeam  error
+		members          []membershiptbl.Membership
+		errRetrieveMembs error
+		tasks            []tasktbl.Task
+		errRetrieveTasks error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:             "NoAuth",
+			authToken:        "",
+			authDecoded:      cookie.Auth{},
+			errDecodeAuth:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			wantStatus:       http.StatusUnauthorized,
+			assertFunc:       assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:             "InvalidAuth",
+			authToken:        "nonempty",
+			authDecoded:      cookie.Auth{},
+			errDecodeAuth:    cookie.ErrInvalid,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			wantStatus:       http.StatusUnauthorized,
+			assertFunc:       assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:             "NotAdmin",
+			authToken:        "nonempty",
+			authDecoded:      cookie.Auth{Role: role.Member},
+			errDecodeAuth:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			wantStatus:       http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can view quotas.",
+			),
+		},
+		{
+			name:             "ErrRetrieveTeam",
+			authToken:        "nonempty",
+			authDecoded:      cookie.Auth{Role: role.Admin},
+			errDecodeAuth:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  errors.New("retrieve team failed"),
+			members:          nil,
+			errRetrieveMembs: nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:             "ErrRetrieveMembers",
+			authToken:        "nonempty",
+			authDecoded:      cookie.Auth{Role: role.Admin},
+			errDecodeAuth:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: errors.New("retrieve members failed"),
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve members failed"),
+		},
+		{
+			name:             "ErrRetrieveTasks",
+			authToken:        "nonempty",
+			authDecoded:      cookie.Auth{Role: role.Admin},
+			errDecodeAuth:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			tasks:            nil,
+			errRetrieveTasks: errors.New("retrieve tasks failed"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:             "TeamNotFound",
+			authToken:        "nonempty",
+			authDecoded:      cookie.Auth{Role: role.Admin},
+			errDecodeAuth:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  db.ErrNoItem,
+			members:          make([]membershiptbl.Membership, 1),
+			errRetrieveMembs: nil,
+			tasks:            nil,
+			errRetrieveTasks: nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody GetResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+
+				assert.Equal(t.Error, respBody.Limits, quota.Limits{
+					Boards:          3,
+					TasksPerBoard:   quota.Unlimited,
+					Members:         10,
+					SubtasksPerTask: 5,
+				})
+				assert.Equal(t.Error, respBody.Usage.Boards, 0)
+				assert.Equal(t.Error, len(respBody.Usage.TasksPerBoard), 0)
+				assert.Equal(t.Error, respBody.Usage.Members, 1)
+				assert.Equal(t.Error, respBody.Usage.SubtasksPerTask, 0)
+			},
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errDecodeAuth: nil,
+			team: teamtbl.Team{
+				Boards: []teamtbl.Board{
+					{ID: "board1"},
+					{ID: "board2"},
+					{ID: "board3", Archived: true},
+				},
+				Quota: quota.Limits{Boards: 5, TasksPerBoard: 20},
+			},
+			errRetrieveTeam:  nil,
+			members:          make([]membershiptbl.Membership, 4),
+			errRetrieveMembs: nil,
+			tasks: []tasktbl.Task{
+				{BoardID: "board1", Subtasks: make([]tasktbl.Subtask, 2)},
+				{BoardID: "board1", Subtasks: make([]tasktbl.Subtask, 1)},
+				{BoardID: "deletedboard", Subtasks: make([]tasktbl.Subtask, 9)},
+			},
+			errRetrieveTasks: nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody GetResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+
+				assert.Equal(t.Error, respBody.Limits, quota.Limits{
+					Boards:          5,
+					TasksPerBoard:   20,
+					Members:         10,
+					SubtasksPerTask: 5,
+				})
+				assert.Equal(t.Error, respBody.Usage.Boards, 2)
+				assert.Equal(t.Error, len(respBody.Usage.TasksPerBoard), 3)
+				assert.Equal(t.Error, respBody.Usage.TasksPerBoard["board1"], 2)
+				assert.Equal(t.Error, respBody.Usage.TasksPerBoard["board2"], 0)
+				assert.Equal(t.Error, respBody.Usage.Members, 4)
+				assert.Equal(t.Error, respBody.Usage.SubtasksPerTask, 2)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecodeAuth
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			memberRetriever.Res = c.members
+			memberRetriever.Err = c.errRetrieveMembs
+			taskRetriever.Res = c.tasks
+			taskRetriever.Err = c.errRetrieveTasks
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
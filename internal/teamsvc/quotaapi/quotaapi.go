@@ -0,0 +1,4 @@
+// Package quotaapi contains code for responding to HTTP requests made to the
+// team quota API route, which is used by team admins for checking how much of
+// each resource their team is using against the limits that apply to it.
+package quotaapi
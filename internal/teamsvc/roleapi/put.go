@@ -0,0 +1,190 @@
+package roleapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// PutReq defines the body of PUT role requests.
+type PutReq struct {
+	Username string    `json:"username"`
+	Role     role.Role `json:"role"`
+}
+
+// PutResp defines the body of PUT role responses.
+type PutResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PutHandler is an api.MethodHandler that can be used to handle PUT role
+// requests, which are used for changing the role of a member of the team. The
+// owner's role can only be changed by transferring the ownership, so the owner
+// role can neither be given nor taken away here. The member gets their new role
+// in their auth token the next time it is refreshed.
+type PutHandler struct {
+	authDecoder   cookie.Decoder[cookie.Auth]
+	teamRetriever db.Retriever[teamtbl.Team]
+	memberUpdater db.Updater[membershiptbl.Membership]
+	log           log.Errorer
+}
+
+// NewPutHandler creates and returns a new PutHandler.
+func NewPutHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	teamRetriever db.Retriever[teamtbl.Team],
+	memberUpdater db.Updater[membershiptbl.Membership],
+	log log.Errorer,
+) PutHandler {
+	return PutHandler{
+		authDecoder:   authDecoder,
+		teamRetriever: teamRetriever,
+		memberUpdater: memberUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles PUT requests sent to the role route.
+func (h PutHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can change roles
+	if !role.Can(auth.Role, role.ChangeRole) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Only the team owner can change roles.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode and validate request
+	var req PutReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	var errMsg string
+	if req.Username == "" {
+		errMsg = "Username cannot be empty."
+	} else if req.Username == auth.Username {
+		errMsg = "You cannot change your own role."
+	} else if !req.Role.IsValid() || req.Role == role.Owner {
+		errMsg = "Role must be admin, member, or viewer."
+	}
+	if errMsg != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: errMsg,
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team and check that the user still owns it and that the
+	// member is a member of it - the auth token might be outdated if the user
+	// has transferred the ownership since it was issued
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Team not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if team.Owner != auth.Username {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Only the team owner can change roles.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	var isMember bool
+	for _, m := range team.Members {
+		if m == req.Username {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Member not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// change the role
+	if err = h.memberUpdater.Update(r.Context(), membershiptbl.NewMembership(
+		req.Username, team.ID, req.Role,
+	)); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Member not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,250 @@
+//go:build utest
+
+package roleapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestPutHandler tests the Handle method of PutHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPutHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	memberUpdater := &db.FakeUpdater[membershiptbl.Membership]{}
+	log := &log.FakeErrorer{}
+	sut := NewPutHandler(authDecoder, teamRetriever, memberUpdater, log)
+
+	owner := cookie.Auth{Username: "bob123", Role: role.Owner, TeamID: "teamid"}
+	team := teamtbl.Team{
+		ID: "teamid", Owner: "bob123", Members: []string{"bob123", "bob124"},
+	}
+	okBody := `{"username": "bob124", "role": "admin"}`
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		authDecoded   cookie.Auth
+		reqBody       string
+		team          teamtbl.Team
+		errRetrieve   error
+		errUpdate     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			reqBody:       okBody,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			authDecoded:   cookie.Auth{},
+			reqBody:       okBody,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotOwner",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			reqBody:       okBody,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only the team owner can change roles.",
+			),
+		},
+		{
+			name:          "UsernameEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "", "role": "admin"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Username cannot be empty."),
+		},
+		{
+			name:          "OwnRole",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob123", "role": "admin"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"You cannot change your own role.",
+			),
+		},
+		{
+			name:          "RoleInvalid",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob124", "role": "superuser"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Role must be admin, member, or viewer.",
+			),
+		},
+		{
+			name:          "RoleOwner",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob124", "role": "owner"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Role must be admin, member, or viewer.",
+			),
+		},
+		{
+			name:          "TeamNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       okBody,
+			team:          teamtbl.Team{},
+			errRetrieve:   db.ErrNoItem,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Team not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       okBody,
+			team:          teamtbl.Team{},
+			errRetrieve:   errors.New("retrieve failed"),
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "NoLongerOwner",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       okBody,
+			team:          teamtbl.Team{Owner: "bob124", Members: team.Members},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only the team owner can change roles.",
+			),
+		},
+		{
+			name:          "MemberNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       `{"username": "bob125", "role": "admin"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Member not found."),
+		},
+		{
+			name:          "UpdateNoItem",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       okBody,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Member not found."),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       okBody,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     errors.New("update failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   owner,
+			reqBody:       okBody,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecodeAuth
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			memberUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPut, "/team/role", strings.NewReader(c.reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
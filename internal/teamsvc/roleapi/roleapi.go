@@ -0,0 +1,4 @@
+// Package roleapi contains code for responding to HTTP requests made to the
+// team role API route, which is used by team owners for changing the roles of
+// the other members of their team.
+package roleapi
@@ -9,22 +9,62 @@ import (
 
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 // GetResp defines the body of GET team responses.
-type GetResp teamtbl.Team
+type GetResp struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Owner      string   `json:"owner"`
+	Members    []Member `json:"members"`
+	Boards     []Board  `json:"boards"`
+	RequireMFA bool     `json:"requireMFA"`
+}
+
+// Member defines the summary of a team member's profile in GET team responses.
+type Member struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+	AvatarURL   string `json:"avatarURL"`
+}
+
+// Board defines a board of the team in GET team responses. Archived boards are
+// only included if the request's includeArchived query parameter is "true".
+type Board struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Members  []string `json:"members"`
+	Columns  []Column `json:"columns"`
+	Archived bool     `json:"archived"`
+}
+
+// Column defines a column of a board in GET team responses. Count is the
+// number of tasks in the column, which is displayed next to its WIP limit.
+type Column struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	WIPLimit int    `json:"wipLimit"`
+	Count    int    `json:"count"`
+}
 
 // GetHandler is an api.MethodHandler that can handle GET requests sent to the
 // team route.
 type GetHandler struct {
-	authDecoder   cookie.Decoder[cookie.Auth]
-	teamRetriever db.Retriever[teamtbl.Team]
-	teamInserter  db.Inserter[teamtbl.Team]
-	teamUpdater   db.Updater[teamtbl.Team]
-	inviteEncoder cookie.Encoder[cookie.Invite]
-	log           log.Errorer
+	authDecoder         cookie.Decoder[cookie.Auth]
+	teamRetriever       db.Retriever[teamtbl.Team]
+	teamInserter        db.Inserter[teamtbl.Team]
+	teamUpdater         db.Updater[teamtbl.Team]
+	memberRetriever     db.RetrieverDualKey[membershiptbl.Membership]
+	profileRetriever    db.RetrieverBatch[usertbl.Profile]
+	boardTasksRetriever db.Retriever[[]tasktbl.Task]
+	log                 log.Errorer
 }
 
 // NewGetHandler creates and returns a new GetHandler.
@@ -33,23 +73,27 @@ func NewGetHandler(
 	teamRetriever db.Retriever[teamtbl.Team],
 	teamInserter db.Inserter[teamtbl.Team],
 	teamUpdater db.Updater[teamtbl.Team],
-	inviteEncoder cookie.Encoder[cookie.Invite],
+	memberRetriever db.RetrieverDualKey[membershiptbl.Membership],
+	profileRetriever db.RetrieverBatch[usertbl.Profile],
+	boardTasksRetriever db.Retriever[[]tasktbl.Task],
 	log log.Errorer,
 ) GetHandler {
 	return GetHandler{
-		authDecoder:   authDecoder,
-		teamRetriever: teamRetriever,
-		teamInserter:  teamInserter,
-		teamUpdater:   teamUpdater,
-		inviteEncoder: inviteEncoder,
-		log:           log,
+		authDecoder:         authDecoder,
+		teamRetriever:       teamRetriever,
+		teamInserter:        teamInserter,
+		teamUpdater:         teamUpdater,
+		memberRetriever:     memberRetriever,
+		profileRetriever:    profileRetriever,
+		boardTasksRetriever: boardTasksRetriever,
+		log:                 log,
 	}
 }
 
 // Handle handles GET requests sent to the team route.
 func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 	// get auth token
-	ckAuth, err := r.Cookie(cookie.AuthName)
+	ckAuth, err := cookie.GetAuth(r)
 	if err == http.ErrNoCookie {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
@@ -70,9 +114,9 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		// from the register endpoint that this is a new user and we should
 		// create a new team for them
 
-		// register endpoint must have set the isAdmin to true
+		// register endpoint must have made the user the team's owner
 		// this check might be redundant but it's here just in case
-		if !auth.IsAdmin {
+		if auth.Role != role.Owner {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
@@ -80,6 +124,8 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		// create team
 		team = teamtbl.NewTeam(
 			auth.TeamID,
+			"New Team",
+			auth.Username,
 			[]string{auth.Username},
 			[]teamtbl.Board{
 				teamtbl.NewBoard(uuid.NewString(), "New Board"),
@@ -111,7 +157,7 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 	} else {
 		status = http.StatusOK
 
-		if !auth.IsAdmin {
+		if !auth.Role.IsAdmin() {
 			var isTeamMember bool
 			for _, member := range team.Members {
 				if member == auth.Username {
@@ -123,6 +169,20 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 			// to the team - this is a synchronisation step and is safe since we
 			// validated the JWT and got the username and the team ID from it
 			if !isTeamMember {
+				// the user must still be a member of the team, otherwise they
+				// were removed from it and their token is no longer valid
+				_, err := h.memberRetriever.Retrieve(
+					r.Context(), auth.Username, team.ID,
+				)
+				if errors.Is(err, db.ErrNoItem) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				} else if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					h.log.Error(err)
+					return
+				}
+
 				team.Members = append(team.Members, auth.Username)
 				if err = h.teamUpdater.Update(r.Context(), team); err != nil {
 					w.WriteHeader(http.StatusInternalServerError)
@@ -145,20 +205,86 @@ func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		}
 	}
 
-	// encode invite token if the user is admin
-	if auth.IsAdmin {
-		ckInv, err := h.inviteEncoder.Encode(cookie.NewInvite(team.ID))
+	// leave out the archived boards unless they were asked for
+	if r.URL.Query().Get("includeArchived") != "true" {
+		var boards []teamtbl.Board
+		for _, b := range team.Boards {
+			if !b.Archived {
+				boards = append(boards, b)
+			}
+		}
+		team.Boards = boards
+	}
+
+	// map the boards along with their columns and the number of tasks in each
+	// - boards that were created before boards had their own columns are
+	// given the default columns
+	boards := make([]Board, len(team.Boards))
+	for i, b := range team.Boards {
+		// only the tasks of the boards in the response are counted so that
+		// the tasks of the rest of the team's boards aren't loaded for nothing
+		tasks, err := h.boardTasksRetriever.Retrieve(r.Context(), b.ID)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			h.log.Error(err)
 			return
 		}
-		http.SetCookie(w, &ckInv)
+		counts := make(map[string]int)
+		for _, t := range tasks {
+			counts[t.ColID]++
+		}
+
+		var cols []Column
+		for _, c := range b.ColumnsOrDefault() {
+			cols = append(cols, Column{
+				ID:       c.ID,
+				Name:     c.Name,
+				Color:    c.Color,
+				WIPLimit: c.WIPLimit,
+				Count:    counts[c.ID],
+			})
+		}
+		boards[i] = Board{
+			ID:       b.ID,
+			Name:     b.Name,
+			Members:  b.Members,
+			Columns:  cols,
+			Archived: b.Archived,
+		}
+	}
+
+	// retrieve the profiles of the team's members - members who were deleted
+	// since they joined the team are still returned with their username
+	profiles, err := h.profileRetriever.Retrieve(r.Context(), team.Members)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	profilesByUser := make(map[string]usertbl.Profile, len(profiles))
+	for _, p := range profiles {
+		profilesByUser[p.Username] = p
+	}
+	members := make([]Member, 0, len(team.Members))
+	for _, username := range team.Members {
+		p := profilesByUser[username]
+		members = append(members, Member{
+			Username:    username,
+			DisplayName: p.DisplayName,
+			AvatarURL:   p.AvatarURL,
+		})
 	}
 
 	// encode team
 	w.WriteHeader(status)
-	if err = json.NewEncoder(w).Encode(GetResp(team)); err != nil {
+	if err = json.NewEncoder(w).Encode(GetResp{
+		ID:         team.ID,
+		Name:       team.Name,
+		Owner:      team.Owner,
+		Members:    members,
+		Boards:     boards,
+		RequireMFA: team.RequireMFA,
+	}); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		h.log.Error(err)
 		return
@@ -12,8 +12,12 @@ import (
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 func TestGetHandler(t *testing.T) {
@@ -21,14 +25,18 @@ func TestGetHandler(t *testing.T) {
 	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
 	teamInserter := &db.FakeInserter[teamtbl.Team]{}
 	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
-	inviteEncoder := &cookie.FakeEncoder[cookie.Invite]{}
+	memberRetriever := &db.FakeRetrieverDualKey[membershiptbl.Membership]{}
+	profileRetriever := &db.FakeRetrieverBatch[usertbl.Profile]{}
+	boardTasksRetriever := &db.FakeRetrieverByKey[[]tasktbl.Task]{}
 	log := &log.FakeErrorer{}
 	sut := NewGetHandler(
 		authDecoder,
 		teamRetriever,
 		teamInserter,
 		teamUpdater,
-		inviteEncoder,
+		memberRetriever,
+		profileRetriever,
+		boardTasksRetriever,
 		log,
 	)
 
@@ -36,208 +44,385 @@ func TestGetHandler(t *testing.T) {
 		ID:      "teamid",
 		Members: []string{"memberone", "membertwo"},
 		Boards: []teamtbl.Board{
-			{ID: "board1", Name: "boardone", Members: []string{"memberone"}},
+			{
+				ID:      "board1",
+				Name:    "boardone",
+				Members: []string{"memberone"},
+				Columns: []teamtbl.Column{
+					teamtbl.NewColumn("todo", "To Do", "#1a2b3c", 3),
+					teamtbl.NewColumn("doing", "Doing", "", 0),
+				},
+			},
 			{ID: "board2", Name: "boardtwo", Members: []string{"membertwo"}},
 		},
 	}
+	archivedTeam := teamtbl.Team{
+		ID:      "teamid",
+		Members: []string{"memberone"},
+		Boards: []teamtbl.Board{
+			{ID: "board1", Name: "boardone"},
+			{ID: "board2", Name: "boardtwo", Archived: true},
+		},
+	}
+	tasks := map[string][]tasktbl.Task{
+		"board1": {
+			{BoardID: "board1", ColID: "todo"},
+			{BoardID: "board1", ColID: "todo"},
+		},
+		"board2": {{BoardID: "board2", ColID: "go"}},
+	}
+	profiles := []usertbl.Profile{
+		{
+			Username:    "memberone",
+			DisplayName: "Member One",
+			AvatarURL:   "https://goteam.io/memberone.png",
+		},
+	}
+
+	// usernames returns the usernames of the given members.
+	usernames := func(members []Member) []string {
+		var usernames []string
+		for _, m := range members {
+			usernames = append(usernames, m.Username)
+		}
+		return usernames
+	}
 
 	for _, c := range []struct {
-		name            string
-		auth            string
-		errDecodeAuth   error
-		authDecoded     cookie.Auth
-		errRetrieve     error
-		team            teamtbl.Team
-		errInsert       error
-		errUpdate       error
-		errEncodeInvite error
-		inviteEncoded   http.Cookie
-		wantStatus      int
-		assertFunc      func(*testing.T, *http.Response, []any)
+		name          string
+		auth          string
+		query         string
+		errDecodeAuth error
+		authDecoded   cookie.Auth
+		errRetrieve   error
+		team          teamtbl.Team
+		errInsert     error
+		errUpdate     error
+		errMembership error
+		errProfiles   error
+		errTasks      error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
 	}{
 		{
-			name:            "NoAuth",
-			auth:            "",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{},
-			errRetrieve:     nil,
-			team:            teamtbl.Team{},
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusUnauthorized,
-			assertFunc:      func(*testing.T, *http.Response, []any) {},
+			name:          "NoAuth",
+			auth:          "",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
 		},
 		{
-			name:            "InvalidAuth",
-			auth:            "nonempty",
-			errDecodeAuth:   errors.New("decode auth failed"),
-			authDecoded:     cookie.Auth{},
-			errRetrieve:     nil,
-			team:            teamtbl.Team{},
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusUnauthorized,
-			assertFunc:      func(*testing.T, *http.Response, []any) {},
+			name:          "InvalidAuth",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: errors.New("decode auth failed"),
+			authDecoded:   cookie.Auth{},
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
 		},
 		{
-			name:            "ErrRetrieve",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{},
-			errRetrieve:     errors.New("retrieve failed"),
-			team:            teamtbl.Team{},
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusInternalServerError,
-			assertFunc:      assert.OnLoggedErr("retrieve failed"),
+			name:          "ErrRetrieve",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			errRetrieve:   errors.New("retrieve failed"),
+			team:          teamtbl.Team{},
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
 		},
 		// check comments in implementation for explanation
 		{
-			name:            "NotAdmin",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: false},
-			errRetrieve:     db.ErrNoItem,
-			team:            teamtbl.Team{},
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusUnauthorized,
-			assertFunc:      func(*testing.T, *http.Response, []any) {},
+			name:          "NotOwner",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin},
+			errRetrieve:   db.ErrNoItem,
+			team:          teamtbl.Team{},
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:          "ErrInsert",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Owner},
+			errRetrieve:   db.ErrNoItem,
+			team:          teamtbl.Team{},
+			errInsert:     errors.New("insert failed"),
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("insert failed"),
+		},
+		{
+			name:          "ErrUpdate",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Member},
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errInsert:     nil,
+			errUpdate:     errors.New("update failed"),
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update failed"),
 		},
 		{
-			name:            "ErrInsert",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true},
-			errRetrieve:     db.ErrNoItem,
-			team:            teamtbl.Team{},
-			errInsert:       errors.New("insert failed"),
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusInternalServerError,
-			assertFunc:      assert.OnLoggedErr("insert failed"),
+			name:          "ErrRetrieveMember",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Member, Username: "newuser"},
+			errRetrieve:   nil,
+			team:          teamtbl.Team{ID: "teamid"},
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: errors.New("retrieve member failed"),
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve member failed"),
 		},
 		{
-			name:            "ErrUpdate",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: false},
-			errRetrieve:     nil,
-			team:            teamtbl.Team{},
-			errInsert:       nil,
-			errUpdate:       errors.New("update failed"),
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusInternalServerError,
-			assertFunc:      assert.OnLoggedErr("update failed"),
+			name:          "NotMember",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Member, Username: "newuser"},
+			errRetrieve:   nil,
+			team:          teamtbl.Team{ID: "teamid"},
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: db.ErrNoItem,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
 		},
 		{
-			name:            "ErrEncodeInvite",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true, Username: "memberone"},
-			errRetrieve:     nil,
-			team:            teamtbl.Team{Members: []string{"memberone"}},
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: errors.New("encode invite failed"),
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusInternalServerError,
-			assertFunc:      assert.OnLoggedErr("encode invite failed"),
+			name:          "ErrRetrieveProfiles",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin, Username: "memberone"},
+			errRetrieve:   nil,
+			team:          wantTeam,
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   errors.New("retrieve profiles failed"),
+			errTasks:      nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve profiles failed"),
 		},
 		{
-			name:            "OKAdmin",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true, Username: "memberone"},
-			errRetrieve:     nil,
-			team:            wantTeam,
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
-			wantStatus:      http.StatusOK,
+			name:          "ErrRetrieveTasks",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin, Username: "memberone"},
+			errRetrieve:   nil,
+			team:          wantTeam,
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      errors.New("retrieve tasks failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve tasks failed"),
+		},
+		{
+			name:          "OKAdmin",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin, Username: "memberone"},
+			errRetrieve:   nil,
+			team:          wantTeam,
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
-				var team teamtbl.Team
+				var team GetResp
 				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
 					t.Fatal(err)
 				}
 
 				// since the user is admin, the team should be returned as is
 				assert.Equal(t.Error, team.ID, wantTeam.ID)
-				assert.AllEqual(t.Error, team.Members, wantTeam.Members)
+				assert.AllEqual(t.Error, team.Members, []Member{
+					{
+						Username:    "memberone",
+						DisplayName: "Member One",
+						AvatarURL:   "https://goteam.io/memberone.png",
+					},
+					{Username: "membertwo", DisplayName: "", AvatarURL: ""},
+				})
 				for i, wantB := range wantTeam.Boards {
 					b := team.Boards[i]
 					assert.Equal(t.Error, b.ID, wantB.ID)
 					assert.Equal(t.Error, b.Name, wantB.Name)
 					assert.AllEqual(t.Error, b.Members, wantB.Members)
+					for j, wantC := range wantB.ColumnsOrDefault() {
+						c := b.Columns[j]
+						assert.Equal(t.Error, c.ID, wantC.ID)
+						assert.Equal(t.Error, c.Name, wantC.Name)
+						assert.Equal(t.Error, c.Color, wantC.Color)
+						assert.Equal(t.Error, c.WIPLimit, wantC.WIPLimit)
+					}
+				}
+
+				// the tasks in each column should be counted
+				assert.Equal(t.Error, team.Boards[0].Columns[0].Count, 2)
+				assert.Equal(t.Error, team.Boards[0].Columns[1].Count, 0)
+				assert.Equal(t.Error, team.Boards[1].Columns[0].Count, 0)
+				assert.Equal(t.Error, team.Boards[1].Columns[2].Count, 1)
+			},
+		},
+		{
+			name:          "OKArchivedExcluded",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin, Username: "memberone"},
+			errRetrieve:   nil,
+			team:          archivedTeam,
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var team GetResp
+				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+					t.Fatal(err)
 				}
 
-				// invite cookie should be set for admin
-				ckInv := resp.Cookies()[0]
-				assert.Equal(t.Error, ckInv.Name, "invite-token")
-				assert.Equal(t.Error, ckInv.Value, "aksdfj")
+				assert.Equal(t.Fatal, len(team.Boards), 1)
+				assert.Equal(t.Error, team.Boards[0].ID, "board1")
+				assert.Equal(t.Error, team.Boards[0].Archived, false)
 			},
 		},
 		{
-			name:            "OKAdminNewTeam",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: true, Username: "newuser"},
-			errRetrieve:     db.ErrNoItem,
-			team:            teamtbl.Team{},
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
-			wantStatus:      http.StatusCreated,
+			name:          "OKArchivedIncluded",
+			auth:          "nonempty",
+			query:         "?includeArchived=true",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Admin, Username: "memberone"},
+			errRetrieve:   nil,
+			team:          archivedTeam,
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
-				var team teamtbl.Team
+				var team GetResp
+				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t.Fatal, len(team.Boards), 2)
+				assert.Equal(t.Error, team.Boards[1].ID, "board2")
+				assert.Equal(t.Error, team.Boards[1].Archived, true)
+			},
+		},
+		{
+			name:          "OKAdminNewTeam",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Owner, Username: "newuser"},
+			errRetrieve:   db.ErrNoItem,
+			team:          teamtbl.Team{},
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusCreated,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var team GetResp
 				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
 					t.Fatal(err)
 				}
 
 				// since the admin has no team, one should be created with a new
 				// board
-				assert.AllEqual(t.Error, team.Members, []string{"newuser"})
+				assert.Equal(t.Error, team.Name, "New Team")
+				assert.Equal(t.Error, team.Owner, "newuser")
+				assert.AllEqual(t.Error,
+					usernames(team.Members), []string{"newuser"},
+				)
 				assert.Equal(t.Error, len(team.Boards), 1)
 				assert.Equal(t.Error, team.Boards[0].Name, "New Board")
-
-				// invite cookie should be set for admin
-				ckInv := resp.Cookies()[0]
-				assert.Equal(t.Error, ckInv.Name, "invite-token")
-				assert.Equal(t.Error, ckInv.Value, "aksdfj")
 			},
 		},
 		{
-			name:            "OKMember",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: false, Username: "memberone"},
-			errRetrieve:     nil,
-			team:            wantTeam,
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{Name: "invite-token", Value: "aksdfj"},
-			wantStatus:      http.StatusOK,
+			name:          "OKMember",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded: cookie.Auth{
+				Role: role.Member, Username: "memberone",
+			},
+			errRetrieve:   nil,
+			team:          wantTeam,
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
-				var team teamtbl.Team
+				var team GetResp
 				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
 					t.Fatal(err)
 				}
 
 				assert.Equal(t.Error, team.ID, wantTeam.ID)
-				assert.AllEqual(t.Error, team.Members, wantTeam.Members)
+				assert.AllEqual(t.Error,
+					usernames(team.Members), wantTeam.Members,
+				)
 
 				// since not an admin, only the boards the user is a member of
 				// should be returned
@@ -247,39 +432,36 @@ func TestGetHandler(t *testing.T) {
 				assert.Equal(t.Error, b.ID, wantB.ID)
 				assert.Equal(t.Error, b.Name, wantB.Name)
 				assert.AllEqual(t.Error, b.Members, wantB.Members)
-
-				// no invite cookie should be set for non-admin
-				assert.Equal(t.Error, len(resp.Cookies()), 0)
 			},
 		},
 		{
-			name:            "OKInvitee",
-			auth:            "nonempty",
-			errDecodeAuth:   nil,
-			authDecoded:     cookie.Auth{IsAdmin: false, Username: "newuser"},
-			errRetrieve:     nil,
-			team:            wantTeam,
-			errInsert:       nil,
-			errUpdate:       nil,
-			errEncodeInvite: nil,
-			inviteEncoded:   http.Cookie{},
-			wantStatus:      http.StatusOK,
+			name:          "OKInvitee",
+			auth:          "nonempty",
+			query:         "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Member, Username: "newuser"},
+			errRetrieve:   nil,
+			team:          wantTeam,
+			errInsert:     nil,
+			errUpdate:     nil,
+			errMembership: nil,
+			errProfiles:   nil,
+			errTasks:      nil,
+			wantStatus:    http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
-				var team teamtbl.Team
+				var team GetResp
 				if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
 					t.Fatal(err)
 				}
 
 				assert.Equal(t.Error, team.ID, wantTeam.ID)
 				assert.AllEqual(t.Error,
-					team.Members, append(wantTeam.Members, "newuser"),
+					usernames(team.Members),
+					append(wantTeam.Members, "newuser"),
 				)
 
 				// since the user is not yet a member of any boards, no boards
 				assert.Equal(t.Error, len(team.Boards), 0)
-
-				// no invite cookie should be set for non-admin
-				assert.Equal(t.Error, len(resp.Cookies()), 0)
 			},
 		},
 	} {
@@ -290,10 +472,13 @@ func TestGetHandler(t *testing.T) {
 			teamRetriever.Res = c.team
 			teamInserter.Err = c.errInsert
 			teamUpdater.Err = c.errUpdate
-			inviteEncoder.Err = c.errEncodeInvite
-			inviteEncoder.Res = c.inviteEncoded
+			memberRetriever.Err = c.errMembership
+			profileRetriever.Res = profiles
+			profileRetriever.Err = c.errProfiles
+			boardTasksRetriever.Res = tasks
+			boardTasksRetriever.Err = c.errTasks
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r := httptest.NewRequest(http.MethodGet, "/"+c.query, nil)
 			if c.auth != "" {
 				r.AddCookie(&http.Cookie{Name: "auth-token", Value: c.auth})
 			}
@@ -0,0 +1,158 @@
+package teamapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// PatchReq defines the body of PATCH team requests. Both fields are optional
+// and the team keeps its current settings for those left out.
+type PatchReq struct {
+	Name       string `json:"name,omitempty"`
+	RequireMFA *bool  `json:"requireMFA,omitempty"`
+}
+
+// maxNameLen is the maximum number of characters that a team name can have.
+const maxNameLen = 35
+
+// PatchResp defines the body of PATCH team responses.
+type PatchResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can be used to handle PATCH team
+// requests, which are used for changing the team's settings.
+type PatchHandler struct {
+	authDecoder   cookie.Decoder[cookie.Auth]
+	teamRetriever db.Retriever[teamtbl.Team]
+	teamUpdater   db.Updater[teamtbl.Team]
+	log           log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		authDecoder:   authDecoder,
+		teamRetriever: teamRetriever,
+		teamUpdater:   teamUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles PATCH requests sent to the team route.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can edit the team
+	if !role.Can(auth.Role, role.EditTeam) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Only team admins can edit the team.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate team name
+	if len(req.Name) > maxNameLen {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Team name cannot be longer than 35 characters.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team, update its settings, and save it
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Team not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if req.Name != "" {
+		team.Name = req.Name
+	}
+	if req.RequireMFA != nil {
+		team.RequireMFA = *req.RequireMFA
+	}
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Team not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,214 @@
+//go:build utest
+
+package teamapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(authDecoder, teamRetriever, teamUpdater, log)
+
+	admin := cookie.Auth{Username: "bob123", Role: role.Admin, TeamID: "teamid"}
+	team := teamtbl.Team{ID: "teamid", Members: []string{"bob123", "bob124"}}
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		authDecoded   cookie.Auth
+		reqBody       string
+		team          teamtbl.Team
+		errRetrieve   error
+		errUpdate     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			reqBody:       `{"requireMFA": true}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			authDecoded:   cookie.Auth{},
+			reqBody:       `{"requireMFA": true}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotAdmin",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Username: "bob124", TeamID: "teamid"},
+			reqBody:       `{"requireMFA": true}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc:    assert.OnRespErr("Only team admins can edit the team."),
+		},
+		{
+			name:          "ErrDecodeReq",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       "{",
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("unexpected EOF"),
+		},
+		{
+			name:          "TeamNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       `{"requireMFA": true}`,
+			team:          teamtbl.Team{},
+			errRetrieve:   db.ErrNoItem,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Team not found."),
+		},
+		{
+			name:          "NameTooLong",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       `{"name": "` + strings.Repeat("a", 36) + `"}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Team name cannot be longer than 35 characters.",
+			),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       `{"requireMFA": true}`,
+			team:          teamtbl.Team{},
+			errRetrieve:   errors.New("retrieve failed"),
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "TeamNotFoundUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       `{"requireMFA": true}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Team not found."),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       `{"requireMFA": true}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     errors.New("update failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:          "Success",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       `{"requireMFA": true}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:          "SuccessRename",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       `{"name": "Team Rocket", "requireMFA": false}`,
+			team:          team,
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:          "SuccessRenameOnly",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			reqBody:       `{"name": "Team Rocket"}`,
+			team:          teamtbl.Team{ID: "teamid", RequireMFA: true},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecodeAuth
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieve
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch, "/team", strings.NewReader(c.reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package userapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// DeleteResp defines the body of DELETE user responses.
+type DeleteResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteHandler is an api.MethodHandler that can be used to handle DELETE user
+// requests, which are used for removing a member from a team. Users who aren't
+// a member of any other team are deleted along with their membership, personal
+// access tokens, and linked identities. The removed user is signed out
+// everywhere so that they lose access to the team straight away instead of
+// when their auth token expires.
+type DeleteHandler struct {
+	authDecoder        cookie.Decoder[cookie.Auth]
+	teamRetriever      db.Retriever[teamtbl.Team]
+	revocationInserter db.Inserter[revocationtbl.Revocation]
+	sessionsDeleter    db.Deleter
+	memberDeleter      db.DeleterDualKey
+	memberRetriever    db.Retriever[[]membershiptbl.Membership]
+	userRetriever      db.Retriever[usertbl.User]
+	userUpdater        db.Updater[usertbl.User]
+	tokenRetriever     db.Retriever[[]tokentbl.Token]
+	tokenDeleter       db.DeleterDualKey
+	identityRetriever  db.Retriever[[]identitytbl.Identity]
+	identityDeleter    db.Deleter
+	userDeleter        db.Deleter
+	teamUpdater        db.Updater[teamtbl.Team]
+	log                log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	teamRetriever db.Retriever[teamtbl.Team],
+	revocationInserter db.Inserter[revocationtbl.Revocation],
+	sessionsDeleter db.Deleter,
+	memberDeleter db.DeleterDualKey,
+	memberRetriever db.Retriever[[]membershiptbl.Membership],
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	tokenRetriever db.Retriever[[]tokentbl.Token],
+	tokenDeleter db.DeleterDualKey,
+	identityRetriever db.Retriever[[]identitytbl.Identity],
+	identityDeleter db.Deleter,
+	userDeleter db.Deleter,
+	teamUpdater db.Updater[teamtbl.Team],
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		authDecoder:        authDecoder,
+		teamRetriever:      teamRetriever,
+		revocationInserter: revocationInserter,
+		sessionsDeleter:    sessionsDeleter,
+		memberDeleter:      memberDeleter,
+		memberRetriever:    memberRetriever,
+		userRetriever:      userRetriever,
+		userUpdater:        userUpdater,
+		tokenRetriever:     tokenRetriever,
+		tokenDeleter:       tokenDeleter,
+		identityRetriever:  identityRetriever,
+		identityDeleter:    identityDeleter,
+		userDeleter:        userDeleter,
+		teamUpdater:        teamUpdater,
+		log:                log,
+	}
+}
+
+// Handle handles DELETE user requests.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can remove members
+	if !role.Can(auth.Role, role.RemoveMember) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Only team admins can remove members.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate username
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Username cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if username == auth.Username {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Team admins cannot remove themselves from the team.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team and check that the user is a member of it other than
+	// its owner, who must transfer ownership before they can be removed
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Team not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if username == team.Owner {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "The team owner cannot be removed from the team.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	members := removeString(team.Members, username)
+	if len(members) == len(team.Members) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Member not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// revoke all auth tokens issued to the user so far and end all of their
+	// sessions so that they can't be refreshed - this is done before the user
+	// is removed so that a failed attempt can be retried while they are still
+	// a member
+	if err = h.revocationInserter.Insert(
+		r.Context(),
+		revocationtbl.NewAllRevocation(username, time.Now().UnixMilli()),
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.sessionsDeleter.Delete(r.Context(), username); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// delete the membership so that the user can no longer be synced
+	// back into the team by GET team - if it was already deleted in a previous
+	// attempt that failed to update the team, carry on
+	if err = h.memberDeleter.Delete(
+		r.Context(), username, team.ID,
+	); err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.leaveTeam(r, username, team.ID); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// remove the user from the team and all of its boards
+	team.Members = members
+	for i, b := range team.Boards {
+		team.Boards[i].Members = removeString(b.Members, username)
+	}
+	if err = h.teamUpdater.Update(
+		r.Context(), team,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Team not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
+
+// leaveTeam deletes the user with the given username if they aren't a member of
+// any other team, and switches their active team to another one of their teams
+// if it was the team with the given ID.
+func (h DeleteHandler) leaveTeam(
+	r *http.Request, username, teamID string,
+) error {
+	memberships, err := h.memberRetriever.Retrieve(r.Context(), username)
+	if err != nil {
+		return err
+	}
+	if len(memberships) == 0 {
+		return h.deleteUser(r, username)
+	}
+
+	user, err := h.userRetriever.Retrieve(r.Context(), username)
+	if errors.Is(err, db.ErrNoItem) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if user.TeamID != teamID {
+		return nil
+	}
+	user.TeamID, user.Role = memberships[0].TeamID, memberships[0].Role
+	err = h.userUpdater.Update(r.Context(), user)
+	if errors.Is(err, db.ErrNoItem) {
+		return nil
+	}
+	return err
+}
+
+// deleteUser deletes the user with the given username along with their personal
+// access tokens and linked identities, which are looked up by username and
+// would otherwise let in whoever registers the username next. The user is
+// deleted last so that a failed attempt can be retried.
+func (h DeleteHandler) deleteUser(r *http.Request, username string) error {
+	tokens, err := h.tokenRetriever.Retrieve(r.Context(), username)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if err = h.tokenDeleter.Delete(
+			r.Context(), username, t.Name,
+		); err != nil && !errors.Is(err, db.ErrNoItem) {
+			return err
+		}
+	}
+
+	identities, err := h.identityRetriever.Retrieve(r.Context(), username)
+	if err != nil {
+		return err
+	}
+	for _, i := range identities {
+		if err = h.identityDeleter.Delete(
+			r.Context(), i.ID,
+		); err != nil && !errors.Is(err, db.ErrNoItem) {
+			return err
+		}
+	}
+
+	err = h.userDeleter.Delete(r.Context(), username)
+	if errors.Is(err, db.ErrNoItem) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,502 @@
+//go:build utest
+
+package userapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	revocationInserter := &db.FakeInserter[revocationtbl.Revocation]{}
+	sessionsDeleter := &db.FakeDeleter{}
+	memberDeleter := &db.FakeDeleterDualKey{}
+	memberRetriever := &db.FakeRetriever[[]membershiptbl.Membership]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	tokenRetriever := &db.FakeRetriever[[]tokentbl.Token]{}
+	tokenDeleter := &db.FakeDeleterDualKey{}
+	identityRetriever := &db.FakeRetriever[[]identitytbl.Identity]{}
+	identityDeleter := &db.FakeDeleter{}
+	userDeleter := &db.FakeDeleter{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(
+		authDecoder,
+		teamRetriever,
+		revocationInserter,
+		sessionsDeleter,
+		memberDeleter,
+		memberRetriever,
+		userRetriever,
+		userUpdater,
+		tokenRetriever,
+		tokenDeleter,
+		identityRetriever,
+		identityDeleter,
+		userDeleter,
+		teamUpdater,
+		log,
+	)
+
+	admin := cookie.Auth{Role: role.Admin, Username: "bob123"}
+	team := teamtbl.Team{
+		ID:      "teamid",
+		Members: []string{"bob123", "bob124"},
+		Boards: []teamtbl.Board{
+			{ID: "boardid", Name: "board", Members: []string{"bob124"}},
+		},
+	}
+
+	otherTeams := []membershiptbl.Membership{
+		membershiptbl.NewMembership("bob124", "otherteam", role.Viewer),
+	}
+
+	for _, c := range []struct {
+		name              string
+		authToken         string
+		errDecodeAuth     error
+		authDecoded       cookie.Auth
+		username          string
+		errRetrieve       error
+		team              teamtbl.Team
+		errRevoke         error
+		errDeleteSessions error
+		errDeleteMember   error
+		memberships       []membershiptbl.Membership
+		errRetrieveMember error
+		user              usertbl.User
+		errRetrieveUser   error
+		errUpdateUser     error
+		tokens            []tokentbl.Token
+		errRetrieveTokens error
+		errDeleteToken    error
+		identities        []identitytbl.Identity
+		errRetrieveIDs    error
+		errDeleteID       error
+		errDelete         error
+		errUpdate         error
+		wantStatus        int
+		assertFunc        func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			username:      "",
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			authDecoded:   cookie.Auth{},
+			username:      "",
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotAdmin",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Member},
+			username:      "",
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can remove members.",
+			),
+		},
+		{
+			name:          "UsernameEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "",
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Username cannot be empty."),
+		},
+		{
+			name:          "RemoveSelf",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob123",
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Team admins cannot remove themselves from the team.",
+			),
+		},
+		{
+			name:          "TeamNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   db.ErrNoItem,
+			team:          teamtbl.Team{},
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Team not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   errors.New("retrieve team failed"),
+			team:          teamtbl.Team{},
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:          "RemoveOwner",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          teamtbl.Team{Owner: "bob124", Members: team.Members},
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"The team owner cannot be removed from the team.",
+			),
+		},
+		{
+			name:          "MemberNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob125",
+			errRetrieve:   nil,
+			team:          team,
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Member not found."),
+		},
+		{
+			name:          "ErrRevoke",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          team,
+			errRevoke:     errors.New("revoke failed"),
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("revoke failed"),
+		},
+		{
+			name:              "ErrDeleteSessions",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       admin,
+			username:          "bob124",
+			errRetrieve:       nil,
+			team:              team,
+			errDeleteSessions: errors.New("delete sessions failed"),
+			errDelete:         nil,
+			errUpdate:         nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("delete sessions failed"),
+		},
+		{
+			name:            "ErrDeleteMember",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			authDecoded:     admin,
+			username:        "bob124",
+			errRetrieve:     nil,
+			team:            team,
+			errDeleteMember: errors.New("delete member failed"),
+			errDelete:       nil,
+			errUpdate:       nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("delete member failed"),
+		},
+		{
+			name:              "ErrRetrieveMemberships",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       admin,
+			username:          "bob124",
+			errRetrieve:       nil,
+			team:              team,
+			errDeleteMember:   db.ErrNoItem,
+			errRetrieveMember: errors.New("retrieve memberships failed"),
+			errDelete:         nil,
+			errUpdate:         nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr(
+				"retrieve memberships failed",
+			),
+		},
+		{
+			name:            "ErrRetrieveUser",
+			authToken:       "nonempty",
+			errDecodeAuth:   nil,
+			authDecoded:     admin,
+			username:        "bob124",
+			errRetrieve:     nil,
+			team:            team,
+			memberships:     otherTeams,
+			errRetrieveUser: errors.New("retrieve user failed"),
+			errDelete:       nil,
+			errUpdate:       nil,
+			wantStatus:      http.StatusInternalServerError,
+			assertFunc:      assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:          "ErrUpdateUser",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          team,
+			memberships:   otherTeams,
+			user:          usertbl.User{TeamID: "teamid"},
+			errUpdateUser: errors.New("update user failed"),
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:              "ErrRetrieveTokens",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			authDecoded:       admin,
+			username:          "bob124",
+			errRetrieve:       nil,
+			team:              team,
+			errRetrieveTokens: errors.New("retrieve tokens failed"),
+			errDelete:         nil,
+			errUpdate:         nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve tokens failed"),
+		},
+		{
+			name:           "ErrDeleteToken",
+			authToken:      "nonempty",
+			errDecodeAuth:  nil,
+			authDecoded:    admin,
+			username:       "bob124",
+			errRetrieve:    nil,
+			team:           team,
+			tokens:         []tokentbl.Token{{Name: "ci"}},
+			errDeleteToken: errors.New("delete token failed"),
+			errDelete:      nil,
+			errUpdate:      nil,
+			wantStatus:     http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("delete token failed"),
+		},
+		{
+			name:           "ErrRetrieveIdentities",
+			authToken:      "nonempty",
+			errDecodeAuth:  nil,
+			authDecoded:    admin,
+			username:       "bob124",
+			errRetrieve:    nil,
+			team:           team,
+			errRetrieveIDs: errors.New("retrieve identities failed"),
+			errDelete:      nil,
+			errUpdate:      nil,
+			wantStatus:     http.StatusInternalServerError,
+			assertFunc:     assert.OnLoggedErr("retrieve identities failed"),
+		},
+		{
+			name:          "ErrDeleteIdentity",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          team,
+			identities:    []identitytbl.Identity{{ID: "idp#sub"}},
+			errDeleteID:   errors.New("delete identity failed"),
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("delete identity failed"),
+		},
+		{
+			name:          "ErrDelete",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          team,
+			errDelete:     errors.New("delete user failed"),
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("delete user failed"),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          team,
+			errDelete:     nil,
+			errUpdate:     errors.New("update team failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:          "UserAlreadyDeleted",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          team,
+			errDelete:     db.ErrNoItem,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:          "OKSwitchActiveTeam",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          team,
+			memberships:   otherTeams,
+			user:          usertbl.User{TeamID: "teamid"},
+			errRetrieveTokens: errors.New(
+				"tokens must not be deleted",
+			),
+			errRetrieveIDs: errors.New("identities must not be deleted"),
+			errDelete:      errors.New("user must not be deleted"),
+			errUpdate:      nil,
+			wantStatus:     http.StatusOK,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:          "OKOtherActiveTeam",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errRetrieve:   nil,
+			team:          team,
+			memberships:   otherTeams,
+			user:          usertbl.User{TeamID: "otherteam"},
+			errUpdateUser: errors.New("user must not be updated"),
+			errDelete:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:           "OK",
+			authToken:      "nonempty",
+			errDecodeAuth:  nil,
+			authDecoded:    admin,
+			username:       "bob124",
+			errRetrieve:    nil,
+			team:           team,
+			tokens:         []tokentbl.Token{{Name: "ci"}},
+			errDeleteToken: db.ErrNoItem,
+			identities:     []identitytbl.Identity{{ID: "idp#sub"}},
+			errDeleteID:    db.ErrNoItem,
+			errDelete:      nil,
+			errUpdate:      nil,
+			wantStatus:     http.StatusOK,
+			assertFunc:     func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			authDecoder.Res = c.authDecoded
+			teamRetriever.Err = c.errRetrieve
+			teamRetriever.Res = c.team
+			revocationInserter.Err = c.errRevoke
+			sessionsDeleter.Err = c.errDeleteSessions
+			memberDeleter.Err = c.errDeleteMember
+			memberRetriever.Res = c.memberships
+			memberRetriever.Err = c.errRetrieveMember
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
+			userUpdater.Err = c.errUpdateUser
+			tokenRetriever.Res = c.tokens
+			tokenRetriever.Err = c.errRetrieveTokens
+			tokenDeleter.Err = c.errDeleteToken
+			identityRetriever.Res = c.identities
+			identityRetriever.Err = c.errRetrieveIDs
+			identityDeleter.Err = c.errDeleteID
+			userDeleter.Err = c.errDelete
+			teamUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodDelete, "/?username="+c.username, nil,
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  cookie.AuthName,
+					Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
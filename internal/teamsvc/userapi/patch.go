@@ -0,0 +1,220 @@
+package userapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PatchReq defines the body of PATCH user requests.
+type PatchReq struct {
+	BoardID  string `json:"boardID"`
+	IsActive bool   `json:"isActive"`
+}
+
+// PatchResp defines the body of PATCH user responses.
+type PatchResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can be used to handle PATCH user
+// requests, which are used for adding/removing a team member to/from a board.
+type PatchHandler struct {
+	authDecoder      cookie.Decoder[cookie.Auth]
+	boardIDValidator validator.String
+	teamRetriever    db.Retriever[teamtbl.Team]
+	boardUpdater     db.UpdaterDualKey[teamtbl.Board]
+	log              log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	boardIDValidator validator.String,
+	teamRetriever db.Retriever[teamtbl.Team],
+	boardUpdater db.UpdaterDualKey[teamtbl.Board],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		authDecoder:      authDecoder,
+		boardIDValidator: boardIDValidator,
+		teamRetriever:    teamRetriever,
+		boardUpdater:     boardUpdater,
+		log:              log,
+	}
+}
+
+// Handle handles PATCH user requests.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate user can edit board members
+	if !role.Can(auth.Role, role.EditBoardMembers) {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Only team admins can edit board members.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate username
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Username cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if username == auth.Username {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Team admins have access to all boards.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate board ID
+	if err := h.boardIDValidator.Validate(req.BoardID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		var msg string
+		if errors.Is(err, validator.ErrEmpty) {
+			msg = "Board ID cannot be empty."
+		} else if errors.Is(err, validator.ErrWrongFormat) {
+			msg = "Board ID must be a UUID."
+		}
+
+		if err = json.NewEncoder(w).Encode(PatchResp{Error: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the team
+	team, err := h.teamRetriever.Retrieve(r.Context(), auth.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Team not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate that the user is a member of the team
+	if !containsString(team.Members, username) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Member not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// find the board to add the user to or remove the user from
+	var board teamtbl.Board
+	var found bool
+	for _, b := range team.Boards {
+		if b.ID == req.BoardID {
+			board, found = b, true
+			break
+		}
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// add/remove the user to/from the board's members
+	board.Members = removeString(board.Members, username)
+	if req.IsActive {
+		board.Members = append(board.Members, username)
+	}
+
+	// update the board for the team
+	if err := h.boardUpdater.Update(
+		r.Context(), auth.TeamID, board,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Board not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
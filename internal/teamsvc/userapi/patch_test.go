@@ -0,0 +1,277 @@
+//go:build utest
+
+package userapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	boardIDValidator := &api.FakeStringValidator{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	boardUpdater := &db.FakeUpdaterDualKey[teamtbl.Board]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(
+		authDecoder, boardIDValidator, teamRetriever, boardUpdater, log,
+	)
+
+	team := teamtbl.Team{
+		ID:      "teamid",
+		Members: []string{"bob123", "bob124"},
+		Boards: []teamtbl.Board{
+			{
+				ID:      "c193d6ba-ebfe-45fe-80d9-00b545690b4b",
+				Name:    "board",
+				Members: []string{},
+			},
+		},
+	}
+	admin := cookie.Auth{Role: role.Admin, Username: "bob123"}
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		authDecoded   cookie.Auth
+		username      string
+		errValidateID error
+		errRetrieve   error
+		team          teamtbl.Team
+		errUpdate     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			username:      "",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			authDecoded:   cookie.Auth{},
+			username:      "",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NotAdmin",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Role: role.Member},
+			username:      "",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Only team admins can edit board members.",
+			),
+		},
+		{
+			name:          "UsernameEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Username cannot be empty."),
+		},
+		{
+			name:          "UsernameAdmin",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob123",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Team admins have access to all boards.",
+			),
+		},
+		{
+			name:          "BoardIDEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errValidateID: validator.ErrEmpty,
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Board ID cannot be empty."),
+		},
+		{
+			name:          "BoardIDNotUUID",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errValidateID: validator.ErrWrongFormat,
+			errRetrieve:   nil,
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Board ID must be a UUID."),
+		},
+		{
+			name:          "TeamNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errValidateID: nil,
+			errRetrieve:   db.ErrNoItem,
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Team not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errValidateID: nil,
+			errRetrieve:   errors.New("retrieve team failed"),
+			team:          teamtbl.Team{},
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:          "MemberNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob125",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          team,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Member not found."),
+		},
+		{
+			name:          "BoardNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          teamtbl.Team{Members: team.Members},
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "BoardNotFoundOnUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          team,
+			errUpdate:     db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("Board not found."),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          team,
+			errUpdate:     errors.New("update board failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update board failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   admin,
+			username:      "bob124",
+			errValidateID: nil,
+			errRetrieve:   nil,
+			team:          team,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			authDecoder.Res = c.authDecoded
+			boardIDValidator.Err = c.errValidateID
+			teamRetriever.Err = c.errRetrieve
+			teamRetriever.Res = c.team
+			boardUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch,
+				"/?username="+c.username,
+				strings.NewReader(`{
+                    "boardID": "c193d6ba-ebfe-45fe-80d9-00b545690b4b",
+                    "isActive": true
+                }`),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  cookie.AuthName,
+					Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// Package userapi contains code for responding to HTTP requests made to the
+// user API route, which is used by team admins for managing team members.
+package userapi
+
+// removeString returns a copy of the given slice of strings with all
+// occurrences of the given string removed from it.
+func removeString(strs []string, s string) []string {
+	res := make([]string, 0, len(strs))
+	for _, str := range strs {
+		if str != s {
+			res = append(res, str)
+		}
+	}
+	return res
+}
+
+// containsString checks whether the given slice of strings contains the given
+// string.
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
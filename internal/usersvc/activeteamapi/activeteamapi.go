@@ -0,0 +1,4 @@
+// Package activeteamapi contains code for responding to HTTP requests made to
+// the active team API route, which is used by users who are a member of more
+// than one team for switching the team that they are working in.
+package activeteamapi
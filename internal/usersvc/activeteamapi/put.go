@@ -0,0 +1,185 @@
+package activeteamapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PutReq defines the body of PUT active team requests.
+type PutReq struct {
+	TeamID string `json:"teamID"`
+}
+
+// PutResp defines the body of PUT active team responses.
+type PutResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PutHandler is an api.MethodHandler that can be used to handle PUT active team
+// requests. The user's active team is stored on the user so that the tokens
+// issued to them on login and refresh are for the team they last switched to.
+type PutHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	memberRetriever db.RetrieverDualKey[membershiptbl.Membership]
+	userRetriever   db.Retriever[usertbl.User]
+	teamRetriever   db.Retriever[teamtbl.Team]
+	userUpdater     db.Updater[usertbl.User]
+	authEncoder     cookie.Encoder[cookie.Auth]
+	log             log.Errorer
+}
+
+// NewPutHandler creates and returns a new PutHandler.
+func NewPutHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	memberRetriever db.RetrieverDualKey[membershiptbl.Membership],
+	userRetriever db.Retriever[usertbl.User],
+	teamRetriever db.Retriever[teamtbl.Team],
+	userUpdater db.Updater[usertbl.User],
+	authEncoder cookie.Encoder[cookie.Auth],
+	log log.Errorer,
+) PutHandler {
+	return PutHandler{
+		authDecoder:     authDecoder,
+		memberRetriever: memberRetriever,
+		userRetriever:   userRetriever,
+		teamRetriever:   teamRetriever,
+		userUpdater:     userUpdater,
+		authEncoder:     authEncoder,
+		log:             log,
+	}
+}
+
+// Handle handles the PUT requests sent to the active team route.
+func (h PutHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode and validate request
+	var req PutReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if req.TeamID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Team ID cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// the user must be a member of the team they are switching to
+	membership, err := h.memberRetriever.Retrieve(
+		r.Context(), auth.Username, req.TeamID,
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Team not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// retrieve the user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	user.TeamID, user.Role = membership.TeamID, membership.Role
+
+	// a user who hasn't enabled two-factor authentication cannot switch to a
+	// team that requires it, since they would have to enroll on their next
+	// login anyway
+	step, err := loginapi.MFAStep(r.Context(), h.teamRetriever, user)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if step == loginapi.MFAEnroll {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(PutResp{
+			Error: "This team requires two-factor authentication.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// make the team the user's active team
+	if err = h.userUpdater.Update(r.Context(), user); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// re-issue the auth token for the team
+	ckAuthNew, err := h.authEncoder.Encode(cookie.NewAuth(
+		user.Username, user.Role, user.TeamID,
+	))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	http.SetCookie(w, &ckAuthNew)
+}
@@ -0,0 +1,311 @@
+//go:build utest
+
+package activeteamapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestPutHandler tests the Handle method of PutHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPutHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	memberRetriever := &db.FakeRetrieverDualKey[membershiptbl.Membership]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	authEncoder := &cookie.FakeEncoder[cookie.Auth]{}
+	log := &log.FakeErrorer{}
+	sut := NewPutHandler(
+		authDecoder,
+		memberRetriever,
+		userRetriever,
+		teamRetriever,
+		userUpdater,
+		authEncoder,
+		log,
+	)
+
+	membership := membershiptbl.NewMembership("bob123", "team2", role.Viewer)
+	user := usertbl.NewUser("bob123", "", nil, role.Owner, "team1")
+	userMFA := user
+	userMFA.MFAEnabled = true
+
+	for _, c := range []struct {
+		name              string
+		authToken         string
+		errDecodeAuth     error
+		reqBody           string
+		membership        membershiptbl.Membership
+		errRetrieveMember error
+		user              usertbl.User
+		errRetrieveUser   error
+		team              teamtbl.Team
+		errRetrieveTeam   error
+		errUpdate         error
+		errEncode         error
+		wantStatus        int
+		assertFunc        func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:              "NoAuth",
+			authToken:         "",
+			errDecodeAuth:     nil,
+			reqBody:           "",
+			membership:        membershiptbl.Membership{},
+			errRetrieveMember: nil,
+			user:              usertbl.User{},
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:              "InvalidAuth",
+			authToken:         "nonempty",
+			errDecodeAuth:     cookie.ErrInvalid,
+			reqBody:           "",
+			membership:        membershiptbl.Membership{},
+			errRetrieveMember: nil,
+			user:              usertbl.User{},
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:              "TeamIDEmpty",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": ""}`,
+			membership:        membershiptbl.Membership{},
+			errRetrieveMember: nil,
+			user:              usertbl.User{},
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusBadRequest,
+			assertFunc:        assert.OnRespErr("Team ID cannot be empty."),
+		},
+		{
+			name:              "NotMember",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membershiptbl.Membership{},
+			errRetrieveMember: db.ErrNoItem,
+			user:              usertbl.User{},
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusNotFound,
+			assertFunc:        assert.OnRespErr("Team not found."),
+		},
+		{
+			name:              "ErrRetrieveMember",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membershiptbl.Membership{},
+			errRetrieveMember: errors.New("retrieve member failed"),
+			user:              usertbl.User{},
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve member failed"),
+		},
+		{
+			name:              "UserNotFound",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membership,
+			errRetrieveMember: nil,
+			user:              usertbl.User{},
+			errRetrieveUser:   db.ErrNoItem,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:              "ErrRetrieveUser",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membership,
+			errRetrieveMember: nil,
+			user:              usertbl.User{},
+			errRetrieveUser:   errors.New("retrieve user failed"),
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:              "ErrRetrieveTeam",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membership,
+			errRetrieveMember: nil,
+			user:              user,
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   errors.New("retrieve team failed"),
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:              "TeamRequiresMFA",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membership,
+			errRetrieveMember: nil,
+			user:              user,
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{RequireMFA: true},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"This team requires two-factor authentication.",
+			),
+		},
+		{
+			name:              "ErrUpdate",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membership,
+			errRetrieveMember: nil,
+			user:              user,
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         errors.New("update failed"),
+			errEncode:         nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:              "ErrEncode",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membership,
+			errRetrieveMember: nil,
+			user:              user,
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         errors.New("encode failed"),
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("encode failed"),
+		},
+		{
+			name:              "OKMFAEnabled",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membership,
+			errRetrieveMember: nil,
+			user:              userMFA,
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{RequireMFA: true},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.Equal(t.Fatal, len(resp.Cookies()), 1)
+				assert.Equal(t.Error, resp.Cookies()[0].Value, "newauth")
+			},
+		},
+		{
+			name:              "OK",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			reqBody:           `{"teamID": "team2"}`,
+			membership:        membership,
+			errRetrieveMember: nil,
+			user:              user,
+			errRetrieveUser:   nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			errUpdate:         nil,
+			errEncode:         nil,
+			wantStatus:        http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.Equal(t.Fatal, len(resp.Cookies()), 1)
+				assert.Equal(t.Error, resp.Cookies()[0].Value, "newauth")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = cookie.Auth{Username: "bob123", TeamID: "team1"}
+			authDecoder.Err = c.errDecodeAuth
+			memberRetriever.Res = c.membership
+			memberRetriever.Err = c.errRetrieveMember
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			userUpdater.Err = c.errUpdate
+			authEncoder.Res = http.Cookie{
+				Name: cookie.AuthName, Value: "newauth",
+			}
+			authEncoder.Err = c.errEncode
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPut, "/", strings.NewReader(c.reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
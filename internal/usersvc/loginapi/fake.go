@@ -11,3 +11,14 @@ type fakeHashComparer struct{ err error }
 
 // Compare implements the Comparator interface on fakeHashComparer.
 func (f *fakeHashComparer) Compare(_ []byte, _ string) error { return f.err }
+
+// fakeRehasher is a test fake for Rehasher.
+type fakeRehasher struct {
+	hash []byte
+	err  error
+}
+
+// Rehash implements the Rehasher interface on fakeRehasher.
+func (f *fakeRehasher) Rehash(_ []byte, _ string) ([]byte, error) {
+	return f.hash, f.err
+}
@@ -0,0 +1,256 @@
+package loginapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mfa"
+)
+
+const (
+	// MFAVerify is the MFA step for users who have enabled two-factor
+	// authentication, who must verify a code from their authenticator app or
+	// one of their recovery codes.
+	MFAVerify = "verify"
+
+	// MFAEnroll is the MFA step for users whose team requires two-factor
+	// authentication but who haven't enabled it yet, who must enroll in it.
+	MFAEnroll = "enroll"
+)
+
+// MFAStep returns the step of two-factor authentication that the user must
+// complete before being issued an auth token, or an empty string if they don't
+// need to complete it.
+func MFAStep(
+	ctx context.Context,
+	teamRetriever db.Retriever[teamtbl.Team],
+	user usertbl.User,
+) (string, error) {
+	if user.MFAEnabled {
+		return MFAVerify, nil
+	}
+
+	// teams are only created once their admin first loads them, so a missing
+	// team can't have required two-factor authentication yet
+	team, err := teamRetriever.Retrieve(ctx, user.TeamID)
+	if errors.Is(err, db.ErrNoItem) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if team.RequireMFA {
+		return MFAEnroll, nil
+	}
+	return "", nil
+}
+
+// MFAReq defines the body of POST login MFA requests.
+type MFAReq struct {
+	Code string `json:"code"`
+}
+
+// MFAHandler is an api.MethodHandler that can be used to handle the second
+// step of logging in for users who have enabled two-factor authentication.
+type MFAHandler struct {
+	mfaDecoder         cookie.Decoder[cookie.MFA]
+	attemptRetriever   db.Retriever[attempttbl.Attempt]
+	attemptIncrementer db.Incrementer[attempttbl.Attempt]
+	attemptDeleter     db.Deleter
+	userRetriever      db.Retriever[usertbl.User]
+	verifier           mfa.Verifier
+	userUpdater        db.Updater[usertbl.User]
+	authEncoder        cookie.Encoder[cookie.Auth]
+	refreshEncoder     cookie.Encoder[cookie.Refresh]
+	sessionInserter    db.Inserter[sessiontbl.Session]
+	audit              log.Warner
+	log                log.Errorer
+}
+
+// NewMFAHandler creates and returns a new MFAHandler. Failed attempts count
+// towards the same lockout as failed login attempts and are logged to audit.
+func NewMFAHandler(
+	mfaDecoder cookie.Decoder[cookie.MFA],
+	attemptRetriever db.Retriever[attempttbl.Attempt],
+	attemptIncrementer db.Incrementer[attempttbl.Attempt],
+	attemptDeleter db.Deleter,
+	userRetriever db.Retriever[usertbl.User],
+	verifier mfa.Verifier,
+	userUpdater db.Updater[usertbl.User],
+	authEncoder cookie.Encoder[cookie.Auth],
+	refreshEncoder cookie.Encoder[cookie.Refresh],
+	sessionInserter db.Inserter[sessiontbl.Session],
+	audit log.Warner,
+	log log.Errorer,
+) MFAHandler {
+	return MFAHandler{
+		mfaDecoder:         mfaDecoder,
+		attemptRetriever:   attemptRetriever,
+		attemptIncrementer: attemptIncrementer,
+		attemptDeleter:     attemptDeleter,
+		userRetriever:      userRetriever,
+		verifier:           verifier,
+		userUpdater:        userUpdater,
+		authEncoder:        authEncoder,
+		refreshEncoder:     refreshEncoder,
+		sessionInserter:    sessionInserter,
+		audit:              audit,
+		log:                log,
+	}
+}
+
+// Handle handles the POST requests sent to the login MFA route.
+func (h MFAHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get and decode the MFA token issued on entering the correct password
+	ckMFA, err := r.Cookie(cookie.MFAName)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	mfaToken, err := h.mfaDecoder.Decode(*ckMFA)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// read and validate request body
+	var req MFAReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if req.Code == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// reject the attempt if the username or the client IP is locked out so
+	// that codes can't be guessed
+	ip := clientIP(r)
+	wait, err := lockedOutFor(
+		r.Context(), h.attemptRetriever, mfaToken.Username, ip,
+	)
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if wait > 0 {
+		h.audit.Warn(
+			"locked out mfa attempt for", mfaToken.Username, "from", ip,
+		)
+		w.Header().Set("Retry-After", strconv.FormatInt(wait, 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	// retrieve the user, who must have enabled two-factor authentication
+	user, err := h.userRetriever.Retrieve(r.Context(), mfaToken.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !user.MFAEnabled {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// verify the code as a TOTP code, and as a recovery code if that fails,
+	// marking it as used either way
+	step, err := h.verifier.Verify(user.MFASecret, req.Code, user.MFALastStep)
+	if errors.Is(err, mfa.ErrInvalidCode) {
+		codes, err := mfa.UseRecoveryCode(user.RecoveryCodes, req.Code)
+		if err != nil {
+			h.fail(w, r, user.Username, ip)
+			return
+		}
+		user.RecoveryCodes = codes
+	} else if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	} else {
+		user.MFALastStep = step
+	}
+	if err = h.userUpdater.Update(r.Context(), user); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// reset the failures for the username now that the user has proved they
+	// own it
+	if err = h.attemptDeleter.Delete(
+		r.Context(), attempttbl.UserID(user.Username),
+	); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// encode a new auth token
+	ckAuth, err := h.authEncoder.Encode(cookie.NewAuth(
+		user.Username, user.Role, user.TeamID,
+	))
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// start a new session and encode a refresh token for it
+	familyID, tokenID := uuid.NewString(), uuid.NewString()
+	ckRefresh, err := h.refreshEncoder.Encode(
+		cookie.NewRefresh(familyID, tokenID),
+	)
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err = h.sessionInserter.Insert(r.Context(), sessiontbl.NewSession(
+		familyID, user.Username, tokenID, ckRefresh.Expires.Unix(),
+	)); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// delete the MFA token and set auth and refresh tokens in cookies
+	ckExpired := cookie.NewExpired(cookie.MFAName)
+	http.SetCookie(w, &ckExpired)
+	http.SetCookie(w, &ckAuth)
+	http.SetCookie(w, &ckRefresh)
+}
+
+// fail records a failed MFA attempt for the given username and client IP and
+// writes the response for it.
+func (h MFAHandler) fail(
+	w http.ResponseWriter, r *http.Request, username, ip string,
+) {
+	h.audit.Warn("failed mfa attempt for", username, "from", ip)
+	if err := addFailure(
+		r.Context(), h.attemptIncrementer, username, ip,
+	); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusBadRequest)
+}
@@ -0,0 +1,607 @@
+//go:build utest
+
+package loginapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mfa"
+)
+
+func TestMFAStep(t *testing.T) {
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+
+	for _, c := range []struct {
+		name     string
+		user     usertbl.User
+		team     teamtbl.Team
+		errTeam  error
+		wantStep string
+		wantErr  error
+	}{
+		{
+			name:     "MFAEnabled",
+			user:     usertbl.User{MFAEnabled: true},
+			team:     teamtbl.Team{},
+			errTeam:  errors.New("team retriever error"),
+			wantStep: MFAVerify,
+			wantErr:  nil,
+		},
+		{
+			name:     "ErrRetrieveTeam",
+			user:     usertbl.User{},
+			team:     teamtbl.Team{},
+			errTeam:  errors.New("team retriever error"),
+			wantStep: "",
+			wantErr:  errors.New("team retriever error"),
+		},
+		{
+			name:     "TeamNotFound",
+			user:     usertbl.User{},
+			team:     teamtbl.Team{},
+			errTeam:  db.ErrNoItem,
+			wantStep: "",
+			wantErr:  nil,
+		},
+		{
+			name:     "NotRequired",
+			user:     usertbl.User{},
+			team:     teamtbl.Team{RequireMFA: false},
+			errTeam:  nil,
+			wantStep: "",
+			wantErr:  nil,
+		},
+		{
+			name:     "Required",
+			user:     usertbl.User{},
+			team:     teamtbl.Team{RequireMFA: true},
+			errTeam:  nil,
+			wantStep: MFAEnroll,
+			wantErr:  nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errTeam
+
+			step, err := MFAStep(context.Background(), teamRetriever, c.user)
+
+			if c.wantErr != nil {
+				assert.Equal(t.Error, err.Error(), c.wantErr.Error())
+			} else {
+				assert.Nil(t.Error, err)
+			}
+			assert.Equal(t.Error, step, c.wantStep)
+		})
+	}
+}
+
+func TestMFAHandler(t *testing.T) {
+	var (
+		mfaDecoder         = &cookie.FakeDecoder[cookie.MFA]{}
+		attemptRetriever   = &db.FakeRetriever[attempttbl.Attempt]{}
+		attemptIncrementer = &db.FakeIncrementer[attempttbl.Attempt]{}
+		attemptDeleter     = &db.FakeDeleter{}
+		userRetriever      = &db.FakeRetriever[usertbl.User]{}
+		verifier           = &mfa.FakeVerifier{}
+		userUpdater        = &db.FakeUpdater[usertbl.User]{}
+		authEncoder        = &cookie.FakeEncoder[cookie.Auth]{}
+		refreshEncoder     = &cookie.FakeEncoder[cookie.Refresh]{}
+		sessionInserter    = &db.FakeInserter[sessiontbl.Session]{}
+		audit              = &log.FakeWarner{}
+		log                = &log.FakeErrorer{}
+	)
+	sut := NewMFAHandler(
+		mfaDecoder,
+		attemptRetriever,
+		attemptIncrementer,
+		attemptDeleter,
+		userRetriever,
+		verifier,
+		userUpdater,
+		authEncoder,
+		refreshEncoder,
+		sessionInserter,
+		audit,
+		log,
+	)
+
+	recoveryCodes, hashes, err := mfa.NewRecoveryCodes()
+	assert.Nil(t.Fatal, err)
+	user := usertbl.User{
+		Username:      "bob123",
+		MFAEnabled:    true,
+		MFASecret:     "SECRET",
+		RecoveryCodes: hashes,
+	}
+
+	// assertMFALoggedIn asserts that the MFA token was deleted and the auth
+	// and refresh tokens were set.
+	assertMFALoggedIn := func(t *testing.T, resp *http.Response, _ []any) {
+		cks := resp.Cookies()
+		assert.Equal(t.Fatal, len(cks), 3)
+		assert.Equal(t.Error, cks[0].Name, cookie.MFAName)
+		assert.Equal(t.Error, cks[0].MaxAge, -1)
+		assert.Equal(t.Error, cks[1].Name, "foo")
+		assert.Equal(t.Error, cks[1].Value, "bar")
+		assert.Equal(t.Error, cks[2].Name, "baz")
+		assert.Equal(t.Error, cks[2].Value, "qux")
+	}
+
+	for _, c := range []struct {
+		name               string
+		mfaToken           string
+		errDecodeMFA       error
+		mfaDecoded         cookie.MFA
+		code               string
+		attempt            attempttbl.Attempt
+		errRetrieveAttempt error
+		user               usertbl.User
+		errRetrieveUser    error
+		errVerify          error
+		errIncrement       error
+		errUpdateUser      error
+		errDeleteAttempt   error
+		authToken          http.Cookie
+		errEncodeAuth      error
+		refreshToken       http.Cookie
+		errEncodeRefresh   error
+		errInsertSession   error
+		wantStatus         int
+		assertFunc         func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:               "NoMFAToken",
+			mfaToken:           "",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:               "InvalidMFAToken",
+			mfaToken:           "token",
+			errDecodeMFA:       errors.New("decode mfa failed"),
+			mfaDecoded:         cookie.MFA{},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:               "EmptyCode",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusBadRequest,
+			assertFunc:         func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:               "ErrRetrieveAttempt",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: errors.New("attempt retriever error"),
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("attempt retriever error"),
+		},
+		{
+			name:         "LockedOut",
+			mfaToken:     "token",
+			errDecodeMFA: nil,
+			mfaDecoded:   cookie.MFA{Username: "bob123"},
+			code:         "123456",
+			attempt: attempttbl.Attempt{
+				Failures: 20, LastFailedAt: time.Now().Unix(),
+			},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusTooManyRequests,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.True(t.Error, resp.Header.Get("Retry-After") != "")
+				assert.Equal(t.Error,
+					audit.Args[0], "locked out mfa attempt for",
+				)
+			},
+		},
+		{
+			name:               "UserNotFound",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    db.ErrNoItem,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:               "ErrRetrieveUser",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    errors.New("user retriever error"),
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("user retriever error"),
+		},
+		{
+			name:               "MFANotEnabled",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               usertbl.User{Username: "bob123"},
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusBadRequest,
+			assertFunc:         func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:               "ErrVerify",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          errors.New("verifier error"),
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("verifier error"),
+		},
+		{
+			name:               "InvalidCode",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          mfa.ErrInvalidCode,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusBadRequest,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error, audit.Args[0], "failed mfa attempt for")
+			},
+		},
+		{
+			name:               "ErrIncrement",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          mfa.ErrInvalidCode,
+			errIncrement:       errors.New("incrementer error"),
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("incrementer error"),
+		},
+		{
+			name:               "ErrUpdateUser",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      errors.New("user updater error"),
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("user updater error"),
+		},
+		{
+			name:               "ErrDeleteAttempt",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   errors.New("attempt deleter error"),
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("attempt deleter error"),
+		},
+		{
+			name:               "ErrEncodeAuth",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      errors.New("auth encoder error"),
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("auth encoder error"),
+		},
+		{
+			name:               "ErrEncodeRefresh",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   errors.New("refresh encoder error"),
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("refresh encoder error"),
+		},
+		{
+			name:               "ErrInsertSession",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   errors.New("session inserter error"),
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("session inserter error"),
+		},
+		{
+			name:               "SuccessTOTP",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               "123456",
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          nil,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{Name: "foo", Value: "bar"},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusOK,
+			assertFunc:         assertMFALoggedIn,
+		},
+		{
+			name:               "SuccessRecoveryCode",
+			mfaToken:           "token",
+			errDecodeMFA:       nil,
+			mfaDecoded:         cookie.MFA{Username: "bob123"},
+			code:               recoveryCodes[0],
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errVerify:          mfa.ErrInvalidCode,
+			errIncrement:       nil,
+			errUpdateUser:      nil,
+			errDeleteAttempt:   nil,
+			authToken:          http.Cookie{Name: "foo", Value: "bar"},
+			errEncodeAuth:      nil,
+			refreshToken:       http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusOK,
+			assertFunc:         assertMFALoggedIn,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			mfaDecoder.Res = c.mfaDecoded
+			mfaDecoder.Err = c.errDecodeMFA
+			attemptRetriever.Res = c.attempt
+			attemptRetriever.Err = c.errRetrieveAttempt
+			attemptIncrementer.Err = c.errIncrement
+			attemptDeleter.Err = c.errDeleteAttempt
+			audit.Args = nil
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
+			verifier.Err = c.errVerify
+			userUpdater.Err = c.errUpdateUser
+			authEncoder.Res = c.authToken
+			authEncoder.Err = c.errEncodeAuth
+			refreshEncoder.Res = c.refreshToken
+			refreshEncoder.Err = c.errEncodeRefresh
+			sessionInserter.Err = c.errInsertSession
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("", "/", strings.NewReader(
+				`{"code": "`+c.code+`"}`,
+			))
+			if c.mfaToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.MFAName, Value: c.mfaToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
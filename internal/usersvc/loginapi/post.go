@@ -4,13 +4,18 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
 
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
 )
 
 // PostReq defines the body of POST login requests.
@@ -19,29 +24,67 @@ type PostReq struct {
 	Password string `json:"password"`
 }
 
+// PostResp defines the body of POST login responses. It is only written when
+// the user must complete two-factor authentication, in which case MFA is the
+// step they must complete.
+type PostResp struct {
+	MFA string `json:"mfa"`
+}
+
 // PostHandler is a http.PostHandler that can be used to handle login requests.
 type PostHandler struct {
-	validator     ReqValidator
-	userRetriever db.Retriever[usertbl.User]
-	pwdComparator Comparator
-	authEncoder   cookie.Encoder[cookie.Auth]
-	log           log.Errorer
+	validator          ReqValidator
+	attemptRetriever   db.Retriever[attempttbl.Attempt]
+	attemptIncrementer db.Incrementer[attempttbl.Attempt]
+	attemptDeleter     db.Deleter
+	userRetriever      db.Retriever[usertbl.User]
+	pwdComparator      Comparator
+	rehasher           Rehasher
+	userUpdater        db.Updater[usertbl.User]
+	teamRetriever      db.Retriever[teamtbl.Team]
+	mfaEncoder         cookie.Encoder[cookie.MFA]
+	authEncoder        cookie.Encoder[cookie.Auth]
+	refreshEncoder     cookie.Encoder[cookie.Refresh]
+	sessionInserter    db.Inserter[sessiontbl.Session]
+	audit              log.Warner
+	log                log.Errorer
 }
 
-// NewPostHandler creates and returns a new Handler.
+// NewPostHandler creates and returns a new Handler. Failed and locked out login
+// attempts are logged to audit.
 func NewPostHandler(
 	validator ReqValidator,
+	attemptRetriever db.Retriever[attempttbl.Attempt],
+	attemptIncrementer db.Incrementer[attempttbl.Attempt],
+	attemptDeleter db.Deleter,
 	userRetriever db.Retriever[usertbl.User],
 	pwdComparator Comparator,
+	rehasher Rehasher,
+	userUpdater db.Updater[usertbl.User],
+	teamRetriever db.Retriever[teamtbl.Team],
+	mfaEncoder cookie.Encoder[cookie.MFA],
 	encodeAuth cookie.Encoder[cookie.Auth],
+	refreshEncoder cookie.Encoder[cookie.Refresh],
+	sessionInserter db.Inserter[sessiontbl.Session],
+	audit log.Warner,
 	log log.Errorer,
 ) PostHandler {
 	return PostHandler{
-		validator:     validator,
-		userRetriever: userRetriever,
-		pwdComparator: pwdComparator,
-		authEncoder:   encodeAuth,
-		log:           log,
+		validator:          validator,
+		attemptRetriever:   attemptRetriever,
+		attemptIncrementer: attemptIncrementer,
+		attemptDeleter:     attemptDeleter,
+		userRetriever:      userRetriever,
+		pwdComparator:      pwdComparator,
+		rehasher:           rehasher,
+		userUpdater:        userUpdater,
+		teamRetriever:      teamRetriever,
+		mfaEncoder:         mfaEncoder,
+		authEncoder:        encodeAuth,
+		refreshEncoder:     refreshEncoder,
+		sessionInserter:    sessionInserter,
+		audit:              audit,
+		log:                log,
 	}
 }
 
@@ -59,11 +102,29 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		return
 	}
 
+	// Reject the attempt if the username or the client IP is locked out due to
+	// too many failed attempts.
+	ip := clientIP(r)
+	wait, err := lockedOutFor(
+		r.Context(), h.attemptRetriever, req.Username, ip,
+	)
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if wait > 0 {
+		h.audit.Warn("locked out login attempt for", req.Username, "from", ip)
+		w.Header().Set("Retry-After", strconv.FormatInt(wait, 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
 	// Read the user in the database who owns the username that came in the
 	// request.
 	user, err := h.userRetriever.Retrieve(r.Context(), req.Username)
 	if errors.Is(err, db.ErrNoItem) {
-		w.WriteHeader(http.StatusBadRequest)
+		h.fail(w, r, req.Username, ip)
 		return
 	} else if err != nil {
 		h.log.Error(err)
@@ -75,8 +136,8 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 	// of the user from the database.
 	if err = h.pwdComparator.Compare(
 		user.Password, req.Password,
-	); errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-		w.WriteHeader(http.StatusBadRequest)
+	); errors.Is(err, password.ErrMismatch) {
+		h.fail(w, r, req.Username, ip)
 		return
 	} else if err != nil {
 		h.log.Error(err)
@@ -84,9 +145,55 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		return
 	}
 
+	// Upgrade the user's password hash if it was created with an outdated
+	// algorithm or parameters now that the plaintext password is known. This
+	// is only an upgrade, so the user is logged in even if it fails.
+	if hash, err := h.rehasher.Rehash(user.Password, req.Password); err != nil {
+		h.log.Error(err)
+	} else if hash != nil {
+		user.Password = hash
+		if err = h.userUpdater.Update(r.Context(), user); err != nil {
+			h.log.Error(err)
+		}
+	}
+
+	// Reset the failures for the username now that the user has proved they
+	// own it. The client IP's failures are left to expire so that logging into
+	// an owned account can't be used to keep guessing others' passwords.
+	if err = h.attemptDeleter.Delete(
+		r.Context(), attempttbl.UserID(user.Username),
+	); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// if the user has enabled two-factor authentication or their team requires
+	// it, issue an MFA token instead of the auth token for them to complete it
+	step, err := MFAStep(r.Context(), h.teamRetriever, user)
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if step != "" {
+		ckMFA, err := h.mfaEncoder.Encode(cookie.NewMFA(user.Username))
+		if err != nil {
+			h.log.Error(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		http.SetCookie(w, &ckMFA)
+		if err := json.NewEncoder(w).Encode(PostResp{MFA: step}); err != nil {
+			h.log.Error(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
 	// encode a new auth token
 	ckAuth, err := h.authEncoder.Encode(cookie.NewAuth(
-		user.Username, user.IsAdmin, user.TeamID,
+		user.Username, user.Role, user.TeamID,
 	))
 	if err != nil {
 		h.log.Error(err)
@@ -94,6 +201,41 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		return
 	}
 
-	// set auth token in cookie
+	// start a new session and encode a refresh token for it
+	familyID, tokenID := uuid.NewString(), uuid.NewString()
+	ckRefresh, err := h.refreshEncoder.Encode(
+		cookie.NewRefresh(familyID, tokenID),
+	)
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err = h.sessionInserter.Insert(r.Context(), sessiontbl.NewSession(
+		familyID, user.Username, tokenID, ckRefresh.Expires.Unix(),
+	)); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// set auth and refresh tokens in cookies
 	http.SetCookie(w, &ckAuth)
+	http.SetCookie(w, &ckRefresh)
+}
+
+// fail records a failed login attempt for the given username and client IP and
+// writes the response for it.
+func (h PostHandler) fail(
+	w http.ResponseWriter, r *http.Request, username, ip string,
+) {
+	h.audit.Warn("failed login attempt for", username, "from", ip)
+	if err := addFailure(
+		r.Context(), h.attemptIncrementer, username, ip,
+	); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusBadRequest)
 }
@@ -3,143 +3,670 @@
 package loginapi
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
-
-	"golang.org/x/crypto/bcrypt"
+	"time"
 
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
 )
 
 // TestHandler tests the ServeHTTP method of Handler to assert that it behaves
 // correctly.
 func TestPOSTHandler(t *testing.T) {
 	var (
-		validator        = &fakeReqValidator{}
-		userRetriever    = &db.FakeRetriever[usertbl.User]{}
-		passwordComparer = &fakeHashComparer{}
-		authEncoder      = &cookie.FakeEncoder[cookie.Auth]{}
-		log              = &log.FakeErrorer{}
+		validator          = &fakeReqValidator{}
+		attemptRetriever   = &db.FakeRetriever[attempttbl.Attempt]{}
+		attemptIncrementer = &db.FakeIncrementer[attempttbl.Attempt]{}
+		attemptDeleter     = &db.FakeDeleter{}
+		userRetriever      = &db.FakeRetriever[usertbl.User]{}
+		passwordComparer   = &fakeHashComparer{}
+		rehasher           = &fakeRehasher{}
+		userUpdater        = &db.FakeUpdater[usertbl.User]{}
+		teamRetriever      = &db.FakeRetriever[teamtbl.Team]{}
+		mfaEncoder         = &cookie.FakeEncoder[cookie.MFA]{}
+		authEncoder        = &cookie.FakeEncoder[cookie.Auth]{}
+		refreshEncoder     = &cookie.FakeEncoder[cookie.Refresh]{}
+		sessionInserter    = &db.FakeInserter[sessiontbl.Session]{}
+		audit              = &log.FakeWarner{}
+		log                = &log.FakeErrorer{}
 	)
 	sut := NewPostHandler(
-		validator, userRetriever, passwordComparer, authEncoder, log,
+		validator,
+		attemptRetriever,
+		attemptIncrementer,
+		attemptDeleter,
+		userRetriever,
+		passwordComparer,
+		rehasher,
+		userUpdater,
+		teamRetriever,
+		mfaEncoder,
+		authEncoder,
+		refreshEncoder,
+		sessionInserter,
+		audit,
+		log,
 	)
 
 	for _, c := range []struct {
-		name             string
-		reqIsValid       bool
-		user             usertbl.User
-		errRetrieveUser  error
-		errCompareHash   error
-		authToken        http.Cookie
-		errGenerateToken error
-		wantStatus       int
-		assertFunc       func(*testing.T, *http.Response, []any)
+		name               string
+		reqIsValid         bool
+		attempt            attempttbl.Attempt
+		errRetrieveAttempt error
+		errIncrement       error
+		errDeleteAttempt   error
+		user               usertbl.User
+		errRetrieveUser    error
+		errCompareHash     error
+		rehash             []byte
+		errRehash          error
+		errUpdateUser      error
+		team               teamtbl.Team
+		errRetrieveTeam    error
+		mfaToken           http.Cookie
+		errEncodeMFA       error
+		authToken          http.Cookie
+		errGenerateToken   error
+		refreshToken       http.Cookie
+		errEncodeRefresh   error
+		errInsertSession   error
+		wantStatus         int
+		assertFunc         func(*testing.T, *http.Response, []any)
 	}{
 		{
-			name:             "InvalidRequest",
-			reqIsValid:       false,
-			user:             usertbl.User{},
+			name:               "InvalidRequest",
+			reqIsValid:         false,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errCompareHash:     nil,
+			rehash:             nil,
+			errRehash:          nil,
+			errUpdateUser:      nil,
+			team:               teamtbl.Team{},
+			errRetrieveTeam:    nil,
+			mfaToken:           http.Cookie{},
+			errEncodeMFA:       nil,
+			authToken:          http.Cookie{},
+			errGenerateToken:   nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusBadRequest,
+			assertFunc:         func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:               "ErrRetrieveAttempt",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: errors.New("attempt retriever error"),
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errCompareHash:     nil,
+			rehash:             nil,
+			errRehash:          nil,
+			errUpdateUser:      nil,
+			team:               teamtbl.Team{},
+			errRetrieveTeam:    nil,
+			mfaToken:           http.Cookie{},
+			errEncodeMFA:       nil,
+			authToken:          http.Cookie{},
+			errGenerateToken:   nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("attempt retriever error"),
+		},
+		{
+			name:       "LockedOut",
+			reqIsValid: true,
+			attempt: attempttbl.Attempt{
+				Failures: 20, LastFailedAt: time.Now().Unix(),
+			},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errCompareHash:     nil,
+			rehash:             nil,
+			errRehash:          nil,
+			errUpdateUser:      nil,
+			team:               teamtbl.Team{},
+			errRetrieveTeam:    nil,
+			mfaToken:           http.Cookie{},
+			errEncodeMFA:       nil,
+			authToken:          http.Cookie{},
+			errGenerateToken:   nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusTooManyRequests,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.True(t.Error, resp.Header.Get("Retry-After") != "")
+				assert.Equal(t.Error,
+					audit.Args[0], "locked out login attempt for",
+				)
+			},
+		},
+		{
+			name:               "UserNotFound",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    db.ErrNoItem,
+			errCompareHash:     nil,
+			rehash:             nil,
+			errRehash:          nil,
+			errUpdateUser:      nil,
+			team:               teamtbl.Team{},
+			errRetrieveTeam:    nil,
+			mfaToken:           http.Cookie{},
+			errEncodeMFA:       nil,
+			authToken:          http.Cookie{},
+			errGenerateToken:   nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusBadRequest,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error, audit.Args[0], "failed login attempt for")
+			},
+		},
+		{
+			name:               "ErrIncrement",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       errors.New("attempt incrementer error"),
+			errDeleteAttempt:   nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    db.ErrNoItem,
+			errCompareHash:     nil,
+			rehash:             nil,
+			errRehash:          nil,
+			errUpdateUser:      nil,
+			team:               teamtbl.Team{},
+			errRetrieveTeam:    nil,
+			mfaToken:           http.Cookie{},
+			errEncodeMFA:       nil,
+			authToken:          http.Cookie{},
+			errGenerateToken:   nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("attempt incrementer error"),
+		},
+		{
+			name:               "UserSelectorError",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    errors.New("user selector error"),
+			errCompareHash:     nil,
+			rehash:             nil,
+			errRehash:          nil,
+			errUpdateUser:      nil,
+			team:               teamtbl.Team{},
+			errRetrieveTeam:    nil,
+			mfaToken:           http.Cookie{},
+			errEncodeMFA:       nil,
+			authToken:          http.Cookie{},
+			errGenerateToken:   nil,
+			refreshToken:       http.Cookie{},
+			errEncodeRefresh:   nil,
+			errInsertSession:   nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("user selector error"),
+		},
+		{
+			name:               "WrongPassword",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
 			errRetrieveUser:  nil,
-			errCompareHash:   nil,
+			errCompareHash:   password.ErrMismatch,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
 			wantStatus:       http.StatusBadRequest,
-			assertFunc:       func(*testing.T, *http.Response, []any) {},
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.Equal(t.Error, audit.Args[0], "failed login attempt for")
+			},
 		},
 		{
-			name:             "UserNotFound",
-			reqIsValid:       true,
-			user:             usertbl.User{},
-			errRetrieveUser:  db.ErrNoItem,
-			errCompareHash:   nil,
+			name:               "HashComparerError",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   errors.New("hash comparer error"),
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
-			wantStatus:       http.StatusBadRequest,
-			assertFunc:       func(*testing.T, *http.Response, []any) {},
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("hash comparer error"),
 		},
 		{
-			name:             "UserSelectorError",
-			reqIsValid:       true,
-			user:             usertbl.User{},
-			errRetrieveUser:  errors.New("user selector error"),
+			name:               "ErrDeleteAttempt",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   errors.New("attempt deleter error"),
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
 			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
 			wantStatus:       http.StatusInternalServerError,
-			assertFunc:       assert.OnLoggedErr("user selector error"),
+			assertFunc:       assert.OnLoggedErr("attempt deleter error"),
 		},
 		{
-			name:       "WrongPassword",
-			reqIsValid: true,
+			name:               "ErrRetrieveTeam",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
 			user: usertbl.User{
 				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
 			},
 			errRetrieveUser:  nil,
-			errCompareHash:   bcrypt.ErrMismatchedHashAndPassword,
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  errors.New("team retriever error"),
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
-			wantStatus:       http.StatusBadRequest,
-			assertFunc:       func(*testing.T, *http.Response, []any) {},
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("team retriever error"),
 		},
 		{
-			name:       "HashComparerError",
-			reqIsValid: true,
+			name:               "ErrEncodeMFA",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
 			user: usertbl.User{
-				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+				Username:   "bob123",
+				Password:   []byte("$2a$ASasdflak$kajdsfh"),
+				MFAEnabled: true,
 			},
 			errRetrieveUser:  nil,
-			errCompareHash:   errors.New("hash comparer error"),
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     errors.New("mfa encoder error"),
 			authToken:        http.Cookie{},
 			errGenerateToken: nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
 			wantStatus:       http.StatusInternalServerError,
-			assertFunc:       assert.OnLoggedErr("hash comparer error"),
+			assertFunc:       assert.OnLoggedErr("mfa encoder error"),
 		},
 		{
-			name:       "TokenGeneratorError",
-			reqIsValid: true,
+			name:               "MFAVerify",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username:   "bob123",
+				Password:   []byte("$2a$ASasdflak$kajdsfh"),
+				MFAEnabled: true,
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{Name: "mfa", Value: "token"},
+			errEncodeMFA:     nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errGenerateToken: nil,
+			refreshToken:     http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusOK,
+			assertFunc:       assertMFAStep(MFAVerify),
+		},
+		{
+			name:               "MFAEnroll",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{RequireMFA: true},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{Name: "mfa", Value: "token"},
+			errEncodeMFA:     nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errGenerateToken: nil,
+			refreshToken:     http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusOK,
+			assertFunc:       assertMFAStep(MFAEnroll),
+		},
+		{
+			name:               "TokenGeneratorError",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
 			user: usertbl.User{
 				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
 			},
 			errRetrieveUser:  nil,
 			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
 			authToken:        http.Cookie{},
 			errGenerateToken: errors.New("token generator error"),
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
 			wantStatus:       http.StatusInternalServerError,
 			assertFunc:       assert.OnLoggedErr("token generator error"),
 		},
 		{
-			name:       "Success",
-			reqIsValid: true,
+			name:               "RefreshEncoderError",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
+			authToken:        http.Cookie{},
+			errGenerateToken: nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: errors.New("refresh encoder error"),
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("refresh encoder error"),
+		},
+		{
+			name:               "SessionInserterError",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
+			authToken:        http.Cookie{},
+			errGenerateToken: nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: errors.New("session inserter error"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("session inserter error"),
+		},
+		{
+			name:               "SuccessNoTeam",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  db.ErrNoItem,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errGenerateToken: nil,
+			refreshToken:     http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[0].Name, "foo")
+				assert.Equal(t.Error, cks[0].Value, "bar")
+				assert.Equal(t.Error, cks[1].Name, "baz")
+				assert.Equal(t.Error, cks[1].Value, "qux")
+			},
+		},
+		{
+			name:               "ErrRehash",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        errors.New("rehash failed"),
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{ID: "bob123"},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errGenerateToken: nil,
+			refreshToken:     http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, args []any) {
+				assert.OnLoggedErr("rehash failed")(t, resp, args)
+				assert.Equal(t.Error, len(resp.Cookies()), 2)
+			},
+		},
+		{
+			name:               "ErrUpdateUser",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
 			user: usertbl.User{
 				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
 			},
 			errRetrieveUser:  nil,
 			errCompareHash:   nil,
+			rehash:           []byte("$argon2id$v=19$m=64,t=1,p=1$salt$key"),
+			errRehash:        nil,
+			errUpdateUser:    errors.New("update user failed"),
+			team:             teamtbl.Team{ID: "bob123"},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
 			authToken:        http.Cookie{Name: "foo", Value: "bar"},
 			errGenerateToken: nil,
+			refreshToken:     http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, args []any) {
+				assert.OnLoggedErr("update user failed")(t, resp, args)
+				assert.Equal(t.Error, len(resp.Cookies()), 2)
+			},
+		},
+		{
+			name:               "Success",
+			reqIsValid:         true,
+			attempt:            attempttbl.Attempt{},
+			errRetrieveAttempt: nil,
+			errIncrement:       nil,
+			errDeleteAttempt:   nil,
+			user: usertbl.User{
+				Username: "bob123", Password: []byte("$2a$ASasdflak$kajdsfh"),
+			},
+			errRetrieveUser:  nil,
+			errCompareHash:   nil,
+			rehash:           nil,
+			errRehash:        nil,
+			errUpdateUser:    nil,
+			team:             teamtbl.Team{ID: "bob123"},
+			errRetrieveTeam:  nil,
+			mfaToken:         http.Cookie{},
+			errEncodeMFA:     nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errGenerateToken: nil,
+			refreshToken:     http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
 			wantStatus:       http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
-				ck := resp.Cookies()[0]
-				assert.Equal(t.Error, ck.Name, "foo")
-				assert.Equal(t.Error, ck.Value, "bar")
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[0].Name, "foo")
+				assert.Equal(t.Error, cks[0].Value, "bar")
+				assert.Equal(t.Error, cks[1].Name, "baz")
+				assert.Equal(t.Error, cks[1].Value, "qux")
 			},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			validator.isValid = c.reqIsValid
+			attemptRetriever.Res = c.attempt
+			attemptRetriever.Err = c.errRetrieveAttempt
+			attemptIncrementer.Err = c.errIncrement
+			attemptDeleter.Err = c.errDeleteAttempt
+			audit.Args = nil
 			userRetriever.Res = c.user
 			userRetriever.Err = c.errRetrieveUser
 			passwordComparer.err = c.errCompareHash
+			rehasher.hash = c.rehash
+			rehasher.err = c.errRehash
+			userUpdater.Err = c.errUpdateUser
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			mfaEncoder.Res = c.mfaToken
+			mfaEncoder.Err = c.errEncodeMFA
 			authEncoder.Res = c.authToken
 			authEncoder.Err = c.errGenerateToken
+			refreshEncoder.Res = c.refreshToken
+			refreshEncoder.Err = c.errEncodeRefresh
+			sessionInserter.Err = c.errInsertSession
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("", "/", strings.NewReader("{}"))
 
@@ -151,3 +678,21 @@ func TestPOSTHandler(t *testing.T) {
 		})
 	}
 }
+
+// assertMFAStep returns a function that asserts that only the MFA token was
+// set and the response body has the given MFA step.
+func assertMFAStep(
+	wantStep string,
+) func(*testing.T, *http.Response, []any) {
+	return func(t *testing.T, resp *http.Response, _ []any) {
+		cks := resp.Cookies()
+		assert.Equal(t.Fatal, len(cks), 1)
+		assert.Equal(t.Error, cks[0].Name, "mfa")
+		assert.Equal(t.Error, cks[0].Value, "token")
+
+		var body PostResp
+		err := json.NewDecoder(resp.Body).Decode(&body)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, body.MFA, wantStep)
+	}
+}
@@ -0,0 +1,102 @@
+package loginapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+)
+
+const (
+	// userFreeFailures is the number of failed login attempts allowed for a
+	// username before it is locked out.
+	userFreeFailures = 5
+
+	// ipFreeFailures is the number of failed login attempts allowed from a
+	// client IP before it is locked out. It is higher than userFreeFailures
+	// since many users can share the same IP.
+	ipFreeFailures = 20
+
+	// baseLockout is the number of seconds a username or client IP is locked
+	// out for on the first failure after its free failures. It is doubled on
+	// each subsequent failure up to maxLockout.
+	baseLockout = 30
+
+	// maxLockout is the maximum number of seconds a username or client IP can
+	// be locked out for.
+	maxLockout = 60 * 60
+)
+
+// retryAfter returns the number of seconds until another login attempt can be
+// made for the given attempt counter, or 0 if it is not locked out.
+func retryAfter(attempt attempttbl.Attempt, freeFailures int, now int64) int64 {
+	if attempt.Failures < freeFailures {
+		return 0
+	}
+
+	lockout := int64(maxLockout)
+	if excess := attempt.Failures - freeFailures; excess < 7 {
+		lockout = min(int64(baseLockout)<<excess, maxLockout)
+	}
+
+	if until := attempt.LastFailedAt + lockout; until > now {
+		return until - now
+	}
+	return 0
+}
+
+// lockedOutFor returns the number of seconds until the given username and
+// client IP can make another login attempt, or 0 if neither is locked out.
+func lockedOutFor(
+	ctx context.Context,
+	attemptRetriever db.Retriever[attempttbl.Attempt],
+	username, ip string,
+) (int64, error) {
+	now := time.Now().Unix()
+	var wait int64
+	for id, freeFailures := range map[string]int{
+		attempttbl.UserID(username): userFreeFailures,
+		attempttbl.IPID(ip):         ipFreeFailures,
+	} {
+		attempt, err := attemptRetriever.Retrieve(ctx, id)
+		if errors.Is(err, db.ErrNoItem) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		wait = max(wait, retryAfter(attempt, freeFailures, now))
+	}
+	return wait, nil
+}
+
+// addFailure records a failed login attempt for the given username and client
+// IP.
+func addFailure(
+	ctx context.Context,
+	attemptIncrementer db.Incrementer[attempttbl.Attempt],
+	username, ip string,
+) error {
+	for _, id := range []string{
+		attempttbl.UserID(username), attempttbl.IPID(ip),
+	} {
+		if _, err := attemptIncrementer.Increment(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clientIP returns the IP of the client that made the request. The
+// X-Forwarded-For header is not used since it can be set by the client to get
+// around the IP lockout.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
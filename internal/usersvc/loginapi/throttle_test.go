@@ -0,0 +1,70 @@
+//go:build utest
+
+package loginapi
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+)
+
+func TestRetryAfter(t *testing.T) {
+	const now = 1700000000
+
+	for _, c := range []struct {
+		name    string
+		attempt attempttbl.Attempt
+		want    int64
+	}{
+		{
+			name:    "NoFailures",
+			attempt: attempttbl.Attempt{},
+			want:    0,
+		},
+		{
+			name:    "UnderFreeFailures",
+			attempt: attempttbl.Attempt{Failures: 4, LastFailedAt: now},
+			want:    0,
+		},
+		{
+			name:    "FirstLockout",
+			attempt: attempttbl.Attempt{Failures: 5, LastFailedAt: now - 10},
+			want:    20,
+		},
+		{
+			name:    "Doubled",
+			attempt: attempttbl.Attempt{Failures: 7, LastFailedAt: now},
+			want:    120,
+		},
+		{
+			name:    "Capped",
+			attempt: attempttbl.Attempt{Failures: 50, LastFailedAt: now},
+			want:    3600,
+		},
+		{
+			name:    "LockoutPassed",
+			attempt: attempttbl.Attempt{Failures: 6, LastFailedAt: now - 60},
+			want:    0,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got := retryAfter(c.attempt, 5, now)
+			assert.Equal(t.Error, got, c.want)
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	r := httptest.NewRequest("", "/", nil)
+
+	r.RemoteAddr = "192.0.2.1:1234"
+	assert.Equal(t.Error, clientIP(r), "192.0.2.1")
+
+	r.RemoteAddr = "[2001:db8::1]:1234"
+	assert.Equal(t.Error, clientIP(r), "2001:db8::1")
+
+	r.RemoteAddr = "192.0.2.1"
+	assert.Equal(t.Error, clientIP(r), "192.0.2.1")
+}
@@ -0,0 +1,4 @@
+// Package logoutapi contains code for responding to HTTP requests made to the
+// logout API route, which is used for logging out a user on the device that
+// made the request.
+package logoutapi
@@ -0,0 +1,76 @@
+package logoutapi
+
+import (
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PostHandler is an api.MethodHandler that can be used to handle POST logout
+// requests.
+type PostHandler struct {
+	authDecoder        cookie.Decoder[cookie.Auth]
+	refreshDecoder     cookie.Decoder[cookie.Refresh]
+	revocationInserter db.Inserter[revocationtbl.Revocation]
+	sessionDeleter     db.Deleter
+	log                log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	refreshDecoder cookie.Decoder[cookie.Refresh],
+	revocationInserter db.Inserter[revocationtbl.Revocation],
+	sessionDeleter db.Deleter,
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		authDecoder:        authDecoder,
+		refreshDecoder:     refreshDecoder,
+		revocationInserter: revocationInserter,
+		sessionDeleter:     sessionDeleter,
+		log:                log,
+	}
+}
+
+// Handle handles the POST requests sent to the logout route. Tokens that are
+// missing or can't be decoded are skipped since they can't be used anyway.
+func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// revoke the auth token until it expires
+	if ckAuth, err := cookie.GetAuth(r); err == nil {
+		auth, err := h.authDecoder.Decode(*ckAuth)
+		if err == nil && auth.TokenID != "" {
+			if err = h.revocationInserter.Insert(
+				r.Context(), revocationtbl.NewTokenRevocation(
+					auth.Username, auth.TokenID, auth.ExpiresAt,
+				),
+			); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+				return
+			}
+		}
+	}
+
+	// end the session that the refresh token belongs to
+	if ckRefresh, err := r.Cookie(cookie.RefreshName); err == nil {
+		if ref, err := h.refreshDecoder.Decode(*ckRefresh); err == nil {
+			if err = h.sessionDeleter.Delete(
+				r.Context(), ref.FamilyID,
+			); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+				return
+			}
+		}
+	}
+
+	// delete auth and refresh cookies on the client
+	ckAuth := cookie.NewExpired(cookie.AuthName)
+	ckRefresh := cookie.NewExpired(cookie.RefreshName)
+	http.SetCookie(w, &ckAuth)
+	http.SetCookie(w, &ckRefresh)
+}
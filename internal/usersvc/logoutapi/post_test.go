@@ -0,0 +1,140 @@
+//go:build utest
+
+package logoutapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	refreshDecoder := &cookie.FakeDecoder[cookie.Refresh]{}
+	revocationInserter := &db.FakeInserter[revocationtbl.Revocation]{}
+	sessionDeleter := &db.FakeDeleter{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		authDecoder, refreshDecoder, revocationInserter, sessionDeleter, log,
+	)
+
+	// assertCookiesDeleted asserts that both the auth and the refresh cookies
+	// were deleted on the client.
+	assertCookiesDeleted := func(t *testing.T, resp *http.Response, _ []any) {
+		cks := resp.Cookies()
+		assert.Equal(t.Fatal, len(cks), 2)
+		assert.Equal(t.Error, cks[0].Name, cookie.AuthName)
+		assert.True(t.Error, cks[0].MaxAge < 0)
+		assert.Equal(t.Error, cks[1].Name, cookie.RefreshName)
+		assert.True(t.Error, cks[1].MaxAge < 0)
+	}
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		authDecoded   cookie.Auth
+		errInsert     error
+		refreshToken  string
+		errDecodeRef  error
+		errDelete     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoTokens",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			errInsert:     nil,
+			refreshToken:  "",
+			errDecodeRef:  nil,
+			errDelete:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    assertCookiesDeleted,
+		},
+		{
+			name:          "InvalidTokens",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			authDecoded:   cookie.Auth{},
+			errInsert:     errors.New("insert revocation failed"),
+			refreshToken:  "nonempty",
+			errDecodeRef:  cookie.ErrInvalid,
+			errDelete:     errors.New("delete session failed"),
+			wantStatus:    http.StatusOK,
+			assertFunc:    assertCookiesDeleted,
+		},
+		{
+			name:          "ErrInsert",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Username: "bob123", TokenID: "id"},
+			errInsert:     errors.New("insert revocation failed"),
+			refreshToken:  "",
+			errDecodeRef:  nil,
+			errDelete:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("insert revocation failed"),
+		},
+		{
+			name:          "ErrDelete",
+			authToken:     "",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{},
+			errInsert:     nil,
+			refreshToken:  "nonempty",
+			errDecodeRef:  nil,
+			errDelete:     errors.New("delete session failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("delete session failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			authDecoded:   cookie.Auth{Username: "bob123", TokenID: "id"},
+			errInsert:     nil,
+			refreshToken:  "nonempty",
+			errDecodeRef:  nil,
+			errDelete:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    assertCookiesDeleted,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = c.authDecoded
+			authDecoder.Err = c.errDecodeAuth
+			revocationInserter.Err = c.errInsert
+			refreshDecoder.Err = c.errDecodeRef
+			sessionDeleter.Err = c.errDelete
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+			if c.refreshToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.RefreshName, Value: c.refreshToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Package mfaapi contains code for responding to HTTP requests made to the MFA
+// API route, which is used by users for enrolling in two-factor
+// authentication.
+package mfaapi
+
+import (
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+)
+
+// issuer is the name that authenticator apps show the user's account under.
+const issuer = "GoTeam!"
+
+// identify returns the username of the user who made the request from their
+// auth token. If there is none, it is read from their MFA token instead, which
+// is issued on logging in to users whose team requires two-factor
+// authentication for them to be able to enroll in it, and isMFA is true.
+func identify(
+	r *http.Request,
+	authDecoder cookie.Decoder[cookie.Auth],
+	mfaDecoder cookie.Decoder[cookie.MFA],
+) (username string, isMFA bool, err error) {
+	if ckAuth, err := cookie.GetAuth(r); err == nil {
+		auth, err := authDecoder.Decode(*ckAuth)
+		return auth.Username, false, err
+	}
+
+	ckMFA, err := r.Cookie(cookie.MFAName)
+	if err != nil {
+		return "", false, err
+	}
+	mfaToken, err := mfaDecoder.Decode(*ckMFA)
+	return mfaToken.Username, true, err
+}
@@ -0,0 +1,235 @@
+package mfaapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mfa"
+)
+
+// PatchReq defines the body of PATCH MFA requests.
+type PatchReq struct {
+	Code string `json:"code"`
+}
+
+// PatchResp defines the body of PATCH MFA responses.
+type PatchResp struct {
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can be used to handle PATCH MFA
+// requests, which are used for completing the enrollment in two-factor
+// authentication by verifying a code generated with the secret.
+type PatchHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	mfaDecoder      cookie.Decoder[cookie.MFA]
+	userRetriever   db.Retriever[usertbl.User]
+	verifier        mfa.Verifier
+	userUpdater     db.Updater[usertbl.User]
+	authEncoder     cookie.Encoder[cookie.Auth]
+	refreshEncoder  cookie.Encoder[cookie.Refresh]
+	sessionInserter db.Inserter[sessiontbl.Session]
+	log             log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	mfaDecoder cookie.Decoder[cookie.MFA],
+	userRetriever db.Retriever[usertbl.User],
+	verifier mfa.Verifier,
+	userUpdater db.Updater[usertbl.User],
+	authEncoder cookie.Encoder[cookie.Auth],
+	refreshEncoder cookie.Encoder[cookie.Refresh],
+	sessionInserter db.Inserter[sessiontbl.Session],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		authDecoder:     authDecoder,
+		mfaDecoder:      mfaDecoder,
+		userRetriever:   userRetriever,
+		verifier:        verifier,
+		userUpdater:     userUpdater,
+		authEncoder:     authEncoder,
+		refreshEncoder:  refreshEncoder,
+		sessionInserter: sessionInserter,
+		log:             log,
+	}
+}
+
+// Handle handles the PATCH requests sent to the MFA route.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get the username from the auth or MFA token
+	username, isMFA, err := identify(r, h.authDecoder, h.mfaDecoder)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode and validate request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if req.Code == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Code cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve user
+	user, err := h.userRetriever.Retrieve(r.Context(), username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if user.MFAEnabled {
+		w.WriteHeader(http.StatusConflict)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Two-factor authentication is already enabled.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if user.MFASecret == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Two-factor authentication enrollment was not started.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// verify the code to make sure the user saved the secret correctly
+	step, err := h.verifier.Verify(user.MFASecret, req.Code, user.MFALastStep)
+	if errors.Is(err, mfa.ErrInvalidCode) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Invalid code.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// enable two-factor authentication with a new set of recovery codes
+	codes, hashes, err := mfa.NewRecoveryCodes()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	user.MFAEnabled = true
+	user.MFALastStep = step
+	user.RecoveryCodes = hashes
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// if the user enrolled while logging in, they have now completed
+	// two-factor authentication, so start a new session for them
+	if isMFA {
+		ckAuth, err := h.authEncoder.Encode(cookie.NewAuth(
+			user.Username, user.Role, user.TeamID,
+		))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		familyID, tokenID := uuid.NewString(), uuid.NewString()
+		ckRefresh, err := h.refreshEncoder.Encode(
+			cookie.NewRefresh(familyID, tokenID),
+		)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		if err = h.sessionInserter.Insert(r.Context(), sessiontbl.NewSession(
+			familyID, user.Username, tokenID, ckRefresh.Expires.Unix(),
+		)); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+
+		ckExpired := cookie.NewExpired(cookie.MFAName)
+		http.SetCookie(w, &ckExpired)
+		http.SetCookie(w, &ckAuth)
+		http.SetCookie(w, &ckRefresh)
+	}
+
+	// respond with the recovery codes, which are only shown to the user once
+	if err = json.NewEncoder(w).Encode(PatchResp{
+		RecoveryCodes: codes,
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
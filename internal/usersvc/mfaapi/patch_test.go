@@ -0,0 +1,421 @@
+//go:build utest
+
+package mfaapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mfa"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	mfaDecoder := &cookie.FakeDecoder[cookie.MFA]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	verifier := &mfa.FakeVerifier{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	authEncoder := &cookie.FakeEncoder[cookie.Auth]{}
+	refreshEncoder := &cookie.FakeEncoder[cookie.Refresh]{}
+	sessionInserter := &db.FakeInserter[sessiontbl.Session]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(
+		authDecoder,
+		mfaDecoder,
+		userRetriever,
+		verifier,
+		userUpdater,
+		authEncoder,
+		refreshEncoder,
+		sessionInserter,
+		log,
+	)
+
+	authDecoder.Res = cookie.Auth{Username: "bob123"}
+	mfaDecoder.Res = cookie.MFA{Username: "bob123"}
+	authEncoder.Res = http.Cookie{Name: cookie.AuthName, Value: "auth"}
+	refreshEncoder.Res = http.Cookie{Name: cookie.RefreshName, Value: "ref"}
+	user := usertbl.User{Username: "bob123", MFASecret: "SECRET"}
+	validReq := `{"code": "123456"}`
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		mfaToken      string
+		errDecodeMFA  error
+		reqBody       string
+		user          usertbl.User
+		errRetrieve   error
+		errVerify     error
+		errUpdate     error
+		errEncodeAuth error
+		errEncodeRef  error
+		errInsert     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "InvalidMFA",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "nonempty",
+			errDecodeMFA:  cookie.ErrInvalid,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "CodeEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       `{"code": ""}`,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Code cannot be empty."),
+		},
+		{
+			name:          "UserNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          usertbl.User{},
+			errRetrieve:   db.ErrNoItem,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          usertbl.User{},
+			errRetrieve:   errors.New("retrieve failed"),
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "MFAEnabled",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          usertbl.User{MFASecret: "SECRET", MFAEnabled: true},
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Two-factor authentication is already enabled.",
+			),
+		},
+		{
+			name:          "NotStarted",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Two-factor authentication enrollment was not started.",
+			),
+		},
+		{
+			name:          "InvalidCode",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     mfa.ErrInvalidCode,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Invalid code."),
+		},
+		{
+			name:          "ErrVerify",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     errors.New("verify failed"),
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("verify failed"),
+		},
+		{
+			name:          "UserNotFoundUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     db.ErrNoItem,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     errors.New("update failed"),
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:          "ErrEncodeAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "nonempty",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: errors.New("encode auth failed"),
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("encode auth failed"),
+		},
+		{
+			name:          "ErrEncodeRefresh",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "nonempty",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  errors.New("encode refresh failed"),
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("encode refresh failed"),
+		},
+		{
+			name:          "ErrInsertSession",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "nonempty",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     errors.New("insert failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("insert failed"),
+		},
+		{
+			name:          "Success",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assertRecoveryCodes(t, resp)
+
+				// the user is already logged in so no cookies must be set
+				assert.Equal(t.Error, len(resp.Cookies()), 0)
+			},
+		},
+		{
+			name:          "SuccessMFAToken",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "nonempty",
+			errDecodeMFA:  nil,
+			reqBody:       validReq,
+			user:          user,
+			errRetrieve:   nil,
+			errVerify:     nil,
+			errUpdate:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assertRecoveryCodes(t, resp)
+
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 3)
+				assert.Equal(t.Error, cks[0].Name, cookie.MFAName)
+				assert.Equal(t.Error, cks[0].MaxAge, -1)
+				assert.Equal(t.Error, cks[1].Name, cookie.AuthName)
+				assert.Equal(t.Error, cks[1].Value, "auth")
+				assert.Equal(t.Error, cks[2].Name, cookie.RefreshName)
+				assert.Equal(t.Error, cks[2].Value, "ref")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			mfaDecoder.Err = c.errDecodeMFA
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			verifier.Err = c.errVerify
+			userUpdater.Err = c.errUpdate
+			authEncoder.Err = c.errEncodeAuth
+			refreshEncoder.Err = c.errEncodeRef
+			sessionInserter.Err = c.errInsert
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch, "/user/mfa", strings.NewReader(c.reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+			if c.mfaToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.MFAName, Value: c.mfaToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
+
+// assertRecoveryCodes asserts that the response body has a full set of
+// recovery codes.
+func assertRecoveryCodes(t *testing.T, resp *http.Response) {
+	var body PatchResp
+	err := json.NewDecoder(resp.Body).Decode(&body)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(body.RecoveryCodes), 10)
+}
@@ -0,0 +1,136 @@
+package mfaapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mfa"
+)
+
+// PostResp defines the body of POST MFA responses.
+type PostResp struct {
+	Secret string `json:"secret,omitempty"`
+	URI    string `json:"uri,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can be used to handle POST MFA
+// requests, which are used for starting the enrollment in two-factor
+// authentication.
+type PostHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	mfaDecoder      cookie.Decoder[cookie.MFA]
+	userRetriever   db.Retriever[usertbl.User]
+	secretGenerator mfa.SecretGenerator
+	userUpdater     db.Updater[usertbl.User]
+	log             log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	mfaDecoder cookie.Decoder[cookie.MFA],
+	userRetriever db.Retriever[usertbl.User],
+	secretGenerator mfa.SecretGenerator,
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		authDecoder:     authDecoder,
+		mfaDecoder:      mfaDecoder,
+		userRetriever:   userRetriever,
+		secretGenerator: secretGenerator,
+		userUpdater:     userUpdater,
+		log:             log,
+	}
+}
+
+// Handle handles the POST requests sent to the MFA route.
+func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get the username from the auth or MFA token
+	username, _, err := identify(r, h.authDecoder, h.mfaDecoder)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve user
+	user, err := h.userRetriever.Retrieve(r.Context(), username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if user.MFAEnabled {
+		w.WriteHeader(http.StatusConflict)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Two-factor authentication is already enabled.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// generate a new secret and save it for the user to verify a code for -
+	// starting over replaces any secret from an unfinished enrollment
+	if user.MFASecret, err = h.secretGenerator.NewSecret(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// respond with the secret for the user to add to their authenticator app
+	if err = json.NewEncoder(w).Encode(PostResp{
+		Secret: user.MFASecret,
+		URI:    mfa.URI(issuer, user.Username, user.MFASecret),
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,236 @@
+//go:build utest
+
+package mfaapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mfa"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	mfaDecoder := &cookie.FakeDecoder[cookie.MFA]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	secretGenerator := &mfa.FakeSecretGenerator{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		authDecoder,
+		mfaDecoder,
+		userRetriever,
+		secretGenerator,
+		userUpdater,
+		log,
+	)
+
+	authDecoder.Res = cookie.Auth{Username: "bob123"}
+	mfaDecoder.Res = cookie.MFA{Username: "bob123"}
+	secretGenerator.Res = "SECRET"
+
+	assertSecret := func(t *testing.T, resp *http.Response, _ []any) {
+		var body PostResp
+		err := json.NewDecoder(resp.Body).Decode(&body)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, body.Secret, "SECRET")
+		assert.Equal(t.Error, body.URI, mfa.URI(issuer, "bob123", "SECRET"))
+	}
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		mfaToken      string
+		errDecodeMFA  error
+		user          usertbl.User
+		errRetrieve   error
+		errSecret     error
+		errUpdate     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errSecret:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errSecret:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "InvalidMFA",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "nonempty",
+			errDecodeMFA:  cookie.ErrInvalid,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errSecret:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "UserNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{},
+			errRetrieve:   db.ErrNoItem,
+			errSecret:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{},
+			errRetrieve:   errors.New("retrieve failed"),
+			errSecret:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "MFAEnabled",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{Username: "bob123", MFAEnabled: true},
+			errRetrieve:   nil,
+			errSecret:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"Two-factor authentication is already enabled.",
+			),
+		},
+		{
+			name:          "ErrSecret",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errSecret:     errors.New("secret failed"),
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("secret failed"),
+		},
+		{
+			name:          "UserNotFoundUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errSecret:     nil,
+			errUpdate:     db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errSecret:     nil,
+			errUpdate:     errors.New("update failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update failed"),
+		},
+		{
+			name:          "Success",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			mfaToken:      "",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errSecret:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    assertSecret,
+		},
+		{
+			name:          "SuccessMFAToken",
+			authToken:     "",
+			errDecodeAuth: nil,
+			mfaToken:      "nonempty",
+			errDecodeMFA:  nil,
+			user:          usertbl.User{Username: "bob123"},
+			errRetrieve:   nil,
+			errSecret:     nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc:    assertSecret,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			mfaDecoder.Err = c.errDecodeMFA
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			secretGenerator.Err = c.errSecret
+			userUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/user/mfa", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+			if c.mfaToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.MFAName, Value: c.mfaToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package oidcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/oidc"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// Error codes that the user is redirected to the client's login page with when
+// an OIDC login fails.
+const (
+	// ErrCodeInvalid means that the login could not be verified, either
+	// because the identity provider rejected it or because the callback did
+	// not match the login it claimed to be for.
+	ErrCodeInvalid = "oidc_invalid"
+
+	// ErrCodeUsername means that no valid username could be derived from the
+	// identity provider account.
+	ErrCodeUsername = "oidc_username_invalid"
+
+	// ErrCodeTaken means that the username derived from the identity provider
+	// account belongs to a user who could not be proven to be its owner. The
+	// user can still log in to that user and link the account from there.
+	ErrCodeTaken = "oidc_username_taken"
+
+	// ErrCodeLinked means that the identity provider account that the user
+	// tried to link to their user is already linked to another one.
+	ErrCodeLinked = "oidc_identity_linked"
+
+	// ErrCodeServer means that the login failed due to a server error.
+	ErrCodeServer = "oidc_failed"
+)
+
+// errLogin is used internally to carry the error code that a login failed
+// with.
+type errLogin struct{ code string }
+
+// Error implements the error interface on errLogin.
+func (e errLogin) Error() string { return e.code }
+
+// CallbackHandler is an api.MethodHandler that can be used to handle GET OIDC
+// callback requests, which the identity provider redirects the user to after
+// they log in there.
+type CallbackHandler struct {
+	oidcDecoder       cookie.Decoder[cookie.OIDC]
+	authenticator     oidc.Authenticator
+	identityRetriever db.Retriever[identitytbl.Identity]
+	identityInserter  db.Inserter[identitytbl.Identity]
+	userRetriever     db.Retriever[usertbl.User]
+	usernameValidator registerapi.StrValidator
+	userInserter      db.Inserter[usertbl.User]
+	memberInserter    db.Inserter[membershiptbl.Membership]
+	teamRetriever     db.Retriever[teamtbl.Team]
+	mfaEncoder        cookie.Encoder[cookie.MFA]
+	authEncoder       cookie.Encoder[cookie.Auth]
+	refreshEncoder    cookie.Encoder[cookie.Refresh]
+	sessionInserter   db.Inserter[sessiontbl.Session]
+	clientURL         string
+	log               log.Errorer
+}
+
+// NewCallbackHandler creates and returns a new CallbackHandler. clientURL is
+// the URL of the client app that the user is redirected to after the login.
+// Users who must complete two-factor authentication are redirected to its
+// login page instead with the MFA step in the mfa query parameter.
+func NewCallbackHandler(
+	oidcDecoder cookie.Decoder[cookie.OIDC],
+	authenticator oidc.Authenticator,
+	identityRetriever db.Retriever[identitytbl.Identity],
+	identityInserter db.Inserter[identitytbl.Identity],
+	userRetriever db.Retriever[usertbl.User],
+	usernameValidator registerapi.StrValidator,
+	userInserter db.Inserter[usertbl.User],
+	memberInserter db.Inserter[membershiptbl.Membership],
+	teamRetriever db.Retriever[teamtbl.Team],
+	mfaEncoder cookie.Encoder[cookie.MFA],
+	authEncoder cookie.Encoder[cookie.Auth],
+	refreshEncoder cookie.Encoder[cookie.Refresh],
+	sessionInserter db.Inserter[sessiontbl.Session],
+	clientURL string,
+	log log.Errorer,
+) CallbackHandler {
+	return CallbackHandler{
+		oidcDecoder:       oidcDecoder,
+		authenticator:     authenticator,
+		identityRetriever: identityRetriever,
+		identityInserter:  identityInserter,
+		userRetriever:     userRetriever,
+		usernameValidator: usernameValidator,
+		userInserter:      userInserter,
+		memberInserter:    memberInserter,
+		teamRetriever:     teamRetriever,
+		mfaEncoder:        mfaEncoder,
+		authEncoder:       authEncoder,
+		refreshEncoder:    refreshEncoder,
+		sessionInserter:   sessionInserter,
+		clientURL:         clientURL,
+		log:               log,
+	}
+}
+
+// Handle handles the GET requests sent to the OIDC callback route.
+func (h CallbackHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// the OIDC token is single-use, so delete it whatever the outcome
+	ckExpired := cookie.NewExpired(cookie.OIDCName)
+	http.SetCookie(w, &ckExpired)
+
+	// check that the callback is for the login that this client started
+	ckOIDC, err := r.Cookie(cookie.OIDCName)
+	if err != nil {
+		h.fail(w, r, ErrCodeInvalid)
+		return
+	}
+	flow, err := h.oidcDecoder.Decode(*ckOIDC)
+	if err != nil {
+		h.fail(w, r, ErrCodeInvalid)
+		return
+	}
+	q := r.URL.Query()
+	if q.Get("error") != "" || q.Get("code") == "" || subtle.ConstantTimeCompare(
+		[]byte(q.Get("state")), []byte(flow.State),
+	) != 1 {
+		h.fail(w, r, ErrCodeInvalid)
+		return
+	}
+
+	// exchange the code for the user's claims
+	claims, err := h.authenticator.Exchange(
+		r.Context(), q.Get("code"), flow.Verifier, flow.Nonce,
+	)
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeInvalid)
+		return
+	}
+
+	// find the user that the identity is linked to, linking it to a new or
+	// existing user on first login, or to the user who started the login if
+	// they are linking it
+	var user usertbl.User
+	if flow.Username != "" {
+		user, err = h.link(r.Context(), claims, flow.Username)
+	} else {
+		user, err = h.userFor(r.Context(), claims)
+	}
+	var errL errLogin
+	if errors.As(err, &errL) {
+		h.fail(w, r, errL.code)
+		return
+	} else if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeServer)
+		return
+	}
+
+	// if the user has enabled two-factor authentication or their team requires
+	// it, issue an MFA token instead of the auth token for them to complete it
+	step, err := loginapi.MFAStep(r.Context(), h.teamRetriever, user)
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeServer)
+		return
+	}
+	if step != "" {
+		ckMFA, err := h.mfaEncoder.Encode(cookie.NewMFA(user.Username))
+		if err != nil {
+			h.log.Error(err)
+			h.fail(w, r, ErrCodeServer)
+			return
+		}
+		http.SetCookie(w, &ckMFA)
+		http.Redirect(w, r,
+			h.clientURL+"/login?"+url.Values{"mfa": {step}}.Encode(),
+			http.StatusFound,
+		)
+		return
+	}
+
+	// encode a new auth token
+	ckAuth, err := h.authEncoder.Encode(cookie.NewAuth(
+		user.Username, user.Role, user.TeamID,
+	))
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeServer)
+		return
+	}
+
+	// start a new session and encode a refresh token for it
+	familyID, tokenID := uuid.NewString(), uuid.NewString()
+	ckRefresh, err := h.refreshEncoder.Encode(
+		cookie.NewRefresh(familyID, tokenID),
+	)
+	if err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeServer)
+		return
+	}
+	if err = h.sessionInserter.Insert(r.Context(), sessiontbl.NewSession(
+		familyID, user.Username, tokenID, ckRefresh.Expires.Unix(),
+	)); err != nil {
+		h.log.Error(err)
+		h.fail(w, r, ErrCodeServer)
+		return
+	}
+
+	// set auth and refresh tokens in cookies and send the user to the client
+	http.SetCookie(w, &ckAuth)
+	http.SetCookie(w, &ckRefresh)
+	http.Redirect(w, r, h.clientURL, http.StatusFound)
+}
+
+// userFor returns the user that the identity with the given claims is linked
+// to. If the identity isn't linked to a user yet, it is linked to the user with
+// the username derived from the claims, who is created if they don't exist.
+func (h CallbackHandler) userFor(
+	ctx context.Context, claims oidc.Claims,
+) (usertbl.User, error) {
+	identity, err := h.identityRetriever.Retrieve(
+		ctx, identitytbl.ID(claims.Issuer, claims.Subject),
+	)
+	if err == nil {
+		return h.userRetriever.Retrieve(ctx, identity.Username)
+	} else if !errors.Is(err, db.ErrNoItem) {
+		return usertbl.User{}, err
+	}
+
+	username := usernameFrom(claims)
+	if errs := h.usernameValidator.Validate(username); len(errs) > 0 {
+		return usertbl.User{}, errLogin{code: ErrCodeUsername}
+	}
+
+	user, err := h.userRetriever.Retrieve(ctx, username)
+	if errors.Is(err, db.ErrNoItem) {
+		// a new user is the admin of their own team, same as a user who
+		// registers without an invite
+		var email string
+		if claims.EmailVerified {
+			email = claims.Email
+		}
+		user = usertbl.NewUser(
+			username, email, nil, role.Owner, uuid.NewString(),
+		)
+		user.EmailVerified = email != ""
+		err = h.userInserter.Insert(ctx, user)
+		if errors.Is(err, db.ErrDupKey) {
+			return usertbl.User{}, errLogin{code: ErrCodeTaken}
+		} else if err != nil {
+			return usertbl.User{}, err
+		}
+		if err = h.memberInserter.Insert(ctx, membershiptbl.NewMembership(
+			user.Username, user.TeamID, user.Role,
+		)); err != nil {
+			return usertbl.User{}, err
+		}
+	} else if err != nil {
+		return usertbl.User{}, err
+	} else if !claims.EmailVerified || !user.EmailVerified ||
+		user.Email == "" || !strings.EqualFold(user.Email, claims.Email) {
+		// only link an existing user if both the identity provider and the
+		// user have verified the email, since the account's owner could be
+		// anyone otherwise - the user can still link it explicitly
+		return usertbl.User{}, errLogin{code: ErrCodeTaken}
+	}
+
+	if err = h.identityInserter.Insert(ctx, identitytbl.NewIdentity(
+		claims.Issuer, claims.Subject, user.Username,
+	)); err != nil {
+		return usertbl.User{}, err
+	}
+	return user, nil
+}
+
+// link links the identity with the given claims to the user with the given
+// username, who started the login while logged in, and returns them. The
+// identity can't be linked if it is already linked to another user.
+func (h CallbackHandler) link(
+	ctx context.Context, claims oidc.Claims, username string,
+) (usertbl.User, error) {
+	id := identitytbl.ID(claims.Issuer, claims.Subject)
+	identity, err := h.identityRetriever.Retrieve(ctx, id)
+	if err == nil {
+		if identity.Username != username {
+			return usertbl.User{}, errLogin{code: ErrCodeLinked}
+		}
+		return h.userRetriever.Retrieve(ctx, username)
+	} else if !errors.Is(err, db.ErrNoItem) {
+		return usertbl.User{}, err
+	}
+
+	user, err := h.userRetriever.Retrieve(ctx, username)
+	if errors.Is(err, db.ErrNoItem) {
+		return usertbl.User{}, errLogin{code: ErrCodeInvalid}
+	} else if err != nil {
+		return usertbl.User{}, err
+	}
+
+	err = h.identityInserter.Insert(ctx, identitytbl.NewIdentity(
+		claims.Issuer, claims.Subject, user.Username,
+	))
+	if errors.Is(err, db.ErrDupKey) {
+		return usertbl.User{}, errLogin{code: ErrCodeLinked}
+	} else if err != nil {
+		return usertbl.User{}, err
+	}
+	return user, nil
+}
+
+// fail redirects the user to the client's login page with the given error
+// code.
+func (h CallbackHandler) fail(
+	w http.ResponseWriter, r *http.Request, code string,
+) {
+	http.Redirect(w, r,
+		h.clientURL+"/login?"+url.Values{"error": {code}}.Encode(),
+		http.StatusFound,
+	)
+}
+
+// usernameFrom derives a username from the given claims, using the preferred
+// username if there is one and the part of the email before the @ otherwise.
+// Characters that aren't allowed in usernames are dropped.
+func usernameFrom(claims oidc.Claims) string {
+	username := claims.PreferredUsername
+	if username == "" {
+		username, _, _ = strings.Cut(claims.Email, "@")
+	}
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return -1
+		}
+		return r
+	}, username)
+}
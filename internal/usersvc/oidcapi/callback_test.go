@@ -0,0 +1,1047 @@
+//go:build utest
+
+package oidcapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/oidc"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	var (
+		oidcDecoder       = &cookie.FakeDecoder[cookie.OIDC]{}
+		authenticator     = &oidc.FakeAuthenticator{}
+		identityRetriever = &db.FakeRetriever[identitytbl.Identity]{}
+		identityInserter  = &db.FakeInserter[identitytbl.Identity]{}
+		userRetriever     = &db.FakeRetriever[usertbl.User]{}
+		usernameValidator = &fakeStrValidator{}
+		userInserter      = &db.FakeInserter[usertbl.User]{}
+		memberInserter    = &db.FakeInserter[membershiptbl.Membership]{}
+		teamRetriever     = &db.FakeRetriever[teamtbl.Team]{}
+		mfaEncoder        = &cookie.FakeEncoder[cookie.MFA]{}
+		authEncoder       = &cookie.FakeEncoder[cookie.Auth]{}
+		refreshEncoder    = &cookie.FakeEncoder[cookie.Refresh]{}
+		sessionInserter   = &db.FakeInserter[sessiontbl.Session]{}
+		clientURL         = "https://goteam.io"
+		log               = &log.FakeErrorer{}
+	)
+	sut := NewCallbackHandler(
+		oidcDecoder,
+		authenticator,
+		identityRetriever,
+		identityInserter,
+		userRetriever,
+		usernameValidator,
+		userInserter,
+		memberInserter,
+		teamRetriever,
+		mfaEncoder,
+		authEncoder,
+		refreshEncoder,
+		sessionInserter,
+		clientURL,
+		log,
+	)
+
+	claimsA := oidc.Claims{
+		Issuer:            "https://idp.goteam.io",
+		Subject:           "sub123",
+		Email:             "bob@goteam.io",
+		EmailVerified:     true,
+		PreferredUsername: "bob123",
+	}
+	claimsUnverified := claimsA
+	claimsUnverified.EmailVerified = false
+	identityA := identitytbl.NewIdentity(
+		claimsA.Issuer, claimsA.Subject, "bob123",
+	)
+	userA := usertbl.NewUser(
+		"bob123", "Bob@goteam.io", nil, role.Owner, "bob123",
+	)
+	userA.EmailVerified = true
+	userUnverified := userA
+	userUnverified.EmailVerified = false
+	userNoEmail := usertbl.NewUser("bob123", "", nil, role.Owner, "bob123")
+	userOtherEmail := usertbl.NewUser(
+		"bob123", "other@goteam.io", nil, role.Owner, "bob123",
+	)
+	locInvalid := clientURL + "/login?error=" + ErrCodeInvalid
+	locUsername := clientURL + "/login?error=" + ErrCodeUsername
+	locTaken := clientURL + "/login?error=" + ErrCodeTaken
+	locLinked := clientURL + "/login?error=" + ErrCodeLinked
+	locServer := clientURL + "/login?error=" + ErrCodeServer
+	userMFA := userA
+	userMFA.MFAEnabled = true
+	// assertMFA asserts that the MFA token was set instead of the auth and
+	// refresh tokens.
+	assertMFA := func(t *testing.T, resp *http.Response, _ []any) {
+		names := map[string]bool{}
+		for _, ck := range resp.Cookies() {
+			names[ck.Name] = true
+		}
+		assert.True(t.Error, names[cookie.MFAName])
+		assert.True(t.Error, !names[cookie.AuthName])
+		assert.True(t.Error, !names[cookie.RefreshName])
+	}
+	assertLoggedIn := func(t *testing.T, resp *http.Response, _ []any) {
+		names := map[string]bool{}
+		for _, ck := range resp.Cookies() {
+			names[ck.Name] = true
+		}
+		assert.True(t.Error, names[cookie.AuthName])
+		assert.True(t.Error, names[cookie.RefreshName])
+	}
+
+	for _, c := range []struct {
+		name                string
+		hasCookie           bool
+		errDecode           error
+		query               string
+		linkUsername        string
+		claims              oidc.Claims
+		errExchange         error
+		identity            identitytbl.Identity
+		errRetrieveIdentity error
+		user                usertbl.User
+		errRetrieveUser     error
+		usernameErrs        []string
+		errInsertUser       error
+		errInsertMember     error
+		errInsertIdentity   error
+		team                teamtbl.Team
+		errRetrieveTeam     error
+		errEncodeMFA        error
+		errEncodeAuth       error
+		errEncodeRefresh    error
+		errInsertSession    error
+		wantLocation        string
+		assertFunc          func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:                "NoCookie",
+			hasCookie:           false,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locInvalid,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "ErrDecode",
+			hasCookie:           true,
+			errDecode:           errors.New("decode failed"),
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locInvalid,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "IdPError",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "error=access_denied&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locInvalid,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "NoCode",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locInvalid,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "StateMismatch",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=0th3r",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locInvalid,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "ErrExchange",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         errors.New("exchange failed"),
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locInvalid,
+			assertFunc:          assert.OnLoggedErr("exchange failed"),
+		},
+		{
+			name:                "ErrRetrieveIdentity",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: errors.New("retrieve identity failed"),
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc: assert.OnLoggedErr(
+				"retrieve identity failed",
+			),
+		},
+		{
+			name:                "LinkedErrRetrieveUser",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identityA,
+			errRetrieveIdentity: nil,
+			user:                usertbl.User{},
+			errRetrieveUser:     errors.New("retrieve user failed"),
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:                "UsernameInvalid",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        []string{"Username cannot be empty."},
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locUsername,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "ErrRetrieveUser",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     errors.New("retrieve user failed"),
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:                "TakenEmailUnverified",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsUnverified,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locTaken,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "TakenLocalEmailUnverified",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                userUnverified,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locTaken,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "TakenNoEmail",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                userNoEmail,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locTaken,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "TakenEmailMismatch",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                userOtherEmail,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locTaken,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "TakenOnInsert",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       db.ErrDupKey,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locTaken,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "ErrInsertUser",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       errors.New("insert user failed"),
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("insert user failed"),
+		},
+		{
+			name:                "ErrInsertMembership",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertMember:     errors.New("insert membership failed"),
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("insert membership failed"),
+		},
+		{
+			name:                "ErrInsertIdentity",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   errors.New("insert identity failed"),
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("insert identity failed"),
+		},
+		{
+			name:                "ErrEncodeAuth",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       errors.New("encode auth failed"),
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("encode auth failed"),
+		},
+		{
+			name:                "ErrEncodeRefresh",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    errors.New("encode refresh failed"),
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("encode refresh failed"),
+		},
+		{
+			name:                "ErrInsertSession",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    errors.New("insert session failed"),
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("insert session failed"),
+		},
+		{
+			name:                "ErrRetrieveTeam",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identityA,
+			errRetrieveIdentity: nil,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     errors.New("retrieve team failed"),
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:                "ErrEncodeMFA",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identityA,
+			errRetrieveIdentity: nil,
+			user:                userMFA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        errors.New("encode mfa failed"),
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("encode mfa failed"),
+		},
+		{
+			name:                "MFAVerify",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identityA,
+			errRetrieveIdentity: nil,
+			user:                userMFA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        clientURL + "/login?mfa=" + loginapi.MFAVerify,
+			assertFunc:          assertMFA,
+		},
+		{
+			name:                "MFAEnroll",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identityA,
+			errRetrieveIdentity: nil,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{RequireMFA: true},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        clientURL + "/login?mfa=" + loginapi.MFAEnroll,
+			assertFunc:          assertMFA,
+		},
+		{
+			name:                "OKLinked",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identityA,
+			errRetrieveIdentity: nil,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        clientURL,
+			assertFunc:          assertLoggedIn,
+		},
+		{
+			name:                "OKExistingUser",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        clientURL,
+			assertFunc:          assertLoggedIn,
+		},
+		{
+			name:                "OKNewUser",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        clientURL,
+			assertFunc:          assertLoggedIn,
+		},
+		{
+			name:                "LinkErrRetrieveIdentity",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "bob123",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: errors.New("retrieve identity failed"),
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("retrieve identity failed"),
+		},
+		{
+			name:                "LinkLinkedToOther",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "alice123",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identityA,
+			errRetrieveIdentity: nil,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locLinked,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "LinkUserNotFound",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "bob123",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     db.ErrNoItem,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locInvalid,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "LinkErrRetrieveUser",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "bob123",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                usertbl.User{},
+			errRetrieveUser:     errors.New("retrieve user failed"),
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:                "LinkLinkedOnInsert",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "bob123",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   db.ErrDupKey,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locLinked,
+			assertFunc:          func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:                "LinkErrInsertIdentity",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "bob123",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   errors.New("insert identity failed"),
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        locServer,
+			assertFunc:          assert.OnLoggedErr("insert identity failed"),
+		},
+		{
+			name:                "OKLinkAlreadyLinked",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "bob123",
+			claims:              claimsA,
+			errExchange:         nil,
+			identity:            identityA,
+			errRetrieveIdentity: nil,
+			user:                userA,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        clientURL,
+			assertFunc:          assertLoggedIn,
+		},
+		{
+			name:                "OKLink",
+			hasCookie:           true,
+			errDecode:           nil,
+			query:               "code=c0d3&state=st4t3",
+			linkUsername:        "bob123",
+			claims:              claimsUnverified,
+			errExchange:         nil,
+			identity:            identitytbl.Identity{},
+			errRetrieveIdentity: db.ErrNoItem,
+			user:                userNoEmail,
+			errRetrieveUser:     nil,
+			usernameErrs:        nil,
+			errInsertUser:       nil,
+			errInsertIdentity:   nil,
+			team:                teamtbl.Team{},
+			errRetrieveTeam:     nil,
+			errEncodeMFA:        nil,
+			errEncodeAuth:       nil,
+			errEncodeRefresh:    nil,
+			errInsertSession:    nil,
+			wantLocation:        clientURL,
+			assertFunc:          assertLoggedIn,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			oidcDecoder.Res = cookie.NewOIDC("st4t3", "n0nc3", "v3r1f13r")
+			oidcDecoder.Res.Username = c.linkUsername
+			oidcDecoder.Err = c.errDecode
+			authenticator.Claims = c.claims
+			authenticator.ErrClaims = c.errExchange
+			identityRetriever.Res = c.identity
+			identityRetriever.Err = c.errRetrieveIdentity
+			identityInserter.Err = c.errInsertIdentity
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
+			usernameValidator.errs = c.usernameErrs
+			userInserter.Err = c.errInsertUser
+			memberInserter.Err = c.errInsertMember
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			mfaEncoder.Res = http.Cookie{Name: cookie.MFAName}
+			mfaEncoder.Err = c.errEncodeMFA
+			authEncoder.Res = http.Cookie{Name: cookie.AuthName}
+			authEncoder.Err = c.errEncodeAuth
+			refreshEncoder.Res = http.Cookie{Name: cookie.RefreshName}
+			refreshEncoder.Err = c.errEncodeRefresh
+			sessionInserter.Err = c.errInsertSession
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodGet, "/oidc/callback?"+c.query, nil,
+			)
+			if c.hasCookie {
+				r.AddCookie(&http.Cookie{Name: cookie.OIDCName, Value: "t"})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+			assert.Equal(t.Error, resp.Header.Get("Location"), c.wantLocation)
+			assert.Equal(t.Error, resp.Cookies()[0].Name, cookie.OIDCName)
+			assert.True(t.Error, resp.Cookies()[0].MaxAge < 0)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
+
+func TestUsernameFrom(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		claims oidc.Claims
+		want   string
+	}{
+		{
+			name:   "PreferredUsername",
+			claims: oidc.Claims{PreferredUsername: "bob.123", Email: "a@b.c"},
+			want:   "bob123",
+		},
+		{
+			name:   "Email",
+			claims: oidc.Claims{PreferredUsername: "", Email: "bob_123@b.c"},
+			want:   "bob123",
+		},
+		{
+			name:   "NonASCII",
+			claims: oidc.Claims{PreferredUsername: "bób123", Email: ""},
+			want:   "bb123",
+		},
+		{
+			name:   "Empty",
+			claims: oidc.Claims{PreferredUsername: "", Email: ""},
+			want:   "",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, usernameFrom(c.claims), c.want)
+		})
+	}
+}
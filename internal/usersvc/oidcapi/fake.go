@@ -0,0 +1,8 @@
+package oidcapi
+
+// fakeStrValidator is a test fake for registerapi.StrValidator.
+type fakeStrValidator struct{ errs []string }
+
+// Validate implements the registerapi.StrValidator interface on
+// fakeStrValidator.
+func (f *fakeStrValidator) Validate(_ string) []string { return f.errs }
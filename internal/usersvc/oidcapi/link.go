@@ -0,0 +1,55 @@
+package oidcapi
+
+import (
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/oidc"
+)
+
+// LinkHandler is an api.MethodHandler that can be used to handle GET OIDC link
+// requests, which start a login the same way as LoginHandler but link the
+// identity to the logged-in user when the identity provider redirects them
+// back. This is how users whose username or email doesn't match their identity
+// provider account can log in through it.
+type LinkHandler struct {
+	authDecoder cookie.Decoder[cookie.Auth]
+	login       LoginHandler
+}
+
+// NewLinkHandler creates and returns a new LinkHandler.
+func NewLinkHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	authenticator oidc.Authenticator,
+	oidcEncoder cookie.Encoder[cookie.OIDC],
+	log log.Errorer,
+) LinkHandler {
+	return LinkHandler{
+		authDecoder: authDecoder,
+		login:       NewLoginHandler(authenticator, oidcEncoder, log),
+	}
+}
+
+// Handle handles the GET requests sent to the OIDC link route.
+func (h LinkHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get and decode auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.login.log.Error(err)
+		return
+	}
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	h.login.start(w, r, auth.Username)
+}
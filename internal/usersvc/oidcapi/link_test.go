@@ -0,0 +1,96 @@
+//go:build utest
+
+package oidcapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/oidc"
+)
+
+func TestLinkHandler(t *testing.T) {
+	var (
+		authDecoder   = &cookie.FakeDecoder[cookie.Auth]{}
+		authenticator = &oidc.FakeAuthenticator{}
+		oidcEncoder   = &cookie.FakeEncoder[cookie.OIDC]{}
+		log           = &log.FakeErrorer{}
+	)
+	sut := NewLinkHandler(authDecoder, authenticator, oidcEncoder, log)
+
+	authURL := "https://idp.goteam.io/authorize?state=st4t3"
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		errEncode     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			errEncode:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			errEncode:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:          "ErrEncode",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errEncode:     errors.New("encode failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("encode failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errEncode:     nil,
+			wantStatus:    http.StatusFound,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.Equal(t.Error, resp.Header.Get("Location"), authURL)
+				assert.Equal(t.Error,
+					resp.Cookies()[0].Name, cookie.OIDCName,
+				)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = cookie.Auth{Username: "bob123"}
+			authDecoder.Err = c.errDecodeAuth
+			authenticator.URL = authURL
+			authenticator.ErrURL = nil
+			oidcEncoder.Res = http.Cookie{Name: cookie.OIDCName, Value: "t"}
+			oidcEncoder.Err = c.errEncode
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/oidc/link", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
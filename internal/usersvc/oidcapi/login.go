@@ -0,0 +1,80 @@
+package oidcapi
+
+import (
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/oidc"
+)
+
+// LoginHandler is an api.MethodHandler that can be used to handle GET OIDC
+// login requests, which start the login by redirecting the user to the
+// identity provider.
+type LoginHandler struct {
+	authenticator oidc.Authenticator
+	oidcEncoder   cookie.Encoder[cookie.OIDC]
+	log           log.Errorer
+}
+
+// NewLoginHandler creates and returns a new LoginHandler.
+func NewLoginHandler(
+	authenticator oidc.Authenticator,
+	oidcEncoder cookie.Encoder[cookie.OIDC],
+	log log.Errorer,
+) LoginHandler {
+	return LoginHandler{
+		authenticator: authenticator,
+		oidcEncoder:   oidcEncoder,
+		log:           log,
+	}
+}
+
+// Handle handles the GET requests sent to the OIDC login route.
+func (h LoginHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	h.start(w, r, "")
+}
+
+// start starts a login by redirecting the user to the identity provider. If
+// username is set, the identity is linked to the user with that username when
+// the identity provider redirects them back.
+func (h LoginHandler) start(
+	w http.ResponseWriter, r *http.Request, username string,
+) {
+	// generate the values to check when the user is redirected back
+	var vals [3]string
+	for i := range vals {
+		val, err := oidc.NewRandom()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		vals[i] = val
+	}
+	state, nonce, verifier := vals[0], vals[1], vals[2]
+
+	// get the identity provider URL to redirect to
+	authURL, err := h.authenticator.AuthURL(
+		r.Context(), state, nonce, oidc.Challenge(verifier),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// store the values in a cookie and redirect
+	flow := cookie.NewOIDC(state, nonce, verifier)
+	flow.Username = username
+	ckOIDC, err := h.oidcEncoder.Encode(flow)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	http.SetCookie(w, &ckOIDC)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
@@ -0,0 +1,76 @@
+//go:build utest
+
+package oidcapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/oidc"
+)
+
+func TestLoginHandler(t *testing.T) {
+	var (
+		authenticator = &oidc.FakeAuthenticator{}
+		oidcEncoder   = &cookie.FakeEncoder[cookie.OIDC]{}
+		log           = &log.FakeErrorer{}
+	)
+	sut := NewLoginHandler(authenticator, oidcEncoder, log)
+
+	authURL := "https://idp.goteam.io/authorize?state=st4t3"
+
+	for _, c := range []struct {
+		name       string
+		errAuthURL error
+		errEncode  error
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "ErrAuthURL",
+			errAuthURL: errors.New("discovery failed"),
+			errEncode:  nil,
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("discovery failed"),
+		},
+		{
+			name:       "ErrEncode",
+			errAuthURL: nil,
+			errEncode:  errors.New("encode failed"),
+			wantStatus: http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr("encode failed"),
+		},
+		{
+			name:       "OK",
+			errAuthURL: nil,
+			errEncode:  nil,
+			wantStatus: http.StatusFound,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.Equal(t.Error, resp.Header.Get("Location"), authURL)
+				assert.Equal(t.Error,
+					resp.Cookies()[0].Name, cookie.OIDCName,
+				)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authenticator.URL = authURL
+			authenticator.ErrURL = c.errAuthURL
+			oidcEncoder.Res = http.Cookie{Name: cookie.OIDCName, Value: "t"}
+			oidcEncoder.Err = c.errEncode
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/oidc/login", nil)
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package oidcapi contains code for responding to HTTP requests made to the
+// OIDC API routes, which are used for logging in a user through an external
+// OpenID Connect identity provider.
+package oidcapi
@@ -0,0 +1,23 @@
+package passwordapi
+
+// fakeComparator is a test fake for loginapi.Comparator.
+type fakeComparator struct{ err error }
+
+// Compare implements the loginapi.Comparator interface on fakeComparator.
+func (f *fakeComparator) Compare(_ []byte, _ string) error { return f.err }
+
+// fakeStrValidator is a test fake for registerapi.StrValidator.
+type fakeStrValidator struct{ errs []string }
+
+// Validate implements the registerapi.StrValidator interface on
+// fakeStrValidator.
+func (f *fakeStrValidator) Validate(_ string) []string { return f.errs }
+
+// fakeHasher is a test fake for registerapi.Hasher.
+type fakeHasher struct {
+	hash []byte
+	err  error
+}
+
+// Hash implements the registerapi.Hasher interface on fakeHasher.
+func (f *fakeHasher) Hash(_ string) ([]byte, error) { return f.hash, f.err }
@@ -0,0 +1,3 @@
+// Package passwordapi contains code for responding to HTTP requests made to the
+// password API route, which is used by users for changing their password.
+package passwordapi
@@ -0,0 +1,251 @@
+package passwordapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
+)
+
+// PatchReq defines the body of PATCH password requests.
+type PatchReq struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// PatchResp defines the body of PATCH password responses.
+type PatchResp struct {
+	Error          string   `json:"error,omitempty"`
+	ValidationErrs []string `json:"validationErrors,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can be used to handle PATCH
+// password requests.
+type PatchHandler struct {
+	authDecoder        cookie.Decoder[cookie.Auth]
+	pwdValidator       registerapi.StrValidator
+	userRetriever      db.Retriever[usertbl.User]
+	pwdComparator      loginapi.Comparator
+	hasher             registerapi.Hasher
+	userUpdater        db.Updater[usertbl.User]
+	revocationInserter db.Inserter[revocationtbl.Revocation]
+	sessionsDeleter    db.Deleter
+	authEncoder        cookie.Encoder[cookie.Auth]
+	refreshEncoder     cookie.Encoder[cookie.Refresh]
+	sessionInserter    db.Inserter[sessiontbl.Session]
+	log                log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	pwdValidator registerapi.StrValidator,
+	userRetriever db.Retriever[usertbl.User],
+	pwdComparator loginapi.Comparator,
+	hasher registerapi.Hasher,
+	userUpdater db.Updater[usertbl.User],
+	revocationInserter db.Inserter[revocationtbl.Revocation],
+	sessionsDeleter db.Deleter,
+	authEncoder cookie.Encoder[cookie.Auth],
+	refreshEncoder cookie.Encoder[cookie.Refresh],
+	sessionInserter db.Inserter[sessiontbl.Session],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		authDecoder:        authDecoder,
+		pwdValidator:       pwdValidator,
+		userRetriever:      userRetriever,
+		pwdComparator:      pwdComparator,
+		hasher:             hasher,
+		userUpdater:        userUpdater,
+		revocationInserter: revocationInserter,
+		sessionsDeleter:    sessionsDeleter,
+		authEncoder:        authEncoder,
+		refreshEncoder:     refreshEncoder,
+		sessionInserter:    sessionInserter,
+		log:                log,
+	}
+}
+
+// Handle handles the PATCH requests sent to the password route.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate request
+	if req.CurrentPassword == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Current password cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if errs := h.pwdValidator.Validate(req.NewPassword); len(errs) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			ValidationErrs: errs,
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// check current password
+	if err = h.pwdComparator.Compare(
+		user.Password, req.CurrentPassword,
+	); errors.Is(err, password.ErrMismatch) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Current password is incorrect.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// hash new password and update user
+	if user.Password, err = h.hasher.Hash(req.NewPassword); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// invalidate all existing auth tokens and sessions of the user
+	if err = h.revocationInserter.Insert(
+		r.Context(),
+		revocationtbl.NewAllRevocation(user.Username, time.Now().UnixMilli()),
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.sessionsDeleter.Delete(r.Context(), user.Username); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// start a new session for the user on the device that made the request so
+	// that they remain logged in on it
+	ckNewAuth, err := h.authEncoder.Encode(cookie.NewAuth(
+		user.Username, user.Role, user.TeamID,
+	))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	familyID, tokenID := uuid.NewString(), uuid.NewString()
+	ckRefresh, err := h.refreshEncoder.Encode(
+		cookie.NewRefresh(familyID, tokenID),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.sessionInserter.Insert(r.Context(), sessiontbl.NewSession(
+		familyID, user.Username, tokenID, ckRefresh.Expires.Unix(),
+	)); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// set auth and refresh cookies
+	http.SetCookie(w, &ckNewAuth)
+	http.SetCookie(w, &ckRefresh)
+}
@@ -0,0 +1,424 @@
+//go:build utest
+
+package passwordapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	pwdValidator := &fakeStrValidator{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	pwdComparator := &fakeComparator{}
+	hasher := &fakeHasher{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	revocationInserter := &db.FakeInserter[revocationtbl.Revocation]{}
+	sessionsDeleter := &db.FakeDeleter{}
+	authEncoder := &cookie.FakeEncoder[cookie.Auth]{}
+	refreshEncoder := &cookie.FakeEncoder[cookie.Refresh]{}
+	sessionInserter := &db.FakeInserter[sessiontbl.Session]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(
+		authDecoder,
+		pwdValidator,
+		userRetriever,
+		pwdComparator,
+		hasher,
+		userUpdater,
+		revocationInserter,
+		sessionsDeleter,
+		authEncoder,
+		refreshEncoder,
+		sessionInserter,
+		log,
+	)
+
+	authDecoder.Res = cookie.Auth{Username: "bob123"}
+	authEncoder.Res = http.Cookie{Name: cookie.AuthName, Value: "auth"}
+	refreshEncoder.Res = http.Cookie{Name: cookie.RefreshName, Value: "ref"}
+	validReq := `{"currentPassword": "0ldP4ss!", "newPassword": "N3wP4ss!"}`
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		reqBody       string
+		pwdErrs       []string
+		errRetrieve   error
+		errCompare    error
+		errHash       error
+		errUpdate     error
+		errRevoke     error
+		errDelete     error
+		errEncodeAuth error
+		errEncodeRef  error
+		errInsert     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "CurrentPasswordEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       `{"currentPassword": "", "newPassword": "N3wP4ss!"}`,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Current password cannot be empty."),
+		},
+		{
+			name:          "NewPasswordInvalid",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       []string{"Password cannot be empty."},
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var body PatchResp
+				err := json.NewDecoder(resp.Body).Decode(&body)
+				assert.Nil(t.Fatal, err)
+				assert.AllEqual(t.Error,
+					body.ValidationErrs, []string{"Password cannot be empty."},
+				)
+			},
+		},
+		{
+			name:          "UserNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   db.ErrNoItem,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   errors.New("retrieve user failed"),
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:          "WrongPassword",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    password.ErrMismatch,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Current password is incorrect."),
+		},
+		{
+			name:          "ErrCompare",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    errors.New("compare failed"),
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("compare failed"),
+		},
+		{
+			name:          "ErrHash",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       errors.New("hash failed"),
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("hash failed"),
+		},
+		{
+			name:          "UserNotFoundOnUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     db.ErrNoItem,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     errors.New("update user failed"),
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:          "ErrRevoke",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     errors.New("insert revocation failed"),
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("insert revocation failed"),
+		},
+		{
+			name:          "ErrDeleteSessions",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     errors.New("delete sessions failed"),
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("delete sessions failed"),
+		},
+		{
+			name:          "ErrEncodeAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: errors.New("encode auth failed"),
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("encode auth failed"),
+		},
+		{
+			name:          "ErrEncodeRefresh",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  errors.New("encode refresh failed"),
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("encode refresh failed"),
+		},
+		{
+			name:          "ErrInsertSession",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     errors.New("insert session failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("insert session failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			pwdErrs:       nil,
+			errRetrieve:   nil,
+			errCompare:    nil,
+			errHash:       nil,
+			errUpdate:     nil,
+			errRevoke:     nil,
+			errDelete:     nil,
+			errEncodeAuth: nil,
+			errEncodeRef:  nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[0].Name, cookie.AuthName)
+				assert.Equal(t.Error, cks[1].Name, cookie.RefreshName)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			pwdValidator.errs = c.pwdErrs
+			userRetriever.Err = c.errRetrieve
+			pwdComparator.err = c.errCompare
+			hasher.err = c.errHash
+			userUpdater.Err = c.errUpdate
+			revocationInserter.Err = c.errRevoke
+			sessionsDeleter.Err = c.errDelete
+			authEncoder.Err = c.errEncodeAuth
+			refreshEncoder.Err = c.errEncodeRef
+			sessionInserter.Err = c.errInsert
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch, "/", strings.NewReader(c.reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package profileapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
+)
+
+// DeleteReq defines the body of DELETE user profile requests.
+type DeleteReq struct {
+	Password string `json:"password"`
+}
+
+// DeleteResp defines the body of DELETE user profile responses.
+type DeleteResp struct {
+	Report *DeleteReport `json:"report,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// DeleteReport lists what was removed when a user deleted their account. The
+// user's sessions and login attempt counters are always removed and their auth
+// tokens revoked as well, so they are not listed.
+type DeleteReport struct {
+	Username   string   `json:"username"`
+	Teams      []string `json:"teams"`      // IDs of the teams left
+	Boards     []string `json:"boards"`     // IDs of the boards left
+	Tokens     []string `json:"tokens"`     // names of personal access tokens
+	Identities []string `json:"identities"` // IDs of linked OIDC identities
+}
+
+// DeleteHandler is an api.MethodHandler that can be used to handle DELETE user
+// profile requests, which are used by users for deleting their own account.
+type DeleteHandler struct {
+	authDecoder        cookie.Decoder[cookie.Auth]
+	userRetriever      db.Retriever[usertbl.User]
+	pwdComparator      loginapi.Comparator
+	memberRetriever    db.Retriever[[]membershiptbl.Membership]
+	teamRetriever      db.Retriever[teamtbl.Team]
+	teamUpdater        db.Updater[teamtbl.Team]
+	memberDeleter      db.DeleterDualKey
+	tokenRetriever     db.Retriever[[]tokentbl.Token]
+	tokenDeleter       db.DeleterDualKey
+	identityRetriever  db.Retriever[[]identitytbl.Identity]
+	identityDeleter    db.Deleter
+	attemptDeleter     db.Deleter
+	sessionsDeleter    db.Deleter
+	revocationInserter db.Inserter[revocationtbl.Revocation]
+	userDeleter        db.Deleter
+	log                log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	userRetriever db.Retriever[usertbl.User],
+	pwdComparator loginapi.Comparator,
+	memberRetriever db.Retriever[[]membershiptbl.Membership],
+	teamRetriever db.Retriever[teamtbl.Team],
+	teamUpdater db.Updater[teamtbl.Team],
+	memberDeleter db.DeleterDualKey,
+	tokenRetriever db.Retriever[[]tokentbl.Token],
+	tokenDeleter db.DeleterDualKey,
+	identityRetriever db.Retriever[[]identitytbl.Identity],
+	identityDeleter db.Deleter,
+	attemptDeleter db.Deleter,
+	sessionsDeleter db.Deleter,
+	revocationInserter db.Inserter[revocationtbl.Revocation],
+	userDeleter db.Deleter,
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		authDecoder:        authDecoder,
+		userRetriever:      userRetriever,
+		pwdComparator:      pwdComparator,
+		memberRetriever:    memberRetriever,
+		teamRetriever:      teamRetriever,
+		teamUpdater:        teamUpdater,
+		memberDeleter:      memberDeleter,
+		tokenRetriever:     tokenRetriever,
+		tokenDeleter:       tokenDeleter,
+		identityRetriever:  identityRetriever,
+		identityDeleter:    identityDeleter,
+		attemptDeleter:     attemptDeleter,
+		sessionsDeleter:    sessionsDeleter,
+		revocationInserter: revocationInserter,
+		userDeleter:        userDeleter,
+		log:                log,
+	}
+}
+
+// Handle handles the DELETE requests sent to the user profile route.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode and validate request
+	var req DeleteReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if req.Password == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Password cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// confirm password - users who signed up through an identity provider
+	// don't have one, so they must set one through a password reset first
+	if len(user.Password) == 0 {
+		w.WriteHeader(http.StatusForbidden)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Set a password through a password reset before " +
+				"deleting your account.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err = h.pwdComparator.Compare(
+		user.Password, req.Password,
+	); errors.Is(err, password.ErrMismatch) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Password is incorrect.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// retrieve the user's teams and check that they don't own any of them
+	// before removing anything so that the request either fails as a whole or
+	// can be retried until it succeeds
+	memberships, err := h.memberRetriever.Retrieve(r.Context(), user.Username)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	var teams []teamtbl.Team
+	for _, m := range memberships {
+		team, err := h.teamRetriever.Retrieve(r.Context(), m.TeamID)
+		if errors.Is(err, db.ErrNoItem) {
+			continue
+		} else if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		if team.Owner == user.Username {
+			w.WriteHeader(http.StatusConflict)
+			if err := json.NewEncoder(w).Encode(DeleteResp{
+				Error: "You own the team \"" + team.Name + "\". Transfer " +
+					"its ownership or delete it before deleting your " +
+					"account.",
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
+		}
+		teams = append(teams, team)
+	}
+
+	report := DeleteReport{
+		Username:   user.Username,
+		Teams:      []string{},
+		Boards:     []string{},
+		Tokens:     []string{},
+		Identities: []string{},
+	}
+
+	// remove the user from their teams and all of their boards
+	for _, team := range teams {
+		team.Members = removeString(team.Members, user.Username)
+		for i, b := range team.Boards {
+			members := removeString(b.Members, user.Username)
+			if len(members) != len(b.Members) {
+				report.Boards = append(report.Boards, b.ID)
+			}
+			team.Boards[i].Members = members
+		}
+		if err = h.teamUpdater.Update(
+			r.Context(), team,
+		); err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+	}
+
+	// delete memberships, including those of teams that no longer exist
+	for _, m := range memberships {
+		if err = h.memberDeleter.Delete(
+			r.Context(), user.Username, m.TeamID,
+		); err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		report.Teams = append(report.Teams, m.TeamID)
+	}
+
+	// delete personal access tokens
+	tokens, err := h.tokenRetriever.Retrieve(r.Context(), user.Username)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	for _, t := range tokens {
+		if err = h.tokenDeleter.Delete(
+			r.Context(), user.Username, t.Name,
+		); err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		report.Tokens = append(report.Tokens, t.Name)
+	}
+
+	// unlink identities so that logging in through the identity provider
+	// again creates a new account instead of failing
+	identities, err := h.identityRetriever.Retrieve(
+		r.Context(), user.Username,
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	for _, i := range identities {
+		if err = h.identityDeleter.Delete(
+			r.Context(), i.ID,
+		); err != nil && !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		report.Identities = append(report.Identities, i.ID)
+	}
+
+	// delete login attempt counter
+	if err = h.attemptDeleter.Delete(
+		r.Context(), attempttbl.UserID(user.Username),
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// invalidate all existing auth tokens and sessions of the user
+	if err = h.revocationInserter.Insert(
+		r.Context(),
+		revocationtbl.NewAllRevocation(user.Username, time.Now().UnixMilli()),
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.sessionsDeleter.Delete(r.Context(), user.Username); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// delete the user last so that the request can be retried if any of the
+	// steps above fail
+	if err = h.userDeleter.Delete(
+		r.Context(), user.Username,
+	); err != nil && !errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// clear auth and refresh cookies and write the report
+	ckExpAuth := cookie.NewExpired(cookie.AuthName)
+	ckExpRefresh := cookie.NewExpired(cookie.RefreshName)
+	http.SetCookie(w, &ckExpAuth)
+	http.SetCookie(w, &ckExpRefresh)
+	if err = json.NewEncoder(w).Encode(DeleteResp{
+		Report: &report,
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
+
+// removeString returns a copy of the given slice of strings with all
+// occurrences of the given string removed from it.
+func removeString(strs []string, s string) []string {
+	res := make([]string, 0, len(strs))
+	for _, str := range strs {
+		if str != s {
+			res = append(res, str)
+		}
+	}
+	return res
+}
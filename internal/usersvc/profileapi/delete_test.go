@@ -0,0 +1,780 @@
+//go:build utest
+
+package profileapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	pwdComparator := &fakeComparator{}
+	memberRetriever := &db.FakeRetriever[[]membershiptbl.Membership]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	teamUpdater := &db.FakeUpdater[teamtbl.Team]{}
+	memberDeleter := &db.FakeDeleterDualKey{}
+	tokenRetriever := &db.FakeRetriever[[]tokentbl.Token]{}
+	tokenDeleter := &db.FakeDeleterDualKey{}
+	identityRetriever := &db.FakeRetriever[[]identitytbl.Identity]{}
+	identityDeleter := &db.FakeDeleter{}
+	attemptDeleter := &db.FakeDeleter{}
+	sessionsDeleter := &db.FakeDeleter{}
+	revocationInserter := &db.FakeInserter[revocationtbl.Revocation]{}
+	userDeleter := &db.FakeDeleter{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(
+		authDecoder,
+		userRetriever,
+		pwdComparator,
+		memberRetriever,
+		teamRetriever,
+		teamUpdater,
+		memberDeleter,
+		tokenRetriever,
+		tokenDeleter,
+		identityRetriever,
+		identityDeleter,
+		attemptDeleter,
+		sessionsDeleter,
+		revocationInserter,
+		userDeleter,
+		log,
+	)
+
+	authDecoder.Res = cookie.Auth{Username: "bob123"}
+	memberRetriever.Res = []membershiptbl.Membership{
+		membershiptbl.NewMembership("bob123", "team1", role.Member),
+	}
+	tokenRetriever.Res = []tokentbl.Token{{Username: "bob123", Name: "ci"}}
+	identityRetriever.Res = []identitytbl.Identity{
+		identitytbl.NewIdentity("https://idp.goteam.io", "sub1", "bob123"),
+	}
+	validReq := `{"password": "P4ssw0rd!"}`
+
+	// assertReport asserts that the response contains the deletion report
+	// with the given board IDs and that the auth and refresh cookies are
+	// cleared.
+	assertReport := func(
+		wantBoards []string,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			var body DeleteResp
+			err := json.NewDecoder(resp.Body).Decode(&body)
+			assert.Nil(t.Fatal, err)
+			assert.True(t.Fatal, body.Report != nil)
+			assert.Equal(t.Error, body.Report.Username, "bob123")
+			assert.AllEqual(t.Error, body.Report.Teams, []string{"team1"})
+			assert.AllEqual(t.Error, body.Report.Boards, wantBoards)
+			assert.AllEqual(t.Error, body.Report.Tokens, []string{"ci"})
+			assert.AllEqual(t.Error,
+				body.Report.Identities,
+				[]string{"https://idp.goteam.io#sub1"},
+			)
+
+			cookies := resp.Cookies()
+			assert.Equal(t.Fatal, len(cookies), 2)
+			for _, ck := range cookies {
+				assert.Equal(t.Error, ck.MaxAge, -1)
+			}
+		}
+	}
+
+	for _, c := range []struct {
+		name               string
+		authToken          string
+		errDecodeAuth      error
+		reqBody            string
+		password           []byte
+		errRetrieveUser    error
+		errCompare         error
+		errRetrieveMembers error
+		teamOwner          string
+		errRetrieveTeam    error
+		errUpdateTeam      error
+		errDeleteMember    error
+		errRetrieveTokens  error
+		errDeleteToken     error
+		errRetrieveIdents  error
+		errDeleteIdent     error
+		errDeleteAttempt   error
+		errRevoke          error
+		errDeleteSessions  error
+		errDeleteUser      error
+		wantStatus         int
+		assertFunc         func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:               "NoAuth",
+			authToken:          "",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:               "InvalidAuth",
+			authToken:          "nonempty",
+			errDecodeAuth:      cookie.ErrInvalid,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:               "InvalidBody",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            `{"password": 1}`,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusBadRequest,
+			assertFunc:         func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:               "EmptyPassword",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            `{"password": ""}`,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusBadRequest,
+			assertFunc:         assert.OnRespErr("Password cannot be empty."),
+		},
+		{
+			name:               "UserNotFound",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    db.ErrNoItem,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusNotFound,
+			assertFunc:         assert.OnRespErr("User not found."),
+		},
+		{
+			name:               "ErrRetrieveUser",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    errors.New("retrieve user failed"),
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:               "NoPassword",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           nil,
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"Set a password through a password reset before deleting " +
+					"your account.",
+			),
+		},
+		{
+			name:               "PasswordIncorrect",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         password.ErrMismatch,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusBadRequest,
+			assertFunc:         assert.OnRespErr("Password is incorrect."),
+		},
+		{
+			name:               "ErrCompare",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         errors.New("compare failed"),
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("compare failed"),
+		},
+		{
+			name:               "ErrRetrieveMembers",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: errors.New("retrieve members failed"),
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("retrieve members failed"),
+		},
+		{
+			name:               "ErrRetrieveTeam",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    errors.New("retrieve team failed"),
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:               "TeamOwner",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "bob123",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"You own the team \"Team 1\". Transfer its ownership or " +
+					"delete it before deleting your account.",
+			),
+		},
+		{
+			name:               "ErrUpdateTeam",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      errors.New("update team failed"),
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("update team failed"),
+		},
+		{
+			name:               "ErrDeleteMember",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    errors.New("delete member failed"),
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("delete member failed"),
+		},
+		{
+			name:               "ErrRetrieveTokens",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  errors.New("retrieve tokens failed"),
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("retrieve tokens failed"),
+		},
+		{
+			name:               "ErrDeleteToken",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     errors.New("delete token failed"),
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("delete token failed"),
+		},
+		{
+			name:               "ErrRetrieveIdents",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  errors.New("retrieve idents failed"),
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("retrieve idents failed"),
+		},
+		{
+			name:               "ErrDeleteIdent",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     errors.New("delete identity failed"),
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("delete identity failed"),
+		},
+		{
+			name:               "ErrDeleteAttempt",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   errors.New("delete attempt failed"),
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("delete attempt failed"),
+		},
+		{
+			name:               "ErrRevoke",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          errors.New("revoke failed"),
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("revoke failed"),
+		},
+		{
+			name:               "ErrDeleteSessions",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  errors.New("delete sessions failed"),
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("delete sessions failed"),
+		},
+		{
+			name:               "ErrDeleteUser",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      errors.New("delete user failed"),
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("delete user failed"),
+		},
+		{
+			name:               "TeamNotFound",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    db.ErrNoItem,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusOK,
+			assertFunc:         assertReport([]string{}),
+		},
+		{
+			name:               "AlreadyRemoved",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      db.ErrNoItem,
+			errDeleteMember:    db.ErrNoItem,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     db.ErrNoItem,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     db.ErrNoItem,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      db.ErrNoItem,
+			wantStatus:         http.StatusOK,
+			assertFunc:         assertReport([]string{"board1"}),
+		},
+		{
+			name:               "OK",
+			authToken:          "nonempty",
+			errDecodeAuth:      nil,
+			reqBody:            validReq,
+			password:           []byte("hash"),
+			errRetrieveUser:    nil,
+			errCompare:         nil,
+			errRetrieveMembers: nil,
+			teamOwner:          "alice",
+			errRetrieveTeam:    nil,
+			errUpdateTeam:      nil,
+			errDeleteMember:    nil,
+			errRetrieveTokens:  nil,
+			errDeleteToken:     nil,
+			errRetrieveIdents:  nil,
+			errDeleteIdent:     nil,
+			errDeleteAttempt:   nil,
+			errRevoke:          nil,
+			errDeleteSessions:  nil,
+			errDeleteUser:      nil,
+			wantStatus:         http.StatusOK,
+			assertFunc:         assertReport([]string{"board1"}),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			userRetriever.Res = usertbl.User{
+				Username: "bob123", Password: c.password,
+			}
+			userRetriever.Err = c.errRetrieveUser
+			pwdComparator.err = c.errCompare
+			memberRetriever.Err = c.errRetrieveMembers
+			teamRetriever.Res = teamtbl.Team{
+				ID:      "team1",
+				Name:    "Team 1",
+				Owner:   c.teamOwner,
+				Members: []string{"alice", "bob123"},
+				Boards: []teamtbl.Board{
+					{ID: "board1", Members: []string{"alice", "bob123"}},
+					{ID: "board2", Members: []string{"alice"}},
+				},
+			}
+			teamRetriever.Err = c.errRetrieveTeam
+			teamUpdater.Err = c.errUpdateTeam
+			memberDeleter.Err = c.errDeleteMember
+			tokenRetriever.Err = c.errRetrieveTokens
+			tokenDeleter.Err = c.errDeleteToken
+			identityRetriever.Err = c.errRetrieveIdents
+			identityDeleter.Err = c.errDeleteIdent
+			attemptDeleter.Err = c.errDeleteAttempt
+			revocationInserter.Err = c.errRevoke
+			sessionsDeleter.Err = c.errDeleteSessions
+			userDeleter.Err = c.errDeleteUser
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodDelete, "/", strings.NewReader(c.reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
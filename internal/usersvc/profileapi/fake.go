@@ -0,0 +1,22 @@
+package profileapi
+
+// fakeReqValidator is a test fake for ReqValidator.
+type fakeReqValidator struct{ validationErrs ValidationErrs }
+
+// Validate implements the ReqValidator interface on fakeReqValidator.
+func (f *fakeReqValidator) Validate(_ PatchReq) ValidationErrs {
+	return f.validationErrs
+}
+
+// fakeStrValidator is a test fake for registerapi.StrValidator.
+type fakeStrValidator struct{ errs []string }
+
+// Validate implements the registerapi.StrValidator interface on
+// fakeStrValidator.
+func (f *fakeStrValidator) Validate(_ string) []string { return f.errs }
+
+// fakeComparator is a test fake for loginapi.Comparator.
+type fakeComparator struct{ err error }
+
+// Compare implements the loginapi.Comparator interface on fakeComparator.
+func (f *fakeComparator) Compare(_ []byte, _ string) error { return f.err }
@@ -0,0 +1,120 @@
+package profileapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// Profile defines the user's profile in user profile responses.
+type Profile struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+	AvatarURL   string `json:"avatarURL"`
+	TimeZone    string `json:"timeZone"`
+	Locale      string `json:"locale"`
+}
+
+// NewProfile creates and returns a new Profile from the user's profile fields.
+func NewProfile(user usertbl.User) Profile {
+	return Profile{
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Email:       user.Email,
+		AvatarURL:   user.AvatarURL,
+		TimeZone:    user.TimeZone,
+		Locale:      user.Locale,
+	}
+}
+
+// GetResp defines the body of GET user profile responses.
+type GetResp struct {
+	Profile
+	Error string `json:"error,omitempty"`
+}
+
+// GetHandler is an api.MethodHandler that can be used to handle GET user
+// profile requests.
+type GetHandler struct {
+	authDecoder   cookie.Decoder[cookie.Auth]
+	userRetriever db.Retriever[usertbl.User]
+	log           log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	userRetriever db.Retriever[usertbl.User],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		authDecoder:   authDecoder,
+		userRetriever: userRetriever,
+		log:           log,
+	}
+}
+
+// Handle handles the GET requests sent to the user profile route.
+func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// write profile
+	if err = json.NewEncoder(w).Encode(GetResp{
+		Profile: NewProfile(user),
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,124 @@
+//go:build utest
+
+package profileapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(authDecoder, userRetriever, log)
+
+	authDecoder.Res = cookie.Auth{Username: "bob123"}
+	user := usertbl.User{
+		Username:    "bob123",
+		Email:       "bob@goteam.io",
+		Password:    []byte("hash"),
+		DisplayName: "Bob",
+		AvatarURL:   "https://goteam.io/bob.png",
+		TimeZone:    "Europe/London",
+		Locale:      "en-GB",
+	}
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		user          usertbl.User
+		errRetrieve   error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			user:          usertbl.User{},
+			errRetrieve:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			user:          usertbl.User{},
+			errRetrieve:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "UserNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			user:          usertbl.User{},
+			errRetrieve:   db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			user:          usertbl.User{},
+			errRetrieve:   errors.New("retrieve user failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			user:          user,
+			errRetrieve:   nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var body GetResp
+				err := json.NewDecoder(resp.Body).Decode(&body)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, body.Profile, Profile{
+					Username:    "bob123",
+					DisplayName: "Bob",
+					Email:       "bob@goteam.io",
+					AvatarURL:   "https://goteam.io/bob.png",
+					TimeZone:    "Europe/London",
+					Locale:      "en-GB",
+				})
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package profileapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PatchReq defines the body of PATCH user profile requests. Only the fields
+// that are set are updated, and setting a field to an empty string removes it
+// from the profile.
+type PatchReq struct {
+	DisplayName *string `json:"displayName"`
+	Email       *string `json:"email"`
+	AvatarURL   *string `json:"avatarURL"`
+	TimeZone    *string `json:"timeZone"`
+	Locale      *string `json:"locale"`
+}
+
+// PatchResp defines the body of PATCH user profile responses.
+type PatchResp struct {
+	Profile
+	Error          string         `json:"error,omitempty"`
+	ValidationErrs ValidationErrs `json:"validationErrors,omitempty"`
+}
+
+// ValidationErrs defines the validation errors returned in PatchResp.
+type ValidationErrs struct {
+	DisplayName []string `json:"displayName,omitempty"`
+	Email       []string `json:"email,omitempty"`
+	AvatarURL   []string `json:"avatarURL,omitempty"`
+	TimeZone    []string `json:"timeZone,omitempty"`
+	Locale      []string `json:"locale,omitempty"`
+}
+
+// Any checks whether there are any validation errors within the
+// ValidationErrs.
+func (e ValidationErrs) Any() bool {
+	return len(e.DisplayName) > 0 ||
+		len(e.Email) > 0 ||
+		len(e.AvatarURL) > 0 ||
+		len(e.TimeZone) > 0 ||
+		len(e.Locale) > 0
+}
+
+// PatchHandler is an api.MethodHandler that can be used to handle PATCH user
+// profile requests.
+type PatchHandler struct {
+	authDecoder   cookie.Decoder[cookie.Auth]
+	reqValidator  ReqValidator
+	userRetriever db.Retriever[usertbl.User]
+	userUpdater   db.Updater[usertbl.User]
+	log           log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	reqValidator ReqValidator,
+	userRetriever db.Retriever[usertbl.User],
+	userUpdater db.Updater[usertbl.User],
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		authDecoder:   authDecoder,
+		reqValidator:  reqValidator,
+		userRetriever: userRetriever,
+		userUpdater:   userUpdater,
+		log:           log,
+	}
+}
+
+// Handle handles the PATCH requests sent to the user profile route.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if req.DisplayName != nil {
+		*req.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+
+	// validate request
+	if vdtErrs := h.reqValidator.Validate(req); vdtErrs.Any() {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			ValidationErrs: vdtErrs,
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve user
+	user, err := h.userRetriever.Retrieve(r.Context(), auth.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// update the fields that were set on the request
+	if req.DisplayName != nil {
+		user.DisplayName = *req.DisplayName
+	}
+	if req.Email != nil {
+		// the new email hasn't been verified to belong to the user
+		if !strings.EqualFold(user.Email, *req.Email) {
+			user.EmailVerified = false
+		}
+		user.Email = *req.Email
+	}
+	if req.AvatarURL != nil {
+		user.AvatarURL = *req.AvatarURL
+	}
+	if req.TimeZone != nil {
+		user.TimeZone = *req.TimeZone
+	}
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// write the updated profile
+	if err = json.NewEncoder(w).Encode(PatchResp{
+		Profile: NewProfile(user),
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
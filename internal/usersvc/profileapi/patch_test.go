@@ -0,0 +1,197 @@
+//go:build utest
+
+package profileapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	reqValidator := &fakeReqValidator{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(
+		authDecoder, reqValidator, userRetriever, userUpdater, log,
+	)
+
+	authDecoder.Res = cookie.Auth{Username: "bob123"}
+	userRetriever.Res = usertbl.User{
+		Username:    "bob123",
+		Email:       "bob@goteam.io",
+		DisplayName: "Bob",
+		TimeZone:    "Europe/London",
+		Locale:      "en-GB",
+	}
+	validReq := `{"displayName": "  Bobby ", "locale": ""}`
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		reqBody       string
+		vdtErrs       ValidationErrs
+		errRetrieve   error
+		errUpdate     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			vdtErrs:       ValidationErrs{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			reqBody:       validReq,
+			vdtErrs:       ValidationErrs{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "InvalidBody",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       `{"displayName": 1}`,
+			vdtErrs:       ValidationErrs{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:          "InvalidProfile",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			vdtErrs: ValidationErrs{
+				Locale: []string{"Locale is not a valid language tag."},
+			},
+			errRetrieve: nil,
+			errUpdate:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var body PatchResp
+				err := json.NewDecoder(resp.Body).Decode(&body)
+				assert.Nil(t.Fatal, err)
+				assert.AllEqual(t.Error,
+					body.ValidationErrs.Locale,
+					[]string{"Locale is not a valid language tag."},
+				)
+			},
+		},
+		{
+			name:          "UserNotFound",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			vdtErrs:       ValidationErrs{},
+			errRetrieve:   db.ErrNoItem,
+			errUpdate:     nil,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			vdtErrs:       ValidationErrs{},
+			errRetrieve:   errors.New("retrieve user failed"),
+			errUpdate:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:          "UserNotFoundOnUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			vdtErrs:       ValidationErrs{},
+			errRetrieve:   nil,
+			errUpdate:     db.ErrNoItem,
+			wantStatus:    http.StatusNotFound,
+			assertFunc:    assert.OnRespErr("User not found."),
+		},
+		{
+			name:          "ErrUpdate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			vdtErrs:       ValidationErrs{},
+			errRetrieve:   nil,
+			errUpdate:     errors.New("update user failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			reqBody:       validReq,
+			vdtErrs:       ValidationErrs{},
+			errRetrieve:   nil,
+			errUpdate:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var body PatchResp
+				err := json.NewDecoder(resp.Body).Decode(&body)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, body.Profile, Profile{
+					Username:    "bob123",
+					DisplayName: "Bobby",
+					Email:       "bob@goteam.io",
+					AvatarURL:   "",
+					TimeZone:    "Europe/London",
+					Locale:      "",
+				})
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			reqValidator.validationErrs = c.vdtErrs
+			userRetriever.Err = c.errRetrieve
+			userUpdater.Err = c.errUpdate
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch, "/", strings.NewReader(c.reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
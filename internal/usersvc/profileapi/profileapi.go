@@ -0,0 +1,4 @@
+// Package profileapi contains code for responding to HTTP requests made to the
+// user profile API route, which is used by users for viewing and editing their
+// display name, email, avatar, time zone, and locale.
+package profileapi
@@ -0,0 +1,164 @@
+package profileapi
+
+import (
+	"net/url"
+	"regexp"
+	"time"
+	"unicode"
+
+	// embed the time zone database so that time zones can be validated on
+	// systems that don't have it installed
+	_ "time/tzdata"
+
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+)
+
+// ReqValidator describes a type that validates a request body and returns
+// validation errors that occur.
+type ReqValidator interface{ Validate(PatchReq) ValidationErrs }
+
+// ProfileValidator is the ReqValidator for the user profile route. Only the
+// fields that are set on the request are validated.
+type ProfileValidator struct {
+	DisplayNameValidator registerapi.StrValidator
+	EmailValidator       registerapi.StrValidator
+	AvatarURLValidator   registerapi.StrValidator
+	TimeZoneValidator    registerapi.StrValidator
+	LocaleValidator      registerapi.StrValidator
+}
+
+// NewProfileValidator creates and returns a new ProfileValidator.
+func NewProfileValidator(
+	displayNameValidator,
+	emailValidator,
+	avatarURLValidator,
+	timeZoneValidator,
+	localeValidator registerapi.StrValidator,
+) ProfileValidator {
+	return ProfileValidator{
+		DisplayNameValidator: displayNameValidator,
+		EmailValidator:       emailValidator,
+		AvatarURLValidator:   avatarURLValidator,
+		TimeZoneValidator:    timeZoneValidator,
+		LocaleValidator:      localeValidator,
+	}
+}
+
+// Validate uses the ProfileValidator's field validators to validate the fields
+// that are set on requests sent to the user profile route.
+func (v ProfileValidator) Validate(req PatchReq) ValidationErrs {
+	var errs ValidationErrs
+	if req.DisplayName != nil {
+		errs.DisplayName = v.DisplayNameValidator.Validate(*req.DisplayName)
+	}
+	if req.Email != nil {
+		errs.Email = v.EmailValidator.Validate(*req.Email)
+	}
+	if req.AvatarURL != nil {
+		errs.AvatarURL = v.AvatarURLValidator.Validate(*req.AvatarURL)
+	}
+	if req.TimeZone != nil {
+		errs.TimeZone = v.TimeZoneValidator.Validate(*req.TimeZone)
+	}
+	if req.Locale != nil {
+		errs.Locale = v.LocaleValidator.Validate(*req.Locale)
+	}
+	return errs
+}
+
+// DisplayNameValidator is the display name field validator for the user profile
+// route. An empty display name is valid and removes it from the profile.
+type DisplayNameValidator struct{}
+
+// NewDisplayNameValidator creates and returns a new DisplayNameValidator.
+func NewDisplayNameValidator() DisplayNameValidator {
+	return DisplayNameValidator{}
+}
+
+// Validate applies display name validation rules to the display name string
+// and returns the error message if any fails.
+func (v DisplayNameValidator) Validate(name string) (errs []string) {
+	if len([]rune(name)) > 50 {
+		errs = append(errs, "Display name cannot be longer than 50 characters.")
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			errs = append(
+				errs, "Display name cannot contain control characters.",
+			)
+			break
+		}
+	}
+	return
+}
+
+// AvatarURLValidator is the avatar URL field validator for the user profile
+// route. An empty avatar URL is valid and removes it from the profile.
+type AvatarURLValidator struct{}
+
+// NewAvatarURLValidator creates and returns a new AvatarURLValidator.
+func NewAvatarURLValidator() AvatarURLValidator { return AvatarURLValidator{} }
+
+// Validate applies avatar URL validation rules to the avatar URL string and
+// returns the error message if any fails.
+func (v AvatarURLValidator) Validate(avatarURL string) (errs []string) {
+	if avatarURL == "" {
+		return
+	}
+	if len(avatarURL) > 2048 {
+		errs = append(errs, "Avatar URL cannot be longer than 2048 characters.")
+		return
+	}
+	// only https is allowed so that the avatar can't be used to run scripts
+	// or to serve mixed content to the client
+	u, err := url.Parse(avatarURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		errs = append(errs, "Avatar URL must be a valid HTTPS URL.")
+	}
+	return
+}
+
+// TimeZoneValidator is the time zone field validator for the user profile
+// route. An empty time zone is valid and removes it from the profile.
+type TimeZoneValidator struct{}
+
+// NewTimeZoneValidator creates and returns a new TimeZoneValidator.
+func NewTimeZoneValidator() TimeZoneValidator { return TimeZoneValidator{} }
+
+// Validate applies time zone validation rules to the time zone string and
+// returns the error message if any fails.
+func (v TimeZoneValidator) Validate(tz string) (errs []string) {
+	if tz == "" {
+		return
+	}
+	// "Local" is accepted by time.LoadLocation but means the server's time zone
+	if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+		errs = append(errs, "Time zone is not a valid IANA time zone.")
+	}
+	return
+}
+
+// localeRegexp matches language tags made up of a language and an optional
+// script and region, e.g. "en", "en-GB", "zh-Hant-TW", or "es-419".
+var localeRegexp = regexp.MustCompile(
+	"^[a-zA-Z]{2,3}(-[a-zA-Z]{4})?(-([a-zA-Z]{2}|[0-9]{3}))?$",
+)
+
+// LocaleValidator is the locale field validator for the user profile route. An
+// empty locale is valid and removes it from the profile.
+type LocaleValidator struct{}
+
+// NewLocaleValidator creates and returns a new LocaleValidator.
+func NewLocaleValidator() LocaleValidator { return LocaleValidator{} }
+
+// Validate applies locale validation rules to the locale string and returns
+// the error message if any fails.
+func (v LocaleValidator) Validate(locale string) (errs []string) {
+	if locale == "" {
+		return
+	}
+	if !localeRegexp.MatchString(locale) {
+		errs = append(errs, "Locale is not a valid language tag.")
+	}
+	return
+}
@@ -0,0 +1,220 @@
+//go:build utest
+
+package profileapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// These constants are declared separately from the strings that are actually
+// used in validators so that the corresponding assertions fail when the strings
+// used are accidentally edited.
+const (
+	nameTooLong     = "Display name cannot be longer than 50 characters."
+	nameControlChar = "Display name cannot contain control characters."
+
+	avatarTooLong = "Avatar URL cannot be longer than 2048 characters."
+	avatarInvalid = "Avatar URL must be a valid HTTPS URL."
+
+	tzInvalid = "Time zone is not a valid IANA time zone."
+
+	localeInvalid = "Locale is not a valid language tag."
+)
+
+// TestProfileValidator tests the ProfileValidator's Validate method to ensure
+// that it validates only the fields that are set on the request.
+func TestProfileValidator(t *testing.T) {
+	nameValidator := &fakeStrValidator{errs: []string{nameTooLong}}
+	emailValidator := &fakeStrValidator{errs: []string{"email"}}
+	avatarValidator := &fakeStrValidator{errs: []string{avatarInvalid}}
+	tzValidator := &fakeStrValidator{errs: []string{tzInvalid}}
+	localeValidator := &fakeStrValidator{errs: []string{localeInvalid}}
+	sut := NewProfileValidator(
+		nameValidator,
+		emailValidator,
+		avatarValidator,
+		tzValidator,
+		localeValidator,
+	)
+	str := "nonempty"
+
+	for _, c := range []struct {
+		name     string
+		req      PatchReq
+		wantErrs ValidationErrs
+	}{
+		{name: "NoFields", req: PatchReq{}, wantErrs: ValidationErrs{}},
+		{
+			name: "SomeFields",
+			req:  PatchReq{DisplayName: &str, TimeZone: &str},
+			wantErrs: ValidationErrs{
+				DisplayName: []string{nameTooLong},
+				TimeZone:    []string{tzInvalid},
+			},
+		},
+		{
+			name: "AllFields",
+			req: PatchReq{
+				DisplayName: &str,
+				Email:       &str,
+				AvatarURL:   &str,
+				TimeZone:    &str,
+				Locale:      &str,
+			},
+			wantErrs: ValidationErrs{
+				DisplayName: []string{nameTooLong},
+				Email:       []string{"email"},
+				AvatarURL:   []string{avatarInvalid},
+				TimeZone:    []string{tzInvalid},
+				Locale:      []string{localeInvalid},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			errs := sut.Validate(c.req)
+
+			assert.AllEqual(t.Error, errs.DisplayName, c.wantErrs.DisplayName)
+			assert.AllEqual(t.Error, errs.Email, c.wantErrs.Email)
+			assert.AllEqual(t.Error, errs.AvatarURL, c.wantErrs.AvatarURL)
+			assert.AllEqual(t.Error, errs.TimeZone, c.wantErrs.TimeZone)
+			assert.AllEqual(t.Error, errs.Locale, c.wantErrs.Locale)
+		})
+	}
+}
+
+// TestDisplayNameValidator tests the DisplayNameValidator to assert that it
+// returns the correct error strings based on the display name passed to it.
+func TestDisplayNameValidator(t *testing.T) {
+	sut := NewDisplayNameValidator()
+
+	for _, c := range []struct {
+		name        string
+		displayName string
+		wantErrs    []string
+	}{
+		{name: "Empty", displayName: "", wantErrs: nil},
+		{
+			name:        "TooLong",
+			displayName: strings.Repeat("b", 51),
+			wantErrs:    []string{nameTooLong},
+		},
+		{
+			name:        "ControlChar",
+			displayName: "Bob\nSmith",
+			wantErrs:    []string{nameControlChar},
+		},
+		{
+			name:        "TooLong,ControlChar",
+			displayName: strings.Repeat("b", 50) + "\t",
+			wantErrs:    []string{nameTooLong, nameControlChar},
+		},
+		{name: "OK", displayName: "Bøb Smith", wantErrs: nil},
+		{
+			name:        "OKMaxLength",
+			displayName: strings.Repeat("ö", 50),
+			wantErrs:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			errs := sut.Validate(c.displayName)
+			assert.AllEqual(t.Error, errs, c.wantErrs)
+		})
+	}
+}
+
+// TestAvatarURLValidator tests the AvatarURLValidator to assert that it
+// returns the correct error strings based on the avatar URL passed to it.
+func TestAvatarURLValidator(t *testing.T) {
+	sut := NewAvatarURLValidator()
+
+	for _, c := range []struct {
+		name      string
+		avatarURL string
+		wantErrs  []string
+	}{
+		{name: "Empty", avatarURL: "", wantErrs: nil},
+		{
+			name:      "TooLong",
+			avatarURL: "https://goteam.io/" + strings.Repeat("b", 2031),
+			wantErrs:  []string{avatarTooLong},
+		},
+		{
+			name:      "NotURL",
+			avatarURL: "https://go team.io/bob.png",
+			wantErrs:  []string{avatarInvalid},
+		},
+		{
+			name:      "Relative",
+			avatarURL: "/bob.png",
+			wantErrs:  []string{avatarInvalid},
+		},
+		{
+			name:      "HTTP",
+			avatarURL: "http://goteam.io/bob.png",
+			wantErrs:  []string{avatarInvalid},
+		},
+		{
+			name:      "JavaScript",
+			avatarURL: "javascript:alert(1)",
+			wantErrs:  []string{avatarInvalid},
+		},
+		{name: "OK", avatarURL: "https://goteam.io/bob.png", wantErrs: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			errs := sut.Validate(c.avatarURL)
+			assert.AllEqual(t.Error, errs, c.wantErrs)
+		})
+	}
+}
+
+// TestTimeZoneValidator tests the TimeZoneValidator to assert that it returns
+// the correct error strings based on the time zone passed to it.
+func TestTimeZoneValidator(t *testing.T) {
+	sut := NewTimeZoneValidator()
+
+	for _, c := range []struct {
+		name     string
+		tz       string
+		wantErrs []string
+	}{
+		{name: "Empty", tz: "", wantErrs: nil},
+		{name: "Unknown", tz: "Mars/Olympus", wantErrs: []string{tzInvalid}},
+		{name: "Local", tz: "Local", wantErrs: []string{tzInvalid}},
+		{name: "OKUTC", tz: "UTC", wantErrs: nil},
+		{name: "OK", tz: "Europe/London", wantErrs: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			errs := sut.Validate(c.tz)
+			assert.AllEqual(t.Error, errs, c.wantErrs)
+		})
+	}
+}
+
+// TestLocaleValidator tests the LocaleValidator to assert that it returns the
+// correct error strings based on the locale passed to it.
+func TestLocaleValidator(t *testing.T) {
+	sut := NewLocaleValidator()
+
+	for _, c := range []struct {
+		name     string
+		locale   string
+		wantErrs []string
+	}{
+		{name: "Empty", locale: "", wantErrs: nil},
+		{name: "TooShort", locale: "e", wantErrs: []string{localeInvalid}},
+		{name: "Underscore", locale: "en_GB", wantErrs: []string{localeInvalid}},
+		{name: "BadRegion", locale: "en-GBR", wantErrs: []string{localeInvalid}},
+		{name: "OKLanguage", locale: "en", wantErrs: nil},
+		{name: "OKRegion", locale: "en-GB", wantErrs: nil},
+		{name: "OKScript", locale: "zh-Hant-TW", wantErrs: nil},
+		{name: "OKNumericRegion", locale: "es-419", wantErrs: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			errs := sut.Validate(c.locale)
+			assert.AllEqual(t.Error, errs, c.wantErrs)
+		})
+	}
+}
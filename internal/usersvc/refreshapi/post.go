@@ -0,0 +1,197 @@
+package refreshapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PostResp defines the body of POST refresh responses.
+type PostResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can be used to handle POST refresh
+// requests.
+type PostHandler struct {
+	refreshDecoder   cookie.Decoder[cookie.Refresh]
+	sessionRetriever db.Retriever[sessiontbl.Session]
+	userRetriever    db.Retriever[usertbl.User]
+	refreshEncoder   cookie.Encoder[cookie.Refresh]
+	authEncoder      cookie.Encoder[cookie.Auth]
+	sessionUpdater   db.UpdaterDualKey[sessiontbl.Session]
+	sessionDeleter   db.Deleter
+	log              log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	refreshDecoder cookie.Decoder[cookie.Refresh],
+	sessionRetriever db.Retriever[sessiontbl.Session],
+	userRetriever db.Retriever[usertbl.User],
+	refreshEncoder cookie.Encoder[cookie.Refresh],
+	authEncoder cookie.Encoder[cookie.Auth],
+	sessionUpdater db.UpdaterDualKey[sessiontbl.Session],
+	sessionDeleter db.Deleter,
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		refreshDecoder:   refreshDecoder,
+		sessionRetriever: sessionRetriever,
+		userRetriever:    userRetriever,
+		refreshEncoder:   refreshEncoder,
+		authEncoder:      authEncoder,
+		sessionUpdater:   sessionUpdater,
+		sessionDeleter:   sessionDeleter,
+		log:              log,
+	}
+}
+
+// Handle handles the POST requests sent to the refresh route.
+func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get refresh token
+	ckRefresh, err := r.Cookie(cookie.RefreshName)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Refresh token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode refresh token
+	ref, err := h.refreshDecoder.Decode(*ckRefresh)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Invalid refresh token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the session that the refresh token belongs to
+	session, err := h.sessionRetriever.Retrieve(r.Context(), ref.FamilyID)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Session not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// if the refresh token is not the latest one in its family, it has been
+	// used before - revoke the whole family as it may have been stolen
+	if session.TokenID != ref.TokenID {
+		h.revoke(w, r, session.ID)
+		return
+	}
+
+	// retrieve the user that the session belongs to so that the new auth
+	// token reflects their current state
+	user, err := h.userRetriever.Retrieve(r.Context(), session.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		if err = h.sessionDeleter.Delete(r.Context(), session.ID); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// encode a rotated refresh token and a new auth token
+	tokenID := uuid.NewString()
+	ckNewRefresh, err := h.refreshEncoder.Encode(
+		cookie.NewRefresh(session.ID, tokenID),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	ckAuth, err := h.authEncoder.Encode(cookie.NewAuth(
+		user.Username, user.Role, user.TeamID,
+	))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// store the rotated refresh token's ID in the session - if the token was
+	// rotated concurrently since it was retrieved, it is being reused
+	if err = h.sessionUpdater.Update(
+		r.Context(), ref.TokenID, sessiontbl.NewSession(
+			session.ID,
+			session.Username,
+			tokenID,
+			ckNewRefresh.Expires.Unix(),
+		),
+	); errors.Is(err, db.ErrNoItem) {
+		h.revoke(w, r, session.ID)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// set auth and refresh cookies
+	http.SetCookie(w, &ckAuth)
+	http.SetCookie(w, &ckNewRefresh)
+}
+
+// revoke deletes the session with the given ID, which revokes all refresh
+// tokens in its family, and writes an unauthorized response.
+func (h PostHandler) revoke(w http.ResponseWriter, r *http.Request, id string) {
+	if err := h.sessionDeleter.Delete(r.Context(), id); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	w.WriteHeader(http.StatusUnauthorized)
+	if err := json.NewEncoder(w).Encode(PostResp{
+		Error: "Refresh token has already been used. Please log in again.",
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
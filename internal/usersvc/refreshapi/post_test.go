@@ -0,0 +1,314 @@
+//go:build utest
+
+package refreshapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	refreshDecoder := &cookie.FakeDecoder[cookie.Refresh]{}
+	sessionRetriever := &db.FakeRetriever[sessiontbl.Session]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	refreshEncoder := &cookie.FakeEncoder[cookie.Refresh]{}
+	authEncoder := &cookie.FakeEncoder[cookie.Auth]{}
+	sessionUpdater := &db.FakeUpdaterDualKey[sessiontbl.Session]{}
+	sessionDeleter := &db.FakeDeleter{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		refreshDecoder,
+		sessionRetriever,
+		userRetriever,
+		refreshEncoder,
+		authEncoder,
+		sessionUpdater,
+		sessionDeleter,
+		log,
+	)
+
+	refreshDecoder.Res = cookie.NewRefresh("familyid", "tokenid")
+	refreshEncoder.Res = http.Cookie{
+		Name: cookie.RefreshName, Value: "refreshtoken",
+	}
+	authEncoder.Res = http.Cookie{Name: cookie.AuthName, Value: "authtoken"}
+	session := sessiontbl.NewSession("familyid", "bob123", "tokenid", 0)
+	rotated := sessiontbl.NewSession("familyid", "bob123", "newtokenid", 0)
+	user := usertbl.NewUser("bob123", "", []byte("hash"), role.Owner, "bob123")
+
+	for _, c := range []struct {
+		name               string
+		refreshToken       string
+		errDecode          error
+		session            sessiontbl.Session
+		errRetrieveSession error
+		user               usertbl.User
+		errRetrieveUser    error
+		errEncodeRefresh   error
+		errEncodeAuth      error
+		errUpdate          error
+		errDelete          error
+		wantStatus         int
+		assertFunc         func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:               "NoRefresh",
+			refreshToken:       "",
+			errDecode:          nil,
+			session:            sessiontbl.Session{},
+			errRetrieveSession: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         assert.OnRespErr("Refresh token not found."),
+		},
+		{
+			name:               "InvalidRefresh",
+			refreshToken:       "nonempty",
+			errDecode:          cookie.ErrInvalid,
+			session:            sessiontbl.Session{},
+			errRetrieveSession: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         assert.OnRespErr("Invalid refresh token."),
+		},
+		{
+			name:               "SessionNotFound",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            sessiontbl.Session{},
+			errRetrieveSession: db.ErrNoItem,
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         assert.OnRespErr("Session not found."),
+		},
+		{
+			name:               "ErrRetrieveSession",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            sessiontbl.Session{},
+			errRetrieveSession: errors.New("retrieve session failed"),
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("retrieve session failed"),
+		},
+		{
+			name:               "TokenReusedErrDelete",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            rotated,
+			errRetrieveSession: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          errors.New("delete session failed"),
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("delete session failed"),
+		},
+		{
+			name:               "TokenReused",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            rotated,
+			errRetrieveSession: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr(
+				"Refresh token has already been used. Please log in again.",
+			),
+		},
+		{
+			name:               "UserNotFoundErrDelete",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            session,
+			errRetrieveSession: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    db.ErrNoItem,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          errors.New("delete session failed"),
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("delete session failed"),
+		},
+		{
+			name:               "UserNotFound",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            session,
+			errRetrieveSession: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    db.ErrNoItem,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc:         assert.OnRespErr("User not found."),
+		},
+		{
+			name:               "ErrRetrieveUser",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            session,
+			errRetrieveSession: nil,
+			user:               usertbl.User{},
+			errRetrieveUser:    errors.New("retrieve user failed"),
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:               "ErrEncodeRefresh",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            session,
+			errRetrieveSession: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   errors.New("encode refresh failed"),
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("encode refresh failed"),
+		},
+		{
+			name:               "ErrEncodeAuth",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            session,
+			errRetrieveSession: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      errors.New("encode auth failed"),
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("encode auth failed"),
+		},
+		{
+			name:               "RotatedConcurrently",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            session,
+			errRetrieveSession: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          db.ErrNoItem,
+			errDelete:          nil,
+			wantStatus:         http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr(
+				"Refresh token has already been used. Please log in again.",
+			),
+		},
+		{
+			name:               "ErrUpdate",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            session,
+			errRetrieveSession: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          errors.New("update session failed"),
+			errDelete:          nil,
+			wantStatus:         http.StatusInternalServerError,
+			assertFunc:         assert.OnLoggedErr("update session failed"),
+		},
+		{
+			name:               "OK",
+			refreshToken:       "nonempty",
+			errDecode:          nil,
+			session:            session,
+			errRetrieveSession: nil,
+			user:               user,
+			errRetrieveUser:    nil,
+			errEncodeRefresh:   nil,
+			errEncodeAuth:      nil,
+			errUpdate:          nil,
+			errDelete:          nil,
+			wantStatus:         http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[0].Name, cookie.AuthName)
+				assert.Equal(t.Error, cks[0].Value, "authtoken")
+				assert.Equal(t.Error, cks[1].Name, cookie.RefreshName)
+				assert.Equal(t.Error, cks[1].Value, "refreshtoken")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			refreshDecoder.Err = c.errDecode
+			sessionRetriever.Res = c.session
+			sessionRetriever.Err = c.errRetrieveSession
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieveUser
+			refreshEncoder.Err = c.errEncodeRefresh
+			authEncoder.Err = c.errEncodeAuth
+			sessionUpdater.Err = c.errUpdate
+			sessionDeleter.Err = c.errDelete
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if c.refreshToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  cookie.RefreshName,
+					Value: c.refreshToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
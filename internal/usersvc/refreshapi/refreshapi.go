@@ -0,0 +1,4 @@
+// Package refreshapi contains code for responding to HTTP requests made to the
+// refresh API route, which is used for exchanging a refresh token for a new
+// auth token and a rotated refresh token.
+package refreshapi
@@ -24,3 +24,16 @@ type fakeHasher struct {
 func (f *fakeHasher) Hash(_ string) ([]byte, error) {
 	return f.hash, f.err
 }
+
+// fakeBlocklist is a test fake for Blocklist.
+type fakeBlocklist struct{ pwds []string }
+
+// Contains implements the Blocklist interface on fakeBlocklist.
+func (f *fakeBlocklist) Contains(pwd string) bool {
+	for _, p := range f.pwds {
+		if p == pwd {
+			return true
+		}
+	}
+	return false
+}
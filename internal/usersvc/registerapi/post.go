@@ -2,17 +2,28 @@ package registerapi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
 
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 // PostReq defines the body of POST register requests.
 type PostReq struct {
 	Username string `json:"username"`
+	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
@@ -25,41 +36,63 @@ type PostResp struct {
 // ValidationErrs defines the validation errors returned in POSTResp.
 type ValidationErrs struct {
 	Username []string `json:"username,omitempty"`
+	Email    []string `json:"email,omitempty"`
 	Password []string `json:"password,omitempty"`
 }
 
 // Any checks whether there are any validation errors within the ValidationErrors.
 func (e ValidationErrs) Any() bool {
-	return len(e.Username) > 0 || len(e.Password) > 0
+	return len(e.Username) > 0 || len(e.Email) > 0 || len(e.Password) > 0
 }
 
 // PostHandler is a api.MethodHandler that can be used to handle POST register
 // requests.
 type PostHandler struct {
-	reqValidator  ReqValidator
-	hasher        Hasher
-	inviteDecoder cookie.StringDecoder[cookie.Invite]
-	userInserter  db.Inserter[usertbl.User]
-	authEncoder   cookie.Encoder[cookie.Auth]
-	log           log.Errorer
+	reqValidator       ReqValidator
+	hasher             Hasher
+	inviteConsumer     db.ConsumerDualKey[invitetbl.Invite]
+	inviteReleaser     db.Releaser
+	teamRetriever      db.Retriever[teamtbl.Team]
+	memberRetriever    db.Retriever[[]membershiptbl.Membership]
+	limits             quota.Limits
+	userInserter       db.Inserter[usertbl.User]
+	membershipInserter db.Inserter[membershiptbl.Membership]
+	authEncoder        cookie.Encoder[cookie.Auth]
+	refreshEncoder     cookie.Encoder[cookie.Refresh]
+	sessionInserter    db.Inserter[sessiontbl.Session]
+	log                log.Errorer
 }
 
 // NewPostHandler creates and returns a new HandlerPost.
 func NewPostHandler(
 	userValidator ReqValidator,
-	inviteDecoder cookie.StringDecoder[cookie.Invite],
+	inviteConsumer db.ConsumerDualKey[invitetbl.Invite],
+	inviteReleaser db.Releaser,
+	teamRetriever db.Retriever[teamtbl.Team],
+	memberRetriever db.Retriever[[]membershiptbl.Membership],
+	limits quota.Limits,
 	hasher Hasher,
 	userInserter db.Inserter[usertbl.User],
+	membershipInserter db.Inserter[membershiptbl.Membership],
 	authEncoder cookie.Encoder[cookie.Auth],
+	refreshEncoder cookie.Encoder[cookie.Refresh],
+	sessionInserter db.Inserter[sessiontbl.Session],
 	log log.Errorer,
 ) PostHandler {
 	return PostHandler{
-		reqValidator:  userValidator,
-		hasher:        hasher,
-		inviteDecoder: inviteDecoder,
-		userInserter:  userInserter,
-		authEncoder:   authEncoder,
-		log:           log,
+		reqValidator:       userValidator,
+		hasher:             hasher,
+		inviteConsumer:     inviteConsumer,
+		inviteReleaser:     inviteReleaser,
+		teamRetriever:      teamRetriever,
+		memberRetriever:    memberRetriever,
+		limits:             limits,
+		userInserter:       userInserter,
+		membershipInserter: membershipInserter,
+		authEncoder:        authEncoder,
+		refreshEncoder:     refreshEncoder,
+		sessionInserter:    sessionInserter,
+		log:                log,
 	}
 }
 
@@ -86,16 +119,28 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		return
 	}
 
-	// determine teamID and isAdmin based on invite token.
+	// hash password
+	pwdHash, err := h.hasher.Hash(req.Password)
+	if err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// determine teamID and role based on invite token - the invite is
+	// consumed before the user is inserted so that it cannot be used more
+	// times than allowed by concurrent requests
 	invCode := r.URL.Query().Get("inviteToken")
 	var teamID string
-	var isAdmin bool
+	var userRole role.Role
 	if invCode == "" {
-		teamID = req.Username
-		isAdmin = true
+		teamID = uuid.NewString()
+		userRole = role.Owner
 	} else {
-		invite, err := h.inviteDecoder.Decode(invCode)
-		if err != nil {
+		invite, err := h.inviteConsumer.Consume(
+			r.Context(), invCode, req.Username,
+		)
+		if errors.Is(err, db.ErrNoItem) {
 			w.WriteHeader(http.StatusBadRequest)
 			if err := json.NewEncoder(w).Encode(
 				PostResp{Err: "Invalid invite token."},
@@ -104,23 +149,58 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 				h.log.Error(err)
 			}
 			return
+		} else if err != nil {
+			h.log.Error(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
 		}
 		teamID = invite.TeamID
-		isAdmin = false
-	}
 
-	// hash password
-	pwdHash, err := h.hasher.Hash(req.Password)
-	if err != nil {
-		h.log.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		// invites created before roles were introduced don't have one
+		userRole = invite.Role
+		if !userRole.IsValid() {
+			userRole = role.Member
+		}
+
+		// make sure the team has room for another member - the team may not
+		// have been created yet, in which case no limits are overridden
+		team, err := h.teamRetriever.Retrieve(r.Context(), teamID)
+		if err != nil && !errors.Is(err, db.ErrNoItem) {
+			h.releaseInvite(r, invCode)
+			h.log.Error(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		members, err := h.memberRetriever.Retrieve(r.Context(), teamID)
+		if err != nil {
+			h.releaseInvite(r, invCode)
+			h.log.Error(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		limit := h.limits.Override(team.Quota).Members
+		if !quota.Allows(limit, len(members), 1) {
+			h.releaseInvite(r, invCode)
+			w.WriteHeader(http.StatusBadRequest)
+			if err := json.NewEncoder(w).Encode(PostResp{
+				Err: "The team you were invited to has reached its limit " +
+					"of " + strconv.Itoa(limit) + " members.",
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
+		}
 	}
 
 	// insert a new user into the user table
-	if err = h.userInserter.Insert(r.Context(), usertbl.NewUser(
-		req.Username, pwdHash, isAdmin, teamID,
-	)); err == db.ErrDupKey {
+	err = h.userInserter.Insert(r.Context(), usertbl.NewUser(
+		req.Username, req.Email, pwdHash, userRole, teamID,
+	))
+	if err != nil && invCode != "" {
+		h.releaseInvite(r, invCode)
+	}
+	if err == db.ErrDupKey {
 		w.WriteHeader(http.StatusBadRequest)
 		if err := json.NewEncoder(w).Encode(
 			PostResp{ValidationErrs: ValidationErrs{
@@ -137,11 +217,55 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		return
 	}
 
+	// record the user's membership of the team they registered into
+	if err = h.membershipInserter.Insert(
+		r.Context(),
+		membershiptbl.NewMembership(req.Username, teamID, userRole),
+	); err != nil {
+		h.log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		if err := json.NewEncoder(w).Encode(
+			PostResp{
+				Err: "You have been registered successfully but something " +
+					"went wrong. Please log in using the credentials you " +
+					"registered with.",
+			},
+		); err != nil {
+			h.log.Error(err)
+		}
+		return
+	}
+
 	// generate an auth token
 	ckAuth, err := h.authEncoder.Encode(
-		cookie.NewAuth(req.Username, isAdmin, teamID),
+		cookie.NewAuth(req.Username, userRole, teamID),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		if err := json.NewEncoder(w).Encode(
+			PostResp{
+				Err: "You have been registered successfully but something " +
+					"went wrong. Please log in using the credentials you " +
+					"registered with.",
+			},
+		); err != nil {
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// start a new session and generate a refresh token for it
+	familyID, tokenID := uuid.NewString(), uuid.NewString()
+	ckRefresh, err := h.refreshEncoder.Encode(
+		cookie.NewRefresh(familyID, tokenID),
 	)
+	if err == nil {
+		err = h.sessionInserter.Insert(r.Context(), sessiontbl.NewSession(
+			familyID, req.Username, tokenID, ckRefresh.Expires.Unix(),
+		))
+	}
 	if err != nil {
+		h.log.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		if err := json.NewEncoder(w).Encode(
 			PostResp{
@@ -155,6 +279,17 @@ func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
 		return
 	}
 
-	// set auth cookie
+	// set auth and refresh cookies
 	http.SetCookie(w, &ckAuth)
+	http.SetCookie(w, &ckRefresh)
+}
+
+// releaseInvite gives back the use of the invite with the given code since the
+// user couldn't register with it.
+func (h PostHandler) releaseInvite(r *http.Request, invCode string) {
+	if err := h.inviteReleaser.Release(
+		r.Context(), invCode,
+	); err != nil && !errors.Is(err, db.ErrNoItem) {
+		h.log.Error(err)
+	}
 }
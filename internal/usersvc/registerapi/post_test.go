@@ -13,21 +13,44 @@ import (
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 func TestHandler(t *testing.T) {
 	var (
-		userValidator = &fakeReqValidator{}
-		hasher        = &fakeHasher{}
-		inviteDecoder = &cookie.FakeStringDecoder[cookie.Invite]{}
-		userInserter  = &db.FakeInserter[usertbl.User]{}
-		authEncoder   = &cookie.FakeEncoder[cookie.Auth]{}
-		log           = &log.FakeErrorer{}
+		userValidator   = &fakeReqValidator{}
+		hasher          = &fakeHasher{}
+		inviteConsumer  = &db.FakeConsumerDualKey[invitetbl.Invite]{}
+		inviteReleaser  = &db.FakeReleaser{}
+		teamRetriever   = &db.FakeRetriever[teamtbl.Team]{}
+		memberRetriever = &db.FakeRetriever[[]membershiptbl.Membership]{}
+		userInserter    = &db.FakeInserter[usertbl.User]{}
+		memberInserter  = &db.FakeInserter[membershiptbl.Membership]{}
+		authEncoder     = &cookie.FakeEncoder[cookie.Auth]{}
+		refreshEncoder  = &cookie.FakeEncoder[cookie.Refresh]{}
+		sessionInserter = &db.FakeInserter[sessiontbl.Session]{}
+		log             = &log.FakeErrorer{}
 	)
 	sut := NewPostHandler(
-		userValidator, inviteDecoder, hasher, userInserter, authEncoder, log,
+		userValidator,
+		inviteConsumer,
+		inviteReleaser,
+		teamRetriever,
+		memberRetriever,
+		quota.Limits{Members: 3},
+		hasher,
+		userInserter,
+		memberInserter,
+		authEncoder,
+		refreshEncoder,
+		sessionInserter,
+		log,
 	)
 
 	// Used in status 400 cases to assert on validation errors.
@@ -52,19 +75,28 @@ func TestHandler(t *testing.T) {
 
 	validRBody := `{"username": "bob123", "password": "Myp4ssword!"}`
 	for _, c := range []struct {
-		name            string
-		req             string
-		errValidate     ValidationErrs
-		tkInvite        string
-		inviteDecoded   cookie.Invite
-		errDecodeInvite error
-		pwdHash         []byte
-		errHash         error
-		errInsertUser   error
-		authToken       http.Cookie
-		errEncodeAuth   error
-		wantStatus      int
-		assertFunc      func(*testing.T, *http.Response, []any)
+		name             string
+		req              string
+		errValidate      ValidationErrs
+		tkInvite         string
+		inviteConsumed   invitetbl.Invite
+		errConsumeInvite error
+		errReleaseInvite error
+		team             teamtbl.Team
+		errRetrieveTeam  error
+		members          []membershiptbl.Membership
+		errRetrieveMembs error
+		pwdHash          []byte
+		errHash          error
+		errInsertUser    error
+		errInsertMember  error
+		authToken        http.Cookie
+		errEncodeAuth    error
+		refreshToken     http.Cookie
+		errEncodeRefresh error
+		errInsertSession error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
 	}{
 		{
 			name: "ErrsValidate",
@@ -72,15 +104,18 @@ func TestHandler(t *testing.T) {
 			errValidate: ValidationErrs{
 				Username: []string{idTooLong}, Password: []string{pwdNoDigit},
 			},
-			tkInvite:        "",
-			inviteDecoded:   cookie.Invite{},
-			errDecodeInvite: nil,
-			pwdHash:         nil,
-			errHash:         nil,
-			errInsertUser:   nil,
-			authToken:       http.Cookie{},
-			errEncodeAuth:   nil,
-			wantStatus:      http.StatusBadRequest,
+			tkInvite:         "",
+			inviteConsumed:   invitetbl.Invite{},
+			errConsumeInvite: nil,
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    nil,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusBadRequest,
 			assertFunc: assertOnErrsValidate(
 				ValidationErrs{
 					Username: []string{idTooLong},
@@ -89,33 +124,39 @@ func TestHandler(t *testing.T) {
 			),
 		},
 		{
-			name:            "ErrDecodeInvite",
-			req:             "{}",
-			errValidate:     ValidationErrs{},
-			tkInvite:        "someinvitetoken",
-			inviteDecoded:   cookie.Invite{},
-			errDecodeInvite: errors.New("an error"),
-			pwdHash:         nil,
-			errHash:         nil,
-			errInsertUser:   nil,
-			authToken:       http.Cookie{},
-			errEncodeAuth:   nil,
-			wantStatus:      http.StatusBadRequest,
-			assertFunc:      assert.OnRespErr("Invalid invite token."),
-		},
-		{
-			name:            "ErrUsnTaken",
-			req:             "{}",
-			errValidate:     ValidationErrs{},
-			tkInvite:        "",
-			inviteDecoded:   cookie.Invite{},
-			errDecodeInvite: nil,
-			pwdHash:         nil,
-			errHash:         nil,
-			errInsertUser:   db.ErrDupKey,
-			authToken:       http.Cookie{},
-			errEncodeAuth:   nil,
-			wantStatus:      http.StatusBadRequest,
+			name:             "InvalidInvite",
+			req:              "{}",
+			errValidate:      ValidationErrs{},
+			tkInvite:         "someinvitetoken",
+			inviteConsumed:   invitetbl.Invite{},
+			errConsumeInvite: db.ErrNoItem,
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    nil,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Invalid invite token."),
+		},
+		{
+			name:             "ErrUsnTaken",
+			req:              "{}",
+			errValidate:      ValidationErrs{},
+			tkInvite:         "",
+			inviteConsumed:   invitetbl.Invite{},
+			errConsumeInvite: nil,
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    db.ErrDupKey,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusBadRequest,
 			assertFunc: assertOnErrsValidate(
 				ValidationErrs{
 					Username: []string{"Username is already taken."},
@@ -123,63 +164,222 @@ func TestHandler(t *testing.T) {
 			),
 		},
 		{
-			name:          "ErrHash",
-			req:           validRBody,
-			errValidate:   ValidationErrs{},
-			tkInvite:      "{}",
-			inviteDecoded: cookie.Invite{},
-			pwdHash:       nil,
-			errHash:       errors.New("hasher error"),
-			errInsertUser: nil,
-			authToken:     http.Cookie{},
-			errEncodeAuth: nil,
-			wantStatus:    http.StatusInternalServerError,
-			assertFunc:    assert.OnLoggedErr("hasher error"),
-		},
-		{
-			name:          "ErrUsnTaken",
-			req:           "{}",
-			errValidate:   ValidationErrs{},
-			tkInvite:      "",
-			inviteDecoded: cookie.Invite{},
-			pwdHash:       nil,
-			errHash:       nil,
-			errInsertUser: db.ErrDupKey,
-			authToken:     http.Cookie{},
-			errEncodeAuth: nil,
-			wantStatus:    http.StatusBadRequest,
-			assertFunc: assertOnErrsValidate(
-				ValidationErrs{
-					Username: []string{"Username is already taken."},
-				},
+			name:             "ErrHash",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "{}",
+			inviteConsumed:   invitetbl.Invite{},
+			pwdHash:          nil,
+			errHash:          errors.New("hasher error"),
+			errInsertUser:    nil,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("hasher error"),
+		},
+		{
+			name:             "ErrConsumeInvite",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "someinvitetoken",
+			inviteConsumed:   invitetbl.Invite{},
+			errConsumeInvite: errors.New("failed to consume invite"),
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    nil,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("failed to consume invite"),
+		},
+		{
+			name:             "ErrRetrieveTeam",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "someinvitetoken",
+			inviteConsumed:   invitetbl.Invite{TeamID: "teamid"},
+			errConsumeInvite: nil,
+			errReleaseInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  errors.New("failed to retrieve team"),
+			members:          nil,
+			errRetrieveMembs: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("failed to retrieve team"),
+		},
+		{
+			name:             "ErrRetrieveMembers",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "someinvitetoken",
+			inviteConsumed:   invitetbl.Invite{TeamID: "teamid"},
+			errConsumeInvite: nil,
+			errReleaseInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  db.ErrNoItem,
+			members:          nil,
+			errRetrieveMembs: errors.New("failed to retrieve members"),
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("failed to retrieve members"),
+		},
+		{
+			name:             "MemberLimitReached",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "someinvitetoken",
+			inviteConsumed:   invitetbl.Invite{TeamID: "teamid"},
+			errConsumeInvite: nil,
+			errReleaseInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          make([]membershiptbl.Membership, 3),
+			errRetrieveMembs: nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"The team you were invited to has reached its limit of 3 " +
+					"members.",
+			),
+		},
+		{
+			name:             "MemberLimitOverridden",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "someinvitetoken",
+			inviteConsumed:   invitetbl.Invite{TeamID: "teamid"},
+			errConsumeInvite: nil,
+			errReleaseInvite: nil,
+			team: teamtbl.Team{
+				Quota: quota.Limits{Members: 1},
+			},
+			errRetrieveTeam:  nil,
+			members:          make([]membershiptbl.Membership, 1),
+			errRetrieveMembs: nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"The team you were invited to has reached its limit of 1 " +
+					"members.",
+			),
+		},
+		{
+			name:             "ErrUsnTakenReleaseInvite",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "someinvitetoken",
+			inviteConsumed:   invitetbl.Invite{TeamID: "teamid"},
+			errReleaseInvite: errors.New("failed to release invite"),
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    db.ErrDupKey,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnLoggedErr("failed to release invite"),
+		},
+		{
+			name:             "ErrPutUser",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "",
+			inviteConsumed:   invitetbl.Invite{},
+			errInsertUser:    errors.New("failed to put user"),
+			pwdHash:          nil,
+			errHash:          nil,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("failed to put user"),
+		},
+		{
+			name:             "ErrInsertMembership",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "",
+			inviteConsumed:   invitetbl.Invite{},
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    nil,
+			errInsertMember:  errors.New("failed to insert membership"),
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc: assert.OnRespErr(
+				"You have been registered successfully but something went " +
+					"wrong. Please log in using the credentials you " +
+					"registered with.",
+			),
+		},
+		{
+			name:             "ErrEncodeAuth",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "",
+			inviteConsumed:   invitetbl.Invite{},
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    nil,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    errors.New("error encoding auth token"),
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc: assert.OnRespErr(
+				"You have been registered successfully but something went " +
+					"wrong. Please log in using the credentials you " +
+					"registered with.",
+			),
+		},
+		{
+			name:             "ErrEncodeRefresh",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "",
+			inviteConsumed:   invitetbl.Invite{},
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    nil,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: errors.New("error encoding refresh token"),
+			errInsertSession: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc: assert.OnRespErr(
+				"You have been registered successfully but something went " +
+					"wrong. Please log in using the credentials you " +
+					"registered with.",
 			),
 		},
 		{
-			name:          "ErrPutUser",
-			req:           validRBody,
-			errValidate:   ValidationErrs{},
-			tkInvite:      "",
-			inviteDecoded: cookie.Invite{},
-			errInsertUser: errors.New("failed to put user"),
-			pwdHash:       nil,
-			errHash:       nil,
-			authToken:     http.Cookie{},
-			errEncodeAuth: nil,
-			wantStatus:    http.StatusInternalServerError,
-			assertFunc:    assert.OnLoggedErr("failed to put user"),
-		},
-		{
-			name:          "ErrEncodeAuth",
-			req:           validRBody,
-			errValidate:   ValidationErrs{},
-			tkInvite:      "",
-			inviteDecoded: cookie.Invite{},
-			pwdHash:       nil,
-			errHash:       nil,
-			errInsertUser: nil,
-			authToken:     http.Cookie{},
-			errEncodeAuth: errors.New("error encoding auth token"),
-			wantStatus:    http.StatusInternalServerError,
+			name:             "ErrInsertSession",
+			req:              validRBody,
+			errValidate:      ValidationErrs{},
+			tkInvite:         "",
+			inviteConsumed:   invitetbl.Invite{},
+			pwdHash:          nil,
+			errHash:          nil,
+			errInsertUser:    nil,
+			authToken:        http.Cookie{},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{},
+			errEncodeRefresh: nil,
+			errInsertSession: errors.New("error inserting session"),
+			wantStatus:       http.StatusInternalServerError,
 			assertFunc: assert.OnRespErr(
 				"You have been registered successfully but something went " +
 					"wrong. Please log in using the credentials you " +
@@ -187,33 +387,49 @@ func TestHandler(t *testing.T) {
 			),
 		},
 		{
-			name: "Success",
-			req:  validRBody,
-			tkInvite: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ0ZWFtSUQiOi" +
-				"J0ZWFtaWQifQ.1h_fmLJ1ip-Z6kJq9JXYDgGuWDPOcOf8abwCgKtHHcY",
-			errValidate:   ValidationErrs{},
-			errInsertUser: nil,
-			pwdHash:       nil,
-			errHash:       nil,
-			authToken:     http.Cookie{Name: "foo", Value: "bar"},
-			errEncodeAuth: nil,
-			wantStatus:    http.StatusOK,
+			name:             "Success",
+			req:              validRBody,
+			tkInvite:         "someinvitetoken",
+			inviteConsumed:   invitetbl.Invite{TeamID: "teamid"},
+			members:          make([]membershiptbl.Membership, 2),
+			errValidate:      ValidationErrs{},
+			errInsertUser:    nil,
+			pwdHash:          nil,
+			errHash:          nil,
+			authToken:        http.Cookie{Name: "foo", Value: "bar"},
+			errEncodeAuth:    nil,
+			refreshToken:     http.Cookie{Name: "baz", Value: "qux"},
+			errEncodeRefresh: nil,
+			errInsertSession: nil,
+			wantStatus:       http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
-				ck := resp.Cookies()[0]
-				assert.Equal(t.Error, ck.Name, "foo")
-				assert.Equal(t.Error, ck.Value, "bar")
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[0].Name, "foo")
+				assert.Equal(t.Error, cks[0].Value, "bar")
+				assert.Equal(t.Error, cks[1].Name, "baz")
+				assert.Equal(t.Error, cks[1].Value, "qux")
 			},
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			userValidator.validationErrs = c.errValidate
-			inviteDecoder.Res = c.inviteDecoded
-			inviteDecoder.Err = c.errDecodeInvite
+			inviteConsumer.Res = c.inviteConsumed
+			inviteConsumer.Err = c.errConsumeInvite
+			inviteReleaser.Err = c.errReleaseInvite
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			memberRetriever.Res = c.members
+			memberRetriever.Err = c.errRetrieveMembs
 			hasher.hash = c.pwdHash
 			hasher.err = c.errHash
 			userInserter.Err = c.errInsertUser
+			memberInserter.Err = c.errInsertMember
 			authEncoder.Res = c.authToken
 			authEncoder.Err = c.errEncodeAuth
+			refreshEncoder.Res = c.refreshToken
+			refreshEncoder.Err = c.errEncodeRefresh
+			sessionInserter.Err = c.errInsertSession
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(
 				http.MethodPost,
@@ -1,7 +1,9 @@
 package registerapi
 
 import (
+	"net/mail"
 	"regexp"
+	"strconv"
 )
 
 // ReqValidator describes a type that validates a request body and returns
@@ -11,26 +13,29 @@ type ReqValidator interface{ Validate(PostReq) ValidationErrs }
 // UserValidator is the ReqValidator for the register route.
 type UserValidator struct {
 	UsernameValidator StrValidator
+	EmailValidator    StrValidator
 	PasswordValidator StrValidator
 }
 
 // NewUserValidator creates and returns a new UserValidator.
 func NewUserValidator(
-	usernameValidator, passwordValidator StrValidator,
+	usernameValidator, emailValidator, passwordValidator StrValidator,
 ) UserValidator {
 	return UserValidator{
 		UsernameValidator: usernameValidator,
+		EmailValidator:    emailValidator,
 		PasswordValidator: passwordValidator,
 	}
 }
 
-// Validate uses UsernameValidator and PasswordValidator to validate requests
-// sent the register route. It returns an errors object if any of the individual
-// validations fail. It implements the UserValidator interface on the
-// ReqValidator struct.
+// Validate uses UsernameValidator, EmailValidator, and PasswordValidator to
+// validate requests sent the register route. It returns an errors object if any
+// of the individual validations fail. It implements the UserValidator interface
+// on the ReqValidator struct.
 func (v UserValidator) Validate(req PostReq) ValidationErrs {
 	errs := ValidationErrs{
 		Username: v.UsernameValidator.Validate(req.Username),
+		Email:    v.EmailValidator.Validate(req.Email),
 		Password: v.PasswordValidator.Validate(req.Password),
 	}
 	return errs
@@ -72,11 +77,77 @@ func (v IDValidator) Validate(id string) (errs []string) {
 	return
 }
 
+// EmailValidator is the email field validator for the register route. Email is
+// optional, so an empty email is valid.
+type EmailValidator struct{}
+
+// NewEmailValidator creates and returns a new EmailValidator.
+func NewEmailValidator() EmailValidator { return EmailValidator{} }
+
+// Validate applies email validation rules to the email string and returns the
+// error message if any fails.
+func (v EmailValidator) Validate(email string) (errs []string) {
+	if email == "" {
+		return
+	}
+	if len(email) > 254 {
+		errs = append(errs, "Email cannot be longer than 254 characters.")
+		return
+	}
+	// reject display names etc. - only a bare address is accepted
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		errs = append(errs, "Email is not a valid email address.")
+	}
+	return
+}
+
+// PwdPolicy defines the rules that passwords must follow.
+type PwdPolicy struct {
+	MinLength      int
+	MaxLength      int
+	RequireLower   bool
+	RequireUpper   bool
+	RequireDigit   bool
+	RequireSpecial bool
+	NoSpaces       bool
+	ASCIIOnly      bool
+}
+
+// StrictPwdPolicy requires passwords to be 8 to 64 ASCII characters with no
+// spaces, made up of at least one lowercase letter, uppercase letter, digit,
+// and special character.
+var StrictPwdPolicy = PwdPolicy{
+	MinLength:      8,
+	MaxLength:      64,
+	RequireLower:   true,
+	RequireUpper:   true,
+	RequireDigit:   true,
+	RequireSpecial: true,
+	NoSpaces:       true,
+	ASCIIOnly:      true,
+}
+
+// NISTPwdPolicy follows NIST SP 800-63B by only limiting the length of
+// passwords and allowing spaces and all Unicode characters so that passphrases
+// can be used. It should be paired with a Blocklist.
+var NISTPwdPolicy = PwdPolicy{MinLength: 8, MaxLength: 64}
+
+// Blocklist describes a type that can be used to check whether a password is
+// known to be unsafe.
+type Blocklist interface{ Contains(string) bool }
+
 // PwdValidator is the password field validator for the register route.
-type PwdValidator struct{}
+type PwdValidator struct {
+	policy    PwdPolicy
+	blocklist Blocklist
+}
 
-// NewPasswordValidator creates and returns a new PasswordValidator.
-func NewPasswordValidator() PwdValidator { return PwdValidator{} }
+// NewPasswordValidator creates and returns a new PasswordValidator. blocklist
+// can be nil, in which case passwords are only checked against the policy.
+func NewPasswordValidator(policy PwdPolicy, blocklist Blocklist) PwdValidator {
+	return PwdValidator{policy: policy, blocklist: blocklist}
+}
 
 // Validate applies password validation rules to the Password string and returns
 // the error message if any fails.
@@ -85,40 +156,67 @@ func (v PwdValidator) Validate(pwd string) (errs []string) {
 		errs = append(errs, "Password cannot be empty.")
 		// if password empty, further validation is pointless
 		return
-	} else if len([]rune(pwd)) < 8 {
-		errs = append(errs, "Password cannot be shorter than 8 characters.")
-	} else if len([]rune(pwd)) > 64 {
-		errs = append(errs, "Password cannot be longer than 64 characters.")
+	} else if len([]rune(pwd)) < v.policy.MinLength {
+		errs = append(errs, "Password cannot be shorter than "+
+			strconv.Itoa(v.policy.MinLength)+" characters.")
+	} else if v.policy.MaxLength > 0 &&
+		len([]rune(pwd)) > v.policy.MaxLength {
+		errs = append(errs, "Password cannot be longer than "+
+			strconv.Itoa(v.policy.MaxLength)+" characters.")
 	}
 
-	if match, _ := regexp.MatchString("[a-z]", pwd); !match {
-		errs = append(errs, "Password must contain a lowercase letter (a-z).")
+	if v.policy.RequireLower {
+		if match, _ := regexp.MatchString("[a-z]", pwd); !match {
+			errs = append(
+				errs, "Password must contain a lowercase letter (a-z).",
+			)
+		}
 	}
-	if match, _ := regexp.MatchString("[A-Z]", pwd); !match {
-		errs = append(errs, "Password must contain an uppercase letter (A-Z).")
+	if v.policy.RequireUpper {
+		if match, _ := regexp.MatchString("[A-Z]", pwd); !match {
+			errs = append(
+				errs, "Password must contain an uppercase letter (A-Z).",
+			)
+		}
 	}
-	if match, _ := regexp.MatchString("[0-9]", pwd); !match {
-		errs = append(errs, "Password must contain a digit (0-9).")
+	if v.policy.RequireDigit {
+		if match, _ := regexp.MatchString("[0-9]", pwd); !match {
+			errs = append(errs, "Password must contain a digit (0-9).")
+		}
 	}
-	if match, _ := regexp.MatchString(
-		"[!\"#$%&'()*+,-./:;<=>?[\\]^_`{|}~]", pwd,
-	); !match {
-		errs = append(
-			errs,
-			"Password must contain one of the following special characters: "+
-				"! \" # $ % & ' ( ) * + , - . / : ; < = > ? [ \\ ] ^ _ ` { | "+
-				"} ~.",
-		)
+	if v.policy.RequireSpecial {
+		if match, _ := regexp.MatchString(
+			"[!\"#$%&'()*+,-./:;<=>?[\\]^_`{|}~]", pwd,
+		); !match {
+			errs = append(
+				errs,
+				"Password must contain one of the following special "+
+					"characters: ! \" # $ % & ' ( ) * + , - . / : ; < = > ? "+
+					"[ \\ ] ^ _ ` { | } ~.",
+			)
+		}
+	}
+	if v.policy.NoSpaces {
+		if match, _ := regexp.MatchString("\\s", pwd); match {
+			errs = append(errs, "Password cannot contain spaces.")
+		}
 	}
-	if match, _ := regexp.MatchString("\\s", pwd); match {
-		errs = append(errs, "Password cannot contain spaces.")
+	if v.policy.ASCIIOnly {
+		if match, _ := regexp.MatchString("[^\\x00-\\x7F]", pwd); match {
+			errs = append(
+				errs,
+				"Password can contain only letters (a-z/A-Z), digits (0-9), "+
+					"and the following special characters: ! \" # $ % & ' ( "+
+					") * + , - . / : ; < = > ? [ \\ ] ^ _ ` { | } ~.",
+			)
+		}
 	}
-	if match, _ := regexp.MatchString("[^\\x00-\\x7F]", pwd); match {
+
+	if v.blocklist != nil && v.blocklist.Contains(pwd) {
 		errs = append(
 			errs,
-			"Password can contain only letters (a-z/A-Z), digits (0-9), and "+
-				"the following special characters: ! \" # $ % & ' ( ) * + , "+
-				"- . / : ; < = > ? [ \\ ] ^ _ ` { | } ~.",
+			"Password has appeared in a data breach. Please choose a "+
+				"different password.",
 		)
 	}
 
@@ -3,6 +3,7 @@
 package registerapi
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/kxplxn/goteam/pkg/assert"
@@ -18,6 +19,9 @@ const (
 	usnInvalidChar = "Username can contain only letters (a-z/A-Z) and digits (0-9)."
 	usnDigitStart  = "Username can start only with a letter (a-z/A-Z)."
 
+	emailTooLong = "Email cannot be longer than 254 characters."
+	emailInvalid = "Email is not a valid email address."
+
 	pwdEmpty     = "Password cannot be empty."
 	pwdTooShort  = "Password cannot be shorter than 8 characters."
 	pwdTooLong   = "Password cannot be longer than 64 characters."
@@ -30,48 +34,68 @@ const (
 	pwdNonASCII = "Password can contain only letters (a-z/A-Z), digits (0-9), " +
 		"and the following special characters: " +
 		"! \" # $ % & ' ( ) * + , - . / : ; < = > ? [ \\ ] ^ _ ` { | } ~."
+	pwdBreached = "Password has appeared in a data breach. Please choose a " +
+		"different password."
 )
 
 // TestUserValidator tests the UserValidator's Validate method to ensure that it returns
 // whatever error is returned to it by UsernameValidator and PasswordValidator.
 func TestUserValidator(t *testing.T) {
 	fakeIDValidator := &fakeStringValidator{}
+	fakeEmailValidator := &fakeStringValidator{}
 	fakePasswordValidator := &fakeStringValidator{}
 
-	sut := NewUserValidator(fakeIDValidator, fakePasswordValidator)
+	sut := NewUserValidator(
+		fakeIDValidator, fakeEmailValidator, fakePasswordValidator,
+	)
 
 	for _, c := range []struct {
 		name         string
 		reqBody      PostReq
 		usernameErrs []string
+		emailErrs    []string
 		passwordErrs []string
 	}{
 		{
 			name:         "UsnEmpty,PwdEmpty",
 			reqBody:      PostReq{Username: "", Password: ""},
 			usernameErrs: []string{idEmpty},
+			emailErrs:    nil,
 			passwordErrs: []string{pwdEmpty},
 		},
 		{
 			name:         "UsnTooShort,UsnInvalidChar,PwdEmpty",
 			reqBody:      PostReq{Username: "bob!", Password: "myNØNÅSCÎÎp4ssword!"},
 			usernameErrs: []string{idTooShort, usnInvalidChar},
+			emailErrs:    nil,
 			passwordErrs: []string{pwdNonASCII},
 		},
 		{
 			name:         "UsnDigitStart,PwdTooLong,PwdNoDigit",
 			reqBody:      PostReq{Username: "1bobob", Password: "MyPass!"},
 			usernameErrs: []string{usnDigitStart},
+			emailErrs:    nil,
 			passwordErrs: []string{pwdTooShort, pwdNoDigit},
 		},
+		{
+			name: "EmailInvalid",
+			reqBody: PostReq{
+				Username: "bob123", Email: "bob", Password: "Myp4ssword!",
+			},
+			usernameErrs: nil,
+			emailErrs:    []string{emailInvalid},
+			passwordErrs: nil,
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			fakeIDValidator.errs = c.usernameErrs
+			fakeEmailValidator.errs = c.emailErrs
 			fakePasswordValidator.errs = c.passwordErrs
 
 			errs := sut.Validate(c.reqBody)
 
 			assert.AllEqual(t.Error, errs.Username, c.usernameErrs)
+			assert.AllEqual(t.Error, errs.Email, c.emailErrs)
 			assert.AllEqual(t.Error, errs.Password, c.passwordErrs)
 		})
 	}
@@ -152,10 +176,41 @@ func TestUsernameValidator(t *testing.T) {
 	}
 }
 
+// TestEmailValidator tests the EmailValidator to assert that it returns the
+// correct error strings based on the email passed to it.
+func TestEmailValidator(t *testing.T) {
+	sut := NewEmailValidator()
+
+	for _, c := range []struct {
+		name     string
+		email    string
+		wantErrs []string
+	}{
+		{name: "Empty", email: "", wantErrs: nil},
+		{
+			name:     "TooLong",
+			email:    strings.Repeat("b", 250) + "@x.io",
+			wantErrs: []string{emailTooLong},
+		},
+		{name: "NoAt", email: "bob.goteam.io", wantErrs: []string{emailInvalid}},
+		{
+			name:     "WithName",
+			email:    "Bob <bob@goteam.io>",
+			wantErrs: []string{emailInvalid},
+		},
+		{name: "OK", email: "bob@goteam.io", wantErrs: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			errs := sut.Validate(c.email)
+			assert.AllEqual(t.Error, errs, c.wantErrs)
+		})
+	}
+}
+
 // TestPasswordValidator tests the PasswordValidator to assert that it returns
 // the correct error strings based on the password passed to it.
 func TestValidatorPassword(t *testing.T) {
-	sut := NewPasswordValidator()
+	sut := NewPasswordValidator(StrictPwdPolicy, nil)
 
 	for _, c := range []struct {
 		name     string
@@ -1357,3 +1412,107 @@ func TestValidatorPassword(t *testing.T) {
 		})
 	}
 }
+
+// TestValidatorPasswordPolicy tests that the PasswordValidator only applies the
+// rules enabled in its policy and checks passwords against its blocklist.
+func TestValidatorPasswordPolicy(t *testing.T) {
+	blocklist := &fakeBlocklist{pwds: []string{"password123"}}
+
+	for _, c := range []struct {
+		name      string
+		policy    PwdPolicy
+		blocklist Blocklist
+		password  string
+		wantErrs  []string
+	}{
+		{
+			name:      "NISTPassphrase",
+			policy:    NISTPwdPolicy,
+			blocklist: blocklist,
+			password:  "correct horse battery staple",
+			wantErrs:  nil,
+		},
+		{
+			name:      "NISTNonASCII",
+			policy:    NISTPwdPolicy,
+			blocklist: blocklist,
+			password:  "çok güzel bir şifre",
+			wantErrs:  nil,
+		},
+		{
+			name:      "NISTEmpty",
+			policy:    NISTPwdPolicy,
+			blocklist: blocklist,
+			password:  "",
+			wantErrs:  []string{pwdEmpty},
+		},
+		{
+			name:      "NISTTooShort",
+			policy:    NISTPwdPolicy,
+			blocklist: blocklist,
+			password:  "short",
+			wantErrs:  []string{pwdTooShort},
+		},
+		{
+			name:      "NISTBreached",
+			policy:    NISTPwdPolicy,
+			blocklist: blocklist,
+			password:  "password123",
+			wantErrs:  []string{pwdBreached},
+		},
+		{
+			name:      "NoBlocklist",
+			policy:    NISTPwdPolicy,
+			blocklist: nil,
+			password:  "password123",
+			wantErrs:  nil,
+		},
+		{
+			name:      "StrictBreached",
+			policy:    StrictPwdPolicy,
+			blocklist: blocklist,
+			password:  "password123",
+			wantErrs:  []string{pwdNoUpper, pwdNoSpecial, pwdBreached},
+		},
+		{
+			name:      "CustomTooShort",
+			policy:    PwdPolicy{MinLength: 12, MaxLength: 128},
+			blocklist: nil,
+			password:  "elevenchars",
+			wantErrs: []string{
+				"Password cannot be shorter than 12 characters.",
+			},
+		},
+		{
+			name:      "CustomTooLong",
+			policy:    PwdPolicy{MinLength: 4, MaxLength: 10},
+			blocklist: nil,
+			password:  "elevenchars",
+			wantErrs: []string{
+				"Password cannot be longer than 10 characters.",
+			},
+		},
+		{
+			name:      "CustomNoMaxLength",
+			policy:    PwdPolicy{MinLength: 4},
+			blocklist: nil,
+			password:  strings.Repeat("a", 1000),
+			wantErrs:  nil,
+		},
+		{
+			name:      "CustomDigitOnly",
+			policy:    PwdPolicy{MinLength: 4, RequireDigit: true},
+			blocklist: nil,
+			password:  "no digits here",
+			wantErrs:  []string{pwdNoDigit},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sut := NewPasswordValidator(c.policy, c.blocklist)
+
+			gotErrs := sut.Validate(c.password)
+
+			assert.AllEqual(t.Error, c.wantErrs, gotErrs)
+		})
+	}
+}
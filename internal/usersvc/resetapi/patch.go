@@ -0,0 +1,177 @@
+package resetapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/resettbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// PatchReq defines the body of PATCH reset requests.
+type PatchReq struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+// PatchResp defines the body of PATCH reset responses.
+type PatchResp struct {
+	Error          string   `json:"error,omitempty"`
+	ValidationErrs []string `json:"validationErrors,omitempty"`
+}
+
+// PatchHandler is an api.MethodHandler that can be used to handle PATCH reset
+// requests.
+type PatchHandler struct {
+	pwdValidator       registerapi.StrValidator
+	resetConsumer      db.Consumer[resettbl.Reset]
+	userRetriever      db.Retriever[usertbl.User]
+	hasher             registerapi.Hasher
+	userUpdater        db.Updater[usertbl.User]
+	revocationInserter db.Inserter[revocationtbl.Revocation]
+	sessionsDeleter    db.Deleter
+	log                log.Errorer
+}
+
+// NewPatchHandler creates and returns a new PatchHandler.
+func NewPatchHandler(
+	pwdValidator registerapi.StrValidator,
+	resetConsumer db.Consumer[resettbl.Reset],
+	userRetriever db.Retriever[usertbl.User],
+	hasher registerapi.Hasher,
+	userUpdater db.Updater[usertbl.User],
+	revocationInserter db.Inserter[revocationtbl.Revocation],
+	sessionsDeleter db.Deleter,
+	log log.Errorer,
+) PatchHandler {
+	return PatchHandler{
+		pwdValidator:       pwdValidator,
+		resetConsumer:      resetConsumer,
+		userRetriever:      userRetriever,
+		hasher:             hasher,
+		userUpdater:        userUpdater,
+		revocationInserter: revocationInserter,
+		sessionsDeleter:    sessionsDeleter,
+		log:                log,
+	}
+}
+
+// Handle handles the PATCH requests sent to the reset route.
+func (h PatchHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// decode request
+	var req PatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate request before consuming the token so that the user can retry
+	// with a valid password using the same token
+	if req.Token == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Reset token cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if errs := h.pwdValidator.Validate(req.NewPassword); len(errs) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			ValidationErrs: errs,
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// consume reset token - expired resets may not have been deleted by TTL
+	// yet so expiry is checked here as well
+	reset, err := h.resetConsumer.Consume(
+		r.Context(), resettbl.HashToken(req.Token),
+	)
+	if errors.Is(err, db.ErrNoItem) ||
+		(err == nil && reset.ExpiresAt < time.Now().Unix()) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "Invalid or expired reset token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// retrieve user
+	user, err := h.userRetriever.Retrieve(r.Context(), reset.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// hash new password and update user
+	if user.Password, err = h.hasher.Hash(req.NewPassword); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.userUpdater.Update(
+		r.Context(), user,
+	); errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusNotFound)
+		if err := json.NewEncoder(w).Encode(PatchResp{
+			Error: "User not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// invalidate all existing auth tokens and sessions of the user
+	if err = h.revocationInserter.Insert(
+		r.Context(),
+		revocationtbl.NewAllRevocation(user.Username, time.Now().UnixMilli()),
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if err = h.sessionsDeleter.Delete(r.Context(), user.Username); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,274 @@
+//go:build utest
+
+package resetapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/resettbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestPatchHandler tests the Handle method of PatchHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPatchHandler(t *testing.T) {
+	pwdValidator := &fakeStrValidator{}
+	resetConsumer := &db.FakeConsumer[resettbl.Reset]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	hasher := &fakeHasher{}
+	userUpdater := &db.FakeUpdater[usertbl.User]{}
+	revocationInserter := &db.FakeInserter[revocationtbl.Revocation]{}
+	sessionsDeleter := &db.FakeDeleter{}
+	log := &log.FakeErrorer{}
+	sut := NewPatchHandler(
+		pwdValidator,
+		resetConsumer,
+		userRetriever,
+		hasher,
+		userUpdater,
+		revocationInserter,
+		sessionsDeleter,
+		log,
+	)
+
+	validReq := `{"token": "sometoken", "newPassword": "N3wP4ss!"}`
+	validReset := resettbl.NewReset(
+		"hash", "bob123", time.Now().Add(time.Hour).Unix(),
+	)
+
+	for _, c := range []struct {
+		name        string
+		reqBody     string
+		pwdErrs     []string
+		reset       resettbl.Reset
+		errConsume  error
+		errRetrieve error
+		errHash     error
+		errUpdate   error
+		errRevoke   error
+		errDelete   error
+		wantStatus  int
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "TokenEmpty",
+			reqBody:     `{"token": "", "newPassword": "N3wP4ss!"}`,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Reset token cannot be empty."),
+		},
+		{
+			name:        "NewPasswordInvalid",
+			reqBody:     validReq,
+			pwdErrs:     []string{"Password cannot be empty."},
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var body PatchResp
+				err := json.NewDecoder(resp.Body).Decode(&body)
+				assert.Nil(t.Fatal, err)
+				assert.AllEqual(t.Error,
+					body.ValidationErrs, []string{"Password cannot be empty."},
+				)
+			},
+		},
+		{
+			name:        "ResetNotFound",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  db.ErrNoItem,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Invalid or expired reset token."),
+		},
+		{
+			name:        "ResetExpired",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       resettbl.NewReset("hash", "bob123", 1),
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusBadRequest,
+			assertFunc:  assert.OnRespErr("Invalid or expired reset token."),
+		},
+		{
+			name:        "ErrConsume",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  errors.New("consume reset failed"),
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("consume reset failed"),
+		},
+		{
+			name:        "UserNotFound",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: db.ErrNoItem,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrRetrieve",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: errors.New("retrieve user failed"),
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:        "ErrHash",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     errors.New("hash failed"),
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("hash failed"),
+		},
+		{
+			name:        "UserNotFoundOnUpdate",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   db.ErrNoItem,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusNotFound,
+			assertFunc:  assert.OnRespErr("User not found."),
+		},
+		{
+			name:        "ErrUpdate",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   errors.New("update user failed"),
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("update user failed"),
+		},
+		{
+			name:        "ErrRevoke",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   errors.New("insert revocation failed"),
+			errDelete:   nil,
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("insert revocation failed"),
+		},
+		{
+			name:        "ErrDeleteSessions",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   errors.New("delete sessions failed"),
+			wantStatus:  http.StatusInternalServerError,
+			assertFunc:  assert.OnLoggedErr("delete sessions failed"),
+		},
+		{
+			name:        "OK",
+			reqBody:     validReq,
+			pwdErrs:     nil,
+			reset:       validReset,
+			errConsume:  nil,
+			errRetrieve: nil,
+			errHash:     nil,
+			errUpdate:   nil,
+			errRevoke:   nil,
+			errDelete:   nil,
+			wantStatus:  http.StatusOK,
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			pwdValidator.errs = c.pwdErrs
+			resetConsumer.Res = c.reset
+			resetConsumer.Err = c.errConsume
+			userRetriever.Err = c.errRetrieve
+			hasher.err = c.errHash
+			userUpdater.Err = c.errUpdate
+			revocationInserter.Err = c.errRevoke
+			sessionsDeleter.Err = c.errDelete
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch, "/", strings.NewReader(c.reqBody),
+			)
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package resetapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/resettbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mail"
+)
+
+// resetTTL is how long a password reset token can be used for after it is
+// issued.
+const resetTTL = time.Hour
+
+// PostReq defines the body of POST reset requests.
+type PostReq struct {
+	Username string `json:"username"`
+}
+
+// PostResp defines the body of POST reset responses.
+type PostResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can be used to handle POST reset
+// requests.
+type PostHandler struct {
+	userRetriever db.Retriever[usertbl.User]
+	resetInserter db.Inserter[resettbl.Reset]
+	mailer        mail.Mailer
+	resetURL      string
+	log           log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler. resetURL is the URL of
+// the client page that the token is sent to in the password reset email.
+func NewPostHandler(
+	userRetriever db.Retriever[usertbl.User],
+	resetInserter db.Inserter[resettbl.Reset],
+	mailer mail.Mailer,
+	resetURL string,
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		userRetriever: userRetriever,
+		resetInserter: resetInserter,
+		mailer:        mailer,
+		resetURL:      resetURL,
+		log:           log,
+	}
+}
+
+// Handle handles the POST requests sent to the reset route.
+func (h PostHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// decode request
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate request
+	if req.Username == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Username cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve user - respond the same way as on success if the user doesn't
+	// exist or has no email so that the route can't be used to find out which
+	// usernames are taken
+	user, err := h.userRetriever.Retrieve(r.Context(), req.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	if user.Email == "" {
+		return
+	}
+
+	// generate a reset token and store its hash
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	if err = h.resetInserter.Insert(r.Context(), resettbl.NewReset(
+		resettbl.HashToken(token),
+		user.Username,
+		time.Now().Add(resetTTL).Unix(),
+	)); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// mail the reset token to the user
+	if err = h.mailer.Send(
+		user.Email,
+		"Reset your Go Team! password",
+		"Hi "+user.Username+",\n\n"+
+			"Someone requested a password reset for your Go Team! account. "+
+			"If it was you, use the link below within an hour to set a new "+
+			"password:\n\n"+
+			h.resetURL+"?token="+token+"\n\n"+
+			"If it wasn't you, you can ignore this email.",
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,153 @@
+//go:build utest
+
+package resetapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/resettbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mail"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	resetInserter := &db.FakeInserter[resettbl.Reset]{}
+	mailer := &mail.FakeMailer{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		userRetriever,
+		resetInserter,
+		mailer,
+		"http://localhost:3000/reset",
+		log,
+	)
+
+	for _, c := range []struct {
+		name        string
+		reqBody     string
+		user        usertbl.User
+		errRetrieve error
+		errInsert   error
+		errSend     error
+		wantStatus  int
+		wantMailTo  string
+		assertFunc  func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:        "UsernameEmpty",
+			reqBody:     `{"username": ""}`,
+			user:        usertbl.User{},
+			errRetrieve: nil,
+			errInsert:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusBadRequest,
+			wantMailTo:  "",
+			assertFunc:  assert.OnRespErr("Username cannot be empty."),
+		},
+		{
+			name:        "UserNotFound",
+			reqBody:     `{"username": "bob123"}`,
+			user:        usertbl.User{},
+			errRetrieve: db.ErrNoItem,
+			errInsert:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusOK,
+			wantMailTo:  "",
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:        "ErrRetrieve",
+			reqBody:     `{"username": "bob123"}`,
+			user:        usertbl.User{},
+			errRetrieve: errors.New("retrieve user failed"),
+			errInsert:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusInternalServerError,
+			wantMailTo:  "",
+			assertFunc:  assert.OnLoggedErr("retrieve user failed"),
+		},
+		{
+			name:        "NoEmail",
+			reqBody:     `{"username": "bob123"}`,
+			user:        usertbl.User{Username: "bob123"},
+			errRetrieve: nil,
+			errInsert:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusOK,
+			wantMailTo:  "",
+			assertFunc:  func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:    "ErrInsert",
+			reqBody: `{"username": "bob123"}`,
+			user: usertbl.User{
+				Username: "bob123", Email: "bob@goteam.io",
+			},
+			errRetrieve: nil,
+			errInsert:   errors.New("insert reset failed"),
+			errSend:     nil,
+			wantStatus:  http.StatusInternalServerError,
+			wantMailTo:  "",
+			assertFunc:  assert.OnLoggedErr("insert reset failed"),
+		},
+		{
+			name:    "ErrSend",
+			reqBody: `{"username": "bob123"}`,
+			user: usertbl.User{
+				Username: "bob123", Email: "bob@goteam.io",
+			},
+			errRetrieve: nil,
+			errInsert:   nil,
+			errSend:     errors.New("send mail failed"),
+			wantStatus:  http.StatusInternalServerError,
+			wantMailTo:  "bob@goteam.io",
+			assertFunc:  assert.OnLoggedErr("send mail failed"),
+		},
+		{
+			name:    "OK",
+			reqBody: `{"username": "bob123"}`,
+			user: usertbl.User{
+				Username: "bob123", Email: "bob@goteam.io",
+			},
+			errRetrieve: nil,
+			errInsert:   nil,
+			errSend:     nil,
+			wantStatus:  http.StatusOK,
+			wantMailTo:  "bob@goteam.io",
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				assert.True(t.Error, strings.Contains(
+					mailer.Body, "http://localhost:3000/reset?token=",
+				))
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			userRetriever.Res = c.user
+			userRetriever.Err = c.errRetrieve
+			resetInserter.Err = c.errInsert
+			mailer.To, mailer.Subject, mailer.Body = "", "", ""
+			mailer.Err = c.errSend
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(c.reqBody),
+			)
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			assert.Equal(t.Error, mailer.To, c.wantMailTo)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package resetapi contains code for responding to HTTP requests made to the
+// reset API route, which is used by users for resetting a forgotten password.
+package resetapi
@@ -0,0 +1,100 @@
+package sessionsapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// DeleteResp defines the body of DELETE sessions responses.
+type DeleteResp struct {
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteHandler is an api.MethodHandler that can be used to handle DELETE
+// sessions requests.
+type DeleteHandler struct {
+	authDecoder        cookie.Decoder[cookie.Auth]
+	revocationInserter db.Inserter[revocationtbl.Revocation]
+	sessionsDeleter    db.Deleter
+	log                log.Errorer
+}
+
+// NewDeleteHandler creates and returns a new DeleteHandler.
+func NewDeleteHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	revocationInserter db.Inserter[revocationtbl.Revocation],
+	sessionsDeleter db.Deleter,
+	log log.Errorer,
+) DeleteHandler {
+	return DeleteHandler{
+		authDecoder:        authDecoder,
+		revocationInserter: revocationInserter,
+		sessionsDeleter:    sessionsDeleter,
+		log:                log,
+	}
+}
+
+// Handle handles the DELETE requests sent to the sessions route.
+func (h DeleteHandler) Handle(
+	w http.ResponseWriter, r *http.Request, _ string,
+) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(DeleteResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// revoke all auth tokens issued to the user so far
+	if err = h.revocationInserter.Insert(
+		r.Context(),
+		revocationtbl.NewAllRevocation(auth.Username, time.Now().UnixMilli()),
+	); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// end all sessions of the user so that their refresh tokens can't be used
+	if err = h.sessionsDeleter.Delete(r.Context(), auth.Username); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// delete auth and refresh cookies on the client
+	ckAuthDel := cookie.NewExpired(cookie.AuthName)
+	ckRefreshDel := cookie.NewExpired(cookie.RefreshName)
+	http.SetCookie(w, &ckAuthDel)
+	http.SetCookie(w, &ckRefreshDel)
+}
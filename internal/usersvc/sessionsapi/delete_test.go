@@ -0,0 +1,111 @@
+//go:build utest
+
+package sessionsapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// TestDeleteHandler tests the Handle method of DeleteHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestDeleteHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	revocationInserter := &db.FakeInserter[revocationtbl.Revocation]{}
+	sessionsDeleter := &db.FakeDeleter{}
+	log := &log.FakeErrorer{}
+	sut := NewDeleteHandler(
+		authDecoder, revocationInserter, sessionsDeleter, log,
+	)
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		errInsert     error
+		errDelete     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			errInsert:     nil,
+			errDelete:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			errInsert:     nil,
+			errDelete:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "ErrInsert",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errInsert:     errors.New("insert revocation failed"),
+			errDelete:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("insert revocation failed"),
+		},
+		{
+			name:          "ErrDelete",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errInsert:     nil,
+			errDelete:     errors.New("delete sessions failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("delete sessions failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errInsert:     nil,
+			errDelete:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[0].Name, cookie.AuthName)
+				assert.True(t.Error, cks[0].MaxAge < 0)
+				assert.Equal(t.Error, cks[1].Name, cookie.RefreshName)
+				assert.True(t.Error, cks[1].MaxAge < 0)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = cookie.Auth{Username: "bob123"}
+			authDecoder.Err = c.errDecodeAuth
+			revocationInserter.Err = c.errInsert
+			sessionsDeleter.Err = c.errDelete
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package sessionsapi contains code for responding to HTTP requests made to the
+// sessions API route, which is used for logging out a user on all devices.
+package sessionsapi
@@ -0,0 +1,121 @@
+package teamsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// GetResp defines the body of GET user teams responses.
+type GetResp struct {
+	Teams []Team `json:"teams"`
+	Error string `json:"error,omitempty"`
+}
+
+// Team defines a team that the user is a member of in GET user teams responses.
+type Team struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Role     role.Role `json:"role"`
+	IsActive bool      `json:"isActive"`
+}
+
+// GetHandler is an api.MethodHandler that can be used to handle GET user teams
+// requests.
+type GetHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	memberRetriever db.Retriever[[]membershiptbl.Membership]
+	teamRetriever   db.Retriever[teamtbl.Team]
+	log             log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	memberRetriever db.Retriever[[]membershiptbl.Membership],
+	teamRetriever db.Retriever[teamtbl.Team],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		authDecoder:     authDecoder,
+		memberRetriever: memberRetriever,
+		teamRetriever:   teamRetriever,
+		log:             log,
+	}
+}
+
+// Handle handles the GET requests sent to the user teams route.
+func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the user's memberships
+	memberships, err := h.memberRetriever.Retrieve(
+		r.Context(), auth.Username,
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// retrieve the name of each team - teams are only created once their
+	// owner first loads them, so a team that doesn't exist yet has no name
+	resp := GetResp{Teams: []Team{}}
+	for _, m := range memberships {
+		var name string
+		team, err := h.teamRetriever.Retrieve(r.Context(), m.TeamID)
+		if err == nil {
+			name = team.Name
+		} else if !errors.Is(err, db.ErrNoItem) {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+		resp.Teams = append(resp.Teams, Team{
+			ID:       m.TeamID,
+			Name:     name,
+			Role:     m.Role,
+			IsActive: m.TeamID == auth.TeamID,
+		})
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
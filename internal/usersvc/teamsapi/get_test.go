@@ -0,0 +1,158 @@
+//go:build utest
+
+package teamsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	memberRetriever := &db.FakeRetriever[[]membershiptbl.Membership]{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(authDecoder, memberRetriever, teamRetriever, log)
+
+	memberships := []membershiptbl.Membership{
+		membershiptbl.NewMembership("bob123", "team1", role.Owner),
+		membershiptbl.NewMembership("bob123", "team2", role.Viewer),
+	}
+
+	for _, c := range []struct {
+		name              string
+		authToken         string
+		errDecodeAuth     error
+		memberships       []membershiptbl.Membership
+		errRetrieveMember error
+		team              teamtbl.Team
+		errRetrieveTeam   error
+		wantStatus        int
+		assertFunc        func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:              "NoAuth",
+			authToken:         "",
+			errDecodeAuth:     nil,
+			memberships:       nil,
+			errRetrieveMember: nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:              "InvalidAuth",
+			authToken:         "nonempty",
+			errDecodeAuth:     cookie.ErrInvalid,
+			memberships:       nil,
+			errRetrieveMember: nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			wantStatus:        http.StatusUnauthorized,
+			assertFunc:        assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:              "ErrRetrieveMemberships",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			memberships:       nil,
+			errRetrieveMember: errors.New("retrieve memberships failed"),
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   nil,
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc: assert.OnLoggedErr(
+				"retrieve memberships failed",
+			),
+		},
+		{
+			name:              "ErrRetrieveTeam",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			memberships:       memberships,
+			errRetrieveMember: nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   errors.New("retrieve team failed"),
+			wantStatus:        http.StatusInternalServerError,
+			assertFunc:        assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:              "OKTeamNotCreated",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			memberships:       memberships[:1],
+			errRetrieveMember: nil,
+			team:              teamtbl.Team{},
+			errRetrieveTeam:   db.ErrNoItem,
+			wantStatus:        http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody GetResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Fatal, len(respBody.Teams), 1)
+				assert.Equal(t.Error, respBody.Teams[0].ID, "team1")
+				assert.Equal(t.Error, respBody.Teams[0].Name, "")
+				assert.Equal(t.Error, respBody.Teams[0].Role, role.Owner)
+				assert.True(t.Error, respBody.Teams[0].IsActive)
+			},
+		},
+		{
+			name:              "OK",
+			authToken:         "nonempty",
+			errDecodeAuth:     nil,
+			memberships:       memberships,
+			errRetrieveMember: nil,
+			team:              teamtbl.Team{Name: "My Team"},
+			errRetrieveTeam:   nil,
+			wantStatus:        http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody GetResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Fatal, len(respBody.Teams), 2)
+				for i, m := range memberships {
+					team := respBody.Teams[i]
+					assert.Equal(t.Error, team.ID, m.TeamID)
+					assert.Equal(t.Error, team.Name, "My Team")
+					assert.Equal(t.Error, team.Role, m.Role)
+					assert.Equal(t.Error, team.IsActive, i == 0)
+				}
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = cookie.Auth{Username: "bob123", TeamID: "team1"}
+			authDecoder.Err = c.errDecodeAuth
+			memberRetriever.Res = c.memberships
+			memberRetriever.Err = c.errRetrieveMember
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
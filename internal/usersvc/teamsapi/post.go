@@ -0,0 +1,200 @@
+package teamsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// PostResp defines the body of POST user teams responses.
+type PostResp struct {
+	Team  Team   `json:"team"`
+	Error string `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can be used to handle POST user
+// teams requests, which are used by existing users for joining a team with an
+// invite.
+type PostHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	inviteConsumer  db.ConsumerDualKey[invitetbl.Invite]
+	inviteReleaser  db.Releaser
+	teamRetriever   db.Retriever[teamtbl.Team]
+	memberRetriever db.Retriever[[]membershiptbl.Membership]
+	limits          quota.Limits
+	memberInserter  db.Inserter[membershiptbl.Membership]
+	log             log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	inviteConsumer db.ConsumerDualKey[invitetbl.Invite],
+	inviteReleaser db.Releaser,
+	teamRetriever db.Retriever[teamtbl.Team],
+	memberRetriever db.Retriever[[]membershiptbl.Membership],
+	limits quota.Limits,
+	memberInserter db.Inserter[membershiptbl.Membership],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		authDecoder:     authDecoder,
+		inviteConsumer:  inviteConsumer,
+		inviteReleaser:  inviteReleaser,
+		teamRetriever:   teamRetriever,
+		memberRetriever: memberRetriever,
+		limits:          limits,
+		memberInserter:  memberInserter,
+		log:             log,
+	}
+}
+
+// Handle handles the POST requests sent to the user teams route.
+func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// use up the invite - this is done before the membership is inserted so
+	// that it cannot be used more times than allowed by concurrent requests
+	invCode := r.URL.Query().Get("inviteToken")
+	if invCode == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Invite token cannot be empty.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	invite, err := h.inviteConsumer.Consume(
+		r.Context(), invCode, auth.Username,
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Invalid invite token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// invites created before roles were introduced don't have one
+	memberRole := invite.Role
+	if !memberRole.IsValid() {
+		memberRole = role.Member
+	}
+
+	// make sure the team has room for another member
+	team, err := h.teamRetriever.Retrieve(r.Context(), invite.TeamID)
+	if err != nil && !errors.Is(err, db.ErrNoItem) {
+		h.releaseInvite(r, invCode)
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	members, err := h.memberRetriever.Retrieve(r.Context(), invite.TeamID)
+	if err != nil {
+		h.releaseInvite(r, invCode)
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	limit := h.limits.Override(team.Quota).Members
+	if !quota.Allows(limit, len(members), 1) {
+		h.releaseInvite(r, invCode)
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "This team has reached its limit of " +
+				strconv.Itoa(limit) + " members.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// insert the user's membership of the team
+	err = h.memberInserter.Insert(r.Context(), membershiptbl.NewMembership(
+		auth.Username, invite.TeamID, memberRole,
+	))
+	if err != nil {
+		h.releaseInvite(r, invCode)
+	}
+	if errors.Is(err, db.ErrDupKey) {
+		w.WriteHeader(http.StatusConflict)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "You are already a member of this team.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(PostResp{Team: Team{
+		ID: invite.TeamID, Role: memberRole,
+	}}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
+
+// releaseInvite gives back the use of the invite with the given code since the
+// user couldn't join the team with it.
+func (h PostHandler) releaseInvite(r *http.Request, invCode string) {
+	if err := h.inviteReleaser.Release(
+		r.Context(), invCode,
+	); err != nil && !errors.Is(err, db.ErrNoItem) {
+		h.log.Error(err)
+	}
+}
@@ -0,0 +1,312 @@
+//go:build utest
+
+package teamsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	inviteConsumer := &db.FakeConsumerDualKey[invitetbl.Invite]{}
+	inviteReleaser := &db.FakeReleaser{}
+	teamRetriever := &db.FakeRetriever[teamtbl.Team]{}
+	memberRetriever := &db.FakeRetriever[[]membershiptbl.Membership]{}
+	memberInserter := &db.FakeInserter[membershiptbl.Membership]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		authDecoder,
+		inviteConsumer,
+		inviteReleaser,
+		teamRetriever,
+		memberRetriever,
+		quota.Limits{Members: 3},
+		memberInserter,
+		log,
+	)
+
+	for _, c := range []struct {
+		name             string
+		authToken        string
+		errDecodeAuth    error
+		tkInvite         string
+		invite           invitetbl.Invite
+		errConsumeInvite error
+		team             teamtbl.Team
+		errRetrieveTeam  error
+		members          []membershiptbl.Membership
+		errRetrieveMembs error
+		errInsert        error
+		errReleaseInvite error
+		wantStatus       int
+		assertFunc       func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:             "NoAuth",
+			authToken:        "",
+			errDecodeAuth:    nil,
+			tkInvite:         "",
+			invite:           invitetbl.Invite{},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusUnauthorized,
+			assertFunc:       assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:             "InvalidAuth",
+			authToken:        "nonempty",
+			errDecodeAuth:    cookie.ErrInvalid,
+			tkInvite:         "",
+			invite:           invitetbl.Invite{},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusUnauthorized,
+			assertFunc:       assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:             "NoInvite",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "",
+			invite:           invitetbl.Invite{},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Invite token cannot be empty.",
+			),
+		},
+		{
+			name:             "InvalidInvite",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{},
+			errConsumeInvite: db.ErrNoItem,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc:       assert.OnRespErr("Invalid invite token."),
+		},
+		{
+			name:             "ErrConsumeInvite",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{},
+			errConsumeInvite: errors.New("consume failed"),
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("consume failed"),
+		},
+		{
+			name:             "ErrRetrieveTeam",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{TeamID: "team2"},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  errors.New("retrieve team failed"),
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve team failed"),
+		},
+		{
+			name:             "ErrRetrieveMembers",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{TeamID: "team2"},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: errors.New("retrieve members failed"),
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("retrieve members failed"),
+		},
+		{
+			name:             "MemberLimitReached",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{TeamID: "team2"},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  db.ErrNoItem,
+			members:          make([]membershiptbl.Membership, 3),
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"This team has reached its limit of 3 members.",
+			),
+		},
+		{
+			name:             "MemberLimitOverridden",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{TeamID: "team2"},
+			errConsumeInvite: nil,
+			team: teamtbl.Team{
+				Quota: quota.Limits{Members: quota.Unlimited},
+			},
+			errRetrieveTeam:  nil,
+			members:          make([]membershiptbl.Membership, 3),
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusCreated,
+			assertFunc:       func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:             "AlreadyMember",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{TeamID: "team2"},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        db.ErrDupKey,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"You are already a member of this team.",
+			),
+		},
+		{
+			name:             "AlreadyMemberErrRelease",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{TeamID: "team2"},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        db.ErrDupKey,
+			errReleaseInvite: errors.New("release failed"),
+			wantStatus:       http.StatusConflict,
+			assertFunc:       assert.OnLoggedErr("release failed"),
+		},
+		{
+			name:             "ErrInsert",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{TeamID: "team2"},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        errors.New("insert failed"),
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusInternalServerError,
+			assertFunc:       assert.OnLoggedErr("insert failed"),
+		},
+		{
+			name:             "OK",
+			authToken:        "nonempty",
+			errDecodeAuth:    nil,
+			tkInvite:         "someinvitetoken",
+			invite:           invitetbl.Invite{TeamID: "team2"},
+			errConsumeInvite: nil,
+			team:             teamtbl.Team{},
+			errRetrieveTeam:  nil,
+			members:          nil,
+			errRetrieveMembs: nil,
+			errInsert:        nil,
+			errReleaseInvite: nil,
+			wantStatus:       http.StatusCreated,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody PostResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, respBody.Team.ID, "team2")
+				assert.Equal(t.Error, respBody.Team.Role, role.Member)
+				assert.True(t.Error, !respBody.Team.IsActive)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Res = cookie.Auth{Username: "bob123", TeamID: "team1"}
+			authDecoder.Err = c.errDecodeAuth
+			inviteConsumer.Res = c.invite
+			inviteConsumer.Err = c.errConsumeInvite
+			teamRetriever.Res = c.team
+			teamRetriever.Err = c.errRetrieveTeam
+			memberRetriever.Res = c.members
+			memberRetriever.Err = c.errRetrieveMembs
+			memberInserter.Err = c.errInsert
+			inviteReleaser.Err = c.errReleaseInvite
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/?inviteToken="+c.tkInvite, nil,
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package teamsapi contains code for responding to HTTP requests made to the
+// user teams API route, which is used by users for listing the teams that they
+// are a member of and for joining more teams with an invite.
+package teamsapi
@@ -0,0 +1,111 @@
+package tokenapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// GetResp defines the body of GET tokens responses.
+type GetResp struct {
+	Tokens []Token `json:"tokens"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Token defines a personal access token in GET tokens responses. The token
+// itself is not included since only its hash is stored.
+type Token struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	CreatedAt int64    `json:"createdAt"`
+	ExpiresAt int64    `json:"expiresAt"`
+}
+
+// GetHandler is an api.MethodHandler that can be used to handle GET tokens
+// requests, which are used for listing a user's personal access tokens.
+type GetHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	retrieverByUser db.Retriever[[]tokentbl.Token]
+	log             log.Errorer
+}
+
+// NewGetHandler creates and returns a new GetHandler.
+func NewGetHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	retrieverByUser db.Retriever[[]tokentbl.Token],
+	log log.Errorer,
+) GetHandler {
+	return GetHandler{
+		authDecoder:     authDecoder,
+		retrieverByUser: retrieverByUser,
+		log:             log,
+	}
+}
+
+// Handle handles the GET requests sent to the tokens route.
+func (h GetHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(GetResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// retrieve the user's tokens
+	tokens, err := h.retrieverByUser.Retrieve(r.Context(), auth.Username)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// respond with the tokens that haven't expired yet - expired tokens are
+	// filtered out since DynamoDB doesn't delete them as soon as their TTL
+	// passes
+	resp := GetResp{Tokens: []Token{}}
+	now := time.Now().Unix()
+	for _, t := range tokens {
+		if t.ExpiresAt <= now {
+			continue
+		}
+		resp.Tokens = append(resp.Tokens, Token{
+			Name:      t.Name,
+			Scopes:    t.Scopes,
+			CreatedAt: t.CreatedAt,
+			ExpiresAt: t.ExpiresAt,
+		})
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
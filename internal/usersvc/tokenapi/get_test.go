@@ -0,0 +1,124 @@
+//go:build utest
+
+package tokenapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pat"
+)
+
+// TestGetHandler tests the Handle method of GetHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestGetHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	retrieverByUser := &db.FakeRetriever[[]tokentbl.Token]{}
+	log := &log.FakeErrorer{}
+	sut := NewGetHandler(authDecoder, retrieverByUser, log)
+
+	authDecoder.Res = cookie.Auth{Username: "bob123"}
+	future := time.Now().Add(time.Hour).Unix()
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		tokens        []tokentbl.Token
+		errRetrieve   error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			tokens:        nil,
+			errRetrieve:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			tokens:        nil,
+			errRetrieve:   nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			tokens:        nil,
+			errRetrieve:   errors.New("retrieve failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			tokens: []tokentbl.Token{
+				{
+					ID:        "hash1",
+					Name:      "ci",
+					Scopes:    []string{pat.ScopeTasksRead},
+					CreatedAt: 1700000000,
+					ExpiresAt: future,
+				},
+				{
+					ID:        "hash2",
+					Name:      "expired",
+					Scopes:    []string{pat.ScopeTeamRead},
+					CreatedAt: 1700000000,
+					ExpiresAt: 1700000001,
+				},
+			},
+			errRetrieve: nil,
+			wantStatus:  http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody GetResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Fatal, len(respBody.Tokens), 1)
+				tk := respBody.Tokens[0]
+				assert.Equal(t.Error, tk.Name, "ci")
+				assert.AllEqual(t.Error,
+					tk.Scopes, []string{pat.ScopeTasksRead},
+				)
+				assert.Equal(t.Error, tk.CreatedAt, int64(1700000000))
+				assert.Equal(t.Error, tk.ExpiresAt, future)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			retrieverByUser.Res = c.tokens
+			retrieverByUser.Err = c.errRetrieve
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
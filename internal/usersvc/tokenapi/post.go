@@ -0,0 +1,191 @@
+package tokenapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pat"
+	"github.com/kxplxn/goteam/pkg/validator"
+)
+
+// PostReq defines the body of POST tokens requests.
+type PostReq struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays"`
+}
+
+// PostResp defines the body of POST tokens responses. Token is only ever sent
+// in this response since only its hash is stored.
+type PostResp struct {
+	Token     string   `json:"token,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+	ExpiresAt int64    `json:"expiresAt,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// PostHandler is an api.MethodHandler that can be used to handle POST tokens
+// requests, which are used for creating a personal access token.
+type PostHandler struct {
+	authDecoder     cookie.Decoder[cookie.Auth]
+	validateReq     validator.Func[PostReq]
+	retrieverByUser db.Retriever[[]tokentbl.Token]
+	tokenInserter   db.Inserter[tokentbl.Token]
+	log             log.Errorer
+}
+
+// NewPostHandler creates and returns a new PostHandler.
+func NewPostHandler(
+	authDecoder cookie.Decoder[cookie.Auth],
+	validateReq validator.Func[PostReq],
+	retrieverByUser db.Retriever[[]tokentbl.Token],
+	tokenInserter db.Inserter[tokentbl.Token],
+	log log.Errorer,
+) PostHandler {
+	return PostHandler{
+		authDecoder:     authDecoder,
+		validateReq:     validateReq,
+		retrieverByUser: retrieverByUser,
+		tokenInserter:   tokenInserter,
+		log:             log,
+	}
+}
+
+// Handle handles the POST requests sent to the tokens route.
+func (h PostHandler) Handle(w http.ResponseWriter, r *http.Request, _ string) {
+	// get auth token
+	ckAuth, err := cookie.GetAuth(r)
+	if err == http.ErrNoCookie {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Auth token not found.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// decode auth token
+	auth, err := h.authDecoder.Decode(*ckAuth)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		if err := json.NewEncoder(w).Encode(PostResp{
+			Error: "Invalid auth token.",
+		}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// decode request
+	var req PostReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// validate request
+	if err := h.validateReq(req); err != nil {
+		var msg string
+		switch {
+		case errors.Is(err, errNameEmpty):
+			msg = "Token name cannot be empty."
+		case errors.Is(err, errNameTooLong):
+			msg = "Token name cannot be longer than 35 characters."
+		case errors.Is(err, errScopesEmpty):
+			msg = "At least one scope must be granted."
+		case errors.Is(err, errScopeInvalid):
+			msg = "Scopes must be one of " + pat.ScopeTasksRead + ", " +
+				pat.ScopeTasksWrite + ", " + pat.ScopeTeamRead + ", " +
+				pat.ScopeTeamWrite + "."
+		case errors.Is(err, errExpiryOutOfBounds):
+			msg = "Tokens must expire in 1 to 365 days."
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		if err = json.NewEncoder(w).Encode(PostResp{Error: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			h.log.Error(err)
+		}
+		return
+	}
+
+	// validate the user has no token with the same name so that it can be
+	// revoked by its name
+	tokens, err := h.retrieverByUser.Retrieve(r.Context(), auth.Username)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+	for _, t := range tokens {
+		if t.Name == req.Name {
+			w.WriteHeader(http.StatusConflict)
+			if err := json.NewEncoder(w).Encode(PostResp{
+				Error: "You already have a token with this name.",
+			}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				h.log.Error(err)
+			}
+			return
+		}
+	}
+
+	// generate a token and store its hash - retry up to 3 times for the
+	// unlikely event that the generated token is a duplicate
+	var (
+		token string
+		now   = time.Now()
+		exp   = now.AddDate(0, 0, req.ExpiresInDays).Unix()
+	)
+	for i := 0; i < 3; i++ {
+		if token, err = pat.New(); err != nil {
+			break
+		}
+		if err = h.tokenInserter.Insert(r.Context(), tokentbl.NewToken(
+			tokentbl.HashToken(token),
+			auth.Username,
+			req.Name,
+			req.Scopes,
+			now.Unix(),
+			exp,
+		)); !errors.Is(err, db.ErrDupKey) {
+			break
+		}
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+		return
+	}
+
+	// respond with the token for the user to copy
+	if err = json.NewEncoder(w).Encode(PostResp{
+		Token:     token,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: exp,
+	}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
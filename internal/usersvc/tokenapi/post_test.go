@@ -0,0 +1,227 @@
+//go:build utest
+
+package tokenapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pat"
+)
+
+// TestPostHandler tests the Handle method of PostHandler to assert that it
+// behaves correctly in all possible scenarios.
+func TestPostHandler(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	var validateErr error
+	validateReq := func(PostReq) error { return validateErr }
+	retrieverByUser := &db.FakeRetriever[[]tokentbl.Token]{}
+	tokenInserter := &db.FakeInserter[tokentbl.Token]{}
+	log := &log.FakeErrorer{}
+	sut := NewPostHandler(
+		authDecoder, validateReq, retrieverByUser, tokenInserter, log,
+	)
+
+	authDecoder.Res = cookie.Auth{Username: "bob123"}
+	reqBody := `{"name": "ci", "scopes": ["tasks:read"], "expiresInDays": 30}`
+
+	for _, c := range []struct {
+		name          string
+		authToken     string
+		errDecodeAuth error
+		errValidate   error
+		tokens        []tokentbl.Token
+		errRetrieve   error
+		errInsert     error
+		wantStatus    int
+		assertFunc    func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:          "NoAuth",
+			authToken:     "",
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			tokens:        nil,
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:          "InvalidAuth",
+			authToken:     "nonempty",
+			errDecodeAuth: cookie.ErrInvalid,
+			errValidate:   nil,
+			tokens:        nil,
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusUnauthorized,
+			assertFunc:    assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:          "NameEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   errNameEmpty,
+			tokens:        nil,
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc:    assert.OnRespErr("Token name cannot be empty."),
+		},
+		{
+			name:          "NameTooLong",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   errNameTooLong,
+			tokens:        nil,
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Token name cannot be longer than 35 characters.",
+			),
+		},
+		{
+			name:          "ScopesEmpty",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   errScopesEmpty,
+			tokens:        nil,
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"At least one scope must be granted.",
+			),
+		},
+		{
+			name:          "ScopeInvalid",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   errScopeInvalid,
+			tokens:        nil,
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Scopes must be one of tasks:read, tasks:write, team:read, " +
+					"team:write.",
+			),
+		},
+		{
+			name:          "ExpiryOutOfBounds",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   errExpiryOutOfBounds,
+			tokens:        nil,
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusBadRequest,
+			assertFunc: assert.OnRespErr(
+				"Tokens must expire in 1 to 365 days.",
+			),
+		},
+		{
+			name:          "ErrValidate",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   errors.New("validate failed"),
+			tokens:        nil,
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("validate failed"),
+		},
+		{
+			name:          "ErrRetrieve",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			tokens:        nil,
+			errRetrieve:   errors.New("retrieve failed"),
+			errInsert:     nil,
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("retrieve failed"),
+		},
+		{
+			name:          "NameTaken",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			tokens:        []tokentbl.Token{{Name: "cli"}, {Name: "ci"}},
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusConflict,
+			assertFunc: assert.OnRespErr(
+				"You already have a token with this name.",
+			),
+		},
+		{
+			name:          "ErrInsert",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			tokens:        []tokentbl.Token{{Name: "cli"}},
+			errRetrieve:   nil,
+			errInsert:     errors.New("insert failed"),
+			wantStatus:    http.StatusInternalServerError,
+			assertFunc:    assert.OnLoggedErr("insert failed"),
+		},
+		{
+			name:          "OK",
+			authToken:     "nonempty",
+			errDecodeAuth: nil,
+			errValidate:   nil,
+			tokens:        []tokentbl.Token{{Name: "cli"}},
+			errRetrieve:   nil,
+			errInsert:     nil,
+			wantStatus:    http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				var respBody PostResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+				assert.True(t.Error,
+					strings.HasPrefix(respBody.Token, pat.Prefix),
+				)
+				assert.Equal(t.Error, respBody.Name, "ci")
+				assert.AllEqual(t.Error,
+					respBody.Scopes, []string{pat.ScopeTasksRead},
+				)
+				assert.True(t.Error, respBody.ExpiresAt > 0)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			authDecoder.Err = c.errDecodeAuth
+			validateErr = c.errValidate
+			retrieverByUser.Res = c.tokens
+			retrieverByUser.Err = c.errRetrieve
+			tokenInserter.Err = c.errInsert
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(reqBody),
+			)
+			if c.authToken != "" {
+				r.AddCookie(&http.Cookie{
+					Name: cookie.AuthName, Value: c.authToken,
+				})
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, log.Args)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package tokenapi contains code for responding to HTTP requests made to the
+// tokens API route, which is used by users for managing the personal access
+// tokens that they call the APIs with from scripts.
+package tokenapi
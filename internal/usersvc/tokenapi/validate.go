@@ -0,0 +1,46 @@
+package tokenapi
+
+import (
+	"errors"
+
+	"github.com/kxplxn/goteam/pkg/pat"
+)
+
+// ValidatePostReq validates a given PostReq.
+func ValidatePostReq(req PostReq) error {
+	if req.Name == "" {
+		return errNameEmpty
+	}
+	if len(req.Name) > 35 {
+		return errNameTooLong
+	}
+	if len(req.Scopes) == 0 {
+		return errScopesEmpty
+	}
+	for _, s := range req.Scopes {
+		if !pat.IsValidScope(s) {
+			return errScopeInvalid
+		}
+	}
+	if req.ExpiresInDays < 1 || req.ExpiresInDays > 365 {
+		return errExpiryOutOfBounds
+	}
+	return nil
+}
+
+var (
+	// errNameEmpty is returned when a token name is empty.
+	errNameEmpty = errors.New("name is empty")
+
+	// errNameTooLong is returned when a token name is too long.
+	errNameTooLong = errors.New("name is too long")
+
+	// errScopesEmpty is returned when no scopes are requested for a token.
+	errScopesEmpty = errors.New("scopes are empty")
+
+	// errScopeInvalid is returned when a requested scope doesn't exist.
+	errScopeInvalid = errors.New("scope is invalid")
+
+	// errExpiryOutOfBounds is returned when a token's expiry is out of bounds.
+	errExpiryOutOfBounds = errors.New("expiry is out of bounds")
+)
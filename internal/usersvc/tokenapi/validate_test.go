@@ -0,0 +1,77 @@
+//go:build utest
+
+package tokenapi
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/pat"
+)
+
+// TestValidatePostReq tests the ValidatePostReq function to assert that it
+// returns the correct error based on the PostReq input.
+func TestValidatePostReq(t *testing.T) {
+	sut := ValidatePostReq
+
+	for _, c := range []struct {
+		name    string
+		req     PostReq
+		wantErr error
+	}{
+		{
+			name:    "NameEmpty",
+			req:     PostReq{Name: ""},
+			wantErr: errNameEmpty,
+		},
+		{
+			name:    "NameTooLong",
+			req:     PostReq{Name: "abcdefghijklmnopqrstuvwxyzabcdefghij"},
+			wantErr: errNameTooLong,
+		},
+		{
+			name:    "ScopesEmpty",
+			req:     PostReq{Name: "ci", Scopes: []string{}},
+			wantErr: errScopesEmpty,
+		},
+		{
+			name: "ScopeInvalid",
+			req: PostReq{
+				Name: "ci", Scopes: []string{pat.ScopeTasksRead, "admin"},
+			},
+			wantErr: errScopeInvalid,
+		},
+		{
+			name: "ExpiryTooSmall",
+			req: PostReq{
+				Name:          "ci",
+				Scopes:        []string{pat.ScopeTasksRead},
+				ExpiresInDays: 0,
+			},
+			wantErr: errExpiryOutOfBounds,
+		},
+		{
+			name: "ExpiryTooBig",
+			req: PostReq{
+				Name:          "ci",
+				Scopes:        []string{pat.ScopeTasksRead},
+				ExpiresInDays: 366,
+			},
+			wantErr: errExpiryOutOfBounds,
+		},
+		{
+			name: "OK",
+			req: PostReq{
+				Name:          "ci",
+				Scopes:        []string{pat.ScopeTasksRead},
+				ExpiresInDays: 365,
+			},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := sut(c.req)
+			assert.ErrIs(t.Error, err, c.wantErr)
+		})
+	}
+}
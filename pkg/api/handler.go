@@ -25,7 +25,10 @@ func NewHandler(methodHandlers map[string]MethodHandler) Handler {
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// add cors headers
 	w.Header().Set("Access-Control-Allow-Origin", os.Getenv("CLIENTORIGIN"))
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set(
+		"Access-Control-Allow-Headers", "Content-Type, Authorization",
+	)
+	w.Header().Set("Access-Control-Expose-Headers", "Retry-After")
 	w.Header().Add("Access-Control-Allow-Credentials", "true")
 
 	// add allowed methods header
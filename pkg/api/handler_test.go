@@ -64,6 +64,10 @@ func TestHandler(t *testing.T) {
 
 				resp := w.Result()
 				assert.Equal(t.Error, resp.StatusCode, http.StatusOK)
+				assert.Equal(t.Error,
+					resp.Header.Get("Access-Control-Expose-Headers"),
+					"Retry-After",
+				)
 				fakeMethodHandler := methodHandler.(*FakeMethodHandler)
 				assert.Equal(t.Error, fakeMethodHandler.InResponseWriter, w)
 				assert.Equal(t.Error, fakeMethodHandler.InR, r)
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+// JWKSHandler is a MethodHandler that can be used to serve the public keys that
+// a service's JWTs can be verified with on the /.well-known/jwks.json route.
+type JWKSHandler struct {
+	jwks cookie.JWKS
+	log  log.Errorer
+}
+
+// NewJWKSHandler creates and returns a new JWKSHandler.
+func NewJWKSHandler(jwks cookie.JWKS, log log.Errorer) JWKSHandler {
+	return JWKSHandler{jwks: jwks, log: log}
+}
+
+// Handle handles the GET requests sent to the /.well-known/jwks.json route.
+func (h JWKSHandler) Handle(w http.ResponseWriter, _ *http.Request, _ string) {
+	// key sets are cached by their consumers, so they can be cached on the way
+	// too as long as the cache is short enough for key rotations to go through
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := json.NewEncoder(w).Encode(h.jwks); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Error(err)
+	}
+}
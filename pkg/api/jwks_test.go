@@ -0,0 +1,41 @@
+//go:build utest
+
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/log"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	key, err := cookie.NewSigningKey(
+		"kid1", ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)),
+	)
+	assert.Nil(t.Fatal, err)
+	sut := NewJWKSHandler(cookie.NewJWKS(key), &log.FakeErrorer{})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+
+	sut.Handle(w, r, "")
+
+	resp := w.Result()
+	assert.Equal(t.Error, resp.StatusCode, http.StatusOK)
+	assert.Equal(t.Error,
+		resp.Header.Get("Content-Type"), "application/json",
+	)
+	assert.Equal(t.Error,
+		resp.Header.Get("Cache-Control"), "public, max-age=300",
+	)
+	var jwks cookie.JWKS
+	assert.Nil(t.Fatal, json.NewDecoder(resp.Body).Decode(&jwks))
+	assert.Equal(t.Fatal, len(jwks.Keys), 1)
+	assert.Equal(t.Error, jwks.Keys[0], cookie.NewJWKS(key).Keys[0])
+}
@@ -1,48 +1,89 @@
 package cookie
 
 import (
+	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 // AuthName is the name of the auth token.
 const AuthName = "auth-token"
 
-// Auth defines the body of an Auth token.
+// Auth defines the body of an Auth token. IsAdmin is derived from Role and is
+// kept so that the client can tell admins apart without knowing about roles.
+// TokenID, IssuedAtMilli, and ExpiresAt are set by the encoder and only
+// populated on decoded tokens. IssuedAtMilli is in Unix milliseconds so that
+// tokens issued within the same second as a revocation can be told apart.
+// Scopes is only set when the request was authenticated with a personal access
+// token instead, and is nil otherwise, which means that there are no
+// restrictions.
 type Auth struct {
-	Username string
-	IsAdmin  bool
-	TeamID   string
+	Username      string
+	Role          role.Role
+	IsAdmin       bool
+	TeamID        string
+	TokenID       string
+	IssuedAtMilli int64
+	ExpiresAt     int64
+	Scopes        []string
 }
 
 // NewAuth creates and returns a new Auth.
-func NewAuth(username string, isAdmin bool, teamID string) Auth {
-	return Auth{Username: username, IsAdmin: isAdmin, TeamID: teamID}
+func NewAuth(username string, r role.Role, teamID string) Auth {
+	return Auth{
+		Username: username, Role: r, IsAdmin: r.IsAdmin(), TeamID: teamID,
+	}
+}
+
+// GetAuth returns the auth token sent with the request. It is read from the
+// auth cookie, or from the Authorization header if it is sent as a bearer token
+// instead, in which case it is returned as an auth cookie so that it can be
+// decoded the same way. It returns http.ErrNoCookie if neither is present.
+func GetAuth(r *http.Request) (*http.Cookie, error) {
+	ck, err := r.Cookie(AuthName)
+	if err != http.ErrNoCookie {
+		return ck, err
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, http.ErrNoCookie
+	}
+	return &http.Cookie{Name: AuthName, Value: token}, nil
 }
 
 // EncoderAuth defines a type that can be used to encode an auth token.
 type EncoderAuth struct {
-	key []byte
+	key SigningKey
 	dur time.Duration
 }
 
 // NewAuthEncoder creates and returns a new AuthEncoder.
-func NewAuthEncoder(jwtKey []byte, duration time.Duration) EncoderAuth {
-	return EncoderAuth{key: jwtKey, dur: duration}
+func NewAuthEncoder(key SigningKey, duration time.Duration) EncoderAuth {
+	return EncoderAuth{key: key, dur: duration}
 }
 
 // Encode encodes an Auth into a JWT string.
 func (e EncoderAuth) Encode(auth Auth) (http.Cookie, error) {
-	exp := time.Now().Add(e.dur)
+	now := time.Now()
+	exp := now.Add(e.dur)
 
-	tk, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	tk, err := sign(e.key, jwt.MapClaims{
 		"username": auth.Username,
+		"role":     string(auth.Role),
 		"isAdmin":  auth.IsAdmin,
 		"teamID":   auth.TeamID,
+		"jti":      uuid.NewString(),
+		"iat":      now.Unix(),
+		"iatMilli": now.UnixMilli(),
 		"exp":      exp.Unix(),
-	}).SignedString(e.key)
+	})
 	if err != nil {
 		return http.Cookie{}, err
 	}
@@ -56,11 +97,28 @@ func (e EncoderAuth) Encode(auth Auth) (http.Cookie, error) {
 	}, nil
 }
 
+// RevocationChecker defines a type that can be used to check whether an auth
+// token has been revoked, either by its ID or by its user having invalidated
+// all of their sessions after it was issued at issuedAtMilli.
+type RevocationChecker interface {
+	IsRevoked(
+		ctx context.Context,
+		username string,
+		tokenID string,
+		issuedAtMilli int64,
+	) (bool, error)
+}
+
 // AuthDecoder defines a type that can be used to decode an auth token.
-type AuthDecoder struct{ key []byte }
+type AuthDecoder struct {
+	keys        KeySet
+	revocations RevocationChecker
+}
 
 // NewAuthDecoder creates and returns a new AuthDecoder.
-func NewAuthDecoder(key []byte) AuthDecoder { return AuthDecoder{key: key} }
+func NewAuthDecoder(keys KeySet, revocations RevocationChecker) AuthDecoder {
+	return AuthDecoder{keys: keys, revocations: revocations}
+}
 
 // Decode validates and decodes a raw JWT string into an Auth.
 func (d AuthDecoder) Decode(ck http.Cookie) (Auth, error) {
@@ -69,11 +127,7 @@ func (d AuthDecoder) Decode(ck http.Cookie) (Auth, error) {
 	}
 
 	claims := jwt.MapClaims{}
-	if _, err := jwt.ParseWithClaims(
-		ck.Value, &claims, func(token *jwt.Token) (any, error) {
-			return d.key, nil
-		},
-	); err != nil {
+	if err := parse(d.keys, ck.Value, &claims); err != nil {
 		return Auth{}, err
 	}
 
@@ -92,5 +146,43 @@ func (d AuthDecoder) Decode(ck http.Cookie) (Auth, error) {
 		return Auth{}, ErrInvalid
 	}
 
-	return NewAuth(username, isAdmin, teamID), nil
+	// tokens issued before roles were introduced don't have a role claim, so
+	// their role is derived from the isAdmin claim instead
+	r := role.Role("")
+	if rawRole, ok := claims["role"].(string); ok {
+		if r = role.Role(rawRole); !r.IsValid() {
+			return Auth{}, ErrInvalid
+		}
+	} else {
+		r = role.FromIsAdmin(isAdmin)
+	}
+
+	// tokens issued before jti and iat claims were introduced don't have
+	// them, so they are not required - the ones issued before the iatMilli
+	// claim was introduced are taken to be issued at the start of their second
+	auth := NewAuth(username, r, teamID)
+	auth.TokenID, _ = claims["jti"].(string)
+	if iatMilli, ok := claims["iatMilli"].(float64); ok {
+		auth.IssuedAtMilli = int64(iatMilli)
+	} else if iat, ok := claims["iat"].(float64); ok {
+		auth.IssuedAtMilli = int64(iat) * 1000
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		auth.ExpiresAt = int64(exp)
+	}
+
+	isRevoked, err := d.revocations.IsRevoked(
+		context.Background(),
+		auth.Username,
+		auth.TokenID,
+		auth.IssuedAtMilli,
+	)
+	if err != nil {
+		return Auth{}, err
+	}
+	if isRevoked {
+		return Auth{}, ErrRevoked
+	}
+
+	return auth, nil
 }
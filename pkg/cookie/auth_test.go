@@ -3,26 +3,30 @@
 package cookie
 
 import (
+	"crypto/ed25519"
+	"errors"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 
 	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 func TestAuth(t *testing.T) {
-	key := []byte("signkey")
+	key := newTestKey(t, "kid1", 1)
 	username := "bob123"
-	isAdmin := true
+	r := role.Admin
 	teamID := "teamid"
 
 	t.Run("Encode", func(t *testing.T) {
 		dur := 1 * time.Hour
 		sut := NewAuthEncoder(key, dur)
 
-		ck, err := sut.Encode(NewAuth(username, isAdmin, teamID))
+		ck, err := sut.Encode(NewAuth(username, r, teamID))
 		assert.Nil(t.Fatal, err)
 
 		assert.Nil(t.Fatal, ck.Valid())
@@ -35,16 +39,28 @@ func TestAuth(t *testing.T) {
 			ck.Expires.UTC().Before(time.Now().Add(61*time.Minute).UTC()))
 
 		claims := jwt.MapClaims{}
-		_, err = jwt.ParseWithClaims(
+		tk, err := jwt.ParseWithClaims(
 			ck.Value, &claims, func(token *jwt.Token) (any, error) {
-				return key, nil
+				return key.Key.Public(), nil
 			},
 		)
 		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, tk.Method.Alg(), "EdDSA")
+		assert.Equal(t.Error, tk.Header["kid"].(string), key.ID)
 
 		assert.Equal(t.Error, claims["username"].(string), username)
-		assert.Equal(t.Error, claims["isAdmin"].(bool), isAdmin)
+		assert.Equal(t.Error, claims["role"].(string), string(r))
+		assert.Equal(t.Error, claims["isAdmin"].(bool), true)
 		assert.Equal(t.Error, claims["teamID"].(string), teamID)
+		_, err = uuid.Parse(claims["jti"].(string))
+		assert.Nil(t.Error, err)
+		assert.True(t.Error,
+			int64(claims["iat"].(float64)) <= time.Now().Unix(),
+		)
+		iatMilli := int64(claims["iatMilli"].(float64))
+		assert.Equal(t.Error,
+			iatMilli/1000, int64(claims["iat"].(float64)),
+		)
 		assert.True(t.Error,
 			int64(claims["exp"].(float64)) >
 				time.Now().Add(59*time.Minute).Unix(),
@@ -56,65 +72,325 @@ func TestAuth(t *testing.T) {
 	})
 
 	t.Run("Decode", func(t *testing.T) {
-		sut := NewAuthDecoder(key)
+		oldKey := newTestKey(t, "kid0", 0)
+		revocations := &FakeRevocationChecker{}
+		sut := NewAuthDecoder(NewStaticKeySet(key, oldKey), revocations)
+
+		claims := jwt.MapClaims{
+			"username": username,
+			"role":     string(r),
+			"isAdmin":  true,
+			"teamID":   teamID,
+			"exp":      time.Now().Add(time.Hour).Unix(),
+		}
+		tkValid := signTestToken(t, key.Method, key.ID, key.Key, claims)
+		errCheck := errors.New("check revocation failed")
 
 		for _, c := range []struct {
 			name         string
 			token        string
 			wantUsername string
+			wantRole     role.Role
 			wantIsAdmin  bool
 			wantTeamID   string
+			isRevoked    bool
+			errRevoked   error
 			wantErr      error
 		}{
 			{
 				name: "InvalidSignature",
-				token: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJleHAiOjE3MDE5" +
-					"MDE0NzMsInRlYW1JRCI6ImFza2RqZmFza2RmamFoIn0.g0jCuok1he1o" +
-					"puHRGfVmvuGtpwfWlIBbnRK64qgLsx4",
+				token: signTestToken(
+					t, key.Method, key.ID, oldKey.Key, claims,
+				),
+				wantUsername: "",
+				wantRole:     "",
+				wantIsAdmin:  false,
+				wantTeamID:   "",
+				isRevoked:    false,
+				errRevoked:   nil,
+				wantErr:      jwt.ErrTokenSignatureInvalid,
+			},
+			{
+				name: "AlgHS256",
+				token: signTestToken(
+					t, jwt.SigningMethodHS256, key.ID,
+					[]byte(key.Key.Public().(ed25519.PublicKey)), claims,
+				),
+				wantUsername: "",
+				wantRole:     "",
+				wantIsAdmin:  false,
+				wantTeamID:   "",
+				isRevoked:    false,
+				errRevoked:   nil,
+				wantErr:      jwt.ErrTokenSignatureInvalid,
+			},
+			{
+				name: "AlgNone",
+				token: signTestToken(
+					t, jwt.SigningMethodNone, key.ID,
+					jwt.UnsafeAllowNoneSignatureType, claims,
+				),
+				wantUsername: "",
+				wantRole:     "",
+				wantIsAdmin:  false,
+				wantTeamID:   "",
+				isRevoked:    false,
+				errRevoked:   nil,
+				wantErr:      jwt.ErrTokenSignatureInvalid,
+			},
+			{
+				name: "UnknownKey",
+				token: signTestToken(
+					t, key.Method, "kid2", key.Key, claims,
+				),
 				wantUsername: "",
+				wantRole:     "",
 				wantIsAdmin:  false,
 				wantTeamID:   "",
-				wantErr:      jwt.ErrSignatureInvalid,
+				isRevoked:    false,
+				errRevoked:   nil,
+				wantErr:      ErrUnknownKey,
 			},
 			{
 				name: "TokenMalformed",
 				token: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCb2IyMSJ9.k6QDVjyaHx" +
 					"PYixeoQBLixC5c79VK-WZ_kD9u4fjX_Ks",
 				wantUsername: "",
+				wantRole:     "",
 				wantIsAdmin:  false,
 				wantTeamID:   "",
+				isRevoked:    false,
+				errRevoked:   nil,
 				wantErr:      jwt.ErrTokenMalformed,
 			},
 			{
 				name: "Expired",
-				token: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJleHAiOjE3MDE4" +
-					"OTgwOTYsInRlYW1JRCI6InRlYW1pZCJ9.MAORMCFqzNrLnY4l_wrPA86" +
-					"K9w6W9pzH_4b6iNHr1SE",
+				token: signTestToken(t, key.Method, key.ID, key.Key,
+					jwt.MapClaims{
+						"username": username,
+						"role":     string(r),
+						"isAdmin":  true,
+						"teamID":   teamID,
+						"exp":      time.Now().Add(-time.Hour).Unix(),
+					},
+				),
 				wantUsername: "",
+				wantRole:     "",
 				wantIsAdmin:  false,
 				wantTeamID:   "",
+				isRevoked:    false,
+				errRevoked:   nil,
 				wantErr:      jwt.ErrTokenExpired,
 			},
 			{
-				name: "Success",
-				token: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJib2FyZElEcyI6" +
-					"WyJib2FyZDEiLCJib2FyZDIiXSwiaXNBZG1pbiI6dHJ1ZSwidGVhbUlE" +
-					"IjoidGVhbWlkIiwidXNlcm5hbWUiOiJib2IxMjMifQ.4uS5-QGd3Gj2I" +
-					"5Jm2-dnq-1-_3IqcBepLBdzPjjfRuM",
+				name: "InvalidRole",
+				token: signTestToken(t, key.Method, key.ID, key.Key,
+					jwt.MapClaims{
+						"username": username,
+						"role":     "superuser",
+						"isAdmin":  true,
+						"teamID":   teamID,
+						"exp":      time.Now().Add(time.Hour).Unix(),
+					},
+				),
+				wantUsername: "",
+				wantRole:     "",
+				wantIsAdmin:  false,
+				wantTeamID:   "",
+				isRevoked:    false,
+				errRevoked:   nil,
+				wantErr:      ErrInvalid,
+			},
+			{
+				name:         "ErrCheckRevoked",
+				token:        tkValid,
+				wantUsername: "",
+				wantRole:     "",
+				wantIsAdmin:  false,
+				wantTeamID:   "",
+				isRevoked:    false,
+				errRevoked:   errCheck,
+				wantErr:      errCheck,
+			},
+			{
+				name:         "Revoked",
+				token:        tkValid,
+				wantUsername: "",
+				wantRole:     "",
+				wantIsAdmin:  false,
+				wantTeamID:   "",
+				isRevoked:    true,
+				errRevoked:   nil,
+				wantErr:      ErrRevoked,
+			},
+			{
+				name: "SuccessRotatedKey",
+				token: signTestToken(
+					t, oldKey.Method, oldKey.ID, oldKey.Key, claims,
+				),
 				wantUsername: username,
-				wantIsAdmin:  isAdmin,
+				wantRole:     r,
+				wantIsAdmin:  true,
 				wantTeamID:   teamID,
+				isRevoked:    false,
+				errRevoked:   nil,
+				wantErr:      nil,
+			},
+			{
+				name: "SuccessNoRole",
+				token: signTestToken(t, key.Method, key.ID, key.Key,
+					jwt.MapClaims{
+						"username": username,
+						"isAdmin":  false,
+						"teamID":   teamID,
+						"exp":      time.Now().Add(time.Hour).Unix(),
+					},
+				),
+				wantUsername: username,
+				wantRole:     role.Member,
+				wantIsAdmin:  false,
+				wantTeamID:   teamID,
+				isRevoked:    false,
+				errRevoked:   nil,
+				wantErr:      nil,
+			},
+			{
+				name:         "Success",
+				token:        tkValid,
+				wantUsername: username,
+				wantRole:     r,
+				wantIsAdmin:  true,
+				wantTeamID:   teamID,
+				isRevoked:    false,
+				errRevoked:   nil,
 				wantErr:      nil,
 			},
 		} {
 			t.Run(c.name, func(t *testing.T) {
+				revocations.Res = c.isRevoked
+				revocations.Err = c.errRevoked
+
 				auth, err := sut.Decode(http.Cookie{Value: c.token})
 
 				assert.ErrIs(t.Error, err, c.wantErr)
 				assert.Equal(t.Error, auth.Username, c.wantUsername)
+				assert.Equal(t.Error, auth.Role, c.wantRole)
 				assert.Equal(t.Error, auth.IsAdmin, c.wantIsAdmin)
 				assert.Equal(t.Error, auth.TeamID, c.wantTeamID)
 			})
 		}
 	})
+
+	t.Run("DecodeIssuedAt", func(t *testing.T) {
+		sut := NewAuthDecoder(NewStaticKeySet(key), &FakeRevocationChecker{})
+
+		for _, c := range []struct {
+			name              string
+			claims            jwt.MapClaims
+			wantIssuedAtMilli int64
+		}{
+			{
+				name:              "None",
+				claims:            jwt.MapClaims{},
+				wantIssuedAtMilli: 0,
+			},
+			{
+				name:              "Seconds",
+				claims:            jwt.MapClaims{"iat": 1700000000},
+				wantIssuedAtMilli: 1700000000000,
+			},
+			{
+				name: "Milliseconds",
+				claims: jwt.MapClaims{
+					"iat": 1700000000, "iatMilli": 1700000000123,
+				},
+				wantIssuedAtMilli: 1700000000123,
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				c.claims["username"] = username
+				c.claims["isAdmin"] = true
+				c.claims["teamID"] = teamID
+				c.claims["exp"] = time.Now().Add(time.Hour).Unix()
+				tk := signTestToken(t, key.Method, key.ID, key.Key, c.claims)
+
+				auth, err := sut.Decode(http.Cookie{Value: tk})
+
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, auth.IssuedAtMilli, c.wantIssuedAtMilli)
+			})
+		}
+	})
+}
+
+func TestGetAuth(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		cookie    string
+		header    string
+		wantValue string
+		wantErr   error
+	}{
+		{
+			name:      "None",
+			cookie:    "",
+			header:    "",
+			wantValue: "",
+			wantErr:   http.ErrNoCookie,
+		},
+		{
+			name:      "NotBearer",
+			cookie:    "",
+			header:    "Basic Ym9iMTIzOnBhc3M=",
+			wantValue: "",
+			wantErr:   http.ErrNoCookie,
+		},
+		{
+			name:      "EmptyBearer",
+			cookie:    "",
+			header:    "Bearer ",
+			wantValue: "",
+			wantErr:   http.ErrNoCookie,
+		},
+		{
+			name:      "Cookie",
+			cookie:    "cookietoken",
+			header:    "",
+			wantValue: "cookietoken",
+			wantErr:   nil,
+		},
+		{
+			name:      "Bearer",
+			cookie:    "",
+			header:    "bearer headertoken",
+			wantValue: "headertoken",
+			wantErr:   nil,
+		},
+		{
+			name:      "CookieOverBearer",
+			cookie:    "cookietoken",
+			header:    "Bearer headertoken",
+			wantValue: "cookietoken",
+			wantErr:   nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "/", nil)
+			assert.Nil(t.Fatal, err)
+			if c.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: AuthName, Value: c.cookie})
+			}
+			if c.header != "" {
+				r.Header.Set("Authorization", c.header)
+			}
+
+			ck, err := GetAuth(r)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			if c.wantErr == nil {
+				assert.Equal(t.Error, ck.Name, AuthName)
+				assert.Equal(t.Error, ck.Value, c.wantValue)
+			}
+		})
+	}
 }
@@ -7,14 +7,28 @@ import (
 	"net/http"
 )
 
+// NewExpired creates and returns an expired cookie with the given name, which
+// can be set on a response to delete the cookie with that name on the client.
+func NewExpired(name string) http.Cookie {
+	return http.Cookie{
+		Name:     name,
+		Value:    "",
+		MaxAge:   -1,
+		SameSite: http.SameSiteNoneMode,
+		Secure:   true,
+	}
+}
+
 // Encoder defines a type that can be used to encode a JWT.
 type Encoder[T any] interface{ Encode(T) (http.Cookie, error) }
 
 // Decoder defines a type that can be used to decode a JWT.
 type Decoder[T any] interface{ Decode(http.Cookie) (T, error) }
 
-// StringDecoder defines a type that can be used to decode a JWT from a string.
-type StringDecoder[T any] interface{ Decode(string) (T, error) }
+var (
+	// ErrInvalid means that the given cookie was invalid.
+	ErrInvalid = errors.New("invalid cookie")
 
-// ErrInvalid means that the given cookie was invalid.
-var ErrInvalid = errors.New("invalid cookie")
+	// ErrRevoked means that the given cookie was valid but has been revoked.
+	ErrRevoked = errors.New("revoked cookie")
+)
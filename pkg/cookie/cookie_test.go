@@ -0,0 +1,21 @@
+//go:build utest
+
+package cookie
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestNewExpired(t *testing.T) {
+	ck := NewExpired(AuthName)
+
+	assert.Nil(t.Fatal, ck.Valid())
+	assert.Equal(t.Error, ck.Name, AuthName)
+	assert.Equal(t.Error, ck.Value, "")
+	assert.True(t.Error, ck.MaxAge < 0)
+	assert.Equal(t.Error, ck.SameSite, http.SameSiteNoneMode)
+	assert.True(t.Error, ck.Secure)
+}
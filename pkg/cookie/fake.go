@@ -3,6 +3,7 @@
 package cookie
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -30,14 +31,16 @@ func (f *FakeDecoder[T]) Decode(http.Cookie) (T, error) {
 	return f.Res, f.Err
 }
 
-// FakeStringDecoder is a test fake for StringDecoder.
-type FakeStringDecoder[T any] struct {
-	Res T
+// FakeRevocationChecker is a test fake for RevocationChecker.
+type FakeRevocationChecker struct {
+	Res bool
 	Err error
 }
 
-// FakeStringDecoder discards the input parameters and returns the
-// FakeStringDecoder's Res and Err field values.
-func (f *FakeStringDecoder[T]) Decode(string) (T, error) {
+// IsRevoked discards the input parameters and returns the
+// FakeRevocationChecker's Res and Err field values.
+func (f *FakeRevocationChecker) IsRevoked(
+	context.Context, string, string, int64,
+) (bool, error) {
 	return f.Res, f.Err
 }
@@ -0,0 +1,87 @@
+package cookie
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+)
+
+// JWKS defines a JSON Web Key Set as described in RFC 7517, which is used to
+// publish the public keys that JWTs can be verified with.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK defines a JSON Web Key. Only the fields needed for Ed25519 (RFC 8037) and
+// RSA (RFC 7518) public keys are included.
+type JWK struct {
+	ID  string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// NewJWKS creates and returns a new JWKS that holds the public keys of the
+// given signing keys.
+func NewJWKS(keys ...SigningKey) JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, k := range keys {
+		jwk := JWK{ID: k.ID, Alg: k.Method.Alg(), Use: "sig"}
+		switch pub := k.Key.Public().(type) {
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(
+				big.NewInt(int64(pub.E)).Bytes(),
+			)
+		default:
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+// PublicKey decodes the JWK into an Ed25519 or RSA public key.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, ErrUnsupportedKey
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
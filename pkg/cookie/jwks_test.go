@@ -0,0 +1,63 @@
+//go:build utest
+
+package cookie
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestJWKS(t *testing.T) {
+	edKey := newTestKey(t, "kid1", 1)
+	rsaPriv, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t.Fatal, err)
+	rsaKey, err := NewSigningKey("kid2", rsaPriv)
+	assert.Nil(t.Fatal, err)
+
+	jwks := NewJWKS(edKey, rsaKey)
+	assert.Equal(t.Fatal, len(jwks.Keys), 2)
+
+	t.Run("Ed25519", func(t *testing.T) {
+		jwk := jwks.Keys[0]
+		assert.Equal(t.Error, jwk.ID, "kid1")
+		assert.Equal(t.Error, jwk.Kty, "OKP")
+		assert.Equal(t.Error, jwk.Alg, "EdDSA")
+		assert.Equal(t.Error, jwk.Use, "sig")
+		assert.Equal(t.Error, jwk.Crv, "Ed25519")
+
+		pub, err := jwk.PublicKey()
+
+		assert.Nil(t.Fatal, err)
+		assert.True(t.Error,
+			edKey.Key.Public().(ed25519.PublicKey).Equal(pub))
+	})
+
+	t.Run("RSA", func(t *testing.T) {
+		jwk := jwks.Keys[1]
+		assert.Equal(t.Error, jwk.ID, "kid2")
+		assert.Equal(t.Error, jwk.Kty, "RSA")
+		assert.Equal(t.Error, jwk.Alg, "RS256")
+		assert.Equal(t.Error, jwk.Use, "sig")
+		assert.Equal(t.Error, jwk.E, "AQAB")
+
+		pub, err := jwk.PublicKey()
+
+		assert.Nil(t.Fatal, err)
+		assert.True(t.Error, rsaPriv.PublicKey.Equal(pub))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		for _, jwk := range []JWK{
+			{Kty: "EC", Crv: "P-256"},
+			{Kty: "OKP", Crv: "X25519"},
+		} {
+			_, err := jwk.PublicKey()
+
+			assert.ErrIs(t.Error, err, ErrUnsupportedKey)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+//go:build utest
+
+package cookie
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// newTestKey creates a deterministic Ed25519 SigningKey to be used in tests.
+func newTestKey(t *testing.T, id string, seed byte) SigningKey {
+	key, err := NewSigningKey(id, ed25519.NewKeyFromSeed(
+		bytes.Repeat([]byte{seed}, ed25519.SeedSize),
+	))
+	assert.Nil(t.Fatal, err)
+	return key
+}
+
+// signTestToken signs the given claims with the given method and key, setting
+// the kid header to kid, to be used in tests.
+func signTestToken(
+	t *testing.T, method jwt.SigningMethod, kid string, key any,
+	claims jwt.MapClaims,
+) string {
+	tk := jwt.NewWithClaims(method, claims)
+	tk.Header["kid"] = kid
+	s, err := tk.SignedString(key)
+	assert.Nil(t.Fatal, err)
+	return s
+}
+
+// encodeTestPEM encodes the given private key into PKCS #8 PEM, to be used in
+// tests.
+func encodeTestPEM(t *testing.T, key crypto.Signer) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t.Fatal, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestSigningKey(t *testing.T) {
+	edKey := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t.Fatal, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t.Fatal, err)
+
+	for _, c := range []struct {
+		name       string
+		key        crypto.Signer
+		wantMethod jwt.SigningMethod
+		wantErr    error
+	}{
+		{
+			name:       "Unsupported",
+			key:        ecKey,
+			wantMethod: nil,
+			wantErr:    ErrUnsupportedKey,
+		},
+		{
+			name:       "Ed25519",
+			key:        edKey,
+			wantMethod: jwt.SigningMethodEdDSA,
+			wantErr:    nil,
+		},
+		{
+			name:       "RSA",
+			key:        rsaKey,
+			wantMethod: jwt.SigningMethodRS256,
+			wantErr:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Run("New", func(t *testing.T) {
+				key, err := NewSigningKey("kid", c.key)
+
+				assert.ErrIs(t.Error, err, c.wantErr)
+				assert.Equal(t.Error, key.Method, c.wantMethod)
+			})
+
+			t.Run("Parse", func(t *testing.T) {
+				key, err := ParseSigningKey("kid", encodeTestPEM(t, c.key))
+
+				assert.ErrIs(t.Error, err, c.wantErr)
+				assert.Equal(t.Error, key.Method, c.wantMethod)
+			})
+		})
+	}
+
+	t.Run("ParseNoPEM", func(t *testing.T) {
+		_, err := ParseSigningKey("kid", []byte("notapemfile"))
+
+		assert.Equal(t.Error, err.Error(), "no PEM block found")
+	})
+
+	t.Run("Load", func(t *testing.T) {
+		dir := t.TempDir()
+		pathEd := filepath.Join(dir, "ed.pem")
+		pathRSA := filepath.Join(dir, "rsa.pem")
+		assert.Nil(t.Fatal, os.WriteFile(pathEd, encodeTestPEM(t, edKey), 0600))
+		assert.Nil(t.Fatal,
+			os.WriteFile(pathRSA, encodeTestPEM(t, rsaKey), 0600))
+
+		t.Run("InvalidSpec", func(t *testing.T) {
+			_, err := LoadSigningKeys("kid1")
+
+			assert.Equal(t.Error, err.Error(), "invalid signing key spec: kid1")
+		})
+
+		t.Run("FileNotFound", func(t *testing.T) {
+			_, err := LoadSigningKeys("kid1:" + filepath.Join(dir, "no.pem"))
+
+			assert.ErrIs(t.Error, err, os.ErrNotExist)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			keys, err := LoadSigningKeys(
+				"kid1:" + pathEd + ", kid2:" + pathRSA,
+			)
+
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Fatal, len(keys), 2)
+			assert.Equal(t.Error, keys[0].ID, "kid1")
+			assert.Equal(t.Error, keys[0].Method, jwt.SigningMethodEdDSA)
+			assert.Equal(t.Error, keys[1].ID, "kid2")
+			assert.Equal(t.Error, keys[1].Method, jwt.SigningMethodRS256)
+		})
+	})
+}
@@ -0,0 +1,161 @@
+package cookie
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey means that no public key was found for a JWT's kid header.
+var ErrUnknownKey = errors.New("unknown key")
+
+// KeySet defines a type that can be used to look up the public key to verify a
+// JWT with by the JWT's kid header.
+type KeySet interface {
+	Key(kid string) (crypto.PublicKey, error)
+}
+
+// StaticKeySet is a KeySet that holds a fixed set of public keys. It is used by
+// the service that holds the signing keys.
+type StaticKeySet map[string]crypto.PublicKey
+
+// NewStaticKeySet creates and returns a new StaticKeySet that holds the public
+// keys of the given signing keys.
+func NewStaticKeySet(keys ...SigningKey) StaticKeySet {
+	s := StaticKeySet{}
+	for _, k := range keys {
+		s[k.ID] = k.Key.Public()
+	}
+	return s
+}
+
+// Key returns the public key with the given ID.
+func (s StaticKeySet) Key(kid string) (crypto.PublicKey, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// minRefetch is the minimum time between two fetches of a RemoteKeySet's JWKS
+// that are triggered by an unknown kid, so that tokens with made-up kids can't
+// be used to flood the JWKS endpoint.
+const minRefetch = time.Minute
+
+// RemoteKeySet is a KeySet that fetches its public keys from a JWKS endpoint
+// and caches them. The keys are refetched when the cache gets older than the
+// given TTL, or when an unknown kid is seen since a new key may have been
+// rotated in. Concurrent lookups that need a refetch share a single one, and
+// the cache is only locked to read or swap the keys, so lookups that can be
+// served from it don't wait for the JWKS endpoint.
+type RemoteKeySet struct {
+	url       string
+	ttl       time.Duration
+	client    *http.Client
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	inflight  *keyFetch
+}
+
+// keyFetch is a JWKS fetch in progress. done is closed once it completes and
+// err is set.
+type keyFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewRemoteKeySet creates and returns a new RemoteKeySet.
+func NewRemoteKeySet(url string, ttl time.Duration) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   map[string]crypto.PublicKey{},
+	}
+}
+
+// Key returns the public key with the given ID, fetching the JWKS if needed.
+// If the fetch fails, the cached key is returned if there is one.
+func (s *RemoteKeySet) Key(kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	key, ok := s.keys[kid]
+	age := time.Since(s.fetchedAt)
+	s.mu.Unlock()
+
+	if age > s.ttl || (!ok && age > minRefetch) {
+		if err := s.refresh(); err != nil {
+			if ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		s.mu.Lock()
+		key, ok = s.keys[kid]
+		s.mu.Unlock()
+	}
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// refresh fetches the JWKS and swaps the cached keys with the ones in it. If a
+// fetch is already in progress, it waits for that one instead.
+func (s *RemoteKeySet) refresh() error {
+	s.mu.Lock()
+	if f := s.inflight; f != nil {
+		s.mu.Unlock()
+		<-f.done
+		return f.err
+	}
+	f := &keyFetch{done: make(chan struct{})}
+	s.inflight = f
+	s.mu.Unlock()
+
+	keys, err := s.fetch()
+
+	s.mu.Lock()
+	if err == nil {
+		s.keys, s.fetchedAt = keys, time.Now()
+	}
+	f.err = err
+	s.inflight = nil
+	s.mu.Unlock()
+	close(f.done)
+	return err
+}
+
+// fetch fetches the JWKS and returns the public keys in it by their IDs.
+func (s *RemoteKeySet) fetch() (map[string]crypto.PublicKey, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected jwks status: %d", resp.StatusCode)
+	}
+
+	var jwks JWKS
+	if err = json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		key, err := jwk.PublicKey()
+		if errors.Is(err, ErrUnsupportedKey) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		keys[jwk.ID] = key
+	}
+	return keys, nil
+}
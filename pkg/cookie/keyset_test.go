@@ -0,0 +1,178 @@
+//go:build utest
+
+package cookie
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestStaticKeySet(t *testing.T) {
+	key := newTestKey(t, "kid1", 1)
+	sut := NewStaticKeySet(key)
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		_, err := sut.Key("kid2")
+
+		assert.ErrIs(t.Error, err, ErrUnknownKey)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		pub, err := sut.Key("kid1")
+
+		assert.Nil(t.Fatal, err)
+		assert.True(t.Error, key.Key.Public().(ed25519.PublicKey).Equal(pub))
+	})
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	key1 := newTestKey(t, "kid1", 1)
+	key2 := newTestKey(t, "kid2", 2)
+
+	jwks := NewJWKS(key1)
+	status := http.StatusOK
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fetches++
+			w.WriteHeader(status)
+			if err := json.NewEncoder(w).Encode(jwks); err != nil {
+				t.Fatal(err)
+			}
+		},
+	))
+	defer srv.Close()
+
+	sut := NewRemoteKeySet(srv.URL, time.Hour)
+
+	t.Run("FetchesOnFirstUse", func(t *testing.T) {
+		_, err := sut.Key("kid1")
+
+		assert.Nil(t.Error, err)
+		assert.Equal(t.Error, fetches, 1)
+	})
+
+	t.Run("UsesCache", func(t *testing.T) {
+		_, err := sut.Key("kid1")
+
+		assert.Nil(t.Error, err)
+		assert.Equal(t.Error, fetches, 1)
+	})
+
+	t.Run("UnknownKeyRateLimited", func(t *testing.T) {
+		_, err := sut.Key("kid2")
+
+		assert.ErrIs(t.Error, err, ErrUnknownKey)
+		assert.Equal(t.Error, fetches, 1)
+	})
+
+	t.Run("UnknownKeyRefetched", func(t *testing.T) {
+		jwks = NewJWKS(key2, key1)
+		sut.fetchedAt = time.Now().Add(-2 * minRefetch)
+
+		_, err := sut.Key("kid2")
+
+		assert.Nil(t.Error, err)
+		assert.Equal(t.Error, fetches, 2)
+	})
+
+	t.Run("ExpiredRefetched", func(t *testing.T) {
+		jwks = NewJWKS(key2)
+		sut.fetchedAt = time.Now().Add(-2 * time.Hour)
+
+		_, err := sut.Key("kid1")
+
+		assert.ErrIs(t.Error, err, ErrUnknownKey)
+		assert.Equal(t.Error, fetches, 3)
+	})
+
+	t.Run("FetchFailedServesStale", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		sut.fetchedAt = time.Now().Add(-2 * time.Hour)
+
+		_, err := sut.Key("kid2")
+
+		assert.Nil(t.Error, err)
+		assert.Equal(t.Error, fetches, 4)
+	})
+
+	t.Run("FetchFailed", func(t *testing.T) {
+		sut.fetchedAt = time.Now().Add(-2 * time.Hour)
+
+		_, err := sut.Key("kid1")
+
+		assert.Equal(t.Error, err.Error(), "unexpected jwks status: 500")
+		assert.Equal(t.Error, fetches, 5)
+	})
+}
+
+func TestRemoteKeySetConcurrent(t *testing.T) {
+	key1 := newTestKey(t, "kid1", 1)
+	key2 := newTestKey(t, "kid2", 2)
+
+	var fetches atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// the first fetch primes the cache and the next one blocks until
+			// released
+			if fetches.Add(1) == 1 {
+				_ = json.NewEncoder(w).Encode(NewJWKS(key1))
+				return
+			}
+			close(started)
+			<-release
+			_ = json.NewEncoder(w).Encode(NewJWKS(key2, key1))
+		},
+	))
+	defer srv.Close()
+
+	sut := NewRemoteKeySet(srv.URL, time.Hour)
+	_, err := sut.Key("kid1")
+	assert.Nil(t.Fatal, err)
+	sut.fetchedAt = time.Now().Add(-2 * minRefetch)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = sut.Key("kid2")
+		}(i)
+	}
+	<-started
+
+	t.Run("CachedKeyNotBlockedByFetch", func(t *testing.T) {
+		done := make(chan error, 1)
+		go func() {
+			_, err := sut.Key("kid1")
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			assert.Nil(t.Error, err)
+		case <-time.After(time.Second):
+			t.Error("lookup of a cached key waited for the fetch")
+		}
+	})
+
+	close(release)
+	wg.Wait()
+
+	t.Run("FetchShared", func(t *testing.T) {
+		for _, err := range errs {
+			assert.Nil(t.Error, err)
+		}
+		assert.Equal(t.Error, fetches.Load(), int32(2))
+	})
+}
@@ -0,0 +1,85 @@
+package cookie
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// MFAName is the name of the MFA token.
+const MFAName = "mfa-token"
+
+// MFA defines the body of an MFA token, which is issued to a user who entered
+// the correct password but must still complete two-factor authentication to
+// be issued an auth token.
+type MFA struct{ Username string }
+
+// NewMFA creates and returns a new MFA.
+func NewMFA(username string) MFA { return MFA{Username: username} }
+
+// MFAEncoder defines a type that can be used to encode an MFA token.
+type MFAEncoder struct {
+	key []byte
+	dur time.Duration
+}
+
+// NewMFAEncoder creates and returns a new MFAEncoder.
+func NewMFAEncoder(key []byte, dur time.Duration) MFAEncoder {
+	return MFAEncoder{key: key, dur: dur}
+}
+
+// Encode encodes an MFA into a JWT string.
+func (e MFAEncoder) Encode(m MFA) (http.Cookie, error) {
+	exp := time.Now().Add(e.dur)
+
+	tk, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"mfaUsername": m.Username,
+		"exp":         exp.Unix(),
+	}).SignedString(e.key)
+	if err != nil {
+		return http.Cookie{}, err
+	}
+
+	return http.Cookie{
+		Name:     MFAName,
+		Value:    tk,
+		Expires:  exp.UTC(),
+		SameSite: http.SameSiteNoneMode,
+		Secure:   true,
+		HttpOnly: true,
+	}, nil
+}
+
+// MFADecoder defines a type that can be used to decode an MFA token.
+type MFADecoder struct{ key []byte }
+
+// NewMFADecoder creates and returns a new MFADecoder.
+func NewMFADecoder(key []byte) MFADecoder { return MFADecoder{key: key} }
+
+// Decode validates and decodes a raw JWT string into an MFA.
+func (d MFADecoder) Decode(ck http.Cookie) (MFA, error) {
+	if ck.Value == "" {
+		return MFA{}, ErrInvalid
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+	).ParseWithClaims(
+		ck.Value, &claims, func(token *jwt.Token) (any, error) {
+			return d.key, nil
+		},
+	); err != nil {
+		return MFA{}, err
+	}
+
+	// the claim is named differently to the auth token's username so that
+	// other tokens signed with the same key can't be used as MFA tokens
+	username, ok := claims["mfaUsername"].(string)
+	if !ok || username == "" {
+		return MFA{}, ErrInvalid
+	}
+
+	return NewMFA(username), nil
+}
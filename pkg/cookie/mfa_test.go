@@ -0,0 +1,128 @@
+//go:build utest
+
+package cookie
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestMFA(t *testing.T) {
+	key := []byte("signkey")
+	username := "bob123"
+
+	t.Run("Encode", func(t *testing.T) {
+		sut := NewMFAEncoder(key, 5*time.Minute)
+
+		ck, err := sut.Encode(NewMFA(username))
+		assert.Nil(t.Fatal, err)
+
+		assert.Nil(t.Fatal, ck.Valid())
+		assert.Equal(t.Error, ck.Name, MFAName)
+		assert.Equal(t.Error, ck.SameSite, http.SameSiteNoneMode)
+		assert.True(t.Error, ck.Secure)
+		assert.True(t.Error, ck.HttpOnly)
+		assert.True(t.Error,
+			ck.Expires.UTC().After(time.Now().Add(4*time.Minute).UTC()))
+		assert.True(t.Error,
+			ck.Expires.UTC().Before(time.Now().Add(6*time.Minute).UTC()))
+
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(
+			ck.Value, &claims, func(token *jwt.Token) (any, error) {
+				return key, nil
+			},
+		)
+		assert.Nil(t.Fatal, err)
+
+		assert.Equal(t.Error, claims["mfaUsername"].(string), username)
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		sut := NewMFADecoder(key)
+
+		sign := func(signKey []byte, claims jwt.MapClaims) string {
+			tk, err := jwt.NewWithClaims(
+				jwt.SigningMethodHS256, claims,
+			).SignedString(signKey)
+			assert.Nil(t.Fatal, err)
+			return tk
+		}
+		// signHS512 signs with the right key but a method that isn't accepted
+		signHS512 := func(claims jwt.MapClaims) string {
+			tk, err := jwt.NewWithClaims(
+				jwt.SigningMethodHS512, claims,
+			).SignedString(key)
+			assert.Nil(t.Fatal, err)
+			return tk
+		}
+		exp := time.Now().Add(time.Hour).Unix()
+
+		for _, c := range []struct {
+			name         string
+			token        string
+			wantUsername string
+			wantErr      error
+		}{
+			{
+				name:         "Empty",
+				token:        "",
+				wantUsername: "",
+				wantErr:      ErrInvalid,
+			},
+			{
+				name: "InvalidSignature",
+				token: sign([]byte("otherkey"), jwt.MapClaims{
+					"mfaUsername": username, "exp": exp,
+				}),
+				wantUsername: "",
+				wantErr:      jwt.ErrSignatureInvalid,
+			},
+			{
+				name: "InvalidMethod",
+				token: signHS512(jwt.MapClaims{
+					"mfaUsername": username, "exp": exp,
+				}),
+				wantUsername: "",
+				wantErr:      jwt.ErrTokenSignatureInvalid,
+			},
+			{
+				name: "Expired",
+				token: sign(key, jwt.MapClaims{
+					"mfaUsername": username,
+					"exp":         time.Now().Add(-time.Hour).Unix(),
+				}),
+				wantUsername: "",
+				wantErr:      jwt.ErrTokenExpired,
+			},
+			{
+				name: "NoUsername",
+				token: sign(key, jwt.MapClaims{
+					"username": username, "exp": exp,
+				}),
+				wantUsername: "",
+				wantErr:      ErrInvalid,
+			},
+			{
+				name: "Success",
+				token: sign(key, jwt.MapClaims{
+					"mfaUsername": username, "exp": exp,
+				}),
+				wantUsername: username,
+				wantErr:      nil,
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				m, err := sut.Decode(http.Cookie{Value: c.token})
+
+				assert.ErrIs(t.Error, err, c.wantErr)
+				assert.Equal(t.Error, m.Username, c.wantUsername)
+			})
+		}
+	})
+}
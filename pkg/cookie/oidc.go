@@ -0,0 +1,111 @@
+package cookie
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// OIDCName is the name of the OIDC token.
+const OIDCName = "oidc-token"
+
+// OIDC defines the body of an OIDC token, which holds the values generated at
+// the start of an OpenID Connect login that must be checked when the identity
+// provider redirects the user back. Username is only set when the login was
+// started by a logged-in user to link the identity provider account to their
+// user, and is empty otherwise.
+type OIDC struct {
+	State    string
+	Nonce    string
+	Verifier string
+	Username string
+}
+
+// NewOIDC creates and returns a new OIDC.
+func NewOIDC(state, nonce, verifier string) OIDC {
+	return OIDC{State: state, Nonce: nonce, Verifier: verifier}
+}
+
+// OIDCEncoder defines a type that can be used to encode an OIDC token.
+type OIDCEncoder struct {
+	key []byte
+	dur time.Duration
+}
+
+// NewOIDCEncoder creates and returns a new OIDCEncoder.
+func NewOIDCEncoder(key []byte, dur time.Duration) OIDCEncoder {
+	return OIDCEncoder{key: key, dur: dur}
+}
+
+// Encode encodes an OIDC into a JWT string.
+func (e OIDCEncoder) Encode(o OIDC) (http.Cookie, error) {
+	exp := time.Now().Add(e.dur)
+
+	tk, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"state":    o.State,
+		"nonce":    o.Nonce,
+		"verifier": o.Verifier,
+		"username": o.Username,
+		"exp":      exp.Unix(),
+	}).SignedString(e.key)
+	if err != nil {
+		return http.Cookie{}, err
+	}
+
+	return http.Cookie{
+		Name:     OIDCName,
+		Value:    tk,
+		Expires:  exp.UTC(),
+		SameSite: http.SameSiteNoneMode,
+		Secure:   true,
+		HttpOnly: true,
+	}, nil
+}
+
+// OIDCDecoder defines a type that can be used to decode an OIDC token.
+type OIDCDecoder struct{ key []byte }
+
+// NewOIDCDecoder creates and returns a new OIDCDecoder.
+func NewOIDCDecoder(key []byte) OIDCDecoder {
+	return OIDCDecoder{key: key}
+}
+
+// Decode validates and decodes a raw JWT string into an OIDC.
+func (d OIDCDecoder) Decode(ck http.Cookie) (OIDC, error) {
+	if ck.Value == "" {
+		return OIDC{}, ErrInvalid
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+	).ParseWithClaims(
+		ck.Value, &claims, func(token *jwt.Token) (any, error) {
+			return d.key, nil
+		},
+	); err != nil {
+		return OIDC{}, err
+	}
+
+	state, ok := claims["state"].(string)
+	if !ok {
+		return OIDC{}, ErrInvalid
+	}
+
+	nonce, ok := claims["nonce"].(string)
+	if !ok {
+		return OIDC{}, ErrInvalid
+	}
+
+	verifier, ok := claims["verifier"].(string)
+	if !ok {
+		return OIDC{}, ErrInvalid
+	}
+
+	// the username is only set for logins that link an identity
+	o := NewOIDC(state, nonce, verifier)
+	o.Username, _ = claims["username"].(string)
+
+	return o, nil
+}
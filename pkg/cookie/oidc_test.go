@@ -0,0 +1,185 @@
+//go:build utest
+
+package cookie
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestOIDC(t *testing.T) {
+	key := []byte("signkey")
+	state := "c3RhdGU"
+	nonce := "bm9uY2U"
+	verifier := "dmVyaWZpZXI"
+
+	t.Run("Encode", func(t *testing.T) {
+		sut := NewOIDCEncoder(key, 10*time.Minute)
+
+		o := NewOIDC(state, nonce, verifier)
+		o.Username = "bob123"
+		ck, err := sut.Encode(o)
+		assert.Nil(t.Fatal, err)
+
+		assert.Nil(t.Fatal, ck.Valid())
+		assert.Equal(t.Error, ck.Name, OIDCName)
+		assert.Equal(t.Error, ck.SameSite, http.SameSiteNoneMode)
+		assert.True(t.Error, ck.Secure)
+		assert.True(t.Error, ck.HttpOnly)
+		assert.True(t.Error,
+			ck.Expires.UTC().After(time.Now().Add(9*time.Minute).UTC()))
+		assert.True(t.Error,
+			ck.Expires.UTC().Before(time.Now().Add(11*time.Minute).UTC()))
+
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(
+			ck.Value, &claims, func(token *jwt.Token) (any, error) {
+				return key, nil
+			},
+		)
+		assert.Nil(t.Fatal, err)
+
+		assert.Equal(t.Error, claims["state"].(string), state)
+		assert.Equal(t.Error, claims["nonce"].(string), nonce)
+		assert.Equal(t.Error, claims["verifier"].(string), verifier)
+		assert.Equal(t.Error, claims["username"].(string), "bob123")
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		sut := NewOIDCDecoder(key)
+
+		sign := func(signKey []byte, claims jwt.MapClaims) string {
+			tk, err := jwt.NewWithClaims(
+				jwt.SigningMethodHS256, claims,
+			).SignedString(signKey)
+			assert.Nil(t.Fatal, err)
+			return tk
+		}
+		// signHS512 signs with the right key but a method that isn't accepted
+		signHS512 := func(claims jwt.MapClaims) string {
+			tk, err := jwt.NewWithClaims(
+				jwt.SigningMethodHS512, claims,
+			).SignedString(key)
+			assert.Nil(t.Fatal, err)
+			return tk
+		}
+		exp := time.Now().Add(time.Hour).Unix()
+
+		for _, c := range []struct {
+			name         string
+			token        string
+			wantState    string
+			wantNonce    string
+			wantVerifier string
+			wantUsername string
+			wantErr      error
+		}{
+			{
+				name:         "Empty",
+				token:        "",
+				wantState:    "",
+				wantNonce:    "",
+				wantVerifier: "",
+				wantUsername: "",
+				wantErr:      ErrInvalid,
+			},
+			{
+				name: "InvalidSignature",
+				token: sign([]byte("otherkey"), jwt.MapClaims{
+					"state":    state,
+					"nonce":    nonce,
+					"verifier": verifier,
+					"exp":      exp,
+				}),
+				wantState:    "",
+				wantNonce:    "",
+				wantVerifier: "",
+				wantUsername: "",
+				wantErr:      jwt.ErrSignatureInvalid,
+			},
+			{
+				name: "InvalidMethod",
+				token: signHS512(jwt.MapClaims{
+					"state":    state,
+					"nonce":    nonce,
+					"verifier": verifier,
+					"exp":      exp,
+				}),
+				wantState:    "",
+				wantNonce:    "",
+				wantVerifier: "",
+				wantUsername: "",
+				wantErr:      jwt.ErrTokenSignatureInvalid,
+			},
+			{
+				name: "Expired",
+				token: sign(key, jwt.MapClaims{
+					"state":    state,
+					"nonce":    nonce,
+					"verifier": verifier,
+					"exp":      time.Now().Add(-time.Hour).Unix(),
+				}),
+				wantState:    "",
+				wantNonce:    "",
+				wantVerifier: "",
+				wantUsername: "",
+				wantErr:      jwt.ErrTokenExpired,
+			},
+			{
+				name: "NoVerifier",
+				token: sign(key, jwt.MapClaims{
+					"state": state, "nonce": nonce, "exp": exp,
+				}),
+				wantState:    "",
+				wantNonce:    "",
+				wantVerifier: "",
+				wantUsername: "",
+				wantErr:      ErrInvalid,
+			},
+			{
+				name: "Success",
+				token: sign(key, jwt.MapClaims{
+					"state":    state,
+					"nonce":    nonce,
+					"verifier": verifier,
+					"exp":      exp,
+				}),
+				wantState:    state,
+				wantNonce:    nonce,
+				wantVerifier: verifier,
+				wantUsername: "",
+				wantErr:      nil,
+			},
+			{
+				name: "SuccessLink",
+				token: sign(key, jwt.MapClaims{
+					"state":    state,
+					"nonce":    nonce,
+					"verifier": verifier,
+					"username": "bob123",
+					"exp":      exp,
+				}),
+				wantState:    state,
+				wantNonce:    nonce,
+				wantVerifier: verifier,
+				wantUsername: "bob123",
+				wantErr:      nil,
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				o, err := sut.Decode(http.Cookie{Value: c.token})
+
+				assert.ErrIs(t.Error, err, c.wantErr)
+				assert.Equal(t.Error, o.State, c.wantState)
+				assert.Equal(t.Error, o.Nonce, c.wantNonce)
+				assert.Equal(t.Error, o.Verifier, c.wantVerifier)
+				assert.Equal(t.Error, o.Username, c.wantUsername)
+			})
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package cookie
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// RefreshName is the name of the refresh token.
+const RefreshName = "refresh-token"
+
+// Refresh defines the body of a Refresh token. FamilyID identifies the session
+// the token belongs to and stays the same across rotations, whereas TokenID is
+// changed every time the token is rotated.
+type Refresh struct {
+	FamilyID string
+	TokenID  string
+}
+
+// NewRefresh creates and returns a new Refresh.
+func NewRefresh(familyID string, tokenID string) Refresh {
+	return Refresh{FamilyID: familyID, TokenID: tokenID}
+}
+
+// RefreshEncoder defines a type that can be used to encode a refresh token.
+type RefreshEncoder struct {
+	key []byte
+	dur time.Duration
+}
+
+// NewRefreshEncoder creates and returns a new RefreshEncoder.
+func NewRefreshEncoder(key []byte, dur time.Duration) RefreshEncoder {
+	return RefreshEncoder{key: key, dur: dur}
+}
+
+// Encode encodes a Refresh into a JWT string.
+func (e RefreshEncoder) Encode(ref Refresh) (http.Cookie, error) {
+	exp := time.Now().Add(e.dur)
+
+	tk, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"familyID": ref.FamilyID,
+		"tokenID":  ref.TokenID,
+		"exp":      exp.Unix(),
+	}).SignedString(e.key)
+	if err != nil {
+		return http.Cookie{}, err
+	}
+
+	return http.Cookie{
+		Name:     RefreshName,
+		Value:    tk,
+		Expires:  exp.UTC(),
+		SameSite: http.SameSiteNoneMode,
+		Secure:   true,
+		HttpOnly: true,
+	}, nil
+}
+
+// RefreshDecoder defines a type that can be used to decode a refresh token.
+type RefreshDecoder struct{ key []byte }
+
+// NewRefreshDecoder creates and returns a new RefreshDecoder.
+func NewRefreshDecoder(key []byte) RefreshDecoder {
+	return RefreshDecoder{key: key}
+}
+
+// Decode validates and decodes a raw JWT string into a Refresh.
+func (d RefreshDecoder) Decode(ck http.Cookie) (Refresh, error) {
+	if ck.Value == "" {
+		return Refresh{}, ErrInvalid
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+	).ParseWithClaims(
+		ck.Value, &claims, func(token *jwt.Token) (any, error) {
+			return d.key, nil
+		},
+	); err != nil {
+		return Refresh{}, err
+	}
+
+	familyID, ok := claims["familyID"].(string)
+	if !ok {
+		return Refresh{}, ErrInvalid
+	}
+
+	tokenID, ok := claims["tokenID"].(string)
+	if !ok {
+		return Refresh{}, ErrInvalid
+	}
+
+	return NewRefresh(familyID, tokenID), nil
+}
@@ -0,0 +1,140 @@
+//go:build utest
+
+package cookie
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestRefresh(t *testing.T) {
+	key := []byte("signkey")
+	familyID := "c57e5a34-ea70-4a33-9d8d-6c8b1b1e38b1"
+	tokenID := "f7e1b1a8-9a2e-4e6b-a36a-1b5a8c0b9f4e"
+
+	t.Run("Encode", func(t *testing.T) {
+		dur := 24 * time.Hour
+		sut := NewRefreshEncoder(key, dur)
+
+		ck, err := sut.Encode(NewRefresh(familyID, tokenID))
+		assert.Nil(t.Fatal, err)
+
+		assert.Nil(t.Fatal, ck.Valid())
+		assert.Equal(t.Error, ck.Name, RefreshName)
+		assert.Equal(t.Error, ck.SameSite, http.SameSiteNoneMode)
+		assert.True(t.Error, ck.Secure)
+		assert.True(t.Error, ck.HttpOnly)
+		assert.True(t.Error,
+			ck.Expires.UTC().After(time.Now().Add(23*time.Hour).UTC()))
+		assert.True(t.Error,
+			ck.Expires.UTC().Before(time.Now().Add(25*time.Hour).UTC()))
+
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(
+			ck.Value, &claims, func(token *jwt.Token) (any, error) {
+				return key, nil
+			},
+		)
+		assert.Nil(t.Fatal, err)
+
+		assert.Equal(t.Error, claims["familyID"].(string), familyID)
+		assert.Equal(t.Error, claims["tokenID"].(string), tokenID)
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		sut := NewRefreshDecoder(key)
+
+		sign := func(signKey []byte, claims jwt.MapClaims) string {
+			tk, err := jwt.NewWithClaims(
+				jwt.SigningMethodHS256, claims,
+			).SignedString(signKey)
+			assert.Nil(t.Fatal, err)
+			return tk
+		}
+		// signHS512 signs with the right key but a method that isn't accepted
+		signHS512 := func(claims jwt.MapClaims) string {
+			tk, err := jwt.NewWithClaims(
+				jwt.SigningMethodHS512, claims,
+			).SignedString(key)
+			assert.Nil(t.Fatal, err)
+			return tk
+		}
+		exp := time.Now().Add(time.Hour).Unix()
+
+		for _, c := range []struct {
+			name         string
+			token        string
+			wantFamilyID string
+			wantTokenID  string
+			wantErr      error
+		}{
+			{
+				name:         "Empty",
+				token:        "",
+				wantFamilyID: "",
+				wantTokenID:  "",
+				wantErr:      ErrInvalid,
+			},
+			{
+				name: "InvalidSignature",
+				token: sign([]byte("otherkey"), jwt.MapClaims{
+					"familyID": familyID, "tokenID": tokenID, "exp": exp,
+				}),
+				wantFamilyID: "",
+				wantTokenID:  "",
+				wantErr:      jwt.ErrSignatureInvalid,
+			},
+			{
+				name: "InvalidMethod",
+				token: signHS512(jwt.MapClaims{
+					"familyID": familyID, "tokenID": tokenID, "exp": exp,
+				}),
+				wantFamilyID: "",
+				wantTokenID:  "",
+				wantErr:      jwt.ErrTokenSignatureInvalid,
+			},
+			{
+				name: "Expired",
+				token: sign(key, jwt.MapClaims{
+					"familyID": familyID,
+					"tokenID":  tokenID,
+					"exp":      time.Now().Add(-time.Hour).Unix(),
+				}),
+				wantFamilyID: "",
+				wantTokenID:  "",
+				wantErr:      jwt.ErrTokenExpired,
+			},
+			{
+				name: "NoTokenID",
+				token: sign(key, jwt.MapClaims{
+					"familyID": familyID, "exp": exp,
+				}),
+				wantFamilyID: "",
+				wantTokenID:  "",
+				wantErr:      ErrInvalid,
+			},
+			{
+				name: "Success",
+				token: sign(key, jwt.MapClaims{
+					"familyID": familyID, "tokenID": tokenID, "exp": exp,
+				}),
+				wantFamilyID: familyID,
+				wantTokenID:  tokenID,
+				wantErr:      nil,
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				ref, err := sut.Decode(http.Cookie{Value: c.token})
+
+				assert.ErrIs(t.Error, err, c.wantErr)
+				assert.Equal(t.Error, ref.FamilyID, c.wantFamilyID)
+				assert.Equal(t.Error, ref.TokenID, c.wantTokenID)
+			})
+		}
+	})
+}
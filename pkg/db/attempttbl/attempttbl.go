@@ -0,0 +1,36 @@
+// Package attempttbl contains code to interact with the login attempt table in
+// DynamoDB.
+package attempttbl
+
+import (
+	"strings"
+	"time"
+)
+
+// tableName is the name of the environment variable to retrieve the login
+// attempt table's name from.
+const tableName = "ATTEMPT_TABLE_NAME"
+
+// ttl is how long a login attempt counter is kept after its last failure.
+const ttl = 24 * time.Hour
+
+// Attempt defines the login attempt entity. Each attempt counts the failed
+// login attempts made for a username or from a client IP since the counter was
+// last reset or expired.
+type Attempt struct {
+	ID           string
+	Failures     int
+	LastFailedAt int64
+	ExpiresAt    int64
+}
+
+// UserID returns the ID of the Attempt that counts the failures for the given
+// username. Usernames are case-insensitive, so the failures for all casings of
+// a username are counted together.
+func UserID(username string) string {
+	return "user#" + strings.ToLower(username)
+}
+
+// IPID returns the ID of the Attempt that counts the failures from the given
+// client IP.
+func IPID(ip string) string { return "ip#" + ip }
@@ -0,0 +1,34 @@
+package attempttbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Deleter can be used to delete by ID a login attempt counter from the attempt
+// table.
+type Deleter struct{ idel db.DynamoItemDeleter }
+
+// NewDeleter creates and returns a new Deleter.
+func NewDeleter(idel db.DynamoItemDeleter) Deleter {
+	return Deleter{idel: idel}
+}
+
+// Delete deletes by ID a login attempt counter from the attempt table.
+// Deleting a counter that doesn't exist is not an error since the end result
+// is the same.
+func (d Deleter) Delete(ctx context.Context, id string) error {
+	_, err := d.idel.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	return err
+}
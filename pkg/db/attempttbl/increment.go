@@ -0,0 +1,84 @@
+package attempttbl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Incrementer can be used to record a failed login attempt in the attempt
+// table.
+type Incrementer struct{ iupput db.DynamoItemUpdatePutter }
+
+// NewIncrementer creates and returns a new Incrementer.
+func NewIncrementer(iupput db.DynamoItemUpdatePutter) Incrementer {
+	return Incrementer{iupput: iupput}
+}
+
+// Increment atomically increments the failures of the login attempt counter
+// with the given ID and returns the counter's new state. If the counter has
+// expired but not been deleted yet, it is started over instead.
+func (i Incrementer) Increment(
+	ctx context.Context, id string,
+) (Attempt, error) {
+	now := time.Now()
+	nowStr := strconv.FormatInt(now.Unix(), 10)
+	expStr := strconv.FormatInt(now.Add(ttl).Unix(), 10)
+
+	out, err := i.iupput.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression: aws.String(
+			"ADD Failures :one SET LastFailedAt = :now, ExpiresAt = :exp",
+		),
+		ConditionExpression: aws.String(
+			"attribute_not_exists(ID) OR ExpiresAt > :now",
+		),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{Value: "1"},
+			":now": &types.AttributeValueMemberN{Value: nowStr},
+			":exp": &types.AttributeValueMemberN{Value: expStr},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		attempt := Attempt{
+			ID:           id,
+			Failures:     1,
+			LastFailedAt: now.Unix(),
+			ExpiresAt:    now.Add(ttl).Unix(),
+		}
+		item, err := attributevalue.MarshalMap(attempt)
+		if err != nil {
+			return Attempt{}, err
+		}
+		if _, err = i.iupput.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(os.Getenv(tableName)),
+			Item:      item,
+		}); err != nil {
+			return Attempt{}, err
+		}
+		return attempt, nil
+	}
+	if err != nil {
+		return Attempt{}, err
+	}
+
+	var attempt Attempt
+	if err = attributevalue.UnmarshalMap(out.Attributes, &attempt); err != nil {
+		return Attempt{}, err
+	}
+	return attempt, nil
+}
@@ -0,0 +1,85 @@
+//go:build utest
+
+package attempttbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestIncrementer(t *testing.T) {
+	iup := &db.FakeDynamoItemUpdatePutter{}
+	sut := NewIncrementer(iup)
+
+	errUpdate := errors.New("failed to update item")
+	errPut := errors.New("failed to put item")
+	errCondFail := &smithy.OperationError{
+		Err: &types.ConditionalCheckFailedException{},
+	}
+
+	for _, c := range []struct {
+		name         string
+		outUpdate    *dynamodb.UpdateItemOutput
+		errUpdate    error
+		errPut       error
+		wantFailures int
+		wantErr      error
+	}{
+		{
+			name:         "ErrUpdate",
+			outUpdate:    nil,
+			errUpdate:    errUpdate,
+			errPut:       nil,
+			wantFailures: 0,
+			wantErr:      errUpdate,
+		},
+		{
+			name:         "ExpiredErrPut",
+			outUpdate:    nil,
+			errUpdate:    errCondFail,
+			errPut:       errPut,
+			wantFailures: 0,
+			wantErr:      errPut,
+		},
+		{
+			name:         "Expired",
+			outUpdate:    nil,
+			errUpdate:    errCondFail,
+			errPut:       nil,
+			wantFailures: 1,
+			wantErr:      nil,
+		},
+		{
+			name: "OK",
+			outUpdate: &dynamodb.UpdateItemOutput{
+				Attributes: map[string]types.AttributeValue{
+					"ID":       &types.AttributeValueMemberS{Value: "ip#1"},
+					"Failures": &types.AttributeValueMemberN{Value: "4"},
+				},
+			},
+			errUpdate:    nil,
+			errPut:       nil,
+			wantFailures: 4,
+			wantErr:      nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			iup.OutUpdate = c.outUpdate
+			iup.ErrUpdate = c.errUpdate
+			iup.ErrPut = c.errPut
+
+			attempt, err := sut.Increment(context.Background(), "ip#1")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, attempt.Failures, c.wantFailures)
+		})
+	}
+}
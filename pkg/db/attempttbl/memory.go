@@ -0,0 +1,62 @@
+package attempttbl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Memory is an in-memory alternative to the attempt table. It can be used in
+// place of Retriever, Incrementer, and Deleter when running a single instance
+// of a service (e.g. locally or in tests).
+type Memory struct {
+	mu       sync.Mutex
+	attempts map[string]Attempt
+}
+
+// NewMemory creates and returns a new Memory.
+func NewMemory() *Memory {
+	return &Memory{attempts: map[string]Attempt{}}
+}
+
+// Retrieve retrieves by ID a login attempt counter.
+func (m *Memory) Retrieve(_ context.Context, id string) (Attempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[id]
+	if !ok || attempt.ExpiresAt <= time.Now().Unix() {
+		return Attempt{}, db.ErrNoItem
+	}
+	return attempt, nil
+}
+
+// Increment increments the failures of the login attempt counter with the
+// given ID and returns the counter's new state. Expired counters are started
+// over.
+func (m *Memory) Increment(_ context.Context, id string) (Attempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	attempt, ok := m.attempts[id]
+	if !ok || attempt.ExpiresAt <= now.Unix() {
+		attempt = Attempt{ID: id}
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = now.Unix()
+	attempt.ExpiresAt = now.Add(ttl).Unix()
+	m.attempts[id] = attempt
+	return attempt, nil
+}
+
+// Delete deletes by ID a login attempt counter.
+func (m *Memory) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, id)
+	return nil
+}
@@ -0,0 +1,48 @@
+//go:build utest
+
+package attempttbl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	sut := NewMemory()
+
+	_, err := sut.Retrieve(ctx, "user#bob123")
+	assert.ErrIs(t.Error, err, db.ErrNoItem)
+
+	for i := 1; i <= 3; i++ {
+		attempt, err := sut.Increment(ctx, "user#bob123")
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, attempt.Failures, i)
+	}
+
+	attempt, err := sut.Retrieve(ctx, "user#bob123")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, attempt.Failures, 3)
+
+	_, err = sut.Retrieve(ctx, "ip#127.0.0.1")
+	assert.ErrIs(t.Error, err, db.ErrNoItem)
+
+	// expired counters are not found and are started over on increment
+	sut.attempts["user#bob123"] = Attempt{
+		ID: "user#bob123", Failures: 9, ExpiresAt: time.Now().Unix() - 1,
+	}
+	_, err = sut.Retrieve(ctx, "user#bob123")
+	assert.ErrIs(t.Error, err, db.ErrNoItem)
+	attempt, err = sut.Increment(ctx, "user#bob123")
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, attempt.Failures, 1)
+
+	err = sut.Delete(ctx, "user#bob123")
+	assert.Nil(t.Fatal, err)
+	_, err = sut.Retrieve(ctx, "user#bob123")
+	assert.ErrIs(t.Error, err, db.ErrNoItem)
+}
@@ -0,0 +1,50 @@
+package attempttbl
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve by ID a login attempt counter from the
+// attempt table.
+type Retriever struct{ iget db.DynamoItemGetter }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(iget db.DynamoItemGetter) Retriever {
+	return Retriever{iget: iget}
+}
+
+// Retrieve retrieves by ID a login attempt counter from the attempt table.
+// Expired counters are treated as not found since DynamoDB doesn't delete them
+// as soon as their TTL passes.
+func (r Retriever) Retrieve(ctx context.Context, id string) (Attempt, error) {
+	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return Attempt{}, err
+	}
+	if out.Item == nil {
+		return Attempt{}, db.ErrNoItem
+	}
+
+	var attempt Attempt
+	if err = attributevalue.UnmarshalMap(out.Item, &attempt); err != nil {
+		return Attempt{}, err
+	}
+	if attempt.ExpiresAt <= time.Now().Unix() {
+		return Attempt{}, db.ErrNoItem
+	}
+	return attempt, nil
+}
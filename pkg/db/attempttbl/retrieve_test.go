@@ -0,0 +1,94 @@
+//go:build utest
+
+package attempttbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	now := time.Now().Unix()
+	attemptA := Attempt{
+		ID:           "user#bob123",
+		Failures:     3,
+		LastFailedAt: now,
+		ExpiresAt:    now + 60,
+	}
+	errA := errors.New("failed to get item")
+	item := func(a Attempt) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: a.ID},
+			"Failures": &types.AttributeValueMemberN{
+				Value: strconv.Itoa(a.Failures),
+			},
+			"LastFailedAt": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(a.LastFailedAt, 10),
+			},
+			"ExpiresAt": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(a.ExpiresAt, 10),
+			},
+		}
+	}
+
+	for _, c := range []struct {
+		name        string
+		igOut       *dynamodb.GetItemOutput
+		igErr       error
+		wantAttempt Attempt
+		wantErr     error
+	}{
+		{
+			name:        "Err",
+			igOut:       nil,
+			igErr:       errA,
+			wantAttempt: Attempt{},
+			wantErr:     errA,
+		},
+		{
+			name:        "NoItem",
+			igOut:       &dynamodb.GetItemOutput{Item: nil},
+			igErr:       nil,
+			wantAttempt: Attempt{},
+			wantErr:     db.ErrNoItem,
+		},
+		{
+			name: "Expired",
+			igOut: &dynamodb.GetItemOutput{Item: item(Attempt{
+				ID: "user#bob123", Failures: 3, ExpiresAt: now - 1,
+			})},
+			igErr:       nil,
+			wantAttempt: Attempt{},
+			wantErr:     db.ErrNoItem,
+		},
+		{
+			name:        "OK",
+			igOut:       &dynamodb.GetItemOutput{Item: item(attemptA)},
+			igErr:       nil,
+			wantAttempt: attemptA,
+			wantErr:     nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			attempt, err := sut.Retrieve(context.Background(), "")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, attempt, c.wantAttempt)
+		})
+	}
+}
@@ -39,6 +39,42 @@ type Deleter interface {
 	Delete(context.Context, string) error
 }
 
+// Consumer defines a type that can retrieve and delete an item from a DynamoDB
+// table in a single operation.
+type Consumer[T any] interface {
+	Consume(context.Context, string) (T, error)
+}
+
+// Incrementer defines a type that can atomically increment a counter item in a
+// DynamoDB table and return its new state.
+type Incrementer[T any] interface {
+	Increment(context.Context, string) (T, error)
+}
+
+// Releaser defines a type that can give back a use of an item that was
+// consumed from a DynamoDB table without deleting it.
+type Releaser interface {
+	Release(context.Context, string) error
+}
+
+// Transferer defines a type that can transfer the ownership of an item in a
+// DynamoDB table from one user to another.
+type Transferer interface {
+	Transfer(ctx context.Context, id, from, to string) error
+}
+
+// RetrieverBatch defines a type that can retrieve multiple items from a
+// DynamoDB table by their identifiers in a single operation.
+type RetrieverBatch[T any] interface {
+	Retrieve(context.Context, []string) ([]T, error)
+}
+
+// RetrieverDualKey defines a type that can retrieve an item from a DynamoDB
+// table using two identifiers.
+type RetrieverDualKey[T any] interface {
+	Retrieve(context.Context, string, string) (T, error)
+}
+
 // InserterDualKey defines a type that can insert an item into a DynamoDB table
 // using an additional identifier separate to the T's ID field.
 type InserterDualKey[T any] interface {
@@ -51,12 +87,25 @@ type UpdaterDualKey[T any] interface {
 	Update(context.Context, string, T) error
 }
 
+// ConsumerDualKey defines a type that can retrieve and use up an item from a
+// DynamoDB table in a single operation using an additional identifier that the
+// item must be consumable by.
+type ConsumerDualKey[T any] interface {
+	Consume(context.Context, string, string) (T, error)
+}
+
 // DeleterDualKey defines a type that can delete an item from a DynamoDB table
 // using two identifiers.
 type DeleterDualKey interface {
 	Delete(context.Context, string, string) error
 }
 
+// ArchiverDualKey defines a type that can archive or restore an item in a
+// DynamoDB table using two identifiers.
+type ArchiverDualKey interface {
+	Archive(ctx context.Context, id1, id2 string, archived bool) error
+}
+
 // DynamoItemGetter defines a type that can be used to get an item from a
 // DynamoDB table. It is used to dependency-inject the DynamoDB client into
 // Retrievers.
@@ -75,6 +124,15 @@ type DynamoQueryer interface {
 	) (*dynamodb.QueryOutput, error)
 }
 
+// DynamoBatchItemGetter defines a type that can be used to get multiple items
+// from a DynamoDB table in a single operation. It is used to dependency-inject
+// the DynamoDB client into RetrieverBatches.
+type DynamoBatchItemGetter interface {
+	BatchGetItem(
+		context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options),
+	) (*dynamodb.BatchGetItemOutput, error)
+}
+
 // DynamoItemPutter defines a type that can be used to put an item into a
 // DynamoDB table. It is used to dependency-inject the DynamoDB client into
 // Inserters and Updaters.
@@ -84,6 +142,27 @@ type DynamoItemPutter interface {
 	) (*dynamodb.PutItemOutput, error)
 }
 
+// DynamoItemUpdater defines a type that can be used to update an item's
+// attributes in a DynamoDB table. It is used to dependency-inject the DynamoDB
+// client into Incrementers and Releasers.
+type DynamoItemUpdater interface {
+	UpdateItem(
+		context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options),
+	) (*dynamodb.UpdateItemOutput, error)
+}
+
+// DynamoBatchItemWriter defines a type that can be used to put or delete
+// multiple items in a DynamoDB table in a single operation. It is used to
+// dependency-inject the DynamoDB client into Deleters that delete a collection
+// of items in batches.
+type DynamoBatchItemWriter interface {
+	BatchWriteItem(
+		context.Context,
+		*dynamodb.BatchWriteItemInput,
+		...func(*dynamodb.Options),
+	) (*dynamodb.BatchWriteItemOutput, error)
+}
+
 // DynamoItemDeleter defines a type that can be used to delete an item from a
 // DynamoDB table. It is used to dependency-inject the DynamoDB client into
 // Deleters.
@@ -104,6 +183,15 @@ type DynamoTransactWriter interface {
 	) (*dynamodb.TransactWriteItemsOutput, error)
 }
 
+// DynamoTransactWriteUpdater defines a type that can be used to write multiple
+// items to DynamoDB tables in a transaction and to update an item's attributes
+// afterwards. It is used to dependency-inject the DynamoDB client into
+// Transferers that keep denormalised attributes in sync after a transaction.
+type DynamoTransactWriteUpdater interface {
+	DynamoTransactWriter
+	DynamoItemUpdater
+}
+
 // DynamoItemGetter defines a type that can be used to get and put an item
 // from/to a DynamoDB table. It is used to dependency-inject the DynamoDB client
 // into some deleters and putters that operate in an item's internal fields.
@@ -111,3 +199,36 @@ type DynamoItemGetPutter interface {
 	DynamoItemGetter
 	DynamoItemPutter
 }
+
+// DynamoQueryDeleter defines a type that can be used to query and delete items
+// from a DynamoDB table. It is used to dependency-inject the DynamoDB client
+// into deleters that delete a collection of items found by querying an index.
+type DynamoQueryDeleter interface {
+	DynamoQueryer
+	DynamoItemDeleter
+}
+
+// DynamoQueryBatchWriter defines a type that can be used to query and batch
+// write items to a DynamoDB table. It is used to dependency-inject the DynamoDB
+// client into deleters that delete a large collection of items found by
+// querying an index.
+type DynamoQueryBatchWriter interface {
+	DynamoQueryer
+	DynamoBatchItemWriter
+}
+
+// DynamoItemUpdatePutter defines a type that can be used to update and put
+// items in a DynamoDB table. It is used to dependency-inject the DynamoDB client
+// into Incrementers that need to reset a counter instead of incrementing it.
+type DynamoItemUpdatePutter interface {
+	DynamoItemUpdater
+	DynamoItemPutter
+}
+
+// DynamoItemGetQueryer defines a type that can be used to get an item from and
+// query a DynamoDB table. It is used to dependency-inject the DynamoDB client
+// into Retrievers that fall back to querying an index if the item isn't found.
+type DynamoItemGetQueryer interface {
+	DynamoItemGetter
+	DynamoQueryer
+}
@@ -4,8 +4,10 @@ package db
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 // FakeRetriever is a test fake for Retriever.
@@ -20,6 +22,21 @@ func (f *FakeRetriever[T]) Retrieve(context.Context, string) (T, error) {
 	return f.Res, f.Err
 }
 
+// FakeRetrieverByKey is a test fake for Retriever that returns a different
+// result for each key.
+type FakeRetrieverByKey[T any] struct {
+	Res map[string]T
+	Err error
+}
+
+// Retrieve discards the context and returns the value in the Res map for the
+// given key along with the Err field set on FakeRetrieverByKey.
+func (f *FakeRetrieverByKey[T]) Retrieve(
+	_ context.Context, key string,
+) (T, error) {
+	return f.Res[key], f.Err
+}
+
 // FakeInserter is a test fake for Inserter.
 type FakeInserter[T any] struct{ Err error }
 
@@ -38,6 +55,73 @@ type FakeDeleter struct{ Err error }
 // Delete discards params and returns FakeDeleter.Err.
 func (f *FakeDeleter) Delete(context.Context, string) error { return f.Err }
 
+// FakeConsumer is a test fake for Consumer.
+type FakeConsumer[T any] struct {
+	Res T
+	Err error
+}
+
+// Consume discards params and returns FakeConsumer.Res and FakeConsumer.Err.
+func (f *FakeConsumer[T]) Consume(context.Context, string) (T, error) {
+	return f.Res, f.Err
+}
+
+// FakeIncrementer is a test fake for Incrementer.
+type FakeIncrementer[T any] struct {
+	Res T
+	Err error
+}
+
+// Increment discards params and returns FakeIncrementer.Res and
+// FakeIncrementer.Err.
+func (f *FakeIncrementer[T]) Increment(context.Context, string) (T, error) {
+	return f.Res, f.Err
+}
+
+// FakeReleaser is a test fake for Releaser.
+type FakeReleaser struct{ Err error }
+
+// Release discards params and returns FakeReleaser.Err.
+func (f *FakeReleaser) Release(context.Context, string) error { return f.Err }
+
+// FakeTransferer is a test fake for Transferer.
+type FakeTransferer struct{ Err error }
+
+// Transfer discards params and returns FakeTransferer.Err.
+func (f *FakeTransferer) Transfer(
+	context.Context, string, string, string,
+) error {
+	return f.Err
+}
+
+// FakeRetrieverBatch is a test fake for RetrieverBatch.
+type FakeRetrieverBatch[T any] struct {
+	Res []T
+	Err error
+}
+
+// Retrieve discards params and returns FakeRetrieverBatch.Res and
+// FakeRetrieverBatch.Err.
+func (f *FakeRetrieverBatch[T]) Retrieve(
+	context.Context, []string,
+) ([]T, error) {
+	return f.Res, f.Err
+}
+
+// FakeRetrieverDualKey is a test fake for RetrieverDualKey.
+type FakeRetrieverDualKey[T any] struct {
+	Res T
+	Err error
+}
+
+// Retrieve discards params and returns FakeRetrieverDualKey.Res and
+// FakeRetrieverDualKey.Err.
+func (f *FakeRetrieverDualKey[T]) Retrieve(
+	context.Context, string, string,
+) (T, error) {
+	return f.Res, f.Err
+}
+
 // FakeInserterDualKey is a test fake for InserterDualKey.
 type FakeInserterDualKey[T any] struct{ Err error }
 
@@ -54,6 +138,20 @@ func (f *FakeUpdaterDualKey[T]) Update(context.Context, string, T) error {
 	return f.Err
 }
 
+// FakeConsumerDualKey is a test fake for ConsumerDualKey.
+type FakeConsumerDualKey[T any] struct {
+	Res T
+	Err error
+}
+
+// Consume discards params and returns FakeConsumerDualKey.Res and
+// FakeConsumerDualKey.Err.
+func (f *FakeConsumerDualKey[T]) Consume(
+	context.Context, string, string,
+) (T, error) {
+	return f.Res, f.Err
+}
+
 // FakeDeleterDualKey is a test fake for DeleterDualKey.
 type FakeDeleterDualKey struct{ Err error }
 
@@ -62,6 +160,16 @@ func (f *FakeDeleterDualKey) Delete(context.Context, string, string) error {
 	return f.Err
 }
 
+// FakeArchiverDualKey is a test fake for ArchiverDualKey.
+type FakeArchiverDualKey struct{ Err error }
+
+// Archive discards params and returns FakeArchiverDualKey.Err.
+func (f *FakeArchiverDualKey) Archive(
+	context.Context, string, string, bool,
+) error {
+	return f.Err
+}
+
 // FakeDynamoItemGetter is a test fake for DynamoItemGetter.
 type FakeDynamoItemGetter struct {
 	Out *dynamodb.GetItemOutput
@@ -90,6 +198,52 @@ func (f *FakeDynamoQueryer) Query(
 	return f.Out, f.Err
 }
 
+// FakeDynamoPagedQueryer is a test fake for DynamoQueryer that returns the
+// results of a query in Pages, one page per call.
+type FakeDynamoPagedQueryer struct {
+	Pages []*dynamodb.QueryOutput
+	Err   error
+}
+
+// Query returns the page that the ExclusiveStartKey of the input points to, or
+// the first one if it isn't set, with a LastEvaluatedKey that points to the
+// next page if there is one. It returns the Err field set on
+// FakeDynamoPagedQueryer instead if it is set.
+func (f *FakeDynamoPagedQueryer) Query(
+	_ context.Context, in *dynamodb.QueryInput, _ ...func(*dynamodb.Options),
+) (*dynamodb.QueryOutput, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	i := 0
+	k, ok := in.ExclusiveStartKey["Page"].(*types.AttributeValueMemberN)
+	if ok {
+		i, _ = strconv.Atoi(k.Value)
+	}
+	out := *f.Pages[i]
+	if i+1 < len(f.Pages) {
+		out.LastEvaluatedKey = map[string]types.AttributeValue{
+			"Page": &types.AttributeValueMemberN{Value: strconv.Itoa(i + 1)},
+		}
+	}
+	return &out, nil
+}
+
+// FakeDynamoBatchItemGetter is a test fake for DynamoBatchItemGetter.
+type FakeDynamoBatchItemGetter struct {
+	Out *dynamodb.BatchGetItemOutput
+	Err error
+}
+
+// BatchGetItem discards the input parameters and returns Out and Err fields set
+// on FakeDynamoBatchItemGetter.
+func (f *FakeDynamoBatchItemGetter) BatchGetItem(
+	context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.BatchGetItemOutput, error) {
+	return f.Out, f.Err
+}
+
 // FakeDynamoItemPutter is a test fake for DynamoItemPutter.
 type FakeDynamoItemPutter struct {
 	Out *dynamodb.PutItemOutput
@@ -104,6 +258,34 @@ func (f *FakeDynamoItemPutter) PutItem(
 	return f.Out, f.Err
 }
 
+// FakeDynamoItemUpdater is a test fake for DynamoItemUpdater.
+type FakeDynamoItemUpdater struct {
+	Out *dynamodb.UpdateItemOutput
+	Err error
+}
+
+// UpdateItem discards the input parameters and returns Out and Err fields set
+// on FakeDynamoItemUpdater.
+func (f *FakeDynamoItemUpdater) UpdateItem(
+	context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.UpdateItemOutput, error) {
+	return f.Out, f.Err
+}
+
+// FakeDynamoBatchItemWriter is a test fake for DynamoBatchItemWriter.
+type FakeDynamoBatchItemWriter struct {
+	Out *dynamodb.BatchWriteItemOutput
+	Err error
+}
+
+// BatchWriteItem discards the input parameters and returns Out and Err fields
+// set on FakeDynamoBatchItemWriter.
+func (f *FakeDynamoBatchItemWriter) BatchWriteItem(
+	context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.BatchWriteItemOutput, error) {
+	return f.Out, f.Err
+}
+
 // FakeDynamoItemDeleter is a test fake for DynamoItemDeleter.
 type FakeDynamoItemDeleter struct {
 	Out *dynamodb.DeleteItemOutput
@@ -157,3 +339,125 @@ func (f *FakeDynamoItemGetPutter) PutItem(
 ) (*dynamodb.PutItemOutput, error) {
 	return f.OutPut, f.ErrPut
 }
+
+// FakeDynamoQueryDeleter is a test fake for DynamoQueryDeleter.
+type FakeDynamoQueryDeleter struct {
+	OutQuery  *dynamodb.QueryOutput
+	ErrQuery  error
+	OutDelete *dynamodb.DeleteItemOutput
+	ErrDelete error
+}
+
+// Query discards the input parameters and returns OutQuery and ErrQuery fields
+// set on FakeDynamoQueryDeleter.
+func (f *FakeDynamoQueryDeleter) Query(
+	context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options),
+) (*dynamodb.QueryOutput, error) {
+	return f.OutQuery, f.ErrQuery
+}
+
+// DeleteItem discards the input parameters and returns OutDelete and ErrDelete
+// fields set on FakeDynamoQueryDeleter.
+func (f *FakeDynamoQueryDeleter) DeleteItem(
+	context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.DeleteItemOutput, error) {
+	return f.OutDelete, f.ErrDelete
+}
+
+// FakeDynamoQueryBatchWriter is a test fake for DynamoQueryBatchWriter.
+type FakeDynamoQueryBatchWriter struct {
+	OutQuery *dynamodb.QueryOutput
+	ErrQuery error
+	OutWrite *dynamodb.BatchWriteItemOutput
+	ErrWrite error
+}
+
+// Query discards the input parameters and returns OutQuery and ErrQuery fields
+// set on FakeDynamoQueryBatchWriter.
+func (f *FakeDynamoQueryBatchWriter) Query(
+	context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options),
+) (*dynamodb.QueryOutput, error) {
+	return f.OutQuery, f.ErrQuery
+}
+
+// BatchWriteItem discards the input parameters and returns OutWrite and
+// ErrWrite fields set on FakeDynamoQueryBatchWriter.
+func (f *FakeDynamoQueryBatchWriter) BatchWriteItem(
+	context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.BatchWriteItemOutput, error) {
+	return f.OutWrite, f.ErrWrite
+}
+
+// FakeDynamoItemUpdatePutter is a test fake for DynamoItemUpdatePutter.
+type FakeDynamoItemUpdatePutter struct {
+	OutUpdate *dynamodb.UpdateItemOutput
+	ErrUpdate error
+	OutPut    *dynamodb.PutItemOutput
+	ErrPut    error
+}
+
+// UpdateItem discards the input parameters and returns OutUpdate and ErrUpdate
+// fields set on FakeDynamoItemUpdatePutter.
+func (f *FakeDynamoItemUpdatePutter) UpdateItem(
+	context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.UpdateItemOutput, error) {
+	return f.OutUpdate, f.ErrUpdate
+}
+
+// PutItem discards the input parameters and returns OutPut and ErrPut fields
+// set on FakeDynamoItemUpdatePutter.
+func (f *FakeDynamoItemUpdatePutter) PutItem(
+	context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.PutItemOutput, error) {
+	return f.OutPut, f.ErrPut
+}
+
+// FakeDynamoTransactWriteUpdater is a test fake for DynamoTransactWriteUpdater.
+type FakeDynamoTransactWriteUpdater struct {
+	OutTransactWrite *dynamodb.TransactWriteItemsOutput
+	ErrTransactWrite error
+	OutUpdate        *dynamodb.UpdateItemOutput
+	ErrUpdate        error
+}
+
+// TransactWriteItems discards the input parameters and returns OutTransactWrite
+// and ErrTransactWrite fields set on FakeDynamoTransactWriteUpdater.
+func (f *FakeDynamoTransactWriteUpdater) TransactWriteItems(
+	context.Context,
+	*dynamodb.TransactWriteItemsInput,
+	...func(*dynamodb.Options),
+) (*dynamodb.TransactWriteItemsOutput, error) {
+	return f.OutTransactWrite, f.ErrTransactWrite
+}
+
+// UpdateItem discards the input parameters and returns OutUpdate and ErrUpdate
+// fields set on FakeDynamoTransactWriteUpdater.
+func (f *FakeDynamoTransactWriteUpdater) UpdateItem(
+	context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.UpdateItemOutput, error) {
+	return f.OutUpdate, f.ErrUpdate
+}
+
+// FakeDynamoItemGetQueryer is a test fake for DynamoItemGetQueryer.
+type FakeDynamoItemGetQueryer struct {
+	OutGet   *dynamodb.GetItemOutput
+	ErrGet   error
+	OutQuery *dynamodb.QueryOutput
+	ErrQuery error
+}
+
+// GetItem discards the input parameters and returns OutGet and ErrGet fields
+// set on FakeDynamoItemGetQueryer.
+func (f *FakeDynamoItemGetQueryer) GetItem(
+	context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options),
+) (*dynamodb.GetItemOutput, error) {
+	return f.OutGet, f.ErrGet
+}
+
+// Query discards the input parameters and returns OutQuery and ErrQuery fields
+// set on FakeDynamoItemGetQueryer.
+func (f *FakeDynamoItemGetQueryer) Query(
+	context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options),
+) (*dynamodb.QueryOutput, error) {
+	return f.OutQuery, f.ErrQuery
+}
@@ -0,0 +1,40 @@
+package identitytbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Deleter can be used to delete by ID an identity from the identity table.
+type Deleter struct{ idel db.DynamoItemDeleter }
+
+// NewDeleter creates and returns a new Deleter.
+func NewDeleter(idel db.DynamoItemDeleter) Deleter {
+	return Deleter{idel: idel}
+}
+
+// Delete deletes by ID an identity from the identity table. It returns
+// db.ErrNoItem if no identity with the given ID exists.
+func (d Deleter) Delete(ctx context.Context, id string) error {
+	_, err := d.idel.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression: aws.String("attribute_exists(ID)"),
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	}
+
+	return err
+}
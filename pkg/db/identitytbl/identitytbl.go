@@ -0,0 +1,25 @@
+// Package identitytbl contains code to interact with the identity table in
+// DynamoDB.
+package identitytbl
+
+// tableName is the name of the environment variable to retrieve the identity
+// table's name from.
+const tableName = "IDENTITY_TABLE_NAME"
+
+// Identity defines the identity entity, which links an account at an external
+// OpenID Connect identity provider to the user it logs in as.
+type Identity struct {
+	ID       string
+	Username string
+}
+
+// NewIdentity creates and returns a new Identity for the account with the given
+// subject at the identity provider with the given issuer.
+func NewIdentity(issuer, subject, username string) Identity {
+	return Identity{ID: ID(issuer, subject), Username: username}
+}
+
+// ID returns the ID of the identity for the account with the given subject at
+// the identity provider with the given issuer. Subjects are only unique per
+// issuer, so both are needed.
+func ID(issuer, subject string) string { return issuer + "#" + subject }
@@ -0,0 +1,43 @@
+package identitytbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve by ID an identity from the identity table.
+type Retriever struct{ iget db.DynamoItemGetter }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(iget db.DynamoItemGetter) Retriever {
+	return Retriever{iget: iget}
+}
+
+// Retrieve retrieves by ID an identity from the identity table.
+func (r Retriever) Retrieve(ctx context.Context, id string) (Identity, error) {
+	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+	if out.Item == nil {
+		return Identity{}, db.ErrNoItem
+	}
+
+	var identity Identity
+	if err = attributevalue.UnmarshalMap(out.Item, &identity); err != nil {
+		return Identity{}, err
+	}
+	return identity, nil
+}
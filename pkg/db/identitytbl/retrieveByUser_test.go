@@ -0,0 +1,76 @@
+//go:build utest
+
+package identitytbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetrieverByUser(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetrieverByUser(queryer)
+
+	errA := errors.New("failed to query")
+
+	for _, c := range []struct {
+		name    string
+		qOut    *dynamodb.QueryOutput
+		qErr    error
+		wantIDs []string
+		wantErr error
+	}{
+		{
+			name:    "Err",
+			qOut:    nil,
+			qErr:    errA,
+			wantIDs: []string{},
+			wantErr: errA,
+		},
+		{
+			name:    "NoItems",
+			qOut:    &dynamodb.QueryOutput{},
+			qErr:    nil,
+			wantIDs: []string{},
+			wantErr: nil,
+		},
+		{
+			name: "OK",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					{
+						"ID":       &types.AttributeValueMemberS{Value: "a#1"},
+						"Username": &types.AttributeValueMemberS{Value: "bob"},
+					},
+					{
+						"ID":       &types.AttributeValueMemberS{Value: "b#2"},
+						"Username": &types.AttributeValueMemberS{Value: "bob"},
+					},
+				},
+			},
+			qErr:    nil,
+			wantIDs: []string{"a#1", "b#2"},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.qOut
+			queryer.Err = c.qErr
+
+			identities, err := sut.Retrieve(context.Background(), "bob")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(identities), len(c.wantIDs))
+			for i, id := range c.wantIDs {
+				assert.Equal(t.Error, identities[i].ID, id)
+			}
+		})
+	}
+}
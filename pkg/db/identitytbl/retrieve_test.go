@@ -0,0 +1,70 @@
+//go:build utest
+
+package identitytbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	identityA := NewIdentity("https://idp.goteam.io", "sub123", "bob123")
+	errA := errors.New("failed to get item")
+
+	for _, c := range []struct {
+		name         string
+		igOut        *dynamodb.GetItemOutput
+		igErr        error
+		wantIdentity Identity
+		wantErr      error
+	}{
+		{
+			name:         "Err",
+			igOut:        nil,
+			igErr:        errA,
+			wantIdentity: Identity{},
+			wantErr:      errA,
+		},
+		{
+			name:         "NoItem",
+			igOut:        &dynamodb.GetItemOutput{Item: nil},
+			igErr:        nil,
+			wantIdentity: Identity{},
+			wantErr:      db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			igOut: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: identityA.ID},
+					"Username": &types.AttributeValueMemberS{
+						Value: identityA.Username,
+					},
+				},
+			},
+			igErr:        nil,
+			wantIdentity: identityA,
+			wantErr:      nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			identity, err := sut.Retrieve(context.Background(), identityA.ID)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, identity, c.wantIdentity)
+		})
+	}
+}
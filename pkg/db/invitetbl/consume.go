@@ -0,0 +1,73 @@
+package invitetbl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Consumer can be used to use up an invite in the invite table when a user
+// registers with it.
+type Consumer struct{ iup db.DynamoItemUpdater }
+
+// NewConsumer creates and returns a new Consumer.
+func NewConsumer(iup db.DynamoItemUpdater) Consumer {
+	return Consumer{iup: iup}
+}
+
+// Consume increments the uses of the invite with the given ID and returns the
+// updated invite. Since the update is conditional on the invite being pending
+// and either having no target username or targeting the given username in any
+// casing, no more than MaxUses concurrent calls can succeed, and db.ErrNoItem
+// is returned for all the others.
+func (c Consumer) Consume(
+	ctx context.Context, id, username string,
+) (Invite, error) {
+	nowStr := strconv.FormatInt(time.Now().Unix(), 10)
+
+	out, err := c.iup.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression: aws.String("SET Uses = Uses + :one"),
+		ConditionExpression: aws.String(
+			"attribute_exists(ID) AND Uses < MaxUses AND ExpiresAt > :now " +
+				"AND (Username = :none OR Username = :username " +
+				"OR UsernameLower = :lower)",
+		),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":      &types.AttributeValueMemberN{Value: "1"},
+			":now":      &types.AttributeValueMemberN{Value: nowStr},
+			":none":     &types.AttributeValueMemberS{Value: ""},
+			":username": &types.AttributeValueMemberS{Value: username},
+			":lower": &types.AttributeValueMemberS{
+				Value: strings.ToLower(username),
+			},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var ex *types.ConditionalCheckFailedException
+		if errors.As(err, &ex) {
+			return Invite{}, db.ErrNoItem
+		}
+		return Invite{}, err
+	}
+
+	var invite Invite
+	if err = attributevalue.UnmarshalMap(out.Attributes, &invite); err != nil {
+		return Invite{}, err
+	}
+	return invite, nil
+}
@@ -0,0 +1,90 @@
+//go:build utest
+
+package invitetbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestConsumer(t *testing.T) {
+	iup := &db.FakeDynamoItemUpdater{}
+	sut := NewConsumer(iup)
+
+	inviteA := Invite{
+		ID:        "inviteid",
+		TeamID:    "teamid",
+		CreatedBy: "bob123",
+		MaxUses:   2,
+		Uses:      1,
+		ExpiresAt: 1700000000,
+	}
+	errA := errors.New("failed to update item")
+
+	for _, c := range []struct {
+		name       string
+		outUpd     *dynamodb.UpdateItemOutput
+		errUpd     error
+		wantInvite Invite
+		wantErr    error
+	}{
+		{
+			name:       "Err",
+			outUpd:     nil,
+			errUpd:     errA,
+			wantInvite: Invite{},
+			wantErr:    errA,
+		},
+		{
+			name:   "NoItem",
+			outUpd: nil,
+			errUpd: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{},
+			},
+			wantInvite: Invite{},
+			wantErr:    db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			outUpd: &dynamodb.UpdateItemOutput{
+				Attributes: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: inviteA.ID},
+					"TeamID": &types.AttributeValueMemberS{
+						Value: inviteA.TeamID,
+					},
+					"CreatedBy": &types.AttributeValueMemberS{
+						Value: inviteA.CreatedBy,
+					},
+					"MaxUses": &types.AttributeValueMemberN{Value: "2"},
+					"Uses":    &types.AttributeValueMemberN{Value: "1"},
+					"ExpiresAt": &types.AttributeValueMemberN{
+						Value: "1700000000",
+					},
+				},
+			},
+			errUpd:     nil,
+			wantInvite: inviteA,
+			wantErr:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			iup.Out = c.outUpd
+			iup.Err = c.errUpd
+
+			invite, err := sut.Consume(
+				context.Background(), "inviteid", "alice",
+			)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, invite, c.wantInvite)
+		})
+	}
+}
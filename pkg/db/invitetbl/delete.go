@@ -0,0 +1,46 @@
+package invitetbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Deleter can be used to revoke a team's invite by deleting it from the invite
+// table.
+type Deleter struct{ idel db.DynamoItemDeleter }
+
+// NewDeleter creates and returns a new Deleter.
+func NewDeleter(idel db.DynamoItemDeleter) Deleter {
+	return Deleter{idel: idel}
+}
+
+// Delete deletes the invite with the given ID from the invite table. Since the
+// delete is conditional on the invite belonging to the given team, it returns
+// db.ErrNoItem both when the invite doesn't exist and when it belongs to a
+// different team.
+func (d Deleter) Delete(ctx context.Context, teamID, id string) error {
+	_, err := d.idel.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression: aws.String("TeamID = :teamID"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":teamID": &types.AttributeValueMemberS{Value: teamID},
+		},
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	}
+
+	return err
+}
@@ -0,0 +1,46 @@
+//go:build utest
+
+package invitetbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestDeleter(t *testing.T) {
+	idel := &db.FakeDynamoItemDeleter{}
+	sut := NewDeleter(idel)
+
+	errA := errors.New("failed to delete item")
+
+	for _, c := range []struct {
+		name    string
+		errDel  error
+		wantErr error
+	}{
+		{name: "Err", errDel: errA, wantErr: errA},
+		{
+			name: "NoItem",
+			errDel: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{},
+			},
+			wantErr: db.ErrNoItem,
+		},
+		{name: "OK", errDel: nil, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			idel.Err = c.errDel
+
+			err := sut.Delete(context.Background(), "teamid", "inviteid")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package invitetbl contains code to interact with the team invite table in
+// DynamoDB.
+package invitetbl
+
+import (
+	"strings"
+
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// tableName is the name of the environment variable to retrieve the invite
+// table's name from.
+const tableName = "INVITE_TABLE_NAME"
+
+// Invite defines the team invite entity. Each invite is created by a team admin
+// and lets up to MaxUses users register into the team before it expires. If
+// Username is not empty, only the user with that username can register with
+// the invite. Role is the role that the users who register with the invite are
+// given in the team.
+//
+// UsernameLower is Username in lower case, which the username of the user who
+// uses the invite is matched against since usernames are case-insensitive.
+// Invites created before then don't have it and only match the exact casing.
+type Invite struct {
+	ID            string
+	TeamID        string
+	CreatedBy     string
+	Username      string
+	UsernameLower string `dynamodbav:",omitempty"`
+	Role          role.Role
+	MaxUses       int
+	Uses          int
+	CreatedAt     int64
+	ExpiresAt     int64
+}
+
+// NewInvite creates and returns a new Invite with no uses.
+func NewInvite(
+	id string,
+	teamID string,
+	createdBy string,
+	username string,
+	r role.Role,
+	maxUses int,
+	createdAt int64,
+	expiresAt int64,
+) Invite {
+	return Invite{
+		ID:            id,
+		TeamID:        teamID,
+		CreatedBy:     createdBy,
+		Username:      username,
+		UsernameLower: strings.ToLower(username),
+		Role:          r,
+		MaxUses:       maxUses,
+		Uses:          0,
+		CreatedAt:     createdAt,
+		ExpiresAt:     expiresAt,
+	}
+}
+
+// IsPending returns whether the invite can still be used at the given Unix
+// time.
+func (i Invite) IsPending(now int64) bool {
+	return i.Uses < i.MaxUses && i.ExpiresAt > now
+}
@@ -0,0 +1,37 @@
+//go:build utest
+
+package invitetbl
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestInviteIsPending(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		invite Invite
+		want   bool
+	}{
+		{
+			name:   "UsedUp",
+			invite: Invite{MaxUses: 2, Uses: 2, ExpiresAt: 200},
+			want:   false,
+		},
+		{
+			name:   "Expired",
+			invite: Invite{MaxUses: 2, Uses: 1, ExpiresAt: 100},
+			want:   false,
+		},
+		{
+			name:   "Pending",
+			invite: Invite{MaxUses: 2, Uses: 1, ExpiresAt: 200},
+			want:   true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, c.invite.IsPending(100), c.want)
+		})
+	}
+}
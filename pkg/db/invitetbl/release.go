@@ -0,0 +1,47 @@
+package invitetbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Releaser can be used to give back a use of an invite in the invite table
+// when registering with it fails after it was consumed.
+type Releaser struct{ iup db.DynamoItemUpdater }
+
+// NewReleaser creates and returns a new Releaser.
+func NewReleaser(iup db.DynamoItemUpdater) Releaser {
+	return Releaser{iup: iup}
+}
+
+// Release decrements the uses of the invite with the given ID. It returns
+// db.ErrNoItem if the invite doesn't exist or has no uses to give back, which
+// is the case when it was revoked in the meantime.
+func (r Releaser) Release(ctx context.Context, id string) error {
+	_, err := r.iup.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:    aws.String("SET Uses = Uses - :one"),
+		ConditionExpression: aws.String("attribute_exists(ID) AND Uses > :zero"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":  &types.AttributeValueMemberN{Value: "1"},
+			":zero": &types.AttributeValueMemberN{Value: "0"},
+		},
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	}
+
+	return err
+}
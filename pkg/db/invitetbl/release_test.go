@@ -0,0 +1,46 @@
+//go:build utest
+
+package invitetbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestReleaser(t *testing.T) {
+	iup := &db.FakeDynamoItemUpdater{}
+	sut := NewReleaser(iup)
+
+	errA := errors.New("failed to update item")
+
+	for _, c := range []struct {
+		name    string
+		errUpd  error
+		wantErr error
+	}{
+		{name: "Err", errUpd: errA, wantErr: errA},
+		{
+			name: "NoItem",
+			errUpd: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{},
+			},
+			wantErr: db.ErrNoItem,
+		},
+		{name: "OK", errUpd: nil, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			iup.Err = c.errUpd
+
+			err := sut.Release(context.Background(), "inviteid")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package invitetbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// RetrieverByTeam can be used to retrieve all invites of a team from the
+// invite table.
+type RetrieverByTeam struct{ queryer db.DynamoQueryer }
+
+// NewRetrieverByTeam creates and returns a new RetrieverByTeam.
+func NewRetrieverByTeam(queryer db.DynamoQueryer) RetrieverByTeam {
+	return RetrieverByTeam{queryer: queryer}
+}
+
+// Retrieve retrieves all invites of a team from the invite table.
+func (r RetrieverByTeam) Retrieve(
+	ctx context.Context, teamID string,
+) ([]Invite, error) {
+	keyCond := expression.Key("TeamID").Equal(expression.Value(teamID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := r.queryer.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		IndexName:                 aws.String("TeamID-index"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invites := []Invite{}
+	err = attributevalue.UnmarshalListOfMaps(out.Items, &invites)
+	return invites, err
+}
@@ -0,0 +1,76 @@
+//go:build utest
+
+package invitetbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetrieverByTeam(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetrieverByTeam(queryer)
+
+	errA := errors.New("failed to query")
+
+	for _, c := range []struct {
+		name    string
+		qOut    *dynamodb.QueryOutput
+		qErr    error
+		wantIDs []string
+		wantErr error
+	}{
+		{
+			name:    "Err",
+			qOut:    nil,
+			qErr:    errA,
+			wantIDs: []string{},
+			wantErr: errA,
+		},
+		{
+			name:    "NoItems",
+			qOut:    &dynamodb.QueryOutput{},
+			qErr:    nil,
+			wantIDs: []string{},
+			wantErr: nil,
+		},
+		{
+			name: "OK",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					{
+						"ID":     &types.AttributeValueMemberS{Value: "a"},
+						"TeamID": &types.AttributeValueMemberS{Value: "t"},
+					},
+					{
+						"ID":     &types.AttributeValueMemberS{Value: "b"},
+						"TeamID": &types.AttributeValueMemberS{Value: "t"},
+					},
+				},
+			},
+			qErr:    nil,
+			wantIDs: []string{"a", "b"},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.qOut
+			queryer.Err = c.qErr
+
+			invites, err := sut.Retrieve(context.Background(), "t")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(invites), len(c.wantIDs))
+			for i, id := range c.wantIDs {
+				assert.Equal(t.Error, invites[i].ID, id)
+			}
+		})
+	}
+}
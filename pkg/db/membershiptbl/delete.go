@@ -0,0 +1,43 @@
+package membershiptbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Deleter can be used to delete a user's membership of a team from the
+// membership table.
+type Deleter struct{ idel db.DynamoItemDeleter }
+
+// NewDeleter creates and returns a new Deleter.
+func NewDeleter(idel db.DynamoItemDeleter) Deleter {
+	return Deleter{idel: idel}
+}
+
+// Delete deletes the membership of the user with the given username of the
+// team with the given ID from the membership table. It returns db.ErrNoItem if
+// the user is not a member of the team.
+func (d Deleter) Delete(ctx context.Context, username, teamID string) error {
+	_, err := d.idel.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: username},
+			"TeamID":   &types.AttributeValueMemberS{Value: teamID},
+		},
+		ConditionExpression: aws.String("attribute_exists(TeamID)"),
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	}
+
+	return err
+}
@@ -0,0 +1,46 @@
+//go:build utest
+
+package membershiptbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestDeleter(t *testing.T) {
+	idel := &db.FakeDynamoItemDeleter{}
+	sut := NewDeleter(idel)
+
+	errA := errors.New("failed to delete item")
+
+	for _, c := range []struct {
+		name    string
+		idelErr error
+		wantErr error
+	}{
+		{name: "Err", idelErr: errA, wantErr: errA},
+		{
+			name: "NoItem",
+			idelErr: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{},
+			},
+			wantErr: db.ErrNoItem,
+		},
+		{name: "OK", idelErr: nil, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			idel.Err = c.idelErr
+
+			err := sut.Delete(context.Background(), "bob123", "teamid")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
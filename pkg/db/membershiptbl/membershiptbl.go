@@ -0,0 +1,23 @@
+// Package membershiptbl contains code to interact with the membership table in
+// DynamoDB.
+package membershiptbl
+
+import "github.com/kxplxn/goteam/pkg/role"
+
+// tableName is the name of the environment variable to retrieve the membership
+// table's name from.
+const tableName = "MEMBERSHIP_TABLE_NAME"
+
+// Membership defines the membership entity, which relates a user to one of the
+// teams that they are a member of along with the role they have in it. The
+// table is keyed by Username and TeamID so that a user's teams can be queried.
+type Membership struct {
+	Username string
+	TeamID   string
+	Role     role.Role
+}
+
+// NewMembership creates and returns a new Membership.
+func NewMembership(username, teamID string, r role.Role) Membership {
+	return Membership{Username: username, TeamID: teamID, Role: r}
+}
@@ -0,0 +1,49 @@
+package membershiptbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve a user's membership of a team from the
+// membership table.
+type Retriever struct{ iget db.DynamoItemGetter }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(iget db.DynamoItemGetter) Retriever {
+	return Retriever{iget: iget}
+}
+
+// Retrieve retrieves the membership of the user with the given username of the
+// team with the given ID from the membership table. It returns db.ErrNoItem if
+// the user is not a member of the team.
+func (r Retriever) Retrieve(
+	ctx context.Context, username, teamID string,
+) (Membership, error) {
+	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: username},
+			"TeamID":   &types.AttributeValueMemberS{Value: teamID},
+		},
+	})
+	if err != nil {
+		return Membership{}, err
+	}
+	if out.Item == nil {
+		return Membership{}, db.ErrNoItem
+	}
+
+	var m Membership
+	if err = attributevalue.UnmarshalMap(out.Item, &m); err != nil {
+		return Membership{}, err
+	}
+	return m, nil
+}
@@ -0,0 +1,76 @@
+//go:build utest
+
+package membershiptbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetrieverByTeam(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetrieverByTeam(queryer)
+
+	errA := errors.New("failed to query")
+
+	for _, c := range []struct {
+		name          string
+		qOut          *dynamodb.QueryOutput
+		qErr          error
+		wantUsernames []string
+		wantErr       error
+	}{
+		{
+			name:          "Err",
+			qOut:          nil,
+			qErr:          errA,
+			wantUsernames: []string{},
+			wantErr:       errA,
+		},
+		{
+			name:          "NoItems",
+			qOut:          &dynamodb.QueryOutput{},
+			qErr:          nil,
+			wantUsernames: []string{},
+			wantErr:       nil,
+		},
+		{
+			name: "OK",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					{
+						"Username": &types.AttributeValueMemberS{Value: "bob"},
+						"TeamID":   &types.AttributeValueMemberS{Value: "a"},
+					},
+					{
+						"Username": &types.AttributeValueMemberS{Value: "sam"},
+						"TeamID":   &types.AttributeValueMemberS{Value: "a"},
+					},
+				},
+			},
+			qErr:          nil,
+			wantUsernames: []string{"bob", "sam"},
+			wantErr:       nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.qOut
+			queryer.Err = c.qErr
+
+			memberships, err := sut.Retrieve(context.Background(), "a")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(memberships), len(c.wantUsernames))
+			for i, username := range c.wantUsernames {
+				assert.Equal(t.Error, memberships[i].Username, username)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+//go:build utest
+
+package membershiptbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetrieverByUser(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetrieverByUser(queryer)
+
+	errA := errors.New("failed to query")
+
+	for _, c := range []struct {
+		name        string
+		qOut        *dynamodb.QueryOutput
+		qErr        error
+		wantTeamIDs []string
+		wantErr     error
+	}{
+		{
+			name:        "Err",
+			qOut:        nil,
+			qErr:        errA,
+			wantTeamIDs: []string{},
+			wantErr:     errA,
+		},
+		{
+			name:        "NoItems",
+			qOut:        &dynamodb.QueryOutput{},
+			qErr:        nil,
+			wantTeamIDs: []string{},
+			wantErr:     nil,
+		},
+		{
+			name: "OK",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					{
+						"Username": &types.AttributeValueMemberS{Value: "bob"},
+						"TeamID":   &types.AttributeValueMemberS{Value: "a"},
+					},
+					{
+						"Username": &types.AttributeValueMemberS{Value: "bob"},
+						"TeamID":   &types.AttributeValueMemberS{Value: "b"},
+					},
+				},
+			},
+			qErr:        nil,
+			wantTeamIDs: []string{"a", "b"},
+			wantErr:     nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.qOut
+			queryer.Err = c.qErr
+
+			memberships, err := sut.Retrieve(context.Background(), "bob")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(memberships), len(c.wantTeamIDs))
+			for i, teamID := range c.wantTeamIDs {
+				assert.Equal(t.Error, memberships[i].TeamID, teamID)
+			}
+		})
+	}
+}
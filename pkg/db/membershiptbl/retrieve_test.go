@@ -0,0 +1,70 @@
+//go:build utest
+
+package membershiptbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	membershipA := NewMembership("bob123", "teamid", role.Admin)
+	errA := errors.New("failed to get item")
+
+	for _, c := range []struct {
+		name           string
+		igOut          *dynamodb.GetItemOutput
+		igErr          error
+		wantMembership Membership
+		wantErr        error
+	}{
+		{
+			name:           "Err",
+			igOut:          nil,
+			igErr:          errA,
+			wantMembership: Membership{},
+			wantErr:        errA,
+		},
+		{
+			name:           "NoItem",
+			igOut:          &dynamodb.GetItemOutput{Item: nil},
+			igErr:          nil,
+			wantMembership: Membership{},
+			wantErr:        db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			igOut: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Username": &types.AttributeValueMemberS{Value: "bob123"},
+					"TeamID":   &types.AttributeValueMemberS{Value: "teamid"},
+					"Role":     &types.AttributeValueMemberS{Value: "admin"},
+				},
+			},
+			igErr:          nil,
+			wantMembership: membershipA,
+			wantErr:        nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			m, err := sut.Retrieve(context.Background(), "bob123", "teamid")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, m, c.wantMembership)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package membershiptbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// Updater can be used to update the role of a member in the membership table.
+type Updater struct{ iu db.DynamoItemUpdater }
+
+// NewUpdater creates and returns a new Updater.
+func NewUpdater(iu db.DynamoItemUpdater) Updater {
+	return Updater{iu: iu}
+}
+
+// Update sets the role of the user in the team of the given membership. It
+// returns db.ErrNoItem if the user is not a member of the team, or if they are
+// its owner, whose role can only be changed by transferring the ownership.
+//
+// The role of the user is also updated in the user table if the team is their
+// active team, so that it is picked up the next time their auth token is
+// issued.
+func (u Updater) Update(ctx context.Context, m Membership) error {
+	var (
+		teamIDAttr = &types.AttributeValueMemberS{Value: m.TeamID}
+		roleAttr   = &types.AttributeValueMemberS{Value: string(m.Role)}
+	)
+
+	_, err := u.iu.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: m.Username},
+			"TeamID":   teamIDAttr,
+		},
+		UpdateExpression: aws.String("SET #role = :role"),
+		ConditionExpression: aws.String(
+			"attribute_exists(TeamID) AND #role <> :owner",
+		),
+		ExpressionAttributeNames: map[string]string{"#role": "Role"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":role": roleAttr,
+			":owner": &types.AttributeValueMemberS{
+				Value: string(role.Owner),
+			},
+		},
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	} else if err != nil {
+		return err
+	}
+
+	_, err = u.iu.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(os.Getenv("USER_TABLE_NAME")),
+		Key: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: m.Username},
+		},
+		UpdateExpression:         aws.String("SET #role = :role"),
+		ConditionExpression:      aws.String("TeamID = :teamID"),
+		ExpressionAttributeNames: map[string]string{"#role": "Role"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":role":   roleAttr,
+			":teamID": teamIDAttr,
+		},
+	})
+	if errors.As(err, &ex) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,49 @@
+//go:build utest
+
+package membershiptbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+func TestUpdater(t *testing.T) {
+	iu := &db.FakeDynamoItemUpdater{}
+	sut := NewUpdater(iu)
+
+	errA := errors.New("failed to update item")
+
+	for _, c := range []struct {
+		name    string
+		iuErr   error
+		wantErr error
+	}{
+		{name: "Err", iuErr: errA, wantErr: errA},
+		{
+			name: "NoItem",
+			iuErr: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{},
+			},
+			wantErr: db.ErrNoItem,
+		},
+		{name: "OK", iuErr: nil, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			iu.Err = c.iuErr
+
+			err := sut.Update(context.Background(), NewMembership(
+				"bob123", "teamid", role.Admin,
+			))
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Package overridetbl contains code to interact with the WIP limit override
+// table in DynamoDB.
+package overridetbl
+
+// tableName is the name of the environment variable to retrieve the override
+// table's name from.
+const tableName = "OVERRIDE_TABLE_NAME"
+
+// Override defines the record of a user exceeding the WIP limit of a board's
+// column. Count is the number of tasks the column had after the change that
+// exceeded its limit.
+type Override struct {
+	BoardID   string
+	ID        string
+	TeamID    string
+	ColID     string
+	Username  string
+	WIPLimit  int
+	Count     int
+	CreatedAt int64
+}
+
+// NewOverride creates and returns a new Override.
+func NewOverride(
+	boardID string,
+	id string,
+	teamID string,
+	colID string,
+	username string,
+	wipLimit int,
+	count int,
+	createdAt int64,
+) Override {
+	return Override{
+		BoardID:   boardID,
+		ID:        id,
+		TeamID:    teamID,
+		ColID:     colID,
+		Username:  username,
+		WIPLimit:  wipLimit,
+		Count:     count,
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,51 @@
+package resettbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Consumer can be used to retrieve and delete by ID a password reset from the
+// reset table in a single operation.
+type Consumer struct{ idel db.DynamoItemDeleter }
+
+// NewConsumer creates and returns a new Consumer.
+func NewConsumer(idel db.DynamoItemDeleter) Consumer {
+	return Consumer{idel: idel}
+}
+
+// Consume deletes by ID a password reset from the reset table and returns the
+// deleted reset. Since the delete is conditional on the reset existing, only
+// one of any concurrent calls with the same ID succeeds, which makes each
+// reset single-use.
+func (c Consumer) Consume(ctx context.Context, id string) (Reset, error) {
+	out, err := c.idel.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression: aws.String("attribute_exists(ID)"),
+		ReturnValues:        types.ReturnValueAllOld,
+	})
+	if err != nil {
+		var ex *types.ConditionalCheckFailedException
+		if errors.As(err, &ex) {
+			return Reset{}, db.ErrNoItem
+		}
+		return Reset{}, err
+	}
+
+	var reset Reset
+	if err = attributevalue.UnmarshalMap(out.Attributes, &reset); err != nil {
+		return Reset{}, err
+	}
+	return reset, nil
+}
@@ -0,0 +1,76 @@
+//go:build utest
+
+package resettbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestConsumer(t *testing.T) {
+	id := &db.FakeDynamoItemDeleter{}
+	sut := NewConsumer(id)
+
+	resetA := Reset{ID: "hash", Username: "bob123", ExpiresAt: 1700000000}
+	errA := errors.New("failed to delete item")
+
+	for _, c := range []struct {
+		name      string
+		idOut     *dynamodb.DeleteItemOutput
+		idErr     error
+		wantReset Reset
+		wantErr   error
+	}{
+		{
+			name:      "Err",
+			idOut:     nil,
+			idErr:     errA,
+			wantReset: Reset{},
+			wantErr:   errA,
+		},
+		{
+			name:  "NoItem",
+			idOut: nil,
+			idErr: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{},
+			},
+			wantReset: Reset{},
+			wantErr:   db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			idOut: &dynamodb.DeleteItemOutput{
+				Attributes: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: resetA.ID},
+					"Username": &types.AttributeValueMemberS{
+						Value: resetA.Username,
+					},
+					"ExpiresAt": &types.AttributeValueMemberN{
+						Value: "1700000000",
+					},
+				},
+			},
+			idErr:     nil,
+			wantReset: resetA,
+			wantErr:   nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			id.Out = c.idOut
+			id.Err = c.idErr
+
+			reset, err := sut.Consume(context.Background(), "hash")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, reset, c.wantReset)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Package resettbl contains code to interact with the password reset table in
+// DynamoDB.
+package resettbl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// tableName is the name of the environment variable to retrieve the password
+// reset table's name from.
+const tableName = "RESET_TABLE_NAME"
+
+// Reset defines the password reset entity. Each reset represents a single-use
+// token that was mailed to a user to let them set a new password. Only the
+// hash of the token is stored so that a leaked table cannot be used to reset
+// passwords.
+type Reset struct {
+	ID        string
+	Username  string
+	ExpiresAt int64
+}
+
+// NewReset creates and returns a new Reset.
+func NewReset(id string, username string, expiresAt int64) Reset {
+	return Reset{ID: id, Username: username, ExpiresAt: expiresAt}
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a password reset token to
+// be used as the ID of the corresponding Reset.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
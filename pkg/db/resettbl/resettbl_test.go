@@ -0,0 +1,16 @@
+//go:build utest
+
+package resettbl
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestHashToken(t *testing.T) {
+	assert.Equal(t.Error,
+		HashToken("abc"),
+		"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	)
+}
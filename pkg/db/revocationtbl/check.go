@@ -0,0 +1,67 @@
+package revocationtbl
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Checker can be used to check whether an auth token was revoked using the
+// revocation table.
+type Checker struct{ queryer db.DynamoQueryer }
+
+// NewChecker creates and returns a new Checker.
+func NewChecker(queryer db.DynamoQueryer) Checker {
+	return Checker{queryer: queryer}
+}
+
+// IsRevoked checks whether the auth token with the given ID and issue time in
+// Unix milliseconds was revoked for the given user.
+func (c Checker) IsRevoked(
+	ctx context.Context, username string, tokenID string, issuedAtMilli int64,
+) (bool, error) {
+	keyCond := expression.Key("Username").Equal(expression.Value(username))
+	filt := expression.Name("ID").In(
+		expression.Value(tokenID), expression.Value(AllID),
+	)
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(keyCond).
+		WithFilter(filt).
+		Build()
+	if err != nil {
+		return false, err
+	}
+
+	out, err := c.queryer.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var revs []Revocation
+	if err = attributevalue.UnmarshalListOfMaps(out.Items, &revs); err != nil {
+		return false, err
+	}
+
+	// expired items are filtered out since DynamoDB doesn't delete them as
+	// soon as their TTL passes
+	now := time.Now().Unix()
+	for _, rev := range revs {
+		if rev.revokes(tokenID, issuedAtMilli, now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
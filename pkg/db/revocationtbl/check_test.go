@@ -0,0 +1,161 @@
+//go:build utest
+
+package revocationtbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestChecker(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewChecker(queryer)
+
+	errA := errors.New("failed to query")
+	now := time.Now().Unix()
+	issuedAtMilli := (now - 60) * 1000
+	item := func(
+		id string, invalidatedAtMilli int64, expiresAt int64,
+	) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: "bob123"},
+			"ID":       &types.AttributeValueMemberS{Value: id},
+			"InvalidatedAtMilli": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(invalidatedAtMilli, 10),
+			},
+			"ExpiresAt": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(expiresAt, 10),
+			},
+		}
+	}
+	legacyItem := func(invalidatedAt int64) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: "bob123"},
+			"ID":       &types.AttributeValueMemberS{Value: AllID},
+			"InvalidatedAt": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(invalidatedAt, 10),
+			},
+		}
+	}
+
+	for _, c := range []struct {
+		name          string
+		qOut          *dynamodb.QueryOutput
+		qErr          error
+		wantIsRevoked bool
+		wantErr       error
+	}{
+		{
+			name:          "Err",
+			qOut:          nil,
+			qErr:          errA,
+			wantIsRevoked: false,
+			wantErr:       errA,
+		},
+		{
+			name:          "NoItems",
+			qOut:          &dynamodb.QueryOutput{},
+			qErr:          nil,
+			wantIsRevoked: false,
+			wantErr:       nil,
+		},
+		{
+			name: "TokenRevocationExpired",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					item("tokenid", 0, now-1),
+				},
+			},
+			qErr:          nil,
+			wantIsRevoked: false,
+			wantErr:       nil,
+		},
+		{
+			name: "IssuedAfterAllRevocation",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					item(AllID, issuedAtMilli-1, 0),
+				},
+			},
+			qErr:          nil,
+			wantIsRevoked: false,
+			wantErr:       nil,
+		},
+		{
+			name: "IssuedWithAllRevocation",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					item(AllID, issuedAtMilli, 0),
+				},
+			},
+			qErr:          nil,
+			wantIsRevoked: false,
+			wantErr:       nil,
+		},
+		{
+			name: "IssuedAfterLegacyAllRevocation",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					legacyItem(now - 61),
+				},
+			},
+			qErr:          nil,
+			wantIsRevoked: false,
+			wantErr:       nil,
+		},
+		{
+			name: "IssuedSameSecondAsLegacyAllRevocation",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					legacyItem(now - 60),
+				},
+			},
+			qErr:          nil,
+			wantIsRevoked: true,
+			wantErr:       nil,
+		},
+		{
+			name: "TokenRevoked",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					item("tokenid", 0, now+60),
+				},
+			},
+			qErr:          nil,
+			wantIsRevoked: true,
+			wantErr:       nil,
+		},
+		{
+			name: "IssuedBeforeAllRevocation",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					item(AllID, issuedAtMilli+1, 0),
+				},
+			},
+			qErr:          nil,
+			wantIsRevoked: true,
+			wantErr:       nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.qOut
+			queryer.Err = c.qErr
+
+			isRevoked, err := sut.IsRevoked(
+				context.Background(), "bob123", "tokenid", issuedAtMilli,
+			)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, isRevoked, c.wantIsRevoked)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package revocationtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Inserter can be used to insert a revocation into the revocation table.
+type Inserter struct{ iput db.DynamoItemPutter }
+
+// NewInserter creates and returns a new Inserter.
+func NewInserter(iput db.DynamoItemPutter) Inserter {
+	return Inserter{iput: iput}
+}
+
+// Insert inserts a revocation into the revocation table, replacing the
+// existing one with the same username and ID if there is any.
+func (i Inserter) Insert(ctx context.Context, rev Revocation) error {
+	item, err := attributevalue.MarshalMap(rev)
+	if err != nil {
+		return err
+	}
+
+	_, err = i.iput.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Item:      item,
+	})
+	return err
+}
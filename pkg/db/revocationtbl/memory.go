@@ -0,0 +1,61 @@
+package revocationtbl
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory alternative to the revocation table. It can be used
+// in place of both Inserter and Checker when running a single instance of a
+// service (e.g. locally or in tests).
+type Memory struct {
+	mu   sync.RWMutex
+	revs map[string]map[string]Revocation
+}
+
+// NewMemory creates and returns a new Memory.
+func NewMemory() *Memory {
+	return &Memory{revs: map[string]map[string]Revocation{}}
+}
+
+// Insert stores a revocation, replacing the existing one with the same username
+// and ID if there is any. Expired revocations of the user are discarded.
+func (m *Memory) Insert(_ context.Context, rev Revocation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	userRevs, ok := m.revs[rev.Username]
+	if !ok {
+		userRevs = map[string]Revocation{}
+		m.revs[rev.Username] = userRevs
+	}
+
+	now := time.Now().Unix()
+	for id, r := range userRevs {
+		if r.ExpiresAt != 0 && r.ExpiresAt <= now {
+			delete(userRevs, id)
+		}
+	}
+
+	userRevs[rev.ID] = rev
+	return nil
+}
+
+// IsRevoked checks whether the auth token with the given ID and issue time in
+// Unix milliseconds was revoked for the given user.
+func (m *Memory) IsRevoked(
+	_ context.Context, username string, tokenID string, issuedAtMilli int64,
+) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now().Unix()
+	for _, id := range []string{tokenID, AllID} {
+		if rev, ok := m.revs[username][id]; ok &&
+			rev.revokes(tokenID, issuedAtMilli, now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,145 @@
+//go:build utest
+
+package revocationtbl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	nowMilli := time.Now().UnixMilli()
+
+	for _, c := range []struct {
+		name          string
+		revs          []Revocation
+		username      string
+		tokenID       string
+		issuedAt      int64
+		wantIsRevoked bool
+	}{
+		{
+			name:          "NoRevocations",
+			revs:          []Revocation{},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      nowMilli,
+			wantIsRevoked: false,
+		},
+		{
+			name: "OtherUser",
+			revs: []Revocation{
+				NewTokenRevocation("bob124", "tokenid", now+60),
+				NewAllRevocation("bob124", nowMilli+60000),
+			},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      nowMilli,
+			wantIsRevoked: false,
+		},
+		{
+			name: "OtherToken",
+			revs: []Revocation{
+				NewTokenRevocation("bob123", "othertokenid", now+60),
+			},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      nowMilli,
+			wantIsRevoked: false,
+		},
+		{
+			name: "TokenRevocationExpired",
+			revs: []Revocation{
+				NewTokenRevocation("bob123", "tokenid", now-1),
+			},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      nowMilli - 60000,
+			wantIsRevoked: false,
+		},
+		{
+			name: "TokenRevoked",
+			revs: []Revocation{
+				NewTokenRevocation("bob123", "tokenid", now+60),
+			},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      nowMilli,
+			wantIsRevoked: true,
+		},
+		{
+			name: "IssuedAfterAllRevocation",
+			revs: []Revocation{
+				NewAllRevocation("bob123", nowMilli-60000),
+			},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      nowMilli,
+			wantIsRevoked: false,
+		},
+		{
+			name:          "IssuedWithAllRevocation",
+			revs:          []Revocation{NewAllRevocation("bob123", nowMilli)},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      nowMilli,
+			wantIsRevoked: false,
+		},
+		{
+			name:          "IssuedJustBeforeAllRevocation",
+			revs:          []Revocation{NewAllRevocation("bob123", nowMilli)},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      nowMilli - 1,
+			wantIsRevoked: true,
+		},
+		{
+			name:          "IssuedBeforeAllRevocation",
+			revs:          []Revocation{NewAllRevocation("bob123", nowMilli)},
+			username:      "bob123",
+			tokenID:       "",
+			issuedAt:      nowMilli - 60000,
+			wantIsRevoked: true,
+		},
+		{
+			name: "IssuedAfterLegacyAllRevocation",
+			revs: []Revocation{
+				{Username: "bob123", ID: AllID, InvalidatedAt: now},
+			},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      (now + 1) * 1000,
+			wantIsRevoked: false,
+		},
+		{
+			name: "IssuedSameSecondAsLegacyAllRevocation",
+			revs: []Revocation{
+				{Username: "bob123", ID: AllID, InvalidatedAt: now},
+			},
+			username:      "bob123",
+			tokenID:       "tokenid",
+			issuedAt:      now*1000 + 999,
+			wantIsRevoked: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sut := NewMemory()
+			for _, rev := range c.revs {
+				err := sut.Insert(ctx, rev)
+				assert.Nil(t.Fatal, err)
+			}
+
+			isRevoked, err := sut.IsRevoked(
+				ctx, c.username, c.tokenID, c.issuedAt,
+			)
+
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, isRevoked, c.wantIsRevoked)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Package revocationtbl contains code to interact with the revocation table in
+// DynamoDB, which keeps track of the auth tokens that were revoked before they
+// expired.
+package revocationtbl
+
+// tableName is the name of the environment variable to retrieve the revocation
+// table's name from.
+const tableName = "REVOCATION_TABLE_NAME"
+
+// AllID is the ID of the revocation that, when present for a user, revokes
+// all auth tokens that were issued to that user before its InvalidatedAtMilli.
+const AllID = "all"
+
+// Revocation defines the revocation entity. It either revokes a single auth
+// token by its ID or, if its ID is AllID, all auth tokens of a user issued
+// before InvalidatedAtMilli. Revocations stored before InvalidatedAtMilli was
+// introduced only have InvalidatedAt instead, which is in seconds and revokes
+// the tokens issued before or during that second. A zero ExpiresAt means the
+// revocation never expires.
+type Revocation struct {
+	Username           string
+	ID                 string
+	InvalidatedAt      int64 `dynamodbav:",omitempty"`
+	InvalidatedAtMilli int64 `dynamodbav:",omitempty"`
+	ExpiresAt          int64 `dynamodbav:",omitempty"`
+}
+
+// NewTokenRevocation creates and returns a new Revocation that revokes the auth
+// token with the given ID until it expires.
+func NewTokenRevocation(
+	username string, tokenID string, expiresAt int64,
+) Revocation {
+	return Revocation{Username: username, ID: tokenID, ExpiresAt: expiresAt}
+}
+
+// NewAllRevocation creates and returns a new Revocation that revokes all auth
+// tokens of the given user issued before invalidatedAtMilli, which is in Unix
+// milliseconds.
+func NewAllRevocation(username string, invalidatedAtMilli int64) Revocation {
+	return Revocation{
+		Username: username, ID: AllID, InvalidatedAtMilli: invalidatedAtMilli,
+	}
+}
+
+// revokes determines whether the Revocation revokes an auth token with the
+// given ID and issue time in Unix milliseconds at the given time in seconds.
+func (r Revocation) revokes(
+	tokenID string, issuedAtMilli int64, now int64,
+) bool {
+	if r.ExpiresAt != 0 && r.ExpiresAt <= now {
+		return false
+	}
+	if r.ID == AllID {
+		if r.InvalidatedAtMilli == 0 {
+			return issuedAtMilli/1000 <= r.InvalidatedAt
+		}
+		return issuedAtMilli < r.InvalidatedAtMilli
+	}
+	return tokenID != "" && r.ID == tokenID
+}
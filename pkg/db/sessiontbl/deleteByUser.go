@@ -0,0 +1,63 @@
+package sessiontbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// DeleterByUser can be used to delete all sessions of a user from the session
+// table.
+type DeleterByUser struct{ qdel db.DynamoQueryDeleter }
+
+// NewDeleterByUser creates and returns a new DeleterByUser.
+func NewDeleterByUser(qdel db.DynamoQueryDeleter) DeleterByUser {
+	return DeleterByUser{qdel: qdel}
+}
+
+// Delete deletes all sessions of a user from the session table.
+func (d DeleterByUser) Delete(ctx context.Context, username string) error {
+	keyCond := expression.Key("Username").Equal(expression.Value(username))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return err
+	}
+
+	tableName := os.Getenv(tableName)
+	out, err := d.qdel.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		IndexName:                 aws.String("Username-index"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var sessions []Session
+	if err = attributevalue.UnmarshalListOfMaps(
+		out.Items, &sessions,
+	); err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		if _, err = d.qdel.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberS{Value: s.ID},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build utest
+
+package sessiontbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestDeleter(t *testing.T) {
+	idel := &db.FakeDynamoItemDeleter{}
+	sut := NewDeleter(idel)
+
+	errA := errors.New("failed to delete item")
+
+	for _, c := range []struct {
+		name    string
+		idelErr error
+		wantErr error
+	}{
+		{name: "Err", idelErr: errA, wantErr: errA},
+		{name: "OK", idelErr: nil, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			idel.Err = c.idelErr
+
+			err := sut.Delete(context.Background(), "")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
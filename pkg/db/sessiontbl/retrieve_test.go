@@ -0,0 +1,84 @@
+//go:build utest
+
+package sessiontbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	sessionA := Session{
+		ID:        "c57e5a34-ea70-4a33-9d8d-6c8b1b1e38b1",
+		Username:  "bob123",
+		TokenID:   "f7e1b1a8-9a2e-4e6b-a36a-1b5a8c0b9f4e",
+		ExpiresAt: 1700000000,
+	}
+	errA := errors.New("failed to get item")
+
+	for _, c := range []struct {
+		name        string
+		igOut       *dynamodb.GetItemOutput
+		igErr       error
+		wantSession *Session
+		wantErr     error
+	}{
+		{
+			name:        "Err",
+			igOut:       nil,
+			igErr:       errA,
+			wantSession: nil,
+			wantErr:     errA,
+		},
+		{
+			name:        "NoItem",
+			igOut:       &dynamodb.GetItemOutput{Item: nil},
+			igErr:       nil,
+			wantSession: nil,
+			wantErr:     db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			igOut: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: sessionA.ID},
+					"Username": &types.AttributeValueMemberS{
+						Value: sessionA.Username,
+					},
+					"TokenID": &types.AttributeValueMemberS{
+						Value: sessionA.TokenID,
+					},
+					"ExpiresAt": &types.AttributeValueMemberN{
+						Value: strconv.FormatInt(sessionA.ExpiresAt, 10),
+					},
+				},
+			},
+			igErr:       nil,
+			wantSession: &sessionA,
+			wantErr:     nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			session, err := sut.Retrieve(context.Background(), "")
+
+			assert.Equal(t.Fatal, err, c.wantErr)
+			if c.wantSession != nil {
+				assert.Equal(t.Error, session, *c.wantSession)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+// Package sessiontbl contains code to interact with the session table in
+// DynamoDB.
+package sessiontbl
+
+// tableName is the name of the environment variable to retrieve the session
+// table's name from.
+const tableName = "SESSION_TABLE_NAME"
+
+// Session defines the session entity. Each session represents a family of
+// refresh tokens that were issued to a user starting from a single login, and
+// only the latest refresh token of the family is stored.
+type Session struct {
+	ID        string
+	Username  string
+	TokenID   string
+	ExpiresAt int64
+}
+
+// NewSession creates and returns a new Session.
+func NewSession(
+	id string, username string, tokenID string, expiresAt int64,
+) Session {
+	return Session{
+		ID:        id,
+		Username:  username,
+		TokenID:   tokenID,
+		ExpiresAt: expiresAt,
+	}
+}
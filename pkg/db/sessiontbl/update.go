@@ -0,0 +1,49 @@
+package sessiontbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Updater can be used to rotate the refresh token of a session in the session
+// table.
+type Updater struct{ iput db.DynamoItemPutter }
+
+// NewUpdater creates and returns a new Updater.
+func NewUpdater(iput db.DynamoItemPutter) Updater { return Updater{iput: iput} }
+
+// Update updates a session in the session table only if its current token ID
+// matches prevTokenID, so that the same refresh token can't be rotated twice.
+// It returns db.ErrNoItem if the session doesn't exist or was rotated already.
+func (u Updater) Update(
+	ctx context.Context, prevTokenID string, session Session,
+) error {
+	item, err := attributevalue.MarshalMap(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.iput.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(os.Getenv(tableName)),
+		Item:                item,
+		ConditionExpression: aws.String("TokenID = :prevTokenID"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":prevTokenID": &types.AttributeValueMemberS{Value: prevTokenID},
+		},
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	}
+
+	return err
+}
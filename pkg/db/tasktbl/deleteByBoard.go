@@ -0,0 +1,150 @@
+package tasktbl
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// maxBatchWrites is the maximum number of requests that DynamoDB accepts in a
+// single BatchWriteItem request.
+const maxBatchWrites = 25
+
+// maxWriteAttempts is the maximum number of times that a batch of delete
+// requests is sent before giving up on the ones that are left unprocessed.
+const maxWriteAttempts = 8
+
+// baseBackoff is how long to wait at most before sending the unprocessed
+// delete requests of a batch again for the first time. It doubles with each
+// attempt after that.
+const baseBackoff = 50 * time.Millisecond
+
+// ErrUnprocessed means that some of a board's tasks were left unprocessed by
+// DynamoDB after maxWriteAttempts attempts at deleting them.
+var ErrUnprocessed = errors.New("tasks left unprocessed")
+
+// DeleterByBoard can be used to delete all tasks of a board from the task
+// table.
+type DeleterByBoard struct {
+	qbw     db.DynamoQueryBatchWriter
+	backoff time.Duration
+}
+
+// NewDeleterByBoard creates and returns a new DeleterByBoard.
+func NewDeleterByBoard(qbw db.DynamoQueryBatchWriter) DeleterByBoard {
+	return DeleterByBoard{qbw: qbw, backoff: baseBackoff}
+}
+
+// Delete deletes all tasks of the board with the given ID that belong to the
+// team with the given ID from the task table in batches. Deleting tasks that
+// were already deleted is not an error, so it can be called again to delete
+// the tasks that are left over if it fails part of the way through.
+func (d DeleterByBoard) Delete(
+	ctx context.Context, teamID, boardID string,
+) error {
+	keyCond := expression.Key("BoardID").Equal(expression.Value(boardID))
+	filter := expression.Name("TeamID").Equal(expression.Value(teamID))
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(keyCond).
+		WithFilter(filter).
+		WithProjection(expression.NamesList(
+			expression.Name("TeamID"), expression.Name("ID"),
+		)).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	// find the keys of all of the board's tasks, going through every page of
+	// the results since a board may have more tasks than fit in a single one
+	table := os.Getenv(tableName)
+	var (
+		tasks    []Task
+		startKey map[string]types.AttributeValue
+	)
+	for {
+		out, err := d.qbw.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(table),
+			IndexName:                 aws.String("BoardID-index"),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			KeyConditionExpression:    expr.KeyCondition(),
+			FilterExpression:          expr.Filter(),
+			ProjectionExpression:      expr.Projection(),
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		var page []Task
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return err
+		}
+		tasks = append(tasks, page...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = out.LastEvaluatedKey
+	}
+
+	for start := 0; start < len(tasks); start += maxBatchWrites {
+		end := min(start+maxBatchWrites, len(tasks))
+		reqs := make([]types.WriteRequest, 0, end-start)
+		for _, t := range tasks[start:end] {
+			reqs = append(reqs, types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{
+					Key: map[string]types.AttributeValue{
+						"TeamID": &types.AttributeValueMemberS{
+							Value: t.TeamID,
+						},
+						"ID": &types.AttributeValueMemberS{Value: t.ID},
+					},
+				},
+			})
+		}
+
+		// DynamoDB may not process all requests at once, in which case the
+		// unprocessed ones are sent again after an exponential backoff with
+		// jitter so that throttled deletes don't retry in lockstep
+		for attempt := 0; len(reqs) > 0; attempt++ {
+			if attempt == maxWriteAttempts {
+				return ErrUnprocessed
+			}
+			if attempt > 0 {
+				wait := d.backoff << (attempt - 1)
+				select {
+				case <-time.After(time.Duration(rand.Int63n(int64(wait)) + 1)):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			out, err := d.qbw.BatchWriteItem(
+				ctx, &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]types.WriteRequest{
+						table: reqs,
+					},
+				},
+			)
+			if err != nil {
+				return err
+			}
+			reqs = out.UnprocessedItems[table]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+//go:build utest
+
+package tasktbl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestDeleterByBoard(t *testing.T) {
+	qbw := &db.FakeDynamoQueryBatchWriter{}
+	sut := NewDeleterByBoard(qbw)
+	sut.backoff = time.Millisecond
+
+	errQuery := errors.New("failed to query")
+	errWrite := errors.New("failed to batch write")
+	key := map[string]types.AttributeValue{
+		"TeamID": &types.AttributeValueMemberS{Value: "teamid"},
+		"ID":     &types.AttributeValueMemberS{Value: "taskid"},
+	}
+	outQuery := &dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{key},
+	}
+
+	for _, c := range []struct {
+		name     string
+		outQuery *dynamodb.QueryOutput
+		errQuery error
+		outWrite *dynamodb.BatchWriteItemOutput
+		errWrite error
+		wantErr  error
+	}{
+		{
+			name:     "ErrQuery",
+			outQuery: nil,
+			errQuery: errQuery,
+			outWrite: nil,
+			errWrite: nil,
+			wantErr:  errQuery,
+		},
+		{
+			name:     "ErrWrite",
+			outQuery: outQuery,
+			errQuery: nil,
+			outWrite: nil,
+			errWrite: errWrite,
+			wantErr:  errWrite,
+		},
+		{
+			name:     "ErrUnprocessed",
+			outQuery: outQuery,
+			errQuery: nil,
+			outWrite: &dynamodb.BatchWriteItemOutput{
+				UnprocessedItems: map[string][]types.WriteRequest{
+					os.Getenv(tableName): {
+						{DeleteRequest: &types.DeleteRequest{Key: key}},
+					},
+				},
+			},
+			errWrite: nil,
+			wantErr:  ErrUnprocessed,
+		},
+		{
+			name:     "NoTasks",
+			outQuery: &dynamodb.QueryOutput{},
+			errQuery: nil,
+			outWrite: nil,
+			errWrite: errWrite,
+			wantErr:  nil,
+		},
+		{
+			name:     "OK",
+			outQuery: outQuery,
+			errQuery: nil,
+			outWrite: &dynamodb.BatchWriteItemOutput{},
+			errWrite: nil,
+			wantErr:  nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			qbw.OutQuery = c.outQuery
+			qbw.ErrQuery = c.errQuery
+			qbw.OutWrite = c.outWrite
+			qbw.ErrWrite = c.errWrite
+
+			err := sut.Delete(context.Background(), "teamid", "boardid")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -15,17 +15,22 @@ import (
 // Retriever can be used to retrieve by ID a task from the task table.
 type Retriever struct{ iget db.DynamoItemGetter }
 
-// NewRetriever creates and returns a new Getter.
+// NewRetriever creates and returns a new Retriever.
 func NewRetriever(iget db.DynamoItemGetter) Retriever {
 	return Retriever{iget: iget}
 }
 
-// Retrieve retrieves by ID a task from the task table.
-func (r Retriever) Retrieve(ctx context.Context, id string) (Task, error) {
+// Retrieve retrieves the task with the given ID that belongs to the team with
+// the given ID from the task table. It returns db.ErrNoItem if the team has no
+// such task.
+func (r Retriever) Retrieve(
+	ctx context.Context, teamID, id string,
+) (Task, error) {
 	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(os.Getenv(tableName)),
 		Key: map[string]types.AttributeValue{
-			"ID": &types.AttributeValueMemberS{Value: id},
+			"TeamID": &types.AttributeValueMemberS{Value: teamID},
+			"ID":     &types.AttributeValueMemberS{Value: id},
 		},
 	})
 	if err != nil {
@@ -36,6 +41,9 @@ func (r Retriever) Retrieve(ctx context.Context, id string) (Task, error) {
 	}
 
 	var task Task
-	err = attributevalue.UnmarshalMap(out.Item, &task)
-	return task, err
+	if err = attributevalue.UnmarshalMap(out.Item, &task); err != nil {
+		return Task{}, err
+	}
+	task.resolveColID()
+	return task, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/kxplxn/goteam/pkg/db"
 )
@@ -31,18 +32,40 @@ func (r RetrieverByBoard) Retrieve(
 		return nil, err
 	}
 
-	out, err := r.queryer.Query(ctx, &dynamodb.QueryInput{
-		TableName:                 aws.String(os.Getenv(tableName)),
-		IndexName:                 aws.String("BoardID-index"),
-		ExpressionAttributeNames:  expr.Names(),
-		ExpressionAttributeValues: expr.Values(),
-		KeyConditionExpression:    expr.KeyCondition(),
-	})
-	if err != nil {
-		return nil, err
-	}
+	// go through every page of the results since a board may have more tasks
+	// than fit in a single one
+	var (
+		tasks    []Task
+		startKey map[string]types.AttributeValue
+	)
+	for {
+		out, err := r.queryer.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(os.Getenv(tableName)),
+			IndexName:                 aws.String("BoardID-index"),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	var tasks []Task
-	err = attributevalue.UnmarshalListOfMaps(out.Items, &tasks)
-	return tasks, err
+		var page []Task
+		if err = attributevalue.UnmarshalListOfMaps(
+			out.Items, &page,
+		); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = out.LastEvaluatedKey
+	}
+	for i := range tasks {
+		tasks[i].resolveColID()
+	}
+	return tasks, nil
 }
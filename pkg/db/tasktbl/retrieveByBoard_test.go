@@ -16,7 +16,7 @@ import (
 )
 
 func TestRetrieverByBoard(t *testing.T) {
-	queryer := &db.FakeDynamoQueryer{}
+	queryer := &db.FakeDynamoPagedQueryer{}
 	sut := NewRetrieverByBoard(queryer)
 
 	errA := errors.New("failed")
@@ -25,7 +25,7 @@ func TestRetrieverByBoard(t *testing.T) {
 			TeamID:      "577965d9-c7ba-4a18-ae7b-47d879b12879",
 			ID:          "8c5088eb-e86f-4371-86d0-da186dab78a7",
 			BoardID:     "19639b75-45ef-49aa-981e-346c15b0ffbf",
-			ColNo:       1,
+			ColID:       "ready",
 			Title:       "Do something!",
 			Description: "Do it!",
 			Order:       21,
@@ -38,7 +38,7 @@ func TestRetrieverByBoard(t *testing.T) {
 			TeamID:      "577965d9-c7ba-4a18-ae7b-47d879b12879",
 			ID:          "0c328813-e1b1-4371-86d2-da184d567877",
 			BoardID:     "19639b75-45ef-49aa-981e-346c15b0ffbf",
-			ColNo:       0,
+			ColID:       "inbox",
 			Title:       "Do something again!",
 			Description: "Dooooooo it!",
 			Order:       52,
@@ -49,82 +49,88 @@ func TestRetrieverByBoard(t *testing.T) {
 		},
 	}
 
+	item := func(t Task) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"TeamID":  &types.AttributeValueMemberN{Value: t.TeamID},
+			"BoardID": &types.AttributeValueMemberS{Value: t.BoardID},
+			"ColID":   &types.AttributeValueMemberS{Value: t.ColID},
+			"ID":      &types.AttributeValueMemberS{Value: t.ID},
+			"Title":   &types.AttributeValueMemberS{Value: t.Title},
+			"Description": &types.AttributeValueMemberS{
+				Value: t.Description,
+			},
+			"Order": &types.AttributeValueMemberN{
+				Value: strconv.Itoa(t.Order),
+			},
+			"Subtasks": &types.AttributeValueMemberL{
+				Value: []types.AttributeValue{
+					&types.AttributeValueMemberM{
+						Value: map[string]types.AttributeValue{
+							"Title": &types.AttributeValueMemberS{
+								Value: t.Subtasks[0].Title,
+							},
+							"IsDone": &types.AttributeValueMemberBOOL{
+								Value: t.Subtasks[0].IsDone,
+							},
+						},
+					},
+					&types.AttributeValueMemberM{
+						Value: map[string]types.AttributeValue{
+							"Title": &types.AttributeValueMemberS{
+								Value: t.Subtasks[1].Title,
+							},
+							"IsDone": &types.AttributeValueMemberBOOL{
+								Value: t.Subtasks[1].IsDone,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
 	for _, c := range []struct {
 		name      string
-		dqOut     *dynamodb.QueryOutput
+		dqPages   []*dynamodb.QueryOutput
 		dqErr     error
 		wantTasks []Task
 		wantErr   error
 	}{
 		{
 			name:      "Err",
-			dqOut:     nil,
+			dqPages:   nil,
 			dqErr:     errA,
 			wantTasks: []Task{},
 			wantErr:   errA,
 		},
 		{
 			name: "OK",
-			dqOut: func() *dynamodb.QueryOutput {
-				var out dynamodb.QueryOutput
-				for _, t := range someTasks {
-					out.Items = append(out.Items, map[string]types.
-						AttributeValue{
-						"TeamID": &types.AttributeValueMemberN{
-							Value: t.TeamID,
-						},
-						"BoardID": &types.AttributeValueMemberS{
-							Value: t.BoardID,
-						},
-						"ColNo": &types.AttributeValueMemberN{
-							Value: strconv.Itoa(t.ColNo),
-						},
-						"ID":    &types.AttributeValueMemberS{Value: t.ID},
-						"Title": &types.AttributeValueMemberS{Value: t.Title},
-						"Description": &types.AttributeValueMemberS{
-							Value: t.Description,
-						},
-						"Order": &types.AttributeValueMemberN{
-							Value: strconv.Itoa(t.Order),
-						},
-						"Subtasks": &types.AttributeValueMemberL{
-							Value: []types.AttributeValue{
-								&types.AttributeValueMemberM{
-									Value: map[string]types.AttributeValue{
-										"Title": &types.AttributeValueMemberS{
-											Value: t.Subtasks[0].Title,
-										},
-										"IsDone": &types.
-											AttributeValueMemberBOOL{
-											Value: t.Subtasks[0].IsDone,
-										},
-									},
-								},
-								&types.AttributeValueMemberM{
-									Value: map[string]types.AttributeValue{
-										"Title": &types.
-											AttributeValueMemberS{
-											Value: t.Subtasks[1].Title,
-										},
-										"IsDone": &types.
-											AttributeValueMemberBOOL{
-											Value: t.Subtasks[1].IsDone,
-										},
-									},
-								},
-							},
-						},
-					})
-				}
-				return &out
-			}(),
+			dqPages: []*dynamodb.QueryOutput{{
+				Items: []map[string]types.AttributeValue{
+					item(someTasks[0]), item(someTasks[1]),
+				},
+			}},
+			dqErr:     nil,
+			wantTasks: someTasks,
+			wantErr:   nil,
+		},
+		{
+			name: "OKPaged",
+			dqPages: []*dynamodb.QueryOutput{
+				{Items: []map[string]types.AttributeValue{
+					item(someTasks[0]),
+				}},
+				{Items: []map[string]types.AttributeValue{
+					item(someTasks[1]),
+				}},
+			},
 			dqErr:     nil,
 			wantTasks: someTasks,
 			wantErr:   nil,
 		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			queryer.Out = c.dqOut
+			queryer.Pages = c.dqPages
 			queryer.Err = c.dqErr
 
 			tasks, err := sut.Retrieve(context.Background(), "")
@@ -141,7 +147,7 @@ func TestRetrieverByBoard(t *testing.T) {
 				assert.Equal(t.Error, task.Order, wt.Order)
 				assert.Equal(t.Error, task.BoardID, wt.BoardID)
 				assert.Equal(t.Error,
-					task.ColNo, wt.ColNo,
+					task.ColID, wt.ColID,
 				)
 
 				for j, wst := range wt.Subtasks {
@@ -30,7 +30,7 @@ func TestRetriever(t *testing.T) {
 			{Title: "Do another thing", IsDone: false},
 		},
 		BoardID: "19639b75-45ef-49aa-981e-346c15b0ffbf",
-		ColNo:   1,
+		ColID:   "ready",
 	}
 
 	for _, c := range []struct {
@@ -93,8 +93,8 @@ func TestRetriever(t *testing.T) {
 					"BoardID": &types.AttributeValueMemberS{
 						Value: taskA.BoardID,
 					},
-					"ColNo": &types.AttributeValueMemberN{
-						Value: strconv.Itoa(taskA.ColNo),
+					"ColID": &types.AttributeValueMemberS{
+						Value: taskA.ColID,
 					},
 				},
 			},
@@ -102,12 +102,41 @@ func TestRetriever(t *testing.T) {
 			wantTask: &taskA,
 			wantErr:  nil,
 		},
+		{
+			name: "LegacyColNo",
+			igOut: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"ID":    &types.AttributeValueMemberS{Value: taskA.ID},
+					"Title": &types.AttributeValueMemberS{Value: taskA.Title},
+					"Description": &types.AttributeValueMemberS{
+						Value: taskA.Description,
+					},
+					"Order": &types.AttributeValueMemberN{
+						Value: strconv.Itoa(taskA.Order),
+					},
+					"BoardID": &types.AttributeValueMemberS{
+						Value: taskA.BoardID,
+					},
+					"ColNo": &types.AttributeValueMemberN{Value: "1"},
+				},
+			},
+			igErr: nil,
+			wantTask: &Task{
+				ID:          taskA.ID,
+				Title:       taskA.Title,
+				Description: taskA.Description,
+				Order:       taskA.Order,
+				BoardID:     taskA.BoardID,
+				ColID:       "ready",
+			},
+			wantErr: nil,
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			ig.Out = c.igOut
 			ig.Err = c.igErr
 
-			task, err := sut.Retrieve(context.Background(), "")
+			task, err := sut.Retrieve(context.Background(), "", "")
 
 			assert.Equal(t.Fatal, err, c.wantErr)
 			if c.wantTask != nil {
@@ -117,7 +146,7 @@ func TestRetriever(t *testing.T) {
 				assert.Equal(t.Error, task.Order, c.wantTask.Order)
 				assert.Equal(t.Error, task.BoardID, c.wantTask.BoardID)
 				assert.Equal(t.Error,
-					task.ColNo, c.wantTask.ColNo,
+					task.ColID, c.wantTask.ColID,
 				)
 
 				for i, wst := range c.wantTask.Subtasks {
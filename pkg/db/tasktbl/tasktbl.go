@@ -1,27 +1,34 @@
 // Package tasktbl contains code to interact with the task table in DynamoDB.
 package tasktbl
 
+import "github.com/kxplxn/goteam/pkg/db/teamtbl"
+
 // tableName is the name of the environment variable to retrieve the task
 // table's name from.
 const tableName = "TASK_TABLE_NAME"
 
 // Task defines the task entity - the primary entity of task domain.
+//
+// ColNo is only set on tasks that were created before boards had their own
+// columns, and is the position of the task's column in teamtbl.DefaultColumns.
+// It is turned into ColID when the task is retrieved.
 type Task struct {
 	TeamID      string    `json:"teamID"`  // guid
 	BoardID     string    `json:"boardID"` // guid
-	ColNo       int       `json:"colNo"`
+	ColID       string    `json:"colID"`
 	ID          string    `json:"id"` // guid
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Order       int       `json:"order"`
 	Subtasks    []Subtask `json:"subtasks"`
+	ColNo       int       `json:"-" dynamodbav:",omitempty"`
 }
 
 // NewTask creates and returns a new Task.
 func NewTask(
 	teamID string,
 	boardID string,
-	colNo int,
+	colID string,
 	id string,
 	title string,
 	descr string,
@@ -31,7 +38,7 @@ func NewTask(
 	return Task{
 		TeamID:      teamID,
 		BoardID:     boardID,
-		ColNo:       colNo,
+		ColID:       colID,
 		ID:          id,
 		Title:       title,
 		Description: descr,
@@ -40,6 +47,18 @@ func NewTask(
 	}
 }
 
+// resolveColID sets the ColID of a task that was created before boards had
+// their own columns based on its ColNo.
+func (t *Task) resolveColID() {
+	if t.ColID == "" {
+		cols := teamtbl.DefaultColumns()
+		if t.ColNo >= 0 && t.ColNo < len(cols) {
+			t.ColID = cols[t.ColNo].ID
+		}
+	}
+	t.ColNo = 0
+}
+
 // Subtask defines the subtask entity which a task may own one/many of.
 type Subtask struct {
 	Title  string `json:"title"`
@@ -0,0 +1,94 @@
+package teamtbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+// BoardArchiver is a type that can be used to archive and restore an item in a
+// team's boards.
+type BoardArchiver struct {
+	igetput db.DynamoItemGetPutter
+	limits  quota.Limits
+}
+
+// NewBoardArchiver creates and returns a new BoardArchiver. limits are the
+// quota limits for all teams, which the team's own overrides are applied to.
+func NewBoardArchiver(
+	igetput db.DynamoItemGetPutter, limits quota.Limits,
+) BoardArchiver {
+	return BoardArchiver{igetput: igetput, limits: limits}
+}
+
+// Archive archives the board with the given ID in the team with the given ID
+// if archived is true, and restores it otherwise. Archiving a board that is
+// already archived, or restoring one that isn't, does nothing. db.ErrNoItem is
+// returned if the team doesn't have the board, and db.ErrLimitReached is
+// returned if the board would be restored while the team has as many boards
+// that aren't archived as its quota allows.
+func (a BoardArchiver) Archive(
+	ctx context.Context, teamID, boardID string, archived bool,
+) error {
+	// get the existing team as-is
+	out, err := a.igetput.GetItem(ctx, &dynamodb.GetItemInput{
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: teamID},
+		},
+		TableName: aws.String(os.Getenv(tableName)),
+	})
+	if err != nil {
+		return err
+	}
+	if out.Item == nil {
+		return db.ErrNoItem
+	}
+
+	// unmarshal the team
+	var team Team
+	if err := attributevalue.UnmarshalMap(out.Item, &team); err != nil {
+		return err
+	}
+
+	// find the board and check the team has room for it if it's restored
+	i := -1
+	for j, b := range team.Boards {
+		if b.ID == boardID {
+			i = j
+			break
+		}
+	}
+	if i == -1 {
+		return db.ErrNoItem
+	}
+	if team.Boards[i].Archived == archived {
+		return nil
+	}
+	if !archived && !quota.Allows(
+		a.limits.Override(team.Quota).Boards, team.ActiveBoards(), 1,
+	) {
+		return db.ErrLimitReached
+	}
+	team.Boards[i].Archived = archived
+
+	// marshal the new team
+	newItem, err := attributevalue.MarshalMap(team)
+	if err != nil {
+		return err
+	}
+
+	// update the team based on the new team
+	_, err = a.igetput.PutItem(ctx, &dynamodb.PutItemInput{
+		Item:      newItem,
+		TableName: aws.String(os.Getenv(tableName)),
+	})
+
+	return err
+}
@@ -0,0 +1,141 @@
+//go:build utest
+
+package teamtbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/quota"
+)
+
+func TestBoardArchiver(t *testing.T) {
+	igetput := &db.FakeDynamoItemGetPutter{}
+	sut := NewBoardArchiver(igetput, quota.Limits{Boards: 1})
+
+	errA := errors.New("failed")
+	activeBoard := &types.AttributeValueMemberM{
+		Value: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: "activeBoardID"},
+		},
+	}
+	archivedBoard := &types.AttributeValueMemberM{
+		Value: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: "archivedBoardID"},
+			"Archived": &types.AttributeValueMemberBOOL{
+				Value: true,
+			},
+		},
+	}
+	itemA := map[string]types.AttributeValue{
+		"Boards": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{activeBoard, archivedBoard},
+		},
+	}
+	itemB := map[string]types.AttributeValue{
+		"Boards": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{archivedBoard},
+		},
+	}
+
+	for _, c := range []struct {
+		name       string
+		boardID    string
+		archived   bool
+		errGetItem error
+		outGetItem *dynamodb.GetItemOutput
+		errPutItem error
+		wantErr    error
+	}{
+		{
+			name:       "ErrGetItem",
+			boardID:    "activeBoardID",
+			archived:   true,
+			errGetItem: errA,
+			outGetItem: nil,
+			errPutItem: nil,
+			wantErr:    errA,
+		},
+		{
+			name:       "ErrNoItemTeam",
+			boardID:    "activeBoardID",
+			archived:   true,
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: nil},
+			errPutItem: nil,
+			wantErr:    db.ErrNoItem,
+		},
+		{
+			name:       "ErrNoItemBoard",
+			boardID:    "otherBoardID",
+			archived:   true,
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: nil,
+			wantErr:    db.ErrNoItem,
+		},
+		{
+			name:       "AlreadyArchived",
+			boardID:    "archivedBoardID",
+			archived:   true,
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: errA,
+			wantErr:    nil,
+		},
+		{
+			name:       "ErrLimitReached",
+			boardID:    "archivedBoardID",
+			archived:   false,
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: nil,
+			wantErr:    db.ErrLimitReached,
+		},
+		{
+			name:       "ErrPutItem",
+			boardID:    "activeBoardID",
+			archived:   true,
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: errA,
+			wantErr:    errA,
+		},
+		{
+			name:       "OKArchive",
+			boardID:    "activeBoardID",
+			archived:   true,
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemA},
+			errPutItem: nil,
+			wantErr:    nil,
+		},
+		{
+			name:       "OKRestore",
+			boardID:    "archivedBoardID",
+			archived:   false,
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemB},
+			errPutItem: nil,
+			wantErr:    nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			igetput.ErrGet = c.errGetItem
+			igetput.OutGet = c.outGetItem
+			igetput.ErrPut = c.errPutItem
+
+			err := sut.Archive(
+				context.Background(), "teamID", c.boardID, c.archived,
+			)
+
+			assert.Equal(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -45,38 +45,21 @@ func (d BoardDeleter) Delete(
 		return err
 	}
 
-	// check there are boards to delete
-	if len(team.Boards) == 0 {
-		return db.ErrNoItem
-	}
-
-	// check board to be deleted exists and remove it from team's boards
-	var found bool
-	newTeam := Team{
-		ID:      team.ID,
-		Members: team.Members,
-		Boards:  make([]Board, len(team.Boards)-1),
-	}
-	if len(team.Boards) == 1 {
-		if team.Boards[0].ID == boardID {
-			found = true
-		}
-	} else {
-		for i := 0; i < len(team.Boards)-1; i++ {
-			if team.Boards[i].ID == boardID {
-				found = true
-				i--
-				continue
-			}
-			newTeam.Boards[i] = team.Boards[i]
+	// remove the board to be deleted from the team's boards, keeping the rest
+	// of the team as-is
+	boards := make([]Board, 0, len(team.Boards))
+	for _, b := range team.Boards {
+		if b.ID != boardID {
+			boards = append(boards, b)
 		}
 	}
-	if !found {
+	if len(boards) == len(team.Boards) {
 		return db.ErrNoItem
 	}
+	team.Boards = boards
 
 	// marshal the new team
-	newItem, err := attributevalue.MarshalMap(newTeam)
+	newItem, err := attributevalue.MarshalMap(team)
 	if err != nil {
 		return err
 	}
@@ -36,6 +36,28 @@ func TestBoardDeleter(t *testing.T) {
 		},
 	}
 
+	itemB := map[string]types.AttributeValue{
+		"Owner": &types.AttributeValueMemberS{Value: "teamOwner"},
+		"Boards": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberS{
+							Value: "boardID",
+						},
+					},
+				},
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberS{
+							Value: "otherBoardID",
+						},
+					},
+				},
+			},
+		},
+	}
+
 	for _, c := range []struct {
 		name       string
 		errGetItem error
@@ -70,6 +92,27 @@ func TestBoardDeleter(t *testing.T) {
 			errPutItem: nil,
 			wantErr:    db.ErrNoItem,
 		},
+		{
+			name:       "ErrNoItemBoardOther",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Boards": &types.AttributeValueMemberL{
+						Value: []types.AttributeValue{
+							&types.AttributeValueMemberM{
+								Value: map[string]types.AttributeValue{
+									"ID": &types.AttributeValueMemberS{
+										Value: "otherBoardID",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			errPutItem: nil,
+			wantErr:    db.ErrNoItem,
+		},
 		{
 			name:       "ErrPutItem",
 			errGetItem: nil,
@@ -84,6 +127,13 @@ func TestBoardDeleter(t *testing.T) {
 			errPutItem: nil,
 			wantErr:    nil,
 		},
+		{
+			name:       "OKMultipleBoards",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{Item: itemB},
+			errPutItem: nil,
+			wantErr:    nil,
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			igetput.ErrGet = c.errGetItem
@@ -10,18 +10,27 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 // BoardInserter is a type that can be used to insert an item into a team's
 // boards.
-type BoardInserter struct{ igetput db.DynamoItemGetPutter }
+type BoardInserter struct {
+	igetput db.DynamoItemGetPutter
+	limits  quota.Limits
+}
 
-// NewBoardInserter creates and returns a new BoardInserter.
-func NewBoardInserter(igetput db.DynamoItemGetPutter) BoardInserter {
-	return BoardInserter{igetput: igetput}
+// NewBoardInserter creates and returns a new BoardInserter. limits are the
+// quota limits for all teams, which the team's own overrides are applied to.
+func NewBoardInserter(
+	igetput db.DynamoItemGetPutter, limits quota.Limits,
+) BoardInserter {
+	return BoardInserter{igetput: igetput, limits: limits}
 }
 
 // Insert inserts the given board into the boards of the team with the given ID.
+// db.ErrLimitReached is returned if the team has as many boards that aren't
+// archived as its quota allows.
 func (i BoardInserter) Insert(
 	ctx context.Context, teamID string, board Board,
 ) error {
@@ -45,20 +54,14 @@ func (i BoardInserter) Insert(
 		return err
 	}
 
-	// check there are boards to delete
-	var dupKey bool
-	var count int
-	for _, b := range team.Boards {
-		if b.ID == board.ID {
-			dupKey = true
-			break
-		}
-		count++
-	}
-	if dupKey {
+	// check the board doesn't already exist and that the team has room for it
+	// - archived boards don't count towards the team's quota
+	if team.HasBoard(board.ID) {
 		return db.ErrDupKey
 	}
-	if count > 2 {
+	if !quota.Allows(
+		i.limits.Override(team.Quota).Boards, team.ActiveBoards(), 1,
+	) {
 		return db.ErrLimitReached
 	}
 
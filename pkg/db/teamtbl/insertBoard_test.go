@@ -12,11 +12,12 @@ import (
 
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/quota"
 )
 
 func TestBoardInserter(t *testing.T) {
 	igetput := &db.FakeDynamoItemGetPutter{}
-	sut := NewBoardInserter(igetput)
+	sut := NewBoardInserter(igetput, quota.Limits{Boards: 3})
 
 	errA := errors.New("failed")
 	itemA := map[string]types.AttributeValue{
@@ -36,6 +37,49 @@ func TestBoardInserter(t *testing.T) {
 		},
 	}
 
+	threeBoards := &types.AttributeValueMemberL{
+		Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{
+				Value: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{
+						Value: "board1",
+					},
+				},
+			},
+			&types.AttributeValueMemberM{
+				Value: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{
+						Value: "board2",
+					},
+				},
+			},
+			&types.AttributeValueMemberM{
+				Value: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{
+						Value: "board3",
+					},
+				},
+			},
+		},
+	}
+
+	threeBoardsOneArchived := &types.AttributeValueMemberL{
+		Value: []types.AttributeValue{
+			threeBoards.Value[0],
+			threeBoards.Value[1],
+			&types.AttributeValueMemberM{
+				Value: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{
+						Value: "board3",
+					},
+					"Archived": &types.AttributeValueMemberBOOL{
+						Value: true,
+					},
+				},
+			},
+		},
+	}
+
 	for _, c := range []struct {
 		name       string
 		errGetItem error
@@ -83,28 +127,22 @@ func TestBoardInserter(t *testing.T) {
 			errGetItem: nil,
 			outGetItem: &dynamodb.GetItemOutput{
 				Item: map[string]types.AttributeValue{
-					"Boards": &types.AttributeValueMemberL{
-						Value: []types.AttributeValue{
-							&types.AttributeValueMemberM{
-								Value: map[string]types.AttributeValue{
-									"ID": &types.AttributeValueMemberS{
-										Value: "board1",
-									},
-								},
-							},
-							&types.AttributeValueMemberM{
-								Value: map[string]types.AttributeValue{
-									"ID": &types.AttributeValueMemberS{
-										Value: "board2",
-									},
-								},
-							},
-							&types.AttributeValueMemberM{
-								Value: map[string]types.AttributeValue{
-									"ID": &types.AttributeValueMemberS{
-										Value: "board3",
-									},
-								},
+					"Boards": threeBoards,
+				},
+			},
+			errPutItem: nil,
+			wantErr:    db.ErrLimitReached,
+		},
+		{
+			name:       "ErrLimitReachedOverride",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Boards": itemA["Boards"],
+					"Quota": &types.AttributeValueMemberM{
+						Value: map[string]types.AttributeValue{
+							"Boards": &types.AttributeValueMemberN{
+								Value: "1",
 							},
 						},
 					},
@@ -113,6 +151,35 @@ func TestBoardInserter(t *testing.T) {
 			errPutItem: nil,
 			wantErr:    db.ErrLimitReached,
 		},
+		{
+			name:       "OKUnlimitedOverride",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Boards": threeBoards,
+					"Quota": &types.AttributeValueMemberM{
+						Value: map[string]types.AttributeValue{
+							"Boards": &types.AttributeValueMemberN{
+								Value: "-1",
+							},
+						},
+					},
+				},
+			},
+			errPutItem: nil,
+			wantErr:    nil,
+		},
+		{
+			name:       "OKArchivedNotCounted",
+			errGetItem: nil,
+			outGetItem: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Boards": threeBoardsOneArchived,
+				},
+			},
+			errPutItem: nil,
+			wantErr:    nil,
+		},
 		{
 			name:       "ErrPutItem",
 			errGetItem: nil,
@@ -1,28 +1,165 @@
 // Package teamtbl contains code to interact with the team table in DynamoDB.
 package teamtbl
 
+import "github.com/kxplxn/goteam/pkg/quota"
+
 // tableName is the name of the environment variable to retrieve the team
 // table's name from.
 const tableName = "TEAM_TABLE_NAME"
 
 // Team defines the team entity - the primary entity of team domain.
+//
+// Quota holds the limits that are overridden for the team, which replace the
+// ones configured for all teams.
 type Team struct {
-	ID      string   `json:"id"`      // admin's username
-	Members []string `json:"members"` // usernames
-	Boards  []Board  `json:"boards"`
+	ID         string       `json:"id"` // uuid
+	Name       string       `json:"name"`
+	Owner      string       `json:"owner"`   // owner's username
+	Members    []string     `json:"members"` // usernames
+	Boards     []Board      `json:"boards"`
+	RequireMFA bool         `json:"requireMFA" dynamodbav:",omitempty"`
+	Quota      quota.Limits `json:"-" dynamodbav:",omitempty"`
 }
 
 // NewTeam creates and returns a new team.
-func NewTeam(id string, members []string, boards []Board) Team {
-	return Team{ID: id, Members: members, Boards: boards}
+func NewTeam(
+	id, name, owner string, members []string, boards []Board,
+) Team {
+	return Team{
+		ID: id, Name: name, Owner: owner, Members: members, Boards: boards,
+	}
 }
 
 // Board defines the board entity which a team may own one/many of.
+//
+// Columns are in the order that they are displayed in. Boards that were created
+// before columns could be customised don't have any, and use DefaultColumns
+// instead.
+//
+// Archived boards are kept along with their tasks but they are hidden from the
+// team's boards, their tasks can't be changed, and they don't count towards the
+// team's board quota.
 type Board struct {
-	ID      string   `json:"id"` // uuid
-	Name    string   `json:"name"`
-	Members []string `json:"members"`
+	ID       string   `json:"id"` // uuid
+	Name     string   `json:"name"`
+	Members  []string `json:"members"`
+	Columns  []Column `json:"columns" dynamodbav:",omitempty"`
+	Archived bool     `json:"archived" dynamodbav:",omitempty"`
+}
+
+// NewBoard creates and returns a new board with the default columns.
+func NewBoard(id, name string) Board {
+	return Board{ID: id, Name: name, Columns: DefaultColumns()}
+}
+
+// ColumnsOrDefault returns the board's columns, or DefaultColumns if the board
+// doesn't have any.
+func (b Board) ColumnsOrDefault() []Column {
+	if len(b.Columns) == 0 {
+		return DefaultColumns()
+	}
+	return b.Columns
+}
+
+// Column defines a column of a board that the board's tasks are placed in.
+//
+// WIPLimit is the maximum number of tasks that the column can have unless the
+// user moving tasks into it explicitly overrides it. Zero means no limit.
+type Column struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	WIPLimit int    `json:"wipLimit" dynamodbav:",omitempty"`
+}
+
+// NewColumn creates and returns a new column.
+func NewColumn(id, name, color string, wipLimit int) Column {
+	return Column{ID: id, Name: name, Color: color, WIPLimit: wipLimit}
+}
+
+// DefaultColumns returns the columns that new boards start with. They are also
+// the columns that tasks' column numbers referred to, in the same order, before
+// columns could be customised, so their IDs must never change.
+func DefaultColumns() []Column {
+	return []Column{
+		{ID: "inbox", Name: "Inbox"},
+		{ID: "ready", Name: "Ready"},
+		{ID: "go", Name: "Go!"},
+		{ID: "done", Name: "Done"},
+	}
 }
 
-// NewBoard creates and returns a new board.
-func NewBoard(id, name string) Board { return Board{ID: id, Name: name} }
+// HasBoard returns whether the team has a board with the given ID.
+func (t Team) HasBoard(boardID string) bool {
+	for _, b := range t.Boards {
+		if b.ID == boardID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBoardArchived returns whether the team's board with the given ID is
+// archived. It returns false if the team doesn't have a board with that ID.
+func (t Team) IsBoardArchived(boardID string) bool {
+	for _, b := range t.Boards {
+		if b.ID == boardID {
+			return b.Archived
+		}
+	}
+	return false
+}
+
+// ActiveBoards returns the number of the team's boards that aren't archived.
+func (t Team) ActiveBoards() int {
+	var n int
+	for _, b := range t.Boards {
+		if !b.Archived {
+			n++
+		}
+	}
+	return n
+}
+
+// HasColumn returns whether the team's board with the given ID has a column
+// with the given ID. It returns false if the team doesn't have a board with
+// that ID.
+func (t Team) HasColumn(boardID, colID string) bool {
+	_, ok := t.Column(boardID, colID)
+	return ok
+}
+
+// Column returns the column with the given ID on the team's board with the
+// given ID, and whether it was found.
+func (t Team) Column(boardID, colID string) (Column, bool) {
+	for _, b := range t.Boards {
+		if b.ID != boardID {
+			continue
+		}
+		for _, c := range b.ColumnsOrDefault() {
+			if c.ID == colID {
+				return c, true
+			}
+		}
+		return Column{}, false
+	}
+	return Column{}, false
+}
+
+// IsBoardMember returns whether the user with the given username is a member of
+// the team's board with the given ID. It returns false if the team doesn't have
+// a board with that ID.
+func (t Team) IsBoardMember(boardID, username string) bool {
+	for _, b := range t.Boards {
+		if b.ID != boardID {
+			continue
+		}
+		for _, m := range b.Members {
+			if m == username {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+//go:build utest
+
+package teamtbl
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestHasBoard(t *testing.T) {
+	team := Team{Boards: []Board{{ID: "board1"}, {ID: "board2"}}}
+
+	assert.True(t.Error, team.HasBoard("board1"))
+	assert.True(t.Error, team.HasBoard("board2"))
+	assert.True(t.Error, !team.HasBoard("board3"))
+}
+
+func TestIsBoardMember(t *testing.T) {
+	team := Team{Boards: []Board{
+		{ID: "board1", Members: []string{"bob123", "alice"}},
+		{ID: "board2", Members: []string{"carol"}},
+	}}
+
+	assert.True(t.Error, team.IsBoardMember("board1", "alice"))
+	assert.True(t.Error, team.IsBoardMember("board2", "carol"))
+	assert.True(t.Error, !team.IsBoardMember("board2", "alice"))
+	assert.True(t.Error, !team.IsBoardMember("board3", "alice"))
+}
+
+func TestIsBoardArchived(t *testing.T) {
+	team := Team{Boards: []Board{
+		{ID: "board1", Archived: true},
+		{ID: "board2"},
+	}}
+
+	assert.True(t.Error, team.IsBoardArchived("board1"))
+	assert.True(t.Error, !team.IsBoardArchived("board2"))
+	assert.True(t.Error, !team.IsBoardArchived("board3"))
+}
+
+func TestActiveBoards(t *testing.T) {
+	team := Team{Boards: []Board{
+		{ID: "board1", Archived: true},
+		{ID: "board2"},
+		{ID: "board3"},
+	}}
+
+	assert.Equal(t.Error, team.ActiveBoards(), 2)
+	assert.Equal(t.Error, Team{}.ActiveBoards(), 0)
+}
+
+func TestHasColumn(t *testing.T) {
+	team := Team{Boards: []Board{
+		{ID: "board1", Columns: []Column{{ID: "col1"}, {ID: "col2"}}},
+		{ID: "board2"},
+	}}
+
+	assert.True(t.Error, team.HasColumn("board1", "col2"))
+	assert.True(t.Error, !team.HasColumn("board1", "inbox"))
+	assert.True(t.Error, team.HasColumn("board2", "inbox"))
+	assert.True(t.Error, !team.HasColumn("board2", "col1"))
+	assert.True(t.Error, !team.HasColumn("board3", "inbox"))
+}
+
+func TestColumn(t *testing.T) {
+	col2 := NewColumn("col2", "Doing", "", 3)
+	team := Team{Boards: []Board{
+		{ID: "board1", Columns: []Column{{ID: "col1"}, col2}},
+		{ID: "board2"},
+	}}
+
+	col, ok := team.Column("board1", "col2")
+	assert.True(t.Error, ok)
+	assert.Equal(t.Error, col, col2)
+
+	col, ok = team.Column("board2", "go")
+	assert.True(t.Error, ok)
+	assert.Equal(t.Error, col, DefaultColumns()[2])
+
+	_, ok = team.Column("board1", "inbox")
+	assert.True(t.Error, !ok)
+
+	_, ok = team.Column("board3", "col1")
+	assert.True(t.Error, !ok)
+}
+
+func TestColumnsOrDefault(t *testing.T) {
+	cols := []Column{{ID: "col1", Name: "To Do", Color: "#ff0000"}}
+	assert.AllEqual(t.Error, Board{Columns: cols}.ColumnsOrDefault(), cols)
+	assert.AllEqual(t.Error, Board{}.ColumnsOrDefault(), DefaultColumns())
+	assert.AllEqual(t.Error, NewBoard("", "").Columns, DefaultColumns())
+}
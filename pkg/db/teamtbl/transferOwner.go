@@ -0,0 +1,141 @@
+package teamtbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+// OwnerTransferer can be used to transfer the ownership of a team to another
+// one of its members.
+type OwnerTransferer struct{ twu db.DynamoTransactWriteUpdater }
+
+// NewOwnerTransferer creates and returns a new OwnerTransferer.
+func NewOwnerTransferer(twu db.DynamoTransactWriteUpdater) OwnerTransferer {
+	return OwnerTransferer{twu: twu}
+}
+
+// Transfer makes the member with username "to" the owner of the team with the
+// given ID and demotes its current owner with username "from" to admin. The
+// team and both memberships are updated in a single transaction so that the
+// team never ends up with zero or two owners. It returns db.ErrNoItem if from
+// is not the team's owner, or if either user is not a member of the team.
+//
+// The role of each user is also updated in the user table if the team is their
+// active team, so that it is picked up the next time their auth token is
+// issued.
+func (t OwnerTransferer) Transfer(
+	ctx context.Context, teamID, from, to string,
+) error {
+	var (
+		teamTableName       = os.Getenv(tableName)
+		membershipTableName = os.Getenv("MEMBERSHIP_TABLE_NAME")
+		teamIDAttr          = &types.AttributeValueMemberS{Value: teamID}
+	)
+
+	_, err := t.twu.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Update: &types.Update{
+				TableName: &teamTableName,
+				Key: map[string]types.AttributeValue{
+					"ID": teamIDAttr,
+				},
+				UpdateExpression: aws.String("SET #owner = :to"),
+				ConditionExpression: aws.String(
+					"#owner = :from AND contains(Members, :to)",
+				),
+				ExpressionAttributeNames: map[string]string{
+					"#owner": "Owner",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":from": &types.AttributeValueMemberS{Value: from},
+					":to":   &types.AttributeValueMemberS{Value: to},
+				},
+			}},
+			{Update: membershipRoleUpdate(
+				membershipTableName, to, teamIDAttr, role.Owner,
+			)},
+			{Update: membershipRoleUpdate(
+				membershipTableName, from, teamIDAttr, role.Admin,
+			)},
+		},
+	})
+
+	var ex *types.TransactionCanceledException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	} else if err != nil {
+		return err
+	}
+
+	for username, r := range map[string]role.Role{
+		to: role.Owner, from: role.Admin,
+	} {
+		if err = t.updateUserRole(ctx, username, teamIDAttr, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// membershipRoleUpdate returns an update that sets the role of the user with
+// the given username in the team with the given ID as long as they are still a
+// member of it.
+func membershipRoleUpdate(
+	tableName, username string, teamID types.AttributeValue, r role.Role,
+) *types.Update {
+	return &types.Update{
+		TableName: &tableName,
+		Key: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: username},
+			"TeamID":   teamID,
+		},
+		UpdateExpression:    aws.String("SET #role = :role"),
+		ConditionExpression: aws.String("attribute_exists(TeamID)"),
+		ExpressionAttributeNames: map[string]string{
+			"#role": "Role",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":role": &types.AttributeValueMemberS{Value: string(r)},
+		},
+	}
+}
+
+// updateUserRole sets the role of the user with the given username in the user
+// table if the team with the given ID is their active team.
+func (t OwnerTransferer) updateUserRole(
+	ctx context.Context,
+	username string,
+	teamID types.AttributeValue,
+	r role.Role,
+) error {
+	_, err := t.twu.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(os.Getenv("USER_TABLE_NAME")),
+		Key: map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: username},
+		},
+		UpdateExpression:    aws.String("SET #role = :role"),
+		ConditionExpression: aws.String("TeamID = :teamID"),
+		ExpressionAttributeNames: map[string]string{
+			"#role": "Role",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":role":   &types.AttributeValueMemberS{Value: string(r)},
+			":teamID": teamID,
+		},
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return nil
+	}
+
+	return err
+}
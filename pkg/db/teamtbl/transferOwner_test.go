@@ -0,0 +1,61 @@
+//go:build utest
+
+package teamtbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestOwnerTransferer(t *testing.T) {
+	twu := &db.FakeDynamoTransactWriteUpdater{}
+	sut := NewOwnerTransferer(twu)
+
+	errA := errors.New("failed to transact write items")
+	errB := errors.New("failed to update item")
+
+	for _, c := range []struct {
+		name    string
+		twErr   error
+		uErr    error
+		wantErr error
+	}{
+		{name: "ErrTransactWrite", twErr: errA, uErr: nil, wantErr: errA},
+		{
+			name: "NoItem",
+			twErr: &smithy.OperationError{
+				Err: &types.TransactionCanceledException{},
+			},
+			uErr:    nil,
+			wantErr: db.ErrNoItem,
+		},
+		{name: "ErrUpdate", twErr: nil, uErr: errB, wantErr: errB},
+		{
+			name:  "OtherActiveTeam",
+			twErr: nil,
+			uErr: &smithy.OperationError{
+				Err: &types.ConditionalCheckFailedException{},
+			},
+			wantErr: nil,
+		},
+		{name: "OK", twErr: nil, uErr: nil, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			twu.ErrTransactWrite = c.twErr
+			twu.ErrUpdate = c.uErr
+
+			err := sut.Transfer(
+				context.Background(), "teamid", "bob123", "bob124",
+			)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -21,7 +21,9 @@ func NewBoardUpdater(igetput db.DynamoItemGetPutter) BoardUpdater {
 	return BoardUpdater{igetput: igetput}
 }
 
-// Update updates a board in the boards of the team with the given ID.
+// Update updates a board in the boards of the team with the given ID. The
+// board's existing columns are kept if the given board doesn't have any. The
+// board's archived state is always kept since it is changed by BoardArchiver.
 func (d BoardUpdater) Update(
 	ctx context.Context, teamID string, board Board,
 ) error {
@@ -54,6 +56,10 @@ func (d BoardUpdater) Update(
 	var found bool
 	for i, b := range team.Boards {
 		if b.ID == board.ID {
+			if len(board.Columns) == 0 {
+				board.Columns = b.Columns
+			}
+			board.Archived = b.Archived
 			team.Boards[i] = board
 			found = true
 			break
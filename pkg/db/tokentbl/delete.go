@@ -0,0 +1,73 @@
+package tokentbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Deleter can be used to delete a user's personal access token from the token
+// table by its name.
+type Deleter struct{ qdel db.DynamoQueryDeleter }
+
+// NewDeleter creates and returns a new Deleter.
+func NewDeleter(qdel db.DynamoQueryDeleter) Deleter {
+	return Deleter{qdel: qdel}
+}
+
+// Delete deletes the personal access token of the given user with the given
+// name from the token table. It returns db.ErrNoItem if the user has no token
+// with that name.
+func (d Deleter) Delete(ctx context.Context, username, name string) error {
+	keyCond := expression.Key("Username").Equal(expression.Value(username))
+	filt := expression.Name("Name").Equal(expression.Value(name))
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(keyCond).
+		WithFilter(filt).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	tableName := os.Getenv(tableName)
+	out, err := d.qdel.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		IndexName:                 aws.String("Username-index"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var tokens []Token
+	if err = attributevalue.UnmarshalListOfMaps(
+		out.Items, &tokens,
+	); err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return db.ErrNoItem
+	}
+
+	for _, t := range tokens {
+		if _, err = d.qdel.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberS{Value: t.ID},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+//go:build utest
+
+package tokentbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestDeleter(t *testing.T) {
+	qdel := &db.FakeDynamoQueryDeleter{}
+	sut := NewDeleter(qdel)
+
+	errQuery := errors.New("failed to query")
+	errDelete := errors.New("failed to delete item")
+	outQuery := &dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{
+			{
+				"ID":       &types.AttributeValueMemberS{Value: "tokenhash"},
+				"Username": &types.AttributeValueMemberS{Value: "bob123"},
+				"Name":     &types.AttributeValueMemberS{Value: "ci"},
+			},
+		},
+	}
+
+	for _, c := range []struct {
+		name      string
+		outQuery  *dynamodb.QueryOutput
+		errQuery  error
+		errDelete error
+		wantErr   error
+	}{
+		{
+			name:      "ErrQuery",
+			outQuery:  nil,
+			errQuery:  errQuery,
+			errDelete: nil,
+			wantErr:   errQuery,
+		},
+		{
+			name:      "NoItem",
+			outQuery:  &dynamodb.QueryOutput{},
+			errQuery:  nil,
+			errDelete: nil,
+			wantErr:   db.ErrNoItem,
+		},
+		{
+			name:      "ErrDelete",
+			outQuery:  outQuery,
+			errQuery:  nil,
+			errDelete: errDelete,
+			wantErr:   errDelete,
+		},
+		{
+			name:      "OK",
+			outQuery:  outQuery,
+			errQuery:  nil,
+			errDelete: nil,
+			wantErr:   nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			qdel.OutQuery = c.outQuery
+			qdel.ErrQuery = c.errQuery
+			qdel.ErrDelete = c.errDelete
+
+			err := sut.Delete(context.Background(), "bob123", "ci")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package tokentbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Retriever can be used to retrieve by ID a personal access token from the
+// token table.
+type Retriever struct{ iget db.DynamoItemGetter }
+
+// NewRetriever creates and returns a new Retriever.
+func NewRetriever(iget db.DynamoItemGetter) Retriever {
+	return Retriever{iget: iget}
+}
+
+// Retrieve retrieves by ID a personal access token from the token table.
+func (r Retriever) Retrieve(ctx context.Context, id string) (Token, error) {
+	out, err := r.iget.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(os.Getenv(tableName)),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return Token{}, err
+	}
+	if out.Item == nil {
+		return Token{}, db.ErrNoItem
+	}
+
+	var token Token
+	if err = attributevalue.UnmarshalMap(out.Item, &token); err != nil {
+		return Token{}, err
+	}
+	return token, nil
+}
@@ -0,0 +1,49 @@
+package tokentbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// RetrieverByUser can be used to retrieve all personal access tokens of a user
+// from the token table.
+type RetrieverByUser struct{ queryer db.DynamoQueryer }
+
+// NewRetrieverByUser creates and returns a new RetrieverByUser.
+func NewRetrieverByUser(queryer db.DynamoQueryer) RetrieverByUser {
+	return RetrieverByUser{queryer: queryer}
+}
+
+// Retrieve retrieves all personal access tokens of a user from the token
+// table.
+func (r RetrieverByUser) Retrieve(
+	ctx context.Context, username string,
+) ([]Token, error) {
+	keyCond := expression.Key("Username").Equal(expression.Value(username))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := r.queryer.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(os.Getenv(tableName)),
+		IndexName:                 aws.String("Username-index"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := []Token{}
+	err = attributevalue.UnmarshalListOfMaps(out.Items, &tokens)
+	return tokens, err
+}
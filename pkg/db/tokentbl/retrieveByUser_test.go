@@ -0,0 +1,78 @@
+//go:build utest
+
+package tokentbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetrieverByUser(t *testing.T) {
+	queryer := &db.FakeDynamoQueryer{}
+	sut := NewRetrieverByUser(queryer)
+
+	errA := errors.New("failed to query")
+
+	for _, c := range []struct {
+		name      string
+		qOut      *dynamodb.QueryOutput
+		qErr      error
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "Err",
+			qOut:      nil,
+			qErr:      errA,
+			wantNames: []string{},
+			wantErr:   errA,
+		},
+		{
+			name:      "NoItems",
+			qOut:      &dynamodb.QueryOutput{},
+			qErr:      nil,
+			wantNames: []string{},
+			wantErr:   nil,
+		},
+		{
+			name: "OK",
+			qOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					{
+						"ID":       &types.AttributeValueMemberS{Value: "a"},
+						"Username": &types.AttributeValueMemberS{Value: "bob"},
+						"Name":     &types.AttributeValueMemberS{Value: "ci"},
+					},
+					{
+						"ID":       &types.AttributeValueMemberS{Value: "b"},
+						"Username": &types.AttributeValueMemberS{Value: "bob"},
+						"Name":     &types.AttributeValueMemberS{Value: "cli"},
+					},
+				},
+			},
+			qErr:      nil,
+			wantNames: []string{"ci", "cli"},
+			wantErr:   nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			queryer.Out = c.qOut
+			queryer.Err = c.qErr
+
+			tokens, err := sut.Retrieve(context.Background(), "bob")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Fatal, len(tokens), len(c.wantNames))
+			for i, name := range c.wantNames {
+				assert.Equal(t.Error, tokens[i].Name, name)
+			}
+		})
+	}
+}
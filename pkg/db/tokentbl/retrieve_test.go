@@ -0,0 +1,99 @@
+//go:build utest
+
+package tokentbl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestRetriever(t *testing.T) {
+	ig := &db.FakeDynamoItemGetter{}
+	sut := NewRetriever(ig)
+
+	tokenA := Token{
+		ID:        "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61",
+		Username:  "bob123",
+		Name:      "ci",
+		Scopes:    []string{"tasks:read"},
+		CreatedAt: 1700000000,
+		ExpiresAt: 1800000000,
+	}
+	errA := errors.New("failed to get item")
+
+	for _, c := range []struct {
+		name      string
+		igOut     *dynamodb.GetItemOutput
+		igErr     error
+		wantToken *Token
+		wantErr   error
+	}{
+		{
+			name:      "Err",
+			igOut:     nil,
+			igErr:     errA,
+			wantToken: nil,
+			wantErr:   errA,
+		},
+		{
+			name:      "NoItem",
+			igOut:     &dynamodb.GetItemOutput{Item: nil},
+			igErr:     nil,
+			wantToken: nil,
+			wantErr:   db.ErrNoItem,
+		},
+		{
+			name: "OK",
+			igOut: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: tokenA.ID},
+					"Username": &types.AttributeValueMemberS{
+						Value: tokenA.Username,
+					},
+					"Name": &types.AttributeValueMemberS{Value: tokenA.Name},
+					"Scopes": &types.AttributeValueMemberL{
+						Value: []types.AttributeValue{
+							&types.AttributeValueMemberS{
+								Value: tokenA.Scopes[0],
+							},
+						},
+					},
+					"CreatedAt": &types.AttributeValueMemberN{
+						Value: strconv.FormatInt(tokenA.CreatedAt, 10),
+					},
+					"ExpiresAt": &types.AttributeValueMemberN{
+						Value: strconv.FormatInt(tokenA.ExpiresAt, 10),
+					},
+				},
+			},
+			igErr:     nil,
+			wantToken: &tokenA,
+			wantErr:   nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ig.Out = c.igOut
+			ig.Err = c.igErr
+
+			token, err := sut.Retrieve(context.Background(), "")
+
+			assert.Equal(t.Fatal, err, c.wantErr)
+			if c.wantToken != nil {
+				assert.Equal(t.Error, token.ID, c.wantToken.ID)
+				assert.Equal(t.Error, token.Username, c.wantToken.Username)
+				assert.Equal(t.Error, token.Name, c.wantToken.Name)
+				assert.AllEqual(t.Error, token.Scopes, c.wantToken.Scopes)
+				assert.Equal(t.Error, token.CreatedAt, c.wantToken.CreatedAt)
+				assert.Equal(t.Error, token.ExpiresAt, c.wantToken.ExpiresAt)
+			}
+		})
+	}
+}
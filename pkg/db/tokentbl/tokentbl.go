@@ -0,0 +1,52 @@
+// Package tokentbl contains code to interact with the personal access token
+// table in DynamoDB.
+package tokentbl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// tableName is the name of the environment variable to retrieve the personal
+// access token table's name from.
+const tableName = "TOKEN_TABLE_NAME"
+
+// Token defines the personal access token entity. Each token is created by a
+// user under a name that is unique among their tokens so that they can call
+// the APIs from scripts with it, only on the routes that its scopes allow. Only
+// the hash of the token is stored so that a leaked table cannot be used to
+// call the APIs.
+type Token struct {
+	ID        string
+	Username  string
+	Name      string
+	Scopes    []string
+	CreatedAt int64
+	ExpiresAt int64
+}
+
+// NewToken creates and returns a new Token.
+func NewToken(
+	id string,
+	username string,
+	name string,
+	scopes []string,
+	createdAt int64,
+	expiresAt int64,
+) Token {
+	return Token{
+		ID:        id,
+		Username:  username,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: createdAt,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a personal access token to
+// be used as the ID of the corresponding Token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,59 @@
+package usertbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Deleter can be used to delete by username a user from the user table.
+type Deleter struct{ tw db.DynamoTransactWriter }
+
+// NewDeleter creates and returns a new Deleter.
+func NewDeleter(tw db.DynamoTransactWriter) Deleter {
+	return Deleter{tw: tw}
+}
+
+// Delete deletes by username a user from the user table along with the item
+// that claims their username so that it can be taken again.
+func (d Deleter) Delete(ctx context.Context, username string) error {
+	table := os.Getenv(tableName)
+	_, err := d.tw.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Delete: &types.Delete{
+				TableName: &table,
+				Key: map[string]types.AttributeValue{
+					"Username": &types.AttributeValueMemberS{Value: username},
+				},
+				ConditionExpression: aws.String(
+					"attribute_exists(Username)",
+				),
+			}},
+			{Delete: &types.Delete{
+				TableName: &table,
+				Key: map[string]types.AttributeValue{
+					"Username": &types.AttributeValueMemberS{
+						Value: ClaimUsername(username),
+					},
+				},
+			}},
+		},
+	})
+
+	var ex *types.TransactionCanceledException
+	if errors.As(err, &ex) {
+		for _, r := range ex.CancellationReasons {
+			if aws.ToString(r.Code) == "ConditionalCheckFailed" {
+				return db.ErrNoItem
+			}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,52 @@
+//go:build utest
+
+package usertbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestDeleter(t *testing.T) {
+	tw := &db.FakeDynamoTransactWriter{}
+	sut := NewDeleter(tw)
+
+	errA := errors.New("failed to transact write items")
+
+	for _, c := range []struct {
+		name    string
+		twErr   error
+		wantErr error
+	}{
+		{name: "Err", twErr: errA, wantErr: errA},
+		{
+			name: "NoItem",
+			twErr: &smithy.OperationError{
+				Err: &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("ConditionalCheckFailed")},
+						{Code: aws.String("None")},
+					},
+				},
+			},
+			wantErr: db.ErrNoItem,
+		},
+		{name: "OK", twErr: nil, wantErr: nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			tw.Err = c.twErr
+
+			err := sut.Delete(context.Background(), "")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+		})
+	}
+}
@@ -14,29 +14,54 @@ import (
 )
 
 // Inserter can be used to insert a new user into the user table.
-type Inserter struct{ iput db.DynamoItemPutter }
+type Inserter struct{ tw db.DynamoTransactWriter }
 
 // NewInserter creates and returns a new Inserter.
-func NewInserter(iput db.DynamoItemPutter) Inserter {
-	return Inserter{iput: iput}
+func NewInserter(tw db.DynamoTransactWriter) Inserter {
+	return Inserter{tw: tw}
 }
 
-// Insert inserts a new user into the user table.
+// Insert inserts a new user into the user table along with the item that claims
+// their username in all casings. db.ErrDupKey is returned if the username is
+// already taken in any casing.
 func (i Inserter) Insert(ctx context.Context, user User) error {
+	user.UsernameLower = LowerUsername(user.Username)
 	item, err := attributevalue.MarshalMap(user)
 	if err != nil {
 		return err
 	}
 
-	_, err = i.iput.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName:           aws.String(os.Getenv(tableName)),
-		Item:                item,
-		ConditionExpression: aws.String("attribute_not_exists(Username)"),
+	table := os.Getenv(tableName)
+	_, err = i.tw.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName: &table,
+				Item:      item,
+				ConditionExpression: aws.String(
+					"attribute_not_exists(Username)",
+				),
+			}},
+			{Put: &types.Put{
+				TableName: &table,
+				Item: map[string]types.AttributeValue{
+					"Username": &types.AttributeValueMemberS{
+						Value: ClaimUsername(user.Username),
+					},
+				},
+				ConditionExpression: aws.String(
+					"attribute_not_exists(Username)",
+				),
+			}},
+		},
 	})
 
-	var ex *types.ConditionalCheckFailedException
+	var ex *types.TransactionCanceledException
 	if errors.As(err, &ex) {
-		return db.ErrDupKey
+		for _, r := range ex.CancellationReasons {
+			if aws.ToString(r.Code) == "ConditionalCheckFailed" {
+				return db.ErrDupKey
+			}
+		}
 	}
 
 	return err
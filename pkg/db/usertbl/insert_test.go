@@ -7,6 +7,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/smithy-go"
 
@@ -15,30 +16,56 @@ import (
 )
 
 func TestInserter(t *testing.T) {
-	ip := &db.FakeDynamoItemPutter{}
-	sut := NewInserter(ip)
+	tw := &db.FakeDynamoTransactWriter{}
+	sut := NewInserter(tw)
 
-	errA := errors.New("failed to put item")
+	errA := errors.New("failed to transact write items")
+	errB := &smithy.OperationError{
+		Err: &types.TransactionCanceledException{
+			CancellationReasons: []types.CancellationReason{
+				{Code: aws.String("None")},
+				{Code: aws.String("TransactionConflict")},
+			},
+		},
+	}
 
 	for _, c := range []struct {
 		name    string
-		ipErr   error
+		twErr   error
 		wantErr error
 	}{
-		{name: "Err", ipErr: errA, wantErr: errA},
+		{name: "Err", twErr: errA, wantErr: errA},
+		{name: "Conflict", twErr: errB, wantErr: errB},
 		{
 			name: "DupKey",
-			ipErr: &smithy.OperationError{
-				Err: &types.ConditionalCheckFailedException{},
+			twErr: &smithy.OperationError{
+				Err: &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("ConditionalCheckFailed")},
+						{Code: aws.String("None")},
+					},
+				},
+			},
+			wantErr: db.ErrDupKey,
+		},
+		{
+			name: "OtherCasingTaken",
+			twErr: &smithy.OperationError{
+				Err: &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("None")},
+						{Code: aws.String("ConditionalCheckFailed")},
+					},
+				},
 			},
 			wantErr: db.ErrDupKey,
 		},
-		{name: "OK", ipErr: nil, wantErr: nil},
+		{name: "OK", twErr: nil, wantErr: nil},
 	} {
 		t.Run(c.name, func(t *testing.T) {
-			ip.Err = c.ipErr
+			tw.Err = c.twErr
 
-			err := sut.Insert(context.Background(), User{})
+			err := sut.Insert(context.Background(), User{Username: "Bob123"})
 
 			assert.ErrIs(t.Fatal, err, c.wantErr)
 		})
@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 // Retriever can be used to retrieve by username a user from the user table.
@@ -37,9 +38,23 @@ func (g Retriever) Retrieve(
 		return User{}, db.ErrNoItem
 	}
 
+	return unmarshalUser(out.Item)
+}
+
+// unmarshalUser unmarshals a user item from the user table into a User.
+func unmarshalUser(item map[string]types.AttributeValue) (User, error) {
 	var user User
-	if err = attributevalue.UnmarshalMap(out.Item, &user); err != nil {
+	if err := attributevalue.UnmarshalMap(item, &user); err != nil {
 		return User{}, err
 	}
+
+	// users registered before roles were introduced only have IsAdmin
+	if user.Role == "" {
+		var legacy struct{ IsAdmin bool }
+		if err := attributevalue.UnmarshalMap(item, &legacy); err != nil {
+			return User{}, err
+		}
+		user.Role = role.FromIsAdmin(legacy.IsAdmin)
+	}
 	return user, nil
 }
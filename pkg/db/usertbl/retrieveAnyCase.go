@@ -0,0 +1,56 @@
+package usertbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// RetrieverAnyCase can be used to retrieve by username a user from the user
+// table regardless of the casing that the username is in.
+type RetrieverAnyCase struct{ igq db.DynamoItemGetQueryer }
+
+// NewRetrieverAnyCase creates and returns a new RetrieverAnyCase.
+func NewRetrieverAnyCase(igq db.DynamoItemGetQueryer) RetrieverAnyCase {
+	return RetrieverAnyCase{igq: igq}
+}
+
+// Retrieve retrieves by username a user from the user table regardless of the
+// casing that the username is in.
+//
+// The username is looked up in the exact casing first, which also finds users
+// who haven't been given a UsernameLower yet. If more than one user owns the
+// username in different casings, none of them can be told apart from the
+// others and db.ErrNoItem is returned.
+func (r RetrieverAnyCase) Retrieve(
+	ctx context.Context, username string,
+) (User, error) {
+	user, err := NewRetriever(r.igq).Retrieve(ctx, username)
+	if !errors.Is(err, db.ErrNoItem) {
+		return user, err
+	}
+
+	out, err := r.igq.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(os.Getenv(tableName)),
+		IndexName:              aws.String("UsernameLower-index"),
+		KeyConditionExpression: aws.String("UsernameLower = :lower"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":lower": &types.AttributeValueMemberS{
+				Value: LowerUsername(username),
+			},
+		},
+	})
+	if err != nil {
+		return User{}, err
+	}
+	if len(out.Items) != 1 {
+		return User{}, db.ErrNoItem
+	}
+	return unmarshalUser(out.Items[0])
+}
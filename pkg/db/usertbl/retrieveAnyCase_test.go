@@ -0,0 +1,116 @@
+//go:build utest
+
+package usertbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+func TestRetrieverAnyCase(t *testing.T) {
+	igq := &db.FakeDynamoItemGetQueryer{}
+	sut := NewRetrieverAnyCase(igq)
+
+	itemFor := func(username string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"Username": &types.AttributeValueMemberS{Value: username},
+			"UsernameLower": &types.AttributeValueMemberS{
+				Value: LowerUsername(username),
+			},
+			"Role": &types.AttributeValueMemberS{Value: string(role.Member)},
+		}
+	}
+	errA := errors.New("failed to get item")
+	errB := errors.New("failed to query")
+
+	for _, c := range []struct {
+		name         string
+		getOut       *dynamodb.GetItemOutput
+		getErr       error
+		queryOut     *dynamodb.QueryOutput
+		queryErr     error
+		wantUsername string
+		wantErr      error
+	}{
+		{
+			name:         "GetErr",
+			getOut:       nil,
+			getErr:       errA,
+			queryOut:     nil,
+			queryErr:     nil,
+			wantUsername: "",
+			wantErr:      errA,
+		},
+		{
+			name:         "ExactCasing",
+			getOut:       &dynamodb.GetItemOutput{Item: itemFor("Bob123")},
+			getErr:       nil,
+			queryOut:     nil,
+			queryErr:     nil,
+			wantUsername: "Bob123",
+			wantErr:      nil,
+		},
+		{
+			name:         "QueryErr",
+			getOut:       &dynamodb.GetItemOutput{Item: nil},
+			getErr:       nil,
+			queryOut:     nil,
+			queryErr:     errB,
+			wantUsername: "",
+			wantErr:      errB,
+		},
+		{
+			name:         "NoItem",
+			getOut:       &dynamodb.GetItemOutput{Item: nil},
+			getErr:       nil,
+			queryOut:     &dynamodb.QueryOutput{},
+			queryErr:     nil,
+			wantUsername: "",
+			wantErr:      db.ErrNoItem,
+		},
+		{
+			name:   "Ambiguous",
+			getOut: &dynamodb.GetItemOutput{Item: nil},
+			getErr: nil,
+			queryOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					itemFor("Bob123"), itemFor("BOB123"),
+				},
+			},
+			queryErr:     nil,
+			wantUsername: "",
+			wantErr:      db.ErrNoItem,
+		},
+		{
+			name:   "OtherCasing",
+			getOut: &dynamodb.GetItemOutput{Item: nil},
+			getErr: nil,
+			queryOut: &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{itemFor("Bob123")},
+			},
+			queryErr:     nil,
+			wantUsername: "Bob123",
+			wantErr:      nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			igq.OutGet = c.getOut
+			igq.ErrGet = c.getErr
+			igq.OutQuery = c.queryOut
+			igq.ErrQuery = c.queryErr
+
+			user, err := sut.Retrieve(context.Background(), "bob123")
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, user.Username, c.wantUsername)
+		})
+	}
+}
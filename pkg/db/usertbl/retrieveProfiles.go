@@ -0,0 +1,75 @@
+package usertbl
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// maxBatchKeys is the maximum number of keys that DynamoDB accepts in a single
+// BatchGetItem request.
+const maxBatchKeys = 100
+
+// ProfileRetriever can be used to retrieve by username the profiles of multiple
+// users from the user table.
+type ProfileRetriever struct{ bget db.DynamoBatchItemGetter }
+
+// NewProfileRetriever creates and returns a new ProfileRetriever.
+func NewProfileRetriever(bget db.DynamoBatchItemGetter) ProfileRetriever {
+	return ProfileRetriever{bget: bget}
+}
+
+// Retrieve retrieves by username the profiles of multiple users from the user
+// table. Only the profile attributes are read so that no credentials are read
+// out of the table. The profiles are not returned in any particular order and
+// the users that don't exist are left out.
+func (r ProfileRetriever) Retrieve(
+	ctx context.Context, usernames []string,
+) ([]Profile, error) {
+	table := os.Getenv(tableName)
+	profiles := []Profile{}
+	for start := 0; start < len(usernames); start += maxBatchKeys {
+		end := min(start+maxBatchKeys, len(usernames))
+		keys := make([]map[string]types.AttributeValue, 0, end-start)
+		for _, username := range usernames[start:end] {
+			keys = append(keys, map[string]types.AttributeValue{
+				"Username": &types.AttributeValueMemberS{Value: username},
+			})
+		}
+
+		// DynamoDB may not process all keys at once, in which case the
+		// unprocessed ones are requested again
+		for len(keys) > 0 {
+			out, err := r.bget.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: map[string]types.KeysAndAttributes{
+					table: {
+						Keys: keys,
+						ProjectionExpression: aws.String(
+							"Username, DisplayName, AvatarURL",
+						),
+					},
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			var page []Profile
+			if err = attributevalue.UnmarshalListOfMaps(
+				out.Responses[table], &page,
+			); err != nil {
+				return nil, err
+			}
+			profiles = append(profiles, page...)
+
+			keys = out.UnprocessedKeys[table].Keys
+		}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,102 @@
+//go:build utest
+
+package usertbl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+func TestProfileRetriever(t *testing.T) {
+	bg := &db.FakeDynamoBatchItemGetter{}
+	sut := NewProfileRetriever(bg)
+
+	errA := errors.New("failed to batch get items")
+
+	for _, c := range []struct {
+		name         string
+		usernames    []string
+		bgOut        *dynamodb.BatchGetItemOutput
+		bgErr        error
+		wantProfiles []Profile
+		wantErr      error
+	}{
+		{
+			name:         "NoUsernames",
+			usernames:    []string{},
+			bgOut:        nil,
+			bgErr:        errA,
+			wantProfiles: []Profile{},
+			wantErr:      nil,
+		},
+		{
+			name:         "Err",
+			usernames:    []string{"bob123"},
+			bgOut:        nil,
+			bgErr:        errA,
+			wantProfiles: []Profile{},
+			wantErr:      errA,
+		},
+		{
+			name:         "NoItems",
+			usernames:    []string{"bob123"},
+			bgOut:        &dynamodb.BatchGetItemOutput{},
+			bgErr:        nil,
+			wantProfiles: []Profile{},
+			wantErr:      nil,
+		},
+		{
+			name:      "OK",
+			usernames: []string{"bob123", "alice321"},
+			bgOut: &dynamodb.BatchGetItemOutput{
+				Responses: map[string][]map[string]types.AttributeValue{
+					"": {
+						{
+							"Username": &types.AttributeValueMemberS{
+								Value: "bob123",
+							},
+							"DisplayName": &types.AttributeValueMemberS{
+								Value: "Bob",
+							},
+							"AvatarURL": &types.AttributeValueMemberS{
+								Value: "https://goteam.io/bob.png",
+							},
+						},
+						{
+							"Username": &types.AttributeValueMemberS{
+								Value: "alice321",
+							},
+						},
+					},
+				},
+			},
+			bgErr: nil,
+			wantProfiles: []Profile{
+				{
+					Username:    "bob123",
+					DisplayName: "Bob",
+					AvatarURL:   "https://goteam.io/bob.png",
+				},
+				{Username: "alice321"},
+			},
+			wantErr: nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			bg.Out = c.bgOut
+			bg.Err = c.bgErr
+
+			profiles, err := sut.Retrieve(context.Background(), c.usernames)
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.AllEqual(t.Error, profiles, c.wantProfiles)
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/role"
 )
 
 func TestRetriever(t *testing.T) {
@@ -20,8 +21,9 @@ func TestRetriever(t *testing.T) {
 
 	userA := User{
 		Username: "bob123",
+		Email:    "bob@goteam.io",
 		Password: []byte("p4ssw0rd"),
-		IsAdmin:  true,
+		Role:     role.Admin,
 		TeamID:   "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
 	}
 	errA := errors.New("failed to get item")
@@ -52,11 +54,12 @@ func TestRetriever(t *testing.T) {
 			igOut: &dynamodb.GetItemOutput{
 				Item: map[string]types.AttributeValue{
 					"Username": &types.AttributeValueMemberS{Value: userA.Username},
+					"Email":    &types.AttributeValueMemberS{Value: userA.Email},
 					"Password": &types.AttributeValueMemberB{
 						Value: userA.Password,
 					},
-					"IsAdmin": &types.AttributeValueMemberBOOL{
-						Value: userA.IsAdmin,
+					"Role": &types.AttributeValueMemberS{
+						Value: string(userA.Role),
 					},
 					"TeamID": &types.AttributeValueMemberS{
 						Value: userA.TeamID,
@@ -67,6 +70,31 @@ func TestRetriever(t *testing.T) {
 			wantUser: &userA,
 			wantErr:  nil,
 		},
+		{
+			name: "OKLegacyAdmin",
+			igOut: &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					"Username": &types.AttributeValueMemberS{Value: userA.Username},
+					"Email":    &types.AttributeValueMemberS{Value: userA.Email},
+					"Password": &types.AttributeValueMemberB{
+						Value: userA.Password,
+					},
+					"IsAdmin": &types.AttributeValueMemberBOOL{Value: true},
+					"TeamID": &types.AttributeValueMemberS{
+						Value: userA.TeamID,
+					},
+				},
+			},
+			igErr: nil,
+			wantUser: &User{
+				Username: userA.Username,
+				Email:    userA.Email,
+				Password: userA.Password,
+				Role:     role.Owner,
+				TeamID:   userA.TeamID,
+			},
+			wantErr: nil,
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			ig.Out = c.igOut
@@ -77,8 +105,9 @@ func TestRetriever(t *testing.T) {
 			assert.Equal(t.Fatal, err, c.wantErr)
 			if c.wantUser != nil {
 				assert.Equal(t.Error, user.Username, c.wantUser.Username)
+				assert.Equal(t.Error, user.Email, c.wantUser.Email)
 				assert.AllEqual(t.Error, user.Password, c.wantUser.Password)
-				assert.True(t.Error, c.wantUser.IsAdmin)
+				assert.Equal(t.Error, user.Role, c.wantUser.Role)
 				assert.Equal(t.Error, user.TeamID, c.wantUser.TeamID)
 			}
 		})
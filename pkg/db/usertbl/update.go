@@ -0,0 +1,42 @@
+package usertbl
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/db"
+)
+
+// Updater can be used to update a user in the user table.
+type Updater struct{ iput db.DynamoItemPutter }
+
+// NewUpdater creates and returns a new Updater.
+func NewUpdater(iput db.DynamoItemPutter) Updater { return Updater{iput: iput} }
+
+// Update updates a user in the user table.
+func (u Updater) Update(ctx context.Context, user User) error {
+	user.UsernameLower = LowerUsername(user.Username)
+	item, err := attributevalue.MarshalMap(user)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.iput.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(os.Getenv(tableName)),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(Username)"),
+	})
+
+	var ex *types.ConditionalCheckFailedException
+	if errors.As(err, &ex) {
+		return db.ErrNoItem
+	}
+
+	return err
+}
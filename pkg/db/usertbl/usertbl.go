@@ -1,26 +1,88 @@
 // Package usertbl contains code to interact with the user table in DynamoDB.
 package usertbl
 
+import (
+	"strings"
+
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
 // tableName is the name of the environment variable to retrieve the user
 // table's name from.
 const tableName = "USER_TABLE_NAME"
 
 // User defines the user entity - the primary and only entity of user domain.
+//
+// Role is the user's role in their team. Users registered before roles were
+// introduced only have an IsAdmin attribute, from which Retriever derives it.
+//
+// MFASecret is set when the user starts enrolling in two-factor authentication
+// and MFAEnabled once they have verified a code for it. MFALastStep is the time
+// step of the last TOTP code used so that it can't be used again, and
+// RecoveryCodes are the hashes of the recovery codes that are yet to be used.
+//
+// DisplayName, AvatarURL, TimeZone, and Locale make up the user's profile along
+// with their email, and are all optional.
+//
+// EmailVerified is set when the email is known to belong to the user, which is
+// only the case when an identity provider verified it for a user that it
+// created. Emails that users enter themselves are never verified.
+//
+// UsernameLower is Username in lower case. Usernames are compared by it so that
+// they are case-insensitive, while Username keeps the casing the user chose for
+// display. Users registered before usernames were case-insensitive may not have
+// it yet, so it is set on every update.
 type User struct {
-	Username string
-	Password []byte
-	IsAdmin  bool
-	TeamID   string
+	Username      string
+	UsernameLower string `dynamodbav:",omitempty"`
+	Email         string `dynamodbav:",omitempty"`
+	EmailVerified bool   `dynamodbav:",omitempty"`
+	Password      []byte
+	Role          role.Role
+	TeamID        string
+	MFASecret     string   `dynamodbav:",omitempty"`
+	MFAEnabled    bool     `dynamodbav:",omitempty"`
+	MFALastStep   int64    `dynamodbav:",omitempty"`
+	RecoveryCodes []string `dynamodbav:",omitempty"`
+	DisplayName   string   `dynamodbav:",omitempty"`
+	AvatarURL     string   `dynamodbav:",omitempty"`
+	TimeZone      string   `dynamodbav:",omitempty"`
+	Locale        string   `dynamodbav:",omitempty"`
 }
 
 // NewUser creates and returns a new User,
 func NewUser(
-	username string, password []byte, isAdmin bool, teamID string,
+	username, email string, password []byte, r role.Role, teamID string,
 ) User {
 	return User{
-		Username: username,
-		Password: password,
-		IsAdmin:  isAdmin,
-		TeamID:   teamID,
+		Username:      username,
+		UsernameLower: LowerUsername(username),
+		Email:         email,
+		Password:      password,
+		Role:          r,
+		TeamID:        teamID,
 	}
 }
+
+// LowerUsername returns the form of username that usernames are compared by.
+func LowerUsername(username string) string { return strings.ToLower(username) }
+
+// ClaimPrefix is the prefix of the usernames of the items in the user table
+// that claim usernames in all of their casings, so that a username can be
+// checked and taken in a single transaction. Usernames can only contain letters
+// and digits, so these items can't clash with users.
+const ClaimPrefix = "#claim#"
+
+// ClaimUsername returns the username of the item that claims username in all
+// of its casings.
+func ClaimUsername(username string) string {
+	return ClaimPrefix + LowerUsername(username)
+}
+
+// Profile defines the summary of a user's profile that the other members of
+// their teams can see.
+type Profile struct {
+	Username    string
+	DisplayName string `dynamodbav:",omitempty"`
+	AvatarURL   string `dynamodbav:",omitempty"`
+}
@@ -6,3 +6,10 @@ type FakeErrorer struct{ Args []any }
 // Log implements the Errorer interface on FakeErrorer. It assigns the message
 // passed into it to the InMessage field on the fake instance.
 func (f *FakeErrorer) Error(args ...any) { f.Args = args }
+
+// FakeWarner is a test fake for Warner.
+type FakeWarner struct{ Args []any }
+
+// Warn implements the Warner interface on FakeWarner. It assigns the message
+// passed into it to the Args field on the fake instance.
+func (f *FakeWarner) Warn(args ...any) { f.Args = args }
@@ -10,6 +10,10 @@ import (
 // the console.
 type Errorer interface{ Error(...any) }
 
+// Warner describes a type that can be used to log a warning-level message to
+// the console.
+type Warner interface{ Warn(...any) }
+
 // Log can be used to log messages of different log levels across the project.
 type Log struct{}
 
@@ -21,6 +25,11 @@ func (l Log) Info(args ...any) {
 	log.Println(append([]any{"--[INFO]--"}, args...)...)
 }
 
+// Warn logs a warning-level message to the console.
+func (l Log) Warn(args ...any) {
+	log.Println(append([]any{"--[WARN]--"}, args...)...)
+}
+
 // Info logs an error-level message to the console.
 func (l Log) Error(args ...any) {
 	log.Println(append([]any{"--[ERROR]--"}, args...)...)
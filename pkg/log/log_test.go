@@ -25,6 +25,12 @@ func TestLog(t *testing.T) {
 			msg:     "some information",
 			wantLog: "--[INFO]-- some information\n",
 		},
+		{
+			name:    "Warn",
+			logFunc: sut.Warn,
+			msg:     "something looks wrong",
+			wantLog: "--[WARN]-- something looks wrong\n",
+		},
 		{
 			name:    "Error",
 			logFunc: sut.Error,
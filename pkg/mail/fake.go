@@ -0,0 +1,18 @@
+//go:build utest
+
+package mail
+
+// FakeMailer is a test fake for Mailer.
+type FakeMailer struct {
+	To      string
+	Subject string
+	Body    string
+	Err     error
+}
+
+// Send assigns the params to the corresponding fields on FakeMailer and returns
+// FakeMailer.Err.
+func (f *FakeMailer) Send(to, subject, body string) error {
+	f.To, f.Subject, f.Body = to, subject, body
+	return f.Err
+}
@@ -0,0 +1,28 @@
+// Package mail contains code for sending emails to users.
+package mail
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mailer describes a type that can be used to send an email to a single
+// recipient.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// newMsg builds and returns a plain-text RFC 5322 message from the given
+// arguments.
+func newMsg(from, to, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"net"
+	"net/smtp"
+)
+
+// SMTPMailer is a Mailer that sends emails through an SMTP server.
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+	send func(string, smtp.Auth, string, []string, []byte) error
+}
+
+// NewSMTPMailer creates and returns a new SMTPMailer. If username is empty, no
+// authentication is done with the server.
+func NewSMTPMailer(
+	host, port, username, password, from string,
+) SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return SMTPMailer{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+		send: smtp.SendMail,
+	}
+}
+
+// Send sends an email with the given subject and body to the given address.
+func (m SMTPMailer) Send(to, subject, body string) error {
+	return m.send(
+		m.addr, m.auth, m.from, []string{to}, newMsg(m.from, to, subject, body),
+	)
+}
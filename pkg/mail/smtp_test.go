@@ -0,0 +1,57 @@
+//go:build utest
+
+package mail
+
+import (
+	"errors"
+	"net/smtp"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestSMTPMailer(t *testing.T) {
+	var (
+		addr string
+		auth smtp.Auth
+		from string
+		to   []string
+		msg  []byte
+		err  error
+	)
+	sut := NewSMTPMailer("localhost", "1025", "", "", "noreply@goteam.io")
+	sut.send = func(
+		a string, au smtp.Auth, f string, t []string, m []byte,
+	) error {
+		addr, auth, from, to, msg = a, au, f, t, m
+		return err
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		err = errors.New("send failed")
+
+		gotErr := sut.Send("bob@goteam.io", "Hello", "Hi Bob.")
+
+		assert.ErrIs(t.Error, gotErr, err)
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		err = nil
+
+		gotErr := sut.Send("bob@goteam.io", "Hello", "Hi Bob.\nBye.")
+
+		assert.Nil(t.Fatal, gotErr)
+		assert.Equal(t.Error, addr, "localhost:1025")
+		assert.Nil(t.Error, auth)
+		assert.Equal(t.Error, from, "noreply@goteam.io")
+		assert.AllEqual(t.Error, to, []string{"bob@goteam.io"})
+		assert.Equal(t.Error, string(msg), "From: noreply@goteam.io\r\n"+
+			"To: bob@goteam.io\r\n"+
+			"Subject: Hello\r\n"+
+			"MIME-Version: 1.0\r\n"+
+			"Content-Type: text/plain; charset=\"utf-8\"\r\n"+
+			"\r\n"+
+			"Hi Bob.\r\nBye.\r\n",
+		)
+	})
+}
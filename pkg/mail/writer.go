@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"io"
+	"sync"
+)
+
+// WriterMailer is a Mailer that writes emails to an io.Writer instead of
+// sending them. It is meant to be used on local with a file or os.Stdout.
+type WriterMailer struct {
+	w    io.Writer
+	from string
+	mu   *sync.Mutex
+}
+
+// NewWriterMailer creates and returns a new WriterMailer.
+func NewWriterMailer(w io.Writer, from string) WriterMailer {
+	return WriterMailer{w: w, from: from, mu: &sync.Mutex{}}
+}
+
+// Send writes an email with the given subject and body to the writer.
+func (m WriterMailer) Send(to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := m.w.Write(append(newMsg(m.from, to, subject, body), '\n'))
+	return err
+}
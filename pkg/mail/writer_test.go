@@ -0,0 +1,27 @@
+//go:build utest
+
+package mail
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestWriterMailer(t *testing.T) {
+	var buf bytes.Buffer
+	sut := NewWriterMailer(&buf, "noreply@goteam.io")
+
+	err := sut.Send("bob@goteam.io", "Hello", "Hi Bob.")
+
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, buf.String(), "From: noreply@goteam.io\r\n"+
+		"To: bob@goteam.io\r\n"+
+		"Subject: Hello\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n"+
+		"\r\n"+
+		"Hi Bob.\r\n\n",
+	)
+}
@@ -0,0 +1,25 @@
+//go:build utest
+
+package mfa
+
+// FakeSecretGenerator is a test fake for SecretGenerator.
+type FakeSecretGenerator struct {
+	Res string
+	Err error
+}
+
+// NewSecret returns FakeSecretGenerator.Res and FakeSecretGenerator.Err.
+func (f *FakeSecretGenerator) NewSecret() (string, error) {
+	return f.Res, f.Err
+}
+
+// FakeVerifier is a test fake for Verifier.
+type FakeVerifier struct {
+	Res int64
+	Err error
+}
+
+// Verify discards params and returns FakeVerifier.Res and FakeVerifier.Err.
+func (f *FakeVerifier) Verify(string, string, int64) (int64, error) {
+	return f.Res, f.Err
+}
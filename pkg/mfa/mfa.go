@@ -0,0 +1,22 @@
+// Package mfa contains code for two-factor authentication with time-based
+// one-time passwords (TOTP) and single-use recovery codes.
+package mfa
+
+import "errors"
+
+// ErrInvalidCode means that the given code was not valid for the secret or had
+// already been used.
+var ErrInvalidCode = errors.New("invalid code")
+
+// SecretGenerator defines a type that can be used to generate a TOTP secret for
+// a user to enroll in two-factor authentication with.
+type SecretGenerator interface {
+	NewSecret() (string, error)
+}
+
+// Verifier defines a type that can be used to verify a TOTP code. It returns
+// the time step that the code was generated for, which must be passed as
+// lastStep on the next verification for the code to not be accepted again.
+type Verifier interface {
+	Verify(secret, code string, lastStep int64) (int64, error)
+}
@@ -0,0 +1,49 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+// recoveryCodeCount is the number of recovery codes generated for a user on
+// enrolling in two-factor authentication.
+const recoveryCodeCount = 10
+
+// NewRecoveryCodes returns a new set of recovery codes to be shown to the user
+// once, and their hashes to be stored in their place.
+func NewRecoveryCodes() (codes []string, hashes []string, err error) {
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(b32.EncodeToString(b))
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode returns the hash of the given recovery code. Recovery codes
+// have enough entropy that they don't need to be hashed with a slow algorithm
+// like passwords do.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// UseRecoveryCode returns the given hashes without the hash of code, or
+// ErrInvalidCode if code is not one of the recovery codes they are hashes of.
+func UseRecoveryCode(hashes []string, code string) ([]string, error) {
+	hash := []byte(HashRecoveryCode(code))
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), hash) == 1 {
+			return append(hashes[:i:i], hashes[i+1:]...), nil
+		}
+	}
+	return nil, ErrInvalidCode
+}
@@ -0,0 +1,51 @@
+//go:build utest
+
+package mfa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes()
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Fatal, len(codes), recoveryCodeCount)
+	assert.Equal(t.Fatal, len(hashes), recoveryCodeCount)
+
+	seen := map[string]bool{}
+	for i, code := range codes {
+		assert.Equal(t.Error, len(code), 9)
+		assert.Equal(t.Error, code[4], byte('-'))
+		assert.Equal(t.Error, hashes[i], HashRecoveryCode(code))
+		assert.True(t.Error, !seen[code])
+		seen[code] = true
+	}
+
+	t.Run("InvalidCode", func(t *testing.T) {
+		_, err := UseRecoveryCode(hashes, "abcd-efgh")
+
+		assert.ErrIs(t.Error, err, ErrInvalidCode)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// codes should be accepted regardless of case and formatting
+		code := " " + strings.ToUpper(strings.ReplaceAll(codes[3], "-", ""))
+
+		remaining, err := UseRecoveryCode(hashes, code)
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(remaining), recoveryCodeCount-1)
+		for _, h := range remaining {
+			assert.True(t.Error, h != hashes[3])
+		}
+		// the original hashes must not be modified
+		assert.Equal(t.Error, hashes[3], HashRecoveryCode(codes[3]))
+
+		_, err = UseRecoveryCode(remaining, codes[3])
+
+		assert.ErrIs(t.Error, err, ErrInvalidCode)
+	})
+}
@@ -0,0 +1,101 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// period is the number of seconds a TOTP code is valid for.
+	period = 30
+
+	// digits is the number of digits in a TOTP code.
+	digits = 6
+
+	// skew is the number of periods before and after the current one that
+	// codes are accepted from to allow for clock drift between the server and
+	// the user's device.
+	skew = 1
+)
+
+// b32 is the encoding used for TOTP secrets, which authenticator apps expect
+// without padding.
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP can be used to generate TOTP secrets and verify codes as defined in
+// RFC 6238 with the defaults that authenticator apps support (SHA-1, 6 digits,
+// and 30-second periods).
+type TOTP struct{ now func() time.Time }
+
+// NewTOTP creates and returns a new TOTP.
+func NewTOTP() TOTP { return TOTP{now: time.Now} }
+
+// NewSecret returns a new random base32-encoded 160-bit secret.
+func (t TOTP) NewSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(b), nil
+}
+
+// Verify checks that the code is valid for the secret at the current time and
+// was generated after lastStep.
+func (t TOTP) Verify(secret, code string, lastStep int64) (int64, error) {
+	key, err := b32.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, err
+	}
+
+	step := t.now().Unix() / period
+	for i := step - skew; i <= step+skew; i++ {
+		if i <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare(
+			[]byte(generate(key, i)), []byte(code),
+		) == 1 {
+			return i, nil
+		}
+	}
+	return 0, ErrInvalidCode
+}
+
+// generate returns the TOTP code for the given key and time step.
+func generate(key []byte, step int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, bin%1_000_000) // 10^digits
+}
+
+// URI returns the otpauth URI for the given secret, which authenticator apps
+// can scan as a QR code to enroll the user's account.
+func URI(issuer, account, secret string) string {
+	return (&url.URL{
+		Scheme: "otpauth",
+		Host:   "totp",
+		Path:   "/" + issuer + ":" + account,
+		RawQuery: url.Values{
+			"secret":    {secret},
+			"issuer":    {issuer},
+			"algorithm": {"SHA1"},
+			"digits":    {fmt.Sprint(digits)},
+			"period":    {fmt.Sprint(period)},
+		}.Encode(),
+	}).String()
+}
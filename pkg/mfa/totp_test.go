@@ -0,0 +1,139 @@
+//go:build utest
+
+package mfa
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// TestTOTP tests the TOTP type to assert that it generates secrets that it can
+// verify codes for and that it verifies codes as defined in RFC 6238.
+func TestTOTP(t *testing.T) {
+	// the SHA-1 secret from the RFC 6238 test vectors
+	secret := b32.EncodeToString([]byte("12345678901234567890"))
+
+	t.Run("NewSecret", func(t *testing.T) {
+		s, err := NewTOTP().NewSecret()
+
+		assert.Nil(t.Fatal, err)
+		key, err := base32.StdEncoding.WithPadding(base32.NoPadding).
+			DecodeString(s)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, len(key), 20)
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		for _, c := range []struct {
+			name     string
+			secret   string
+			now      int64
+			code     string
+			lastStep int64
+			wantStep int64
+			wantErr  error
+		}{
+			{
+				name:     "InvalidSecret",
+				secret:   "1",
+				now:      59,
+				code:     "287082",
+				lastStep: 0,
+				wantStep: 0,
+				wantErr:  base32.CorruptInputError(0),
+			},
+			{
+				name:     "WrongCode",
+				secret:   secret,
+				now:      59,
+				code:     "287083",
+				lastStep: 0,
+				wantStep: 0,
+				wantErr:  ErrInvalidCode,
+			},
+			{
+				name:     "Expired",
+				secret:   secret,
+				now:      1111111109 + 2*period,
+				code:     "081804",
+				lastStep: 0,
+				wantStep: 0,
+				wantErr:  ErrInvalidCode,
+			},
+			{
+				name:     "Replayed",
+				secret:   secret,
+				now:      1111111111,
+				code:     "050471",
+				lastStep: 1111111111 / period,
+				wantStep: 0,
+				wantErr:  ErrInvalidCode,
+			},
+			{
+				name:     "Success",
+				secret:   secret,
+				now:      59,
+				code:     "287082",
+				lastStep: 0,
+				wantStep: 1,
+				wantErr:  nil,
+			},
+			{
+				name:     "SuccessPrevStep",
+				secret:   secret,
+				now:      1111111109 + period,
+				code:     "081804",
+				lastStep: 0,
+				wantStep: 1111111109 / period,
+				wantErr:  nil,
+			},
+			{
+				name:     "SuccessNextStep",
+				secret:   secret,
+				now:      1234567890 - period,
+				code:     "005924",
+				lastStep: 0,
+				wantStep: 1234567890 / period,
+				wantErr:  nil,
+			},
+			{
+				name:     "SuccessLowercaseSecret",
+				secret:   "gezdgnbvgy3tqojqgezdgnbvgy3tqojq",
+				now:      2000000000,
+				code:     "279037",
+				lastStep: 0,
+				wantStep: 2000000000 / period,
+				wantErr:  nil,
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				sut := TOTP{now: func() time.Time { return time.Unix(c.now, 0) }}
+
+				step, err := sut.Verify(c.secret, c.code, c.lastStep)
+
+				assert.ErrIs(t.Error, err, c.wantErr)
+				assert.Equal(t.Error, step, c.wantStep)
+			})
+		}
+	})
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("GoTeam!", "bob123", "SECRET")
+
+	u, err := url.Parse(uri)
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, u.Scheme, "otpauth")
+	assert.Equal(t.Error, u.Host, "totp")
+	assert.Equal(t.Error, u.Path, "/GoTeam!:bob123")
+	q := u.Query()
+	assert.Equal(t.Error, q.Get("secret"), "SECRET")
+	assert.Equal(t.Error, q.Get("issuer"), "GoTeam!")
+	assert.Equal(t.Error, q.Get("algorithm"), "SHA1")
+	assert.Equal(t.Error, q.Get("digits"), "6")
+	assert.Equal(t.Error, q.Get("period"), "30")
+}
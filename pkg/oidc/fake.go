@@ -0,0 +1,29 @@
+//go:build utest
+
+package oidc
+
+import "context"
+
+// FakeAuthenticator is a test fake for Authenticator.
+type FakeAuthenticator struct {
+	URL       string
+	ErrURL    error
+	Claims    Claims
+	ErrClaims error
+}
+
+// AuthURL discards the input parameters and returns the FakeAuthenticator's URL
+// and ErrURL field values.
+func (f *FakeAuthenticator) AuthURL(
+	context.Context, string, string, string,
+) (string, error) {
+	return f.URL, f.ErrURL
+}
+
+// Exchange discards the input parameters and returns the FakeAuthenticator's
+// Claims and ErrClaims field values.
+func (f *FakeAuthenticator) Exchange(
+	context.Context, string, string, string,
+) (Claims, error) {
+	return f.Claims, f.ErrClaims
+}
@@ -0,0 +1,52 @@
+// Package oidc contains code for logging users in through an external OpenID
+// Connect identity provider using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// Claims defines the claims of an ID token that are used to log a user in.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// Authenticator defines a type that can be used to log users in through an
+// identity provider.
+type Authenticator interface {
+	// AuthURL returns the URL of the identity provider to redirect the user to
+	// for them to log in.
+	AuthURL(ctx context.Context, state, nonce, challenge string) (string, error)
+
+	// Exchange exchanges the authorization code that the identity provider
+	// redirected the user back with for the claims of their ID token.
+	Exchange(ctx context.Context, code, verifier, nonce string) (Claims, error)
+}
+
+// ErrInvalidToken means that the ID token returned by the identity provider was
+// not issued by it for this client and login.
+var ErrInvalidToken = errors.New("invalid id token")
+
+// NewRandom returns a random URL-safe string to be used as a state, nonce, or
+// PKCE code verifier.
+func NewRandom() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge returns the S256 PKCE code challenge for the given code verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
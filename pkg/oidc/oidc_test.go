@@ -0,0 +1,27 @@
+//go:build utest
+
+package oidc
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestNewRandom(t *testing.T) {
+	a, err := NewRandom()
+	assert.Nil(t.Fatal, err)
+	b, err := NewRandom()
+	assert.Nil(t.Fatal, err)
+
+	assert.Equal(t.Error, len(a), 43)
+	assert.True(t.Error, a != b)
+}
+
+func TestChallenge(t *testing.T) {
+	// example from RFC 7636 appendix B
+	assert.Equal(t.Error,
+		Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"),
+		"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+	)
+}
@@ -0,0 +1,219 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+)
+
+// validMethods are the only signing algorithms that ID tokens are accepted
+// with.
+var validMethods = []string{
+	jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg(),
+}
+
+// config defines the parts of an identity provider's discovery document that
+// are used.
+type config struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is an Authenticator for any identity provider that supports OpenID
+// Connect Discovery. The discovery document is fetched on first use and cached
+// from then on.
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	client       *http.Client
+	mu           sync.Mutex
+	cfg          *config
+	keys         cookie.KeySet
+}
+
+// NewProvider creates and returns a new Provider. clientSecret can be left
+// empty for public clients, which rely on PKCE alone.
+func NewProvider(
+	issuer, clientID, clientSecret, redirectURL string,
+) *Provider {
+	return &Provider{
+		issuer:       issuer,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AuthURL returns the URL of the identity provider's authorization endpoint to
+// redirect the user to for them to log in.
+func (p *Provider) AuthURL(
+	ctx context.Context, state, nonce, challenge string,
+) (string, error) {
+	cfg, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(cfg.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.clientID)
+	q.Set("redirect_uri", p.redirectURL)
+	q.Set("scope", "openid email profile")
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange exchanges an authorization code for an ID token at the identity
+// provider's token endpoint, and validates and returns the claims of the ID
+// token.
+func (p *Provider) Exchange(
+	ctx context.Context, code, verifier, nonce string,
+) (Claims, error) {
+	cfg, keys, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	// exchange code for tokens
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"client_id":     {p.clientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, cfg.TokenEndpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return Claims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if p.clientSecret != "" {
+		req.SetBasicAuth(
+			url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret),
+		)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Claims{}, err
+	}
+	defer resp.Body.Close()
+
+	var tkResp struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&tkResp); err != nil {
+		return Claims{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Claims{}, fmt.Errorf(
+			"token exchange failed: %d %s", resp.StatusCode, tkResp.Error,
+		)
+	}
+
+	// validate ID token
+	claims := jwt.MapClaims{}
+	if _, err = jwt.NewParser(
+		jwt.WithValidMethods(validMethods),
+	).ParseWithClaims(
+		tkResp.IDToken, &claims, func(tk *jwt.Token) (any, error) {
+			kid, ok := tk.Header["kid"].(string)
+			if !ok {
+				return nil, cookie.ErrUnknownKey
+			}
+			return keys.Key(kid)
+		},
+	); err != nil {
+		return Claims{}, err
+	}
+	if !claims.VerifyIssuer(cfg.Issuer, true) ||
+		!claims.VerifyAudience(p.clientID, true) ||
+		!claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return Claims{}, ErrInvalidToken
+	}
+	if tkNonce, _ := claims["nonce"].(string); tkNonce != nonce {
+		return Claims{}, ErrInvalidToken
+	}
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return Claims{}, ErrInvalidToken
+	}
+
+	// the rest of the claims are optional
+	c := Claims{Issuer: cfg.Issuer, Subject: sub}
+	c.Email, _ = claims["email"].(string)
+	c.EmailVerified, _ = claims["email_verified"].(bool)
+	c.PreferredUsername, _ = claims["preferred_username"].(string)
+	return c, nil
+}
+
+// discover returns the identity provider's discovery document and key set,
+// fetching the document if it wasn't fetched before.
+func (p *Provider) discover(
+	ctx context.Context,
+) (*config, cookie.KeySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg != nil {
+		return p.cfg, p.keys, nil
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		strings.TrimSuffix(p.issuer, "/")+"/.well-known/openid-configuration",
+		nil,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf(
+			"unexpected discovery status: %d", resp.StatusCode,
+		)
+	}
+
+	var cfg config
+	if err = json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return nil, nil, err
+	}
+	// the issuer in the document must match the one it was fetched from to
+	// stop a provider from issuing tokens on behalf of another
+	if cfg.Issuer != p.issuer {
+		return nil, nil, fmt.Errorf(
+			"discovered issuer %q did not match %q", cfg.Issuer, p.issuer,
+		)
+	}
+
+	p.cfg, p.keys = &cfg, cookie.NewRemoteKeySet(cfg.JWKSURI, time.Hour)
+	return p.cfg, p.keys, nil
+}
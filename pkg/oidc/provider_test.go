@@ -0,0 +1,244 @@
+//go:build utest
+
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+)
+
+func TestProvider(t *testing.T) {
+	key, err := cookie.NewSigningKey("kid1", ed25519.NewKeyFromSeed(
+		bytes.Repeat([]byte{1}, ed25519.SeedSize),
+	))
+	assert.Nil(t.Fatal, err)
+
+	var (
+		issuer    string
+		tkStatus  int
+		idToken   string
+		tkForm    url.Values
+		tkSecret  string
+		clientID  = "client1"
+		secret    = "s3cr3t"
+		redirect  = "https://api.goteam.io/oidc/callback"
+		nonce     = "n0nc3"
+		verifier  = "v3r1f13r"
+		mux       = http.NewServeMux()
+		srv       = httptest.NewServer(mux)
+		encodeRes = func(w http.ResponseWriter, v any) {
+			if err := json.NewEncoder(w).Encode(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+	)
+	defer srv.Close()
+	issuer = srv.URL
+	mux.HandleFunc("/.well-known/openid-configuration",
+		func(w http.ResponseWriter, _ *http.Request) {
+			encodeRes(w, map[string]string{
+				"issuer":                 issuer,
+				"authorization_endpoint": srv.URL + "/authorize?prompt=login",
+				"token_endpoint":         srv.URL + "/token",
+				"jwks_uri":               srv.URL + "/jwks",
+			})
+		},
+	)
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		encodeRes(w, cookie.NewJWKS(key))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		tkForm = r.PostForm
+		_, tkSecret, _ = r.BasicAuth()
+		w.WriteHeader(tkStatus)
+		encodeRes(w, map[string]string{
+			"id_token": idToken, "error": "invalid_grant",
+		})
+	})
+
+	sign := func(method jwt.SigningMethod, k any, claims jwt.MapClaims) string {
+		tk := jwt.NewWithClaims(method, claims)
+		tk.Header["kid"] = key.ID
+		s, err := tk.SignedString(k)
+		assert.Nil(t.Fatal, err)
+		return s
+	}
+	claimsWith := func(overrides jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{
+			"iss":                issuer,
+			"aud":                clientID,
+			"sub":                "sub123",
+			"nonce":              nonce,
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"email":              "bob@goteam.io",
+			"email_verified":     true,
+			"preferred_username": "bob123",
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(claims, k)
+			} else {
+				claims[k] = v
+			}
+		}
+		return claims
+	}
+
+	t.Run("DiscoveryIssuerMismatch", func(t *testing.T) {
+		sut := NewProvider(srv.URL+"/other", clientID, secret, redirect)
+
+		_, err := sut.AuthURL(context.Background(), "s", "n", "c")
+
+		assert.True(t.Error, err != nil)
+	})
+
+	sut := NewProvider(issuer, clientID, secret, redirect)
+
+	t.Run("AuthURL", func(t *testing.T) {
+		authURL, err := sut.AuthURL(
+			context.Background(), "st4t3", nonce, Challenge(verifier),
+		)
+		assert.Nil(t.Fatal, err)
+
+		u, err := url.Parse(authURL)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, u.Path, "/authorize")
+		q := u.Query()
+		assert.Equal(t.Error, q.Get("prompt"), "login")
+		assert.Equal(t.Error, q.Get("response_type"), "code")
+		assert.Equal(t.Error, q.Get("client_id"), clientID)
+		assert.Equal(t.Error, q.Get("redirect_uri"), redirect)
+		assert.Equal(t.Error, q.Get("scope"), "openid email profile")
+		assert.Equal(t.Error, q.Get("state"), "st4t3")
+		assert.Equal(t.Error, q.Get("nonce"), nonce)
+		assert.Equal(t.Error, q.Get("code_challenge"), Challenge(verifier))
+		assert.Equal(t.Error, q.Get("code_challenge_method"), "S256")
+	})
+
+	t.Run("Exchange", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			tkStatus   int
+			idToken    string
+			wantErr    error
+			wantClaims Claims
+		}{
+			{
+				name:       "TokenEndpointErr",
+				tkStatus:   http.StatusBadRequest,
+				idToken:    "",
+				wantErr:    nil,
+				wantClaims: Claims{},
+			},
+			{
+				name:     "AlgHS256",
+				tkStatus: http.StatusOK,
+				idToken: sign(
+					jwt.SigningMethodHS256,
+					[]byte(key.Key.Public().(ed25519.PublicKey)),
+					claimsWith(nil),
+				),
+				wantErr:    jwt.ErrTokenSignatureInvalid,
+				wantClaims: Claims{},
+			},
+			{
+				name:     "WrongIssuer",
+				tkStatus: http.StatusOK,
+				idToken: sign(key.Method, key.Key, claimsWith(jwt.MapClaims{
+					"iss": "https://evil.io",
+				})),
+				wantErr:    ErrInvalidToken,
+				wantClaims: Claims{},
+			},
+			{
+				name:     "WrongAudience",
+				tkStatus: http.StatusOK,
+				idToken: sign(key.Method, key.Key, claimsWith(jwt.MapClaims{
+					"aud": "client2",
+				})),
+				wantErr:    ErrInvalidToken,
+				wantClaims: Claims{},
+			},
+			{
+				name:     "NoExpiry",
+				tkStatus: http.StatusOK,
+				idToken: sign(key.Method, key.Key, claimsWith(jwt.MapClaims{
+					"exp": nil,
+				})),
+				wantErr:    ErrInvalidToken,
+				wantClaims: Claims{},
+			},
+			{
+				name:     "WrongNonce",
+				tkStatus: http.StatusOK,
+				idToken: sign(key.Method, key.Key, claimsWith(jwt.MapClaims{
+					"nonce": "0th3r",
+				})),
+				wantErr:    ErrInvalidToken,
+				wantClaims: Claims{},
+			},
+			{
+				name:     "NoSubject",
+				tkStatus: http.StatusOK,
+				idToken: sign(key.Method, key.Key, claimsWith(jwt.MapClaims{
+					"sub": nil,
+				})),
+				wantErr:    ErrInvalidToken,
+				wantClaims: Claims{},
+			},
+			{
+				name:     "OK",
+				tkStatus: http.StatusOK,
+				idToken: sign(key.Method, key.Key, claimsWith(jwt.MapClaims{
+					"aud": []string{"client0", clientID},
+				})),
+				wantErr: nil,
+				wantClaims: Claims{
+					Issuer:            issuer,
+					Subject:           "sub123",
+					Email:             "bob@goteam.io",
+					EmailVerified:     true,
+					PreferredUsername: "bob123",
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				tkStatus = c.tkStatus
+				idToken = c.idToken
+
+				claims, err := sut.Exchange(
+					context.Background(), "c0d3", verifier, nonce,
+				)
+
+				assert.Equal(t.Error, tkForm.Get("grant_type"),
+					"authorization_code")
+				assert.Equal(t.Error, tkForm.Get("code"), "c0d3")
+				assert.Equal(t.Error, tkForm.Get("code_verifier"), verifier)
+				assert.Equal(t.Error, tkForm.Get("redirect_uri"), redirect)
+				assert.Equal(t.Error, tkSecret, secret)
+				if c.tkStatus != http.StatusOK {
+					assert.Equal(t.Error, err.Error(),
+						"token exchange failed: 400 invalid_grant")
+				} else {
+					assert.ErrIs(t.Error, err, c.wantErr)
+				}
+				assert.Equal(t.Error, claims, c.wantClaims)
+			})
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package password
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// bloomMagic is written at the start of bloom filter files to tell them apart
+// from corpora.
+const bloomMagic = "GTBLOOM1"
+
+// ErrInvalidBloomFilter means that the data a bloom filter was read from was
+// not written by BloomFilter.WriteTo.
+var ErrInvalidBloomFilter = errors.New("invalid bloom filter")
+
+// BloomFilter is a Blocklist that stores a breached password corpus in a
+// fraction of the memory a Corpus would take, at the cost of occasionally
+// reporting a password that is not in the corpus as breached.
+type BloomFilter struct {
+	k    uint64   // number of bits set per password
+	bits []uint64 // the filter, 64 bits per element
+}
+
+// NewBloomFilter creates and returns an empty BloomFilter sized to hold n
+// passwords with a false positive rate of fpRate.
+func NewBloomFilter(n uint64, fpRate float64) *BloomFilter {
+	if n == 0 {
+		n = 1
+	}
+	m := math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	if k < 1 {
+		k = 1
+	}
+	return &BloomFilter{
+		k:    uint64(k),
+		bits: make([]uint64, (uint64(m)+63)/64),
+	}
+}
+
+// Add adds pwd to the filter.
+func (f *BloomFilter) Add(pwd string) { f.AddDigest(NewDigest(pwd)) }
+
+// AddDigest adds the password with the digest d to the filter.
+func (f *BloomFilter) AddDigest(d Digest) {
+	f.each(d, func(i uint64) bool {
+		f.bits[i/64] |= 1 << (i % 64)
+		return true
+	})
+}
+
+// Contains returns whether pwd may be in the filter.
+func (f *BloomFilter) Contains(pwd string) bool {
+	return f.each(NewDigest(pwd), func(i uint64) bool {
+		return f.bits[i/64]&(1<<(i%64)) != 0
+	})
+}
+
+// each calls fn with the index of each bit for digest d, deriving them from
+// two halves of the digest by double hashing. It stops and returns false as
+// soon as fn returns false.
+func (f *BloomFilter) each(d Digest, fn func(uint64) bool) bool {
+	m := uint64(len(f.bits)) * 64
+	h1 := binary.LittleEndian.Uint64(d[0:8])
+	h2 := binary.LittleEndian.Uint64(d[8:16])
+	for i := uint64(0); i < f.k; i++ {
+		if !fn((h1 + i*h2) % m) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteTo writes the filter to w so that it can be read with ReadBloomFilter.
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, len(bloomMagic)+16+len(f.bits)*8)
+	copy(buf, bloomMagic)
+	off := len(bloomMagic)
+	binary.LittleEndian.PutUint64(buf[off:], f.k)
+	binary.LittleEndian.PutUint64(buf[off+8:], uint64(len(f.bits)))
+	off += 16
+	for _, word := range f.bits {
+		binary.LittleEndian.PutUint64(buf[off:], word)
+		off += 8
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ReadBloomFilter reads a BloomFilter written by BloomFilter.WriteTo from r.
+func ReadBloomFilter(r io.Reader) (*BloomFilter, error) {
+	header := make([]byte, len(bloomMagic)+16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrInvalidBloomFilter
+	}
+	if string(header[:len(bloomMagic)]) != bloomMagic {
+		return nil, ErrInvalidBloomFilter
+	}
+	k := binary.LittleEndian.Uint64(header[len(bloomMagic):])
+	words := binary.LittleEndian.Uint64(header[len(bloomMagic)+8:])
+	if k == 0 || words == 0 {
+		return nil, ErrInvalidBloomFilter
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) != words*8 {
+		return nil, ErrInvalidBloomFilter
+	}
+	f := &BloomFilter{k: k, bits: make([]uint64, words)}
+	for i := range f.bits {
+		f.bits[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+	return f, nil
+}
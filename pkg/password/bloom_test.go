@@ -0,0 +1,80 @@
+//go:build utest
+
+package password
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	const n = 1000
+	sut := NewBloomFilter(n, 0.01)
+	for i := 0; i < n; i++ {
+		sut.Add("breached" + strconv.Itoa(i))
+	}
+
+	t.Run("NoFalseNegatives", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			if pwd := "breached" + strconv.Itoa(i); !sut.Contains(pwd) {
+				t.Fatalf("%s was not in the filter", pwd)
+			}
+		}
+	})
+
+	t.Run("FalsePositiveRate", func(t *testing.T) {
+		fps := 0
+		for i := 0; i < 10*n; i++ {
+			if sut.Contains("safe" + strconv.Itoa(i)) {
+				fps++
+			}
+		}
+		// 1% of the 10n checks is n/10 false positives, allow twice as many
+		assert.True(t.Error, fps < n/5)
+	})
+
+	t.Run("AddDigest", func(t *testing.T) {
+		f := NewBloomFilter(1, 0.01)
+		f.AddDigest(NewDigest("password"))
+
+		assert.True(t.Error, f.Contains("password"))
+	})
+
+	t.Run("WriteRead", func(t *testing.T) {
+		var buf bytes.Buffer
+		written, err := sut.WriteTo(&buf)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, written, int64(buf.Len()))
+
+		got, err := ReadBloomFilter(&buf)
+		assert.Nil(t.Fatal, err)
+
+		assert.Equal(t.Error, got.k, sut.k)
+		assert.AllEqual(t.Error, got.bits, sut.bits)
+	})
+
+	t.Run("ReadInvalid", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := sut.WriteTo(&buf)
+		assert.Nil(t.Fatal, err)
+		data := buf.Bytes()
+
+		for _, c := range []struct {
+			name string
+			data []byte
+		}{
+			{name: "Empty", data: nil},
+			{name: "BadMagic", data: append([]byte("NOTBLOOM"), data[8:]...)},
+			{name: "Truncated", data: data[:len(data)-1]},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				_, err := ReadBloomFilter(bytes.NewReader(c.data))
+
+				assert.ErrIs(t.Error, err, ErrInvalidBloomFilter)
+			})
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+// Blocklist is a set of passwords that are known to be unsafe.
+type Blocklist interface{ Contains(pwd string) bool }
+
+// Digest is the SHA-1 digest of a password, which is the format that breached
+// password corpora such as Have I Been Pwned are distributed in.
+type Digest [sha1.Size]byte
+
+// NewDigest returns the SHA-1 digest of pwd.
+func NewDigest(pwd string) Digest { return sha1.Sum([]byte(pwd)) }
+
+// Corpus is a Blocklist that holds the digest of every password in a breached
+// password corpus in memory.
+type Corpus map[Digest]struct{}
+
+// Contains returns whether pwd is in the corpus.
+func (c Corpus) Contains(pwd string) bool {
+	_, ok := c[NewDigest(pwd)]
+	return ok
+}
+
+// ReadCorpus reads a breached password corpus from r. See ScanCorpus for the
+// format it is expected to be in.
+func ReadCorpus(r io.Reader) (Corpus, error) {
+	c := Corpus{}
+	err := ScanCorpus(r, func(d Digest) { c[d] = struct{}{} })
+	return c, err
+}
+
+// ScanCorpus reads a breached password corpus from r and calls fn with the
+// digest of each password in it. The corpus must have one entry per line, each
+// being either a plaintext password or a hex-encoded SHA-1 digest optionally
+// followed by a colon and a count, as in Have I Been Pwned's downloads. Blank
+// lines are skipped.
+func ScanCorpus(r io.Reader, fn func(Digest)) error {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		fn(parseEntry(line))
+	}
+	return s.Err()
+}
+
+// parseEntry returns the digest of the password in a line of a breached
+// password corpus.
+func parseEntry(line string) Digest {
+	hexLen := hex.EncodedLen(sha1.Size)
+	if len(line) == hexLen || (len(line) > hexLen && line[hexLen] == ':') {
+		var d Digest
+		if _, err := hex.Decode(d[:], []byte(line[:hexLen])); err == nil {
+			return d
+		}
+	}
+	return NewDigest(line)
+}
+
+// ReadBlocklist reads a Blocklist from r, which can be either a bloom filter
+// written by BloomFilter.WriteTo or a corpus in the format ScanCorpus expects.
+func ReadBlocklist(r io.Reader) (Blocklist, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(len(bloomMagic)); err == nil &&
+		string(magic) == bloomMagic {
+		return ReadBloomFilter(br)
+	}
+	return ReadCorpus(br)
+}
@@ -0,0 +1,85 @@
+//go:build utest
+
+package password
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestCorpus(t *testing.T) {
+	// hexDigest encodes the digest of pwd as Have I Been Pwned does
+	hexDigest := func(pwd string) string {
+		d := NewDigest(pwd)
+		return strings.ToUpper(hex.EncodeToString(d[:]))
+	}
+
+	t.Run("Plaintext", func(t *testing.T) {
+		sut, err := ReadCorpus(strings.NewReader("password\r\n\nletmein\n"))
+		assert.Nil(t.Fatal, err)
+
+		assert.Equal(t.Error, len(sut), 2)
+		assert.True(t.Error, sut.Contains("password"))
+		assert.True(t.Error, sut.Contains("letmein"))
+		assert.True(t.Error, !sut.Contains("Password"))
+		assert.True(t.Error, !sut.Contains(""))
+	})
+
+	t.Run("Digests", func(t *testing.T) {
+		sut, err := ReadCorpus(strings.NewReader(
+			hexDigest("password") + "\n" + hexDigest("letmein") + ":42\n",
+		))
+		assert.Nil(t.Fatal, err)
+
+		assert.Equal(t.Error, len(sut), 2)
+		assert.True(t.Error, sut.Contains("password"))
+		assert.True(t.Error, sut.Contains("letmein"))
+		assert.True(t.Error, !sut.Contains("P4ssw@rd123"))
+	})
+
+	t.Run("NotHexDigest", func(t *testing.T) {
+		// 40 characters long but not hex, so it must be read as plaintext
+		pwd := strings.Repeat("z", 40)
+		sut, err := ReadCorpus(strings.NewReader(pwd + "\n"))
+		assert.Nil(t.Fatal, err)
+
+		assert.True(t.Error, sut.Contains(pwd))
+	})
+}
+
+func TestReadBlocklist(t *testing.T) {
+	t.Run("Corpus", func(t *testing.T) {
+		sut, err := ReadBlocklist(strings.NewReader("password\n"))
+		assert.Nil(t.Fatal, err)
+
+		_, ok := sut.(Corpus)
+		assert.True(t.Error, ok)
+		assert.True(t.Error, sut.Contains("password"))
+	})
+
+	t.Run("BloomFilter", func(t *testing.T) {
+		f := NewBloomFilter(10, 0.01)
+		f.Add("password")
+		var buf bytes.Buffer
+		_, err := f.WriteTo(&buf)
+		assert.Nil(t.Fatal, err)
+
+		sut, err := ReadBlocklist(&buf)
+		assert.Nil(t.Fatal, err)
+
+		_, ok := sut.(*BloomFilter)
+		assert.True(t.Error, ok)
+		assert.True(t.Error, sut.Contains("password"))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		sut, err := ReadBlocklist(strings.NewReader(""))
+		assert.Nil(t.Fatal, err)
+
+		assert.True(t.Error, !sut.Contains("password"))
+	})
+}
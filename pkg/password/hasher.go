@@ -0,0 +1,140 @@
+package password
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hasher can be used to hash passwords with argon2id and to verify passwords
+// against both argon2id hashes and the bcrypt hashes that were created before
+// argon2id was adopted.
+type Hasher struct{ params Params }
+
+// NewHasher creates and returns a new Hasher that hashes passwords with the
+// given parameters.
+func NewHasher(params Params) Hasher { return Hasher{params: params} }
+
+// Hash hashes a plaintext password with a random salt and returns the hash in
+// the PHC string format, e.g. $argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>.
+func (h Hasher) Hash(plaintext string) ([]byte, error) {
+	salt := make([]byte, h.params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey(
+		[]byte(plaintext),
+		salt,
+		h.params.Time,
+		h.params.Memory,
+		h.params.Threads,
+		h.params.KeyLen,
+	)
+	return []byte(fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory,
+		h.params.Time,
+		h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)), nil
+}
+
+// Compare compares a plaintext password with a hash. It returns ErrMismatch if
+// they don't match. Users who log in through an identity provider may not have
+// a password, in which case no input matches.
+func (h Hasher) Compare(hash []byte, plaintext string) error {
+	if len(hash) == 0 {
+		return ErrMismatch
+	}
+
+	if isBcrypt(hash) {
+		err := bcrypt.CompareHashAndPassword(hash, []byte(plaintext))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatch
+		}
+		return err
+	}
+
+	params, salt, key, err := decode(hash)
+	if err != nil {
+		return err
+	}
+	got := argon2.IDKey(
+		[]byte(plaintext),
+		salt,
+		params.Time,
+		params.Memory,
+		params.Threads,
+		params.KeyLen,
+	)
+	if subtle.ConstantTimeCompare(got, key) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// Rehash returns a new hash of a plaintext password if its current hash was
+// created with bcrypt or with argon2id parameters other than the Hasher's, and
+// nil otherwise. It should only be called once Compare has confirmed that the
+// plaintext matches the hash.
+func (h Hasher) Rehash(hash []byte, plaintext string) ([]byte, error) {
+	if !isBcrypt(hash) {
+		params, _, _, err := decode(hash)
+		if err != nil {
+			return nil, err
+		}
+		if params == h.params {
+			return nil, nil
+		}
+	}
+	return h.Hash(plaintext)
+}
+
+// isBcrypt returns whether the given hash was created with bcrypt, whose
+// hashes start with $2a$, $2b$, or $2y$.
+func isBcrypt(hash []byte) bool { return bytes.HasPrefix(hash, []byte("$2")) }
+
+// decode parses an argon2id hash in the PHC string format and returns the
+// parameters, the salt, and the key that it contains.
+func decode(hash []byte) (Params, []byte, []byte, error) {
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(
+		parts[2], "v=%d", &version,
+	); err != nil || version != argon2.Version {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+
+	var params Params
+	if _, err := fmt.Sscanf(
+		parts[3], "m=%d,t=%d,p=%d",
+		&params.Memory, &params.Time, &params.Threads,
+	); err != nil {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Params{}, nil, nil, ErrInvalidHash
+	}
+	params.SaltLen, params.KeyLen = uint32(len(salt)), uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,167 @@
+//go:build utest
+
+package password
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+// testParams are cheaper than DefaultParams so that the tests run fast.
+var testParams = Params{
+	Memory: 64, Time: 1, Threads: 1, SaltLen: 8, KeyLen: 16,
+}
+
+func TestHasher(t *testing.T) {
+	sut := NewHasher(testParams)
+
+	t.Run("Hash", func(t *testing.T) {
+		hash, err := sut.Hash("password")
+		assert.Nil(t.Fatal, err)
+
+		params, salt, key, err := decode(hash)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, params, testParams)
+		assert.Equal(t.Error, len(salt), 8)
+		assert.Equal(t.Error, len(key), 16)
+
+		// the same password must be hashed with a different salt every time
+		otherHash, err := sut.Hash("password")
+		assert.Nil(t.Fatal, err)
+		assert.True(t.Error, string(otherHash) != string(hash))
+	})
+
+	t.Run("Compare", func(t *testing.T) {
+		argonHash, err := sut.Hash("password")
+		assert.Nil(t.Fatal, err)
+		bcryptHash, err := bcrypt.GenerateFromPassword(
+			[]byte("password"), bcrypt.MinCost,
+		)
+		assert.Nil(t.Fatal, err)
+
+		for _, c := range []struct {
+			name      string
+			hash      []byte
+			plaintext string
+			wantErr   error
+		}{
+			{
+				name:      "NoPassword",
+				hash:      nil,
+				plaintext: "password",
+				wantErr:   ErrMismatch,
+			},
+			{
+				name:      "InvalidHash",
+				hash:      []byte("$argon2id$v=19$m=64,t=1,p=1$c2FsdA"),
+				plaintext: "password",
+				wantErr:   ErrInvalidHash,
+			},
+			{
+				name: "UnsupportedVersion",
+				hash: []byte(
+					"$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5a2V5a2V5",
+				),
+				plaintext: "password",
+				wantErr:   ErrInvalidHash,
+			},
+			{
+				name:      "BcryptErr",
+				hash:      []byte("$2a$04$ngqMWrzBWyg8K"),
+				plaintext: "password",
+				wantErr:   bcrypt.ErrHashTooShort,
+			},
+			{
+				name:      "BcryptMismatch",
+				hash:      bcryptHash,
+				plaintext: "differentPassword",
+				wantErr:   ErrMismatch,
+			},
+			{
+				name:      "BcryptMatch",
+				hash:      bcryptHash,
+				plaintext: "password",
+				wantErr:   nil,
+			},
+			{
+				name:      "Argon2idMismatch",
+				hash:      argonHash,
+				plaintext: "differentPassword",
+				wantErr:   ErrMismatch,
+			},
+			{
+				name:      "Argon2idMatch",
+				hash:      argonHash,
+				plaintext: "password",
+				wantErr:   nil,
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				err := sut.Compare(c.hash, c.plaintext)
+
+				assert.ErrIs(t.Error, err, c.wantErr)
+			})
+		}
+	})
+
+	t.Run("Rehash", func(t *testing.T) {
+		currentHash, err := sut.Hash("password")
+		assert.Nil(t.Fatal, err)
+		oldParams := testParams
+		oldParams.Time = 2
+		oldHash, err := NewHasher(oldParams).Hash("password")
+		assert.Nil(t.Fatal, err)
+		bcryptHash, err := bcrypt.GenerateFromPassword(
+			[]byte("password"), bcrypt.MinCost,
+		)
+		assert.Nil(t.Fatal, err)
+
+		for _, c := range []struct {
+			name       string
+			hash       []byte
+			wantRehash bool
+			wantErr    error
+		}{
+			{
+				name:       "InvalidHash",
+				hash:       []byte("$scrypt$ln=16,r=8,p=1$c2FsdA$a2V5"),
+				wantRehash: false,
+				wantErr:    ErrInvalidHash,
+			},
+			{
+				name:       "Current",
+				hash:       currentHash,
+				wantRehash: false,
+				wantErr:    nil,
+			},
+			{
+				name:       "OutdatedParams",
+				hash:       oldHash,
+				wantRehash: true,
+				wantErr:    nil,
+			},
+			{
+				name:       "Bcrypt",
+				hash:       bcryptHash,
+				wantRehash: true,
+				wantErr:    nil,
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				newHash, err := sut.Rehash(c.hash, "password")
+
+				assert.ErrIs(t.Fatal, err, c.wantErr)
+				assert.Equal(t.Fatal, newHash != nil, c.wantRehash)
+				if c.wantRehash {
+					params, _, _, err := decode(newHash)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, params, testParams)
+					assert.Nil(t.Error, sut.Compare(newHash, "password"))
+				}
+			})
+		}
+	})
+}
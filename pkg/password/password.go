@@ -0,0 +1,34 @@
+// Package password contains code for hashing passwords into self-describing
+// hashes that store the algorithm and parameters they were created with, and
+// for verifying passwords against them.
+package password
+
+import "errors"
+
+var (
+	// ErrMismatch means that a password did not match the hash it was
+	// compared with.
+	ErrMismatch = errors.New("password mismatch")
+
+	// ErrInvalidHash means that a hash was not in a format that is supported.
+	ErrInvalidHash = errors.New("invalid password hash")
+)
+
+// Params defines the argon2id parameters that passwords are hashed with.
+type Params struct {
+	Memory  uint32 // in KiB
+	Time    uint32 // number of passes over the memory
+	Threads uint8
+	SaltLen uint32 // in bytes
+	KeyLen  uint32 // in bytes
+}
+
+// DefaultParams are the argon2id parameters recommended by OWASP, which take
+// around 20ms to hash a password with on a single core.
+var DefaultParams = Params{
+	Memory:  19 * 1024,
+	Time:    2,
+	Threads: 1,
+	SaltLen: 16,
+	KeyLen:  32,
+}
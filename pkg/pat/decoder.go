@@ -0,0 +1,84 @@
+package pat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+)
+
+// Decoder is a cookie.Decoder[cookie.Auth] that accepts personal access tokens
+// in addition to auth tokens. A Decoder is created for each route with the
+// scope that the route requires, and it only accepts personal access tokens
+// that were granted it. All other tokens are passed on to the auth decoder.
+type Decoder struct {
+	authDecoder    cookie.Decoder[cookie.Auth]
+	tokenRetriever db.Retriever[tokentbl.Token]
+	userRetriever  db.Retriever[usertbl.User]
+	scope          string
+}
+
+// NewDecoder creates and returns a new Decoder.
+func NewDecoder(
+	authDecoder cookie.Decoder[cookie.Auth],
+	tokenRetriever db.Retriever[tokentbl.Token],
+	userRetriever db.Retriever[usertbl.User],
+	scope string,
+) Decoder {
+	return Decoder{
+		authDecoder:    authDecoder,
+		tokenRetriever: tokenRetriever,
+		userRetriever:  userRetriever,
+		scope:          scope,
+	}
+}
+
+// Decode validates the personal access token in the given cookie and returns
+// an Auth for its user. The user is retrieved on every request so that
+// changes to their team or role apply to their tokens immediately.
+func (d Decoder) Decode(ck http.Cookie) (cookie.Auth, error) {
+	if !strings.HasPrefix(ck.Value, Prefix) {
+		return d.authDecoder.Decode(ck)
+	}
+
+	ctx := context.Background()
+	token, err := d.tokenRetriever.Retrieve(
+		ctx, tokentbl.HashToken(ck.Value),
+	)
+	if errors.Is(err, db.ErrNoItem) {
+		return cookie.Auth{}, cookie.ErrInvalid
+	}
+	if err != nil {
+		return cookie.Auth{}, err
+	}
+
+	// expired tokens are rejected since DynamoDB doesn't delete them as soon
+	// as their TTL passes
+	if token.ExpiresAt <= time.Now().Unix() {
+		return cookie.Auth{}, cookie.ErrInvalid
+	}
+
+	if !Allows(token.Scopes, d.scope) {
+		return cookie.Auth{}, ErrScope
+	}
+
+	user, err := d.userRetriever.Retrieve(ctx, token.Username)
+	if errors.Is(err, db.ErrNoItem) {
+		return cookie.Auth{}, cookie.ErrInvalid
+	}
+	if err != nil {
+		return cookie.Auth{}, err
+	}
+
+	auth := cookie.NewAuth(user.Username, user.Role, user.TeamID)
+	auth.IssuedAtMilli = token.CreatedAt * 1000
+	auth.ExpiresAt = token.ExpiresAt
+	auth.Scopes = token.Scopes
+	return auth, nil
+}
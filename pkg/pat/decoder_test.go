@@ -0,0 +1,152 @@
+//go:build utest
+
+package pat
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/role"
+)
+
+func TestDecoder(t *testing.T) {
+	authDecoder := &cookie.FakeDecoder[cookie.Auth]{}
+	tokenRetriever := &db.FakeRetriever[tokentbl.Token]{}
+	userRetriever := &db.FakeRetriever[usertbl.User]{}
+	sut := NewDecoder(
+		authDecoder, tokenRetriever, userRetriever, ScopeTasksRead,
+	)
+
+	authDecoder.Res = cookie.Auth{Username: "fromjwt"}
+	userRetriever.Res = usertbl.User{
+		Username: "bob123", Role: role.Admin, TeamID: "teamid",
+	}
+	validToken := tokentbl.Token{
+		Username:  "bob123",
+		Scopes:    []string{ScopeTasksWrite},
+		CreatedAt: 1700000000,
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+	errA := errors.New("failed")
+
+	for _, c := range []struct {
+		name         string
+		value        string
+		token        tokentbl.Token
+		errToken     error
+		errUser      error
+		wantUsername string
+		wantErr      error
+	}{
+		{
+			name:         "AuthToken",
+			value:        "eyJhbGciOiJFZERTQSJ9",
+			token:        validToken,
+			errToken:     nil,
+			errUser:      nil,
+			wantUsername: "fromjwt",
+			wantErr:      nil,
+		},
+		{
+			name:         "TokenNotFound",
+			value:        Prefix + "abc",
+			token:        tokentbl.Token{},
+			errToken:     db.ErrNoItem,
+			errUser:      nil,
+			wantUsername: "",
+			wantErr:      cookie.ErrInvalid,
+		},
+		{
+			name:         "ErrRetrieveToken",
+			value:        Prefix + "abc",
+			token:        tokentbl.Token{},
+			errToken:     errA,
+			errUser:      nil,
+			wantUsername: "",
+			wantErr:      errA,
+		},
+		{
+			name:  "Expired",
+			value: Prefix + "abc",
+			token: tokentbl.Token{
+				Username:  "bob123",
+				Scopes:    []string{ScopeTasksRead},
+				ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+			},
+			errToken:     nil,
+			errUser:      nil,
+			wantUsername: "",
+			wantErr:      cookie.ErrInvalid,
+		},
+		{
+			name:  "InsufficientScope",
+			value: Prefix + "abc",
+			token: tokentbl.Token{
+				Username:  "bob123",
+				Scopes:    []string{ScopeTeamWrite},
+				ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			},
+			errToken:     nil,
+			errUser:      nil,
+			wantUsername: "",
+			wantErr:      ErrScope,
+		},
+		{
+			name:         "UserNotFound",
+			value:        Prefix + "abc",
+			token:        validToken,
+			errToken:     nil,
+			errUser:      db.ErrNoItem,
+			wantUsername: "",
+			wantErr:      cookie.ErrInvalid,
+		},
+		{
+			name:         "ErrRetrieveUser",
+			value:        Prefix + "abc",
+			token:        validToken,
+			errToken:     nil,
+			errUser:      errA,
+			wantUsername: "",
+			wantErr:      errA,
+		},
+		{
+			name:         "OK",
+			value:        Prefix + "abc",
+			token:        validToken,
+			errToken:     nil,
+			errUser:      nil,
+			wantUsername: "bob123",
+			wantErr:      nil,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			tokenRetriever.Res = c.token
+			tokenRetriever.Err = c.errToken
+			userRetriever.Err = c.errUser
+
+			auth, err := sut.Decode(http.Cookie{
+				Name: cookie.AuthName, Value: c.value,
+			})
+
+			assert.ErrIs(t.Fatal, err, c.wantErr)
+			assert.Equal(t.Error, auth.Username, c.wantUsername)
+			if c.name == "OK" {
+				assert.Equal(t.Error, auth.Role, role.Admin)
+				assert.True(t.Error, auth.IsAdmin)
+				assert.Equal(t.Error, auth.TeamID, "teamid")
+				assert.Equal(t.Error,
+					auth.IssuedAtMilli, validToken.CreatedAt*1000,
+				)
+				assert.Equal(t.Error, auth.ExpiresAt, validToken.ExpiresAt)
+				assert.AllEqual(t.Error, auth.Scopes, validToken.Scopes)
+			}
+		})
+	}
+}
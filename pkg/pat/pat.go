@@ -0,0 +1,63 @@
+// Package pat contains code for creating personal access tokens and
+// authenticating requests made with them, which users can use to call the APIs
+// from scripts without logging in through the client.
+package pat
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+// Prefix is prepended to all personal access tokens so that they can be told
+// apart from auth tokens, and found by secret scanners.
+const Prefix = "gtp_"
+
+// Scopes that can be granted to a personal access token. Each write scope also
+// grants the corresponding read scope.
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+	ScopeTeamRead   = "team:read"
+	ScopeTeamWrite  = "team:write"
+)
+
+// implied maps each valid scope to the scopes that it grants.
+var implied = map[string][]string{
+	ScopeTasksRead:  {ScopeTasksRead},
+	ScopeTasksWrite: {ScopeTasksRead, ScopeTasksWrite},
+	ScopeTeamRead:   {ScopeTeamRead},
+	ScopeTeamWrite:  {ScopeTeamRead, ScopeTeamWrite},
+}
+
+// ErrScope means that the personal access token was valid but none of its
+// scopes allowed the route it was used on.
+var ErrScope = errors.New("insufficient scope")
+
+// New generates and returns a new personal access token.
+func New() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return Prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// IsValidScope returns whether scope is one that can be granted to a personal
+// access token.
+func IsValidScope(scope string) bool {
+	_, ok := implied[scope]
+	return ok
+}
+
+// Allows returns whether any of the given scopes grants the required scope.
+func Allows(scopes []string, required string) bool {
+	for _, s := range scopes {
+		for _, granted := range implied[s] {
+			if granted == required {
+				return true
+			}
+		}
+	}
+	return false
+}
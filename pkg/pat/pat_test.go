@@ -0,0 +1,81 @@
+//go:build utest
+
+package pat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestNew(t *testing.T) {
+	tkA, err := New()
+	assert.Nil(t.Fatal, err)
+	tkB, err := New()
+	assert.Nil(t.Fatal, err)
+
+	assert.True(t.Error, strings.HasPrefix(tkA, Prefix))
+	assert.Equal(t.Error, len(tkA), len(Prefix)+43)
+	assert.True(t.Error, tkA != tkB)
+}
+
+func TestIsValidScope(t *testing.T) {
+	for _, s := range []string{
+		ScopeTasksRead, ScopeTasksWrite, ScopeTeamRead, ScopeTeamWrite,
+	} {
+		assert.True(t.Error, IsValidScope(s))
+	}
+	assert.True(t.Error, !IsValidScope(""))
+	assert.True(t.Error, !IsValidScope("tasks"))
+}
+
+func TestAllows(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		scopes   []string
+		required string
+		want     bool
+	}{
+		{
+			name:     "NoScopes",
+			scopes:   nil,
+			required: ScopeTasksRead,
+			want:     false,
+		},
+		{
+			name:     "Exact",
+			scopes:   []string{ScopeTeamRead, ScopeTasksRead},
+			required: ScopeTasksRead,
+			want:     true,
+		},
+		{
+			name:     "WriteGrantsRead",
+			scopes:   []string{ScopeTasksWrite},
+			required: ScopeTasksRead,
+			want:     true,
+		},
+		{
+			name:     "ReadDoesNotGrantWrite",
+			scopes:   []string{ScopeTasksRead},
+			required: ScopeTasksWrite,
+			want:     false,
+		},
+		{
+			name:     "OtherResource",
+			scopes:   []string{ScopeTeamWrite},
+			required: ScopeTasksRead,
+			want:     false,
+		},
+		{
+			name:     "UnknownScope",
+			scopes:   []string{"tasks:*"},
+			required: ScopeTasksRead,
+			want:     false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, Allows(c.scopes, c.required), c.want)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package quota contains code for limiting how much of each resource a team
+// can use.
+package quota
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Unlimited is the limit that allows any amount of a resource.
+const Unlimited = -1
+
+// Limits defines how much of each resource a team can use. Each team uses the
+// limits that are configured for all teams, apart from the ones that are
+// overridden on the team.
+type Limits struct {
+	Boards          int `json:"boards" dynamodbav:",omitempty"`
+	TasksPerBoard   int `json:"tasksPerBoard" dynamodbav:",omitempty"`
+	Members         int `json:"members" dynamodbav:",omitempty"`
+	SubtasksPerTask int `json:"subtasksPerTask" dynamodbav:",omitempty"`
+}
+
+// DefaultLimits are the limits that are used for the resources that no limit
+// is configured for. Teams could have up to three boards before quotas were
+// introduced, so that is kept as the default.
+var DefaultLimits = Limits{
+	Boards:          3,
+	TasksPerBoard:   Unlimited,
+	Members:         Unlimited,
+	SubtasksPerTask: Unlimited,
+}
+
+// Override returns l with each of its limits replaced by the one in o, unless
+// the one in o is zero.
+func (l Limits) Override(o Limits) Limits {
+	if o.Boards != 0 {
+		l.Boards = o.Boards
+	}
+	if o.TasksPerBoard != 0 {
+		l.TasksPerBoard = o.TasksPerBoard
+	}
+	if o.Members != 0 {
+		l.Members = o.Members
+	}
+	if o.SubtasksPerTask != 0 {
+		l.SubtasksPerTask = o.SubtasksPerTask
+	}
+	return l
+}
+
+// Allows returns whether limit allows n more of a resource to be used when
+// used of it is already in use.
+func Allows(limit, used, n int) bool {
+	return limit < 0 || used+n <= limit
+}
+
+// names of the environment variables that the limits for all teams are read
+// from
+const (
+	envBoards          = "QUOTA_BOARDS"
+	envTasksPerBoard   = "QUOTA_TASKS_PER_BOARD"
+	envMembers         = "QUOTA_MEMBERS"
+	envSubtasksPerTask = "QUOTA_SUBTASKS_PER_TASK"
+)
+
+// LoadLimits reads the limits for all teams from environment variables. A
+// negative limit means the resource is unlimited, and DefaultLimits are used
+// for the environment variables that are empty.
+func LoadLimits() (Limits, error) {
+	l := DefaultLimits
+	for _, v := range []struct {
+		env   string
+		limit *int
+	}{
+		{env: envBoards, limit: &l.Boards},
+		{env: envTasksPerBoard, limit: &l.TasksPerBoard},
+		{env: envMembers, limit: &l.Members},
+		{env: envSubtasksPerTask, limit: &l.SubtasksPerTask},
+	} {
+		s := os.Getenv(v.env)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return Limits{}, fmt.Errorf("%s was invalid: %w", v.env, err)
+		}
+		if n < 0 {
+			n = Unlimited
+		}
+		*v.limit = n
+	}
+	return l, nil
+}
@@ -0,0 +1,82 @@
+//go:build utest
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestOverride(t *testing.T) {
+	defaults := Limits{
+		Boards: 3, TasksPerBoard: 100, Members: Unlimited, SubtasksPerTask: 10,
+	}
+
+	got := defaults.Override(Limits{Boards: 10, Members: 5})
+
+	assert.Equal(t.Error, got, Limits{
+		Boards: 10, TasksPerBoard: 100, Members: 5, SubtasksPerTask: 10,
+	})
+	assert.Equal(t.Error, defaults.Override(Limits{}), defaults)
+	assert.Equal(t.Error,
+		defaults.Override(Limits{TasksPerBoard: Unlimited}).TasksPerBoard,
+		Unlimited,
+	)
+}
+
+func TestAllows(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		limit int
+		used  int
+		n     int
+		want  bool
+	}{
+		{name: "Unlimited", limit: Unlimited, used: 1000, n: 1, want: true},
+		{name: "UnderLimit", limit: 3, used: 2, n: 1, want: true},
+		{name: "AtLimit", limit: 3, used: 3, n: 1, want: false},
+		{name: "OverLimit", limit: 3, used: 4, n: 0, want: false},
+		{name: "ManyUnderLimit", limit: 10, used: 0, n: 10, want: true},
+		{name: "ManyOverLimit", limit: 10, used: 0, n: 11, want: false},
+		{name: "Zero", limit: 0, used: 0, n: 1, want: false},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error, Allows(c.limit, c.used, c.n), c.want)
+		})
+	}
+}
+
+func TestLoadLimits(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		got, err := LoadLimits()
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, got, DefaultLimits)
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		t.Setenv(envBoards, "5")
+		t.Setenv(envTasksPerBoard, "200")
+		t.Setenv(envMembers, "-5")
+		t.Setenv(envSubtasksPerTask, "0")
+
+		got, err := LoadLimits()
+
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, got, Limits{
+			Boards:          5,
+			TasksPerBoard:   200,
+			Members:         Unlimited,
+			SubtasksPerTask: 0,
+		})
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Setenv(envMembers, "lots")
+
+		_, err := LoadLimits()
+
+		assert.True(t.Error, err != nil)
+	})
+}
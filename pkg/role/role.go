@@ -0,0 +1,145 @@
+// Package role contains the roles that users can have in their team, and the
+// permission matrix that all handlers consult to decide whether a user is
+// allowed to perform an action.
+package role
+
+// Role defines the role of a user in their team.
+type Role string
+
+// Roles that a user can have in their team. Each team has exactly one owner,
+// who is the user that created it.
+const (
+	Owner  Role = "owner"
+	Admin  Role = "admin"
+	Member Role = "member"
+	Viewer Role = "viewer"
+)
+
+// FromIsAdmin returns the role of a user who was registered before roles were
+// introduced. Only the users who created their team were admins back then, so
+// admins map to owners and everyone else to members.
+func FromIsAdmin(isAdmin bool) Role {
+	if isAdmin {
+		return Owner
+	}
+	return Member
+}
+
+// IsValid returns whether r is one of the defined roles.
+func (r Role) IsValid() bool {
+	switch r {
+	case Owner, Admin, Member, Viewer:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAdmin returns whether r grants admin rights over the team, which both the
+// owner and the admins have.
+func (r Role) IsAdmin() bool { return r == Owner || r == Admin }
+
+// Action defines an action that is subject to permission checks.
+type Action string
+
+// Actions that are subject to permission checks.
+const (
+	ViewTasks        Action = "tasks:view"
+	CreateTask       Action = "task:create"
+	EditTask         Action = "task:edit"
+	MoveTask         Action = "task:move"
+	DeleteTask       Action = "task:delete"
+	CreateBoard      Action = "board:create"
+	EditBoard        Action = "board:edit"
+	EditBoardMembers Action = "board:members:edit"
+	DeleteBoard      Action = "board:delete"
+	EditTeam         Action = "team:edit"
+	TransferTeam     Action = "team:transfer"
+	ViewQuotas       Action = "quotas:view"
+	ViewInvites      Action = "invites:view"
+	CreateInvite     Action = "invite:create"
+	DeleteInvite     Action = "invite:delete"
+	ChangeRole       Action = "member:role"
+	RemoveMember     Action = "member:remove"
+)
+
+// Access defines the extent to which a role is allowed to perform an action.
+type Access int
+
+// Levels of access that a role can have to an action. BoardMember means that
+// the action is only allowed on the boards that the user is a member of, which
+// the caller must check before letting the action through.
+const (
+	Denied Access = iota
+	BoardMember
+	Granted
+)
+
+// matrix maps each role to the access it has to each action. Actions that are
+// missing for a role are denied.
+var matrix = map[Role]map[Action]Access{
+	Owner: {
+		ViewTasks:        Granted,
+		CreateTask:       Granted,
+		EditTask:         Granted,
+		MoveTask:         Granted,
+		DeleteTask:       Granted,
+		CreateBoard:      Granted,
+		EditBoard:        Granted,
+		EditBoardMembers: Granted,
+		DeleteBoard:      Granted,
+		EditTeam:         Granted,
+		TransferTeam:     Granted,
+		ViewQuotas:       Granted,
+		ViewInvites:      Granted,
+		CreateInvite:     Granted,
+		DeleteInvite:     Granted,
+		ChangeRole:       Granted,
+		RemoveMember:     Granted,
+	},
+	Admin: {
+		ViewTasks:        Granted,
+		CreateTask:       Granted,
+		EditTask:         Granted,
+		MoveTask:         Granted,
+		DeleteTask:       Granted,
+		CreateBoard:      Granted,
+		EditBoard:        Granted,
+		EditBoardMembers: Granted,
+		DeleteBoard:      Granted,
+		EditTeam:         Granted,
+		ViewQuotas:       Granted,
+		ViewInvites:      Granted,
+		CreateInvite:     Granted,
+		DeleteInvite:     Granted,
+		RemoveMember:     Granted,
+	},
+	Member: {
+		ViewTasks:  Granted,
+		CreateTask: BoardMember,
+		MoveTask:   BoardMember,
+	},
+	Viewer: {
+		ViewTasks: Granted,
+	},
+}
+
+// Check returns the access that role r has to action a.
+func Check(r Role, a Action) Access { return matrix[r][a] }
+
+// Can returns whether role r is allowed to perform action a on all boards of
+// the team.
+func Can(r Role, a Action) bool { return Check(r, a) == Granted }
+
+// CanOnBoard returns whether role r is allowed to perform action a on a board,
+// given whether the user is a member of that board.
+func CanOnBoard(r Role, a Action, isBoardMember bool) bool {
+	switch Check(r, a) {
+	case Granted:
+		return true
+	case BoardMember:
+		return isBoardMember
+	default:
+		return false
+	}
+}
@@ -0,0 +1,138 @@
+//go:build utest
+
+package role
+
+import (
+	"testing"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+)
+
+func TestFromIsAdmin(t *testing.T) {
+	assert.Equal(t.Error, FromIsAdmin(true), Owner)
+	assert.Equal(t.Error, FromIsAdmin(false), Member)
+}
+
+func TestIsValid(t *testing.T) {
+	for _, r := range []Role{Owner, Admin, Member, Viewer} {
+		assert.True(t.Error, r.IsValid())
+	}
+	assert.True(t.Error, !Role("").IsValid())
+	assert.True(t.Error, !Role("superuser").IsValid())
+}
+
+func TestIsAdmin(t *testing.T) {
+	assert.True(t.Error, Owner.IsAdmin())
+	assert.True(t.Error, Admin.IsAdmin())
+	assert.True(t.Error, !Member.IsAdmin())
+	assert.True(t.Error, !Viewer.IsAdmin())
+	assert.True(t.Error, !Role("").IsAdmin())
+}
+
+func TestCan(t *testing.T) {
+	assert.True(t.Error, Can(Owner, DeleteBoard))
+	assert.True(t.Error, Can(Admin, EditTask))
+	assert.True(t.Error, Can(Member, ViewTasks))
+	assert.True(t.Error, !Can(Member, CreateTask))
+	assert.True(t.Error, !Can(Member, DeleteTask))
+	assert.True(t.Error, Can(Viewer, ViewTasks))
+	assert.True(t.Error, !Can(Viewer, CreateBoard))
+	assert.True(t.Error, Can(Owner, TransferTeam))
+	assert.True(t.Error, !Can(Admin, TransferTeam))
+	assert.True(t.Error, Can(Owner, ChangeRole))
+	assert.True(t.Error, !Can(Admin, ChangeRole))
+	assert.True(t.Error, Can(Admin, CreateInvite))
+	assert.True(t.Error, Can(Admin, RemoveMember))
+	assert.True(t.Error, !Can(Member, ViewInvites))
+	assert.True(t.Error, !Can(Member, EditTeam))
+	assert.True(t.Error, !Can(Viewer, ViewQuotas))
+	assert.True(t.Error, !Can(Member, EditBoardMembers))
+	assert.True(t.Error, !Can(Role("superuser"), ViewTasks))
+}
+
+func TestCanOnBoard(t *testing.T) {
+	for _, c := range []struct {
+		name          string
+		role          Role
+		action        Action
+		isBoardMember bool
+		want          bool
+	}{
+		{
+			name:          "OwnerDeleteBoard",
+			role:          Owner,
+			action:        DeleteBoard,
+			isBoardMember: false,
+			want:          true,
+		},
+		{
+			name:          "AdminEditTask",
+			role:          Admin,
+			action:        EditTask,
+			isBoardMember: false,
+			want:          true,
+		},
+		{
+			name:          "MemberCreateTaskOnOwnBoard",
+			role:          Member,
+			action:        CreateTask,
+			isBoardMember: true,
+			want:          true,
+		},
+		{
+			name:          "MemberCreateTaskOnOtherBoard",
+			role:          Member,
+			action:        CreateTask,
+			isBoardMember: false,
+			want:          false,
+		},
+		{
+			name:          "MemberMoveTaskOnOwnBoard",
+			role:          Member,
+			action:        MoveTask,
+			isBoardMember: true,
+			want:          true,
+		},
+		{
+			name:          "MemberDeleteTask",
+			role:          Member,
+			action:        DeleteTask,
+			isBoardMember: true,
+			want:          false,
+		},
+		{
+			name:          "MemberCreateBoard",
+			role:          Member,
+			action:        CreateBoard,
+			isBoardMember: false,
+			want:          false,
+		},
+		{
+			name:          "ViewerViewTasks",
+			role:          Viewer,
+			action:        ViewTasks,
+			isBoardMember: false,
+			want:          true,
+		},
+		{
+			name:          "ViewerMoveTask",
+			role:          Viewer,
+			action:        MoveTask,
+			isBoardMember: true,
+			want:          false,
+		},
+		{
+			name:          "UnknownRole",
+			role:          Role("superuser"),
+			action:        ViewTasks,
+			isBoardMember: true,
+			want:          false,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t.Error,
+				CanOnBoard(c.role, c.action, c.isBoardMember), c.want,
+			)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package wip contains code for enforcing the work-in-progress (WIP) limits of
+// board columns.
+package wip
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+)
+
+// ParamOverride is the name of the query parameter that a user can set to
+// "true" to move tasks into columns beyond their WIP limits.
+const ParamOverride = "overrideWIP"
+
+// IsOverride returns whether the request explicitly overrides the WIP limits
+// of the columns it moves tasks into.
+func IsOverride(r *http.Request) bool {
+	return r.URL.Query().Get(ParamOverride) == "true"
+}
+
+// Violation defines a column whose WIP limit is exceeded by a change to a
+// board's tasks, and the number of tasks the column has after the change.
+type Violation struct {
+	BoardID string
+	Col     teamtbl.Column
+	Count   int
+}
+
+// Msg returns the message that a change that causes the violation is rejected
+// with.
+func (v Violation) Msg() string {
+	return "Column \"" + v.Col.Name + "\" cannot have more than " +
+		strconv.Itoa(v.Col.WIPLimit) + " tasks without overriding its WIP " +
+		"limit."
+}
+
+// Limited returns whether any of the columns that the changed tasks are placed
+// in on the team's board with the given ID has a WIP limit. The board's tasks
+// only need to be retrieved to check the change if so.
+func Limited(team teamtbl.Team, boardID string, changed []tasktbl.Task) bool {
+	for _, t := range changed {
+		col, ok := team.Column(boardID, t.ColID)
+		if ok && t.BoardID == boardID && col.WIPLimit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns the violations of the WIP limits of the columns of the team's
+// board with the given ID that changing its tasks with changed causes. Changed
+// tasks that aren't in tasks are counted as added to the board.
+//
+// A change only violates a column's limit if it adds tasks to the column, so
+// that tasks can still be edited and moved out of a column whose limit was set
+// lower than the number of tasks it already had.
+func Check(
+	team teamtbl.Team, boardID string, tasks, changed []tasktbl.Task,
+) []Violation {
+	before := count(tasks)
+	after := count(apply(tasks, changed))
+
+	var vs []Violation
+	checked := map[string]bool{}
+	for _, t := range changed {
+		if t.BoardID != boardID || checked[t.ColID] {
+			continue
+		}
+		checked[t.ColID] = true
+
+		col, ok := team.Column(boardID, t.ColID)
+		if !ok || col.WIPLimit <= 0 {
+			continue
+		}
+		if n := after[col.ID]; n > col.WIPLimit && n > before[col.ID] {
+			vs = append(vs, Violation{BoardID: boardID, Col: col, Count: n})
+		}
+	}
+	return vs
+}
+
+// count returns the number of tasks in each column by the column's ID.
+func count(tasks []tasktbl.Task) map[string]int {
+	n := map[string]int{}
+	for _, t := range tasks {
+		n[t.ColID]++
+	}
+	return n
+}
+
+// apply returns tasks with each of the changed tasks in place of the task with
+// the same ID, and the changed tasks that aren't in tasks added to the end.
+func apply(tasks, changed []tasktbl.Task) []tasktbl.Task {
+	res := make([]tasktbl.Task, len(tasks))
+	copy(res, tasks)
+
+	for _, c := range changed {
+		found := false
+		for i, t := range res {
+			if c.ID != "" && t.ID == c.ID {
+				res[i], found = c, true
+				break
+			}
+		}
+		if !found {
+			res = append(res, c)
+		}
+	}
+	return res
+}
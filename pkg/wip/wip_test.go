@@ -0,0 +1,215 @@
+//go:build utest
+
+package wip
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+)
+
+func TestIsOverride(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{name: "NotSet", url: "/tasks", want: false},
+		{name: "False", url: "/tasks?overrideWIP=false", want: false},
+		{name: "True", url: "/tasks?overrideWIP=true", want: true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("PATCH", c.url, nil)
+			assert.Equal(t.Error, IsOverride(r), c.want)
+		})
+	}
+}
+
+func TestViolationMsg(t *testing.T) {
+	v := Violation{Col: teamtbl.NewColumn("go", "Go!", "", 3), Count: 4}
+	assert.Equal(t.Error,
+		v.Msg(),
+		"Column \"Go!\" cannot have more than 3 tasks without overriding "+
+			"its WIP limit.",
+	)
+}
+
+func TestLimited(t *testing.T) {
+	team := teamtbl.Team{Boards: []teamtbl.Board{
+		{ID: "board1", Columns: []teamtbl.Column{
+			teamtbl.NewColumn("todo", "To Do", "", 0),
+			teamtbl.NewColumn("doing", "Doing", "", 2),
+		}},
+		{ID: "board2"},
+	}}
+
+	for _, c := range []struct {
+		name    string
+		boardID string
+		changed []tasktbl.Task
+		want    bool
+	}{
+		{
+			name:    "NoLimit",
+			boardID: "board1",
+			changed: []tasktbl.Task{{BoardID: "board1", ColID: "todo"}},
+			want:    false,
+		},
+		{
+			name:    "DefaultColumns",
+			boardID: "board2",
+			changed: []tasktbl.Task{{BoardID: "board2", ColID: "go"}},
+			want:    false,
+		},
+		{
+			name:    "OtherBoard",
+			boardID: "board2",
+			changed: []tasktbl.Task{{BoardID: "board1", ColID: "doing"}},
+			want:    false,
+		},
+		{
+			name:    "Limited",
+			boardID: "board1",
+			changed: []tasktbl.Task{
+				{BoardID: "board1", ColID: "todo"},
+				{BoardID: "board1", ColID: "doing"},
+			},
+			want: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got := Limited(team, c.boardID, c.changed)
+			assert.Equal(t.Error, got, c.want)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	doing := teamtbl.NewColumn("doing", "Doing", "", 2)
+	team := teamtbl.Team{Boards: []teamtbl.Board{
+		{ID: "board1", Columns: []teamtbl.Column{
+			teamtbl.NewColumn("todo", "To Do", "", 0),
+			doing,
+			teamtbl.NewColumn("review", "Review", "", 1),
+		}},
+	}}
+	task := func(id, colID string) tasktbl.Task {
+		return tasktbl.Task{BoardID: "board1", ID: id, ColID: colID}
+	}
+	tasks := []tasktbl.Task{
+		task("1", "todo"), task("2", "todo"), task("3", "doing"),
+	}
+
+	for _, c := range []struct {
+		name    string
+		tasks   []tasktbl.Task
+		changed []tasktbl.Task
+		want    []Violation
+	}{
+		{
+			name:    "NoLimit",
+			tasks:   tasks,
+			changed: []tasktbl.Task{task("", "todo"), task("3", "todo")},
+			want:    nil,
+		},
+		{
+			name:    "UpToLimit",
+			tasks:   tasks,
+			changed: []tasktbl.Task{task("1", "doing")},
+			want:    nil,
+		},
+		{
+			name:    "NewTaskOverLimit",
+			tasks:   append(tasks, task("4", "doing")),
+			changed: []tasktbl.Task{task("", "doing")},
+			want: []Violation{
+				{BoardID: "board1", Col: doing, Count: 3},
+			},
+		},
+		{
+			name:    "MovedTasksOverLimit",
+			tasks:   tasks,
+			changed: []tasktbl.Task{task("1", "doing"), task("2", "doing")},
+			want: []Violation{
+				{BoardID: "board1", Col: doing, Count: 3},
+			},
+		},
+		{
+			name: "AlreadyOverLimit",
+			tasks: []tasktbl.Task{
+				task("1", "doing"), task("2", "doing"), task("3", "doing"),
+			},
+			changed: []tasktbl.Task{task("1", "doing"), task("3", "todo")},
+			want:    nil,
+		},
+		{
+			name:    "ReorderedInColumn",
+			tasks:   append(tasks, task("4", "doing"), task("5", "doing")),
+			changed: []tasktbl.Task{task("4", "doing"), task("3", "doing")},
+			want:    nil,
+		},
+		{
+			name:  "ManyColumnsOverLimit",
+			tasks: tasks,
+			changed: []tasktbl.Task{
+				task("1", "review"),
+				task("2", "review"),
+				task("", "doing"),
+				task("", "doing"),
+			},
+			want: []Violation{
+				{
+					BoardID: "board1",
+					Col:     teamtbl.NewColumn("review", "Review", "", 1),
+					Count:   2,
+				},
+				{BoardID: "board1", Col: doing, Count: 3},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got := Check(team, "board1", c.tasks, c.changed)
+			assert.AllEqual(t.Error, got, c.want)
+		})
+	}
+}
+
+// TestCheckPagedTasks checks that the tasks on every page of the results of
+// retrieving a board's tasks are counted towards the WIP limits.
+func TestCheckPagedTasks(t *testing.T) {
+	doing := teamtbl.NewColumn("doing", "Doing", "", 2)
+	team := teamtbl.Team{Boards: []teamtbl.Board{
+		{ID: "board1", Columns: []teamtbl.Column{doing}},
+	}}
+	page := func(id string) *dynamodb.QueryOutput {
+		item, err := attributevalue.MarshalMap(tasktbl.Task{
+			BoardID: "board1", ID: id, ColID: "doing",
+		})
+		assert.Nil(t.Fatal, err)
+		return &dynamodb.QueryOutput{
+			Items: []map[string]types.AttributeValue{item},
+		}
+	}
+	retriever := tasktbl.NewRetrieverByBoard(&db.FakeDynamoPagedQueryer{
+		Pages: []*dynamodb.QueryOutput{page("1"), page("2")},
+	})
+
+	tasks, err := retriever.Retrieve(context.Background(), "board1")
+	assert.Nil(t.Fatal, err)
+
+	got := Check(team, "board1", tasks, []tasktbl.Task{
+		{BoardID: "board1", ColID: "doing"},
+	})
+	assert.AllEqual(t.Error, got, []Violation{
+		{BoardID: "board1", Col: doing, Count: 3},
+	})
+}
@@ -0,0 +1,161 @@
+//go:build itest
+
+package test
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/oidc"
+)
+
+// IdP is a fake OpenID Connect identity provider used in integration tests. It
+// serves the discovery, JWKS, and token endpoints of a real provider, but users
+// log in by calling Login instead of going through a login page.
+type IdP struct {
+	*httptest.Server
+	clientID string
+	key      cookie.SigningKey
+	mu       sync.Mutex
+	logins   map[string]idpLogin
+}
+
+// idpLogin holds what the IdP needs to know about a login to issue an ID token
+// for it when its code is exchanged.
+type idpLogin struct {
+	redirectURI string
+	challenge   string
+	claims      jwt.MapClaims
+}
+
+// NewIdP creates, starts, and returns a new IdP that issues ID tokens to the
+// client with the given ID. It must be closed after use.
+func NewIdP(clientID string) *IdP {
+	key, _ := cookie.NewSigningKey("idp", ed25519.NewKeyFromSeed(
+		[]byte("itest-idp-signing-key-0123456789"),
+	))
+	idp := &IdP{
+		clientID: clientID, key: key, logins: map[string]idpLogin{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.serveDiscovery)
+	mux.HandleFunc("/jwks", idp.serveJWKS)
+	mux.HandleFunc("/token", idp.serveToken)
+	idp.Server = httptest.NewServer(mux)
+	return idp
+}
+
+// Login logs in the user with the given claims at the authorization URL that
+// the client redirected them to, and returns the URL that the IdP redirects
+// them back to.
+func (i *IdP) Login(
+	authURL, subject, email, username string,
+) (string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	if q.Get("client_id") != i.clientID ||
+		q.Get("response_type") != "code" ||
+		q.Get("code_challenge_method") != "S256" {
+		return "", errors.New("invalid authorization request")
+	}
+
+	code := uuid.NewString()
+	i.mu.Lock()
+	i.logins[code] = idpLogin{
+		redirectURI: q.Get("redirect_uri"),
+		challenge:   q.Get("code_challenge"),
+		claims: jwt.MapClaims{
+			"iss":                i.URL,
+			"aud":                i.clientID,
+			"sub":                subject,
+			"nonce":              q.Get("nonce"),
+			"email":              email,
+			"email_verified":     true,
+			"preferred_username": username,
+			"iat":                time.Now().Unix(),
+			"exp":                time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	i.mu.Unlock()
+
+	return q.Get("redirect_uri") + "?" + url.Values{
+		"code": {code}, "state": {q.Get("state")},
+	}.Encode(), nil
+}
+
+// serveDiscovery serves the IdP's discovery document.
+func (i *IdP) serveDiscovery(w http.ResponseWriter, _ *http.Request) {
+	i.encode(w, http.StatusOK, map[string]string{
+		"issuer":                 i.URL,
+		"authorization_endpoint": i.URL + "/authorize",
+		"token_endpoint":         i.URL + "/token",
+		"jwks_uri":               i.URL + "/jwks",
+	})
+}
+
+// serveJWKS serves the IdP's JWKS.
+func (i *IdP) serveJWKS(w http.ResponseWriter, _ *http.Request) {
+	i.encode(w, http.StatusOK, cookie.NewJWKS(i.key))
+}
+
+// serveToken exchanges a code for an ID token, checking that the request comes
+// from the client that started the login.
+func (i *IdP) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		i.encode(w, http.StatusBadRequest, map[string]string{
+			"error": "invalid_request",
+		})
+		return
+	}
+
+	// codes are single-use
+	i.mu.Lock()
+	login, ok := i.logins[r.PostForm.Get("code")]
+	delete(i.logins, r.PostForm.Get("code"))
+	i.mu.Unlock()
+
+	if !ok ||
+		r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("client_id") != i.clientID ||
+		r.PostForm.Get("redirect_uri") != login.redirectURI ||
+		oidc.Challenge(r.PostForm.Get("code_verifier")) != login.challenge {
+		i.encode(w, http.StatusBadRequest, map[string]string{
+			"error": "invalid_grant",
+		})
+		return
+	}
+
+	tk := jwt.NewWithClaims(i.key.Method, login.claims)
+	tk.Header["kid"] = i.key.ID
+	idToken, err := tk.SignedString(i.key.Key)
+	if err != nil {
+		i.encode(w, http.StatusInternalServerError, map[string]string{
+			"error": "server_error",
+		})
+		return
+	}
+	i.encode(w, http.StatusOK, map[string]string{
+		"id_token": idToken, "token_type": "Bearer",
+	})
+}
+
+// encode writes v as the JSON body of the response with the given status.
+func (i *IdP) encode(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -73,7 +74,10 @@ func SetUpTestTable(
 		return tearDown, err
 	}
 
-	// populate test table with given write requests
+	// populate test table with given write requests, if any
+	if len(writeReqs) == 0 {
+		return tearDown, nil
+	}
 	_, err = DB().BatchWriteItem(context.TODO(), &dynamodb.BatchWriteItemInput{
 		RequestItems: map[string][]types.WriteRequest{
 			tableName: writeReqs,
@@ -129,9 +133,13 @@ func createTable(
 		{AttributeName: &partKey, KeyType: types.KeyTypeHash},
 	}
 	if sortKey != "" {
-		attrDefs = append(attrDefs, types.AttributeDefinition{
-			AttributeName: &sortKey, AttributeType: types.ScalarAttributeTypeS,
-		})
+		// the sort key may already be defined as the key of an index
+		if !slices.Contains(secINames, sortKey) {
+			attrDefs = append(attrDefs, types.AttributeDefinition{
+				AttributeName: &sortKey,
+				AttributeType: types.ScalarAttributeTypeS,
+			})
+		}
 		keySchema = append(keySchema, types.KeySchemaElement{
 			AttributeName: &sortKey, KeyType: types.KeyTypeRange,
 		})
@@ -15,6 +15,13 @@ import (
 // tableName is the name of the task table used in the integration tests.
 var tableName = "goteam-test-task"
 
+// teamTableName is the name of the team table used in the integration tests.
+var teamTableName = "goteam-test-task-team"
+
+// overrideTableName is the name of the override table used in the
+// integration tests.
+var overrideTableName = "goteam-test-override"
+
 // TestMain sets up the test tables in DynamoDB and runs the tests.
 func TestMain(m *testing.M) {
 	fmt.Println("setting up task table")
@@ -27,11 +34,134 @@ func TestMain(m *testing.M) {
 		return
 	}
 
+	fmt.Println("setting up team table")
+	tearDownTeamTable, err := test.SetUpTestTable(
+		"TEAM_TABLE_NAME", teamTableName, teamWriteReqs, "ID", "",
+	)
+	defer tearDownTeamTable()
+	if err != nil {
+		log.Println("set up team table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up override table")
+	tearDownOverrideTable, err := test.SetUpTestTable(
+		"OVERRIDE_TABLE_NAME", overrideTableName, nil, "BoardID", "ID",
+	)
+	defer tearDownOverrideTable()
+	if err != nil {
+		log.Println("set up override table failed:", err)
+		return
+	}
+
 	m.Run()
 }
 
+// teamWriteReqs are the requests sent to the team test table to initialise it
+// for tests. They are used for checking whether team members belong to the
+// boards they create and move tasks on.
+var teamWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"Members": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "team1Admin"},
+				&types.AttributeValueMemberS{Value: "team1Member"},
+				&types.AttributeValueMemberS{Value: "team1Viewer"},
+			},
+		},
+		"Boards": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberS{
+							Value: "91536664-9749-4dbb-a470-6e52aa353ae4",
+						},
+						"Name": &types.AttributeValueMemberS{
+							Value: "Team 1 Board 1",
+						},
+						"Members": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{
+								&types.AttributeValueMemberS{
+									Value: "team1Member",
+								},
+							},
+						},
+					},
+				},
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberS{
+							Value: "fdb82637-f6a5-4d55-9dc3-9f60061e632f",
+						},
+						"Name": &types.AttributeValueMemberS{
+							Value: "Team 1 Board 2",
+						},
+						"Members": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{},
+						},
+					},
+				},
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberS{
+							Value: "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+						},
+						"Name": &types.AttributeValueMemberS{
+							Value: "Team 1 Board 3",
+						},
+						"Members": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{
+								&types.AttributeValueMemberS{
+									Value: "team1Member",
+								},
+							},
+						},
+					},
+				},
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberS{
+							Value: "e0f4c6d2-3b7a-4c51-9f0e-8a2d6b1c7e93",
+						},
+						"Name": &types.AttributeValueMemberS{
+							Value: "Team 1 Board 4",
+						},
+						"Members": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{
+								&types.AttributeValueMemberS{
+									Value: "team1Member",
+								},
+							},
+						},
+						"Archived": &types.AttributeValueMemberBOOL{
+							Value: true,
+						},
+					},
+				},
+			},
+		},
+	}}},
+}
+
 // writeReqs are the requests sent to the test table to initialise it for tests.
 var writeReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"ID": &types.AttributeValueMemberS{
+			Value: "0a7c2e51-94b3-4d6f-8e21-c5f9b3d07a46",
+		},
+		"Title": &types.AttributeValueMemberS{Value: "archived task"},
+		"Order": &types.AttributeValueMemberN{Value: "1"},
+		"BoardID": &types.AttributeValueMemberS{
+			Value: "e0f4c6d2-3b7a-4c51-9f0e-8a2d6b1c7e93",
+		},
+		"ColID": &types.AttributeValueMemberS{Value: "inbox"},
+	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
 			Value: "74c80ae5-64f3-4298-a8ff-48f8f920c7d4",
@@ -56,7 +186,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "f0c5d521-ccb5-47cc-ba40-313ddb901165",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "0"},
+		"ColID": &types.AttributeValueMemberS{Value: "inbox"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -82,7 +212,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "f0c5d521-ccb5-47cc-ba40-313ddb901165",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "1"},
+		"ColID": &types.AttributeValueMemberS{Value: "ready"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -108,7 +238,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "f0c5d521-ccb5-47cc-ba40-313ddb901165",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "2"},
+		"ColID": &types.AttributeValueMemberS{Value: "go"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -134,7 +264,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "f0c5d521-ccb5-47cc-ba40-313ddb901165",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "3"},
+		"ColID": &types.AttributeValueMemberS{Value: "done"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -148,7 +278,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "91536664-9749-4dbb-a470-6e52aa353ae4",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "0"},
+		"ColID": &types.AttributeValueMemberS{Value: "inbox"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -162,7 +292,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "2"},
+		"ColID": &types.AttributeValueMemberS{Value: "go"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -176,7 +306,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "2"},
+		"ColID": &types.AttributeValueMemberS{Value: "go"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -202,7 +332,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "2"},
+		"ColID": &types.AttributeValueMemberS{Value: "go"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -236,7 +366,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "2"},
+		"ColID": &types.AttributeValueMemberS{Value: "go"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -271,7 +401,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "fdb82637-f6a5-4d55-9dc3-9f60061e632f",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "0"},
+		"ColID": &types.AttributeValueMemberS{Value: "inbox"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -285,7 +415,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "fdb82637-f6a5-4d55-9dc3-9f60061e632f",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "2"},
+		"ColID": &types.AttributeValueMemberS{Value: "go"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -314,7 +444,7 @@ var writeReqs = []types.WriteRequest{
 		"BoardID": &types.AttributeValueMemberS{
 			Value: "ca47fbec-269e-4ef4-a74a-bcfbcd599fd5",
 		},
-		"ColNo": &types.AttributeValueMemberN{Value: "0"},
+		"ColID": &types.AttributeValueMemberS{Value: "inbox"},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"TeamID": &types.AttributeValueMemberS{
@@ -20,19 +20,29 @@ import (
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestTaskAPI(t *testing.T) {
-	authDecoder := cookie.NewAuthDecoder(test.JWTKey)
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
 	titleValidator := taskapi.NewTitleValidator()
 	log := log.New()
 	sut := api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPost: taskapi.NewPostHandler(
 			authDecoder,
 			taskapi.ValidatePostReq,
+			teamtbl.NewRetriever(test.DB()),
+			tasktbl.NewRetrieverByBoard(test.DB()),
+			quota.DefaultLimits,
+			overridetbl.NewInserter(test.DB()),
 			tasktbl.NewInserter(test.DB()),
 			log,
 		),
@@ -40,11 +50,18 @@ func TestTaskAPI(t *testing.T) {
 			authDecoder,
 			titleValidator,
 			titleValidator,
+			tasktbl.NewRetriever(test.DB()),
+			teamtbl.NewRetriever(test.DB()),
+			tasktbl.NewRetrieverByBoard(test.DB()),
+			quota.DefaultLimits,
+			overridetbl.NewInserter(test.DB()),
 			tasktbl.NewUpdater(test.DB()),
 			log,
 		),
 		http.MethodDelete: taskapi.NewDeleteHandler(
 			authDecoder,
+			tasktbl.NewRetriever(test.DB()),
+			teamtbl.NewRetriever(test.DB()),
 			tasktbl.NewDeleter(test.DB()),
 			log,
 		),
@@ -73,14 +90,38 @@ func TestTaskAPI(t *testing.T) {
 				assertFunc:     assert.OnRespErr("Invalid auth token."),
 			},
 			{
-				name:           "NotAdmin",
+				name:           "Viewer",
 				reqBody:        `{}`,
+				authFunc:       test.AddAuthCookie(test.T1ViewerToken),
+				wantStatusCode: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"You do not have permission to create tasks.",
+				),
+			},
+			{
+				name: "NotBoardMember",
+				reqBody: `{
+                    "boardID": "fdb82637-f6a5-4d55-9dc3-9f60061e632f",
+                    "colID":   "inbox",
+                    "title":   "Some Task"
+				}`,
 				authFunc:       test.AddAuthCookie(test.T1MemberToken),
 				wantStatusCode: http.StatusForbidden,
 				assertFunc: assert.OnRespErr(
-					"Only team admins can create tasks.",
+					"You can only create tasks on boards you are a member of.",
 				),
 			},
+			{
+				name: "OKMember",
+				reqBody: `{
+                    "boardID": "91536664-9749-4dbb-a470-6e52aa353ae4",
+                    "colID":   "inbox",
+                    "title":   "Some Member Task"
+				}`,
+				authFunc:       test.AddAuthCookie(test.T1MemberToken),
+				wantStatusCode: http.StatusOK,
+				assertFunc:     func(*testing.T, *http.Response, []any) {},
+			},
 			{
 				name:           "EmptyBoardID",
 				reqBody:        `{"boardID": ""}`,
@@ -98,34 +139,20 @@ func TestTaskAPI(t *testing.T) {
 				),
 			},
 			{
-				name: "ColNoTooLarge",
-				reqBody: `{
-                    "boardID": "91536664-9749-4dbb-a470-6e52aa353ae4",
-                    "colNo":   4
-                }`,
-				authFunc:       test.AddAuthCookie(test.T1AdminToken),
-				wantStatusCode: http.StatusBadRequest,
-				assertFunc: assert.OnRespErr(
-					"Column number must be between 0 and 3.",
-				),
-			},
-			{
-				name: "ColNoTooSmall",
+				name: "ColIDEmpty",
 				reqBody: `{
                     "boardID": "91536664-9749-4dbb-a470-6e52aa353ae4",
-                    "colNo":   -1
+                    "colID":   ""
                 }`,
 				authFunc:       test.AddAuthCookie(test.T1AdminToken),
 				wantStatusCode: http.StatusBadRequest,
-				assertFunc: assert.OnRespErr(
-					"Column number must be between 0 and 3.",
-				),
+				assertFunc:     assert.OnRespErr("Column ID cannot be empty."),
 			},
 			{
 				name: "TitleEmpty",
 				reqBody: `{
                     "boardID":  "91536664-9749-4dbb-a470-6e52aa353ae4",
-                    "colNo": 1,
+                    "colID": "ready",
 					"title":  ""
 				}`,
 				authFunc:       test.AddAuthCookie(test.T1AdminToken),
@@ -136,7 +163,7 @@ func TestTaskAPI(t *testing.T) {
 				name: "TitleTooLong",
 				reqBody: `{
                     "boardID":  "91536664-9749-4dbb-a470-6e52aa353ae4",
-                    "colNo": 1,
+                    "colID": "ready",
 					"title":  "asdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasd"
 				}`,
 				authFunc:       test.AddAuthCookie(test.T1AdminToken),
@@ -149,7 +176,7 @@ func TestTaskAPI(t *testing.T) {
 				name: "DescTooLong",
 				reqBody: `{
                     "boardID":       "91536664-9749-4dbb-a470-6e52aa353ae4",
-                    "colNo":      1,
+                    "colID":      "ready",
 					"title":       "Some Task",
                     "description": "asdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasdasdqweasdqweasdqweasdqweasdqweasdqweasdqwe"
 				}`,
@@ -163,7 +190,7 @@ func TestTaskAPI(t *testing.T) {
 				name: "SubtaskTitleEmpty",
 				reqBody: `{
                     "boardID":    "91536664-9749-4dbb-a470-6e52aa353ae4",
-                    "colNo":   1,
+                    "colID":   "ready",
 					"title":    "Some Task",
                     "subtasks": [{"title": ""}]
 				}`,
@@ -177,7 +204,7 @@ func TestTaskAPI(t *testing.T) {
 				name: "SubtaskTitleTooLong",
 				reqBody: `{
                     "boardID":  "91536664-9749-4dbb-a470-6e52aa353ae4",
-                    "colNo":    1,
+                    "colID":    "ready",
 					"title":    "Some Task",
                     "subtasks": [{
                         "title": "asdqweasdqweasdqweasdqweasdqweasdqweasdqweasdqweasd"
@@ -194,7 +221,7 @@ func TestTaskAPI(t *testing.T) {
 				reqBody: `{
                     "boardID":     "91536664-9749-4dbb-a470-6e52aa353ae4",
 					"description": "Do something. Then, do something else.",
-                    "colNo":       1,
+                    "colID":       "ready",
 					"title":       "Some Task",
 					"subtasks":    [
                         {"title": "Some Subtask"}, 
@@ -206,12 +233,47 @@ func TestTaskAPI(t *testing.T) {
 				wantStatusCode: http.StatusBadRequest,
 				assertFunc:     assert.OnRespErr("Order cannot be negative."),
 			},
+			{
+				name: "BoardNotInTeam",
+				reqBody: `{
+                    "boardID": "f0c5d521-ccb5-47cc-ba40-313ddb901165",
+                    "colID":   "inbox",
+                    "title":   "Some Task"
+				}`,
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusNotFound,
+				assertFunc:     assert.OnRespErr("Board not found."),
+			},
+			{
+				name: "ArchivedBoard",
+				reqBody: `{
+                    "boardID": "e0f4c6d2-3b7a-4c51-9f0e-8a2d6b1c7e93",
+                    "colID":   "inbox",
+                    "title":   "Some Task"
+				}`,
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Tasks of archived boards cannot be changed.",
+				),
+			},
+			{
+				name: "ColumnNotFound",
+				reqBody: `{
+                    "boardID": "91536664-9749-4dbb-a470-6e52aa353ae4",
+                    "colID":   "review",
+                    "title":   "Some Task"
+				}`,
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusNotFound,
+				assertFunc:     assert.OnRespErr("Column not found."),
+			},
 			{
 				name: "OK",
 				reqBody: `{
                     "boardID":     "91536664-9749-4dbb-a470-6e52aa353ae4",
 					"description": "Do something. Then, do something else.",
-                    "colNo":       1,
+                    "colID":       "ready",
 					"title":       "Some Task",
 					"subtasks":    [
                         {"title": "Some Subtask"}, 
@@ -258,7 +320,7 @@ func TestTaskAPI(t *testing.T) {
 							wantDescr := "Do something. Then, do something " +
 								"else."
 							if task.Description == wantDescr &&
-								task.ColNo == 1 &&
+								task.ColID == "ready" &&
 								task.Title == "Some Task" &&
 								task.Subtasks[0].Title == "Some Subtask" &&
 								task.Subtasks[0].IsDone == false &&
@@ -373,12 +435,65 @@ func TestTaskAPI(t *testing.T) {
 					"Subtask title cannot be longer than 50 characters.",
 				),
 			},
+			{
+				name: "TaskNotFound",
+				reqBody: `{
+                    "id": "55e275e4-de80-4241-b73b-88e784d5522b",
+                    "title": "Some Task",
+                    "boardID": "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+                    "colID": "go"
+                }`,
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusNotFound,
+				assertFunc:     assert.OnRespErr("Task not found."),
+			},
+			{
+				name: "BoardChanged",
+				reqBody: `{
+                    "id": "0a7c2e51-94b3-4d6f-8e21-c5f9b3d07a46",
+                    "title": "Some Task",
+                    "boardID": "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+                    "colID": "go"
+                }`,
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Tasks cannot be moved between boards.",
+				),
+			},
+			{
+				name: "ArchivedBoard",
+				reqBody: `{
+                    "id": "0a7c2e51-94b3-4d6f-8e21-c5f9b3d07a46",
+                    "title": "Some Task",
+                    "boardID": "e0f4c6d2-3b7a-4c51-9f0e-8a2d6b1c7e93",
+                    "colID": "inbox"
+                }`,
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Tasks of archived boards cannot be changed.",
+				),
+			},
+			{
+				name: "ColumnNotFound",
+				reqBody: `{
+                    "id": "e0021a56-6a1e-4007-b773-395d3991fb7e",
+					"title": "Some Task",
+                    "boardID": "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+                    "colID": "review"
+				}`,
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusNotFound,
+				assertFunc:     assert.OnRespErr("Column not found."),
+			},
 			{
 				name: "OK",
 				reqBody: `{
                     "id": "e0021a56-6a1e-4007-b773-395d3991fb7e",
 					"title": "Some Task",
                     "boardID": "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+                    "colID": "done",
 					"description": "Some Description",
 					"subtasks": [
 						{
@@ -417,6 +532,7 @@ func TestTaskAPI(t *testing.T) {
 					assert.Nil(t.Fatal, err)
 
 					assert.Equal(t.Error, task.Title, "Some Task")
+					assert.Equal(t.Error, task.ColID, "done")
 					assert.Equal(t.Error, task.Description, "Some Description")
 					assert.Equal(t.Error,
 						task.Subtasks[0].Title, "Some Subtask",
@@ -478,6 +594,22 @@ func TestTaskAPI(t *testing.T) {
 					"Only team admins can delete tasks.",
 				),
 			},
+			{
+				name:           "NotFound",
+				id:             "5d7e6b1a-2c84-4f39-a0e5-71b9c3d48f62",
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusNotFound,
+				assertFunc:     assert.OnRespErr("Task not found."),
+			},
+			{
+				name:           "ArchivedBoard",
+				id:             "0a7c2e51-94b3-4d6f-8e21-c5f9b3d07a46",
+				authFunc:       test.AddAuthCookie(test.T1AdminToken),
+				wantStatusCode: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Tasks of archived boards cannot be changed.",
+				),
+			},
 			{
 				name:           "OK",
 				id:             "9dd9c982-8d1c-49ac-a412-3b01ba74b634",
@@ -490,8 +622,8 @@ func TestTaskAPI(t *testing.T) {
 							TableName: &tableName,
 							Key: map[string]types.AttributeValue{
 								"TeamID": &types.AttributeValueMemberS{
-									Value: "91536664-9749-4dbb-a470-6e52aa353" +
-										"ae4",
+									Value: "afeadc4a-68b0-4c33-9e83-4648d20ff" +
+										"26a",
 								},
 								"ID": &types.AttributeValueMemberS{
 									Value: "9dd9c982-8d1c-49ac-a412-3b01ba74b" +
@@ -18,13 +18,18 @@ import (
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/overridetbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
 	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestTasksAPI(t *testing.T) {
-	authDecoder := cookie.NewAuthDecoder(test.JWTKey)
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
 	log := log.New()
 	sut := api.NewHandler(map[string]api.MethodHandler{
 		http.MethodGet: tasksapi.NewGetHandler(
@@ -32,11 +37,15 @@ func TestTasksAPI(t *testing.T) {
 			tasktbl.NewRetrieverByBoard(test.DB()),
 			authDecoder,
 			tasktbl.NewRetrieverByTeam(test.DB()),
+			teamtbl.NewRetriever(test.DB()),
 			log,
 		),
 		http.MethodPatch: tasksapi.NewPatchHandler(
 			authDecoder,
-			tasksapi.NewColNoValidator(),
+			tasktbl.NewRetriever(test.DB()),
+			teamtbl.NewRetriever(test.DB()),
+			tasktbl.NewRetrieverByBoard(test.DB()),
+			overridetbl.NewInserter(test.DB()),
 			tasktbl.NewMultiUpdater(test.DB()),
 			log,
 		),
@@ -89,7 +98,7 @@ func TestTasksAPI(t *testing.T) {
 									"c",
 								BoardID: "ca47fbec-269e-4ef4-a74a-bcfbcd599fd" +
 									"5",
-								ColNo: 0,
+								ColID: "inbox",
 								ID: "55e275e4-de80-4241-b73b-88e784d5522" +
 									"b",
 								Title:       "team 4 task 1",
@@ -104,7 +113,7 @@ func TestTasksAPI(t *testing.T) {
 									"c",
 								BoardID: "ca47fbec-269e-4ef4-a74a-bcfbcd599fd" +
 									"5",
-								ColNo: 0,
+								ColID: "inbox",
 								ID: "5ccd750d-3783-4832-891d-025f24a4944" +
 									"f",
 								Title:       "team 4 task 2",
@@ -128,7 +137,7 @@ func TestTasksAPI(t *testing.T) {
 							assert.Equal(t.Error, task.TeamID, wt.TeamID)
 							assert.Equal(t.Error, task.BoardID, wt.BoardID)
 							assert.Equal(t.Error,
-								task.ColNo, wt.ColNo,
+								task.ColID, wt.ColID,
 							)
 							assert.Equal(t.Error, task.ID, wt.ID)
 							assert.Equal(t.Error, task.Title, wt.Title)
@@ -194,7 +203,7 @@ func TestTasksAPI(t *testing.T) {
 							{
 								TeamID:  "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
 								BoardID: "91536664-9749-4dbb-a470-6e52aa353ae4",
-								ColNo:   0,
+								ColID:   "inbox",
 								ID: "55e275e4-de80-4241-b73b-88e784d5522" +
 									"b",
 								Title:       "task 5",
@@ -216,7 +225,7 @@ func TestTasksAPI(t *testing.T) {
 							assert.Equal(t.Error, task.TeamID, wt.TeamID)
 							assert.Equal(t.Error, task.BoardID, wt.BoardID)
 							assert.Equal(t.Error,
-								task.ColNo, wt.ColNo,
+								task.ColID, wt.ColID,
 							)
 							assert.Equal(t.Error, task.ID, wt.ID)
 							assert.Equal(t.Error, task.Title, wt.Title)
@@ -271,14 +280,79 @@ func TestTasksAPI(t *testing.T) {
 				assertFunc: assert.OnRespErr("Invalid auth token."),
 			},
 			{
-				name:       "NotAdmin",
+				name:       "Viewer",
 				reqBody:    `[]`,
+				authFunc:   test.AddAuthCookie(test.T1ViewerToken),
+				statusCode: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"You do not have permission to move tasks.",
+				),
+			},
+			{
+				name: "NotBoardMember",
+				reqBody: `[{
+                    "id": "01a3168d-6d2a-46fb-aed9-70c26a4d71e9",
+                    "order": 1,
+                    "boardID": "fdb82637-f6a5-4d55-9dc3-9f60061e632f",
+                    "colID": "go"
+                }]`,
 				authFunc:   test.AddAuthCookie(test.T1MemberToken),
 				statusCode: http.StatusForbidden,
 				assertFunc: assert.OnRespErr(
-					"Only team admins can edit tasks.",
+					"You can only move tasks on boards you are a member of.",
+				),
+			},
+			{
+				name: "BoardChanged",
+				reqBody: `[{
+                    "id": "8fb040a2-910c-47af-a4ab-9dee49f16d1d",
+                    "order": 1,
+                    "boardID": "91536664-9749-4dbb-a470-6e52aa353ae4",
+                    "colID": "go"
+                }]`,
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				statusCode: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Tasks cannot be moved between boards.",
 				),
 			},
+			{
+				name: "OKMember",
+				reqBody: `[{
+                    "id": "8fb040a2-910c-47af-a4ab-9dee49f16d1d",
+                    "title": "task 6",
+                    "order": 1,
+                    "boardID": "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+                    "colID": "done"
+                }]`,
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				statusCode: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					out, err := test.DB().GetItem(
+						context.Background(),
+						&dynamodb.GetItemInput{
+							TableName: &tableName,
+							Key: map[string]types.AttributeValue{
+								"TeamID": &types.AttributeValueMemberS{
+									Value: "afeadc4a-68b0-4c33-9e83-4648d20ff" +
+										"26a",
+								},
+								"ID": &types.AttributeValueMemberS{
+									Value: "8fb040a2-910c-47af-a4ab-9dee49f16" +
+										"d1d",
+								},
+							},
+						},
+					)
+					assert.Nil(t.Fatal, err)
+
+					var task tasktbl.Task
+					assert.Nil(t.Fatal, attributevalue.UnmarshalMap(
+						out.Item, &task,
+					))
+					assert.Equal(t.Error, task.ColID, "done")
+				},
+			},
 			{
 				name:       "NoTasks",
 				reqBody:    `[]`,
@@ -287,14 +361,67 @@ func TestTasksAPI(t *testing.T) {
 				assertFunc: assert.OnRespErr("No tasks provided."),
 			},
 			{
-				name: "OK",
+				name: "TaskNotFound",
 				reqBody: `[{
-                    "id": "c684a6a0-404d-46fa-9fa5-1497f9874567", 
+                    "id": "55e275e4-de80-4241-b73b-88e784d5522b",
+                    "order": 2,
+                    "boardID": "ca47fbec-269e-4ef4-a74a-bcfbcd599fd5",
+                    "colID": "go"
+                }]`,
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				statusCode: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Task not found."),
+			},
+			{
+				name: "ColumnNotFound",
+				reqBody: `[{
+                    "id": "c684a6a0-404d-46fa-9fa5-1497f9874567",
                     "title": "task 5",
                     "order": 2,
                     "subtasks": [],
-                    "boardID": "f0c5d521-ccb5-47cc-ba40-313ddb901165",
-                    "colNo": 2
+                    "boardID": "91536664-9749-4dbb-a470-6e52aa353ae4",
+                    "colID": "review"
+                }]`,
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				statusCode: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Column not found."),
+			},
+			{
+				name: "ArchivedBoard",
+				reqBody: `[{
+                    "id": "0a7c2e51-94b3-4d6f-8e21-c5f9b3d07a46",
+                    "order": 2,
+                    "colID": "go"
+                }]`,
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				statusCode: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Tasks of archived boards cannot be changed.",
+				),
+			},
+			{
+				name: "MovedOutOfArchivedBoard",
+				reqBody: `[{
+                    "id": "0a7c2e51-94b3-4d6f-8e21-c5f9b3d07a46",
+                    "order": 2,
+                    "boardID": "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+                    "colID": "go"
+                }]`,
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				statusCode: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Tasks cannot be moved between boards.",
+				),
+			},
+			{
+				name: "OK",
+				reqBody: `[{
+                    "id": "c684a6a0-404d-46fa-9fa5-1497f9874567",
+                    "title": "task 5 renamed",
+                    "order": 2,
+                    "subtasks": [],
+                    "boardID": "91536664-9749-4dbb-a470-6e52aa353ae4",
+                    "colID": "go"
                 }]`,
 				authFunc:   test.AddAuthCookie(test.T1AdminToken),
 				statusCode: http.StatusOK,
@@ -325,13 +452,14 @@ func TestTasksAPI(t *testing.T) {
 					assert.Equal(t.Error,
 						task.ID, "c684a6a0-404d-46fa-9fa5-1497f9874567",
 					)
+					// only the column and the order of a task can be changed
 					assert.Equal(t.Error, task.Title, "task 5")
 					assert.Equal(t.Error, task.Order, 2)
 					assert.Equal(t.Error, len(task.Subtasks), 0)
 					assert.Equal(t.Error,
-						task.BoardID, "f0c5d521-ccb5-47cc-ba40-313ddb901165",
+						task.BoardID, "91536664-9749-4dbb-a470-6e52aa353ae4",
 					)
-					assert.Equal(t.Error, task.ColNo, 2)
+					assert.Equal(t.Error, task.ColID, "go")
 				},
 			},
 		} {
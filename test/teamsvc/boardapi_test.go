@@ -4,6 +4,7 @@ package test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -17,24 +18,30 @@ import (
 	"github.com/kxplxn/goteam/pkg/api"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestBoardAPI(t *testing.T) {
-	authDecoder := cookie.NewAuthDecoder(test.JWTKey)
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
 	nameValidator := boardapi.NewNameValidator()
 	log := log.New()
 	sut := api.NewHandler(map[string]api.MethodHandler{
 		http.MethodPost: boardapi.NewPostHandler(
 			authDecoder,
 			nameValidator,
-			teamtbl.NewBoardInserter(test.DB()),
+			teamtbl.NewBoardInserter(test.DB(), quota.DefaultLimits),
 			log,
 		),
 		http.MethodDelete: boardapi.NewDeleteHandler(
 			authDecoder,
+			tasktbl.NewDeleterByBoard(test.DB()),
 			teamtbl.NewBoardDeleter(test.DB()),
 			log,
 		),
@@ -42,6 +49,7 @@ func TestBoardAPI(t *testing.T) {
 			authDecoder,
 			boardapi.NewIDValidator(),
 			nameValidator,
+			teamtbl.NewRetriever(test.DB()),
 			teamtbl.NewBoardUpdater(test.DB()),
 			log,
 		),
@@ -110,8 +118,8 @@ func TestBoardAPI(t *testing.T) {
 				wantStatus: http.StatusBadRequest,
 				assertFunc: assert.OnRespErr(
 					"You have already created the maximum amount of boards " +
-						"allowed per team. Please delete one of your boards " +
-						"to create a new one.",
+						"allowed per team. Please delete or archive one of " +
+						"your boards to create a new one.",
 				),
 			},
 			{
@@ -266,6 +274,12 @@ func TestBoardAPI(t *testing.T) {
 					for _, b := range team.Boards {
 						if b.ID == "fdb82637-f6a5-4d55-9dc3-9f60061e632f" {
 							assert.Equal(t.Error, b.Name, "New Board Name")
+							// columns are edited through the columns route
+							// and members through the user route so the
+							// board's own must be kept
+							assert.Equal(t.Error, len(b.Columns), 3)
+							assert.Equal(t.Error, b.Columns[0].ID, "todo")
+							assert.Equal(t.Error, len(b.Members), 0)
 							found = true
 							break
 						}
@@ -275,13 +289,38 @@ func TestBoardAPI(t *testing.T) {
 					}
 				},
 			},
+			{
+				name:       "OKMembersKept",
+				boardID:    "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+				boardName:  "Team 1 Board 3",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					team, err := teamtbl.NewRetriever(test.DB()).Retrieve(
+						context.Background(),
+						"afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+					)
+					assert.Nil(t.Fatal, err)
+
+					for _, b := range team.Boards {
+						if b.ID == "1559a33c-54c5-42c8-8e5f-fe096f7760fa" {
+							assert.AllEqual(t.Error,
+								b.Members, []string{"team1Member"},
+							)
+							return
+						}
+					}
+					t.Error("board not found for team")
+				},
+			},
 		} {
 			t.Run(c.name, func(t *testing.T) {
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(
 					http.MethodPatch, "/team/board", strings.NewReader(`{
                         "id": "`+c.boardID+`",
-                        "name": "`+c.boardName+`"
+                        "name": "`+c.boardName+`",
+                        "members": ["team1Admin"]
                     }`),
 				)
 				c.authFunc(r)
@@ -357,7 +396,25 @@ func TestBoardAPI(t *testing.T) {
 					assert.Nil(t.Fatal, err)
 
 					assert.Equal(t.Error, len(team.Boards), 0)
+
+					tasks, err := tasktbl.NewRetrieverByBoard(test.DB()).
+						Retrieve(
+							context.Background(),
+							"f0c5d521-ccb5-47cc-ba40-313ddb901165",
+						)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, len(tasks), 0)
+				},
+			},
+			{
+				name: "AlreadyDeleted",
+				id:   "f0c5d521-ccb5-47cc-ba40-313ddb901165",
+				authFunc: func(r *http.Request) {
+					test.AddAuthCookie(test.T3AdminToken)(r)
+					test.AddStateCookie(test.T3StateToken)(r)
 				},
+				wantStatusCode: http.StatusNotFound,
+				assertFunc:     func(*testing.T) {},
 			},
 		} {
 			t.Run(c.name, func(t *testing.T) {
@@ -376,3 +433,113 @@ func TestBoardAPI(t *testing.T) {
 		}
 	})
 }
+
+func TestBoardArchiveAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
+	idValidator := boardapi.NewIDValidator()
+	boardArchiver := teamtbl.NewBoardArchiver(test.DB(), quota.DefaultLimits)
+	log := log.New()
+	sut := api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: boardapi.NewGetArchivedHandler(
+			authDecoder, teamtbl.NewRetriever(test.DB()), log,
+		),
+		http.MethodPost: boardapi.NewArchiveHandler(
+			authDecoder, idValidator, boardArchiver, log,
+		),
+		http.MethodDelete: boardapi.NewRestoreHandler(
+			authDecoder, idValidator, boardArchiver, log,
+		),
+	})
+
+	board2 := "fdb82637-f6a5-4d55-9dc3-9f60061e632f"
+	assertArchived := func(want bool) func(*testing.T, *http.Response) {
+		return func(t *testing.T, _ *http.Response) {
+			team, err := teamtbl.NewRetriever(test.DB()).Retrieve(
+				context.Background(), "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+			)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, team.IsBoardArchived(board2), want)
+		}
+	}
+
+	for _, c := range []struct {
+		name       string
+		method     string
+		id         string
+		authFunc   func(*http.Request)
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response)
+	}{
+		{
+			name:       "NotAdmin",
+			method:     http.MethodPost,
+			id:         board2,
+			authFunc:   test.AddAuthCookie(test.T1MemberToken),
+			wantStatus: http.StatusForbidden,
+			assertFunc: assertArchived(false),
+		},
+		{
+			name:       "IDNotUUID",
+			method:     http.MethodPost,
+			id:         "qwerty",
+			authFunc:   test.AddAuthCookie(test.T1AdminToken),
+			wantStatus: http.StatusBadRequest,
+			assertFunc: func(*testing.T, *http.Response) {},
+		},
+		{
+			name:       "BoardNotFound",
+			method:     http.MethodPost,
+			id:         "f0c5d521-ccb5-47cc-ba40-313ddb901165",
+			authFunc:   test.AddAuthCookie(test.T1AdminToken),
+			wantStatus: http.StatusNotFound,
+			assertFunc: func(*testing.T, *http.Response) {},
+		},
+		{
+			name:       "Archive",
+			method:     http.MethodPost,
+			id:         board2,
+			authFunc:   test.AddAuthCookie(test.T1AdminToken),
+			wantStatus: http.StatusOK,
+			assertFunc: assertArchived(true),
+		},
+		{
+			name:       "GetArchived",
+			method:     http.MethodGet,
+			id:         "",
+			authFunc:   test.AddAuthCookie(test.T1AdminToken),
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				var respBody boardapi.GetArchivedResp
+				err := json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.Nil(t.Fatal, err)
+
+				assert.Equal(t.Fatal, len(respBody.Boards), 1)
+				assert.Equal(t.Error, respBody.Boards[0].ID, board2)
+			},
+		},
+		{
+			name:       "Restore",
+			method:     http.MethodDelete,
+			id:         board2,
+			authFunc:   test.AddAuthCookie(test.T1AdminToken),
+			wantStatus: http.StatusOK,
+			assertFunc: assertArchived(false),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				c.method, "/team/board/archive?id="+c.id, nil,
+			)
+			c.authFunc(r)
+
+			sut.ServeHTTP(w, r)
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp)
+		})
+	}
+}
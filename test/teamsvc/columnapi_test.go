@@ -0,0 +1,412 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/boardapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/columnapi"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestColumnAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
+	log := log.New()
+	sut := api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPost: columnapi.NewPostHandler(
+			authDecoder,
+			boardapi.NewIDValidator(),
+			columnapi.NewNameValidator(),
+			columnapi.NewColorValidator(),
+			teamtbl.NewRetriever(test.DB()),
+			teamtbl.NewBoardUpdater(test.DB()),
+			log,
+		),
+		http.MethodPatch: columnapi.NewPatchHandler(
+			authDecoder,
+			boardapi.NewIDValidator(),
+			columnapi.NewNameValidator(),
+			columnapi.NewColorValidator(),
+			teamtbl.NewRetriever(test.DB()),
+			teamtbl.NewBoardUpdater(test.DB()),
+			log,
+		),
+		http.MethodPut: columnapi.NewPutHandler(
+			authDecoder,
+			boardapi.NewIDValidator(),
+			teamtbl.NewRetriever(test.DB()),
+			teamtbl.NewBoardUpdater(test.DB()),
+			log,
+		),
+		http.MethodDelete: columnapi.NewDeleteHandler(
+			authDecoder,
+			boardapi.NewIDValidator(),
+			teamtbl.NewRetriever(test.DB()),
+			tasktbl.NewRetrieverByBoard(test.DB()),
+			tasktbl.NewMultiUpdater(test.DB()),
+			teamtbl.NewBoardUpdater(test.DB()),
+			log,
+		),
+	})
+
+	board1 := "91536664-9749-4dbb-a470-6e52aa353ae4"
+	board2 := "fdb82637-f6a5-4d55-9dc3-9f60061e632f"
+
+	// columns returns the columns of the team 1 board with the given ID as
+	// saved in the team table.
+	columns := func(t *testing.T, boardID string) []teamtbl.Column {
+		out, err := test.DB().GetItem(
+			context.Background(), &dynamodb.GetItemInput{
+				TableName: &tableName,
+				Key: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{
+						Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+					},
+				},
+			},
+		)
+		assert.Nil(t.Fatal, err)
+
+		var team teamtbl.Team
+		assert.Nil(t.Fatal, attributevalue.UnmarshalMap(out.Item, &team))
+		for _, b := range team.Boards {
+			if b.ID == boardID {
+				return b.Columns
+			}
+		}
+		t.Fatal("board not found for team")
+		return nil
+	}
+
+	t.Run("POST", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				reqBody:    `{}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "InvalidAuth",
+				authFunc:   test.AddAuthCookie("asdfasdf"),
+				reqBody:    `{}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				reqBody:    `{}`,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can edit boards.",
+				),
+			},
+			{
+				name:       "BoardIDNotUUID",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				reqBody:    `{"boardID": "asdkjf", "name": "In Review"}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Board ID must be a UUID."),
+			},
+			{
+				name:       "NameEmpty",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				reqBody:    `{"boardID": "` + board2 + `", "name": ""}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Column name cannot be empty."),
+			},
+			{
+				name:     "ColorInvalid",
+				authFunc: test.AddAuthCookie(test.T1AdminToken),
+				reqBody: `{"boardID": "` + board2 + `", "name": "In Review", ` +
+					`"color": "red"}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Column color must be a hex color code such as #1a2b3c.",
+				),
+			},
+			{
+				name:     "BoardNotFound",
+				authFunc: test.AddAuthCookie(test.T1AdminToken),
+				reqBody: `{` +
+					`"boardID": "f0c5d521-ccb5-47cc-ba40-313ddb901165", ` +
+					`"name": "In Review"}`,
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Board not found."),
+			},
+			{
+				name:     "OK",
+				authFunc: test.AddAuthCookie(test.T1AdminToken),
+				reqBody: `{"boardID": "` + board2 + `", "name": "In Review", ` +
+					`"color": "#abcdef", "wipLimit": 2}`,
+				wantStatus: http.StatusCreated,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody columnapi.PostResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+
+					cols := columns(t, board2)
+					assert.Equal(t.Fatal, len(cols), 4)
+					assert.Equal(t.Error, cols[3], teamtbl.NewColumn(
+						respBody.ID, "In Review", "#abcdef", 2,
+					))
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPost,
+					"/board/columns",
+					strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("PATCH", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				reqBody:    `{}`,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can edit boards.",
+				),
+			},
+			{
+				name:     "ColumnNotFound",
+				authFunc: test.AddAuthCookie(test.T1AdminToken),
+				reqBody: `{"boardID": "` + board2 + `", "id": "inbox", ` +
+					`"name": "Inbox"}`,
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Column not found."),
+			},
+			{
+				name:     "OK",
+				authFunc: test.AddAuthCookie(test.T1AdminToken),
+				reqBody: `{"boardID": "` + board2 + `", "id": "doing", ` +
+					`"name": "In Progress", "color": "#00ff00", ` +
+					`"wipLimit": 1}`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					cols := columns(t, board2)
+					assert.Equal(t.Fatal, len(cols), 4)
+					assert.Equal(t.Error, cols[1], teamtbl.NewColumn(
+						"doing", "In Progress", "#00ff00", 1,
+					))
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPatch,
+					"/board/columns",
+					strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("PUT", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			columnIDs  string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				columnIDs:  `[]`,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can edit boards.",
+				),
+			},
+			{
+				name:       "ColumnMissing",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				columnIDs:  `["inbox", "done", "go"]`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Column IDs must contain each of the board's columns " +
+						"exactly once.",
+				),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				columnIDs:  `["inbox", "ready", "done", "go"]`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					// board 1 was created before boards had their own
+					// columns, so the default ones should now be saved in
+					// the new order
+					cols := columns(t, board1)
+					assert.Equal(t.Fatal, len(cols), 4)
+					assert.Equal(t.Error, cols[0].ID, "inbox")
+					assert.Equal(t.Error, cols[1].ID, "ready")
+					assert.Equal(t.Error, cols[2].ID, "done")
+					assert.Equal(t.Error, cols[3].ID, "go")
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPut,
+					"/board/columns",
+					strings.NewReader(`{
+						"boardID": "`+board1+`",
+						"columnIDs": `+c.columnIDs+`
+					}`),
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("DELETE", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			colID      string
+			moveTo     string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				colID:      "inbox",
+				moveTo:     "done",
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can edit boards.",
+				),
+			},
+			{
+				name:       "MoveToSame",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				colID:      "inbox",
+				moveTo:     "inbox",
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Tasks cannot be moved to the column being deleted.",
+				),
+			},
+			{
+				name:       "ColumnNotFound",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				colID:      "todo",
+				moveTo:     "done",
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Column not found."),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				colID:      "inbox",
+				moveTo:     "done",
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					cols := columns(t, board1)
+					assert.Equal(t.Fatal, len(cols), 3)
+					assert.Equal(t.Error, cols[0].ID, "ready")
+					assert.Equal(t.Error, cols[1].ID, "done")
+					assert.Equal(t.Error, cols[2].ID, "go")
+
+					// the task in the inbox should be moved after the one
+					// that was already done
+					tasks, err := tasktbl.NewRetrieverByBoard(test.DB()).
+						Retrieve(context.Background(), board1)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Fatal, len(tasks), 2)
+					for _, task := range tasks {
+						assert.Equal(t.Error, task.ColID, "done")
+						switch task.Title {
+						case "Task 1":
+							assert.Equal(t.Error, task.Order, 5)
+						case "Task 2":
+							assert.Equal(t.Error, task.Order, 4)
+						}
+					}
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodDelete,
+					"/board/columns?boardID="+board1+"&id="+c.colID+
+						"&moveTo="+c.moveTo,
+					nil,
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
@@ -0,0 +1,260 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/inviteapi"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestInviteAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
+	log := log.New()
+	sut := api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: inviteapi.NewGetHandler(
+			authDecoder, invitetbl.NewRetrieverByTeam(test.DB()), log,
+		),
+		http.MethodPost: inviteapi.NewPostHandler(
+			authDecoder,
+			inviteapi.ValidatePostReq,
+			invitetbl.NewInserter(test.DB()),
+			log,
+		),
+		http.MethodDelete: inviteapi.NewDeleteHandler(
+			authDecoder, invitetbl.NewDeleter(test.DB()), log,
+		),
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				reqBody:    "{}",
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				reqBody:    "{}",
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can invite users.",
+				),
+			},
+			{
+				name:       "MaxUsesOutOfBounds",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				reqBody:    `{"maxUses": 0, "expiresInHours": 24}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Invites must be usable 1 to 100 times.",
+				),
+			},
+			{
+				name:     "OK",
+				authFunc: test.AddAuthCookie(test.T1AdminToken),
+				reqBody: `{"username": "alice321", "maxUses": 1, ` +
+					`"expiresInHours": 24}`,
+				wantStatus: http.StatusCreated,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody inviteapi.PostResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+
+					out, err := test.DB().GetItem(
+						context.Background(), &dynamodb.GetItemInput{
+							TableName: &inviteTableName,
+							Key: map[string]types.AttributeValue{
+								"ID": &types.AttributeValueMemberS{
+									Value: respBody.ID,
+								},
+							},
+						},
+					)
+					assert.Nil(t.Fatal, err)
+					var invite invitetbl.Invite
+					err = attributevalue.UnmarshalMap(out.Item, &invite)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error,
+						invite.TeamID, "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+					)
+					assert.Equal(t.Error, invite.CreatedBy, "team1Admin")
+					assert.Equal(t.Error, invite.Username, "alice321")
+					assert.Equal(t.Error, invite.MaxUses, 1)
+					assert.Equal(t.Error, invite.Uses, 0)
+					assert.Equal(t.Error, invite.ExpiresAt, respBody.ExpiresAt)
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPost, "/", strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can view invites.",
+				),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T2AdminToken),
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody inviteapi.GetResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+
+					// only the team's own invites must be returned
+					assert.Equal(t.Fatal, len(respBody.Invites), 1)
+					inv := respBody.Invites[0]
+					assert.Equal(t.Error,
+						inv.ID, "9c1d5e7a-3b8f-4d6e-a2c4-7f1b9e5d3a8c",
+					)
+					assert.Equal(t.Error, inv.CreatedBy, "team2Admin")
+					assert.Equal(t.Error, inv.MaxUses, 1)
+					assert.Equal(t.Error, inv.Uses, 0)
+				},
+			},
+			{
+				name:       "OKPendingOnly",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody inviteapi.GetResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+
+					// the used up invite must not be returned
+					for _, inv := range respBody.Invites {
+						assert.True(t.Error,
+							inv.ID != "5b9e3d1a-7c4f-4a2e-9d8b-1e6f3a9c5d2b",
+						)
+					}
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("DELETE", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			inviteID   string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				inviteID:   "2a7f4c9e-1b3d-4e8a-b6c2-9d5e1f3a7b4c",
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can revoke invites.",
+				),
+			},
+			{
+				name:       "OtherTeam",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				inviteID:   "9c1d5e7a-3b8f-4d6e-a2c4-7f1b9e5d3a8c",
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Invite not found."),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				inviteID:   "2a7f4c9e-1b3d-4e8a-b6c2-9d5e1f3a7b4c",
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					out, err := test.DB().GetItem(
+						context.Background(), &dynamodb.GetItemInput{
+							TableName: &inviteTableName,
+							Key: map[string]types.AttributeValue{
+								"ID": &types.AttributeValueMemberS{
+									Value: "2a7f4c9e-1b3d-4e8a-b6c2-" +
+										"9d5e1f3a7b4c",
+								},
+							},
+						},
+					)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, len(out.Item), 0)
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodDelete, "/?id="+c.inviteID, nil,
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
@@ -15,7 +15,37 @@ import (
 // tableName is the name of the team table used in the integration tests.
 var tableName = "goteam-test-team"
 
-// TestMain sets up the test table in DynamoDB and runs the tests.
+// userTableName is the name of the user table used in the integration tests.
+var userTableName = "goteam-test-team-user"
+
+// inviteTableName is the name of the invite table used in the integration
+// tests.
+var inviteTableName = "goteam-test-team-invite"
+
+// membershipTableName is the name of the membership table used in the
+// integration tests.
+var membershipTableName = "goteam-test-team-membership"
+
+// taskTableName is the name of the task table used in the integration tests.
+var taskTableName = "goteam-test-team-task"
+
+// sessionTableName is the name of the session table used in the integration
+// tests.
+var sessionTableName = "goteam-test-team-session"
+
+// revocationTableName is the name of the revocation table used in the
+// integration tests.
+var revocationTableName = "goteam-test-team-revocation"
+
+// tokenTableName is the name of the personal access token table used in the
+// integration tests.
+var tokenTableName = "goteam-test-team-token"
+
+// identityTableName is the name of the identity table used in the integration
+// tests.
+var identityTableName = "goteam-test-team-identity"
+
+// TestMain sets up the test tables in DynamoDB and runs the tests.
 func TestMain(m *testing.M) {
 	fmt.Println("setting up team table")
 	tearDownTables, err := test.SetUpTestTable(
@@ -27,9 +57,404 @@ func TestMain(m *testing.M) {
 	}
 	defer tearDownTables()
 
+	fmt.Println("setting up user table")
+	tearDownUserTable, err := test.SetUpTestTable(
+		"USER_TABLE_NAME", userTableName, userWriteReqs, "Username", "",
+	)
+	defer tearDownUserTable()
+	if err != nil {
+		log.Println("set up user table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up invite table")
+	tearDownInviteTable, err := test.SetUpTestTable(
+		"INVITE_TABLE_NAME",
+		inviteTableName,
+		inviteWriteReqs,
+		"ID",
+		"",
+		"TeamID",
+	)
+	defer tearDownInviteTable()
+	if err != nil {
+		log.Println("set up invite table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up membership table")
+	tearDownMembershipTable, err := test.SetUpTestTable(
+		"MEMBERSHIP_TABLE_NAME",
+		membershipTableName,
+		membershipWriteReqs,
+		"Username",
+		"TeamID",
+		"TeamID",
+	)
+	defer tearDownMembershipTable()
+	if err != nil {
+		log.Println("set up membership table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up task table")
+	tearDownTaskTable, err := test.SetUpTestTable(
+		"TASK_TABLE_NAME", taskTableName, taskWriteReqs, "TeamID", "ID",
+		"BoardID",
+	)
+	defer tearDownTaskTable()
+	if err != nil {
+		log.Println("set up task table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up session table")
+	tearDownSessionTable, err := test.SetUpTestTable(
+		"SESSION_TABLE_NAME",
+		sessionTableName,
+		sessionWriteReqs,
+		"ID",
+		"",
+		"Username",
+	)
+	defer tearDownSessionTable()
+	if err != nil {
+		log.Println("set up session table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up revocation table")
+	tearDownRevocationTable, err := test.SetUpTestTable(
+		"REVOCATION_TABLE_NAME", revocationTableName, nil, "Username", "ID",
+	)
+	defer tearDownRevocationTable()
+	if err != nil {
+		log.Println("set up revocation table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up token table")
+	tearDownTokenTable, err := test.SetUpTestTable(
+		"TOKEN_TABLE_NAME",
+		tokenTableName,
+		tokenWriteReqs,
+		"ID",
+		"",
+		"Username",
+	)
+	defer tearDownTokenTable()
+	if err != nil {
+		log.Println("set up token table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up identity table")
+	tearDownIdentityTable, err := test.SetUpTestTable(
+		"IDENTITY_TABLE_NAME",
+		identityTableName,
+		identityWriteReqs,
+		"ID",
+		"",
+		"Username",
+	)
+	defer tearDownIdentityTable()
+	if err != nil {
+		log.Println("set up identity table failed:", err)
+		return
+	}
+
 	m.Run()
 }
 
+// sessionWriteReqs are the requests sent to the session test table to
+// initialise it for tests. They are used for checking that the sessions of a
+// member are ended when they are removed from the team.
+var sessionWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "6f1c3e8a-2b9d-4a57-8e40-d3b7a1c59f26",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+		"TokenID": &types.AttributeValueMemberS{
+			Value: "b84e2d1f-7c3a-4f96-a0e5-9d6c2b8f1a73",
+		},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+}
+
+// tokenWriteReqs are the requests sent to the token test table to initialise
+// it for tests. They are used for checking that the personal access tokens of
+// a member are deleted along with them when they are removed from their only
+// team.
+var tokenWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "045b42b4e588334a2dbc76d2008ac9eb" +
+				"f7c05dd06220e22a79f7769a2331e452",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+		"Name":     &types.AttributeValueMemberS{Value: "ci"},
+		"Scopes": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "tasks:read"},
+			},
+		},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+}
+
+// identityWriteReqs are the requests sent to the identity test table to
+// initialise it for tests. They are used for checking that the identities
+// linked to a member are deleted along with them when they are removed from
+// their only team.
+var identityWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "https://idp.goteam.io#sub-4",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+	}}},
+}
+
+// taskWriteReqs are the requests sent to the task test table to initialise it
+// for tests. They are used for checking the usage of team quotas, moving tasks
+// between columns, and deleting tasks along with their board. The first one was
+// created before boards had their own columns, and the third one belongs to a
+// board that is no longer on the team.
+var taskWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"BoardID": &types.AttributeValueMemberS{
+			Value: "91536664-9749-4dbb-a470-6e52aa353ae4",
+		},
+		"ID": &types.AttributeValueMemberS{
+			Value: "c684a6a0-404d-46fa-9fa5-1497f9874567",
+		},
+		"Title": &types.AttributeValueMemberS{Value: "Task 1"},
+		"Subtasks": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"Title": &types.AttributeValueMemberS{
+							Value: "Subtask 1",
+						},
+					},
+				},
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"Title": &types.AttributeValueMemberS{
+							Value: "Subtask 2",
+						},
+					},
+				},
+			},
+		},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"BoardID": &types.AttributeValueMemberS{
+			Value: "91536664-9749-4dbb-a470-6e52aa353ae4",
+		},
+		"ID": &types.AttributeValueMemberS{
+			Value: "9dd9c982-8d1c-49ac-a412-3b01ba74b634",
+		},
+		"ColID": &types.AttributeValueMemberS{Value: "done"},
+		"Title": &types.AttributeValueMemberS{Value: "Task 2"},
+		"Order": &types.AttributeValueMemberN{Value: "4"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"BoardID": &types.AttributeValueMemberS{
+			Value: "e0021a56-6a1e-4007-b773-395d3991fb7e",
+		},
+		"ID": &types.AttributeValueMemberS{
+			Value: "2b9d2d2e-6b3c-4b8a-8c53-7d3d4dc0e7f1",
+		},
+		"Title": &types.AttributeValueMemberS{Value: "Task 3"},
+		"Subtasks": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"Title": &types.AttributeValueMemberS{
+							Value: "Subtask 1",
+						},
+					},
+				},
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"Title": &types.AttributeValueMemberS{
+							Value: "Subtask 2",
+						},
+					},
+				},
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"Title": &types.AttributeValueMemberS{
+							Value: "Subtask 3",
+						},
+					},
+				},
+			},
+		},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "74c80ae5-64f3-4298-a8ff-48f8f920c7d4",
+		},
+		"BoardID": &types.AttributeValueMemberS{
+			Value: "f0c5d521-ccb5-47cc-ba40-313ddb901165",
+		},
+		"ID": &types.AttributeValueMemberS{
+			Value: "4d1f6b2a-8e3c-4a9d-b7f5-2c6e8a1d3f90",
+		},
+		"ColID": &types.AttributeValueMemberS{Value: "inbox"},
+		"Title": &types.AttributeValueMemberS{Value: "Team 3 Task 1"},
+	}}},
+}
+
+// membershipWriteReqs are the requests sent to the membership test table to
+// initialise it for tests.
+var membershipWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team1Member"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "member"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team1Invitee"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "member"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team2Admin"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "owner"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team2Member"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "member"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "member"},
+	}}},
+}
+
+// inviteWriteReqs are the requests sent to the invite test table to initialise
+// it for tests.
+var inviteWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "2a7f4c9e-1b3d-4e8a-b6c2-9d5e1f3a7b4c",
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"CreatedBy": &types.AttributeValueMemberS{Value: "team1Admin"},
+		"Username":  &types.AttributeValueMemberS{Value: ""},
+		"MaxUses":   &types.AttributeValueMemberN{Value: "3"},
+		"Uses":      &types.AttributeValueMemberN{Value: "1"},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "5b9e3d1a-7c4f-4a2e-9d8b-1e6f3a9c5d2b",
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"CreatedBy": &types.AttributeValueMemberS{Value: "team1Admin"},
+		"Username":  &types.AttributeValueMemberS{Value: ""},
+		"MaxUses":   &types.AttributeValueMemberN{Value: "1"},
+		"Uses":      &types.AttributeValueMemberN{Value: "1"},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "9c1d5e7a-3b8f-4d6e-a2c4-7f1b9e5d3a8c",
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+		"CreatedBy": &types.AttributeValueMemberS{Value: "team2Admin"},
+		"Username":  &types.AttributeValueMemberS{Value: ""},
+		"MaxUses":   &types.AttributeValueMemberN{Value: "1"},
+		"Uses":      &types.AttributeValueMemberN{Value: "0"},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+}
+
+// userWriteReqs are the requests sent to the user test table to initialise it
+// for tests.
+var userWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team1Member"},
+		"IsAdmin":  &types.AttributeValueMemberBOOL{Value: false},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"DisplayName": &types.AttributeValueMemberS{Value: "Team 1 Member"},
+		"AvatarURL": &types.AttributeValueMemberS{
+			Value: "https://goteam.io/team1Member.png",
+		},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team1Invitee"},
+		"IsAdmin":  &types.AttributeValueMemberBOOL{Value: false},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team2Admin"},
+		"Role":     &types.AttributeValueMemberS{Value: "owner"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team2Member"},
+		"Role":     &types.AttributeValueMemberS{Value: "member"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+		"IsAdmin":  &types.AttributeValueMemberBOOL{Value: false},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+		},
+	}}},
+	// the item that claims team4Member's username in all casings, which must
+	// be deleted along with them
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#team4member"},
+	}}},
+}
+
 // writeReqs are the requests sent to the test table to initialise it for tests.
 var writeReqs = []types.WriteRequest{
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
@@ -72,6 +497,13 @@ var writeReqs = []types.WriteRequest{
 						"Members": &types.AttributeValueMemberL{
 							Value: []types.AttributeValue{},
 						},
+						"Columns": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{
+								column("todo", "To Do", "#1a2b3c"),
+								column("doing", "Doing", ""),
+								column("finished", "Finished", ""),
+							},
+						},
 					},
 				},
 				&types.AttributeValueMemberM{
@@ -98,6 +530,8 @@ var writeReqs = []types.WriteRequest{
 		"ID": &types.AttributeValueMemberS{
 			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
 		},
+		"Name":  &types.AttributeValueMemberS{Value: "Team 2"},
+		"Owner": &types.AttributeValueMemberS{Value: "team2Admin"},
 		"Members": &types.AttributeValueMemberL{
 			Value: []types.AttributeValue{
 				&types.AttributeValueMemberS{Value: "team2Admin"},
@@ -105,6 +539,11 @@ var writeReqs = []types.WriteRequest{
 			},
 		},
 		"Boards": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+		"Quota": &types.AttributeValueMemberM{
+			Value: map[string]types.AttributeValue{
+				"Members": &types.AttributeValueMemberN{Value: "5"},
+			},
+		},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"ID": &types.AttributeValueMemberS{
@@ -134,6 +573,7 @@ var writeReqs = []types.WriteRequest{
 		"ID": &types.AttributeValueMemberS{
 			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
 		},
+		"Owner": &types.AttributeValueMemberS{Value: "team4Admin"},
 		"Members": &types.AttributeValueMemberL{
 			Value: []types.AttributeValue{
 				&types.AttributeValueMemberS{Value: "team4Admin"},
@@ -156,3 +596,12 @@ var writeReqs = []types.WriteRequest{
 		},
 	}}},
 }
+
+// column returns the attribute value of a board column with the given fields.
+func column(id, name, color string) types.AttributeValue {
+	return &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"ID":    &types.AttributeValueMemberS{Value: id},
+		"Name":  &types.AttributeValueMemberS{Value: name},
+		"Color": &types.AttributeValueMemberS{Value: color},
+	}}
+}
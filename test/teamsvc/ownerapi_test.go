@@ -0,0 +1,147 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/ownerapi"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestOwnerAPI(t *testing.T) {
+	sut := api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPut: ownerapi.NewPutHandler(
+			cookie.NewAuthDecoder(test.KeySet, revocationtbl.NewMemory()),
+			teamtbl.NewRetriever(test.DB()),
+			teamtbl.NewOwnerTransferer(test.DB()),
+			log.New(),
+		),
+	})
+
+	t.Run("PUT", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				reqBody:    `{"username": "team2Member"}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "NotOwner",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				reqBody:    `{"username": "team1Admin"}`,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only the team owner can transfer ownership.",
+				),
+			},
+			{
+				name:       "MemberNotFound",
+				authFunc:   test.AddAuthCookie(test.T2AdminToken),
+				reqBody:    `{"username": "team1Member"}`,
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Member not found."),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T2AdminToken),
+				reqBody:    `{"username": "team2Member"}`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					out, err := test.DB().GetItem(
+						context.Background(), &dynamodb.GetItemInput{
+							TableName: &tableName,
+							Key: map[string]types.AttributeValue{
+								"ID": &types.AttributeValueMemberS{
+									Value: "66ca0ddf-5f62-4713-bcc9-" +
+										"36cb0954eb7b",
+								},
+							},
+						},
+					)
+					assert.Nil(t.Fatal, err)
+					var team teamtbl.Team
+					err = attributevalue.UnmarshalMap(out.Item, &team)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, team.Owner, "team2Member")
+
+					userRetriever := usertbl.NewRetriever(test.DB())
+					newOwner, err := userRetriever.Retrieve(
+						context.Background(), "team2Member",
+					)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, newOwner.Role, role.Owner)
+					oldOwner, err := userRetriever.Retrieve(
+						context.Background(), "team2Admin",
+					)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, oldOwner.Role, role.Admin)
+
+					memberRetriever := membershiptbl.NewRetriever(test.DB())
+					newOwnerM, err := memberRetriever.Retrieve(
+						context.Background(),
+						"team2Member",
+						"66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+					)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, newOwnerM.Role, role.Owner)
+					oldOwnerM, err := memberRetriever.Retrieve(
+						context.Background(),
+						"team2Admin",
+						"66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+					)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, oldOwnerM.Role, role.Admin)
+				},
+			},
+			{
+				name:       "NoLongerOwner",
+				authFunc:   test.AddAuthCookie(test.T2AdminToken),
+				reqBody:    `{"username": "team2Member"}`,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only the team owner can transfer ownership.",
+				),
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPut, "/", strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
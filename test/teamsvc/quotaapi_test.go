@@ -0,0 +1,108 @@
+//go:build itest
+
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/quotaapi"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestQuotaAPI(t *testing.T) {
+	sut := api.NewHandler(map[string]api.MethodHandler{
+		http.MethodGet: quotaapi.NewGetHandler(
+			cookie.NewAuthDecoder(test.KeySet, revocationtbl.NewMemory()),
+			teamtbl.NewRetriever(test.DB()),
+			membershiptbl.NewRetrieverByTeam(test.DB()),
+			tasktbl.NewRetrieverByTeam(test.DB()),
+			quota.DefaultLimits,
+			log.New(),
+		),
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can view quotas.",
+				),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody quotaapi.GetResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+
+					assert.Equal(t.Error, respBody.Limits, quota.DefaultLimits)
+					usage := respBody.Usage
+					assert.Equal(t.Error, usage.Boards, 3)
+					assert.Equal(t.Error, usage.Members, 2)
+					assert.Equal(t.Error, len(usage.TasksPerBoard), 3)
+					board1 := "91536664-9749-4dbb-a470-6e52aa353ae4"
+					assert.Equal(t.Error, usage.TasksPerBoard[board1], 2)
+					board2 := "fdb82637-f6a5-4d55-9dc3-9f60061e632f"
+					assert.Equal(t.Error, usage.TasksPerBoard[board2], 0)
+					assert.Equal(t.Error, usage.SubtasksPerTask, 2)
+				},
+			},
+			{
+				name:       "OKOverridden",
+				authFunc:   test.AddAuthCookie(test.T2AdminToken),
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody quotaapi.GetResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+
+					wantLimits := quota.DefaultLimits
+					wantLimits.Members = 5
+					assert.Equal(t.Error, respBody.Limits, wantLimits)
+					assert.Equal(t.Error, respBody.Usage.Boards, 0)
+					assert.Equal(t.Error, respBody.Usage.Members, 2)
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/team/quota", nil)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
@@ -0,0 +1,136 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/roleapi"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestRoleAPI(t *testing.T) {
+	sut := api.NewHandler(map[string]api.MethodHandler{
+		http.MethodPut: roleapi.NewPutHandler(
+			cookie.NewAuthDecoder(test.KeySet, revocationtbl.NewMemory()),
+			teamtbl.NewRetriever(test.DB()),
+			membershiptbl.NewUpdater(test.DB()),
+			log.New(),
+		),
+	})
+
+	// assertRole returns a function that asserts that team4Member has the
+	// given role both in their membership and in the user table
+	assertRole := func(
+		want role.Role,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, _ *http.Response, _ []any) {
+			m, err := membershiptbl.NewRetriever(test.DB()).Retrieve(
+				context.Background(),
+				"team4Member",
+				"3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+			)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, m.Role, want)
+
+			user, err := usertbl.NewRetriever(test.DB()).Retrieve(
+				context.Background(), "team4Member",
+			)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, user.Role, want)
+		}
+	}
+
+	t.Run("PUT", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				reqBody:    `{"username": "team4Member", "role": "admin"}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "NotOwner",
+				authFunc:   test.AddAuthCookie(test.T4MemberToken),
+				reqBody:    `{"username": "team4Member", "role": "admin"}`,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only the team owner can change roles.",
+				),
+			},
+			{
+				name:       "RoleInvalid",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				reqBody:    `{"username": "team4Member", "role": "owner"}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Role must be admin, member, or viewer.",
+				),
+			},
+			{
+				name:       "MemberNotFound",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				reqBody:    `{"username": "team1Member", "role": "admin"}`,
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Member not found."),
+			},
+			{
+				name:       "NoLongerOwner",
+				authFunc:   test.AddAuthCookie(test.T2AdminToken),
+				reqBody:    `{"username": "team2Member", "role": "viewer"}`,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only the team owner can change roles.",
+				),
+			},
+			{
+				name:       "OKPromote",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				reqBody:    `{"username": "team4Member", "role": "admin"}`,
+				wantStatus: http.StatusOK,
+				assertFunc: assertRole(role.Admin),
+			},
+			{
+				name:       "OKDemote",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				reqBody:    `{"username": "team4Member", "role": "member"}`,
+				wantStatus: http.StatusOK,
+				assertFunc: assertRole(role.Member),
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPut, "/", strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
@@ -7,32 +7,48 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/golang-jwt/jwt/v4"
 
 	"github.com/kxplxn/goteam/internal/teamsvc/teamapi"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/tasktbl"
 	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestTeamAPI(t *testing.T) {
 	handler := teamapi.NewGetHandler(
-		cookie.NewAuthDecoder(test.JWTKey),
+		cookie.NewAuthDecoder(test.KeySet, revocationtbl.NewMemory()),
 		teamtbl.NewRetriever(test.DB()),
 		teamtbl.NewInserter(test.DB()),
 		teamtbl.NewUpdater(test.DB()),
-		cookie.NewInviteEncoder(test.JWTKey, 1*time.Hour),
+		membershiptbl.NewRetriever(test.DB()),
+		usertbl.NewProfileRetriever(test.DB()),
+		tasktbl.NewRetrieverByBoard(test.DB()),
 		log.New(),
 	)
 
+	// team1Members are the members of team 1 as returned by GET team. Only
+	// team1Member has a profile, and team1Admin is not in the user table.
+	team1Members := []teamapi.Member{
+		{Username: "team1Admin", DisplayName: "", AvatarURL: ""},
+		{
+			Username:    "team1Member",
+			DisplayName: "Team 1 Member",
+			AvatarURL:   "https://goteam.io/team1Member.png",
+		},
+	}
+
 	t.Run("GET", func(t *testing.T) {
 		for _, c := range []struct {
 			name       string
@@ -55,8 +71,10 @@ func TestTeamAPI(t *testing.T) {
 			{
 				name: "NotAdmin",
 				authFunc: test.AddAuthCookie(
-					"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJpc0FkbWluIjpmYWx" +
-						"zZX0.Uz6JmqHbxSrzyKAIktxRW4Y_0ldqi_bEcNkYfvIIM8I",
+					"eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0." +
+						"eyJpc0FkbWluIjpmYWxzZX0.UQOPQwqMv3GUua_dHRbgXiWxRoKu" +
+						"yIH88pPbRpfyqNY1iJU0ToDNx2-7RvnC1m2GHBEhznFroKxqYq8H" +
+						"v6NxAA",
 				),
 				wantStatus: http.StatusUnauthorized,
 				assertFunc: func(*testing.T, *http.Response) {},
@@ -64,10 +82,11 @@ func TestTeamAPI(t *testing.T) {
 			{
 				name: "Created",
 				authFunc: test.AddAuthCookie(
-					"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJpc0FkbWluIjp0cn" +
-						"VlLCJ0ZWFtSUQiOiJkNWRjYTliYy1iYzk4LTQ3YjQtYjhiNy05Z" +
-						"jAxODEzZGE1NzEiLCJ1c2VybmFtZSI6Im5ld3VzZXIifQ.lCjQi" +
-						"rzU_3yxOi2bNXRLuyxgzbUnEftITcIFMz2jCb8",
+					"eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0." +
+						"eyJpc0FkbWluIjp0cnVlLCJ0ZWFtSUQiOiJkNWRjYTliYy1iYzk4" +
+						"LTQ3YjQtYjhiNy05ZjAxODEzZGE1NzEiLCJ1c2VybmFtZSI6Im5l" +
+						"d3VzZXIifQ.UsxmxaqzqaGtz5fjU-b6_vrkK5dWsJ6fqUNmL2H8wx" +
+						"vzV61bK6NXietLtSOydaaNd2GAxYSVoIWT6xjcpzmGBA",
 				),
 				wantStatus: http.StatusCreated,
 				assertFunc: func(t *testing.T, resp *http.Response) {
@@ -81,7 +100,11 @@ func TestTeamAPI(t *testing.T) {
 					if err != nil {
 						t.Fatal(err)
 					}
-					assert.AllEqual(t.Error, respBody.Members, wantMembers)
+					assert.Equal(t.Error, respBody.Owner, "newuser")
+					assert.Equal(t.Error, respBody.Name, "New Team")
+					assert.AllEqual(t.Error, respBody.Members, []teamapi.Member{
+						{Username: "newuser", DisplayName: "", AvatarURL: ""},
+					})
 					assert.Equal(t.Error, len(respBody.Boards), wantBoardLen)
 					assert.Equal(t.Error, respBody.Boards[0].Name, wantBoardName)
 
@@ -118,8 +141,8 @@ func TestTeamAPI(t *testing.T) {
 				assertFunc: func(t *testing.T, resp *http.Response) {
 					wantResp := teamapi.GetResp{
 						ID:      "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
-						Members: []string{"team1Admin", "team1Member"},
-						Boards: []teamtbl.Board{
+						Members: team1Members,
+						Boards: []teamapi.Board{
 							{
 								ID:      "91536664-9749-4dbb-a470-6e52aa353ae4",
 								Name:    "Team 1 Board 1",
@@ -158,35 +181,11 @@ func TestTeamAPI(t *testing.T) {
 						assert.AllEqual(t.Error, b.Members, wantB.Members)
 					}
 
-					ckInv := resp.Cookies()[0]
-					assert.Equal(t.Error, ckInv.Name, "invite-token")
-					assert.True(t.Error,
-						ckInv.Expires.After(time.Now().Add(59*time.Minute)))
-					assert.True(t.Error,
-						ckInv.Expires.Before(time.Now().Add(61*time.Minute)))
-					claims := jwt.MapClaims{}
-					if _, err := jwt.ParseWithClaims(
-						ckInv.Value, &claims, func(token *jwt.Token) (any, error) {
-							return test.JWTKey, nil
-						},
-					); err != nil {
-						t.Error(err)
-					}
-					assert.Equal(t.Error,
-						claims["teamID"].(string),
-						"afeadc4a-68b0-4c33-9e83-4648d20ff26a")
-					assert.True(t.Error,
-						claims["exp"].(float64) >
-							float64(time.Now().Add(59*time.Minute).Unix()),
-					)
-					assert.True(t.Error,
-						claims["exp"].(float64) >
-							float64(time.Now().Add(59*time.Minute).Unix()),
-					)
-					assert.True(t.Error,
-						claims["exp"].(float64) <
-							float64(time.Now().Add(61*time.Minute).Unix()),
-					)
+					// both of board 1's tasks were moved into its done column
+					// when its inbox column was deleted
+					done := respBody.Boards[0].Columns[1]
+					assert.Equal(t.Error, done.ID, "done")
+					assert.Equal(t.Error, done.Count, 2)
 				},
 			},
 			{
@@ -196,8 +195,8 @@ func TestTeamAPI(t *testing.T) {
 				assertFunc: func(t *testing.T, resp *http.Response) {
 					wantResp := teamapi.GetResp{
 						ID:      "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
-						Members: []string{"team1Admin", "team1Member"},
-						Boards: []teamtbl.Board{
+						Members: team1Members,
+						Boards: []teamapi.Board{
 							{
 								ID:      "91536664-9749-4dbb-a470-6e52aa353ae4",
 								Name:    "Team 1 Board 1",
@@ -239,9 +238,9 @@ func TestTeamAPI(t *testing.T) {
 				assertFunc: func(t *testing.T, resp *http.Response) {
 					wantResp := teamapi.GetResp{
 						ID: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
-						Members: []string{
-							"team1Admin", "team1Member", "team1Invitee",
-						},
+						Members: append(team1Members, teamapi.Member{
+							Username: "team1Invitee",
+						}),
 					}
 
 					var respBody teamapi.GetResp
@@ -271,4 +270,106 @@ func TestTeamAPI(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("PATCH", func(t *testing.T) {
+		handler := teamapi.NewPatchHandler(
+			cookie.NewAuthDecoder(test.KeySet, revocationtbl.NewMemory()),
+			teamtbl.NewRetriever(test.DB()),
+			teamtbl.NewUpdater(test.DB()),
+			log.New(),
+		)
+
+		// retrieveTeam2 retrieves team 2 from the team table to check the
+		// changes made to its settings.
+		retrieveTeam2 := func(t *testing.T) teamtbl.Team {
+			out, err := test.DB().GetItem(
+				context.Background(),
+				&dynamodb.GetItemInput{
+					TableName: &tableName,
+					Key: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberS{
+							Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+						},
+					},
+				},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var team teamtbl.Team
+			err = attributevalue.UnmarshalMap(out.Item, &team)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return team
+		}
+
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				reqBody:    `{"name": "Team Two", "requireMFA": true}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "InvalidAuth",
+				authFunc:   test.AddAuthCookie("asdfasdf"),
+				reqBody:    `{"name": "Team Two", "requireMFA": true}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				reqBody:    `{"name": "Team Two", "requireMFA": true}`,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can edit the team.",
+				),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T2AdminToken),
+				reqBody:    `{"name": "Team Two", "requireMFA": true}`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					team := retrieveTeam2(t)
+					assert.Equal(t.Error, team.Name, "Team Two")
+					assert.True(t.Error, team.RequireMFA)
+				},
+			},
+			{
+				name:       "OKRenameOnly",
+				authFunc:   test.AddAuthCookie(test.T2AdminToken),
+				reqBody:    `{"name": "Team 2"}`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					team := retrieveTeam2(t)
+					assert.Equal(t.Error, team.Name, "Team 2")
+					assert.True(t.Error, team.RequireMFA)
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				r := httptest.NewRequest(
+					http.MethodPatch, "/team", strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+				w := httptest.NewRecorder()
+
+				handler.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
 }
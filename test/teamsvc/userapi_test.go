@@ -0,0 +1,365 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/internal/teamsvc/boardapi"
+	"github.com/kxplxn/goteam/internal/teamsvc/userapi"
+	"github.com/kxplxn/goteam/pkg/api"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestUserAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
+	log := log.New()
+	sut := api.NewHandler(map[string]api.MethodHandler{
+		http.MethodDelete: userapi.NewDeleteHandler(
+			authDecoder,
+			teamtbl.NewRetriever(test.DB()),
+			revocationtbl.NewInserter(test.DB()),
+			sessiontbl.NewDeleterByUser(test.DB()),
+			membershiptbl.NewDeleter(test.DB()),
+			membershiptbl.NewRetrieverByUser(test.DB()),
+			usertbl.NewRetriever(test.DB()),
+			usertbl.NewUpdater(test.DB()),
+			tokentbl.NewRetrieverByUser(test.DB()),
+			tokentbl.NewDeleter(test.DB()),
+			identitytbl.NewRetrieverByUser(test.DB()),
+			identitytbl.NewDeleter(test.DB()),
+			usertbl.NewDeleter(test.DB()),
+			teamtbl.NewUpdater(test.DB()),
+			log,
+		),
+		http.MethodPatch: userapi.NewPatchHandler(
+			authDecoder,
+			boardapi.NewIDValidator(),
+			teamtbl.NewRetriever(test.DB()),
+			teamtbl.NewBoardUpdater(test.DB()),
+			log,
+		),
+	})
+
+	t.Run("PATCH", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			username   string
+			boardID    string
+			isActive   bool
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				username:   "",
+				boardID:    "",
+				isActive:   false,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "InvalidAuth",
+				authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+				username:   "",
+				boardID:    "",
+				isActive:   false,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				username:   "team1Member",
+				boardID:    "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+				isActive:   false,
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can edit board members.",
+				),
+			},
+			{
+				name:       "UsernameEmpty",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				username:   "",
+				boardID:    "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+				isActive:   false,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Username cannot be empty."),
+			},
+			{
+				name:       "UsernameAdmin",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				username:   "team1Admin",
+				boardID:    "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+				isActive:   false,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Team admins have access to all boards.",
+				),
+			},
+			{
+				name:       "BoardIDEmpty",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				username:   "team1Member",
+				boardID:    "",
+				isActive:   false,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Board ID cannot be empty."),
+			},
+			{
+				name:       "BoardIDNotUUID",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				username:   "team1Member",
+				boardID:    "1559a33c",
+				isActive:   false,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Board ID must be a UUID."),
+			},
+			{
+				name:       "MemberNotFound",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				username:   "team4Member",
+				boardID:    "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+				isActive:   false,
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Member not found."),
+			},
+			{
+				name:       "BoardNotFound",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				username:   "team1Member",
+				boardID:    "ca47fbec-269e-4ef4-a74a-bcfbcd599fd5",
+				isActive:   false,
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Board not found."),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				username:   "team1Member",
+				boardID:    "1559a33c-54c5-42c8-8e5f-fe096f7760fa",
+				isActive:   false,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					out, err := test.DB().GetItem(
+						context.Background(), &dynamodb.GetItemInput{
+							TableName: &tableName,
+							Key: map[string]types.AttributeValue{
+								"ID": &types.AttributeValueMemberS{
+									Value: "afeadc4a-68b0-4c33-9e83-4648d20ff" +
+										"26a",
+								},
+							},
+						},
+					)
+					assert.Nil(t.Fatal, err)
+
+					var team *teamtbl.Team
+					err = attributevalue.UnmarshalMap(out.Item, &team)
+					assert.Nil(t.Fatal, err)
+
+					for _, b := range team.Boards {
+						if b.ID == "1559a33c-54c5-42c8-8e5f-fe096f7760fa" {
+							assert.Equal(t.Error, len(b.Members), 0)
+							return
+						}
+					}
+					t.Error("board not found")
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPatch,
+					"/user?username="+c.username,
+					strings.NewReader(`{
+                        "boardID": "`+c.boardID+`",
+                        "isActive": `+strconv.FormatBool(c.isActive)+`
+                    }`),
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("DELETE", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			username   string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				username:   "",
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "InvalidAuth",
+				authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+				username:   "",
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:       "NotAdmin",
+				authFunc:   test.AddAuthCookie(test.T4MemberToken),
+				username:   "team4Member",
+				wantStatus: http.StatusForbidden,
+				assertFunc: assert.OnRespErr(
+					"Only team admins can remove members.",
+				),
+			},
+			{
+				name:       "UsernameEmpty",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				username:   "",
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Username cannot be empty."),
+			},
+			{
+				name:       "MemberNotFound",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				username:   "team1Member",
+				wantStatus: http.StatusNotFound,
+				assertFunc: assert.OnRespErr("Member not found."),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				username:   "team4Member",
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					out, err := test.DB().GetItem(
+						context.Background(), &dynamodb.GetItemInput{
+							TableName: &tableName,
+							Key: map[string]types.AttributeValue{
+								"ID": &types.AttributeValueMemberS{
+									Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223" +
+										"bbc",
+								},
+							},
+						},
+					)
+					assert.Nil(t.Fatal, err)
+
+					var team *teamtbl.Team
+					err = attributevalue.UnmarshalMap(out.Item, &team)
+					assert.Nil(t.Fatal, err)
+
+					assert.AllEqual(t.Error, team.Members, []string{
+						"team4Admin",
+					})
+					for _, b := range team.Boards {
+						for _, m := range b.Members {
+							assert.True(t.Error, m != "team4Member")
+						}
+					}
+
+					out, err = test.DB().GetItem(
+						context.Background(), &dynamodb.GetItemInput{
+							TableName: &userTableName,
+							Key: map[string]types.AttributeValue{
+								"Username": &types.AttributeValueMemberS{
+									Value: "team4Member",
+								},
+							},
+						},
+					)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, len(out.Item), 0)
+					_, err = usertbl.NewRetriever(test.DB()).Retrieve(
+						context.Background(),
+						usertbl.ClaimUsername("team4Member"),
+					)
+					assert.Equal(t.Error, err, db.ErrNoItem)
+
+					_, err = membershiptbl.NewRetriever(test.DB()).Retrieve(
+						context.Background(),
+						"team4Member",
+						"3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+					)
+					assert.Equal(t.Error, err, db.ErrNoItem)
+
+					// the member's personal access tokens and identities must
+					// be deleted along with them so that they don't let in
+					// whoever registers the username next
+					tokens, err := tokentbl.NewRetrieverByUser(test.DB()).
+						Retrieve(context.Background(), "team4Member")
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, len(tokens), 0)
+					identities, err := identitytbl.NewRetrieverByUser(
+						test.DB(),
+					).Retrieve(context.Background(), "team4Member")
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, len(identities), 0)
+
+					// the member's tokens must be revoked and their sessions
+					// ended
+					_, err = sessiontbl.NewRetriever(test.DB()).Retrieve(
+						context.Background(),
+						"6f1c3e8a-2b9d-4a57-8e40-d3b7a1c59f26",
+					)
+					assert.Equal(t.Error, err, db.ErrNoItem)
+					isRevoked, err := revocationtbl.NewChecker(test.DB()).
+						IsRevoked(
+							context.Background(), "team4Member", "",
+							time.Now().Add(-time.Minute).UnixMilli(),
+						)
+					assert.Nil(t.Fatal, err)
+					assert.True(t.Error, isRevoked)
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodDelete, "/user?username="+c.username, nil,
+				)
+				c.authFunc(r)
+
+				sut.ServeHTTP(w, r)
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
@@ -5,49 +5,69 @@
 package test
 
 import (
+	"crypto/ed25519"
+
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/pkg/cookie"
 )
 
 // db is the DynamoDB client used in integration tests.
 var db *dynamodb.Client
 
-// JWTKey is the key used to sign/validate JWTs in integration tests.
+// JWTKey is the key used to sign/validate refresh tokens in integration tests.
 var JWTKey = []byte("itest-jwt-key-0123456789qwerty")
 
+// SigningKey is the key used to sign auth tokens in integration tests.
+var SigningKey, _ = cookie.NewSigningKey("itest", ed25519.NewKeyFromSeed(
+	[]byte("itest-signing-key-0123456789qwer"),
+))
+
+// KeySet is the key set used to validate auth tokens in integration tests.
+var KeySet = cookie.NewStaticKeySet(SigningKey)
+
 // JWTs used in integration tests.
 const (
-	T1AdminToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJib2FyZElEcyI6WyI" +
-		"5MTUzNjY2NC05NzQ5LTRkYmItYTQ3MC02ZTUyYWEzNTNhZTQiLCJmZGI4MjYzNy1mNm" +
-		"E1LTRkNTUtOWRjMy05ZjYwMDYxZTYzMmYiLCIxNTU5YTMzYy01NGM1LTQyYzgtOGU1Z" +
-		"i1mZTA5NmY3NzYwZmEiXSwiaXNBZG1pbiI6dHJ1ZSwidGVhbUlEIjoiYWZlYWRjNGEt" +
-		"NjhiMC00YzMzLTllODMtNDY0OGQyMGZmMjZhIiwidXNlcm5hbWUiOiJ0ZWFtMUFkbWl" +
-		"uIn0.bOJnHy1J6PkbZpDCfKN3FdlCO3uXwJYxgJTKI2srp6E"
-	T1MemberToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJib2FyZElEcyI6WyI" +
-		"5MTUzNjY2NC05NzQ5LTRkYmItYTQ3MC02ZTUyYWEzNTNhZTQiLCJmZGI4MjYzNy1mNmE" +
-		"1LTRkNTUtOWRjMy05ZjYwMDYxZTYzMmYiLCIxNTU5YTMzYy01NGM1LTQyYzgtOGU1Zi1" +
-		"mZTA5NmY3NzYwZmEiXSwiaXNBZG1pbiI6ZmFsc2UsInRlYW1JRCI6ImFmZWFkYzRhLTY" +
-		"4YjAtNGMzMy05ZTgzLTQ2NDhkMjBmZjI2YSIsInVzZXJuYW1lIjoidGVhbTFNZW1iZXI" +
-		"ifQ.lMskCZoProRSWxKsYzE5K9E4BCKKbTLnMLkwlwuXS_I"
-	T1InviteeToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJpc0FkbWluIjpmYW" +
-		"xzZSwidGVhbUlEIjoiYWZlYWRjNGEtNjhiMC00YzMzLTllODMtNDY0OGQyMGZmMjZhIi" +
-		"widXNlcm5hbWUiOiJ0ZWFtMUludml0ZWUifQ.yy28Kb1tCwMCkdHPSzgwH3kqgQONXAR" +
-		"tP6LpFGlnDTY"
-	T2AdminToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJib2FyZElEcyI6W10s" +
-		"ImlzQWRtaW4iOnRydWUsInRlYW1JRCI6IjY2Y2EwZGRmLTVmNjItNDcxMy1iY2M5LTM2" +
-		"Y2IwOTU0ZWI3YiIsInVzZXJuYW1lIjoidGVhbTJBZG1pbiJ9.Y4Ah4bQHfFg9yVLf70Z" +
-		"kWc3kKCDSOBoLwBB9dXW8RT4"
-	T3AdminToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJpc0FkbWluIjp0cnVl" +
-		"LCJ0ZWFtSUQiOiI3NGM4MGFlNS02NGYzLTQyOTgtYThmZi00OGY4ZjkyMGM3ZDQiLCJ1" +
-		"c2VybmFtZSI6InRlYW0zQWRtaW4ifQ.eqPoE2WmFwzNgCatB9IUzyMmSRn0_t-VjIA2d" +
-		"WVN3vU"
-	T4AdminToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJpc0FkbWluIjp0cnVl" +
-		"LCJ0ZWFtSUQiOiIzYzNlYzRlYS1hODUwLTRmYzUtYWFiMC0yNGU5ZTcyMjNiYmMiLCJ1" +
-		"c2VybmFtZSI6InRlYW00QWRtaW4ifQ.pmbrD7hCLsP5m_ePZHkEK-JbEQfPGbY1EOR24" +
-		"C2PsUA"
-	T4MemberToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJpc0FkbWluIjpmYWx" +
-		"zZSwidGVhbUlEIjoiM2MzZWM0ZWEtYTg1MC00ZmM1LWFhYjAtMjRlOWU3MjIzYmJjIiw" +
-		"idXNlcm5hbWUiOiJ0ZWFtNE1lbWJlciJ9.UNjSqhfTpB_IQ68Le_ApwAKlh4lBoG7gDt" +
-		"N02CFKdLw"
+	T1AdminToken = "eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0.e" +
+		"yJib2FyZElEcyI6WyI5MTUzNjY2NC05NzQ5LTRkYmItYTQ3MC02ZTUyYWEzNTNhZTQiLCJ" +
+		"mZGI4MjYzNy1mNmE1LTRkNTUtOWRjMy05ZjYwMDYxZTYzMmYiLCIxNTU5YTMzYy01NGM1L" +
+		"TQyYzgtOGU1Zi1mZTA5NmY3NzYwZmEiXSwiaXNBZG1pbiI6dHJ1ZSwidGVhbUlEIjoiYWZ" +
+		"lYWRjNGEtNjhiMC00YzMzLTllODMtNDY0OGQyMGZmMjZhIiwidXNlcm5hbWUiOiJ0ZWFtM" +
+		"UFkbWluIn0.DUpFF5Gl2TOIuRlqwSlichXUM8RRerJgKeZlCPEAd6IA9Im7X4Qq-YupBzF" +
+		"-msfgqzDxYFiy8mKLT4pEt0qbCg"
+	T1MemberToken = "eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0." +
+		"eyJib2FyZElEcyI6WyI5MTUzNjY2NC05NzQ5LTRkYmItYTQ3MC02ZTUyYWEzNTNhZTQiLC" +
+		"JmZGI4MjYzNy1mNmE1LTRkNTUtOWRjMy05ZjYwMDYxZTYzMmYiLCIxNTU5YTMzYy01NGM1" +
+		"LTQyYzgtOGU1Zi1mZTA5NmY3NzYwZmEiXSwiaXNBZG1pbiI6ZmFsc2UsInRlYW1JRCI6Im" +
+		"FmZWFkYzRhLTY4YjAtNGMzMy05ZTgzLTQ2NDhkMjBmZjI2YSIsInVzZXJuYW1lIjoidGVh" +
+		"bTFNZW1iZXIifQ.Med0E-NNo6lqPJm6L6xOJMi4vF2wdWxu9EeYw3RzmpZuIZBzeMjOeSY" +
+		"Hm1vDq00p-9IjpH_XBsJr4p5eelcrBQ"
+	T1ViewerToken = "eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0.e" +
+		"yJpc0FkbWluIjpmYWxzZSwicm9sZSI6InZpZXdlciIsInRlYW1JRCI6ImFmZWFkYzRhLTY4" +
+		"YjAtNGMzMy05ZTgzLTQ2NDhkMjBmZjI2YSIsInVzZXJuYW1lIjoidGVhbTFWaWV3ZXIifQ." +
+		"PcuNe64jx1uzX8WQuRw6M97KcE_cDCqYEvR4qDCG1lh-bKBeDSJDtS4WoHsS-QTyOPKzTGB" +
+		"62y8MB_aDeb28AA"
+	T1InviteeToken = "eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0" +
+		".eyJpc0FkbWluIjpmYWxzZSwidGVhbUlEIjoiYWZlYWRjNGEtNjhiMC00YzMzLTllODMtN" +
+		"DY0OGQyMGZmMjZhIiwidXNlcm5hbWUiOiJ0ZWFtMUludml0ZWUifQ.pausQQnZ9TmRwcSE" +
+		"QY8ceN0VA0EnmRGFIyglahY53oZZ3AM7TX8WWVKQa6FLweMzgfL_oI0vFgXl16CSQ7d2BA"
+	T2AdminToken = "eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0.e" +
+		"yJib2FyZElEcyI6W10sImlzQWRtaW4iOnRydWUsInRlYW1JRCI6IjY2Y2EwZGRmLTVmNjI" +
+		"tNDcxMy1iY2M5LTM2Y2IwOTU0ZWI3YiIsInVzZXJuYW1lIjoidGVhbTJBZG1pbiJ9.c-FR" +
+		"izTLh4qWzI-baCN7c0xE-feyS4RyawVL3zl3roJzv3eZQLdPOK6vTQ5y8J6AH6_4HS9VPK" +
+		"Yac1XnLJ9LBQ"
+	T3AdminToken = "eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0.e" +
+		"yJpc0FkbWluIjp0cnVlLCJ0ZWFtSUQiOiI3NGM4MGFlNS02NGYzLTQyOTgtYThmZi00OGY" +
+		"4ZjkyMGM3ZDQiLCJ1c2VybmFtZSI6InRlYW0zQWRtaW4ifQ.10tt1vXE1kvmwm56FUbTZm" +
+		"UwqLrXx-Ana5axabSZNrSqUNSWdQt2w-UyRgk6m7oddJ9XiRk8sF9AbEGwpwMgBg"
+	T4AdminToken = "eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0.e" +
+		"yJpc0FkbWluIjp0cnVlLCJ0ZWFtSUQiOiIzYzNlYzRlYS1hODUwLTRmYzUtYWFiMC0yNGU" +
+		"5ZTcyMjNiYmMiLCJ1c2VybmFtZSI6InRlYW00QWRtaW4ifQ.P5qhS4TMWSBFcP9CYZ0kqt" +
+		"4EoJnNo4sgYMnRT-3XrgIBew5_MKL60eCglk3aVKMC6z8p-NuF0WVCylMPLwacAQ"
+	T4MemberToken = "eyJhbGciOiJFZERTQSIsImtpZCI6Iml0ZXN0IiwidHlwIjoiSldUIn0." +
+		"eyJpc0FkbWluIjpmYWxzZSwidGVhbUlEIjoiM2MzZWM0ZWEtYTg1MC00ZmM1LWFhYjAtMj" +
+		"RlOWU3MjIzYmJjIiwidXNlcm5hbWUiOiJ0ZWFtNE1lbWJlciJ9.ch8ZNEXwQ5GJHK6MT-c" +
+		"Z31xA3raKOQ-UmWuVSESxMAlpAtywg1AE0cJjZeNDvHYhWrOz1pbuyIAo-aV6I-QjCw"
 
 	EmptyStateToken = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJib2FyZHMiOltdf" +
 		"Q.glA6vOsGSCUo4w2tsiAqyngpLelGOLA0cguBXnx-ans"
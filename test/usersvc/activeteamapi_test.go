@@ -0,0 +1,144 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/internal/usersvc/activeteamapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestActiveTeamAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewChecker(test.DB()),
+	)
+	sut := activeteamapi.NewPutHandler(
+		authDecoder,
+		membershiptbl.NewRetriever(test.DB()),
+		usertbl.NewRetriever(test.DB()),
+		teamtbl.NewRetriever(test.DB()),
+		usertbl.NewUpdater(test.DB()),
+		cookie.NewAuthEncoder(test.SigningKey, 1*time.Hour),
+		log.New(),
+	)
+
+	// assertActiveTeam returns an assert function that checks that the auth
+	// token issued and the user's active team are for the given team.
+	assertActiveTeam := func(
+		username, teamID string, wantRole role.Role,
+	) func(*testing.T, *http.Response, []any) {
+		return func(t *testing.T, resp *http.Response, _ []any) {
+			cks := resp.Cookies()
+			assert.Equal(t.Fatal, len(cks), 1)
+			auth, err := authDecoder.Decode(*cks[0])
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, auth.Username, username)
+			assert.Equal(t.Error, auth.TeamID, teamID)
+			assert.Equal(t.Error, auth.Role, wantRole)
+
+			user, err := usertbl.NewRetriever(test.DB()).Retrieve(
+				context.Background(), username,
+			)
+			assert.Nil(t.Fatal, err)
+			assert.Equal(t.Error, user.TeamID, teamID)
+			assert.Equal(t.Error, user.Role, wantRole)
+		}
+	}
+
+	for _, c := range []struct {
+		name       string
+		authFunc   func(*http.Request)
+		reqBody    string
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NoAuth",
+			authFunc:   func(*http.Request) {},
+			reqBody:    "",
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:       "InvalidAuth",
+			authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+			reqBody:    "",
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:       "EmptyTeamID",
+			authFunc:   test.AddAuthCookie(test.T1MemberToken),
+			reqBody:    `{"teamID": ""}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("Team ID cannot be empty."),
+		},
+		{
+			name:       "NotMember",
+			authFunc:   test.AddAuthCookie(test.T1AdminToken),
+			reqBody:    `{"teamID": "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b"}`,
+			wantStatus: http.StatusNotFound,
+			assertFunc: assert.OnRespErr("Team not found."),
+		},
+		{
+			name:       "MFARequired",
+			authFunc:   test.AddAuthCookie(test.T1AdminToken),
+			reqBody:    `{"teamID": "b8e2c6a1-4f3d-4b9e-a7c5-2d0f8e6b3a91"}`,
+			wantStatus: http.StatusForbidden,
+			assertFunc: assert.OnRespErr(
+				"This team requires two-factor authentication.",
+			),
+		},
+		{
+			name:       "OK",
+			authFunc:   test.AddAuthCookie(test.T1MemberToken),
+			reqBody:    `{"teamID": "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b"}`,
+			wantStatus: http.StatusOK,
+			assertFunc: assertActiveTeam(
+				"team1Member",
+				"66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+				role.Viewer,
+			),
+		},
+		{
+			// switch back so that the other tests see team1Member in team 1
+			name:       "OKSwitchBack",
+			authFunc:   test.AddAuthCookie(test.T1MemberToken),
+			reqBody:    `{"teamID": "afeadc4a-68b0-4c33-9e83-4648d20ff26a"}`,
+			wantStatus: http.StatusOK,
+			assertFunc: assertActiveTeam(
+				"team1Member",
+				"afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+				role.Member,
+			),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPut, "/", strings.NewReader(c.reqBody),
+			)
+			c.authFunc(r)
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, []any{})
+		})
+	}
+}
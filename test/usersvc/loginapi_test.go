@@ -3,6 +3,8 @@
 package test
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -14,17 +16,32 @@ import (
 	"github.com/kxplxn/goteam/internal/usersvc/loginapi"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mfa"
+	"github.com/kxplxn/goteam/pkg/password"
 	"github.com/kxplxn/goteam/test"
 )
 
 func TestLoginAPI(t *testing.T) {
 	sut := loginapi.NewPostHandler(
 		loginapi.NewValidator(),
-		usertbl.NewRetriever(test.DB()),
-		loginapi.NewPasswordComparator(),
-		cookie.NewAuthEncoder(test.JWTKey, 1*time.Hour),
+		attempttbl.NewRetriever(test.DB()),
+		attempttbl.NewIncrementer(test.DB()),
+		attempttbl.NewDeleter(test.DB()),
+		usertbl.NewRetrieverAnyCase(test.DB()),
+		password.NewHasher(password.DefaultParams),
+		password.NewHasher(password.DefaultParams),
+		usertbl.NewUpdater(test.DB()),
+		teamtbl.NewRetriever(test.DB()),
+		cookie.NewMFAEncoder(test.JWTKey, 5*time.Minute),
+		cookie.NewAuthEncoder(test.SigningKey, 1*time.Hour),
+		cookie.NewRefreshEncoder(test.JWTKey, 24*time.Hour),
+		sessiontbl.NewInserter(test.DB()),
+		log.New(),
 		log.New(),
 	)
 
@@ -63,12 +80,50 @@ func TestLoginAPI(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			assertFunc:     func(*testing.T, *http.Response) {},
 		},
+		{
+			name:           "LockedOut",
+			username:       "team4Admin",
+			password:       "P4ssw@rd123",
+			wantStatusCode: http.StatusTooManyRequests,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.True(t.Error, resp.Header.Get("Retry-After") != "")
+			},
+		},
+		{
+			name:           "MFAVerify",
+			username:       "mfaVerifier",
+			password:       "P4ssw@rd123",
+			wantStatusCode: http.StatusOK,
+			assertFunc:     assertMFAStep(loginapi.MFAVerify),
+		},
+		{
+			name:           "MFAEnroll",
+			username:       "mfaEnrollee",
+			password:       "P4ssw@rd123",
+			wantStatusCode: http.StatusOK,
+			assertFunc:     assertMFAStep(loginapi.MFAEnroll),
+		},
 		{
 			name:           "Success",
 			username:       "team1Member",
 			password:       "P4ssw@rd123",
 			wantStatusCode: http.StatusOK,
 			assertFunc: func(t *testing.T, resp *http.Response) {
+				// the user's bcrypt hash must be replaced with an argon2id
+				// one that still matches their password
+				user, err := usertbl.NewRetriever(test.DB()).Retrieve(
+					context.Background(), "team1Member",
+				)
+				assert.Nil(t.Fatal, err)
+				assert.True(t.Error,
+					strings.HasPrefix(string(user.Password), "$argon2id$"),
+				)
+				assert.Nil(t.Error,
+					password.NewHasher(password.DefaultParams).Compare(
+						user.Password, "P4ssw@rd123",
+					),
+				)
+
 				ckAuth := resp.Cookies()[0]
 
 				assert.True(t.Error, ckAuth.Secure)
@@ -77,7 +132,7 @@ func TestLoginAPI(t *testing.T) {
 				claims := jwt.MapClaims{}
 				if _, err := jwt.ParseWithClaims(
 					ckAuth.Value, &claims, func(token *jwt.Token) (any, error) {
-						return test.JWTKey, nil
+						return test.SigningKey.Key.Public(), nil
 					},
 				); err != nil {
 					t.Fatal(err)
@@ -108,6 +163,27 @@ func TestLoginAPI(t *testing.T) {
 				)
 			},
 		},
+		{
+			name:           "SuccessOtherCasing",
+			username:       "TEAM1MEMBER",
+			password:       "P4ssw@rd123",
+			wantStatusCode: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				// the token must be issued for the username in the casing
+				// that the user registered with
+				claims := jwt.MapClaims{}
+				if _, err := jwt.ParseWithClaims(
+					resp.Cookies()[0].Value,
+					&claims,
+					func(token *jwt.Token) (any, error) {
+						return test.SigningKey.Key.Public(), nil
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Error, claims["username"], "team1Member")
+			},
+		},
 	} {
 		t.Run(c.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
@@ -126,3 +202,113 @@ func TestLoginAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginMFAAPI(t *testing.T) {
+	sut := loginapi.NewMFAHandler(
+		cookie.NewMFADecoder(test.JWTKey),
+		attempttbl.NewRetriever(test.DB()),
+		attempttbl.NewIncrementer(test.DB()),
+		attempttbl.NewDeleter(test.DB()),
+		usertbl.NewRetriever(test.DB()),
+		mfa.NewTOTP(),
+		usertbl.NewUpdater(test.DB()),
+		cookie.NewAuthEncoder(test.SigningKey, 1*time.Hour),
+		cookie.NewRefreshEncoder(test.JWTKey, 24*time.Hour),
+		sessiontbl.NewInserter(test.DB()),
+		log.New(),
+		log.New(),
+	)
+
+	ckMFA, err := cookie.NewMFAEncoder(test.JWTKey, 5*time.Minute).Encode(
+		cookie.NewMFA("mfaVerifier"),
+	)
+	assert.Nil(t.Fatal, err)
+	code := totpCode(t, "JBSWY3DPEHPK3PXP", time.Now())
+
+	for _, c := range []struct {
+		name           string
+		ckMFA          *http.Cookie
+		code           string
+		wantStatusCode int
+	}{
+		{
+			name:           "NoMFAToken",
+			ckMFA:          nil,
+			code:           code,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "InvalidCode",
+			ckMFA:          &ckMFA,
+			code:           "wrongcode",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "SuccessTOTP",
+			ckMFA:          &ckMFA,
+			code:           code,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "TOTPReused",
+			ckMFA:          &ckMFA,
+			code:           code,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "SuccessRecoveryCode",
+			ckMFA:          &ckMFA,
+			code:           "ABCD-EFGH",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "RecoveryCodeReused",
+			ckMFA:          &ckMFA,
+			code:           "abcd-efgh",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost, "/", strings.NewReader(
+					`{"code": "`+c.code+`"}`,
+				),
+			)
+			if c.ckMFA != nil {
+				r.AddCookie(c.ckMFA)
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatusCode)
+			if c.wantStatusCode == http.StatusOK {
+				names := map[string]bool{}
+				for _, ck := range resp.Cookies() {
+					names[ck.Name] = true
+				}
+				assert.True(t.Error, names[cookie.AuthName])
+				assert.True(t.Error, names[cookie.RefreshName])
+			}
+		})
+	}
+}
+
+// assertMFAStep returns a function that asserts that an MFA token was set
+// instead of an auth token and the response body has the given MFA step.
+func assertMFAStep(wantStep string) func(*testing.T, *http.Response) {
+	return func(t *testing.T, resp *http.Response) {
+		cks := resp.Cookies()
+		assert.Equal(t.Fatal, len(cks), 1)
+		mfaToken, err := cookie.NewMFADecoder(test.JWTKey).Decode(*cks[0])
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, cks[0].Name, cookie.MFAName)
+
+		var body loginapi.PostResp
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, body.MFA, wantStep)
+		assert.True(t.Error, mfaToken.Username != "")
+	}
+}
@@ -0,0 +1,80 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/internal/usersvc/logoutapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestLogoutAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewChecker(test.DB()),
+	)
+	sut := logoutapi.NewPostHandler(
+		authDecoder,
+		cookie.NewRefreshDecoder(test.JWTKey),
+		revocationtbl.NewInserter(test.DB()),
+		sessiontbl.NewDeleter(test.DB()),
+		log.New(),
+	)
+
+	ckAuth, err := cookie.NewAuthEncoder(test.SigningKey, time.Hour).Encode(
+		cookie.NewAuth(
+			"team1Admin", role.Owner, "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		),
+	)
+	assert.Nil(t.Fatal, err)
+	familyID := "9d2f7c1e-5b3a-4e8d-a6f0-1c4b7e2d9a35"
+	ckRefresh, err := cookie.NewRefreshEncoder(test.JWTKey, time.Hour).Encode(
+		cookie.NewRefresh(familyID, "e3a9b4c2-7d1f-4a6e-8b5c-0f2d6e9a1b47"),
+	)
+	assert.Nil(t.Fatal, err)
+
+	// the auth token must be valid before logging out
+	_, err = authDecoder.Decode(ckAuth)
+	assert.Nil(t.Fatal, err)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.AddCookie(&ckAuth)
+	r.AddCookie(&ckRefresh)
+
+	sut.Handle(w, r, "")
+
+	resp := w.Result()
+	assert.Equal(t.Error, resp.StatusCode, http.StatusOK)
+
+	cks := resp.Cookies()
+	assert.Equal(t.Fatal, len(cks), 2)
+	for _, ck := range cks {
+		assert.True(t.Error, ck.MaxAge < 0)
+	}
+
+	_, err = authDecoder.Decode(ckAuth)
+	assert.ErrIs(t.Error, err, cookie.ErrRevoked)
+
+	out, err := test.DB().GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: &sessionTableName,
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: familyID},
+		},
+	})
+	assert.Nil(t.Fatal, err)
+	assert.Equal(t.Error, len(out.Item), 0)
+}
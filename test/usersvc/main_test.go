@@ -15,11 +15,51 @@ import (
 // tableName is the name of the user table used in the integration tests.
 var tableName = "goteam-test-user"
 
-// TestMain sets up the test table in DynamoDB and runs the tests.
+// sessionTableName is the name of the session table used in the integration
+// tests.
+var sessionTableName = "goteam-test-session"
+
+// revocationTableName is the name of the revocation table used in the
+// integration tests.
+var revocationTableName = "goteam-test-revocation"
+
+// resetTableName is the name of the password reset table used in the
+// integration tests.
+var resetTableName = "goteam-test-reset"
+
+// attemptTableName is the name of the login attempt table used in the
+// integration tests.
+var attemptTableName = "goteam-test-attempt"
+
+// identityTableName is the name of the identity table used in the integration
+// tests.
+var identityTableName = "goteam-test-identity"
+
+// tokenTableName is the name of the personal access token table used in the
+// integration tests.
+var tokenTableName = "goteam-test-token"
+
+// inviteTableName is the name of the invite table used in the integration
+// tests.
+var inviteTableName = "goteam-test-user-invite"
+
+// teamTableName is the name of the team table used in the integration tests.
+var teamTableName = "goteam-test-user-team"
+
+// membershipTableName is the name of the membership table used in the
+// integration tests.
+var membershipTableName = "goteam-test-user-membership"
+
+// TestMain sets up the test tables in DynamoDB and runs the tests.
 func TestMain(m *testing.M) {
 	fmt.Println("setting up user table")
 	tearDownTables, err := test.SetUpTestTable(
-		"USER_TABLE_NAME", tableName, writeReqs, "Username", "",
+		"USER_TABLE_NAME",
+		tableName,
+		writeReqs,
+		"Username",
+		"",
+		"UsernameLower",
 	)
 	defer tearDownTables()
 	if err != nil {
@@ -27,9 +67,434 @@ func TestMain(m *testing.M) {
 		return
 	}
 
+	fmt.Println("setting up session table")
+	tearDownSessionTable, err := test.SetUpTestTable(
+		"SESSION_TABLE_NAME",
+		sessionTableName,
+		sessionWriteReqs,
+		"ID",
+		"",
+		"Username",
+	)
+	defer tearDownSessionTable()
+	if err != nil {
+		log.Println("set up session table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up revocation table")
+	tearDownRevocationTable, err := test.SetUpTestTable(
+		"REVOCATION_TABLE_NAME",
+		revocationTableName,
+		revocationWriteReqs,
+		"Username",
+		"ID",
+	)
+	defer tearDownRevocationTable()
+	if err != nil {
+		log.Println("set up revocation table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up reset table")
+	tearDownResetTable, err := test.SetUpTestTable(
+		"RESET_TABLE_NAME", resetTableName, resetWriteReqs, "ID", "",
+	)
+	defer tearDownResetTable()
+	if err != nil {
+		log.Println("set up reset table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up attempt table")
+	tearDownAttemptTable, err := test.SetUpTestTable(
+		"ATTEMPT_TABLE_NAME", attemptTableName, attemptWriteReqs, "ID", "",
+	)
+	defer tearDownAttemptTable()
+	if err != nil {
+		log.Println("set up attempt table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up identity table")
+	tearDownIdentityTable, err := test.SetUpTestTable(
+		"IDENTITY_TABLE_NAME",
+		identityTableName,
+		identityWriteReqs,
+		"ID",
+		"",
+		"Username",
+	)
+	defer tearDownIdentityTable()
+	if err != nil {
+		log.Println("set up identity table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up token table")
+	tearDownTokenTable, err := test.SetUpTestTable(
+		"TOKEN_TABLE_NAME",
+		tokenTableName,
+		tokenWriteReqs,
+		"ID",
+		"",
+		"Username",
+	)
+	defer tearDownTokenTable()
+	if err != nil {
+		log.Println("set up token table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up invite table")
+	tearDownInviteTable, err := test.SetUpTestTable(
+		"INVITE_TABLE_NAME",
+		inviteTableName,
+		inviteWriteReqs,
+		"ID",
+		"",
+		"TeamID",
+	)
+	defer tearDownInviteTable()
+	if err != nil {
+		log.Println("set up invite table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up team table")
+	tearDownTeamTable, err := test.SetUpTestTable(
+		"TEAM_TABLE_NAME", teamTableName, teamWriteReqs, "ID", "",
+	)
+	defer tearDownTeamTable()
+	if err != nil {
+		log.Println("set up team table failed:", err)
+		return
+	}
+
+	fmt.Println("setting up membership table")
+	tearDownMembershipTable, err := test.SetUpTestTable(
+		"MEMBERSHIP_TABLE_NAME",
+		membershipTableName,
+		membershipWriteReqs,
+		"Username",
+		"TeamID",
+		"TeamID",
+	)
+	defer tearDownMembershipTable()
+	if err != nil {
+		log.Println("set up membership table failed:", err)
+		return
+	}
+
 	m.Run()
 }
 
+// inviteWriteReqs are the requests sent to the invite test table to initialise
+// it for tests.
+var inviteWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "0f5c1d8e-2b7a-4e6f-9c3d-8a1b4e7f2c5d",
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"CreatedBy": &types.AttributeValueMemberS{Value: "team1Admin"},
+		"Username":  &types.AttributeValueMemberS{Value: ""},
+		"MaxUses":   &types.AttributeValueMemberN{Value: "1"},
+		"Uses":      &types.AttributeValueMemberN{Value: "0"},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "7d2e9a4b-5c1f-4b8e-a6d3-3f9c2e1b8a7d",
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"CreatedBy": &types.AttributeValueMemberS{Value: "team1Admin"},
+		"Username":  &types.AttributeValueMemberS{Value: "alice321"},
+		"Role":      &types.AttributeValueMemberS{Value: "viewer"},
+		"MaxUses":   &types.AttributeValueMemberN{Value: "1"},
+		"Uses":      &types.AttributeValueMemberN{Value: "0"},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "e4b8c2a6-9d3f-4a1e-8b5c-6f2d9e3a1c7b",
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"CreatedBy": &types.AttributeValueMemberS{Value: "team1Admin"},
+		"Username":  &types.AttributeValueMemberS{Value: ""},
+		"MaxUses":   &types.AttributeValueMemberN{Value: "5"},
+		"Uses":      &types.AttributeValueMemberN{Value: "0"},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "1700000001"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "c3f1a7e5-8b2d-4e9a-b6c4-1d7f3a9e5b28",
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+		"CreatedBy": &types.AttributeValueMemberS{Value: "team2Admin"},
+		"Username":  &types.AttributeValueMemberS{Value: ""},
+		"MaxUses":   &types.AttributeValueMemberN{Value: "5"},
+		"Uses":      &types.AttributeValueMemberN{Value: "0"},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+}
+
+// membershipWriteReqs are the requests sent to the membership test table to
+// initialise it for tests. team1Member is also a member of team 2 and
+// team1Admin of a team that requires two-factor authentication so that
+// switching between teams can be tested. team4Member is only used for testing
+// account deletion.
+var membershipWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team1Admin"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "admin"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team1Admin"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "b8e2c6a1-4f3d-4b9e-a7c5-2d0f8e6b3a91",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "member"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team1Member"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "member"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team1Member"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "viewer"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team2Admin"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "owner"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team4Admin"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "owner"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "member"},
+	}}},
+}
+
+// identityWriteReqs are the requests sent to the identity test table to
+// initialise it for tests.
+var identityWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "https://idp.goteam.io#sub-4",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+	}}},
+}
+
+// tokenWriteReqs are the requests sent to the token test table to initialise
+// it for tests. The ID is the hash of "team4MemberToken".
+var tokenWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "045b42b4e588334a2dbc76d2008ac9eb" +
+				"f7c05dd06220e22a79f7769a2331e452",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+		"Name":     &types.AttributeValueMemberS{Value: "ci"},
+		"Scopes": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "tasks:read"},
+			},
+		},
+		"CreatedAt": &types.AttributeValueMemberN{Value: "1700000000"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+}
+
+// sessionWriteReqs are the requests sent to the session test table to
+// initialise it for tests.
+var sessionWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "0b6d4a52-3a1c-4a6e-9c5f-2c7f1f3b8a41",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team1Member"},
+		"TokenID": &types.AttributeValueMemberS{
+			Value: "5e0e3f7c-8d3b-4f0a-9a3e-6b8a2c1d4e5f",
+		},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "9d2f7c1e-5b3a-4e8d-a6f0-1c4b7e2d9a35",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team1Admin"},
+		"TokenID": &types.AttributeValueMemberS{
+			Value: "e3a9b4c2-7d1f-4a6e-8b5c-0f2d6e9a1b47",
+		},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "2c8e5a1f-6b4d-4f9a-b3e7-5d0c9f1a8e62",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team2Admin"},
+		"TokenID": &types.AttributeValueMemberS{
+			Value: "7f4b1d9e-3a6c-4e2b-9d8f-6a1c5e3b0d94",
+		},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "a5d3f8b2-1e7c-4b9d-8f6a-3c2e0b7d5f19",
+		},
+		"Username": &types.AttributeValueMemberS{Value: "team2Admin"},
+		"TokenID": &types.AttributeValueMemberS{
+			Value: "4e9c2a7b-8d5f-4c1e-a3b6-9f0d2e8c7a53",
+		},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+}
+
+// revocationWriteReqs are the requests sent to the revocation test table to
+// initialise it for tests.
+var revocationWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "team4Member"},
+		"ID":       &types.AttributeValueMemberS{Value: "all"},
+		"InvalidatedAt": &types.AttributeValueMemberN{
+			Value: "1700000000",
+		},
+	}}},
+}
+
+// resetWriteReqs are the requests sent to the reset test table to initialise it
+// for tests. The IDs are the hashes of "v4l1dR3s3tT0k3n" and
+// "3xp1r3dR3s3tT0k3n" respectively.
+var resetWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "fcc2bca87f9ecdb86affd9e500497b1b" +
+				"27f67f4573cd4d09b4af51acc3625960",
+		},
+		"Username":  &types.AttributeValueMemberS{Value: "team2Member"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "4102444800"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "245a3b237f3e6840064ceba7ccdd4a56" +
+				"2c61df92fd60717f07e8e4214ca016dd",
+		},
+		"Username":  &types.AttributeValueMemberS{Value: "team2Member"},
+		"ExpiresAt": &types.AttributeValueMemberN{Value: "1700000000"},
+	}}},
+}
+
+// attemptWriteReqs are the requests sent to the attempt test table to
+// initialise it for tests.
+var attemptWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID":           &types.AttributeValueMemberS{Value: "user#team4Admin"},
+		"Failures":     &types.AttributeValueMemberN{Value: "10"},
+		"LastFailedAt": &types.AttributeValueMemberN{Value: "4102444800"},
+		"ExpiresAt":    &types.AttributeValueMemberN{Value: "4102531200"},
+	}}},
+}
+
+// teamWriteReqs are the requests sent to the team test table to initialise it
+// for tests. Only the teams that the user service needs to know about are
+// added.
+var teamWriteReqs = []types.WriteRequest{
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "b8e2c6a1-4f3d-4b9e-a7c5-2d0f8e6b3a91",
+		},
+		"Members": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "mfaEnrollee"},
+			},
+		},
+		"RequireMFA": &types.AttributeValueMemberBOOL{Value: true},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+		},
+		"Name": &types.AttributeValueMemberS{Value: "Team 2"},
+		"Members": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "team2Admin"},
+				&types.AttributeValueMemberS{Value: "team1Member"},
+			},
+		},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{
+			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+		},
+		"Name":  &types.AttributeValueMemberS{Value: "Team 4"},
+		"Owner": &types.AttributeValueMemberS{Value: "team4Admin"},
+		"Members": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "team4Admin"},
+				&types.AttributeValueMemberS{Value: "team4Member"},
+			},
+		},
+		"Boards": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberM{
+					Value: map[string]types.AttributeValue{
+						"ID": &types.AttributeValueMemberS{
+							Value: "f0c5d521-ccb5-47cc-ba40-313ddb901165",
+						},
+						"Name": &types.AttributeValueMemberS{
+							Value: "Team 4 Board",
+						},
+						"Members": &types.AttributeValueMemberL{
+							Value: []types.AttributeValue{
+								&types.AttributeValueMemberS{
+									Value: "team4Admin",
+								},
+								&types.AttributeValueMemberS{
+									Value: "team4Member",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}},
+}
+
 // writeReqs are the requests sent to the test table to initialise it for tests.
 var writeReqs = []types.WriteRequest{
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
@@ -47,7 +512,8 @@ var writeReqs = []types.WriteRequest{
 		},
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
-		"Username": &types.AttributeValueMemberS{Value: "team1Member"},
+		"Username":      &types.AttributeValueMemberS{Value: "team1Member"},
+		"UsernameLower": &types.AttributeValueMemberS{Value: "team1member"},
 		"Password": &types.AttributeValueMemberB{
 			Value: []byte(
 				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
@@ -76,6 +542,10 @@ var writeReqs = []types.WriteRequest{
 	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"Username": &types.AttributeValueMemberS{Value: "team2Member"},
+		"Email": &types.AttributeValueMemberS{
+			Value: "team2Member@goteam.io",
+		},
+		"EmailVerified": &types.AttributeValueMemberBOOL{Value: true},
 		"Password": &types.AttributeValueMemberB{
 			Value: []byte(
 				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
@@ -88,6 +558,16 @@ var writeReqs = []types.WriteRequest{
 			Value: "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
 		},
 	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "oidcUnverified"},
+		"Email": &types.AttributeValueMemberS{
+			Value: "unverified@goteam.io",
+		},
+		"Role": &types.AttributeValueMemberS{Value: "owner"},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "5d7e3a2b-8c41-4f9a-b6e0-1a2c3d4e5f60",
+		},
+	}}},
 	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
 		"Username": &types.AttributeValueMemberS{Value: "team3Admin"},
 		"Password": &types.AttributeValueMemberB{
@@ -130,4 +610,71 @@ var writeReqs = []types.WriteRequest{
 			Value: "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
 		},
 	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "mfaVerifier"},
+		"Password": &types.AttributeValueMemberB{
+			Value: []byte(
+				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
+			),
+		},
+		"IsAdmin": &types.AttributeValueMemberBOOL{
+			Value: true,
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "5d1b3f4e-9a2c-4e7b-8f6d-0c3a9b2e1f47",
+		},
+		"MFASecret":  &types.AttributeValueMemberS{Value: "JBSWY3DPEHPK3PXP"},
+		"MFAEnabled": &types.AttributeValueMemberBOOL{Value: true},
+		// the hash of recovery code abcd-efgh
+		"RecoveryCodes": &types.AttributeValueMemberL{
+			Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{
+					Value: "9c56cc51b374c3ba189210d5b6d4bf57" +
+						"790d351c96c47c02190ecf1e430635ab",
+				},
+			},
+		},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "mfaEnrollee"},
+		"Password": &types.AttributeValueMemberB{
+			Value: []byte(
+				"$2a$11$kZfdRfTOjhfmel7J4WRG3eltzH9lavxp5qyrpFnzc9MIYLhZNCqTO",
+			),
+		},
+		"IsAdmin": &types.AttributeValueMemberBOOL{
+			Value: false,
+		},
+		"TeamID": &types.AttributeValueMemberS{
+			Value: "b8e2c6a1-4f3d-4b9e-a7c5-2d0f8e6b3a91",
+		},
+	}}},
+	// the items that claim the usernames of the users above in all casings
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#team1admin"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#team1member"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#team2admin"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#team2member"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#team3admin"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#team4admin"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#team4member"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#mfaverifier"},
+	}}},
+	{PutRequest: &types.PutRequest{Item: map[string]types.AttributeValue{
+		"Username": &types.AttributeValueMemberS{Value: "#claim#mfaenrollee"},
+	}}},
 }
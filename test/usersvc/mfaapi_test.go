@@ -0,0 +1,176 @@
+//go:build itest
+
+package test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/internal/usersvc/mfaapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mfa"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestMFAAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
+	mfaDecoder := cookie.NewMFADecoder(test.JWTKey)
+	post := mfaapi.NewPostHandler(
+		authDecoder,
+		mfaDecoder,
+		usertbl.NewRetriever(test.DB()),
+		mfa.NewTOTP(),
+		usertbl.NewUpdater(test.DB()),
+		log.New(),
+	)
+	patch := mfaapi.NewPatchHandler(
+		authDecoder,
+		mfaDecoder,
+		usertbl.NewRetriever(test.DB()),
+		mfa.NewTOTP(),
+		usertbl.NewUpdater(test.DB()),
+		cookie.NewAuthEncoder(test.SigningKey, 15*time.Minute),
+		cookie.NewRefreshEncoder(test.JWTKey, 24*time.Hour),
+		sessiontbl.NewInserter(test.DB()),
+		log.New(),
+	)
+
+	// mfaEnrollee's team requires two-factor authentication, so they enroll
+	// using the MFA token issued to them on logging in
+	ckMFA, err := cookie.NewMFAEncoder(test.JWTKey, 5*time.Minute).Encode(
+		cookie.NewMFA("mfaEnrollee"),
+	)
+	assert.Nil(t.Fatal, err)
+
+	// serve sends a request with the given method and body to the MFA route
+	// and returns the response.
+	serve := func(method, body string, cks ...*http.Cookie) *http.Response {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "/user/mfa", strings.NewReader(body))
+		for _, ck := range cks {
+			r.AddCookie(ck)
+		}
+		if method == http.MethodPost {
+			post.Handle(w, r, "")
+		} else {
+			patch.Handle(w, r, "")
+		}
+		return w.Result()
+	}
+
+	var secret string
+	for _, c := range []struct {
+		name string
+		run  func(*testing.T)
+	}{
+		{
+			name: "NoAuth",
+			run: func(t *testing.T) {
+				resp := serve(http.MethodPost, "")
+
+				assert.Equal(t.Error, resp.StatusCode, http.StatusUnauthorized)
+				assert.OnRespErr("Auth token not found.")(t, resp, nil)
+			},
+		},
+		{
+			name: "POSTOK",
+			run: func(t *testing.T) {
+				resp := serve(http.MethodPost, "", &ckMFA)
+
+				assert.Equal(t.Fatal, resp.StatusCode, http.StatusOK)
+				var body mfaapi.PostResp
+				err := json.NewDecoder(resp.Body).Decode(&body)
+				assert.Nil(t.Fatal, err)
+				assert.True(t.Fatal, body.Secret != "")
+				assert.True(t.Error, strings.HasPrefix(
+					body.URI, "otpauth://totp/GoTeam%21:mfaEnrollee?",
+				))
+				secret = body.Secret
+			},
+		},
+		{
+			name: "PATCHInvalidCode",
+			run: func(t *testing.T) {
+				resp := serve(http.MethodPatch, `{"code": "abcdef"}`, &ckMFA)
+
+				assert.Equal(t.Error, resp.StatusCode, http.StatusBadRequest)
+				assert.OnRespErr("Invalid code.")(t, resp, nil)
+			},
+		},
+		{
+			name: "PATCHOK",
+			run: func(t *testing.T) {
+				resp := serve(
+					http.MethodPatch,
+					`{"code": "`+totpCode(t, secret, time.Now())+`"}`,
+					&ckMFA,
+				)
+
+				assert.Equal(t.Fatal, resp.StatusCode, http.StatusOK)
+				var body mfaapi.PatchResp
+				err := json.NewDecoder(resp.Body).Decode(&body)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, len(body.RecoveryCodes), 10)
+
+				// the user must have been logged in
+				var ckAuth *http.Cookie
+				for _, ck := range resp.Cookies() {
+					if ck.Name == cookie.AuthName {
+						ckAuth = ck
+					}
+				}
+				assert.True(t.Fatal, ckAuth != nil)
+				auth, err := authDecoder.Decode(*ckAuth)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, auth.Username, "mfaEnrollee")
+			},
+		},
+		{
+			name: "POSTAlreadyEnabled",
+			run: func(t *testing.T) {
+				resp := serve(http.MethodPost, "", &ckMFA)
+
+				assert.Equal(t.Error, resp.StatusCode, http.StatusConflict)
+				assert.OnRespErr(
+					"Two-factor authentication is already enabled.",
+				)(t, resp, nil)
+			},
+		},
+	} {
+		t.Run(c.name, c.run)
+	}
+}
+
+// totpCode returns the TOTP code for the given base32-encoded secret at the
+// given time, the same way an authenticator app would.
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).
+		DecodeString(secret)
+	assert.Nil(t.Fatal, err)
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", bin%1_000_000)
+}
@@ -0,0 +1,227 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"github.com/kxplxn/goteam/internal/usersvc/oidcapi"
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/oidc"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestOIDCAPI(t *testing.T) {
+	idp := test.NewIdP("goteam")
+	defer idp.Close()
+
+	clientURL := "http://localhost:3000"
+	callbackURL := "http://localhost:8080/oidc/callback"
+	authenticator := oidc.NewProvider(idp.URL, "goteam", "", callbackURL)
+	login := oidcapi.NewLoginHandler(
+		authenticator,
+		cookie.NewOIDCEncoder(test.JWTKey, 10*time.Minute),
+		log.New(),
+	)
+	link := oidcapi.NewLinkHandler(
+		cookie.NewAuthDecoder(test.KeySet, revocationtbl.NewMemory()),
+		authenticator,
+		cookie.NewOIDCEncoder(test.JWTKey, 10*time.Minute),
+		log.New(),
+	)
+	callback := oidcapi.NewCallbackHandler(
+		cookie.NewOIDCDecoder(test.JWTKey),
+		authenticator,
+		identitytbl.NewRetriever(test.DB()),
+		identitytbl.NewInserter(test.DB()),
+		usertbl.NewRetrieverAnyCase(test.DB()),
+		registerapi.NewUsernameValidator(),
+		usertbl.NewInserter(test.DB()),
+		membershiptbl.NewInserter(test.DB()),
+		teamtbl.NewRetriever(test.DB()),
+		cookie.NewMFAEncoder(test.JWTKey, 5*time.Minute),
+		cookie.NewAuthEncoder(test.SigningKey, 15*time.Minute),
+		cookie.NewRefreshEncoder(test.JWTKey, 24*time.Hour),
+		sessiontbl.NewInserter(test.DB()),
+		clientURL,
+		log.New(),
+	)
+
+	// logIn goes through the whole OIDC login as the given IdP user and returns
+	// the callback response. tamper is applied to the callback URL before it is
+	// requested. If authToken is set, the login is started through the link
+	// route with it instead so that the identity is linked to its user.
+	logIn := func(
+		t *testing.T, authToken, subject, email, username string,
+		tamper func(string) string,
+	) *http.Response {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if authToken == "" {
+			login.Handle(w, r, "")
+		} else {
+			test.AddAuthCookie(authToken)(r)
+			link.Handle(w, r, "")
+		}
+		resp := w.Result()
+		assert.Equal(t.Fatal, resp.StatusCode, http.StatusFound)
+		ckOIDC := resp.Cookies()[0]
+
+		redirectURL, err := idp.Login(
+			resp.Header.Get("Location"), subject, email, username,
+		)
+		assert.Nil(t.Fatal, err)
+
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodGet, tamper(redirectURL), nil)
+		r.AddCookie(ckOIDC)
+		callback.Handle(w, r, "")
+		return w.Result()
+	}
+	noTamper := func(u string) string { return u }
+
+	// authUsername returns the username in the auth token set on the response.
+	authUsername := func(t *testing.T, resp *http.Response) string {
+		for _, ck := range resp.Cookies() {
+			if ck.Name != cookie.AuthName {
+				continue
+			}
+			claims := jwt.MapClaims{}
+			_, err := jwt.ParseWithClaims(
+				ck.Value, &claims, func(*jwt.Token) (any, error) {
+					return test.SigningKey.Key.Public(), nil
+				},
+			)
+			assert.Nil(t.Fatal, err)
+			return claims["username"].(string)
+		}
+		t.Fatal("auth token not found")
+		return ""
+	}
+
+	t.Run("StateMismatch", func(t *testing.T) {
+		resp := logIn(t, "", "sub-1", "oidc1@goteam.io", "oidcUser1",
+			func(u string) string {
+				return strings.Replace(u, "state=", "state=x", 1)
+			},
+		)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"),
+			clientURL+"/login?error="+oidcapi.ErrCodeInvalid)
+	})
+
+	t.Run("NewUser", func(t *testing.T) {
+		resp := logIn(t, "", "sub-1", "oidc1@goteam.io", "oidc.User1", noTamper)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"), clientURL)
+		assert.Equal(t.Error, authUsername(t, resp), "oidcUser1")
+
+		user, err := usertbl.NewRetriever(test.DB()).Retrieve(
+			context.Background(), "oidcUser1",
+		)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, user.Email, "oidc1@goteam.io")
+		assert.Equal(t.Error, user.Role, role.Owner)
+		_, err = uuid.Parse(user.TeamID)
+		assert.Nil(t.Error, err)
+		assert.Equal(t.Error, len(user.Password), 0)
+	})
+
+	t.Run("ReturningUser", func(t *testing.T) {
+		// the username at the IdP changing doesn't matter once it's linked
+		resp := logIn(t, "", "sub-1", "oidc1@goteam.io", "renamed", noTamper)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"), clientURL)
+		assert.Equal(t.Error, authUsername(t, resp), "oidcUser1")
+	})
+
+	t.Run("LinkExistingUser", func(t *testing.T) {
+		resp := logIn(
+			t, "", "sub-2", "team2Member@goteam.io", "team2Member", noTamper,
+		)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"), clientURL)
+		assert.Equal(t.Error, authUsername(t, resp), "team2Member")
+
+		identity, err := identitytbl.NewRetriever(test.DB()).Retrieve(
+			context.Background(), identitytbl.ID(idp.URL, "sub-2"),
+		)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, identity.Username, "team2Member")
+	})
+
+	t.Run("UsernameTaken", func(t *testing.T) {
+		resp := logIn(t, "", "sub-3", "bob@goteam.io", "team1Admin", noTamper)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"),
+			clientURL+"/login?error="+oidcapi.ErrCodeTaken)
+	})
+
+	t.Run("EmailUnverified", func(t *testing.T) {
+		// the user entered the email themselves, so anyone could have done so
+		resp := logIn(
+			t, "", "sub-5", "unverified@goteam.io", "oidcUnverified", noTamper,
+		)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"),
+			clientURL+"/login?error="+oidcapi.ErrCodeTaken)
+	})
+
+	t.Run("LinkLoggedIn", func(t *testing.T) {
+		resp := logIn(t, test.T1MemberToken, "sub-3", "bob@goteam.io",
+			"team1Admin", noTamper,
+		)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"), clientURL)
+		assert.Equal(t.Error, authUsername(t, resp), "team1Member")
+
+		identity, err := identitytbl.NewRetriever(test.DB()).Retrieve(
+			context.Background(), identitytbl.ID(idp.URL, "sub-3"),
+		)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, identity.Username, "team1Member")
+	})
+
+	t.Run("LinkLinkedToOther", func(t *testing.T) {
+		resp := logIn(t, test.T1MemberToken, "sub-1", "oidc1@goteam.io",
+			"oidcUser1", noTamper,
+		)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"),
+			clientURL+"/login?error="+oidcapi.ErrCodeLinked)
+	})
+
+	t.Run("UsernameInvalid", func(t *testing.T) {
+		resp := logIn(t, "", "sub-4", "1@goteam.io", "", noTamper)
+
+		assert.Equal(t.Error, resp.StatusCode, http.StatusFound)
+		assert.Equal(t.Error, resp.Header.Get("Location"),
+			clientURL+"/login?error="+oidcapi.ErrCodeUsername)
+	})
+}
@@ -0,0 +1,141 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/internal/usersvc/passwordapi"
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestPasswordAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewChecker(test.DB()),
+	)
+	sut := passwordapi.NewPatchHandler(
+		authDecoder,
+		registerapi.NewPasswordValidator(registerapi.StrictPwdPolicy, nil),
+		usertbl.NewRetriever(test.DB()),
+		password.NewHasher(password.DefaultParams),
+		password.NewHasher(password.DefaultParams),
+		usertbl.NewUpdater(test.DB()),
+		revocationtbl.NewInserter(test.DB()),
+		sessiontbl.NewDeleterByUser(test.DB()),
+		cookie.NewAuthEncoder(test.SigningKey, 15*time.Minute),
+		cookie.NewRefreshEncoder(test.JWTKey, 24*time.Hour),
+		sessiontbl.NewInserter(test.DB()),
+		log.New(),
+	)
+
+	for _, c := range []struct {
+		name       string
+		authFunc   func(*http.Request)
+		reqBody    string
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NoAuth",
+			authFunc:   func(*http.Request) {},
+			reqBody:    `{}`,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:       "InvalidAuth",
+			authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+			reqBody:    `{}`,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:       "CurrentPasswordEmpty",
+			authFunc:   test.AddAuthCookie(test.T3AdminToken),
+			reqBody:    `{"currentPassword": "", "newPassword": "N3wP4ss!d"}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("Current password cannot be empty."),
+		},
+		{
+			name:     "NewPasswordInvalid",
+			authFunc: test.AddAuthCookie(test.T3AdminToken),
+			reqBody: `{
+				"currentPassword": "P4ssw@rd123", "newPassword": "short"
+			}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: func(*testing.T, *http.Response, []any) {},
+		},
+		{
+			name:     "WrongPassword",
+			authFunc: test.AddAuthCookie(test.T3AdminToken),
+			reqBody: `{
+				"currentPassword": "P4ssw@rd321", "newPassword": "N3wP4ss!d"
+			}`,
+			wantStatus: http.StatusBadRequest,
+			assertFunc: assert.OnRespErr("Current password is incorrect."),
+		},
+		{
+			name:     "OK",
+			authFunc: test.AddAuthCookie(test.T3AdminToken),
+			reqBody: `{
+				"currentPassword": "P4ssw@rd123", "newPassword": "N3wP4ss!d"
+			}`,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				user, err := usertbl.NewRetriever(test.DB()).Retrieve(
+					context.Background(), "team3Admin",
+				)
+				assert.Nil(t.Fatal, err)
+				err = password.NewHasher(password.DefaultParams).Compare(
+					user.Password, "N3wP4ss!d",
+				)
+				assert.Nil(t.Error, err)
+
+				_, err = authDecoder.Decode(http.Cookie{
+					Name: cookie.AuthName, Value: test.T3AdminToken,
+				})
+				assert.ErrIs(t.Error, err, cookie.ErrRevoked)
+
+				var ckAuth *http.Cookie
+				for _, ck := range resp.Cookies() {
+					if ck.Name == cookie.AuthName {
+						ckAuth = ck
+					}
+				}
+				if ckAuth == nil {
+					t.Fatal("auth cookie was not set")
+				}
+				auth, err := authDecoder.Decode(*ckAuth)
+				assert.Nil(t.Error, err)
+				assert.Equal(t.Error, auth.Username, "team3Admin")
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch, "/", strings.NewReader(c.reqBody),
+			)
+			c.authFunc(r)
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, []any{})
+		})
+	}
+}
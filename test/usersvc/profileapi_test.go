@@ -0,0 +1,350 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kxplxn/goteam/internal/usersvc/profileapi"
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db"
+	"github.com/kxplxn/goteam/pkg/db/attempttbl"
+	"github.com/kxplxn/goteam/pkg/db/identitytbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestProfileAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewChecker(test.DB()),
+	)
+
+	t.Run("GET", func(t *testing.T) {
+		sut := profileapi.NewGetHandler(
+			authDecoder, usertbl.NewRetriever(test.DB()), log.New(),
+		)
+
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "InvalidAuth",
+				authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T1AdminToken),
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody profileapi.GetResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, respBody.Profile, profileapi.Profile{
+						Username:    "team1Admin",
+						DisplayName: "",
+						Email:       "",
+						AvatarURL:   "",
+						TimeZone:    "",
+						Locale:      "",
+					})
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				c.authFunc(r)
+
+				sut.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("PATCH", func(t *testing.T) {
+		sut := profileapi.NewPatchHandler(
+			authDecoder,
+			profileapi.NewProfileValidator(
+				profileapi.NewDisplayNameValidator(),
+				registerapi.NewEmailValidator(),
+				profileapi.NewAvatarURLValidator(),
+				profileapi.NewTimeZoneValidator(),
+				profileapi.NewLocaleValidator(),
+			),
+			usertbl.NewRetriever(test.DB()),
+			usertbl.NewUpdater(test.DB()),
+			log.New(),
+		)
+
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				reqBody:    `{}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "InvalidAuth",
+				authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+				reqBody:    `{}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:       "InvalidProfile",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				reqBody:    `{"avatarURL": "http://x.io/a.png", "locale": "e"}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody profileapi.PatchResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+					assert.AllEqual(t.Error,
+						respBody.ValidationErrs.AvatarURL,
+						[]string{"Avatar URL must be a valid HTTPS URL."},
+					)
+					assert.AllEqual(t.Error,
+						respBody.ValidationErrs.Locale,
+						[]string{"Locale is not a valid language tag."},
+					)
+				},
+			},
+			{
+				name:     "OK",
+				authFunc: test.AddAuthCookie(test.T4AdminToken),
+				reqBody: `{
+					"displayName": "Team 4 Admin",
+					"email": "team4Admin@goteam.io",
+					"timeZone": "Europe/London",
+					"locale": "en-GB"
+				}`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					wantProfile := profileapi.Profile{
+						Username:    "team4Admin",
+						DisplayName: "Team 4 Admin",
+						Email:       "team4Admin@goteam.io",
+						AvatarURL:   "",
+						TimeZone:    "Europe/London",
+						Locale:      "en-GB",
+					}
+
+					var respBody profileapi.PatchResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, respBody.Profile, wantProfile)
+
+					// the rest of the user must be left as is
+					user, err := usertbl.NewRetriever(test.DB()).Retrieve(
+						context.Background(), "team4Admin",
+					)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error,
+						profileapi.NewProfile(user), wantProfile,
+					)
+					assert.Equal(t.Error,
+						user.TeamID, "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+					)
+					assert.True(t.Error, len(user.Password) > 0)
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPatch, "/", strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+
+				sut.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("DELETE", func(t *testing.T) {
+		sut := profileapi.NewDeleteHandler(
+			authDecoder,
+			usertbl.NewRetriever(test.DB()),
+			password.NewHasher(password.DefaultParams),
+			membershiptbl.NewRetrieverByUser(test.DB()),
+			teamtbl.NewRetriever(test.DB()),
+			teamtbl.NewUpdater(test.DB()),
+			membershiptbl.NewDeleter(test.DB()),
+			tokentbl.NewRetrieverByUser(test.DB()),
+			tokentbl.NewDeleter(test.DB()),
+			identitytbl.NewRetrieverByUser(test.DB()),
+			identitytbl.NewDeleter(test.DB()),
+			attempttbl.NewDeleter(test.DB()),
+			sessiontbl.NewDeleterByUser(test.DB()),
+			revocationtbl.NewInserter(test.DB()),
+			usertbl.NewDeleter(test.DB()),
+			log.New(),
+		)
+
+		// team4Member's seeded auth tokens are revoked, so a new one is
+		// signed for them
+		ckAuth, err := cookie.NewAuthEncoder(test.SigningKey, time.Hour).Encode(
+			cookie.NewAuth(
+				"team4Member",
+				role.Member,
+				"3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+			),
+		)
+		assert.Nil(t.Fatal, err)
+
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				reqBody:    `{"password": "P4ssw@rd123"}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "InvalidAuth",
+				authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+				reqBody:    `{"password": "P4ssw@rd123"}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:       "PasswordIncorrect",
+				authFunc:   test.AddAuthCookie(ckAuth.Value),
+				reqBody:    `{"password": "P4ssw@rd321"}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Password is incorrect."),
+			},
+			{
+				name:       "TeamOwner",
+				authFunc:   test.AddAuthCookie(test.T4AdminToken),
+				reqBody:    `{"password": "P4ssw@rd123"}`,
+				wantStatus: http.StatusConflict,
+				assertFunc: assert.OnRespErr(
+					"You own the team \"Team 4\". Transfer its ownership " +
+						"or delete it before deleting your account.",
+				),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(ckAuth.Value),
+				reqBody:    `{"password": "P4ssw@rd123"}`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody profileapi.DeleteResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+					report := respBody.Report
+					assert.True(t.Fatal, report != nil)
+					assert.Equal(t.Error, report.Username, "team4Member")
+					assert.AllEqual(t.Error,
+						report.Teams,
+						[]string{"3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc"},
+					)
+					assert.AllEqual(t.Error,
+						report.Boards,
+						[]string{"f0c5d521-ccb5-47cc-ba40-313ddb901165"},
+					)
+					assert.AllEqual(t.Error, report.Tokens, []string{"ci"})
+					assert.AllEqual(t.Error,
+						report.Identities,
+						[]string{"https://idp.goteam.io#sub-4"},
+					)
+
+					// the user must be gone along with their username claim,
+					// memberships, and identities
+					ctx := context.Background()
+					_, err = usertbl.NewRetriever(test.DB()).Retrieve(
+						ctx, "team4Member",
+					)
+					assert.ErrIs(t.Error, err, db.ErrNoItem)
+					_, err = usertbl.NewRetriever(test.DB()).Retrieve(
+						ctx, usertbl.ClaimUsername("team4Member"),
+					)
+					assert.ErrIs(t.Error, err, db.ErrNoItem)
+					memberships, err := membershiptbl.NewRetrieverByUser(
+						test.DB(),
+					).Retrieve(ctx, "team4Member")
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, len(memberships), 0)
+					_, err = identitytbl.NewRetriever(test.DB()).Retrieve(
+						ctx, "https://idp.goteam.io#sub-4",
+					)
+					assert.ErrIs(t.Error, err, db.ErrNoItem)
+
+					// the user must be removed from the team and its boards
+					team, err := teamtbl.NewRetriever(test.DB()).Retrieve(
+						ctx, "3c3ec4ea-a850-4fc5-aab0-24e9e7223bbc",
+					)
+					assert.Nil(t.Fatal, err)
+					assert.AllEqual(t.Error,
+						team.Members, []string{"team4Admin"},
+					)
+					assert.AllEqual(t.Error,
+						team.Boards[0].Members, []string{"team4Admin"},
+					)
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodDelete, "/", strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+
+				sut.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
@@ -0,0 +1,151 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/internal/usersvc/refreshapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestRefreshAPI(t *testing.T) {
+	refreshEncoder := cookie.NewRefreshEncoder(test.JWTKey, 24*time.Hour)
+	refreshDecoder := cookie.NewRefreshDecoder(test.JWTKey)
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewMemory(),
+	)
+	sut := refreshapi.NewPostHandler(
+		refreshDecoder,
+		sessiontbl.NewRetriever(test.DB()),
+		usertbl.NewRetriever(test.DB()),
+		refreshEncoder,
+		cookie.NewAuthEncoder(test.SigningKey, 15*time.Minute),
+		sessiontbl.NewUpdater(test.DB()),
+		sessiontbl.NewDeleter(test.DB()),
+		log.New(),
+	)
+
+	familyID := "0b6d4a52-3a1c-4a6e-9c5f-2c7f1f3b8a41"
+	ckRefresh, err := refreshEncoder.Encode(cookie.NewRefresh(
+		familyID, "5e0e3f7c-8d3b-4f0a-9a3e-6b8a2c1d4e5f",
+	))
+	assert.Nil(t.Fatal, err)
+	ckNoSession, err := refreshEncoder.Encode(cookie.NewRefresh(
+		"6f1a2b3c-4d5e-4f60-8a7b-9c0d1e2f3a4b",
+		"5e0e3f7c-8d3b-4f0a-9a3e-6b8a2c1d4e5f",
+	))
+	assert.Nil(t.Fatal, err)
+
+	// getSession retrieves the session used in the tests from the session
+	// table.
+	getSession := func(t *testing.T) map[string]types.AttributeValue {
+		out, err := test.DB().GetItem(
+			context.Background(), &dynamodb.GetItemInput{
+				TableName: &sessionTableName,
+				Key: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: familyID},
+				},
+			},
+		)
+		assert.Nil(t.Fatal, err)
+		return out.Item
+	}
+
+	for _, c := range []struct {
+		name       string
+		ckRefresh  *http.Cookie
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NoRefresh",
+			ckRefresh:  nil,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Refresh token not found."),
+		},
+		{
+			name: "InvalidRefresh",
+			ckRefresh: &http.Cookie{
+				Name: cookie.RefreshName, Value: "asdkfjahsaksdfjhas",
+			},
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Invalid refresh token."),
+		},
+		{
+			name:       "SessionNotFound",
+			ckRefresh:  &ckNoSession,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Session not found."),
+		},
+		{
+			name:       "OK",
+			ckRefresh:  &ckRefresh,
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				cks := resp.Cookies()
+				assert.Equal(t.Fatal, len(cks), 2)
+				assert.Equal(t.Error, cks[0].Name, cookie.AuthName)
+				assert.Equal(t.Error, cks[1].Name, cookie.RefreshName)
+				assert.True(t.Error, cks[1].HttpOnly)
+
+				auth, err := authDecoder.Decode(*cks[0])
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, auth.Username, "team1Member")
+				assert.Equal(t.Error,
+					auth.TeamID, "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+				)
+
+				ref, err := refreshDecoder.Decode(*cks[1])
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, ref.FamilyID, familyID)
+
+				var session sessiontbl.Session
+				err = attributevalue.UnmarshalMap(getSession(t), &session)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, session.TokenID, ref.TokenID)
+			},
+		},
+		{
+			name:       "Reused",
+			ckRefresh:  &ckRefresh,
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+				assert.OnRespErr(
+					"Refresh token has already been used. Please log in "+
+						"again.",
+				)(t, resp, []any{})
+
+				assert.Equal(t.Error, len(getSession(t)), 0)
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if c.ckRefresh != nil {
+				r.AddCookie(c.ckRefresh)
+			}
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, []any{})
+		})
+	}
+}
@@ -15,13 +15,20 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/golang-jwt/jwt/v4"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
 
 	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
 	"github.com/kxplxn/goteam/pkg/assert"
 	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
 	"github.com/kxplxn/goteam/pkg/db/usertbl"
 	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/password"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
 	"github.com/kxplxn/goteam/test"
 )
 
@@ -29,12 +36,23 @@ func TestRegisterAPI(t *testing.T) {
 	sut := registerapi.NewPostHandler(
 		registerapi.NewUserValidator(
 			registerapi.NewUsernameValidator(),
-			registerapi.NewPasswordValidator(),
+			registerapi.NewEmailValidator(),
+			registerapi.NewPasswordValidator(
+				registerapi.StrictPwdPolicy,
+				password.Corpus{password.NewDigest("Br34ched!pwd"): {}},
+			),
 		),
-		cookie.NewInviteDecoder(test.JWTKey),
-		registerapi.NewPasswordHasher(),
+		invitetbl.NewConsumer(test.DB()),
+		invitetbl.NewReleaser(test.DB()),
+		teamtbl.NewRetriever(test.DB()),
+		membershiptbl.NewRetrieverByTeam(test.DB()),
+		quota.DefaultLimits,
+		password.NewHasher(password.DefaultParams),
 		usertbl.NewInserter(test.DB()),
-		cookie.NewAuthEncoder(test.JWTKey, 1*time.Hour),
+		membershiptbl.NewInserter(test.DB()),
+		cookie.NewAuthEncoder(test.SigningKey, 1*time.Hour),
+		cookie.NewRefreshEncoder(test.JWTKey, 24*time.Hour),
+		sessiontbl.NewInserter(test.DB()),
 		log.New(),
 	)
 
@@ -135,6 +153,20 @@ func TestRegisterAPI(t *testing.T) {
 				},
 			),
 		},
+		{
+			name:           "PwdBreached",
+			username:       "bob321",
+			password:       "Br34ched!pwd",
+			inviteToken:    "",
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc: assertOnValidationErrs(
+				[]string{},
+				[]string{
+					"Password has appeared in a data breach. Please choose " +
+						"a different password.",
+				},
+			),
+		},
 		{
 			name:           "UsnTaken",
 			username:       "team1Member",
@@ -145,6 +177,16 @@ func TestRegisterAPI(t *testing.T) {
 				[]string{"Username is already taken."}, []string{},
 			),
 		},
+		{
+			name:           "UsnTakenOtherCasing",
+			username:       "TEAM1MEMBER",
+			password:       "Myp4ssw0rd!",
+			inviteToken:    "",
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc: assertOnValidationErrs(
+				[]string{"Username is already taken."}, []string{},
+			),
+		},
 		{
 			name:           "InviteInvalid",
 			username:       "bob321",
@@ -153,6 +195,106 @@ func TestRegisterAPI(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			assertFunc:     assertOnResErr("Invalid invite token."),
 		},
+		{
+			name:           "InviteExpired",
+			username:       "bob321",
+			password:       "Myp4ssw0rd!",
+			inviteToken:    "e4b8c2a6-9d3f-4a1e-8b5c-6f2d9e3a1c7b",
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc:     assertOnResErr("Invalid invite token."),
+		},
+		{
+			name:           "InviteForOtherUser",
+			username:       "bob321",
+			password:       "Myp4ssw0rd!",
+			inviteToken:    "7d2e9a4b-5c1f-4b8e-a6d3-3f9c2e1b8a7d",
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc:     assertOnResErr("Invalid invite token."),
+		},
+		{
+			name:           "InviteUsnTaken",
+			username:       "team1Member",
+			password:       "Myp4ssw0rd!",
+			inviteToken:    "0f5c1d8e-2b7a-4e6f-9c3d-8a1b4e7f2c5d",
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc: func(t *testing.T, resp *http.Response, _ string) {
+				// the invite's use must have been given back so that it
+				// can still be used
+				out, err := test.DB().GetItem(
+					context.Background(), &dynamodb.GetItemInput{
+						TableName: &inviteTableName,
+						Key: map[string]types.AttributeValue{
+							"ID": &types.AttributeValueMemberS{
+								Value: "0f5c1d8e-2b7a-4e6f-9c3d-" +
+									"8a1b4e7f2c5d",
+							},
+						},
+					},
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var invite invitetbl.Invite
+				if err = attributevalue.UnmarshalMap(
+					out.Item, &invite,
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Error, invite.Uses, 0)
+			},
+		},
+		{
+			name:           "OKInvite",
+			username:       "carol321",
+			password:       "Myp4ssw0rd!",
+			inviteToken:    "0f5c1d8e-2b7a-4e6f-9c3d-8a1b4e7f2c5d",
+			wantStatusCode: http.StatusOK,
+			assertFunc: func(t *testing.T, resp *http.Response, _ string) {
+				// the user must be put into the invite's team as a member
+				claims := jwt.MapClaims{}
+				if _, err := jwt.ParseWithClaims(
+					resp.Cookies()[0].Value,
+					&claims,
+					func(token *jwt.Token) (any, error) {
+						return test.SigningKey.Key.Public(), nil
+					},
+				); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t.Error, claims["username"].(string), "carol321")
+				assert.Equal(t.Error, claims["isAdmin"].(bool), false)
+				assert.Equal(t.Error,
+					claims["teamID"].(string),
+					"afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+				)
+			},
+		},
+		{
+			name:           "InviteUsedUp",
+			username:       "dave321",
+			password:       "Myp4ssw0rd!",
+			inviteToken:    "0f5c1d8e-2b7a-4e6f-9c3d-8a1b4e7f2c5d",
+			wantStatusCode: http.StatusBadRequest,
+			assertFunc:     assertOnResErr("Invalid invite token."),
+		},
+		{
+			name:           "OKTargetedInvite",
+			username:       "alice321",
+			password:       "Myp4ssw0rd!",
+			inviteToken:    "7d2e9a4b-5c1f-4b8e-a6d3-3f9c2e1b8a7d",
+			wantStatusCode: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ string) {
+				// the user must be given the role that the invite grants
+				user, err := usertbl.NewRetriever(test.DB()).Retrieve(
+					context.Background(), "alice321",
+				)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, user.Role, role.Viewer)
+				assert.Equal(t.Error,
+					user.TeamID, "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+				)
+			},
+		},
 		{
 			name:           "OK",
 			username:       "bob321",
@@ -179,8 +321,8 @@ func TestRegisterAPI(t *testing.T) {
 				if err != nil {
 					t.Fatal(err)
 				}
-				if err = bcrypt.CompareHashAndPassword(
-					user.Password, []byte("Myp4ssw0rd!"),
+				if err = password.NewHasher(password.DefaultParams).Compare(
+					user.Password, "Myp4ssw0rd!",
 				); err != nil {
 					t.Error(err)
 				}
@@ -193,7 +335,7 @@ func TestRegisterAPI(t *testing.T) {
 				claims := jwt.MapClaims{}
 				if _, err = jwt.ParseWithClaims(
 					cookie.Value, &claims, func(token *jwt.Token) (any, error) {
-						return test.JWTKey, nil
+						return test.SigningKey.Key.Public(), nil
 					},
 				); err != nil {
 					t.Fatal(err)
@@ -203,7 +345,8 @@ func TestRegisterAPI(t *testing.T) {
 				// no invite token was sent - therefore user must be put as
 				// admin and given a random guid as team ID
 				assert.Equal(t.Error, claims["isAdmin"].(bool), true)
-				assert.Equal(t.Error, claims["teamID"].(string), "bob321")
+				_, err = uuid.Parse(claims["teamID"].(string))
+				assert.Nil(t.Error, err)
 
 				exp := claims["exp"].(float64)
 				if exp > float64(time.Now().Add(1*time.Hour).Unix()) {
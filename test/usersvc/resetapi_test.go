@@ -0,0 +1,160 @@
+//go:build itest
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/internal/usersvc/registerapi"
+	"github.com/kxplxn/goteam/internal/usersvc/resetapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/db/resettbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/mail"
+	"github.com/kxplxn/goteam/pkg/password"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestResetAPI(t *testing.T) {
+	t.Run("POST", func(t *testing.T) {
+		var mails bytes.Buffer
+		sut := resetapi.NewPostHandler(
+			usertbl.NewRetrieverAnyCase(test.DB()),
+			resettbl.NewInserter(test.DB()),
+			mail.NewWriterMailer(&mails, "noreply@goteam.io"),
+			"http://localhost:3000/reset",
+			log.New(),
+		)
+
+		for _, c := range []struct {
+			name       string
+			reqBody    string
+			wantStatus int
+			wantMail   string
+		}{
+			{
+				name:       "UserNotFound",
+				reqBody:    `{"username": "bob321"}`,
+				wantStatus: http.StatusOK,
+				wantMail:   "",
+			},
+			{
+				name:       "NoEmail",
+				reqBody:    `{"username": "team1Member"}`,
+				wantStatus: http.StatusOK,
+				wantMail:   "",
+			},
+			{
+				name:       "OK",
+				reqBody:    `{"username": "team2Member"}`,
+				wantStatus: http.StatusOK,
+				wantMail:   "To: team2Member@goteam.io",
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				mails.Reset()
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPost, "/", strings.NewReader(c.reqBody),
+				)
+
+				sut.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				if c.wantMail == "" {
+					assert.Equal(t.Error, mails.Len(), 0)
+				} else {
+					assert.True(t.Error,
+						strings.Contains(mails.String(), c.wantMail),
+					)
+				}
+			})
+		}
+	})
+
+	t.Run("PATCH", func(t *testing.T) {
+		sut := resetapi.NewPatchHandler(
+			registerapi.NewPasswordValidator(
+				registerapi.StrictPwdPolicy, nil,
+			),
+			resettbl.NewConsumer(test.DB()),
+			usertbl.NewRetriever(test.DB()),
+			password.NewHasher(password.DefaultParams),
+			usertbl.NewUpdater(test.DB()),
+			revocationtbl.NewInserter(test.DB()),
+			sessiontbl.NewDeleterByUser(test.DB()),
+			log.New(),
+		)
+
+		for _, c := range []struct {
+			name       string
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name: "ResetNotFound",
+				reqBody: `{
+					"token": "n0nEx1st3ntT0k3n", "newPassword": "N3wP4ss!d"
+				}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Invalid or expired reset token."),
+			},
+			{
+				name: "ResetExpired",
+				reqBody: `{
+					"token": "3xp1r3dR3s3tT0k3n", "newPassword": "N3wP4ss!d"
+				}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Invalid or expired reset token."),
+			},
+			{
+				name: "OK",
+				reqBody: `{
+					"token": "v4l1dR3s3tT0k3n", "newPassword": "N3wP4ss!d"
+				}`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+					user, err := usertbl.NewRetriever(test.DB()).Retrieve(
+						context.Background(), "team2Member",
+					)
+					assert.Nil(t.Fatal, err)
+					err = password.NewHasher(password.DefaultParams).Compare(
+						user.Password, "N3wP4ss!d",
+					)
+					assert.Nil(t.Error, err)
+				},
+			},
+			{
+				name: "ResetUsed",
+				reqBody: `{
+					"token": "v4l1dR3s3tT0k3n", "newPassword": "N3wP4ss!d"
+				}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr("Invalid or expired reset token."),
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPatch, "/", strings.NewReader(c.reqBody),
+				)
+
+				sut.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
@@ -0,0 +1,101 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/kxplxn/goteam/internal/usersvc/sessionsapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/sessiontbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestSessionsAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewChecker(test.DB()),
+	)
+	sut := sessionsapi.NewDeleteHandler(
+		authDecoder,
+		revocationtbl.NewInserter(test.DB()),
+		sessiontbl.NewDeleterByUser(test.DB()),
+		log.New(),
+	)
+
+	for _, c := range []struct {
+		name       string
+		authFunc   func(*http.Request)
+		wantStatus int
+		assertFunc func(*testing.T, *http.Response, []any)
+	}{
+		{
+			name:       "NoAuth",
+			authFunc:   func(*http.Request) {},
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Auth token not found."),
+		},
+		{
+			name:       "InvalidAuth",
+			authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Invalid auth token."),
+		},
+		{
+			name:       "OK",
+			authFunc:   test.AddAuthCookie(test.T2AdminToken),
+			wantStatus: http.StatusOK,
+			assertFunc: func(t *testing.T, _ *http.Response, _ []any) {
+				_, err := authDecoder.Decode(http.Cookie{
+					Name: cookie.AuthName, Value: test.T2AdminToken,
+				})
+				assert.ErrIs(t.Error, err, cookie.ErrRevoked)
+
+				keyCond := expression.Key("Username").
+					Equal(expression.Value("team2Admin"))
+				expr, err := expression.NewBuilder().
+					WithKeyCondition(keyCond).
+					Build()
+				assert.Nil(t.Fatal, err)
+				out, err := test.DB().Query(
+					context.Background(), &dynamodb.QueryInput{
+						TableName:                 &sessionTableName,
+						IndexName:                 aws.String("Username-index"),
+						ExpressionAttributeNames:  expr.Names(),
+						ExpressionAttributeValues: expr.Values(),
+						KeyConditionExpression:    expr.KeyCondition(),
+					},
+				)
+				assert.Nil(t.Fatal, err)
+				assert.Equal(t.Error, len(out.Items), 0)
+			},
+		},
+		{
+			name:       "RevokedAuth",
+			authFunc:   test.AddAuthCookie(test.T2AdminToken),
+			wantStatus: http.StatusUnauthorized,
+			assertFunc: assert.OnRespErr("Invalid auth token."),
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			c.authFunc(r)
+
+			sut.Handle(w, r, "")
+
+			resp := w.Result()
+			assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+			c.assertFunc(t, resp, []any{})
+		})
+	}
+}
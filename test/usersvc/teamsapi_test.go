@@ -0,0 +1,214 @@
+//go:build itest
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kxplxn/goteam/internal/usersvc/teamsapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/invitetbl"
+	"github.com/kxplxn/goteam/pkg/db/membershiptbl"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/teamtbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/quota"
+	"github.com/kxplxn/goteam/pkg/role"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestTeamsAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewChecker(test.DB()),
+	)
+
+	t.Run("GET", func(t *testing.T) {
+		sut := teamsapi.NewGetHandler(
+			authDecoder,
+			membershiptbl.NewRetrieverByUser(test.DB()),
+			teamtbl.NewRetriever(test.DB()),
+			log.New(),
+		)
+
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:       "InvalidAuth",
+				authFunc:   test.AddAuthCookie("asdkfjahsaksdfjhas"),
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:       "OK",
+				authFunc:   test.AddAuthCookie(test.T1MemberToken),
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody teamsapi.GetResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+					assert.AllEqual(t.Error, respBody.Teams, []teamsapi.Team{
+						{
+							ID:       "66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+							Name:     "Team 2",
+							Role:     role.Viewer,
+							IsActive: false,
+						},
+						{
+							ID:       "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+							Name:     "",
+							Role:     role.Member,
+							IsActive: true,
+						},
+					})
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				c.authFunc(r)
+
+				sut.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		sut := teamsapi.NewPostHandler(
+			authDecoder,
+			invitetbl.NewConsumer(test.DB()),
+			invitetbl.NewReleaser(test.DB()),
+			teamtbl.NewRetriever(test.DB()),
+			membershiptbl.NewRetrieverByTeam(test.DB()),
+			quota.DefaultLimits,
+			membershiptbl.NewInserter(test.DB()),
+			log.New(),
+		)
+
+		for _, c := range []struct {
+			name        string
+			authFunc    func(*http.Request)
+			inviteToken string
+			wantStatus  int
+			assertFunc  func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:        "NoAuth",
+				authFunc:    func(*http.Request) {},
+				inviteToken: "",
+				wantStatus:  http.StatusUnauthorized,
+				assertFunc:  assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:        "InvalidAuth",
+				authFunc:    test.AddAuthCookie("asdkfjahsaksdfjhas"),
+				inviteToken: "",
+				wantStatus:  http.StatusUnauthorized,
+				assertFunc:  assert.OnRespErr("Invalid auth token."),
+			},
+			{
+				name:        "EmptyInviteToken",
+				authFunc:    test.AddAuthCookie(test.T3AdminToken),
+				inviteToken: "",
+				wantStatus:  http.StatusBadRequest,
+				assertFunc:  assert.OnRespErr("Invite token cannot be empty."),
+			},
+			{
+				name:        "InviteExpired",
+				authFunc:    test.AddAuthCookie(test.T3AdminToken),
+				inviteToken: "e4b8c2a6-9d3f-4a1e-8b5c-6f2d9e3a1c7b",
+				wantStatus:  http.StatusBadRequest,
+				assertFunc:  assert.OnRespErr("Invalid invite token."),
+			},
+			{
+				name:        "AlreadyMember",
+				authFunc:    test.AddAuthCookie(test.T2AdminToken),
+				inviteToken: "c3f1a7e5-8b2d-4e9a-b6c4-1d7f3a9e5b28",
+				wantStatus:  http.StatusConflict,
+				assertFunc: assert.OnRespErr(
+					"You are already a member of this team.",
+				),
+			},
+			{
+				name:        "OK",
+				authFunc:    test.AddAuthCookie(test.T3AdminToken),
+				inviteToken: "c3f1a7e5-8b2d-4e9a-b6c4-1d7f3a9e5b28",
+				wantStatus:  http.StatusCreated,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody teamsapi.PostResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, respBody.Team.ID,
+						"66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+					)
+					assert.Equal(t.Error, respBody.Team.Role, role.Member)
+
+					membership, err := membershiptbl.NewRetriever(test.DB()).
+						Retrieve(
+							context.Background(),
+							"team3Admin",
+							"66ca0ddf-5f62-4713-bcc9-36cb0954eb7b",
+						)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, membership.Role, role.Member)
+
+					// the use taken by AlreadyMember should have been given
+					// back
+					out, err := test.DB().GetItem(
+						context.Background(), &dynamodb.GetItemInput{
+							TableName: &inviteTableName,
+							Key: map[string]types.AttributeValue{
+								"ID": &types.AttributeValueMemberS{
+									Value: "c3f1a7e5-8b2d-4e9a-b6c4-" +
+										"1d7f3a9e5b28",
+								},
+							},
+						},
+					)
+					assert.Nil(t.Fatal, err)
+					var invite invitetbl.Invite
+					err = attributevalue.UnmarshalMap(out.Item, &invite)
+					assert.Nil(t.Fatal, err)
+					assert.Equal(t.Error, invite.Uses, 1)
+				},
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPost, "/?inviteToken="+c.inviteToken, nil,
+				)
+				c.authFunc(r)
+
+				sut.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+}
@@ -0,0 +1,187 @@
+//go:build itest
+
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kxplxn/goteam/internal/usersvc/tokenapi"
+	"github.com/kxplxn/goteam/pkg/assert"
+	"github.com/kxplxn/goteam/pkg/cookie"
+	"github.com/kxplxn/goteam/pkg/db/revocationtbl"
+	"github.com/kxplxn/goteam/pkg/db/tokentbl"
+	"github.com/kxplxn/goteam/pkg/db/usertbl"
+	"github.com/kxplxn/goteam/pkg/log"
+	"github.com/kxplxn/goteam/pkg/pat"
+	"github.com/kxplxn/goteam/test"
+)
+
+func TestTokenAPI(t *testing.T) {
+	authDecoder := cookie.NewAuthDecoder(
+		test.KeySet, revocationtbl.NewChecker(test.DB()),
+	)
+	postHandler := tokenapi.NewPostHandler(
+		authDecoder,
+		tokenapi.ValidatePostReq,
+		tokentbl.NewRetrieverByUser(test.DB()),
+		tokentbl.NewInserter(test.DB()),
+		log.New(),
+	)
+	getHandler := tokenapi.NewGetHandler(
+		authDecoder, tokentbl.NewRetrieverByUser(test.DB()), log.New(),
+	)
+	deleteHandler := tokenapi.NewDeleteHandler(
+		authDecoder, tokentbl.NewDeleter(test.DB()), log.New(),
+	)
+	newPATDecoder := func(scope string) pat.Decoder {
+		return pat.NewDecoder(
+			authDecoder,
+			tokentbl.NewRetriever(test.DB()),
+			usertbl.NewRetriever(test.DB()),
+			scope,
+		)
+	}
+
+	var token string
+
+	t.Run("Post", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			authFunc   func(*http.Request)
+			reqBody    string
+			wantStatus int
+			assertFunc func(*testing.T, *http.Response, []any)
+		}{
+			{
+				name:       "NoAuth",
+				authFunc:   func(*http.Request) {},
+				reqBody:    `{}`,
+				wantStatus: http.StatusUnauthorized,
+				assertFunc: assert.OnRespErr("Auth token not found."),
+			},
+			{
+				name:     "InvalidScope",
+				authFunc: test.AddAuthCookie(test.T1MemberToken),
+				reqBody: `{"name": "ci", "scopes": ["x"], ` +
+					`"expiresInDays": 1}`,
+				wantStatus: http.StatusBadRequest,
+				assertFunc: assert.OnRespErr(
+					"Scopes must be one of tasks:read, tasks:write, " +
+						"team:read, team:write.",
+				),
+			},
+			{
+				name:     "OK",
+				authFunc: test.AddAuthCookie(test.T1MemberToken),
+				reqBody: `{"name": "ci", "scopes": ["tasks:write"], ` +
+					`"expiresInDays": 30}`,
+				wantStatus: http.StatusOK,
+				assertFunc: func(t *testing.T, resp *http.Response, _ []any) {
+					var respBody tokenapi.PostResp
+					err := json.NewDecoder(resp.Body).Decode(&respBody)
+					assert.Nil(t.Fatal, err)
+					assert.True(t.Fatal,
+						strings.HasPrefix(respBody.Token, pat.Prefix),
+					)
+					token = respBody.Token
+				},
+			},
+			{
+				name:     "NameTaken",
+				authFunc: test.AddAuthCookie(test.T1MemberToken),
+				reqBody: `{"name": "ci", "scopes": ["team:read"], ` +
+					`"expiresInDays": 30}`,
+				wantStatus: http.StatusConflict,
+				assertFunc: assert.OnRespErr(
+					"You already have a token with this name.",
+				),
+			},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPost, "/", strings.NewReader(c.reqBody),
+				)
+				c.authFunc(r)
+
+				postHandler.Handle(w, r, "")
+
+				resp := w.Result()
+				assert.Equal(t.Error, resp.StatusCode, c.wantStatus)
+				c.assertFunc(t, resp, []any{})
+			})
+		}
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		ck := http.Cookie{Name: cookie.AuthName, Value: token}
+
+		auth, err := newPATDecoder(pat.ScopeTasksRead).Decode(ck)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Error, auth.Username, "team1Member")
+		assert.Equal(t.Error,
+			auth.TeamID, "afeadc4a-68b0-4c33-9e83-4648d20ff26a",
+		)
+
+		_, err = newPATDecoder(pat.ScopeTeamWrite).Decode(ck)
+		assert.ErrIs(t.Error, err, pat.ErrScope)
+
+		// personal access tokens can't be used to manage tokens
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		getHandler.Handle(w, r, "")
+		assert.Equal(t.Error, w.Result().StatusCode, http.StatusUnauthorized)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+test.T1MemberToken)
+
+		getHandler.Handle(w, r, "")
+
+		resp := w.Result()
+		assert.Equal(t.Fatal, resp.StatusCode, http.StatusOK)
+		var respBody tokenapi.GetResp
+		err := json.NewDecoder(resp.Body).Decode(&respBody)
+		assert.Nil(t.Fatal, err)
+		assert.Equal(t.Fatal, len(respBody.Tokens), 1)
+		assert.Equal(t.Error, respBody.Tokens[0].Name, "ci")
+		assert.AllEqual(t.Error,
+			respBody.Tokens[0].Scopes, []string{pat.ScopeTasksWrite},
+		)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		for _, c := range []struct {
+			name       string
+			tokenName  string
+			wantStatus int
+		}{
+			{name: "NotFound", tokenName: "cli", wantStatus: 404},
+			{name: "OK", tokenName: "ci", wantStatus: 200},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodDelete, "/?name="+c.tokenName, nil,
+				)
+				test.AddAuthCookie(test.T1MemberToken)(r)
+
+				deleteHandler.Handle(w, r, "")
+
+				assert.Equal(t.Error, w.Result().StatusCode, c.wantStatus)
+			})
+		}
+
+		_, err := newPATDecoder(pat.ScopeTasksRead).Decode(http.Cookie{
+			Name: cookie.AuthName, Value: token,
+		})
+		assert.ErrIs(t.Error, err, cookie.ErrInvalid)
+	})
+}
@@ -21,7 +21,7 @@ import './app.sass'
 import Spinner from './components/Home/Spinner/Spinner'
 import TeamAPI from './api/TeamAPI'
 import TasksAPI from './api/TasksAPI'
-import { forEach, orderBy, some } from 'lodash'
+import { find, forEach, orderBy, some } from 'lodash'
 
 const App = () => {
   const [isLoading, setIsLoading] = useState(false)
@@ -51,14 +51,15 @@ const App = () => {
         // if boardId is truthy, start a tasks request with it
         let tasksProm = boardId && TasksAPI.get(boardId)
 
-        // get team - set its ID, invite token, and boards
+        // get team - set its ID, name, owner and boards
         var teamRes = await TeamAPI.get()
 
         console.log("teamres: " + JSON.stringify(teamRes))
 
         setTeam({
           id: teamRes.data.id,
-          inviteToken: cookies.get("invite-token"),
+          name: teamRes.data.name,
+          owner: teamRes.data.owner,
         })
         // a member who isn't assigned to any board will not have any boards
         setBoards(teamRes.data.boards ?? [])
@@ -71,19 +72,23 @@ const App = () => {
           && TasksAPI.get(teamRes.data.boards[0].id)
         )
 
+        // build the active board's columns from the ones the board defines
+        const toColumns = (id) => (
+          find(teamRes.data.boards, (b) => b.id === id)?.columns ?? []
+        ).map((column) => ({ ...column, tasks: [] }))
+
         let board
         if (tasksRes && tasksRes.data.length > 0) {
           // if tasks request returned any results, set the active board
           // accordingly
           board = {
             id: tasksRes.data[0].boardID,
-            columns: [
-              { tasks: [] }, { tasks: [] }, { tasks: [] }, { tasks: [] },
-            ],
+            columns: toColumns(tasksRes.data[0].boardID),
           }
 
           forEach(orderBy(tasksRes.data, ['order']), (task) => {
-            board.columns[task.colNo].tasks.push(task)
+            find(board.columns, (c) => c.id === task.colID)
+              ?.tasks.push(task)
           })
         } else {
           if (!boardId) {
@@ -100,18 +105,17 @@ const App = () => {
           // if not, set the active board's board ID to the first board of team
           board = {
             id: boardId,
-            columns: [
-              { tasks: [] }, { tasks: [] }, { tasks: [] }, { tasks: [] },
-            ],
+            columns: toColumns(boardId),
           }
         }
         setActiveBoard(board)
 
-        setMembers(teamRes.data.members.map((username) => {
-          let isAdmin = username === teamRes.data.id
+        setMembers(teamRes.data.members.map((member) => {
+          let { username, displayName, avatarURL } = member
+          let isAdmin = username === teamRes.data.owner
           let isActive = !isAdmin && board &&
             some(board.members, (m) => m == username)
-          return { username, isAdmin, isActive }
+          return { username, displayName, avatarURL, isAdmin, isActive }
         }))
 
       }
@@ -139,7 +143,6 @@ const App = () => {
   useEffect(() => (
     !cookies.get('auth-token')
     && sessionStorage.removeItem("board-id")
-    && cookies.remove("invite-token")
   ), [])
 
   return (
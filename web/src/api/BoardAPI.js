@@ -12,6 +12,38 @@ const BoardAPI = {
   patch: (boardId, boardData) => axios.patch(
     apiUrl + "?id=" + boardId, boardData, { withCredentials: true },
   ),
+
+  archive: (boardId) => axios.post(
+    apiUrl + "/archive?id=" + boardId, null, { withCredentials: true },
+  ),
+
+  restore: (boardId) => axios.delete(
+    apiUrl + "/archive?id=" + boardId, { withCredentials: true },
+  ),
+
+  getArchived: () => axios.get(
+    apiUrl + "/archive", { withCredentials: true },
+  ),
+
+  addColumn: (columnData) => axios.post(
+    apiUrl + "/columns", columnData, { withCredentials: true },
+  ),
+
+  editColumn: (columnData) => axios.patch(
+    apiUrl + "/columns", columnData, { withCredentials: true },
+  ),
+
+  reorderColumns: (boardId, columnIds) => axios.put(
+    apiUrl + "/columns",
+    { boardID: boardId, columnIDs: columnIds },
+    { withCredentials: true },
+  ),
+
+  deleteColumn: (boardId, columnId, moveTo) => axios.delete(
+    apiUrl + "/columns?boardID=" + boardId + "&id=" + columnId
+    + "&moveTo=" + moveTo,
+    { withCredentials: true },
+  ),
 };
 
 export default BoardAPI;
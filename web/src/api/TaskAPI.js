@@ -2,13 +2,18 @@ import axios from 'axios';
 
 const apiUrl = process.env.REACT_APP_TASK_SERVICE_URL + "/task"
 
+// overrideWIP lets a task into a column past the column's WIP limit
 const TaskAPI = {
-  post: (task) => axios.post(
-    apiUrl, task, { withCredentials: true },
+  post: (task, overrideWIP = false) => axios.post(
+    apiUrl + (overrideWIP ? "?overrideWIP=true" : ""),
+    task,
+    { withCredentials: true },
   ),
 
-  patch: (task) => axios.patch(
-    apiUrl, task, { withCredentials: true },
+  patch: (task, overrideWIP = false) => axios.patch(
+    apiUrl + (overrideWIP ? "?overrideWIP=true" : ""),
+    task,
+    { withCredentials: true },
   ),
 
   delete: (taskId) => axios.delete(
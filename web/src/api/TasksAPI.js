@@ -7,7 +7,12 @@ const TasksAPI = {
     apiUrl + "?boardID=" + boardID, { withCredentials: true },
   ),
 
-  patch: (data) => axios.patch(apiUrl, data, { withCredentials: true }),
+  // overrideWIP lets tasks into columns past their WIP limits
+  patch: (data, overrideWIP = false) => axios.patch(
+    apiUrl + (overrideWIP ? "?overrideWIP=true" : ""),
+    data,
+    { withCredentials: true },
+  ),
 };
 
 export default TasksAPI;
@@ -4,6 +4,33 @@ const TeamAPI = {
   get: () => axios.get(
     process.env.REACT_APP_TEAM_SERVICE_URL + "/team", { withCredentials: true },
   ),
+
+  putOwner: (username) => axios.put(
+    process.env.REACT_APP_TEAM_SERVICE_URL + "/team/owner",
+    { username },
+    { withCredentials: true },
+  ),
+
+  getQuota: () => axios.get(
+    process.env.REACT_APP_TEAM_SERVICE_URL + "/team/quota",
+    { withCredentials: true },
+  ),
+
+  getInvites: () => axios.get(
+    process.env.REACT_APP_TEAM_SERVICE_URL + "/team/invites",
+    { withCredentials: true },
+  ),
+
+  postInvite: (username, maxUses, expiresInHours) => axios.post(
+    process.env.REACT_APP_TEAM_SERVICE_URL + "/team/invites",
+    { username, maxUses, expiresInHours },
+    { withCredentials: true },
+  ),
+
+  deleteInvite: (id) => axios.delete(
+    process.env.REACT_APP_TEAM_SERVICE_URL + "/team/invites?id=" + id,
+    { withCredentials: true },
+  ),
 };
 
 export default TeamAPI;
@@ -1,6 +1,7 @@
 import axios from 'axios';
 
 var apiUrl = process.env.REACT_APP_USER_SERVICE_URL
+var teamApiUrl = process.env.REACT_APP_TEAM_SERVICE_URL
 
 const UserAPI = {
   login: (username, password) => (
@@ -11,6 +12,22 @@ const UserAPI = {
     )
   ),
 
+  verifyMFA: (code) => (
+    axios.post(apiUrl + "/login/mfa", { code }, { withCredentials: true })
+  ),
+
+  startMFA: () => (
+    axios.post(apiUrl + "/user/mfa", {}, { withCredentials: true })
+  ),
+
+  enableMFA: (code) => (
+    axios.patch(apiUrl + "/user/mfa", { code }, { withCredentials: true })
+  ),
+
+  // navigated to rather than requested since it redirects to the identity
+  // provider
+  oidcLoginURL: apiUrl + "/oidc/login",
+
   register: (username, password, inviteToken) => (
     axios.post(
       apiUrl + "/register?inviteToken=" + inviteToken,
@@ -18,6 +35,84 @@ const UserAPI = {
       { withCredentials: true },
     )
   ),
+
+  patch: (username, boardID, isActive) => (
+    axios.patch(
+      teamApiUrl + "/user?username=" + username,
+      { boardID, isActive },
+      { withCredentials: true },
+    )
+  ),
+
+  refresh: () => (
+    axios.post(apiUrl + "/refresh", {}, { withCredentials: true })
+  ),
+
+  logout: () => (
+    axios.post(apiUrl + "/logout", {}, { withCredentials: true })
+  ),
+
+  logoutAll: () => (
+    axios.delete(apiUrl + "/sessions", { withCredentials: true })
+  ),
+
+  changePassword: (currentPassword, newPassword) => (
+    axios.patch(
+      apiUrl + "/user/password",
+      { currentPassword, newPassword },
+      { withCredentials: true },
+    )
+  ),
+
+  requestReset: (username) => (
+    axios.post(apiUrl + "/reset", { username })
+  ),
+
+  reset: (token, newPassword) => (
+    axios.patch(apiUrl + "/reset", { token, newPassword })
+  ),
+
+  getProfile: () => (
+    axios.get(apiUrl + "/user/me", { withCredentials: true })
+  ),
+
+  updateProfile: (profile) => (
+    axios.patch(apiUrl + "/user/me", profile, { withCredentials: true })
+  ),
+
+  deleteAccount: (password) => (
+    axios.delete(apiUrl + "/user/me", {
+      data: { password },
+      withCredentials: true,
+    })
+  ),
+
+  getTeams: () => (
+    axios.get(apiUrl + "/user/teams", { withCredentials: true })
+  ),
+
+  joinTeam: (inviteToken) => (
+    axios.post(
+      apiUrl + "/user/teams?inviteToken=" + inviteToken,
+      {},
+      { withCredentials: true },
+    )
+  ),
+
+  switchTeam: (teamID) => (
+    axios.put(
+      apiUrl + "/user/teams/active",
+      { teamID },
+      { withCredentials: true },
+    )
+  ),
+
+  delete: (username) => (
+    axios.delete(
+      teamApiUrl + "/user?username=" + username,
+      { withCredentials: true },
+    )
+  ),
 };
 
 export default UserAPI;
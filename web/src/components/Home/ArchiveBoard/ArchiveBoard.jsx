@@ -0,0 +1,100 @@
+import React, { useContext } from 'react';
+import PropTypes from 'prop-types';
+import {
+  Button, Col, Form, Row,
+} from 'react-bootstrap';
+
+import AppContext from '../../../AppContext';
+import BoardAPI from '../../../api/BoardAPI';
+import FormGroup from '../../_shared/FormGroup/FormGroup';
+import inputType from '../../../misc/inputType';
+
+import './archiveboard.sass';
+
+const ArchiveBoard = ({ id, name, toggleOff }) => {
+  const {
+    activeBoard, boards, setBoards, loadBoard, notify,
+  } = useContext(AppContext);
+
+  const handleSubmit = (e) => {
+    e.preventDefault();
+
+    // Keep an initial state to avoid loadBoard() on API error
+    const initialBoards = boards;
+
+    // Update client state to avoid load time
+    const newBoards = boards.filter((board) => board.id !== id);
+    setBoards(newBoards);
+
+    // Archive board in database
+    BoardAPI
+      .archive(id)
+      .then(() => {
+        toggleOff();
+        if (activeBoard.id !== id) { return null; }
+        sessionStorage.removeItem('board-id');
+        return newBoards.length > 0 && loadBoard(newBoards[0].id);
+      })
+      .catch((err) => {
+        notify(
+          'Unable to archive board.',
+          `${err?.response?.data?.error || err?.message || 'Server Error'}`,
+        );
+        setBoards(initialBoards);
+      });
+  };
+
+  return (
+    <div className="ArchiveBoard">
+      <Form
+        className="Form"
+        onSubmit={handleSubmit}
+        onClick={(e) => e.stopPropagation()}
+      >
+        <h1>ARCHIVE BOARD</h1>
+        <p>
+          Archived boards are hidden from the team and their tasks become
+          read-only until the board is restored.
+        </p>
+
+        <FormGroup
+          type={inputType.TEXT}
+          label="name"
+          value={name}
+          disabled
+        />
+
+        <Row className="ButtonWrapper">
+          <Col className="ButtonCol">
+            <Button
+              className="Button CancelButton"
+              type="button"
+              aria-label="cancel"
+              onClick={toggleOff}
+            >
+              CANCEL
+            </Button>
+          </Col>
+
+          <Col className="ButtonCol">
+            <Button
+              className="Button ArchiveButton"
+              type="submit"
+              aria-label="submit"
+            >
+              ARCHIVE
+            </Button>
+          </Col>
+        </Row>
+      </Form>
+    </div>
+  );
+};
+
+ArchiveBoard.propTypes = {
+  id: PropTypes.string.isRequired,
+  name: PropTypes.string.isRequired,
+  toggleOff: PropTypes.func.isRequired,
+};
+
+export default ArchiveBoard;
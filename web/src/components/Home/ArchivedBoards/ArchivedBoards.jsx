@@ -0,0 +1,77 @@
+import React, { useContext, useEffect, useState } from 'react';
+import PropTypes from 'prop-types';
+import { Button } from 'react-bootstrap';
+
+import AppContext from '../../../AppContext';
+import BoardAPI from '../../../api/BoardAPI';
+
+import './archivedboards.sass';
+
+const ArchivedBoards = ({ toggleOff }) => {
+  const { user, loadBoard, notify } = useContext(AppContext);
+  const [archived, setArchived] = useState(null);
+
+  useEffect(() => {
+    BoardAPI
+      .getArchived()
+      .then((res) => setArchived(res.data.boards))
+      .catch((err) => {
+        notify(
+          'Unable to load archived boards.',
+          `${err?.response?.data?.error || err?.message || 'Server Error'}`,
+        );
+        toggleOff();
+      });
+  }, []);
+
+  const restore = (id) => {
+    BoardAPI
+      .restore(id)
+      .then(() => {
+        setArchived(archived.filter((board) => board.id !== id));
+        // reload so that the restored board shows up in the boards menu
+        loadBoard();
+      })
+      .catch((err) => notify(
+        'Unable to restore board.',
+        `${err?.response?.data?.error || err?.message || 'Server Error'}`,
+      ));
+  };
+
+  return (
+    <div className="ArchivedBoards" onClick={toggleOff}>
+      <div className="Body" onClick={(e) => e.stopPropagation()}>
+        <h1>ARCHIVED BOARDS</h1>
+
+        {archived && archived.length === 0 && (
+          <p>There are no archived boards.</p>
+        )}
+
+        {archived && archived.map((board) => (
+          <div className="Item" key={board.id}>
+            <span className="Name">{board.name}</span>
+            {user.isAdmin && (
+              <Button
+                className="RestoreButton"
+                type="button"
+                onClick={() => restore(board.id)}
+              >
+                RESTORE
+              </Button>
+            )}
+          </div>
+        ))}
+
+        <Button className="Button" type="button" onClick={toggleOff}>
+          CLOSE
+        </Button>
+      </div>
+    </div>
+  );
+};
+
+ArchivedBoards.propTypes = {
+  toggleOff: PropTypes.func.isRequired,
+};
+
+export default ArchivedBoards;
@@ -36,7 +36,7 @@ const Board = ({ handleActivate }) => {
 
     // update source tasks' orders
     const sourceTasks = source.tasks.map((task, index) => ({
-      ...task, colNo: iSource, order: index,
+      ...task, colID: source.id, order: index,
     }));
 
     // find the destination column – the that the task is being moved into
@@ -47,7 +47,7 @@ const Board = ({ handleActivate }) => {
 
     // update destination tasks' orders
     const destinationTasks = destination.tasks.map((task, index) => ({
-      ...task, colNo: iDest, order: index,
+      ...task, colID: destination.id, order: index,
     }));
 
     // update client state ahead of API calls for faster UI - if errors occur
@@ -57,22 +57,37 @@ const Board = ({ handleActivate }) => {
       columns: activeBoard.columns.map((column, i) => {
         switch (i) {
           case iDest:
-            return { tasks: destinationTasks };
+            return { ...column, tasks: destinationTasks };
           case iSource:
-            return { tasks: sourceTasks };
+            return { ...column, tasks: sourceTasks };
           default:
             return column
         }
       }),
     });
 
+    // patch the destination, asking the user whether to move the task anyway
+    // if that puts the destination over its WIP limit
+    const patchDestination = async () => {
+      try {
+        await TasksAPI.patch(destinationTasks);
+      } catch (err) {
+        const wipError = err?.response?.status === 409
+          && err?.response?.data?.error;
+        if (!wipError || !window.confirm(`${wipError} Move it anyway?`)) {
+          throw err;
+        }
+        await TasksAPI.patch(destinationTasks, true);
+      }
+    };
+
     try {
       // update the source and the destination in the database
       let sourceProm = sourceTasks.length > 0 && iSource !== iDest
         ? TasksAPI.patch(sourceTasks)
         : Promise.resolve()
       let destProm = destinationTasks.length > 0
-        ? await TasksAPI.patch(destinationTasks)
+        ? await patchDestination()
         : Promise.resolve()
       await Promise.all([sourceProm, destProm])
     } catch (err) {
@@ -94,8 +109,12 @@ const Board = ({ handleActivate }) => {
           && activeBoard.columns.map((column, i) => (
             <Column
               id={i}
-              key={i}
-              order={i}
+              key={column.id}
+              colID={column.id}
+              name={column.name}
+              color={column.color}
+              wipLimit={column.wipLimit}
+              isFirst={i === 0}
               tasks={column.tasks}
               handleActivate={handleActivate}
             />
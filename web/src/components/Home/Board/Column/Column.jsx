@@ -1,4 +1,4 @@
-import React, { useContext, useState, useEffect } from 'react';
+import React, { useContext } from 'react';
 import PropTypes from 'prop-types';
 import { Droppable } from 'react-beautiful-dnd';
 import { Col } from 'react-bootstrap';
@@ -7,7 +7,6 @@ import { faPlusCircle } from '@fortawesome/free-solid-svg-icons';
 import _ from 'lodash/core';
 
 import Task from './Task/Task';
-import columnOrder from './columnOrder';
 import capFirstLetterOf from '../../../../misc/util';
 import AppContext from '../../../../AppContext';
 
@@ -15,30 +14,28 @@ import './column.sass';
 import window from '../../../../misc/window';
 
 const Column = ({
-  id, order, tasks, handleActivate,
+  id, colID, name, color, wipLimit, isFirst, tasks, handleActivate,
 }) => {
   const { user } = useContext(AppContext);
-  const [name, setName] = useState('');
 
-  useEffect(() => {
-    order !== null && setName(columnOrder.parseInt(order))
-  }, [order]);
+  // columns without a color of their own use the default column background,
+  // which is set for the default columns by their IDs
+  const style = color ? { backgroundColor: color } : {};
+  const className = color ? '' : `${capFirstLetterOf(colID)}Column`;
 
   return (
     <Col className="ColumnWrapper" xs={3}>
-      <div
-        className={
-          `Column ${name && capFirstLetterOf(name.replace('!', ''))}Column`
-        }
-      >
-        <div className="Header">
+      <div className={`Column ${className}`}>
+        <div className="Header" style={style}>
           {name && name.toUpperCase()}
+          {wipLimit > 0 && ` (${tasks.length}/${wipLimit})`}
         </div>
 
         <Droppable droppableId={`${id}`}>
           {(provided) => (
             <div
               className="Body"
+              style={style}
               {...provided.droppableProps}
               ref={provided.innerRef}
             >
@@ -52,7 +49,7 @@ const Column = ({
                   description={task.description}
                   order={task.order}
                   assignee={task.user}
-                  colNo={task.colNo}
+                  colID={task.colID}
                   subtasks={task.subtasks}
                   handleActivate={handleActivate}
                 />
@@ -60,7 +57,8 @@ const Column = ({
 
               {provided.placeholder}
 
-              {user.isAdmin && name === columnOrder.INBOX && (
+              {(user.isAdmin || user.role === 'member')
+                && isFirst && (
                 <button
                   className="CreateButton"
                   onClick={handleActivate(window.CREATE_TASK)}
@@ -78,7 +76,11 @@ const Column = ({
 };
 
 Column.propTypes = {
-  order: PropTypes.number.isRequired,
+  colID: PropTypes.string.isRequired,
+  name: PropTypes.string.isRequired,
+  color: PropTypes.string,
+  wipLimit: PropTypes.number,
+  isFirst: PropTypes.bool,
   tasks: PropTypes.arrayOf(
     PropTypes.exact({
       teamID: PropTypes.string.isRequired,
@@ -87,7 +89,7 @@ Column.propTypes = {
       title: PropTypes.string.isRequired,
       description: PropTypes.string.isRequired,
       order: PropTypes.number.isRequired,
-      colNo: PropTypes.number,
+      colID: PropTypes.string,
       user: PropTypes.string,
       subtasks: PropTypes.arrayOf(
         PropTypes.exact({
@@ -101,6 +103,9 @@ Column.propTypes = {
 };
 
 Column.defaultProps = {
+  color: '',
+  wipLimit: 0,
+  isFirst: false,
   handleActivate: () => { },
 };
 
@@ -21,7 +21,7 @@ const Task = ({
   order,
   assignedUser,
   handleActivate,
-  colNo,
+  colID,
   subtasks,
 }) => {
   const {
@@ -39,8 +39,8 @@ const Task = ({
   //   // Update client state to avoid load time
   //   setActiveBoard({
   //     ...activeBoard,
-  //     column: activeBoard.columns.map((column, i) => (
-  //       i === colNo ? {
+  //     column: activeBoard.columns.map((column) => (
+  //       column.id === colID ? {
   //         ...column,
   //         tasks: column.tasks.map((task) => (
   //           task.id === id
@@ -76,8 +76,8 @@ const Task = ({
     // Update client state to avoid load screen.
     setActiveBoard({
       ...activeBoard,
-      columns: activeBoard.columns.map((column, i) => (
-        i === colNo ? {
+      columns: activeBoard.columns.map((column) => (
+        column.id === colID ? {
           ...column,
           tasks: column.tasks.map((task) => (
             task.id === id
@@ -93,6 +93,7 @@ const Task = ({
       .patch({
         id,
         boardID: activeBoard.id,
+        colID,
         title,
         description,
         order,
@@ -112,7 +113,7 @@ const Task = ({
     <Draggable
       draggableId={`draggable-${id}`}
       index={order}
-      isDragDisabled={!user.isAdmin && user.username !== assignedUser}
+      isDragDisabled={!user.isAdmin && user.role !== 'member'}
     >
       {(provided) => (
         <div
@@ -200,7 +201,7 @@ const Task = ({
                 title,
                 description,
                 subtasks,
-                colNo: colNo,
+                colID,
                 toggleOff: handleActivate(window.NONE),
               })}
             >
@@ -214,7 +215,7 @@ const Task = ({
                 title,
                 description,
                 subtasks,
-                colNo,
+                colID,
                 toggleOff: handleActivate(window.NONE),
               })}
             >
@@ -233,7 +234,7 @@ Task.propTypes = {
   description: PropTypes.string.isRequired,
   order: PropTypes.number.isRequired,
   // assignee: PropTypes.string,
-  colNo: PropTypes.number.isRequired,
+  colID: PropTypes.string.isRequired,
   subtasks: PropTypes.arrayOf(
     PropTypes.exact({
       title: PropTypes.string.isRequired,
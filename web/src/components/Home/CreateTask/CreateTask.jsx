@@ -52,7 +52,7 @@ const CreateTask = ({ toggleOff }) => {
                 id: "",
                 title,
                 description,
-                colNo: i,
+                colID: column.id,
                 order: column.tasks.length + 1,
                 user: "",
                 subtasks: subts,
@@ -62,15 +62,25 @@ const CreateTask = ({ toggleOff }) => {
         )),
       });
 
-      // Create task in the database
+      // Create task in the database - if the first column is at its WIP
+      // limit, ask the user whether to create the task anyway
+      const task = {
+        boardID: activeBoard.id,
+        colID: activeBoard.columns[0].id,
+        title,
+        description,
+        subtasks: subts,
+        order: activeBoard.columns[0].tasks.length,
+      };
       TaskAPI
-        .post({
-          boardID: activeBoard.id,
-          colNo: 0,
-          title,
-          description,
-          subtasks: subts,
-          order: activeBoard.columns[0].tasks.length,
+        .post(task)
+        .catch((err) => {
+          const wipError = err?.response?.status === 409
+            && err?.response?.data?.error;
+          if (wipError && window.confirm(`${wipError} Create it anyway?`)) {
+            return TaskAPI.post(task, true);
+          }
+          throw err;
         })
         .then(() => {
           // Load board to retrieve the "actual" ID of the created task
@@ -14,7 +14,7 @@ import logo from './deletetask.svg';
 import './deletetask.sass';
 
 const DeleteTask = ({
-  id, title, description, subtasks, colNo, toggleOff,
+  id, title, description, subtasks, colID, toggleOff,
 }) => {
   const { activeBoard, setActiveBoard, notify } = useContext(AppContext);
 
@@ -27,8 +27,8 @@ const DeleteTask = ({
     // Update client state to avoid load time
     setActiveBoard({
       ...activeBoard,
-      columns: activeBoard.columns.map((column, i) => (
-        i === colNo ? {
+      columns: activeBoard.columns.map((column) => (
+        column.id === colID ? {
           ...column,
           tasks: column.tasks.filter((task) => (task.id !== id)),
         } : column
@@ -112,7 +112,7 @@ DeleteTask.propTypes = {
       done: PropTypes.bool.isRequired,
     }),
   ).isRequired,
-  colNo: PropTypes.number.isRequired,
+  colID: PropTypes.string.isRequired,
   toggleOff: PropTypes.func.isRequired,
 };
 
@@ -15,7 +15,7 @@ import logo from './edittask.svg';
 import './edittask.sass';
 
 const EditTask = ({
-  id, title, description, subtasks, colNo, toggleOff,
+  id, title, description, subtasks, colID, toggleOff,
 }) => {
   const {
     activeBoard, setActiveBoard, loadBoard, notify,
@@ -42,8 +42,8 @@ const EditTask = ({
       // Update client state to avoid load time
       setActiveBoard({
         ...activeBoard,
-        columns: activeBoard.columns.map((column, i) => (
-          i === column ? {
+        columns: activeBoard.columns.map((column) => (
+          column.id === colID ? {
             ...column,
             tasks: column.tasks.map((task) => (
               task.id === id ? {
@@ -65,7 +65,7 @@ const EditTask = ({
         .patch({
           id,
           boardID: activeBoard.id,
-          colNo: colNo,
+          colID,
           title: newTitle,
           description: newDescription,
           subtasks: newSubtasks.list,
@@ -158,7 +158,7 @@ EditTask.propTypes = {
       done: PropTypes.bool.isRequired,
     }),
   ).isRequired,
-  colNo: PropTypes.number.isRequired,
+  colID: PropTypes.string.isRequired,
   toggleOff: PropTypes.func.isRequired,
 };
 
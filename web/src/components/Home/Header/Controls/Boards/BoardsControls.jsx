@@ -10,9 +10,18 @@ import AppContext from '../../../../../AppContext';
 import './boardscontrols.sass';
 
 const BoardsControls = ({
-  isActive, handleActivate, handleCreate, handleEdit, handleDelete,
+  isActive,
+  handleActivate,
+  handleCreate,
+  handleEdit,
+  handleDelete,
+  handleArchive,
+  handleViewArchived,
 }) => {
-  const { boards } = useContext(AppContext);
+  const { user, boards } = useContext(AppContext);
+
+  // admins can still open the menu to create or restore boards
+  const isDisabled = boards && boards.length < 1 && !user.isAdmin;
 
   return (
     <Col xs={4} className="BoardsControls">
@@ -21,7 +30,7 @@ const BoardsControls = ({
         onClick={handleActivate}
         aria-label="boards controls toggler"
         type="button"
-        disabled={boards && boards.length < 1}
+        disabled={isDisabled}
       >
         <FontAwesomeIcon icon={faChalkboard} />
 
@@ -33,6 +42,8 @@ const BoardsControls = ({
           handleCreate={handleCreate}
           handleDelete={handleDelete}
           handleEdit={handleEdit}
+          handleArchive={handleArchive}
+          handleViewArchived={handleViewArchived}
         />
       )}
     </Col>
@@ -45,6 +56,8 @@ BoardsControls.propTypes = {
   handleCreate: PropTypes.func.isRequired,
   handleDelete: PropTypes.func.isRequired,
   handleEdit: PropTypes.func.isRequired,
+  handleArchive: PropTypes.func.isRequired,
+  handleViewArchived: PropTypes.func.isRequired,
 };
 
 export default BoardsControls;
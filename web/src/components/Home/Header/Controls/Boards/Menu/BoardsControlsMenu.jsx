@@ -1,14 +1,16 @@
 import React, { useContext } from 'react';
 import PropTypes from 'prop-types';
 import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
-import { faPlusCircle } from '@fortawesome/free-solid-svg-icons';
+import { faArchive, faPlusCircle } from '@fortawesome/free-solid-svg-icons';
 
 import AppContext from '../../../../../../AppContext';
 import BoardsControlsMenuItem from './Item/BoardsControlsMenuItem';
 
 import './boardscontrolsmenu.sass';
 
-const BoardsControlsMenu = ({ handleCreate, handleDelete, handleEdit }) => {
+const BoardsControlsMenu = ({
+  handleCreate, handleDelete, handleEdit, handleArchive, handleViewArchived,
+}) => {
   const { user, boards, activeBoard } = useContext(AppContext);
 
   return (
@@ -21,6 +23,7 @@ const BoardsControlsMenu = ({ handleCreate, handleDelete, handleEdit }) => {
           isActive={board.id === activeBoard.id}
           handleDelete={handleDelete}
           handleEdit={handleEdit}
+          handleArchive={handleArchive}
         />
       ))}
 
@@ -29,6 +32,15 @@ const BoardsControlsMenu = ({ handleCreate, handleDelete, handleEdit }) => {
           <FontAwesomeIcon icon={faPlusCircle} />
         </button>
       )}
+
+      <button
+        className="ArchivedButton"
+        type="button"
+        aria-label="archived boards"
+        onClick={handleViewArchived}
+      >
+        <FontAwesomeIcon icon={faArchive} />
+      </button>
     </div>
   );
 };
@@ -37,6 +49,8 @@ BoardsControlsMenu.propTypes = {
   handleCreate: PropTypes.func.isRequired,
   handleDelete: PropTypes.func.isRequired,
   handleEdit: PropTypes.func.isRequired,
+  handleArchive: PropTypes.func.isRequired,
+  handleViewArchived: PropTypes.func.isRequired,
 };
 
 export default BoardsControlsMenu;
@@ -10,7 +10,7 @@ import AppContext from '../../../../../../../AppContext';
 import './boardscontrolsmenuitem.sass';
 
 const BoardsControlsMenuItem = ({
-  id, name, isActive, handleDelete, handleEdit,
+  id, name, isActive, handleDelete, handleEdit, handleArchive,
 }) => {
   const { user, loadBoard, setIsLoading } = useContext(AppContext);
 
@@ -61,6 +61,12 @@ const BoardsControlsMenuItem = ({
         >
           <span>EDIT</span>
         </Item>
+        <Item
+          className="ContextMenuItem"
+          onClick={() => handleArchive({ id, name })}
+        >
+          <span>ARCHIVE</span>
+        </Item>
         <Item
           className="ContextMenuItem"
           onClick={() => handleDelete({ id, name })}
@@ -79,6 +85,7 @@ BoardsControlsMenuItem.propTypes = {
   isActive: PropTypes.bool.isRequired,
   handleDelete: PropTypes.func.isRequired,
   handleEdit: PropTypes.func.isRequired,
+  handleArchive: PropTypes.func.isRequired,
 };
 
 export default BoardsControlsMenuItem;
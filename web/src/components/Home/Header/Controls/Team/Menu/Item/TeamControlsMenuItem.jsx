@@ -14,7 +14,7 @@ import UserAPI from '../../../../../../../api/UserAPI';
 import './teamcontrolsmenuitem.sass';
 
 const TeamControlsMenuItem = ({
-  username, isAdmin, isActive, handleDelete,
+  username, displayName, isAdmin, isActive, handleDelete,
 }) => {
   const {
     user,
@@ -36,6 +36,9 @@ const TeamControlsMenuItem = ({
   //     })
   // );
 
+  // members who haven't set a display name are shown by their username
+  const name = displayName || username;
+
   const MENU_ID = `item-${username}`;
   const { show } = useContextMenu({ id: MENU_ID });
 
@@ -74,10 +77,10 @@ const TeamControlsMenuItem = ({
 
   return (
     <div className="TeamControlsMenuItem">
-      {username.length <= 20
-        ? viewButton(username)
+      {name.length <= 20 && name === username
+        ? viewButton(name)
         : viewTooltip(
-          viewButton(`${username.substring(0, 17)}...`),
+          viewButton(name.length <= 20 ? name : `${name.substring(0, 17)}...`),
         )}
 
       <Menu className="ContextMenu" id={MENU_ID}>
@@ -95,6 +98,7 @@ const TeamControlsMenuItem = ({
 
 TeamControlsMenuItem.propTypes = {
   username: PropTypes.string.isRequired,
+  displayName: PropTypes.string,
   isAdmin: PropTypes.bool.isRequired,
   isActive: PropTypes.bool,
   handleDelete: PropTypes.func.isRequired,
@@ -17,6 +17,7 @@ const TeamControlsMenu = ({ handleCreate, handleDelete }) => {
         <TeamControlsMenuItem
           key={member.username}
           username={member.username}
+          displayName={member.displayName}
           isAdmin={member.isAdmin}
           isActive={member.isActive}
           handleDelete={handleDelete}
@@ -8,6 +8,7 @@ import { faUsers, faSignOutAlt, faUser } from '@fortawesome/free-solid-svg-icons
 import cookies from 'js-cookie';
 
 import AppContext from '../../../AppContext';
+import UserAPI from '../../../api/UserAPI';
 import InitialStates from '../../../misc/InitialStates';
 import BoardsControls from './Controls/Boards/BoardsControls';
 import TeamControls from './Controls/Team/TeamControls';
@@ -28,7 +29,11 @@ const Header = ({ activeWindow, handleActivate }) => {
     loadBoard,
   } = useContext(AppContext);
 
-  const logout = () => {
+  const logout = async () => {
+    // revoke the tokens on the server before clearing the local state, but
+    // log out locally regardless of whether it succeeds
+    try { await UserAPI.logout(); } catch { /* ignore */ }
+
     setUser(InitialStates.user);
     setTeam(InitialStates.team);
     setMembers(InitialStates.members);
@@ -70,6 +75,8 @@ const Header = ({ activeWindow, handleActivate }) => {
               handleCreate={handleActivate(window.CREATE_BOARD)}
               handleDelete={handleActivate(window.DELETE_BOARD)}
               handleEdit={handleActivate(window.EDIT_BOARD)}
+              handleArchive={handleActivate(window.ARCHIVE_BOARD)}
+              handleViewArchived={handleActivate(window.ARCHIVED_BOARDS)}
             />
 
             <HelpToggler toggle={handleActivate(window.HELP)} />
@@ -14,8 +14,8 @@ const Help = ({ toggleOff }) => (
 
       <h1>Summary</h1>
       <p>
-        In both the control menus and the inbox column, you can click on the
-        plus icon to add a new item.
+        In both the control menus and the first column of a board, you can
+        click on the plus icon to add a new item.
         <br />
         You can also right click on any item to view additional controls for it.
         However, unless you are an admin,
@@ -61,13 +61,19 @@ const Help = ({ toggleOff }) => (
           Admins can click the plus icon at the bottom of the list to create
           a new board.
         </li>
+        <li>
+          Admins can right click on a board to archive it. Click the archive
+          icon at the bottom of the list
+          <br />
+          to view archived boards, which admins can restore from there.
+        </li>
       </ol>
 
       <h1>Task Controls</h1>
       <ol>
         <li>
-          Admins can view and click on the plus icon inside the INBOX column to
-          create a new task.
+          Admins can view and click on the plus icon inside the first column of
+          a board to create a new task.
         </li>
         <li>
           Drag and drop tasks from one column to another in order to move
@@ -5,6 +5,8 @@ import Board from './Board/Board';
 import CreateBoard from './CreateBoard/CreateBoard';
 import DeleteBoard from './DeleteBoard/DeleteBoard';
 import EditBoard from './EditBoard/EditBoard';
+import ArchiveBoard from './ArchiveBoard/ArchiveBoard';
+import ArchivedBoards from './ArchivedBoards/ArchivedBoards';
 import CreateTask from './CreateTask/CreateTask';
 import DeleteTask from './DeleteTask/DeleteTask';
 import EditTask from './EditTask/EditTask';
@@ -52,6 +54,18 @@ const Home = () => {
           />
         );
 
+      case window.ARCHIVE_BOARD:
+        return windowState.id && (
+          <ArchiveBoard
+            id={windowState.id}
+            name={windowState.name}
+            toggleOff={handleActivate(window.NONE)}
+          />
+        );
+
+      case window.ARCHIVED_BOARDS:
+        return <ArchivedBoards toggleOff={handleActivate(window.NONE)} />;
+
       case window.CREATE_TASK:
         return <CreateTask toggleOff={handleActivate(window.NONE)} />;
 
@@ -62,7 +76,7 @@ const Home = () => {
             title={windowState.title}
             description={windowState.description}
             subtasks={windowState.subtasks}
-            colNo={windowState.colNo}
+            colID={windowState.colID}
             toggleOff={handleActivate(window.NONE)}
           />
         );
@@ -74,7 +88,7 @@ const Home = () => {
             title={windowState.title}
             description={windowState.description}
             subtasks={windowState.subtasks}
-            colNo={windowState.colNo}
+            colID={windowState.colID}
             toggleOff={() => setActiveWindow(window.NONE)}
           />
         );
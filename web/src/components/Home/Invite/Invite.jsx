@@ -1,4 +1,4 @@
-import React, { useContext } from 'react';
+import React, { useEffect, useState } from 'react';
 import PropTypes from 'prop-types';
 import {
   Form, Button, OverlayTrigger, Tooltip,
@@ -7,17 +7,26 @@ import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
 import { faQuestion } from '@fortawesome/free-solid-svg-icons';
 
 import FormGroup from '../../_shared/FormGroup/FormGroup';
-import AppContext from '../../../AppContext';
+import TeamAPI from '../../../api/TeamAPI';
 
 import logo from './invite.svg';
 import './invite.sass';
 
 const Invite = ({ toggleOff }) => {
-  const { team } = useContext(AppContext);
+  const [inviteLink, setInviteLink] = useState('');
 
-  const inviteLink = (
-    `${process.env.REACT_APP_FRONTEND_URL}/register/${team.inviteToken}`
-  );
+  // create a single-use invite that expires in a day each time the invite
+  // form is opened
+  useEffect(() => {
+    TeamAPI
+      .postInvite('', 1, 24)
+      .then((res) => setInviteLink(
+        `${process.env.REACT_APP_FRONTEND_URL}/register/${res.data.id}`,
+      ))
+      .catch((err) => setInviteLink(
+        err?.response?.data?.error || 'Could not create an invite link.',
+      ));
+  }, []);
 
   const handleSubmit = (e) => {
     e.preventDefault();
@@ -61,8 +70,9 @@ const Invite = ({ toggleOff }) => {
               when you click &quot;GO!&quot;.
               <br />
               <br />
-              Send it to your colleagues. Once they register through it, they
-              will automatically be added to your team.
+              Send it to a colleague. Once they register through it, they will
+              automatically be added to your team. Each link can only be used
+              once and expires in a day.
             </Tooltip>
           )}
         >
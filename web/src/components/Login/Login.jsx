@@ -1,4 +1,4 @@
-import React, { useContext, useState } from 'react';
+import React, { useContext, useEffect, useState } from 'react';
 import { Form, Button } from 'react-bootstrap';
 // import cookies from 'js-cookie';
 
@@ -17,6 +17,45 @@ const Login = () => {
   const [password, setPassword] = useState('');
   const [errors, setErrors] = useState({ username: '', password: '' });
 
+  // the second login step the server asked for, if any, which is either
+  // 'verify' for entering a code or 'enroll' for setting up 2FA first
+  const [mfaStep, setMFAStep] = useState(
+    new URLSearchParams(window.location.search).get('mfa') || '',
+  );
+  const [mfaCode, setMFACode] = useState('');
+  const [mfaSecret, setMFASecret] = useState(null);
+  const [recoveryCodes, setRecoveryCodes] = useState(null);
+
+  // start enrollment as soon as the server asks for it
+  useEffect(() => {
+    if (mfaStep !== 'enroll') return;
+
+    UserAPI
+      .startMFA()
+      .then((res) => setMFASecret(res.data))
+      .catch(() => notify(
+        'Unable to set up two-factor authentication.', 'Server Error',
+      ));
+  }, [mfaStep]);
+
+  // show the error that an OIDC login redirected back with, if any
+  useEffect(() => {
+    const oidcError = new URLSearchParams(window.location.search).get('error');
+    if (!oidcError) return;
+
+    let message = 'Server Error';
+    if (oidcError === 'oidc_invalid') {
+      message = 'Your identity provider did not confirm the login.';
+    } else if (oidcError === 'oidc_username_invalid') {
+      message = 'A valid username could not be created for your account.';
+    } else if (oidcError === 'oidc_username_taken') {
+      message = 'Your username is taken by another account.';
+    } else if (oidcError === 'oidc_identity_linked') {
+      message = 'Your identity provider account is linked to another user.';
+    }
+    notify('Unable to log in.', message);
+  }, []);
+
   const handleSubmit = (e) => {
     e.preventDefault();
 
@@ -32,7 +71,14 @@ const Login = () => {
 
       UserAPI
         .login(username, password)
-        .then(() => loadBoard())
+        .then((res) => {
+          if (res?.data?.mfa) {
+            setMFAStep(res.data.mfa);
+            setIsLoading(false);
+          } else {
+            loadBoard();
+          }
+        })
         .catch((err) => {
           const serverErrors = {
             username: err?.response?.data?.username || '',
@@ -45,6 +91,10 @@ const Login = () => {
             let message = 'Server Error';
             if (err?.response?.status === 400) {
               message = 'Incorrect username or password.';
+            } else if (err?.response?.status === 429) {
+              const wait = err.response.headers['retry-after'];
+              message = 'Too many failed attempts. Please try again in '
+                + Math.ceil(wait / 60) + ' minute(s).';
             }
             notify('Unable to log in.', message);
           }
@@ -54,6 +104,108 @@ const Login = () => {
     }
   };
 
+  const handleMFASubmit = (e) => {
+    e.preventDefault();
+    setIsLoading(true);
+
+    if (mfaStep === 'verify') {
+      UserAPI
+        .verifyMFA(mfaCode)
+        .then(() => loadBoard())
+        .catch((err) => {
+          let message = 'Server Error';
+          if (err?.response?.status === 400) {
+            message = 'Invalid code.';
+          } else if (err?.response?.status === 401) {
+            message = 'Your login has expired. Please log in again.';
+            setMFAStep('');
+          } else if (err?.response?.status === 429) {
+            const wait = err.response.headers['retry-after'];
+            message = 'Too many failed attempts. Please try again in '
+              + Math.ceil(wait / 60) + ' minute(s).';
+          }
+          notify('Unable to log in.', message);
+          setIsLoading(false);
+        });
+    } else {
+      UserAPI
+        .enableMFA(mfaCode)
+        .then((res) => {
+          setRecoveryCodes(res.data.recoveryCodes);
+          setIsLoading(false);
+        })
+        .catch((err) => {
+          notify(
+            'Unable to set up two-factor authentication.',
+            err?.response?.data?.error || 'Server Error',
+          );
+          setIsLoading(false);
+        });
+    }
+  };
+
+  if (recoveryCodes) {
+    return (
+      <div id="Login">
+        <Form className="Form" onSubmit={(e) => {
+          e.preventDefault();
+          loadBoard();
+        }}>
+          <div className="HeaderWrapper">
+            <img className="Header" alt="logo" src={logo} />
+          </div>
+
+          <p>
+            Store these recovery codes somewhere safe. Each of them can be
+            used once to log in if you lose access to your authenticator.
+          </p>
+          <ul>
+            {recoveryCodes.map((code) => <li key={code}>{code}</li>)}
+          </ul>
+
+          <div className="ButtonWrapper">
+            <Button className="Button" type="submit" aria-label="continue">
+              CONTINUE
+            </Button>
+          </div>
+        </Form>
+      </div>
+    );
+  }
+
+  if (mfaStep) {
+    return (
+      <div id="Login">
+        <Form className="Form" onSubmit={handleMFASubmit}>
+          <div className="HeaderWrapper">
+            <img className="Header" alt="logo" src={logo} />
+          </div>
+
+          {mfaStep === 'enroll' && mfaSecret && (
+            <p>
+              Your team requires two-factor authentication. Add this key to
+              your authenticator app and enter the code it shows:{' '}
+              <a href={mfaSecret.uri}>{mfaSecret.secret}</a>
+            </p>
+          )}
+
+          <FormGroup
+            type={inputType.TEXT}
+            label={mfaStep === 'verify' ? 'code or recovery code' : 'code'}
+            value={mfaCode}
+            setValue={setMFACode}
+          />
+
+          <div className="ButtonWrapper">
+            <Button className="Button" type="submit" aria-label="submit">
+              GO!
+            </Button>
+          </div>
+        </Form>
+      </div>
+    );
+  }
+
   return (
     <div id="Login">
       <Form className="Form" onSubmit={handleSubmit}>
@@ -83,6 +235,18 @@ const Login = () => {
           </Button>
         </div>
 
+        {process.env.REACT_APP_OIDC_ENABLED === 'true' && (
+          <div className="ButtonWrapper">
+            <Button
+              className="Button"
+              href={UserAPI.oidcLoginURL}
+              aria-label="single sign-on"
+            >
+              LOG IN WITH SSO
+            </Button>
+          </div>
+        )}
+
         <div className="Redirect">
           <p>Don&apos;t have an account yet?</p>
           <p>
@@ -1,6 +1,23 @@
 import React from 'react';
 import ReactDOM from 'react-dom';
+import axios from 'axios';
 import App from './App';
+import UserAPI from './api/UserAPI';
+
+// when a request fails because the auth token has expired, exchange the
+// refresh token for a new one and retry the request once
+axios.interceptors.response.use(null, async (err) => {
+  const { config, response } = err;
+  if (
+    response?.status !== 401
+    || config._isRetry
+    || config.url.endsWith('/refresh')
+  ) {
+    throw err;
+  }
+  await UserAPI.refresh();
+  return axios({ ...config, _isRetry: true });
+});
 
 document.addEventListener('contextmenu', (e) => e.preventDefault());
 
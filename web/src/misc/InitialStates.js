@@ -2,17 +2,21 @@ const InitialStates = {
   user: {
     username: '',
     teamId: null,
+    role: '',
     isAdmin: false,
     isAuthenticated: false,
   },
 
   team: {
     id: null,
-    inviteToken: '',
+    name: '',
+    owner: '',
   },
 
   members: [{
     username: '',
+    displayName: '',
+    avatarURL: '',
     isActive: false,
     isAdmin: false,
   }],
@@ -26,10 +30,10 @@ const InitialStates = {
   activeBoard: {
     id: null,
     columns: [
-      { id: null, order: 0, tasks: [] },
-      { id: null, order: 1, tasks: [] },
-      { id: null, order: 2, tasks: [] },
-      { id: null, order: 3, tasks: [] },
+      { id: 'inbox', name: 'Inbox', color: '', tasks: [] },
+      { id: 'ready', name: 'Ready', color: '', tasks: [] },
+      { id: 'go', name: 'Go!', color: '', tasks: [] },
+      { id: 'done', name: 'Done', color: '', tasks: [] },
     ],
   },
 };
@@ -8,6 +8,8 @@ const window = {
   CREATE_BOARD: 'create_board',
   DELETE_BOARD: 'delete_board',
   EDIT_BOARD: 'edit_board',
+  ARCHIVE_BOARD: 'archive_board',
+  ARCHIVED_BOARDS: 'archived_boards',
   CREATE_TASK: 'create_task',
   EDIT_TASK: 'edit_task',
   DELETE_TASK: 'delete_task',